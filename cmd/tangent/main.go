@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"crypto/tls"
+	"errors"
 	"flag"
 	"fmt"
 	"net/http"
@@ -11,6 +12,9 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/tansive/tansive-internal/internal/common/certs"
+	"github.com/tansive/tansive-internal/internal/common/metrics"
+	"github.com/tansive/tansive-internal/internal/tangent/artifacts"
 	"github.com/tansive/tansive-internal/internal/tangent/config"
 	"github.com/tansive/tansive-internal/internal/tangent/server"
 	"github.com/tansive/tansive-internal/internal/tangent/session"
@@ -49,10 +53,18 @@ func run(ctx context.Context) error {
 	if config.Config().ServerPort == "" {
 		return fmt.Errorf("server port not defined")
 	}
-	if err := config.RegisterTangent(); err != nil {
+	if err := session.Init(); err != nil {
+		return fmt.Errorf("initializing runners: %w", err)
+	}
+	if err := artifacts.Init(ctx); err != nil {
+		return fmt.Errorf("initializing artifacts: %w", err)
+	}
+	if err := server.RegisterTangent(); err != nil {
 		return fmt.Errorf("registering tangent: %w", err)
 	}
-	session.Init()
+	if err := server.StartHeartbeat(ctx); err != nil {
+		return fmt.Errorf("starting heartbeat: %w", err)
+	}
 
 	s, err := server.CreateNewServer()
 	if err != nil {
@@ -60,10 +72,9 @@ func run(ctx context.Context) error {
 	}
 	s.MountHandlers()
 
-	srv := &http.Server{
-		Addr:              ":" + config.Config().ServerPort,
-		Handler:           s.Router,
-		ReadHeaderTimeout: 5 * time.Second,
+	srv, err := createHTTPServer(s.Router)
+	if err != nil {
+		return fmt.Errorf("creating http server: %w", err)
 	}
 
 	// Channel to listen for errors coming from the listener.
@@ -74,8 +85,8 @@ func run(ctx context.Context) error {
 		if config.Config().SupportTLS {
 			slog.Info().Str("port", config.Config().ServerPort).Msg("server started with TLS")
 
-			// Create TLS config from PEM certificates
-			tlsConfig, err := createTLSConfig()
+			// Create TLS config that reloads the certificate files as they change
+			tlsConfig, err := createTLSConfig(log.Logger.WithContext(ctx))
 			if err != nil {
 				serverErrors <- fmt.Errorf("creating TLS config: %w", err)
 				return
@@ -100,6 +111,18 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("creating skill service: %w", err)
 	}
 
+	// Metrics are served on their own port, so they are not exposed with the API
+	if port := config.Config().MetricsPort; port != "" {
+		metricsSrv := metrics.NewServer(port, s.Metrics)
+		go func() {
+			slog.Info().Str("port", port).Msg("metrics server started")
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErrors <- fmt.Errorf("metrics server: %w", err)
+			}
+		}()
+		defer metricsSrv.Close()
+	}
+
 	// Channel to listen for an interrupt or terminate signal from the OS.
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
@@ -129,21 +152,48 @@ func run(ctx context.Context) error {
 	return nil
 }
 
-// createTLSConfig creates a TLS configuration from the PEM certificates in the config
-func createTLSConfig() (*tls.Config, error) {
-	cfg := config.Config()
-
-	cert, err := tls.X509KeyPair(cfg.TLSCertPEM, cfg.TLSKeyPEM)
+// createHTTPServer creates the API server with the timeouts in the config, so that slow
+// clients cannot hold connections open indefinitely.
+func createHTTPServer(handler http.Handler) (*http.Server, error) {
+	h := config.Config().HTTP
+	readHeaderTimeout, err := h.GetReadHeaderTimeout()
 	if err != nil {
-		return nil, fmt.Errorf("parsing TLS certificate: %w", err)
+		return nil, fmt.Errorf("http.read_header_timeout: %w", err)
 	}
+	readTimeout, err := h.GetReadTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("http.read_timeout: %w", err)
+	}
+	writeTimeout, err := h.GetWriteTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("http.write_timeout: %w", err)
+	}
+	idleTimeout, err := h.GetIdleTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("http.idle_timeout: %w", err)
+	}
+	return &http.Server{
+		Addr:              ":" + config.Config().ServerPort,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}, nil
+}
 
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		MinVersion:   tls.VersionTLS12,
+// createTLSConfig creates a TLS configuration from the certificate in the config. The
+// certificate files are reloaded when they change or on SIGHUP until ctx is cancelled.
+func createTLSConfig(ctx context.Context) (*tls.Config, error) {
+	cfg := config.Config()
+
+	reloader, err := certs.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, cfg.TLSCertPEM, cfg.TLSKeyPEM)
+	if err != nil {
+		return nil, err
 	}
+	go reloader.Watch(ctx)
 
-	return tlsConfig, nil
+	return reloader.TLSConfig(), nil
 }
 
 const DefaultConfigFile = "/etc/tansive/tangent.conf"
@@ -14,13 +14,20 @@ import (
 
 	"github.com/avast/retry-go/v4"
 	zerolog "github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/server"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/session"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/tenants"
+	"github.com/tansive/tansive-internal/internal/common/certs"
+	"github.com/tansive/tansive-internal/internal/common/health"
 	"github.com/tansive/tansive-internal/internal/common/logtrace"
+	"github.com/tansive/tansive-internal/internal/common/metrics"
 )
 
 func init() {
@@ -35,6 +42,14 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, os.Args[2:]); err != nil {
+			zerolog.Error().Err(err).Msg("migration failed")
+			os.Exit(1)
+		}
+		return
+	}
+
 	if err := run(ctx); err != nil {
 		zerolog.Error().Err(err).Msg("server failed")
 		os.Exit(1)
@@ -53,6 +68,9 @@ func run(ctx context.Context) error {
 	}
 
 	config.Init()
+	if err := migrateOnStartup(ctx); err != nil {
+		return fmt.Errorf("migrating database: %w", err)
+	}
 	db.Init()
 	session.Init()
 
@@ -70,15 +88,19 @@ func run(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("creating server: %w", err)
 	}
+	s.Probes.AddReadiness("migrations", health.UntilPassed(checkMigrations))
 	s.MountHandlers()
 
-	srv := &http.Server{
-		Addr:              ":" + config.Config().ServerPort,
-		Handler:           s.Router,
-		ReadTimeout:       10 * time.Second,
-		WriteTimeout:      10 * time.Second,
-		ReadHeaderTimeout: 5 * time.Second,
-		IdleTimeout:       120 * time.Second,
+	go policy.RunViewExpiryJob(zerolog.Logger.WithContext(ctx), config.Config().Views.GetExpiryCheckIntervalOrDefault())
+	go catalogmanager.RunDeletedObjectPurgeJob(zerolog.Logger.WithContext(ctx),
+		config.Config().Deletion.GetPurgeIntervalOrDefault(), config.Config().Deletion.GetRetentionOrDefault())
+	go catalogmanager.RunObjectGCJob(zerolog.Logger.WithContext(ctx),
+		config.Config().ObjectGC.GetIntervalOrDefault(), config.Config().ObjectGC.GetGracePeriodOrDefault())
+	go catalogmanager.RunSearchIndexBackfill(zerolog.Logger.WithContext(ctx))
+
+	srv, err := createHTTPServer(s.Router)
+	if err != nil {
+		return fmt.Errorf("creating http server: %w", err)
 	}
 
 	// Channel to listen for errors coming from the listener.
@@ -89,8 +111,8 @@ func run(ctx context.Context) error {
 		if config.Config().SupportTLS {
 			log.Info().Str("port", config.Config().ServerPort).Msg("server started with TLS")
 
-			// Create TLS config from PEM certificates
-			tlsConfig, err := createTLSConfig()
+			// Create TLS config that reloads the certificate files as they change
+			tlsConfig, err := createTLSConfig(zerolog.Logger.WithContext(ctx))
 			if err != nil {
 				serverErrors <- fmt.Errorf("creating TLS config: %w", err)
 				return
@@ -110,6 +132,18 @@ func run(ctx context.Context) error {
 		}
 	}()
 
+	// Metrics are served on their own port, so they are not exposed with the API
+	if port := config.Config().MetricsPort; port != "" {
+		metricsSrv := metrics.NewServer(port, s.Metrics)
+		go func() {
+			log.Info().Str("port", port).Msg("metrics server started")
+			if err := metricsSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serverErrors <- fmt.Errorf("metrics server: %w", err)
+			}
+		}()
+		defer metricsSrv.Close()
+	}
+
 	// Channel to listen for an interrupt or terminate signal from the OS.
 	shutdown := make(chan os.Signal, 1)
 	signal.Notify(shutdown, os.Interrupt, syscall.SIGTERM)
@@ -183,24 +217,64 @@ func createDefaultTenantAndProject(ctx context.Context) error {
 		return fmt.Errorf("creating default project: %w", err)
 	}
 
+	// The single user owns the default tenant
+	if _, err := db.DB(dbCtx).GetTenantRole(dbCtx, catcommon.SingleUserID); err != nil {
+		if !errors.Is(err, dberror.ErrNotFound) {
+			return fmt.Errorf("getting default tenant owner: %w", err)
+		}
+		if err := db.DB(dbCtx).SetTenantRole(dbCtx, &models.TenantRole{
+			UserID: catcommon.SingleUserID,
+			Role:   string(tenants.RoleOwner),
+		}); err != nil {
+			return fmt.Errorf("setting default tenant owner: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// createTLSConfig creates a TLS configuration from the PEM certificates in the config
-func createTLSConfig() (*tls.Config, error) {
-	cfg := config.Config()
-
-	cert, err := tls.X509KeyPair(cfg.TLSCertPEM, cfg.TLSKeyPEM)
+// createHTTPServer creates the API server with the timeouts in the config, so that slow
+// clients cannot hold connections open indefinitely.
+func createHTTPServer(handler http.Handler) (*http.Server, error) {
+	h := config.Config().HTTP
+	readHeaderTimeout, err := h.GetReadHeaderTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("http.read_header_timeout: %w", err)
+	}
+	readTimeout, err := h.GetReadTimeout()
 	if err != nil {
-		return nil, fmt.Errorf("parsing TLS certificate: %w", err)
+		return nil, fmt.Errorf("http.read_timeout: %w", err)
 	}
+	writeTimeout, err := h.GetWriteTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("http.write_timeout: %w", err)
+	}
+	idleTimeout, err := h.GetIdleTimeout()
+	if err != nil {
+		return nil, fmt.Errorf("http.idle_timeout: %w", err)
+	}
+	return &http.Server{
+		Addr:              ":" + config.Config().ServerPort,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       idleTimeout,
+	}, nil
+}
 
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		MinVersion:   tls.VersionTLS12,
+// createTLSConfig creates a TLS configuration from the certificate in the config. The
+// certificate files are reloaded when they change or on SIGHUP until ctx is cancelled.
+func createTLSConfig(ctx context.Context) (*tls.Config, error) {
+	cfg := config.Config()
+
+	reloader, err := certs.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, cfg.TLSCertPEM, cfg.TLSKeyPEM)
+	if err != nil {
+		return nil, err
 	}
+	go reloader.Watch(ctx)
 
-	return tlsConfig, nil
+	return reloader.TLSConfig(), nil
 }
 
 const DefaultConfigFile = "/etc/tansive/tansivesrv.conf"
@@ -209,7 +283,7 @@ func parseFlags() cmdoptions {
 	var opt cmdoptions
 	flag.StringVar(&opt.configFile, "config", DefaultConfigFile, "Path to the config file")
 	flag.Usage = func() {
-		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [options]\n       %s migrate [options] up|down|status\n\n", os.Args[0], os.Args[0])
 		fmt.Println("Options:")
 		flag.PrintDefaults()
 	}
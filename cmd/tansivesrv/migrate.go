@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	zerolog "github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/migrate"
+)
+
+// runMigrate implements the migrate subcommand:
+//
+//	tansivesrv migrate [--config file] up|down|status [--steps n]
+func runMigrate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	configFile := fs.String("config", DefaultConfigFile, "Path to the config file")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s migrate [options] up|down|status [--steps n]\n\n", os.Args[0])
+		fmt.Fprintln(fs.Output(), "Commands:")
+		fmt.Fprintln(fs.Output(), "  up      Apply all pending migrations")
+		fmt.Fprintln(fs.Output(), "  down    Revert the most recently applied migrations")
+		fmt.Fprintln(fs.Output(), "  status  List migrations and whether they are applied")
+		fmt.Fprintln(fs.Output(), "\nOptions:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("migrate command is required")
+	}
+	command := fs.Arg(0)
+
+	cmdFlags := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	steps := cmdFlags.Int("steps", 1, "Number of migrations to revert (down only)")
+	if err := cmdFlags.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+
+	if err := config.LoadConfig(*configFile); err != nil {
+		return fmt.Errorf("loading config file: %w", err)
+	}
+	m, err := migrate.Open(config.HatchCatalogDSN())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	ctx = zerolog.Logger.WithContext(ctx)
+	switch command {
+	case "up":
+		applied, err := m.Up(ctx)
+		for _, mg := range applied {
+			fmt.Printf("applied %d_%s\n", mg.Version, mg.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		reverted, err := m.Down(ctx, *steps)
+		for _, mg := range reverted {
+			fmt.Printf("reverted %d_%s\n", mg.Version, mg.Name)
+		}
+		if err == nil && len(reverted) == 0 {
+			fmt.Println("no applied migrations")
+		}
+		return err
+	case "status":
+		status, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range status {
+			state, appliedAt := "pending", ""
+			if s.Applied {
+				state = "applied"
+				appliedAt = s.AppliedAt.Format(time.RFC3339)
+			}
+			if s.Unknown {
+				state = "unknown"
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		return w.Flush()
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown migrate command: %s", command)
+	}
+}
+
+// migrateOnStartup applies pending migrations when db.auto_migrate is set.
+func migrateOnStartup(ctx context.Context) error {
+	if !config.Config().DB.AutoMigrate {
+		return nil
+	}
+	m, err := migrate.Open(config.HatchCatalogDSN())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	applied, err := m.Up(zerolog.Logger.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	zerolog.Info().Int("applied", len(applied)).Msg("database schema is up to date")
+	return nil
+}
+
+// checkMigrations reports an error if the database schema has migrations that are not
+// applied. It is a readiness check.
+func checkMigrations(ctx context.Context) error {
+	m, err := migrate.Open(config.HatchCatalogDSN())
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	status, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	pending := 0
+	for _, s := range status {
+		if !s.Applied {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("%d migrations are not applied", pending)
+	}
+	return nil
+}
@@ -0,0 +1,269 @@
+// Package apikeys implements management of API keys. An API key lets a non-interactive
+// client, such as a CI job or a tangent, act with the permissions of a view without holding
+// a user token.
+package apikeys
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+var keyNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+func createAPIKey(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	userID, catalogID, err := userAndCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Body == nil {
+		return nil, ErrInvalidRequest.Msg("request body is required")
+	}
+	body, goerr := io.ReadAll(r.Body)
+	if goerr != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+	req := createAPIKeyReq{}
+	if goerr := json.Unmarshal(body, &req); goerr != nil {
+		return nil, ErrInvalidRequest.Msg("unable to parse request: " + goerr.Error())
+	}
+
+	if !keyNameRegex.MatchString(req.Name) {
+		return nil, ErrInvalidName.Msg("name must be 1-128 alphanumeric, underscore or hyphen characters")
+	}
+	if req.View == "" {
+		return nil, ErrInvalidRequest.Msg("view is required")
+	}
+
+	expiresIn := req.ExpiresIn
+	if expiresIn == "" {
+		expiresIn = DefaultKeyValidity
+	}
+	validity, goerr := config.ParseDuration(expiresIn)
+	if goerr != nil || validity <= 0 {
+		return nil, ErrInvalidExpiry.Msg("invalid expires_in: " + expiresIn)
+	}
+
+	if err := checkViewAccess(ctx, req.View); err != nil {
+		return nil, err
+	}
+
+	view, err := db.DB(ctx).GetViewByLabel(ctx, req.View, catalogID)
+	if err != nil {
+		return nil, ErrViewNotFound.Err(err)
+	}
+
+	key, displayPrefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &models.APIKey{
+		Name:        req.Name,
+		Description: req.Description,
+		KeyPrefix:   displayPrefix,
+		KeyHash:     hash,
+		ViewID:      view.ViewID,
+		CatalogID:   catalogID,
+		ProjectID:   catcommon.GetProjectID(ctx),
+		CreatedBy:   userID,
+		ExpireAt:    time.Now().Add(validity),
+	}
+	if err := db.DB(ctx).CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("key_id", apiKey.KeyID.String()).Str("view", view.Label).Msg("created api key")
+
+	rsp := newAPIKeyRsp(apiKey, view.Label)
+	rsp.Key = key
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   "/apikeys/" + apiKey.KeyID.String(),
+		Response:   rsp,
+	}, nil
+}
+
+func listAPIKeys(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	_, catalogID, err := userAndCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := db.DB(ctx).ListAPIKeysByCatalog(ctx, catalogID)
+	if err != nil {
+		return nil, err
+	}
+
+	views, err := db.DB(ctx).ListViewsByCatalog(ctx, catalogID)
+	if err != nil {
+		return nil, err
+	}
+	viewLabels := make(map[uuid.UUID]string, len(views))
+	for _, v := range views {
+		viewLabels[v.ViewID] = v.Label
+	}
+
+	rsp := listAPIKeysRsp{Items: []apiKeyRsp{}}
+	for _, k := range keys {
+		rsp.Items = append(rsp.Items, newAPIKeyRsp(k, viewLabels[k.ViewID]))
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+func getAPIKey(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	_, catalogID, err := userAndCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, view, err := loadAPIKey(ctx, chi.URLParam(r, "keyID"), catalogID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   newAPIKeyRsp(key, view.Label),
+	}, nil
+}
+
+func revokeAPIKey(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	_, catalogID, err := userAndCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, view, err := loadAPIKey(ctx, chi.URLParam(r, "keyID"), catalogID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkViewAccess(ctx, view.Label); err != nil {
+		return nil, err
+	}
+
+	if err := db.DB(ctx).RevokeAPIKey(ctx, key.KeyID); err != nil {
+		return nil, err
+	}
+	key, err = db.DB(ctx).GetAPIKey(ctx, key.KeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("key_id", key.KeyID.String()).Msg("revoked api key")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   newAPIKeyRsp(key, view.Label),
+	}, nil
+}
+
+func deleteAPIKey(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	_, catalogID, err := userAndCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, view, err := loadAPIKey(ctx, chi.URLParam(r, "keyID"), catalogID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkViewAccess(ctx, view.Label); err != nil {
+		return nil, err
+	}
+
+	if err := db.DB(ctx).DeleteAPIKey(ctx, key.KeyID); err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+// userAndCatalog returns the calling user and the catalog of the current view. API keys can
+// only be managed by users, not by other API keys or sessions.
+func userAndCatalog(ctx context.Context) (string, uuid.UUID, apperrors.Error) {
+	userContext := catcommon.GetUserContext(ctx)
+	if catcommon.GetSubjectType(ctx) != catcommon.SubjectTypeUser || userContext == nil || userContext.UserID == "" {
+		return "", uuid.Nil, ErrNotAuthorized.Msg("api keys can only be managed by users")
+	}
+	catalogID := catcommon.GetCatalogID(ctx)
+	if catalogID == uuid.Nil {
+		return "", uuid.Nil, ErrInvalidRequest.Msg("unable to resolve catalog")
+	}
+	return userContext.UserID, catalogID, nil
+}
+
+// loadAPIKey loads a key of the given catalog and the view it is bound to.
+func loadAPIKey(ctx context.Context, keyIDStr string, catalogID uuid.UUID) (*models.APIKey, *models.View, apperrors.Error) {
+	keyID, goerr := uuid.Parse(keyIDStr)
+	if goerr != nil {
+		return nil, nil, ErrInvalidRequest.Msg("invalid key ID")
+	}
+
+	key, err := db.DB(ctx).GetAPIKey(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, nil, ErrAPIKeyNotFound
+		}
+		return nil, nil, err
+	}
+	if key.CatalogID != catalogID {
+		return nil, nil, ErrAPIKeyNotFound
+	}
+
+	view, err := db.DB(ctx).GetView(ctx, key.ViewID)
+	if err != nil {
+		return nil, nil, ErrViewNotFound.Err(err)
+	}
+	return key, view, nil
+}
+
+// checkViewAccess verifies that the caller may act with the permissions of the view, using the
+// same rules as view adoption.
+func checkViewAccess(ctx context.Context, viewLabel string) apperrors.Error {
+	allowed, err := policy.CanAdoptView(ctx, viewLabel)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		allowed = policy.CanAdoptViewAsUser(ctx, catcommon.GetCatalogID(ctx), viewLabel)
+	}
+	if !allowed {
+		return ErrDisallowedByPolicy.Msg("view " + viewLabel + " cannot be bound to an api key")
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package apikeys
+
+import (
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+var (
+	ErrAPIKeyError        apperrors.Error = apperrors.New("api key error").SetStatusCode(http.StatusInternalServerError)
+	ErrInvalidRequest     apperrors.Error = ErrAPIKeyError.New("invalid request").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidName        apperrors.Error = ErrAPIKeyError.New("invalid api key name").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidExpiry      apperrors.Error = ErrAPIKeyError.New("invalid expiry").SetStatusCode(http.StatusBadRequest)
+	ErrViewNotFound       apperrors.Error = ErrAPIKeyError.New("view not found").SetStatusCode(http.StatusNotFound)
+	ErrAPIKeyNotFound     apperrors.Error = ErrAPIKeyError.New("api key not found").SetStatusCode(http.StatusNotFound)
+	ErrNotAuthorized      apperrors.Error = ErrAPIKeyError.New("not authorized").SetStatusCode(http.StatusForbidden)
+	ErrDisallowedByPolicy apperrors.Error = ErrAPIKeyError.New("disallowed by policy").SetStatusCode(http.StatusForbidden)
+)
@@ -0,0 +1,53 @@
+package apikeys
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/apis"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+var apiKeyHandlers = []policy.ResponseHandlerParam{
+	{
+		Method:  http.MethodPost,
+		Path:    "/",
+		Handler: createAPIKey,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/",
+		Handler: listAPIKeys,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{keyID}",
+		Handler: getAPIKey,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/{keyID}",
+		Handler: deleteAPIKey,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{keyID}/revoke",
+		Handler: revokeAPIKey,
+	},
+}
+
+// Router creates the router for API key management. Keys are managed by users holding a
+// view in the catalog the keys belong to.
+func Router() chi.Router {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(auth.ContextMiddleware)
+		r.Use(apis.CatalogContextLoader)
+		for _, handler := range apiKeyHandlers {
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
+		}
+	})
+	return r
+}
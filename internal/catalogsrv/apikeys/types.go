@@ -0,0 +1,52 @@
+package apikeys
+
+import (
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// DefaultKeyValidity is used when a key is created without an expiry
+const DefaultKeyValidity = "90d"
+
+type createAPIKeyReq struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	View        string `json:"view"`
+	ExpiresIn   string `json:"expires_in,omitempty"`
+}
+
+type apiKeyRsp struct {
+	KeyID       uuid.UUID  `json:"key_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	KeyPrefix   string     `json:"key_prefix"`
+	View        string     `json:"view"`
+	CreatedBy   string     `json:"created_by"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	// Key is only returned when the key is created
+	Key string `json:"key,omitempty"`
+}
+
+type listAPIKeysRsp struct {
+	Items []apiKeyRsp `json:"items"`
+}
+
+func newAPIKeyRsp(k *models.APIKey, viewLabel string) apiKeyRsp {
+	return apiKeyRsp{
+		KeyID:       k.KeyID,
+		Name:        k.Name,
+		Description: k.Description,
+		KeyPrefix:   k.KeyPrefix,
+		View:        viewLabel,
+		CreatedBy:   k.CreatedBy,
+		ExpiresAt:   k.ExpireAt,
+		LastUsedAt:  k.LastUsedAt,
+		RevokedAt:   k.RevokedAt,
+		CreatedAt:   k.CreatedAt,
+	}
+}
@@ -0,0 +1,421 @@
+package apis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v3"
+)
+
+// applyOrder lists the kinds accepted by /apply in the order they are applied,
+// so that the parent of every object exists before the object itself.
+var applyOrder = []string{
+	catcommon.CatalogKind,
+	catcommon.VariantKind,
+	catcommon.NamespaceKind,
+	catcommon.ViewKind,
+	catcommon.ViewTemplateKind,
+	catcommon.AdmissionWebhookKind,
+	catcommon.SkillSetKind,
+	catcommon.ResourceKind,
+}
+
+const (
+	applyStatusCreated = "created"
+	applyStatusUpdated = "updated"
+	applyStatusFailed  = "failed"
+	applyStatusPruned  = "pruned"
+)
+
+// ApplyResult reports the outcome of applying a single document.
+type ApplyResult struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Location string `json:"location,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ApplyRsp is the response body of /apply. With DryRun set, nothing was persisted.
+type ApplyRsp struct {
+	DryRun  bool          `json:"dryRun,omitempty"`
+	Results []ApplyResult `json:"results"`
+}
+
+type applyDocument struct {
+	kind string
+	name string
+	json []byte
+}
+
+// applyObjects creates or updates every object in a multi-document YAML or JSON stream.
+// All documents are validated before any is applied. Documents are then applied in
+// dependency order and processing stops at the first failure; the response lists the
+// outcome of every document attempted so far.
+// With prune=true, once every document has been applied, the skillsets and resources of
+// the variants and namespaces the documents were applied to that are not in the request
+// are deleted. Other kinds are never pruned.
+// With dryRun=true, everything is applied in a transaction that is rolled back, so the
+// response reports what would have been done.
+func applyObjects(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	var prune bool
+	if p := r.URL.Query().Get("prune"); p != "" {
+		var goerr error
+		prune, goerr = strconv.ParseBool(p)
+		if goerr != nil {
+			return nil, httpx.ErrInvalidRequest("invalid prune value: " + p)
+		}
+	}
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, httpx.ErrRequestTooLarge(maxErr.Limit)
+		}
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	docs, err := parseApplyDocuments(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, httpx.ErrInvalidRequest("no documents to apply")
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(docs, func(a, b applyDocument) int {
+		return slices.Index(applyOrder, a.kind) - slices.Index(applyOrder, b.kind)
+	})
+
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := ApplyRsp{DryRun: dryRun, Results: make([]ApplyResult, 0, len(docs))}
+	ctx, applyErr := admitDocuments(ctx, reqContext, docs, dryRun, &rsp)
+	if applyErr != nil {
+		return &httpx.Response{
+			StatusCode: statusCodeFromError(applyErr),
+			Response:   rsp,
+		}, nil
+	}
+	if dryRun {
+		err := runDryRun(ctx, func(ctx context.Context) apperrors.Error {
+			applyErr = applyDocuments(ctx, reqContext, docs, prune, &rsp)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		applyErr = applyDocuments(ctx, reqContext, docs, prune, &rsp)
+	}
+	if applyErr != nil {
+		return &httpx.Response{
+			StatusCode: statusCodeFromError(applyErr),
+			Response:   rsp,
+		}, nil
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// admitDocuments calls the admission webhooks for the resource documents before any document
+// is applied, since a dry run applies them in a transaction, and replaces the documents with
+// the objects to write. It returns the context to apply the documents with. Webhooks created
+// by the documents are not called for them. Documents whose request context cannot be derived
+// are left to fail when they are applied.
+func admitDocuments(ctx context.Context, reqContext interfaces.RequestContext, docs []applyDocument, dryRun bool, rsp *ApplyRsp) (context.Context, error) {
+	writeCtx := ctx
+	for i := range docs {
+		doc := &docs[i]
+		if doc.kind != catcommon.ResourceKind {
+			continue
+		}
+		docContext, err := requestContextForDocument(ctx, reqContext, *doc)
+		if err != nil {
+			continue
+		}
+		rm, err := catalogmanager.ResourceManagerForKind(ctx, doc.kind, docContext)
+		if err != nil {
+			continue
+		}
+		admittedCtx, admitted, aerr := admitWrite(ctx, rm, doc.json, dryRun)
+		if aerr != nil {
+			log.Ctx(ctx).Error().Err(aerr).Str("kind", doc.kind).Str("name", doc.name).Msg("document rejected by admission")
+			rsp.Results = append(rsp.Results, ApplyResult{Kind: doc.kind, Name: doc.name, Status: applyStatusFailed, Error: aerr.Error()})
+			return ctx, aerr
+		}
+		doc.json = admitted
+		writeCtx = admittedCtx
+	}
+	return writeCtx, nil
+}
+
+// applyDocuments applies the documents in order, then prunes if asked to, recording the
+// outcome of each object in rsp. It stops at the first failure.
+func applyDocuments(ctx context.Context, reqContext interfaces.RequestContext, docs []applyDocument, prune bool, rsp *ApplyRsp) error {
+	scopes := make(map[applyScope]map[string]bool)
+	for _, doc := range docs {
+		docContext, err := requestContextForDocument(ctx, reqContext, doc)
+		result := ApplyResult{Kind: doc.kind, Name: doc.name, Status: applyStatusFailed}
+		if err == nil {
+			result, err = applyDocumentInContext(ctx, docContext, doc)
+		} else {
+			result.Error = err.Error()
+		}
+		rsp.Results = append(rsp.Results, result)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("kind", doc.kind).Str("name", doc.name).Msg("failed to apply document")
+			return err
+		}
+		if doc.kind == catcommon.ResourceKind || doc.kind == catcommon.SkillSetKind {
+			scope := applyScope{variant: docContext.Variant, variantID: docContext.VariantID, namespace: docContext.Namespace}
+			if scopes[scope] == nil {
+				scopes[scope] = make(map[string]bool)
+			}
+			scopes[scope][doc.kind+":"+path.Join("/", docContext.ObjectPath, docContext.ObjectName)] = true
+		}
+	}
+
+	if prune {
+		return pruneObjects(ctx, reqContext, scopes, rsp)
+	}
+	return nil
+}
+
+// parseApplyDocuments splits the request body into its documents and converts each to JSON.
+func parseApplyDocuments(body []byte) ([]applyDocument, error) {
+	jsonDocs, err := decodeDocuments(body)
+	if err != nil {
+		return nil, err
+	}
+	var docs []applyDocument
+	for _, j := range jsonDocs {
+		kind := gjson.GetBytes(j, "kind").String()
+		if !slices.Contains(applyOrder, kind) {
+			return nil, httpx.ErrInvalidRequest("unsupported kind in document: " + kind)
+		}
+		name := gjson.GetBytes(j, "metadata.name").String()
+		if name == "" {
+			return nil, httpx.ErrInvalidRequest("missing metadata.name in " + kind + " document")
+		}
+		docs = append(docs, applyDocument{kind: kind, name: name, json: j})
+	}
+	return docs, nil
+}
+
+// decodeDocuments splits a multi-document YAML or JSON stream into its non-empty documents,
+// each converted to JSON. JSON is a subset of YAML, so both formats are handled by the same
+// decoder.
+func decodeDocuments(body []byte) ([][]byte, error) {
+	var docs [][]byte
+	decoder := yaml.NewDecoder(bytes.NewReader(body))
+	for {
+		var doc map[string]any
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, httpx.ErrInvalidRequest("unable to parse document: " + err.Error())
+		}
+		if len(doc) == 0 {
+			continue
+		}
+		j, err := json.Marshal(doc)
+		if err != nil {
+			return nil, httpx.ErrInvalidRequest("unable to convert document to JSON: " + err.Error())
+		}
+		docs = append(docs, j)
+	}
+	return docs, nil
+}
+
+// applyScope is a variant and namespace that skillsets or resources were applied to.
+type applyScope struct {
+	variant   string
+	variantID uuid.UUID
+	namespace string
+}
+
+// pruneObjects deletes the skillsets and resources of each scope that are not among the
+// objects applied to it, recording each deletion in rsp. It stops at the first failure.
+func pruneObjects(ctx context.Context, base interfaces.RequestContext, scopes map[applyScope]map[string]bool, rsp *ApplyRsp) error {
+	objectsOf := make(map[uuid.UUID][]catalogmanager.StoredObject)
+	for scope, applied := range scopes {
+		objects, ok := objectsOf[scope.variantID]
+		if !ok {
+			var err error
+			objects, err = catalogmanager.ListStoredObjects(ctx, base.Catalog, scope.variantID)
+			if err != nil {
+				return err
+			}
+			objectsOf[scope.variantID] = objects
+		}
+		for _, obj := range objects {
+			if obj.Namespace != scope.namespace || applied[obj.Kind+":"+obj.FullyQualifiedName()] {
+				continue
+			}
+			reqContext := storedObjectRequestContext(base, scope.variant, scope.variantID, obj)
+			reqContext.Namespace = scope.namespace
+			if err := deleteStoredObject(ctx, reqContext, obj, applyStatusPruned, rsp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// applyDocumentInContext creates the object described by doc, or updates it if it already
+// exists. reqContext is the request context for the document.
+func applyDocumentInContext(ctx context.Context, reqContext interfaces.RequestContext, doc applyDocument) (ApplyResult, error) {
+	result := ApplyResult{Kind: doc.kind, Name: doc.name, Status: applyStatusFailed}
+
+	// The catalog is fixed by the session, so a Catalog document can only update it
+	if doc.kind != catcommon.CatalogKind {
+		rm, err := catalogmanager.ResourceManagerForKind(ctx, doc.kind, reqContext)
+		if err != nil {
+			result.Error = err.Error()
+			return result, err
+		}
+		// The create runs in a transaction of its own so that a conflict does not abort the
+		// transaction of a dry run
+		var location string
+		err = db.Tx(ctx, func(ctx context.Context) apperrors.Error {
+			var err apperrors.Error
+			location, err = rm.Create(ctx, doc.json)
+			return err
+		})
+		if err == nil {
+			result.Status = applyStatusCreated
+			result.Location = location
+			publishObjectEvent(ctx, catalogmanager.ObjectEventCreated, doc.kind, reqContext, doc.json, location)
+			return result, nil
+		}
+		if err.StatusCode() != http.StatusConflict {
+			result.Error = err.Error()
+			return result, err
+		}
+	}
+
+	rm, err := catalogmanager.ResourceManagerForKind(ctx, doc.kind, reqContext)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+	// The update takes the lock of the object, so that it does not interleave with conditional
+	// writes
+	update := func(ctx context.Context) apperrors.Error {
+		return rm.Update(ctx, doc.json)
+	}
+	if _, err := catalogmanager.WriteWithPreconditions(ctx, doc.kind, rm, reqContext, catalogmanager.Preconditions{}, update); err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+	result.Status = applyStatusUpdated
+	publishObjectEvent(ctx, catalogmanager.ObjectEventUpdated, doc.kind, reqContext, doc.json, "")
+	return result, nil
+}
+
+// requestContextForDocument derives the request context for a document from the metadata
+// of the document, falling back to the catalog, variant and namespace of the request.
+func requestContextForDocument(ctx context.Context, base interfaces.RequestContext, doc applyDocument) (interfaces.RequestContext, error) {
+	reqContext := base
+	metadata := gjson.GetBytes(doc.json, "metadata")
+
+	if catalog := metadata.Get("catalog").String(); catalog != "" && catalog != base.Catalog {
+		return reqContext, httpx.ErrInvalidRequest("document targets catalog " + catalog + " outside the current catalog " + base.Catalog)
+	}
+	if doc.kind == catcommon.CatalogKind && doc.name != base.Catalog {
+		return reqContext, httpx.ErrInvalidRequest("catalog " + doc.name + " does not match the current catalog " + base.Catalog)
+	}
+
+	if variant := metadata.Get("variant").String(); variant != "" && variant != base.Variant {
+		catalogCtx := &catcommon.CatalogContext{
+			Catalog:   base.Catalog,
+			CatalogID: base.CatalogID,
+			Variant:   variant,
+		}
+		if err := resolveVariantInfo(ctx, catalogCtx); err != nil {
+			return reqContext, catalogmanager.ErrInvalidVariant.Msg("variant " + variant + " not found")
+		}
+		reqContext.Variant = catalogCtx.Variant
+		reqContext.VariantID = catalogCtx.VariantID
+	}
+	if namespace := metadata.Get("namespace").String(); namespace != "" {
+		reqContext.Namespace = namespace
+	}
+
+	return objectRequestContext(reqContext, doc.kind, doc.name, metadata), nil
+}
+
+// objectRequestContext returns the request context addressing the object of a kind with
+// the given name and metadata, within the catalog, variant and namespace of reqContext.
+func objectRequestContext(reqContext interfaces.RequestContext, kind, name string, metadata gjson.Result) interfaces.RequestContext {
+	switch kind {
+	case catcommon.CatalogKind:
+		reqContext.Catalog = name
+	case catcommon.VariantKind:
+		reqContext.Variant = name
+		reqContext.VariantID = uuid.Nil
+	case catcommon.NamespaceKind:
+		reqContext.Namespace = name
+	case catcommon.ViewKind, catcommon.ViewTemplateKind, catcommon.AdmissionWebhookKind:
+		reqContext.ObjectName = name
+	case catcommon.ResourceKind, catcommon.SkillSetKind:
+		reqContext.ObjectName = name
+		reqContext.ObjectPath = metadata.Get("path").String()
+		if reqContext.ObjectPath == "" {
+			reqContext.ObjectPath = "/"
+		}
+		if kind == catcommon.ResourceKind {
+			reqContext.ObjectType = catcommon.CatalogObjectTypeResource
+			reqContext.ObjectProperty = catcommon.ResourcePropertyDefinition
+		} else {
+			reqContext.ObjectType = catcommon.CatalogObjectTypeSkillset
+		}
+	}
+	return reqContext
+}
+
+func statusCodeFromError(err error) int {
+	var httpErr *httpx.Error
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	if appErr, ok := err.(apperrors.Error); ok && appErr.StatusCode() != 0 {
+		return appErr.StatusCode()
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,151 @@
+package apis
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+type authzDecisionRsp struct {
+	DecisionID   uuid.UUID `json:"decisionID"`
+	Principal    string    `json:"principal"`
+	ViewID       string    `json:"viewID,omitempty"`
+	Actions      []string  `json:"actions"`
+	Target       string    `json:"target"`
+	Allowed      bool      `json:"allowed"`
+	Enforced     bool      `json:"enforced"`
+	MatchedRules any       `json:"matchedRules,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+// listAuthzDecisions returns the authorization audit log of a catalog, newest first. The log
+// can be filtered by principal, action, target prefix, result and time range with the
+// principal, action, target, allowed, enforced, since and until query parameters. Denials
+// made under views in audit mode are listed with enforced set to false.
+func listAuthzDecisions(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	catalogName := chi.URLParam(r, "catalogName")
+	if catalogName == "" {
+		return nil, httpx.ErrInvalidRequest("catalog name is required")
+	}
+	catalogID, err := db.DB(ctx).GetCatalogIDByName(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+
+	q := r.URL.Query()
+	opts, goerr := interfaces.ListOptionsFromQuery(q)
+	if goerr != nil {
+		return nil, httpx.ErrInvalidRequest(goerr.Error())
+	}
+
+	filter := &models.AuthzDecisionFilter{
+		CatalogID: catalogID,
+		Principal: q.Get("principal"),
+		Action:    q.Get("action"),
+		Target:    q.Get("target"),
+		// Fetch one extra decision to know whether there is a next page
+		Limit: opts.Limit + 1,
+	}
+	if a := q.Get("allowed"); a != "" {
+		allowed, goerr := strconv.ParseBool(a)
+		if goerr != nil {
+			return nil, httpx.ErrInvalidRequest("invalid allowed: must be true or false")
+		}
+		filter.Allowed = &allowed
+	}
+	if e := q.Get("enforced"); e != "" {
+		enforced, goerr := strconv.ParseBool(e)
+		if goerr != nil {
+			return nil, httpx.ErrInvalidRequest("invalid enforced: must be true or false")
+		}
+		filter.Enforced = &enforced
+	}
+	if filter.Since, goerr = parseTimeParam(q.Get("since")); goerr != nil {
+		return nil, httpx.ErrInvalidRequest("invalid since: must be an RFC 3339 timestamp")
+	}
+	if filter.Until, goerr = parseTimeParam(q.Get("until")); goerr != nil {
+		return nil, httpx.ErrInvalidRequest("invalid until: must be an RFC 3339 timestamp")
+	}
+	if opts.Cursor != "" {
+		after, ok := parseDecisionCursor(opts.Cursor)
+		if !ok {
+			return nil, httpx.ErrInvalidRequest("invalid cursor")
+		}
+		filter.After = after
+	}
+
+	decisions, err := db.DB(ctx).ListAuthzDecisions(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var next string
+	if len(decisions) > opts.Limit {
+		decisions = decisions[:opts.Limit]
+		last := decisions[len(decisions)-1]
+		next = interfaces.EncodeCursor(last.CreatedAt.Format(time.RFC3339Nano) + "," + last.DecisionID.String())
+	}
+
+	items := make([]authzDecisionRsp, 0, len(decisions))
+	for _, d := range decisions {
+		item := authzDecisionRsp{
+			DecisionID: d.DecisionID,
+			Principal:  d.Principal,
+			Actions:    d.Actions,
+			Target:     d.Target,
+			Allowed:    d.Allowed,
+			Enforced:   d.Enforced,
+			CreatedAt:  d.CreatedAt,
+		}
+		if d.ViewID != uuid.Nil {
+			item.ViewID = d.ViewID.String()
+		}
+		if len(d.MatchedRules) > 0 {
+			item.MatchedRules = d.MatchedRules
+		}
+		items = append(items, item)
+	}
+
+	rsp, goerr := interfaces.MarshalList("authzDecisions", items, next)
+	if goerr != nil {
+		return nil, httpx.ErrApplicationError("unable to marshal authz decisions")
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+func parseTimeParam(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func parseDecisionCursor(cursor string) (*models.AuthzDecisionCursor, bool) {
+	createdAt, id, ok := strings.Cut(cursor, ",")
+	if !ok {
+		return nil, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, false
+	}
+	decisionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, false
+	}
+	return &models.AuthzDecisionCursor{CreatedAt: t, DecisionID: decisionID}, true
+}
@@ -0,0 +1,256 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// deleteOrder lists the kinds accepted by /delete in the order they are deleted, so that
+// objects are deleted before the variants and namespaces that hold them.
+var deleteOrder = []string{
+	catcommon.ResourceKind,
+	catcommon.SkillSetKind,
+	catcommon.AdmissionWebhookKind,
+	catcommon.ViewTemplateKind,
+	catcommon.ViewKind,
+	catcommon.NamespaceKind,
+	catcommon.VariantKind,
+}
+
+const applyStatusDeleted = "deleted"
+
+// deleteObjects deletes every object in a multi-document YAML or JSON stream. Only the kind
+// and metadata of the documents are used, so the manifests given to /apply can be sent as
+// they are. Objects are deleted in dependency order and processing stops at the first
+// failure; the response lists the outcome of every object attempted so far.
+// A variant or namespace that still holds skillsets or resources is not deleted unless
+// cascade=true, in which case its objects are deleted first and reported as well.
+// With dryRun=true, everything is deleted in a transaction that is rolled back, so the
+// response reports what would have been deleted.
+func deleteObjects(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	var cascade bool
+	if c := r.URL.Query().Get("cascade"); c != "" {
+		var goerr error
+		cascade, goerr = strconv.ParseBool(c)
+		if goerr != nil {
+			return nil, httpx.ErrInvalidRequest("invalid cascade value: " + c)
+		}
+	}
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, httpx.ErrRequestTooLarge(maxErr.Limit)
+		}
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	docs, err := parseApplyDocuments(body)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, httpx.ErrInvalidRequest("no documents to delete")
+	}
+	for _, doc := range docs {
+		if !slices.Contains(deleteOrder, doc.kind) {
+			return nil, httpx.ErrInvalidRequest("objects of kind " + doc.kind + " cannot be deleted in bulk")
+		}
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(docs, func(a, b applyDocument) int {
+		return slices.Index(deleteOrder, a.kind) - slices.Index(deleteOrder, b.kind)
+	})
+
+	rsp := ApplyRsp{DryRun: dryRun, Results: make([]ApplyResult, 0, len(docs))}
+	var deleteErr error
+	if dryRun {
+		err := runDryRun(ctx, func(ctx context.Context) apperrors.Error {
+			deleteErr = deleteDocuments(ctx, reqContext, docs, cascade, &rsp)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		deleteErr = deleteDocuments(ctx, reqContext, docs, cascade, &rsp)
+	}
+	if deleteErr != nil {
+		return &httpx.Response{
+			StatusCode: statusCodeFromError(deleteErr),
+			Response:   rsp,
+		}, nil
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// deleteDocuments deletes the objects of the documents in order, recording the outcome of
+// each object in rsp. It stops at the first failure.
+func deleteDocuments(ctx context.Context, reqContext interfaces.RequestContext, docs []applyDocument, cascade bool, rsp *ApplyRsp) error {
+	for _, doc := range docs {
+		docContext, err := requestContextForDocument(ctx, reqContext, doc)
+		if err == nil {
+			err = deleteContents(ctx, docContext, doc, cascade, rsp)
+		}
+		if err == nil {
+			var rm interfaces.KindHandler
+			rm, err = catalogmanager.ResourceManagerForKind(ctx, doc.kind, docContext)
+			if err == nil {
+				err = rm.Delete(ctx)
+			}
+		}
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("kind", doc.kind).Str("name", doc.name).Msg("failed to delete document")
+			rsp.Results = append(rsp.Results, ApplyResult{Kind: doc.kind, Name: doc.name, Status: applyStatusFailed, Error: err.Error()})
+			return err
+		}
+		rsp.Results = append(rsp.Results, ApplyResult{Kind: doc.kind, Name: doc.name, Status: applyStatusDeleted})
+		publishObjectEvent(ctx, catalogmanager.ObjectEventDeleted, doc.kind, docContext, nil, "")
+	}
+	return nil
+}
+
+// deleteContents deletes the skillsets and resources of the variant or namespace of doc,
+// and for a variant its namespaces, before the variant or namespace itself is deleted.
+// Without cascade, it fails if there is anything to delete. Other kinds hold no objects.
+func deleteContents(ctx context.Context, docContext interfaces.RequestContext, doc applyDocument, cascade bool, rsp *ApplyRsp) error {
+	if doc.kind != catcommon.VariantKind && doc.kind != catcommon.NamespaceKind {
+		return nil
+	}
+
+	variantID := docContext.VariantID
+	if doc.kind == catcommon.VariantKind {
+		catalogCtx := &catcommon.CatalogContext{
+			Catalog:   docContext.Catalog,
+			CatalogID: docContext.CatalogID,
+			Variant:   doc.name,
+		}
+		if err := resolveVariantInfo(ctx, catalogCtx); err != nil {
+			return catalogmanager.ErrVariantNotFound.Msg("variant " + doc.name + " not found")
+		}
+		variantID = catalogCtx.VariantID
+	}
+
+	objects, err := catalogmanager.ListStoredObjects(ctx, docContext.Catalog, variantID)
+	if err != nil {
+		return err
+	}
+	if doc.kind == catcommon.NamespaceKind {
+		objects = slices.DeleteFunc(objects, func(obj catalogmanager.StoredObject) bool {
+			return obj.Namespace != doc.name
+		})
+	}
+	var namespaces []string
+	if doc.kind == catcommon.VariantKind {
+		models, err := db.DB(ctx).ListNamespacesByVariant(ctx, variantID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to list namespaces")
+			return catalogmanager.ErrUnableToLoadObject.Msg("unable to list namespaces")
+		}
+		for _, ns := range models {
+			if ns.Name != catcommon.DefaultNamespace {
+				namespaces = append(namespaces, ns.Name)
+			}
+		}
+	}
+
+	if len(objects) == 0 && len(namespaces) == 0 {
+		return nil
+	}
+	if !cascade {
+		return catalogmanager.ErrNotEmpty.Msg(doc.kind + " " + doc.name + " is not empty; delete with cascade to delete its contents")
+	}
+
+	for _, obj := range objects {
+		objContext := storedObjectRequestContext(docContext, docContext.Variant, variantID, obj)
+		if err := deleteStoredObject(ctx, objContext, obj, applyStatusDeleted, rsp); err != nil {
+			return err
+		}
+	}
+	for _, name := range namespaces {
+		nsContext := docContext
+		nsContext.VariantID = variantID
+		nsContext.Namespace = name
+		rm, err := catalogmanager.ResourceManagerForKind(ctx, catcommon.NamespaceKind, nsContext)
+		if err == nil {
+			err = rm.Delete(ctx)
+		}
+		if err != nil {
+			rsp.Results = append(rsp.Results, ApplyResult{Kind: catcommon.NamespaceKind, Name: name, Status: applyStatusFailed, Error: err.Error()})
+			return err
+		}
+		rsp.Results = append(rsp.Results, ApplyResult{Kind: catcommon.NamespaceKind, Name: name, Status: applyStatusDeleted})
+		publishObjectEvent(ctx, catalogmanager.ObjectEventDeleted, catcommon.NamespaceKind, nsContext, nil, "")
+	}
+	return nil
+}
+
+// storedObjectRequestContext returns the request context addressing a stored skillset or
+// resource of a variant.
+func storedObjectRequestContext(base interfaces.RequestContext, variant string, variantID uuid.UUID, obj catalogmanager.StoredObject) interfaces.RequestContext {
+	reqContext := base
+	reqContext.Variant = variant
+	reqContext.VariantID = variantID
+	reqContext.Namespace = obj.Namespace
+	reqContext.ObjectName = obj.Name
+	reqContext.ObjectPath = obj.Path
+	if obj.Kind == catcommon.ResourceKind {
+		reqContext.ObjectType = catcommon.CatalogObjectTypeResource
+		reqContext.ObjectProperty = catcommon.ResourcePropertyDefinition
+	} else {
+		reqContext.ObjectType = catcommon.CatalogObjectTypeSkillset
+	}
+	return reqContext
+}
+
+// deleteStoredObject deletes a stored skillset or resource, recording the deletion in rsp
+// with the given status.
+func deleteStoredObject(ctx context.Context, reqContext interfaces.RequestContext, obj catalogmanager.StoredObject, status string, rsp *ApplyRsp) error {
+	result := ApplyResult{Kind: obj.Kind, Name: obj.Name, Status: applyStatusFailed}
+	rm, err := catalogmanager.ResourceManagerForKind(ctx, obj.Kind, reqContext)
+	if err == nil {
+		err = rm.Delete(ctx)
+	}
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("kind", obj.Kind).Str("name", obj.FullyQualifiedName()).Msg("failed to delete object")
+		result.Error = err.Error()
+		rsp.Results = append(rsp.Results, result)
+		return err
+	}
+	result.Status = status
+	rsp.Results = append(rsp.Results, result)
+	publishObjectEvent(ctx, catalogmanager.ObjectEventDeleted, obj.Kind, reqContext, nil, "")
+	return nil
+}
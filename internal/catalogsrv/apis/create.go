@@ -1,16 +1,22 @@
 package apis
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
 
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tidwall/gjson"
 )
 
-// createObject creates a new resource object
+// createObject creates a new resource object. With dryRun=true the object is validated and
+// created in a transaction that is rolled back, and the object that would have been created
+// is returned.
 func createObject(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
 
@@ -37,17 +43,46 @@ func createObject(r *http.Request) (*httpx.Response, error) {
 		return nil, err
 	}
 
-	manager, err := catalogmanager.ResourceManagerForKind(ctx, kind, reqContext)
+	dryRun, err := isDryRun(r)
 	if err != nil {
 		return nil, err
 	}
 
-	resourceLoc, err := manager.Create(ctx, req)
+	manager, err := catalogmanager.ResourceManagerForKind(ctx, kind, reqContext)
 	if err != nil {
-		if errors.Is(err, catalogmanager.ErrInvalidVariant) {
-			return nil, httpx.ErrInvalidVariant()
-		}
-		return nil, err
+		return nil, createError(err)
+	}
+	writeCtx, admitted, err := admitWrite(ctx, manager, req, dryRun)
+	if err != nil {
+		return nil, createError(err)
+	}
+
+	var resourceLoc string
+	var created json.RawMessage
+	create := func(ctx context.Context) apperrors.Error {
+		var err apperrors.Error
+		resourceLoc, err = manager.Create(ctx, admitted)
+		return err
+	}
+	if dryRun {
+		err = runDryRun(writeCtx, func(ctx context.Context) apperrors.Error {
+			if err := create(ctx); err != nil {
+				return err
+			}
+			// Read back the object as it would have been stored
+			objContext := objectRequestContext(reqContext, kind, gjson.GetBytes(req, "metadata.name").String(), gjson.GetBytes(req, "metadata"))
+			manager, err := catalogmanager.ResourceManagerForKind(ctx, kind, objContext)
+			if err != nil {
+				return err
+			}
+			created, err = manager.Get(ctx)
+			return err
+		})
+	} else {
+		err = create(writeCtx)
+	}
+	if err != nil {
+		return nil, createError(err)
 	}
 
 	resp := &httpx.Response{
@@ -55,6 +90,20 @@ func createObject(r *http.Request) (*httpx.Response, error) {
 		Location:   resourceLoc,
 		Response:   nil,
 	}
+	if dryRun {
+		resp.Response = created
+		return resp, nil
+	}
+
+	publishObjectEvent(ctx, catalogmanager.ObjectEventCreated, kind, reqContext, admitted, resourceLoc)
 
 	return resp, nil
 }
+
+// createError returns the error to send for a failed create.
+func createError(err error) error {
+	if errors.Is(err, catalogmanager.ErrInvalidVariant) {
+		return httpx.ErrInvalidVariant()
+	}
+	return err
+}
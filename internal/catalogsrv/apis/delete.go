@@ -25,10 +25,11 @@ func deleteObject(r *http.Request) (*httpx.Response, error) {
 		return nil, err
 	}
 
-	err = rm.Delete(ctx)
+	_, err = catalogmanager.WriteWithPreconditions(ctx, kind, rm, reqContext, preconditionsFromRequest(r), rm.Delete)
 	if err != nil {
 		return nil, err
 	}
+	publishObjectEvent(ctx, catalogmanager.ObjectEventDeleted, kind, reqContext, nil, "")
 
 	rsp := &httpx.Response{
 		StatusCode: http.StatusNoContent,
@@ -0,0 +1,83 @@
+package apis
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// diffResource compares the definition of a resource with its definition in the variant named
+// by the against query parameter, so reviewers can see the impact of a change before merging it
+// into that variant. The view must also allow reading the resource in the against variant.
+func diffResource(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	against := r.URL.Query().Get("against")
+	if against == "" {
+		return nil, httpx.ErrInvalidRequest("against is required")
+	}
+
+	m, err := resourceMetadataFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	resourcePath := strings.TrimPrefix(r.URL.Path, "/"+catcommon.KindNameResources+"/diff")
+	allowed, err := policy.CanReadResourceInVariant(ctx, against, resourcePath)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.ErrDisallowedByPolicy.Msg("not allowed to read the resource in variant " + against)
+	}
+
+	diff, err := catalogmanager.DiffResource(ctx, m, against)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   diff,
+	}, nil
+}
+
+// diffVariant compares the resources and skillsets of a variant with those of the variant
+// named by the against query parameter, such as staging against prod. The diff discloses the
+// values of both variants, so the view must allow administering both of them.
+func diffVariant(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	against := r.URL.Query().Get("against")
+	if against == "" {
+		return nil, httpx.ErrInvalidRequest("against is required")
+	}
+
+	catalogCtx := catcommon.GetCatalogContext(ctx)
+	if catalogCtx == nil || catalogCtx.CatalogID == uuid.Nil || catalogCtx.Variant == "" {
+		return nil, httpx.ErrInvalidRequest("catalog and variant are required")
+	}
+
+	allowed, err := policy.IsActionAllowedOnVariant(ctx, policy.ActionVariantAdmin, against)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, policy.ErrDisallowedByPolicy.Msg("not allowed to compare against variant " + against)
+	}
+
+	diff, err := catalogmanager.DiffVariants(ctx, catalogCtx.CatalogID, catalogCtx.Catalog, catalogCtx.Variant, against)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   diff,
+	}, nil
+}
@@ -0,0 +1,58 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// errDryRun ends the transaction of a dry run once the request has been processed, so that
+// its changes are rolled back.
+var errDryRun = apperrors.New("dry run")
+
+// isDryRun reports whether the request asks for a dry run with dryRun=true.
+func isDryRun(r *http.Request) (bool, error) {
+	d := r.URL.Query().Get("dryRun")
+	if d == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(d)
+	if err != nil {
+		return false, httpx.ErrInvalidRequest("invalid dryRun value: " + d)
+	}
+	return dryRun, nil
+}
+
+// admitWrite calls the admission webhooks for a write of rsrcJSON through rm before the
+// transaction of the write starts, telling them whether the write is a dry run. The write is
+// made with the returned context and object.
+func admitWrite(ctx context.Context, rm interfaces.KindHandler, rsrcJSON []byte, dryRun bool) (context.Context, []byte, apperrors.Error) {
+	if dryRun {
+		ctx = catcommon.WithDryRun(ctx)
+	}
+	return catalogmanager.AdmitWrite(ctx, rm, rsrcJSON)
+}
+
+// runDryRun runs fn in a transaction that is always rolled back. fn performs the writes of
+// the request, with all their validation, and may read back their results, but nothing it
+// writes to the database is persisted.
+func runDryRun(ctx context.Context, fn func(ctx context.Context) apperrors.Error) error {
+	err := db.Tx(catcommon.WithDryRun(ctx), func(ctx context.Context) apperrors.Error {
+		if err := fn(ctx); err != nil {
+			return err
+		}
+		return errDryRun
+	})
+	if errors.Is(err, errDryRun) {
+		return nil
+	}
+	return err
+}
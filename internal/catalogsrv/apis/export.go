@@ -0,0 +1,57 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+const (
+	exportFormatArchive = "tar.gz"
+	exportFormatYAML    = "yaml"
+)
+
+// exportCatalog streams all objects of a catalog as a tar.gz archive, or as a
+// multi-document YAML stream when the format query parameter is "yaml".
+func exportCatalog(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	catalogName := chi.URLParam(r, "catalogName")
+	if catalogName == "" {
+		return nil, httpx.ErrInvalidRequest("catalog name is required")
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = exportFormatArchive
+	}
+	if format != exportFormatArchive && format != exportFormatYAML {
+		return nil, httpx.ErrInvalidRequest("unsupported export format: " + format)
+	}
+
+	manifest, err := catalogmanager.ExportCatalog(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &httpx.Response{
+		StatusCode: http.StatusOK,
+		Chunked:    true,
+	}
+	if format == exportFormatYAML {
+		rsp.ContentType = "application/yaml"
+		rsp.Header = http.Header{"Content-Disposition": {`attachment; filename="` + catalogName + `.yaml"`}}
+		rsp.WriteChunks = func(w http.ResponseWriter) error {
+			return manifest.WriteYAML(w)
+		}
+	} else {
+		rsp.ContentType = "application/gzip"
+		rsp.Header = http.Header{"Content-Disposition": {`attachment; filename="` + catalogName + `.tar.gz"`}}
+		rsp.WriteChunks = func(w http.ResponseWriter) error {
+			return manifest.WriteArchive(w)
+		}
+	}
+	return rsp, nil
+}
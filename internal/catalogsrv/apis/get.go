@@ -2,9 +2,11 @@ package apis
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
@@ -24,6 +26,21 @@ func getObject(r *http.Request) (*httpx.Response, error) {
 		return nil, httpx.ErrInvalidRequest()
 	}
 
+	if inherit := r.URL.Query().Get("inherit"); inherit != "" && kind == catcommon.ResourceKind {
+		resolve, goerr := strconv.ParseBool(inherit)
+		if goerr != nil {
+			return nil, httpx.ErrInvalidRequest("invalid inherit value: " + inherit)
+		}
+		if resolve && reqContext.ObjectProperty == catcommon.ResourcePropertyValue {
+			return getInheritedResourceValue(r)
+		}
+	}
+
+	fields, err := catalogmanager.ParseFieldList(r.URL.Query().Get("fields"))
+	if err != nil {
+		return nil, err
+	}
+
 	rm, err := catalogmanager.ResourceManagerForKind(ctx, kind, reqContext)
 	if err != nil {
 		return nil, err
@@ -34,13 +51,34 @@ func getObject(r *http.Request) (*httpx.Response, error) {
 		return nil, err
 	}
 
+	// The ETag always identifies the full object so it can be used for conditional writes
+	etag := catalogmanager.ETag(rsrc)
+	rsrc, err = catalogmanager.SelectFields(rsrc, fields)
+	if err != nil {
+		return nil, err
+	}
+
 	rsp := &httpx.Response{
 		StatusCode: http.StatusOK,
 		Response:   rsrc,
+		Header:     http.Header{"Etag": {etag}},
 	}
+	addWarnings(rsp.Header, rm)
 	return rsp, nil
 }
 
+// addWarnings adds the warnings of the kind handler, such as deprecations of the objects it
+// served, to the header as Warning headers.
+func addWarnings(h http.Header, rm interfaces.KindHandler) {
+	w, ok := rm.(interfaces.Warner)
+	if !ok {
+		return
+	}
+	for _, warning := range w.Warnings() {
+		h.Add("Warning", "299 - "+strconv.Quote(warning))
+	}
+}
+
 type StatusRsp struct {
 	UserID        string                 `json:"userID,omitempty"`
 	ServerTime    string                 `json:"serverTime,omitempty"`
@@ -0,0 +1,63 @@
+package apis
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// importCatalog recreates a catalog from an archive produced by exportCatalog.
+// The on_conflict query parameter selects how existing objects with different
+// content are treated (fail, skip or overwrite) and dry_run=true reports the
+// planned changes without applying them.
+func importCatalog(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	archive, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, httpx.ErrRequestTooLarge(maxErr.Limit)
+		}
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	opts := catalogmanager.ImportOptions{
+		OnConflict: r.URL.Query().Get("on_conflict"),
+	}
+	if dryRun := r.URL.Query().Get("dry_run"); dryRun != "" {
+		opts.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			return nil, httpx.ErrInvalidRequest("invalid dry_run value: " + dryRun)
+		}
+	}
+
+	manifest, aerr := catalogmanager.ReadExportArchive(bytes.NewReader(archive))
+	if aerr != nil {
+		return nil, aerr
+	}
+
+	report, aerr := catalogmanager.ImportCatalog(ctx, manifest, opts)
+	if aerr != nil {
+		if report == nil {
+			return nil, aerr
+		}
+		return &httpx.Response{
+			StatusCode: statusCodeFromError(aerr),
+			Response:   report,
+		}, nil
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   report,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package apis
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// maxLintDocuments bounds the number of documents in a lint request
+const maxLintDocuments = 100
+
+type lintRsp struct {
+	Valid   bool                         `json:"valid"`
+	Results []*catalogmanager.LintResult `json:"results"`
+}
+
+// lintObjects lints a multi-document YAML or JSON stream of catalog objects without applying
+// them, so that the CLI can report issues before /apply. Each document is linted on its own.
+// The response is 200 with valid set to false if any document has an error finding; warnings
+// do not make the documents invalid.
+func lintObjects(r *http.Request) (*httpx.Response, error) {
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	body, goerr := io.ReadAll(r.Body)
+	if goerr != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(goerr, &maxErr) {
+			return nil, httpx.ErrRequestTooLarge(maxErr.Limit)
+		}
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	docs, goerr := decodeDocuments(body)
+	if goerr != nil {
+		return nil, goerr
+	}
+	if len(docs) == 0 {
+		return nil, httpx.ErrInvalidRequest("no documents to lint")
+	}
+	if len(docs) > maxLintDocuments {
+		return nil, httpx.ErrInvalidRequest("too many documents in request")
+	}
+
+	rsp := lintRsp{Valid: true, Results: make([]*catalogmanager.LintResult, 0, len(docs))}
+	for _, doc := range docs {
+		result := catalogmanager.LintDocument(doc)
+		rsp.Valid = rsp.Valid && !result.HasErrors()
+		rsp.Results = append(rsp.Results, result)
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
@@ -4,10 +4,14 @@ import (
 	"net/http"
 
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
 )
 
+// listObjects returns a page of the objects of a kind. The limit, cursor, labelSelector and
+// includeDeleted query parameters are described by interfaces.ListOptions; pages are taken
+// from the full list in memory, see interfaces.Paginate.
 func listObjects(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
 	var kind string
@@ -16,6 +20,11 @@ func listObjects(r *http.Request) (*httpx.Response, error) {
 	if err != nil {
 		return nil, err
 	}
+	listOptions, err := interfaces.ListOptionsFromQuery(reqContext.QueryParams)
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest(err.Error())
+	}
+	reqContext.ListOptions = listOptions
 
 	kind = getResourceKind(r)
 	if kind == catcommon.InvalidKind {
@@ -35,6 +44,8 @@ func listObjects(r *http.Request) (*httpx.Response, error) {
 	rsp := &httpx.Response{
 		StatusCode: http.StatusOK,
 		Response:   rsrc,
+		Header:     http.Header{},
 	}
+	addWarnings(rsp.Header, rm)
 	return rsp, nil
 }
@@ -10,6 +10,7 @@ import (
 	"net/url"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
@@ -38,6 +39,9 @@ func withContext(r *http.Request) (*http.Request, error) {
 		if err != nil {
 			return r, fmt.Errorf("failed to load metadata from body: %w", err)
 		}
+	} else if r.Method == http.MethodPatch && r.Body != nil {
+		// A merge patch only describes changes, so the target comes from the URL alone
+		r.Body = http.MaxBytesReader(httptest.NewRecorder(), r.Body, config.Config().MaxRequestBodySize)
 	}
 
 	// Try to resolve project ID and catalog info if needed
@@ -64,6 +68,10 @@ func withContext(r *http.Request) (*http.Request, error) {
 		return r, fmt.Errorf("failed to resolve catalog info: %w", err)
 	}
 
+	if err := resolveDefaultVariant(ctx, r, catalogCtx); err != nil {
+		return r, fmt.Errorf("failed to resolve default variant: %w", err)
+	}
+
 	// Resolve variant information
 	if err := resolveVariantInfo(ctx, catalogCtx); err != nil {
 		return r, fmt.Errorf("failed to resolve variant info: %w", err)
@@ -233,6 +241,25 @@ func resolveCatalogInfo(ctx context.Context, catalogCtx *catcommon.CatalogContex
 	return nil
 }
 
+// resolveDefaultVariant sets the variant of requests for objects in a variant that do not name
+// one to the default variant of the catalog.
+func resolveDefaultVariant(ctx context.Context, r *http.Request, catalogCtx *catcommon.CatalogContext) error {
+	if catalogCtx == nil || catalogCtx.CatalogID == uuid.Nil || catalogCtx.VariantID != uuid.Nil || catalogCtx.Variant != "" {
+		return nil
+	}
+	switch getResourceNameFromPath(r) {
+	case catcommon.KindNameNamespaces, catcommon.KindNameResources, catcommon.KindNameSkillsets:
+	default:
+		return nil
+	}
+	catalog, err := db.DB(ctx).GetCatalogByID(ctx, catalogCtx.CatalogID)
+	if err != nil {
+		return fmt.Errorf("failed to get catalog by ID: %w", err)
+	}
+	catalogCtx.Variant = catalogmanager.DefaultVariantOf(catalog)
+	return nil
+}
+
 // resolveVariantInfo resolves variant information using either name or ID
 func resolveVariantInfo(ctx context.Context, catalogCtx *catcommon.CatalogContext) error {
 	if catalogCtx == nil {
@@ -0,0 +1,104 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/mergepatch"
+)
+
+// patchObject applies an RFC 7386 merge patch to an existing resource object.
+// The current object is loaded, patched and then saved through the kind's Update
+// so that the same validation applies as for a full PUT.
+func patchObject(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergepatch.ContentType && mediaType != "application/json") {
+			return nil, httpx.ErrUnsupportedMediaType(contentType)
+		}
+	}
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, httpx.ErrRequestTooLarge(maxErr.Limit)
+		}
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+	kind := getResourceKind(r)
+	if kind == catcommon.InvalidKind {
+		return nil, httpx.ErrInvalidRequest("invalid resource kind")
+	}
+
+	rm, err := catalogmanager.ResourceManagerForKind(ctx, kind, reqContext)
+	if err != nil {
+		return nil, err
+	}
+
+	// The patched object is not returned, so secrets redacted by Get are patched as stored
+	get := rm.Get
+	if g, ok := rm.(interfaces.UnredactedGetter); ok {
+		get = g.GetUnredacted
+	}
+
+	current, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	patched, err := mergepatch.Apply(current, patch)
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest(err.Error())
+	}
+
+	if err := validateRequest(patched, kind); err != nil {
+		return nil, err
+	}
+
+	// Admission webhooks are called before the transaction of the write starts, so the patch
+	// is written only if the object is unchanged when the transaction runs
+	writeCtx, patched, err := admitWrite(ctx, rm, patched, false)
+	if err != nil {
+		return nil, err
+	}
+	etag, err := catalogmanager.WriteWithPreconditions(writeCtx, kind, rm, reqContext, preconditionsFromRequest(r), func(ctx context.Context) apperrors.Error {
+		latest, err := get(ctx)
+		if err != nil {
+			return err
+		}
+		if catalogmanager.ETag(latest) != catalogmanager.ETag(current) {
+			return catalogmanager.ErrConcurrentWrite
+		}
+		return rm.Update(ctx, patched)
+	})
+	if err != nil {
+		return nil, err
+	}
+	publishObjectEvent(ctx, catalogmanager.ObjectEventUpdated, kind, reqContext, patched, r.URL.Path)
+
+	rsp := &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   nil,
+	}
+	setETag(rsp, etag)
+	return rsp, nil
+}
@@ -0,0 +1,46 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+type viewPermissionsRsp struct {
+	View        string                       `json:"view"`
+	Permissions []policy.EffectivePermission `json:"permissions"`
+}
+
+// getViewPermissions reports the effective permissions of a view for security review. The
+// wildcards in the view's rules are expanded against the current contents of the catalog, and
+// the response lists every concrete (action, resource) pair the view grants.
+func getViewPermissions(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	viewName := chi.URLParam(r, "viewName")
+	if viewName == "" {
+		return nil, httpx.ErrInvalidRequest("view name is required")
+	}
+
+	vm, err := policy.NewViewManagerByViewLabel(ctx, viewName)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := catalogmanager.ListCatalogObjects(ctx, catcommon.GetCatalog(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: viewPermissionsRsp{
+			View:        vm.Name(),
+			Permissions: policy.EffectivePermissions(vm.GetViewDefinition(), objects),
+		},
+	}, nil
+}
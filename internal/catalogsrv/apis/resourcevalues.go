@@ -0,0 +1,206 @@
+package apis
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+type resourceValue struct {
+	Resource string            `json:"resource"`
+	Value    types.NullableAny `json:"value"`
+}
+
+type updateResourceValuesReq struct {
+	Values []resourceValue `json:"values"`
+}
+
+// updateResourceValues sets the values of several resources of the variant in one request.
+// All values are validated against their schemas and then saved together, so either every
+// resource is updated or none is. The view must allow writing the value of each resource.
+func updateResourceValues(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest()
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+	var req updateResourceValuesReq
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, httpx.ErrInvalidRequest("unable to parse request")
+	}
+	if len(req.Values) == 0 {
+		return nil, httpx.ErrInvalidRequest("values are required")
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]catalogmanager.ResourceValueUpdate, 0, len(req.Values))
+	contexts := make([]interfaces.RequestContext, 0, len(req.Values))
+	for _, v := range req.Values {
+		resourcePath := strings.TrimPrefix(v.Resource, "/")
+		if resourcePath == "" {
+			return nil, httpx.ErrInvalidRequest("resource is required")
+		}
+		allowed, err := policy.CanPutResourceValue(ctx, resourcePath)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, policy.ErrDisallowedByPolicy.Msg("not allowed to update the value of resource " + v.Resource)
+		}
+
+		resourceContext := reqContext
+		resourceContext.ObjectName, resourceContext.ObjectPath = processPath(resourcePath)
+		resourceContext.ObjectType = catcommon.CatalogObjectTypeResource
+		resourceContext.ObjectProperty = catcommon.ResourcePropertyValue
+		contexts = append(contexts, resourceContext)
+		updates = append(updates, catalogmanager.ResourceValueUpdate{
+			Metadata: &interfaces.Metadata{
+				Catalog:   resourceContext.Catalog,
+				Variant:   types.NullableStringFrom(resourceContext.Variant),
+				Namespace: types.NullableStringFrom(resourceContext.Namespace),
+				Path:      resourceContext.ObjectPath,
+				Name:      resourceContext.ObjectName,
+			},
+			Value: v.Value,
+		})
+	}
+
+	if err := catalogmanager.UpdateResourceValues(ctx, updates); err != nil {
+		return nil, err
+	}
+	for _, resourceContext := range contexts {
+		publishObjectEvent(ctx, catalogmanager.ObjectEventUpdated, catcommon.ResourceKind, resourceContext, nil, "")
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   nil,
+	}, nil
+}
+
+// getInheritedResourceValue returns the value of a resource, inherited from the nearest
+// resource of the same name and schema at an ancestor path if it has none, along with the
+// resources consulted to resolve it.
+func getInheritedResourceValue(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	m, err := resourceMetadataFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := catalogmanager.ResolveResourceValue(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   resolved,
+	}, nil
+}
+
+const (
+	renderFormatDotenv = "dotenv"
+	renderFormatJSON   = "json"
+	renderFormatYAML   = "yaml"
+)
+
+// renderResourceValues renders the values of the resources at or below a path that are
+// annotated with an environment variable as configuration for an application: a dotenv file,
+// or a JSON or YAML object keyed by variable name, chosen by the format query parameter.
+func renderResourceValues(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = renderFormatDotenv
+	}
+	if format != renderFormatDotenv && format != renderFormatJSON && format != renderFormatYAML {
+		return nil, httpx.ErrInvalidRequest("unsupported render format: " + format)
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+	dir := strings.TrimPrefix(r.URL.Path, "/"+catcommon.KindNameResources+"/render")
+	values, err := catalogmanager.ListEnvValues(ctx, reqContext.Catalog, reqContext.VariantID, reqContext.Namespace, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == renderFormatDotenv {
+		var b strings.Builder
+		for _, v := range values {
+			b.WriteString(v.Name + "=" + dotenvValue(v.Value) + "\n")
+		}
+		return &httpx.Response{
+			StatusCode:  http.StatusOK,
+			ContentType: "text/plain",
+			Response:    b.String(),
+		}, nil
+	}
+
+	rendered := make(map[string]json.RawMessage, len(values))
+	for _, v := range values {
+		rendered[v.Name] = v.Value
+	}
+	if format == renderFormatJSON {
+		return &httpx.Response{
+			StatusCode: http.StatusOK,
+			Response:   rendered,
+		}, nil
+	}
+	j, goErr := json.Marshal(rendered)
+	if goErr != nil {
+		return nil, httpx.ErrApplicationError("unable to render values")
+	}
+	y, goErr := yaml.JSONToYAML(j)
+	if goErr != nil {
+		return nil, httpx.ErrApplicationError("unable to render values")
+	}
+	return &httpx.Response{
+		StatusCode:  http.StatusOK,
+		ContentType: "application/yaml",
+		Chunked:     true,
+		WriteChunks: func(w http.ResponseWriter) error {
+			_, err := w.Write(y)
+			return err
+		},
+	}, nil
+}
+
+// dotenvValue formats a JSON value for a dotenv file. Strings are written as is unless they
+// need quoting, null as an empty value, and all other values as JSON.
+func dotenvValue(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err == nil {
+		if s == "" || strings.ContainsAny(s, " \t\r\n\"'#$\\`") {
+			return strconv.Quote(s)
+		}
+		return s
+	}
+	if bytes.Equal(bytes.TrimSpace(value), []byte("null")) {
+		return ""
+	}
+	return string(value)
+}
@@ -0,0 +1,64 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// The restore routes name their path parameter differently from the other catalog and variant
+// routes, so that CatalogContextLoader does not try to resolve an object that is deleted.
+
+// restoreCatalog restores a soft deleted catalog of the project. The caller must be an admin
+// of the catalog; as the policy middleware cannot resolve a deleted catalog, this is checked
+// here against the catalog name.
+func restoreCatalog(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	name := chi.URLParam(r, "deletedCatalogName")
+	if name == "" {
+		return nil, httpx.ErrInvalidRequest("catalog name is required")
+	}
+	catalog := policy.CatalogObject{
+		Kind:     catcommon.KindNameCatalogs,
+		Resource: policy.TargetResource("res://catalogs/" + name),
+	}
+	if !policy.CanWriteObject(ctx, catalog) {
+		return nil, policy.ErrDisallowedByPolicy
+	}
+	if err := catalogmanager.RestoreCatalogByName(ctx, name); err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+		Response:   nil,
+	}, nil
+}
+
+// restoreVariant restores a soft deleted variant of the catalog in context.
+func restoreVariant(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	name := chi.URLParam(r, "deletedVariantName")
+	if name == "" {
+		return nil, httpx.ErrInvalidRequest("variant name is required")
+	}
+	catalogCtx := catcommon.GetCatalogContext(ctx)
+	if catalogCtx == nil || catalogCtx.CatalogID == uuid.Nil {
+		return nil, httpx.ErrInvalidRequest("catalog is required")
+	}
+	if err := catalogmanager.RestoreVariant(ctx, catalogCtx.CatalogID, name); err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+		Response:   nil,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package apis
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/pkg/types"
+)
+
+type resourceRevisionsRsp struct {
+	Revisions []catalogmanager.ResourceRevision `json:"revisions"`
+}
+
+type restoreResourceRevisionReq struct {
+	Revision  string `json:"revision"`
+	ValueOnly bool   `json:"valueOnly"`
+}
+
+// listResourceRevisions lists the current and previous revisions of a resource.
+func listResourceRevisions(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	m, err := resourceMetadataFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := catalogmanager.ListResourceRevisions(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   resourceRevisionsRsp{Revisions: revisions},
+	}, nil
+}
+
+// restoreResourceRevision makes a previous revision of a resource its current revision. With
+// valueOnly set, only the value of the revision is restored and the current definition kept.
+func restoreResourceRevision(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest()
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+	var req restoreResourceRevisionReq
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, httpx.ErrInvalidRequest("unable to parse request")
+	}
+	if req.Revision == "" {
+		return nil, httpx.ErrInvalidRequest("revision is required")
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+	m, err := resourceMetadataFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	restore := catalogmanager.RestoreResourceRevision
+	if req.ValueOnly {
+		restore = catalogmanager.RestoreResourceValue
+	}
+	if err := restore(ctx, m, req.Revision); err != nil {
+		return nil, err
+	}
+	publishObjectEvent(ctx, catalogmanager.ObjectEventUpdated, catcommon.ResourceKind, reqContext, nil, "")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   nil,
+	}, nil
+}
+
+// resourceMetadataFromRequest returns the metadata of the resource addressed by a request.
+func resourceMetadataFromRequest(r *http.Request) (*interfaces.Metadata, error) {
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+	m := &interfaces.Metadata{
+		Catalog:   reqContext.Catalog,
+		Variant:   types.NullableStringFrom(reqContext.Variant),
+		Namespace: types.NullableStringFrom(reqContext.Namespace),
+		Path:      reqContext.ObjectPath,
+		Name:      reqContext.ObjectName,
+	}
+	if err := m.Validate(); err != nil {
+		return nil, httpx.ErrInvalidRequest(err.Error())
+	}
+	return m, nil
+}
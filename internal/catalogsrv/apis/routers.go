@@ -28,6 +28,16 @@ var userSessionHandlers = []policy.ResponseHandlerParam{
 		Path:    "/catalogs",
 		Handler: listObjects,
 	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/catalogs/import",
+		Handler: importCatalog,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/catalogs/{deletedCatalogName}/restore",
+		Handler: restoreCatalog,
+	},
 }
 
 // resourceObjectHandlers defines the API routes and their authorization requirements.
@@ -45,18 +55,42 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        updateObject,
 		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
 	},
+	{
+		Method:         http.MethodPatch,
+		Path:           "/catalogs/{catalogName}",
+		Handler:        patchObject,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
 	{
 		Method:         http.MethodDelete,
 		Path:           "/catalogs/{catalogName}",
 		Handler:        deleteObject,
 		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
 	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/catalogs/{catalogName}/export",
+		Handler:        exportCatalog,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/catalogs/{catalogName}/authz-decisions",
+		Handler:        listAuthzDecisions,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
 	{
 		Method:         http.MethodPost,
 		Path:           "/variants",
 		Handler:        createObject,
 		AllowedActions: []policy.Action{policy.ActionVariantClone},
 	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/variants",
+		Handler:        listObjects,
+		AllowedActions: []policy.Action{policy.ActionVariantList},
+	},
 	{
 		Method:         http.MethodGet,
 		Path:           "/variants/{variantName}",
@@ -69,18 +103,54 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        updateObject,
 		AllowedActions: []policy.Action{policy.ActionVariantAdmin},
 	},
+	{
+		Method:         http.MethodPatch,
+		Path:           "/variants/{variantName}",
+		Handler:        patchObject,
+		AllowedActions: []policy.Action{policy.ActionVariantAdmin},
+	},
 	{
 		Method:         http.MethodDelete,
 		Path:           "/variants/{variantName}",
 		Handler:        deleteObject,
 		AllowedActions: []policy.Action{policy.ActionVariantAdmin},
 	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/variants/{variantName}/default",
+		Handler:        setDefaultVariant,
+		AllowedActions: []policy.Action{policy.ActionVariantAdmin},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/variants/{variantName}/diff",
+		Handler:        diffVariant,
+		AllowedActions: []policy.Action{policy.ActionVariantAdmin},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/variants/{variantName}/clone",
+		Handler:        cloneVariant,
+		AllowedActions: []policy.Action{policy.ActionVariantClone},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/variants/{deletedVariantName}/restore",
+		Handler:        restoreVariant,
+		AllowedActions: []policy.Action{policy.ActionVariantAdmin},
+	},
 	{
 		Method:         http.MethodPost,
 		Path:           "/namespaces",
 		Handler:        createObject,
 		AllowedActions: []policy.Action{policy.ActionNamespaceCreate},
 	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/namespaces",
+		Handler:        listObjects,
+		AllowedActions: []policy.Action{policy.ActionNamespaceList},
+	},
 	{
 		Method:         http.MethodGet,
 		Path:           "/namespaces/{namespaceName}",
@@ -93,6 +163,12 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        updateObject,
 		AllowedActions: []policy.Action{policy.ActionNamespaceAdmin},
 	},
+	{
+		Method:         http.MethodPatch,
+		Path:           "/namespaces/{namespaceName}",
+		Handler:        patchObject,
+		AllowedActions: []policy.Action{policy.ActionNamespaceAdmin},
+	},
 	{
 		Method:         http.MethodDelete,
 		Path:           "/namespaces/{namespaceName}",
@@ -105,6 +181,36 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        createObject,
 		AllowedActions: []policy.Action{policy.ActionCatalogCreateView},
 	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/apply",
+		Handler:        applyObjects,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/delete",
+		Handler:        deleteObjects,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/lint",
+		Handler:        lintObjects,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/watch",
+		Handler:        watchObjects,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/search",
+		Handler:        searchCatalog,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
 	{
 		Method:         http.MethodGet,
 		Path:           "/status",
@@ -112,6 +218,12 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		AllowedActions: []policy.Action{policy.ActionAllow},
 		Options:        []policy.HandlerOptions{policy.SkipViewDefValidation(true)},
 	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/views:validate",
+		Handler:        validateViews,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
 	{
 		Method:         http.MethodGet,
 		Path:           "/views/{viewName}",
@@ -124,12 +236,102 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        updateObject,
 		AllowedActions: []policy.Action{policy.ActionViewAdmin},
 	},
+	{
+		Method:         http.MethodPatch,
+		Path:           "/views/{viewName}",
+		Handler:        patchObject,
+		AllowedActions: []policy.Action{policy.ActionViewAdmin},
+	},
 	{
 		Method:         http.MethodDelete,
 		Path:           "/views/{viewName}",
 		Handler:        deleteObject,
 		AllowedActions: []policy.Action{policy.ActionViewAdmin},
 	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/views/{viewName}/permissions",
+		Handler:        getViewPermissions,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/views/simulate",
+		Handler:        simulateViewDocument,
+		AllowedActions: []policy.Action{policy.ActionCatalogCreateView},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/views/{viewName}/simulate",
+		Handler:        simulateView,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/viewtemplates",
+		Handler:        createObject,
+		AllowedActions: []policy.Action{policy.ActionCatalogCreateView},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/viewtemplates",
+		Handler:        listObjects,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/viewtemplates/{templateName}",
+		Handler:        getObject,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodPut,
+		Path:           "/viewtemplates/{templateName}",
+		Handler:        updateObject,
+		AllowedActions: []policy.Action{policy.ActionViewAdmin},
+	},
+	{
+		Method:         http.MethodDelete,
+		Path:           "/viewtemplates/{templateName}",
+		Handler:        deleteObject,
+		AllowedActions: []policy.Action{policy.ActionViewAdmin},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/viewtemplates/{templateName}/instantiate",
+		Handler:        instantiateViewTemplate,
+		AllowedActions: []policy.Action{policy.ActionCatalogCreateView},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/admissionwebhooks",
+		Handler:        createObject,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/admissionwebhooks",
+		Handler:        listObjects,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/admissionwebhooks/{webhookName}",
+		Handler:        getObject,
+		AllowedActions: []policy.Action{policy.ActionCatalogList},
+	},
+	{
+		Method:         http.MethodPut,
+		Path:           "/admissionwebhooks/{webhookName}",
+		Handler:        updateObject,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
+	{
+		Method:         http.MethodDelete,
+		Path:           "/admissionwebhooks/{webhookName}",
+		Handler:        deleteObject,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
 	{
 		Method:         http.MethodPost,
 		Path:           "/resources",
@@ -142,6 +344,12 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        listObjects,
 		AllowedActions: []policy.Action{policy.ActionResourceList},
 	},
+	{
+		Method:         http.MethodPatch,
+		Path:           "/resources",
+		Handler:        updateResourceValues,
+		AllowedActions: []policy.Action{policy.ActionResourceList, policy.ActionResourcePut},
+	},
 	{
 		Method:         http.MethodGet,
 		Path:           "/resources/definition/*",
@@ -154,12 +362,42 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        updateObject,
 		AllowedActions: []policy.Action{policy.ActionResourceEdit},
 	},
+	{
+		Method:         http.MethodPatch,
+		Path:           "/resources/definition/*",
+		Handler:        patchObject,
+		AllowedActions: []policy.Action{policy.ActionResourceEdit},
+	},
 	{
 		Method:         http.MethodDelete,
 		Path:           "/resources/definition/*",
 		Handler:        deleteObject,
 		AllowedActions: []policy.Action{policy.ActionResourceDelete},
 	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/resources/diff/*",
+		Handler:        diffResource,
+		AllowedActions: []policy.Action{policy.ActionResourceRead, policy.ActionResourceEdit},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/resources/revisions/*",
+		Handler:        listResourceRevisions,
+		AllowedActions: []policy.Action{policy.ActionResourceRead, policy.ActionResourceEdit},
+	},
+	{
+		Method:         http.MethodPost,
+		Path:           "/resources/revisions/*",
+		Handler:        restoreResourceRevision,
+		AllowedActions: []policy.Action{policy.ActionResourceEdit},
+	},
+	{
+		Method:         http.MethodGet,
+		Path:           "/resources/render/*",
+		Handler:        renderResourceValues,
+		AllowedActions: []policy.Action{policy.ActionResourceGet, policy.ActionResourcePut},
+	},
 	{
 		Method:         http.MethodGet,
 		Path:           "/resources/*",
@@ -172,6 +410,12 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        updateObject,
 		AllowedActions: []policy.Action{policy.ActionResourcePut},
 	},
+	{
+		Method:         http.MethodPatch,
+		Path:           "/resources/*",
+		Handler:        patchObject,
+		AllowedActions: []policy.Action{policy.ActionResourcePut},
+	},
 	{
 		Method:         http.MethodPost,
 		Path:           "/skillsets",
@@ -196,6 +440,12 @@ var resourceObjectHandlers = []policy.ResponseHandlerParam{
 		Handler:        updateObject,
 		AllowedActions: []policy.Action{policy.ActionSkillSetAdmin},
 	},
+	{
+		Method:         http.MethodPatch,
+		Path:           "/skillsets/*",
+		Handler:        patchObject,
+		AllowedActions: []policy.Action{policy.ActionSkillSetAdmin},
+	},
 	{
 		Method:         http.MethodDelete,
 		Path:           "/skillsets/*",
@@ -0,0 +1,41 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+type searchRsp struct {
+	Hits []catalogmanager.SearchHit `json:"hits"`
+}
+
+// searchCatalog finds the objects of the catalog whose names, descriptions, annotations or
+// schema property names match the words of the q query parameter. The search is narrowed to a
+// variant with the variant query parameter and to kinds with the kind query parameter.
+func searchCatalog(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	catalogCtx := catcommon.GetCatalogContext(ctx)
+	if catalogCtx == nil || catalogCtx.Catalog == "" {
+		return nil, httpx.ErrInvalidRequest("catalog is required")
+	}
+
+	opts, goerr := interfaces.SearchOptionsFromQuery(r.URL.Query())
+	if goerr != nil {
+		return nil, httpx.ErrInvalidRequest(goerr.Error())
+	}
+
+	hits, err := catalogmanager.SearchCatalog(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   searchRsp{Hits: hits},
+	}, nil
+}
@@ -0,0 +1,212 @@
+package apis
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tidwall/gjson"
+)
+
+// maxSimulationChecks bounds the number of checks in a batch simulation request
+const maxSimulationChecks = 100
+
+type simulationCheck struct {
+	Action policy.Action `json:"action"`
+	Target string        `json:"target"`
+}
+
+type simulateViewReq struct {
+	simulationCheck
+	Checks []simulationCheck `json:"checks,omitempty"`
+}
+
+type simulateViewRsp struct {
+	View    string                     `json:"view"`
+	Results []*policy.SimulationResult `json:"results"`
+}
+
+// simulateView evaluates actions on targets against a view's rules without performing them,
+// to debug a view before rolling it out. The request is either a single
+// {"action": ..., "target": ...} check or a batch of them in "checks". With ?explain=true,
+// each result also explains how the view's rules decided it.
+func simulateView(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	viewName := chi.URLParam(r, "viewName")
+	if viewName == "" {
+		return nil, httpx.ErrInvalidRequest("view name is required")
+	}
+
+	req := simulateViewReq{}
+	if err := readSimulationRequest(r, &req); err != nil {
+		return nil, err
+	}
+	checks, err := req.checks()
+	if err != nil {
+		return nil, err
+	}
+	explain, err := isExplain(r)
+	if err != nil {
+		return nil, err
+	}
+
+	vm, err := policy.NewViewManagerByViewLabel(ctx, viewName)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := simulateChecks(vm, checks, explain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+type simulateViewDocumentReq struct {
+	simulateViewReq
+	View json.RawMessage `json:"view"`
+}
+
+// simulateViewDocument evaluates checks against a view given in the request as a View
+// document, so that a view can be tested before it is created or updated. The view is
+// applied in a dry run, with the same validation as a create or update, and the checks are
+// evaluated against it before the dry run is rolled back. The checks are given as for
+// simulateView.
+func simulateViewDocument(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	req := simulateViewDocumentReq{}
+	if err := readSimulationRequest(r, &req); err != nil {
+		return nil, err
+	}
+	if len(req.View) == 0 {
+		return nil, httpx.ErrInvalidRequest("view is required")
+	}
+	if err := validateRequest(req.View, catcommon.ViewKind); err != nil {
+		return nil, err
+	}
+	doc := applyDocument{
+		kind: catcommon.ViewKind,
+		name: gjson.GetBytes(req.View, "metadata.name").String(),
+		json: req.View,
+	}
+	if doc.name == "" {
+		return nil, httpx.ErrInvalidRequest("missing metadata.name in view")
+	}
+	checks, err := req.checks()
+	if err != nil {
+		return nil, err
+	}
+	explain, err := isExplain(r)
+	if err != nil {
+		return nil, err
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rsp simulateViewRsp
+	var simulateErr error
+	err = runDryRun(ctx, func(ctx context.Context) apperrors.Error {
+		docContext, err := requestContextForDocument(ctx, reqContext, doc)
+		if err == nil {
+			_, err = applyDocumentInContext(ctx, docContext, doc)
+		}
+		if err != nil {
+			simulateErr = err
+			return nil
+		}
+		vm, aerr := policy.NewViewManagerByViewLabel(ctx, doc.name)
+		if aerr != nil {
+			return aerr
+		}
+		rsp, simulateErr = simulateChecks(vm, checks, explain)
+		return nil
+	})
+	if err == nil {
+		err = simulateErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// readSimulationRequest reads the JSON body of a simulation request into req.
+func readSimulationRequest(r *http.Request, req any) error {
+	if r.Body == nil {
+		return httpx.ErrInvalidRequest("request body is required")
+	}
+	body, goerr := io.ReadAll(r.Body)
+	if goerr != nil {
+		return httpx.ErrUnableToReadRequest()
+	}
+	if goerr := json.Unmarshal(body, req); goerr != nil {
+		return httpx.ErrInvalidRequest("unable to parse request: " + goerr.Error())
+	}
+	return nil
+}
+
+// checks returns the checks of the request, either the single check or the batch.
+func (req *simulateViewReq) checks() ([]simulationCheck, error) {
+	checks := req.Checks
+	if req.Action != "" || req.Target != "" {
+		if len(checks) > 0 {
+			return nil, httpx.ErrInvalidRequest("specify either a single check or checks, not both")
+		}
+		checks = []simulationCheck{req.simulationCheck}
+	}
+	if len(checks) == 0 {
+		return nil, httpx.ErrInvalidRequest("no checks to simulate")
+	}
+	if len(checks) > maxSimulationChecks {
+		return nil, httpx.ErrInvalidRequest("too many checks in request")
+	}
+	return checks, nil
+}
+
+// isExplain reports whether the request asks for explanations with explain=true.
+func isExplain(r *http.Request) (bool, error) {
+	e := r.URL.Query().Get("explain")
+	if e == "" {
+		return false, nil
+	}
+	explain, goerr := strconv.ParseBool(e)
+	if goerr != nil {
+		return false, httpx.ErrInvalidRequest("invalid explain: must be true or false")
+	}
+	return explain, nil
+}
+
+// simulateChecks evaluates each check against the view of vm.
+func simulateChecks(vm policy.ViewManager, checks []simulationCheck, explain bool) (simulateViewRsp, error) {
+	rsp := simulateViewRsp{
+		View:    vm.Name(),
+		Results: make([]*policy.SimulationResult, 0, len(checks)),
+	}
+	for _, check := range checks {
+		result, err := policy.SimulateAction(vm.GetViewDefinition(), check.Action, check.Target, explain)
+		if err != nil {
+			return rsp, err
+		}
+		rsp.Results = append(rsp.Results, result)
+	}
+	return rsp, nil
+}
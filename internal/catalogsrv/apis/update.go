@@ -1,15 +1,20 @@
 package apis
 
 import (
+	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
 )
 
-// Create a new resource object
+// updateObject updates a resource object. With dryRun=true the update is validated and made
+// in a transaction that is rolled back, and the object as it would have been updated is
+// returned.
 func updateObject(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
 	var kind string
@@ -39,14 +44,49 @@ func updateObject(r *http.Request) (*httpx.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = rm.Update(ctx, req)
+	preconditions := preconditionsFromRequest(r)
+
+	dryRun, err := isDryRun(r)
+	if err != nil {
+		return nil, err
+	}
+	writeCtx, admitted, err := admitWrite(ctx, rm, req, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	update := func(ctx context.Context) apperrors.Error {
+		return rm.Update(ctx, admitted)
+	}
+	if dryRun {
+		var updated json.RawMessage
+		err := runDryRun(writeCtx, func(ctx context.Context) apperrors.Error {
+			if _, err := catalogmanager.WriteWithPreconditions(ctx, kind, rm, reqContext, preconditions, update); err != nil {
+				return err
+			}
+			// Read back the object as it would have been stored
+			var err apperrors.Error
+			updated, err = rm.Get(ctx)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &httpx.Response{
+			StatusCode: http.StatusOK,
+			Response:   updated,
+		}, nil
+	}
+
+	etag, err := catalogmanager.WriteWithPreconditions(writeCtx, kind, rm, reqContext, preconditions, update)
 	if err != nil {
 		return nil, err
 	}
+	publishObjectEvent(ctx, catalogmanager.ObjectEventUpdated, kind, reqContext, admitted, r.URL.Path)
 
 	rsp := &httpx.Response{
 		StatusCode: http.StatusOK,
 		Response:   nil,
 	}
+	setETag(rsp, etag)
 	return rsp, nil
 }
@@ -7,6 +7,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
@@ -36,6 +37,8 @@ func hydrateRequestContext(r *http.Request) (interfaces.RequestContext, error) {
 
 	ctx := r.Context()
 	viewName := chi.URLParam(r, "viewName")
+	templateName := chi.URLParam(r, "templateName")
+	webhookName := chi.URLParam(r, "webhookName")
 	kindName := getResourceNameFromPath(r)
 
 	n := interfaces.RequestContext{
@@ -54,28 +57,32 @@ func hydrateRequestContext(r *http.Request) (interfaces.RequestContext, error) {
 	n.VariantID = catalogCtx.VariantID
 	n.Namespace = catalogCtx.Namespace
 
-	// Handle view name
+	// Handle view, view template and admission webhook names
 	if viewName != "" {
 		n.ObjectName = viewName
 	}
+	if templateName != "" {
+		n.ObjectName = templateName
+	}
+	if webhookName != "" {
+		n.ObjectName = webhookName
+	}
 
-	// Process resource paths
+	// Process resource paths. The definition, diff and revisions routes address the
+	// definition of the resource; all others its value.
 	if kindName == catcommon.KindNameResources {
-		path := r.URL.Path
-		switch {
-		case strings.HasPrefix(path, "/"+catcommon.KindNameResources+"/definition"):
-			resourcePath := strings.TrimPrefix(path, "/"+catcommon.KindNameResources+"/definition")
-			resourcePath = strings.TrimPrefix(resourcePath, "/")
-			n.ObjectName, n.ObjectPath = processPath(resourcePath)
-			n.ObjectType = catcommon.CatalogObjectTypeResource
-			n.ObjectProperty = catcommon.ResourcePropertyDefinition
-		default:
-			resourceValue := strings.TrimPrefix(path, "/"+catcommon.KindNameResources)
-			resourceValue = strings.TrimPrefix(resourceValue, "/")
-			n.ObjectName, n.ObjectPath = processPath(resourceValue)
-			n.ObjectType = catcommon.CatalogObjectTypeResource
-			n.ObjectProperty = catcommon.ResourcePropertyValue
+		resourcePath := strings.TrimPrefix(r.URL.Path, "/"+catcommon.KindNameResources)
+		n.ObjectType = catcommon.CatalogObjectTypeResource
+		n.ObjectProperty = catcommon.ResourcePropertyValue
+		for _, prefix := range []string{"/definition", "/diff", "/revisions"} {
+			if strings.HasPrefix(resourcePath, prefix) {
+				resourcePath = strings.TrimPrefix(resourcePath, prefix)
+				n.ObjectProperty = catcommon.ResourcePropertyDefinition
+				break
+			}
 		}
+		resourcePath = strings.TrimPrefix(resourcePath, "/")
+		n.ObjectName, n.ObjectPath = processPath(resourcePath)
 	}
 
 	// Process skillset paths
@@ -89,6 +96,26 @@ func hydrateRequestContext(r *http.Request) (interfaces.RequestContext, error) {
 	return n, nil
 }
 
+// preconditionsFromRequest extracts the If-Match and If-None-Match headers of a write request.
+func preconditionsFromRequest(r *http.Request) catalogmanager.Preconditions {
+	return catalogmanager.Preconditions{
+		IfMatch:     catalogmanager.ParseETagList(r.Header.Get("If-Match")),
+		IfNoneMatch: catalogmanager.ParseETagList(r.Header.Get("If-None-Match")),
+	}
+}
+
+// setETag sets the ETag header of the response to the ETag of the object written by the
+// request, if it could be read back.
+func setETag(rsp *httpx.Response, etag string) {
+	if etag == "" {
+		return
+	}
+	if rsp.Header == nil {
+		rsp.Header = http.Header{}
+	}
+	rsp.Header.Set("Etag", etag)
+}
+
 func getResourceKind(r *http.Request) string {
 	return catcommon.KindFromKindName(getResourceNameFromPath(r))
 }
@@ -0,0 +1,52 @@
+package apis
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+type cloneVariantReq struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// cloneVariant creates a new variant of the catalog in context as a copy of the variant in
+// context. The body names the new variant.
+func cloneVariant(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest()
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+	var req cloneVariantReq
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, httpx.ErrInvalidRequest("unable to parse request")
+	}
+	if req.Name == "" {
+		return nil, httpx.ErrInvalidRequest("name is required")
+	}
+
+	catalogCtx := catcommon.GetCatalogContext(ctx)
+	if catalogCtx == nil || catalogCtx.CatalogID == uuid.Nil || catalogCtx.Variant == "" {
+		return nil, httpx.ErrInvalidRequest("catalog and variant are required")
+	}
+	if err := catalogmanager.CloneVariant(ctx, catalogCtx.CatalogID, catalogCtx.Variant, req.Name, req.Description); err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   "/variants/" + req.Name,
+		Response:   nil,
+	}, nil
+}
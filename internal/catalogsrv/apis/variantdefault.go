@@ -0,0 +1,29 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// setDefaultVariant makes the variant in context the default variant of its catalog, used by
+// requests for resources, skillsets and namespaces that do not name a variant.
+func setDefaultVariant(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	catalogCtx := catcommon.GetCatalogContext(ctx)
+	if catalogCtx == nil || catalogCtx.CatalogID == uuid.Nil || catalogCtx.Variant == "" {
+		return nil, httpx.ErrInvalidRequest("catalog and variant are required")
+	}
+	if err := catalogmanager.SetDefaultVariant(ctx, catalogCtx.CatalogID, catalogCtx.Variant); err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+		Response:   nil,
+	}, nil
+}
@@ -0,0 +1,66 @@
+package apis
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+type instantiateViewTemplateReq struct {
+	Name string `json:"name"`
+	policy.ViewTemplateParams
+}
+
+// instantiateViewTemplate renders a view template into a new view. The request names the view
+// and supplies the values of the template's placeholders, e.g.
+// {"name": "dev-reader", "variant": "prod", "namespace": "dev"}. {{catalog}} is the current catalog.
+func instantiateViewTemplate(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	templateName := chi.URLParam(r, "templateName")
+	if templateName == "" {
+		return nil, httpx.ErrInvalidRequest("view template name is required")
+	}
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	body, goerr := io.ReadAll(r.Body)
+	if goerr != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+	req := instantiateViewTemplateReq{}
+	if goerr := json.Unmarshal(body, &req); goerr != nil {
+		return nil, httpx.ErrInvalidRequest("unable to parse request: " + goerr.Error())
+	}
+	if req.Name == "" {
+		return nil, httpx.ErrInvalidRequest("view name is required")
+	}
+
+	reqContext, err := hydrateRequestContext(r)
+	if err != nil {
+		return nil, err
+	}
+	req.Catalog = reqContext.Catalog
+
+	view, err := policy.InstantiateViewTemplate(ctx, reqContext.CatalogID, templateName, req.Name, req.ViewTemplateParams)
+	if err != nil {
+		return nil, err
+	}
+
+	location := "/views/" + view.Label
+	viewJSON, _ := json.Marshal(map[string]any{"metadata": map[string]string{"name": view.Label}})
+	publishObjectEvent(ctx, catalogmanager.ObjectEventCreated, catcommon.ViewKind, reqContext, viewJSON, location)
+
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   location,
+		Response:   nil,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package apis
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// maxValidationDocuments bounds the number of views in a batch validation request
+const maxValidationDocuments = 100
+
+type validateViewsRsp struct {
+	Valid   bool                           `json:"valid"`
+	Parent  string                         `json:"parent,omitempty"`
+	Results []*policy.ViewValidationResult `json:"results"`
+}
+
+// validateViews validates a multi-document YAML or JSON stream of views without applying
+// them, so that CI pipelines can gate policy changes. Each document is checked as creating
+// it would check it. With ?parent=<view>, the rules of each view must also be a subset of
+// the parent's rules, and every action and target the parent does not allow is reported.
+// The response is 200 with valid set to false if any document fails.
+func validateViews(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	body, goerr := io.ReadAll(r.Body)
+	if goerr != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(goerr, &maxErr) {
+			return nil, httpx.ErrRequestTooLarge(maxErr.Limit)
+		}
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	docs, goerr := decodeDocuments(body)
+	if goerr != nil {
+		return nil, goerr
+	}
+	if len(docs) == 0 {
+		return nil, httpx.ErrInvalidRequest("no documents to validate")
+	}
+	if len(docs) > maxValidationDocuments {
+		return nil, httpx.ErrInvalidRequest("too many documents in request")
+	}
+
+	rsp := validateViewsRsp{Valid: true, Parent: r.URL.Query().Get("parent")}
+	var parent *policy.ViewDefinition
+	if rsp.Parent != "" {
+		vm, err := policy.NewViewManagerByViewLabel(ctx, rsp.Parent)
+		if err != nil {
+			return nil, err
+		}
+		parent = vm.GetViewDefinition()
+	}
+
+	rsp.Results = make([]*policy.ViewValidationResult, 0, len(docs))
+	for _, doc := range docs {
+		result := policy.ValidateViewDocument(ctx, doc, parent)
+		rsp.Valid = rsp.Valid && result.Valid
+		rsp.Results = append(rsp.Results, result)
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
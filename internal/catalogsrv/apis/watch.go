@@ -0,0 +1,189 @@
+package apis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	watchBufferSize        = 100
+	watchHeartbeatInterval = 30 * time.Second
+)
+
+var watchableKinds = []string{
+	catcommon.CatalogKind,
+	catcommon.VariantKind,
+	catcommon.NamespaceKind,
+	catcommon.ViewKind,
+	catcommon.ResourceKind,
+	catcommon.SkillSetKind,
+}
+
+// watchObjects streams create, update and delete events for objects in the catalog as
+// Server-Sent Events. Events can be narrowed with the kind, variant, namespace and path query
+// parameters. A path matches resources and skillsets at or below it, so a client can follow
+// the values under a path, e.g. /watch?kind=Resource&path=/db. Only events for objects the
+// view may read are sent. The stream stays open until the client disconnects.
+func watchObjects(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	catalogCtx := catcommon.GetCatalogContext(ctx)
+	if catalogCtx == nil || catalogCtx.Catalog == "" {
+		return nil, httpx.ErrInvalidRequest("catalog is required")
+	}
+
+	query := r.URL.Query()
+	kind := query.Get("kind")
+	if kind == "" {
+		kind = "*"
+	} else if !slices.Contains(watchableKinds, kind) {
+		return nil, httpx.ErrInvalidRequest("unsupported kind: " + kind)
+	}
+	variant := getURLValue(query, "variant")
+	namespace := getURLValue(query, "namespace")
+	objectPath := getURLValue(query, "path")
+	if objectPath != "" {
+		objectPath = path.Clean("/" + objectPath)
+	}
+
+	topic := catalogmanager.ObjectEventTopic(ctx, catalogCtx.Catalog, kind)
+
+	return &httpx.Response{
+		StatusCode:  http.StatusOK,
+		ContentType: "text/event-stream",
+		Header:      http.Header{"Cache-Control": {"no-cache"}},
+		Chunked:     true,
+		WriteChunks: func(w http.ResponseWriter) error {
+			// Watches outlive the server's write timeout
+			rc := http.NewResponseController(w)
+			if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msg("unable to clear write deadline")
+			}
+
+			events, unsubscribe := catalogmanager.SubscribeObjectEvents(topic, watchBufferSize)
+			defer unsubscribe()
+
+			if _, err := fmt.Fprint(w, ": watching\n\n"); err != nil {
+				return err
+			}
+			if err := rc.Flush(); err != nil {
+				return err
+			}
+
+			heartbeat := time.NewTicker(watchHeartbeatInterval)
+			defer heartbeat.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-heartbeat.C:
+					if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+						return err
+					}
+				case event, ok := <-events:
+					if !ok {
+						return nil
+					}
+					ev, ok := event.Data.(catalogmanager.ObjectEvent)
+					if !ok {
+						continue
+					}
+					if (variant != "" && ev.Variant != variant) || (namespace != "" && ev.Namespace != namespace) {
+						continue
+					}
+					if objectPath != "" && !objectUnderPath(ev, objectPath) {
+						continue
+					}
+					if !catalogmanager.CanReadObjectEvent(ctx, ev) {
+						continue
+					}
+					data, err := json.Marshal(ev)
+					if err != nil {
+						log.Ctx(ctx).Error().Err(err).Msg("unable to marshal object event")
+						continue
+					}
+					if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+						return err
+					}
+				}
+				if err := rc.Flush(); err != nil {
+					return err
+				}
+			}
+		},
+	}, nil
+}
+
+// objectUnderPath reports whether the event is for a resource or skillset at or below the path.
+func objectUnderPath(ev catalogmanager.ObjectEvent, prefix string) bool {
+	if ev.Kind != catcommon.ResourceKind && ev.Kind != catcommon.SkillSetKind {
+		return false
+	}
+	p := path.Join("/", ev.Path, ev.Name)
+	return prefix == "/" || p == prefix || strings.HasPrefix(p, prefix+"/")
+}
+
+// publishObjectEvent notifies watchers of a change made through the API. Created objects
+// are identified from their definition; updated and deleted objects from the request context.
+// Nothing is published for dry runs.
+func publishObjectEvent(ctx context.Context, eventType, kind string, reqContext interfaces.RequestContext, objJSON []byte, location string) {
+	if catcommon.IsDryRun(ctx) {
+		return
+	}
+	ev := catalogmanager.ObjectEvent{
+		Type:      eventType,
+		Kind:      kind,
+		Catalog:   reqContext.Catalog,
+		Variant:   reqContext.Variant,
+		Namespace: reqContext.Namespace,
+		Path:      reqContext.ObjectPath,
+		Location:  location,
+	}
+	switch kind {
+	case catcommon.CatalogKind:
+		ev.Name = reqContext.Catalog
+	case catcommon.VariantKind:
+		ev.Name = reqContext.Variant
+	case catcommon.NamespaceKind:
+		ev.Name = reqContext.Namespace
+	default:
+		ev.Name = reqContext.ObjectName
+	}
+
+	if eventType == catalogmanager.ObjectEventCreated {
+		metadata := gjson.GetBytes(objJSON, "metadata")
+		ev.Name = metadata.Get("name").String()
+		if p := metadata.Get("path").String(); p != "" {
+			ev.Path = p
+		}
+		if v := metadata.Get("variant").String(); v != "" {
+			ev.Variant = v
+		}
+		if ns := metadata.Get("namespace").String(); ns != "" {
+			ev.Namespace = ns
+		}
+		switch kind {
+		case catcommon.CatalogKind:
+			ev.Catalog = ev.Name
+		case catcommon.VariantKind:
+			ev.Variant = ev.Name
+		case catcommon.NamespaceKind:
+			ev.Namespace = ev.Name
+		}
+	}
+
+	catalogmanager.PublishObjectEvent(ctx, ev)
+}
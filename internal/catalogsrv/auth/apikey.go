@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+const (
+	// APIKeyPrefix identifies an API key presented as a bearer token
+	APIKeyPrefix = "tnsv_ak_"
+	// apiKeyDisplayLen is the number of characters of the secret kept to help users identify a key
+	apiKeyDisplayLen  = 8
+	apiKeySecretBytes = 32
+)
+
+// GenerateAPIKey returns a new random API key, its display prefix and the hash that is stored in
+// place of the key.
+func GenerateAPIKey() (key string, displayPrefix string, hash []byte, err apperrors.Error) {
+	b := make([]byte, apiKeySecretBytes)
+	if _, goerr := rand.Read(b); goerr != nil {
+		return "", "", nil, ErrTokenGeneration.MsgErr("unable to generate api key", goerr)
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+	key = APIKeyPrefix + secret
+	return key, APIKeyPrefix + secret[:apiKeyDisplayLen], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hash under which an API key is stored. Keys carry enough entropy that
+// a plain SHA-256 is sufficient.
+func HashAPIKey(key string) []byte {
+	h := sha256.Sum256([]byte(key))
+	return h[:]
+}
+
+// IsAPIKey reports whether the bearer token is an API key rather than a JWT.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// handleAPIKey authenticates an API key and sets up the context from the view it is bound to.
+func handleAPIKey(ctx context.Context, token string) (context.Context, error) {
+	// The tenant is known only from the key, so the lookup is not scoped to a tenant
+	lookupCtx := db.WithAllTenants(ctx)
+	key, err := db.DB(lookupCtx).GetAPIKeyByHash(lookupCtx, HashAPIKey(token))
+	if err != nil {
+		return ctx, ErrInvalidToken.Msg("invalid api key")
+	}
+
+	if key.RevokedAt != nil {
+		return ctx, ErrInvalidToken.Msg("api key revoked")
+	}
+	if !key.IsActive(time.Now()) {
+		return ctx, ErrInvalidToken.Msg("api key expired")
+	}
+
+	ctx = catcommon.WithTenantID(ctx, key.TenantID)
+	ctx = catcommon.WithProjectID(ctx, key.ProjectID)
+
+	view, err := db.DB(ctx).GetView(ctx, key.ViewID)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if err := policy.CheckViewActive(view, time.Now()); err != nil {
+		return ctx, err
+	}
+
+	viewDef, err := policy.LoadViewDefinition(ctx, view)
+	if err != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidViewRules, err)
+	}
+
+	ctx = policy.WithViewDefinition(ctx, viewDef)
+	ctx = catcommon.WithCatalogContext(ctx, &catcommon.CatalogContext{
+		Catalog:   viewDef.Scope.Catalog,
+		Variant:   viewDef.Scope.Variant,
+		Namespace: viewDef.Scope.Namespace,
+		CatalogID: view.CatalogID,
+		ViewID:    view.ViewID,
+		ServiceContext: &catcommon.ServiceContext{
+			ServiceName: key.Name,
+		},
+		Subject: catcommon.SubjectTypeService,
+	})
+
+	// Failing to record usage should not fail the request
+	if err := db.DB(ctx).TouchAPIKey(ctx, key.KeyID); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("key_id", key.KeyID.String()).Msg("unable to record api key usage")
+	}
+
+	return ctx, nil
+}
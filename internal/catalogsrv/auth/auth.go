@@ -31,7 +31,8 @@ func getCatalogByRef(ctx context.Context, catalogRef string) (*models.Catalog, a
 }
 
 // adoptView adopts a view from a catalog. The parent view must be scoped to the catalog and
-// the derived view must have a policy subset of the parent view.
+// the derived view must have a policy subset of the parent view. Users signed in with an
+// identity token may instead adopt views assigned to their groups.
 func adoptView(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
 	catalogRef := chi.URLParam(r, "catalogRef")
@@ -42,24 +43,29 @@ func adoptView(r *http.Request) (*httpx.Response, error) {
 		return nil, ErrCatalogNotFound.Err(err)
 	}
 
-	// Validate current context
+	// Validate current context. Users signed in with an identity token have no current view.
+	allowed := false
 	ourViewDef := policy.GetViewDefinition(ctx)
-	if ourViewDef == nil {
+	if ourViewDef == nil && catcommon.GetSubjectType(ctx) != catcommon.SubjectTypeUser {
 		return nil, ErrInvalidView.Msg("no current view definition found")
 	}
-	if ourViewDef.Scope.Catalog != catalog.Name {
-		return nil, ErrInvalidView.Msg("current view not in catalog: " + catalog.Name)
-	}
+	if ourViewDef != nil {
+		if ourViewDef.Scope.Catalog != catalog.Name {
+			return nil, ErrInvalidView.Msg("current view not in catalog: " + catalog.Name)
+		}
 
-	// Check if our current view has permission to adopt the view
-	allowed, err := policy.CanAdoptView(ctx, viewLabel)
-	if err != nil {
-		return nil, err
+		// Check if our current view has permission to adopt the view
+		var err apperrors.Error
+		allowed, err = policy.CanAdoptView(ctx, viewLabel)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// If this is a user, check if they have permission to adopt the view
-	if !allowed {
-		allowed = policy.CanAdoptViewAsUser(ctx, viewLabel)
+	// Users signed in with an identity token may adopt the views assigned to their groups.
+	// Callers holding a view, including handoff tokens, are limited to what the view allows.
+	if ourViewDef == nil {
+		allowed = policy.CanAdoptViewAsUser(ctx, catalog.CatalogID, viewLabel)
 	}
 
 	if !allowed {
@@ -70,6 +76,9 @@ func adoptView(r *http.Request) (*httpx.Response, error) {
 	if err != nil {
 		return nil, ErrViewNotFound.Err(err)
 	}
+	if err := policy.CheckViewActive(wantView, time.Now()); err != nil {
+		return nil, err
+	}
 
 	token, tokenExpiry, err := CreateAccessToken(ctx,
 		wantView,
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -49,10 +50,20 @@ func ContextMiddleware(next http.Handler) http.Handler {
 		ctx, err = ValidateToken(ctx, token)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("token validation failed")
-			httpx.ErrUnAuthorized(GenericAuthError).Send(w)
+			SendTokenError(w, err)
 			return
 		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// SendTokenError sends the response for a token that failed validation. The cause is not
+// disclosed, except for the tokens of suspended tenants, which are valid but refused.
+func SendTokenError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrTenantSuspended) {
+		httpx.SendError(w, ErrTenantSuspended)
+		return
+	}
+	httpx.ErrUnAuthorized(GenericAuthError).Send(w)
+}
@@ -22,6 +22,7 @@ type TokenOptions struct {
 	ParentView        *policy.ViewDefinition
 	CreateDerivedView bool
 	AdditionalClaims  map[string]any
+	Validity          time.Duration
 }
 
 // TokenOption is a function that modifies TokenOptions
@@ -51,6 +52,13 @@ func WithAdditionalClaims(claims map[string]any) TokenOption {
 	}
 }
 
+// WithValidity overrides the configured default token validity
+func WithValidity(d time.Duration) TokenOption {
+	return func(o *TokenOptions) {
+		o.Validity = d
+	}
+}
+
 // CreateDerivedView indicates that a derived view should be created
 func CreateDerivedView() TokenOption {
 	return func(o *TokenOptions) {
@@ -61,6 +69,7 @@ func CreateDerivedView() TokenOption {
 // Reserved JWT claims that cannot be overwritten
 var reservedClaims = map[string]bool{
 	"view_id":   true,
+	"view":      true,
 	"tenant_id": true,
 	"iss":       true,
 	"exp":       true,
@@ -92,10 +101,14 @@ func CreateAccessToken(ctx context.Context, derivedView *models.View, opts ...To
 		}
 	}
 
-	tokenDuration, goerr := config.Config().Auth.GetDefaultTokenValidity()
-	if goerr != nil {
-		log.Ctx(ctx).Error().Err(goerr).Msg("unable to parse token duration")
-		return "", time.Time{}, ErrUnableToParseTokenDuration.MsgErr("unable to parse token duration", goerr)
+	tokenDuration := options.Validity
+	if tokenDuration <= 0 {
+		var goerr error
+		tokenDuration, goerr = config.Config().Auth.GetDefaultTokenValidity()
+		if goerr != nil {
+			log.Ctx(ctx).Error().Err(goerr).Msg("unable to parse token duration")
+			return "", time.Time{}, ErrUnableToParseTokenDuration.MsgErr("unable to parse token duration", goerr)
+		}
 	}
 
 	tokenExpiry := time.Now().Add(tokenDuration)
@@ -135,6 +148,7 @@ func createTokenClaims(ctx context.Context, view *models.View, token *models.Vie
 	claims := jwt.MapClaims{
 		"token_use": catcommon.AccessTokenType,
 		"view_id":   view.ViewID.String(),
+		"view":      view.Label,
 		"tenant_id": catcommon.GetTenantID(ctx),
 		"iss":       config.Config().ServerHostName + ":" + config.Config().ServerPort,
 		"exp":       jwt.NewNumericDate(expiry),
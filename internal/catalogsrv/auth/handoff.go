@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// DefaultHandoffTokenValidity is the lifetime of a handoff token when none is requested
+const DefaultHandoffTokenValidity = "15m"
+
+type viewHandoffReq struct {
+	ExpiresIn string `json:"expires_in,omitempty"`
+}
+
+type viewHandoffRsp struct {
+	Token     string    `json:"token"`
+	View      string    `json:"view"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// createViewHandoff issues a handoff token for a view adopted by the caller's view, so that the
+// caller can pass narrower access to another party such as an agent or a tool. The caller's view
+// must allow catalog.adoptView on the target view, and the target view's rules must be a subset
+// of the caller's. Requests made with the token are authorized by the adopted view, for as long
+// as the adopting view still permits the adoption.
+func createViewHandoff(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	catalogRef := chi.URLParam(r, "catalogRef")
+	viewLabel := chi.URLParam(r, "viewLabel")
+
+	catalog, err := getCatalogByRef(ctx, catalogRef)
+	if err != nil {
+		return nil, ErrCatalogNotFound.Err(err)
+	}
+
+	ourViewDef := policy.GetViewDefinition(ctx)
+	if ourViewDef == nil {
+		return nil, ErrInvalidView.Msg("no current view definition found")
+	}
+	if ourViewDef.Scope.Catalog != catalog.Name {
+		return nil, ErrInvalidView.Msg("current view not in catalog: " + catalog.Name)
+	}
+	ourViewID := catcommon.GetViewID(ctx)
+	if ourViewID == uuid.Nil {
+		return nil, ErrInvalidView.Msg("unable to resolve current view")
+	}
+
+	req := viewHandoffReq{}
+	if r.Body != nil {
+		body, goerr := io.ReadAll(r.Body)
+		if goerr != nil {
+			return nil, httpx.ErrUnableToReadRequest()
+		}
+		if len(body) > 0 {
+			if goerr := json.Unmarshal(body, &req); goerr != nil {
+				return nil, ErrBadRequest.Msg("unable to parse request: " + goerr.Error())
+			}
+		}
+	}
+	expiresIn := req.ExpiresIn
+	if expiresIn == "" {
+		expiresIn = DefaultHandoffTokenValidity
+	}
+	validity, goerr := config.ParseDuration(expiresIn)
+	if goerr != nil || validity <= 0 {
+		return nil, ErrBadRequest.Msg("invalid expires_in: " + expiresIn)
+	}
+	if validity > config.Config().Auth.GetDefaultTokenValidityOrDefault() {
+		return nil, ErrBadRequest.Msg("expires_in exceeds the maximum token validity of " + config.Config().Auth.DefaultTokenValidity)
+	}
+
+	allowed, err := policy.CanAdoptView(ctx, viewLabel)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrDisallowedByPolicy.Msg("view is not allowed to be adopted")
+	}
+
+	wantView, err := db.DB(ctx).GetViewByLabel(ctx, viewLabel, catalog.CatalogID)
+	if err != nil {
+		return nil, ErrViewNotFound.Err(err)
+	}
+	if err := policy.CheckViewActive(wantView, time.Now()); err != nil {
+		return nil, err
+	}
+	wantViewDef, err := policy.LoadViewDefinition(ctx, wantView)
+	if err != nil {
+		return nil, ErrInvalidViewRules.Err(err)
+	}
+	if err := policy.ValidateDerivedView(ctx, ourViewDef, wantViewDef); err != nil {
+		return nil, ErrDisallowedByPolicy.Msg("view grants more than the current view")
+	}
+
+	token, tokenExpiry, err := CreateAccessToken(ctx,
+		wantView,
+		WithValidity(validity),
+		WithAdditionalClaims(map[string]any{
+			"token_use":      catcommon.AccessTokenType,
+			"sub":            catcommon.GetPrincipal(ctx),
+			"parent_view_id": ourViewID.String(),
+		}),
+	)
+	if err != nil {
+		return nil, ErrTokenGeneration.Msg(err.Error())
+	}
+
+	log.Ctx(ctx).Info().Str("sub", catcommon.GetPrincipal(ctx)).Str("view", wantView.Label).Msg("issued view handoff token")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &viewHandoffRsp{
+			Token:     token,
+			View:      wantView.Label,
+			ExpiresAt: tokenExpiry,
+		},
+	}, nil
+}
+
+// validateHandoff checks that the view which adopted the token's view still exists and still
+// permits the adoption, so that narrowing or deleting the adopting view also narrows or
+// invalidates the handoff tokens it issued.
+func validateHandoff(ctx context.Context, tokenObj *Token, viewDef *policy.ViewDefinition) apperrors.Error {
+	parentViewID, ok := tokenObj.GetUUID("parent_view_id")
+	if !ok {
+		return ErrInvalidToken.Msg("invalid parent_view_id claim")
+	}
+
+	parentView, err := db.DB(ctx).GetView(ctx, parentViewID)
+	if err != nil {
+		return ErrInvalidToken.Msg("adopting view no longer exists")
+	}
+	if parentView.CatalogID != tokenObj.GetCatalogID() {
+		return ErrInvalidToken.Msg("adopting view is not in the token's catalog")
+	}
+	if err := policy.CheckViewActive(parentView, time.Now()); err != nil {
+		return ErrInvalidToken.Msg("adopting view is not active")
+	}
+	parentViewDef, err := policy.LoadViewDefinition(ctx, parentView)
+	if err != nil {
+		return ErrInvalidViewRules.Err(err)
+	}
+
+	allowed, err := policy.CanAdoptView(policy.WithViewDefinition(ctx, parentViewDef), tokenObj.GetView().Label)
+	if err != nil || !allowed {
+		return ErrInvalidToken.Msg("adopting view no longer permits the adoption")
+	}
+	if err := policy.ValidateDerivedView(ctx, parentViewDef, viewDef); err != nil {
+		return ErrInvalidToken.Msg("view grants more than the adopting view")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package oidc
+
+import (
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+// Base oidc error
+var (
+	ErrOIDC apperrors.Error = apperrors.New("oidc error").SetStatusCode(http.StatusInternalServerError)
+)
+
+// Provider errors
+var (
+	ErrDiscoveryFailed apperrors.Error = ErrOIDC.New("unable to discover oidc provider").SetStatusCode(http.StatusServiceUnavailable)
+	ErrJWKSFetchFailed apperrors.Error = ErrOIDC.New("unable to fetch signing keys").SetStatusCode(http.StatusServiceUnavailable)
+)
+
+// Token errors
+var (
+	ErrInvalidToken      apperrors.Error = ErrOIDC.New("invalid token").SetStatusCode(http.StatusUnauthorized)
+	ErrUnknownSigningKey apperrors.Error = ErrOIDC.New("unknown signing key").SetStatusCode(http.StatusUnauthorized)
+	ErrMissingClaim      apperrors.Error = ErrOIDC.New("missing required claim").SetStatusCode(http.StatusUnauthorized)
+)
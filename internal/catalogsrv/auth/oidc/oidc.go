@@ -0,0 +1,293 @@
+// Package oidc authenticates users with ID or access tokens issued by an external
+// OpenID Connect provider. The provider's signing keys are located through issuer
+// discovery and cached, and tokens are checked for issuer, audience and expiry before
+// their claims are mapped to a tansive user, tenant and project.
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	// minKeyRefreshInterval limits how often an unknown key ID can force a refetch of the key set.
+	minKeyRefreshInterval = time.Minute
+	httpTimeout           = 10 * time.Second
+)
+
+var validSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// Options configures a Provider.
+type Options struct {
+	Issuer       string
+	Audience     string
+	UserClaim    string
+	TenantClaim  string
+	ProjectClaim string
+	JWKSCacheTTL time.Duration
+	ClockSkew    time.Duration
+	HTTPClient   *http.Client
+}
+
+// Identity is the caller established by a verified token.
+type Identity struct {
+	UserID    string
+	TenantID  string
+	ProjectID string
+}
+
+// Provider verifies tokens issued by a single OIDC issuer.
+type Provider struct {
+	opts Options
+
+	mu          sync.RWMutex
+	jwksURI     string
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastRefresh time.Time
+}
+
+var (
+	providerInstance *Provider
+	providerOnce     sync.Once
+)
+
+// GetProvider returns the provider configured in the auth.oidc section of the server
+// configuration, or nil if OIDC authentication is not enabled.
+func GetProvider() *Provider {
+	providerOnce.Do(func() {
+		c := config.Config().Auth.OIDC
+		if !c.Enabled {
+			return
+		}
+		ttl, err := c.GetJWKSCacheTTL()
+		if err != nil {
+			log.Error().Err(err).Msg("invalid oidc jwks cache ttl")
+			return
+		}
+		skew, err := config.Config().Auth.GetClockSkew()
+		if err != nil {
+			log.Error().Err(err).Msg("invalid clock skew")
+			return
+		}
+		providerInstance = NewProvider(Options{
+			Issuer:       c.Issuer,
+			Audience:     c.Audience,
+			UserClaim:    c.UserClaim,
+			TenantClaim:  c.TenantClaim,
+			ProjectClaim: c.ProjectClaim,
+			JWKSCacheTTL: ttl,
+			ClockSkew:    skew,
+		})
+	})
+	return providerInstance
+}
+
+// NewProvider creates a provider for the issuer in opts. Discovery is deferred until the
+// first token is verified.
+func NewProvider(opts Options) *Provider {
+	if opts.UserClaim == "" {
+		opts.UserClaim = config.DefaultOIDCUserClaim
+	}
+	if opts.TenantClaim == "" {
+		opts.TenantClaim = config.DefaultOIDCTenantClaim
+	}
+	if opts.ProjectClaim == "" {
+		opts.ProjectClaim = config.DefaultOIDCProjectClaim
+	}
+	if opts.JWKSCacheTTL <= 0 {
+		opts.JWKSCacheTTL = time.Hour
+	}
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Timeout: httpTimeout}
+	}
+	return &Provider{
+		opts: opts,
+		keys: make(map[string]crypto.PublicKey),
+	}
+}
+
+// Issued reports whether the token claims to come from this provider's issuer. The
+// signature is not checked; use Verify for that.
+func (p *Provider) Issued(tokenString string) bool {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		return false
+	}
+	iss, err := claims.GetIssuer()
+	return err == nil && iss == p.opts.Issuer
+}
+
+// Verify checks the signature, issuer, audience and validity period of a token and
+// returns the identity it carries.
+func (p *Provider) Verify(ctx context.Context, tokenString string) (*Identity, apperrors.Error) {
+	var keyErr apperrors.Error
+	keyFunc := func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := p.key(ctx, kid)
+		if err != nil {
+			keyErr = err
+			return nil, err
+		}
+		return key, nil
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, keyFunc,
+		jwt.WithValidMethods(validSigningMethods),
+		jwt.WithIssuer(p.opts.Issuer),
+		jwt.WithAudience(p.opts.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(p.opts.ClockSkew),
+	)
+	if err != nil {
+		if keyErr != nil {
+			return nil, keyErr
+		}
+		return nil, ErrInvalidToken.Msg(err.Error())
+	}
+
+	identity := &Identity{
+		UserID:    stringClaim(claims, p.opts.UserClaim),
+		TenantID:  stringClaim(claims, p.opts.TenantClaim),
+		ProjectID: stringClaim(claims, p.opts.ProjectClaim),
+	}
+	if identity.UserID == "" {
+		return nil, ErrMissingClaim.Msg("missing claim " + p.opts.UserClaim)
+	}
+	if identity.TenantID == "" {
+		return nil, ErrMissingClaim.Msg("missing claim " + p.opts.TenantClaim)
+	}
+	return identity, nil
+}
+
+// key returns the public key with the given ID, fetching the key set when the cache has
+// expired or does not contain the key.
+func (p *Provider) key(ctx context.Context, kid string) (crypto.PublicKey, apperrors.Error) {
+	p.mu.RLock()
+	key, fresh, canRefresh := p.cachedKey(kid)
+	p.mu.RUnlock()
+	if key != nil && fresh {
+		return key, nil
+	}
+
+	if !fresh || canRefresh {
+		p.mu.Lock()
+		// Another request may have refreshed the keys while we waited for the lock
+		key, fresh, canRefresh = p.cachedKey(kid)
+		if (key == nil && canRefresh) || !fresh {
+			if err := p.refresh(ctx); err != nil {
+				p.mu.Unlock()
+				// Serve a stale key rather than fail while the provider is unreachable
+				if key != nil {
+					log.Ctx(ctx).Warn().Err(err).Msg("using cached oidc signing key after refresh failure")
+					return key, nil
+				}
+				return nil, err
+			}
+			key, _, _ = p.cachedKey(kid)
+		}
+		p.mu.Unlock()
+	}
+
+	if key == nil {
+		return nil, ErrUnknownSigningKey.Msg("unknown signing key " + kid)
+	}
+	return key, nil
+}
+
+// cachedKey looks up kid in the cache. A token without a key ID can only be matched when
+// the provider publishes a single key. Must be called with p.mu held.
+func (p *Provider) cachedKey(kid string) (key crypto.PublicKey, fresh bool, canRefresh bool) {
+	fresh = !p.fetchedAt.IsZero() && time.Since(p.fetchedAt) < p.opts.JWKSCacheTTL
+	canRefresh = time.Since(p.lastRefresh) >= minKeyRefreshInterval
+	if kid == "" {
+		if len(p.keys) == 1 {
+			for _, k := range p.keys {
+				key = k
+			}
+		}
+		return key, fresh, canRefresh
+	}
+	return p.keys[kid], fresh, canRefresh
+}
+
+// refresh runs discovery if needed and reloads the key set. Must be called with p.mu held.
+func (p *Provider) refresh(ctx context.Context) apperrors.Error {
+	p.lastRefresh = time.Now()
+
+	if p.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := p.getJSON(ctx, strings.TrimSuffix(p.opts.Issuer, "/")+discoveryPath, &discovery); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("issuer", p.opts.Issuer).Msg("oidc discovery failed")
+			return ErrDiscoveryFailed.Msg(err.Error())
+		}
+		if discovery.Issuer != p.opts.Issuer {
+			return ErrDiscoveryFailed.Msg("discovered issuer " + discovery.Issuer + " does not match " + p.opts.Issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return ErrDiscoveryFailed.Msg("provider does not publish jwks_uri")
+		}
+		p.jwksURI = discovery.JWKSURI
+	}
+
+	var set jwks
+	if err := p.getJSON(ctx, p.jwksURI, &set); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("jwks_uri", p.jwksURI).Msg("unable to fetch oidc signing keys")
+		return ErrJWKSFetchFailed.Msg(err.Error())
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("kid", k.Kid).Msg("skipping unusable oidc signing key")
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	p.keys = keys
+	p.fetchedAt = time.Now()
+	return nil
+}
+
+func (p *Provider) getJSON(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	rsp, err := p.opts.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", rsp.StatusCode, url)
+	}
+	return json.NewDecoder(rsp.Body).Decode(v)
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	s, _ := claims[name].(string)
+	return s
+}
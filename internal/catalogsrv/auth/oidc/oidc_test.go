@@ -0,0 +1,168 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testIssuer struct {
+	server   *httptest.Server
+	key      *rsa.PrivateKey
+	kid      string
+	jwksHits int
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ti := &testIssuer{key: key, kid: "key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   ti.server.URL,
+			"jwks_uri": ti.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		ti.jwksHits++
+		json.NewEncoder(w).Encode(jwks{Keys: []jwk{{
+			Kty: "RSA",
+			Kid: ti.kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(ti.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(ti.key.E)).Bytes()),
+		}}})
+	})
+	ti.server = httptest.NewServer(mux)
+	t.Cleanup(ti.server.Close)
+	return ti
+}
+
+func (ti *testIssuer) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	s, err := token.SignedString(ti.key)
+	require.NoError(t, err)
+	return s
+}
+
+func (ti *testIssuer) claims() jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":        ti.server.URL,
+		"aud":        "tansive",
+		"sub":        "user-1",
+		"tenant_id":  "tenant-1",
+		"project_id": "project-1",
+		"iat":        now.Unix(),
+		"exp":        now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestProviderVerify(t *testing.T) {
+	ti := newTestIssuer(t)
+	p := NewProvider(Options{
+		Issuer:   ti.server.URL,
+		Audience: "tansive",
+	})
+	ctx := context.Background()
+
+	t.Run("valid token", func(t *testing.T) {
+		token := ti.sign(t, ti.kid, ti.claims())
+		assert.True(t, p.Issued(token))
+		identity, err := p.Verify(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, &Identity{UserID: "user-1", TenantID: "tenant-1", ProjectID: "project-1"}, identity)
+	})
+
+	t.Run("keys are cached", func(t *testing.T) {
+		hits := ti.jwksHits
+		_, err := p.Verify(ctx, ti.sign(t, ti.kid, ti.claims()))
+		require.NoError(t, err)
+		assert.Equal(t, hits, ti.jwksHits)
+	})
+
+	t.Run("project claim is optional", func(t *testing.T) {
+		claims := ti.claims()
+		delete(claims, "project_id")
+		identity, err := p.Verify(ctx, ti.sign(t, ti.kid, claims))
+		require.NoError(t, err)
+		assert.Empty(t, identity.ProjectID)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		claims := ti.claims()
+		claims["aud"] = "someone-else"
+		_, err := p.Verify(ctx, ti.sign(t, ti.kid, claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		claims := ti.claims()
+		claims["iss"] = "https://example.com"
+		token := ti.sign(t, ti.kid, claims)
+		assert.False(t, p.Issued(token))
+		_, err := p.Verify(ctx, token)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		claims := ti.claims()
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		_, err := p.Verify(ctx, ti.sign(t, ti.kid, claims))
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := p.Verify(ctx, ti.sign(t, "key-2", ti.claims()))
+		assert.ErrorIs(t, err, ErrUnknownSigningKey)
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, ti.claims())
+		token.Header["kid"] = ti.kid
+		s, err := token.SignedString(other)
+		require.NoError(t, err)
+		_, verr := p.Verify(ctx, s)
+		assert.ErrorIs(t, verr, ErrInvalidToken)
+	})
+
+	t.Run("missing tenant claim", func(t *testing.T) {
+		claims := ti.claims()
+		delete(claims, "tenant_id")
+		_, err := p.Verify(ctx, ti.sign(t, ti.kid, claims))
+		assert.ErrorIs(t, err, ErrMissingClaim)
+	})
+}
+
+func TestProviderDiscoveryFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	p := NewProvider(Options{Issuer: server.URL, Audience: "tansive"})
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": server.URL})
+	s, err := token.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, verr := p.Verify(context.Background(), s)
+	assert.ErrorIs(t, verr, ErrInvalidToken)
+
+	_, kerr := p.key(context.Background(), "key-1")
+	assert.ErrorIs(t, kerr, ErrDiscoveryFailed)
+}
@@ -0,0 +1,123 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/userauth"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+type refreshTokenRsp struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// refreshToken exchanges the caller's token for a new token of the same kind and revokes the
+// old one. The new token keeps the subject and lifetime of the old one. Tokens can be refreshed
+// until the original login is older than the maximum token age, which is carried across
+// refreshes in the auth_time claim.
+func refreshToken(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	tokenType, jwtToken, err := ParseAndValidateToken(ctx, bearerToken(r))
+	if err != nil {
+		return nil, ErrInvalidToken.Msg("only tansive tokens can be refreshed")
+	}
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrUnableToParseToken
+	}
+
+	authTime := getAuthTime(claims)
+	if time.Since(authTime) > config.Config().Auth.GetMaxTokenAgeOrDefault() {
+		return nil, ErrInvalidToken.Msg("login expired. login required")
+	}
+
+	// Reserved claims are set afresh when the new token is minted
+	carriedClaims := make(map[string]any)
+	for k, v := range claims {
+		if !reservedClaims[k] {
+			carriedClaims[k] = v
+		}
+	}
+	carriedClaims["auth_time"] = jwt.NewNumericDate(authTime)
+
+	var token string
+	var tokenExpiry time.Time
+	switch tokenType {
+	case catcommon.IdentityTokenType:
+		var goerr error
+		token, tokenExpiry, goerr = userauth.CreateIdentityTokenWithValidity(ctx, getTokenLifetime(claims), carriedClaims)
+		if goerr != nil {
+			return nil, ErrTokenGeneration.Err(goerr)
+		}
+	case catcommon.AccessTokenType:
+		viewIDStr, _ := claims["view_id"].(string)
+		viewID, goerr := uuid.Parse(viewIDStr)
+		if goerr != nil {
+			return nil, ErrUnableToParseToken.Err(goerr)
+		}
+		view, err := db.DB(ctx).GetView(ctx, viewID)
+		if err != nil {
+			return nil, ErrViewNotFound.Err(err)
+		}
+		token, tokenExpiry, err = CreateAccessToken(ctx,
+			view,
+			WithValidity(getTokenLifetime(claims)),
+			WithAdditionalClaims(carriedClaims),
+		)
+		if err != nil {
+			return nil, ErrTokenGeneration.Msg(err.Error())
+		}
+	default:
+		return nil, ErrInvalidToken.Msg("unsupported token type")
+	}
+
+	if err := revokeTokenClaims(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("sub", catcommon.GetPrincipal(ctx)).Str("token_use", string(tokenType)).Msg("refreshed token")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &refreshTokenRsp{
+			Token:     token,
+			ExpiresAt: tokenExpiry,
+		},
+	}, nil
+}
+
+// bearerToken returns the token in the request's Authorization header
+func bearerToken(r *http.Request) string {
+	authHeader := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authHeader, AuthHeaderPrefix) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(authHeader, AuthHeaderPrefix))
+}
+
+// getAuthTime returns the time of the login the token descends from. Tokens that were never
+// refreshed have no auth_time claim, so their issue time is used.
+func getAuthTime(claims jwt.MapClaims) time.Time {
+	if authTime, ok := claims["auth_time"].(float64); ok {
+		return time.Unix(int64(authTime), 0)
+	}
+	iat, _ := claims["iat"].(float64)
+	return time.Unix(int64(iat), 0)
+}
+
+// getTokenLifetime returns the validity the token was minted with
+func getTokenLifetime(claims jwt.MapClaims) time.Duration {
+	exp, _ := claims["exp"].(float64)
+	iat, _ := claims["iat"].(float64)
+	return time.Unix(int64(exp), 0).Sub(time.Unix(int64(iat), 0))
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+type revokeTokenReq struct {
+	Token string `json:"token,omitempty"`
+}
+
+// revokeToken adds a token to the tenant's denylist so that it is rejected before it expires.
+// The caller's own token is revoked unless another token is given in the request. As with
+// OAuth token revocation, a token that is already invalid or expired is not an error.
+func revokeToken(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	req := revokeTokenReq{}
+	if r.Body != nil {
+		body, goerr := io.ReadAll(r.Body)
+		if goerr != nil {
+			return nil, httpx.ErrUnableToReadRequest()
+		}
+		if len(body) > 0 {
+			if goerr := json.Unmarshal(body, &req); goerr != nil {
+				return nil, ErrBadRequest.Msg("unable to parse request: " + goerr.Error())
+			}
+		}
+	}
+
+	token := req.Token
+	if token == "" {
+		token = bearerToken(r)
+	}
+
+	_, jwtToken, err := ParseAndValidateToken(ctx, token)
+	if err != nil {
+		log.Ctx(ctx).Debug().Err(err).Msg("ignoring revocation of invalid token")
+		return &httpx.Response{
+			StatusCode: http.StatusNoContent,
+		}, nil
+	}
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrUnableToParseToken
+	}
+
+	if tenantID, _ := claims["tenant_id"].(string); catcommon.TenantId(tenantID) != catcommon.GetTenantID(ctx) {
+		return nil, ErrDisallowedByPolicy.Msg("token belongs to another tenant")
+	}
+
+	if err := revokeTokenClaims(ctx, claims); err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+// revokeTokenClaims adds the token with the given claims to the denylist until it expires
+func revokeTokenClaims(ctx context.Context, claims jwt.MapClaims) apperrors.Error {
+	jtiStr, _ := claims["jti"].(string)
+	jti, goerr := uuid.Parse(jtiStr)
+	if goerr != nil {
+		return ErrUnableToParseToken.Err(goerr)
+	}
+	exp, _ := claims["exp"].(float64)
+
+	revokedToken := &models.RevokedToken{
+		JTI:       jti,
+		ExpireAt:  time.Unix(int64(exp), 0),
+		RevokedBy: catcommon.GetPrincipal(ctx),
+	}
+	if err := db.DB(ctx).RevokeToken(ctx, revokedToken); err != nil {
+		return err
+	}
+
+	log.Ctx(ctx).Info().Str("jti", jtiStr).Str("revoked_by", revokedToken.RevokedBy).Msg("revoked token")
+	return nil
+}
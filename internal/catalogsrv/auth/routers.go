@@ -22,6 +22,36 @@ var authHandlers = []policy.ResponseHandlerParam{
 		Path:    "/default-view-adoptions/{catalogRef}",
 		Handler: adoptDefaultCatalogView,
 	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/device/approve",
+		Handler: userauth.ApproveDeviceLogin,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/token/refresh",
+		Handler: refreshToken,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/token/revoke",
+		Handler: revokeToken,
+	},
+}
+
+// serviceHandlers accept view-bound credentials, including API keys, so that
+// non-interactive clients can obtain short-lived tokens.
+var serviceHandlers = []policy.ResponseHandlerParam{
+	{
+		Method:  http.MethodPost,
+		Path:    "/service-tokens",
+		Handler: createServiceToken,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/view-handoffs/{catalogRef}/{viewLabel}",
+		Handler: createViewHandoff,
+	},
 }
 
 // Router creates and configures a new router for authentication-related endpoints.
@@ -30,6 +60,9 @@ func Router(r chi.Router) chi.Router {
 	router := chi.NewRouter()
 	router.Group(func(r chi.Router) {
 		r.Method(http.MethodPost, "/login", httpx.WrapHttpRsp(userauth.LoginUser))
+		r.Method(http.MethodPost, "/device", httpx.WrapHttpRsp(userauth.StartDeviceLogin))
+		r.Get("/device", userauth.DeviceLoginPage)
+		r.Method(http.MethodPost, "/device/token", httpx.WrapHttpRsp(userauth.PollDeviceLogin))
 	})
 	router.Group(func(r chi.Router) {
 		r.Use(UserAuthMiddleware)
@@ -38,17 +71,23 @@ func Router(r chi.Router) chi.Router {
 			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
 		}
 	})
+	router.Group(func(r chi.Router) {
+		r.Use(ContextMiddleware)
+		for _, handler := range serviceHandlers {
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
+		}
+	})
 	return router
 }
 
 func LoadContext(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		// Load projectID from URL query parameter
+		// Load projectID from URL query parameter, unless the user's token already carries one
 		projectID := r.URL.Query().Get("project")
-		if projectID != "" {
+		if catcommon.GetProjectID(ctx) == "" && projectID != "" {
 			ctx = catcommon.WithProjectID(ctx, catcommon.ProjectId(projectID))
-		} else if config.Config().SingleUserMode {
+		} else if catcommon.GetProjectID(ctx) == "" && config.Config().SingleUserMode {
 			ctx = catcommon.WithProjectID(ctx, catcommon.ProjectId(config.Config().DefaultProjectID))
 		}
 		next.ServeHTTP(w, r.WithContext(ctx))
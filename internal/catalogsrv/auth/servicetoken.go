@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// DefaultServiceTokenValidity is the lifetime of a service token when none is requested
+const DefaultServiceTokenValidity = "15m"
+
+var serviceAccountRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+type serviceTokenReq struct {
+	ServiceAccount string `json:"service_account"`
+	View           string `json:"view"`
+	ExpiresIn      string `json:"expires_in,omitempty"`
+}
+
+type serviceTokenRsp struct {
+	Token     string    `json:"token"`
+	View      string    `json:"view"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// createServiceToken mints a short-lived access token for a service account, bound to a view
+// in the caller's catalog. The caller must be allowed to adopt the view, and the token's
+// validity cannot exceed the configured default token validity.
+func createServiceToken(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	catalogID := catcommon.GetCatalogID(ctx)
+	if catalogID == uuid.Nil {
+		return nil, ErrInvalidView.Msg("unable to resolve catalog")
+	}
+
+	if r.Body == nil {
+		return nil, ErrBadRequest.Msg("request body is required")
+	}
+	body, goerr := io.ReadAll(r.Body)
+	if goerr != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+	req := serviceTokenReq{}
+	if goerr := json.Unmarshal(body, &req); goerr != nil {
+		return nil, ErrBadRequest.Msg("unable to parse request: " + goerr.Error())
+	}
+	if !serviceAccountRegex.MatchString(req.ServiceAccount) {
+		return nil, ErrBadRequest.Msg("service_account must be 1-128 alphanumeric, underscore or hyphen characters")
+	}
+	if req.View == "" {
+		return nil, ErrBadRequest.Msg("view is required")
+	}
+
+	expiresIn := req.ExpiresIn
+	if expiresIn == "" {
+		expiresIn = DefaultServiceTokenValidity
+	}
+	validity, goerr := config.ParseDuration(expiresIn)
+	if goerr != nil || validity <= 0 {
+		return nil, ErrBadRequest.Msg("invalid expires_in: " + expiresIn)
+	}
+	if validity > config.Config().Auth.GetDefaultTokenValidityOrDefault() {
+		return nil, ErrBadRequest.Msg("expires_in exceeds the maximum token validity of " + config.Config().Auth.DefaultTokenValidity)
+	}
+
+	allowed, err := policy.CanAdoptView(ctx, req.View)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		allowed = policy.CanAdoptViewAsUser(ctx, catalogID, req.View)
+	}
+	if !allowed {
+		return nil, ErrDisallowedByPolicy.Msg("view is not allowed to be adopted")
+	}
+
+	view, err := db.DB(ctx).GetViewByLabel(ctx, req.View, catalogID)
+	if err != nil {
+		return nil, ErrViewNotFound.Err(err)
+	}
+
+	token, tokenExpiry, err := CreateAccessToken(ctx,
+		view,
+		WithValidity(validity),
+		WithAdditionalClaims(map[string]any{
+			"token_use": catcommon.AccessTokenType,
+			"sub":       "service/" + req.ServiceAccount,
+		}),
+	)
+	if err != nil {
+		return nil, ErrTokenGeneration.Msg(err.Error())
+	}
+
+	log.Ctx(ctx).Info().Str("service_account", req.ServiceAccount).Str("view", view.Label).Msg("issued service token")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &serviceTokenRsp{
+			Token:     token,
+			View:      view.Label,
+			ExpiresAt: tokenExpiry,
+		},
+	}, nil
+}
@@ -9,10 +9,12 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/keymanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/userauth"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
 	"github.com/tansive/tansive-internal/internal/common/uuid"
 )
@@ -118,6 +120,15 @@ func ResolveAccessToken(ctx context.Context, token *jwt.Token) (*Token, apperror
 		return nil, ErrInvalidToken.Msg(fmt.Sprintf("view tenant ID %s does not match token tenant ID %s", view.TenantID, tenantID))
 	}
 
+	// Tokens carry the label of the view they were minted for; reject them if the view no longer matches
+	if label, ok := claims["view"].(string); ok && label != view.Label {
+		return nil, ErrInvalidToken.Msg(fmt.Sprintf("view label %s does not match token view %s", view.Label, label))
+	}
+
+	if err := policy.CheckViewActive(view, time.Now()); err != nil {
+		return nil, err
+	}
+
 	tokenObj := &Token{
 		token:  token,
 		claims: claims,
@@ -239,7 +250,7 @@ func (t *Token) Validate(ctx context.Context) apperrors.Error {
 		log.Ctx(ctx).Debug().Msg("token missing or invalid jti claim")
 		return ErrInvalidToken.Msg("missing or invalid jti claim")
 	}
-	if revocationChecker.IsRevoked(jti) {
+	if userauth.IsTokenRevoked(ctx, jti) {
 		log.Ctx(ctx).Debug().Str("jti", jti).Msg("token revoked")
 		return ErrInvalidToken.Msg("token revoked")
 	}
@@ -31,7 +31,12 @@ func CreateIdentityToken(ctx context.Context, additionalClaims map[string]any) (
 		log.Ctx(ctx).Error().Err(goerr).Msg("unable to parse token duration")
 		return "", time.Time{}, ErrUnableToParseTokenDuration.MsgErr("unable to parse token duration", goerr)
 	}
+	return CreateIdentityTokenWithValidity(ctx, tokenDuration, additionalClaims)
+}
 
+// CreateIdentityTokenWithValidity creates a new JWT identity token that is valid for the given
+// duration, such as a refreshed token that keeps the lifetime of the token it replaces.
+func CreateIdentityTokenWithValidity(ctx context.Context, tokenDuration time.Duration, additionalClaims map[string]any) (string, time.Time, error) {
 	tokenExpiry := time.Now().Add(tokenDuration)
 
 	// Generate a unique token ID
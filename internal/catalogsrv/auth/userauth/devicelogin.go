@@ -0,0 +1,378 @@
+package userauth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+const (
+	// DeviceCodeValidity is how long a device login can wait for approval
+	DeviceCodeValidity = 10 * time.Minute
+	// DeviceCodePollInterval is the minimum interval, in seconds, at which clients poll for a token
+	DeviceCodePollInterval = 5
+	// deviceCodeSlowDown is added to the poll interval of a client that polls too fast
+	deviceCodeSlowDown = 5 * time.Second
+	// maxPendingDeviceLogins caps the device logins waiting for approval at any time
+	maxPendingDeviceLogins = 1000
+	// deviceLoginStartLimit is how many device logins a client address may start per
+	// deviceLoginStartWindow
+	deviceLoginStartLimit  = 10
+	deviceLoginStartWindow = time.Minute
+	// userCodeAlphabet leaves out vowels and look-alike characters so codes are easy to type
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+)
+
+// deviceLogin is a pending device login. It is approved by a signed in user, after which the
+// device can exchange its device code for an identity token of that user exactly once.
+type deviceLogin struct {
+	deviceCode string
+	userCode   string
+	expireAt   time.Time
+	approved   bool
+	tenantID   catcommon.TenantId
+	userID     string
+	interval   time.Duration
+	lastPollAt time.Time
+}
+
+// deviceLoginStarts counts the device logins started by a client address in the current window
+type deviceLoginStarts struct {
+	count   int
+	resetAt time.Time
+}
+
+// deviceLoginStore holds pending device logins in memory. Device logins are short-lived, so
+// they are not persisted; a login in progress is lost if the server restarts.
+type deviceLoginStore struct {
+	mu           sync.Mutex
+	byDeviceCode map[string]*deviceLogin
+	byUserCode   map[string]*deviceLogin
+	starts       map[string]*deviceLoginStarts
+}
+
+var deviceLogins = &deviceLoginStore{
+	byDeviceCode: make(map[string]*deviceLogin),
+	byUserCode:   make(map[string]*deviceLogin),
+	starts:       make(map[string]*deviceLoginStarts),
+}
+
+// add records a device login started from the client address. It fails if the client has
+// started too many logins recently or too many logins are pending.
+func (s *deviceLoginStore) add(client string, dl *deviceLogin) apperrors.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpired()
+	now := time.Now()
+	starts, ok := s.starts[client]
+	if !ok {
+		starts = &deviceLoginStarts{resetAt: now.Add(deviceLoginStartWindow)}
+		s.starts[client] = starts
+	}
+	if starts.count >= deviceLoginStartLimit {
+		return ErrTooManyDeviceLogins.Msg("too many device logins started, try again later")
+	}
+	if len(s.byDeviceCode) >= maxPendingDeviceLogins {
+		return ErrTooManyDeviceLogins.Msg("too many device logins pending, try again later")
+	}
+	starts.count++
+	s.byDeviceCode[dl.deviceCode] = dl
+	s.byUserCode[dl.userCode] = dl
+	return nil
+}
+
+func (s *deviceLoginStore) approve(userCode string, tenantID catcommon.TenantId, userID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpired()
+	dl, ok := s.byUserCode[userCode]
+	if !ok || dl.approved {
+		return false
+	}
+	dl.approved = true
+	dl.tenantID = tenantID
+	dl.userID = userID
+	return true
+}
+
+// take returns the device login for the device code. An approved login is removed so that
+// its token is only issued once. A pending login polled sooner than its interval after the
+// previous poll has its interval increased, and slowDown is set.
+func (s *deviceLoginStore) take(deviceCode string) (dl deviceLogin, slowDown bool, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeExpired()
+	pending, ok := s.byDeviceCode[deviceCode]
+	if !ok {
+		return deviceLogin{}, false, false
+	}
+	if pending.approved {
+		delete(s.byDeviceCode, pending.deviceCode)
+		delete(s.byUserCode, pending.userCode)
+		return *pending, false, true
+	}
+	now := time.Now()
+	if !pending.lastPollAt.IsZero() && now.Sub(pending.lastPollAt) < pending.interval {
+		pending.interval += deviceCodeSlowDown
+		slowDown = true
+	}
+	pending.lastPollAt = now
+	return *pending, slowDown, true
+}
+
+func (s *deviceLoginStore) purgeExpired() {
+	now := time.Now()
+	for code, dl := range s.byDeviceCode {
+		if now.After(dl.expireAt) {
+			delete(s.byDeviceCode, code)
+			delete(s.byUserCode, dl.userCode)
+		}
+	}
+	for client, starts := range s.starts {
+		if now.After(starts.resetAt) {
+			delete(s.starts, client)
+		}
+	}
+}
+
+type deviceLoginRsp struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// StartDeviceLogin begins a device login for a client that cannot sign in by itself, such as
+// the CLI on a machine without a browser. The user approves the returned user code while
+// signed in, and the client polls PollDeviceLogin with the device code until then. Each
+// client address may only start a few logins per minute.
+func StartDeviceLogin(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	deviceCode, err := randomDeviceCode()
+	if err != nil {
+		return nil, err
+	}
+	userCode, err := randomUserCode()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := deviceLogins.add(clientAddress(r), &deviceLogin{
+		deviceCode: deviceCode,
+		userCode:   userCode,
+		expireAt:   time.Now().Add(DeviceCodeValidity),
+		interval:   DeviceCodePollInterval * time.Second,
+	}); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("client", clientAddress(r)).Msg("device login refused")
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_code", userCode).Msg("started device login")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &deviceLoginRsp{
+			DeviceCode:              deviceCode,
+			UserCode:                userCode,
+			VerificationURI:         verificationURI(r),
+			VerificationURIComplete: verificationURI(r) + "?user_code=" + url.QueryEscape(userCode),
+			ExpiresIn:               int(DeviceCodeValidity.Seconds()),
+			Interval:                DeviceCodePollInterval,
+		},
+	}, nil
+}
+
+type approveDeviceLoginReq struct {
+	UserCode string `json:"user_code"`
+}
+
+// ApproveDeviceLogin approves a pending device login on behalf of the signed in user, who
+// becomes the subject of the identity token issued to the device. The user must be signed in
+// with an identity token.
+func ApproveDeviceLogin(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	userContext := catcommon.GetUserContext(ctx)
+	if catcommon.GetSubjectType(ctx) != catcommon.SubjectTypeUser || userContext == nil || userContext.UserID == "" {
+		return nil, ErrInvalidToken.Msg("device logins can only be approved by users")
+	}
+	// Access and handoff tokens are narrowed to a view and cannot mint an identity token
+	if policy.GetViewDefinition(ctx) != nil {
+		return nil, ErrInvalidToken.Msg("device logins must be approved with an identity token")
+	}
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, ErrInvalidRequest.Msg("missing tenant ID")
+	}
+
+	req := approveDeviceLoginReq{}
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	userCode := normalizeUserCode(req.UserCode)
+	if userCode == "" {
+		return nil, ErrInvalidRequest.Msg("user_code is required")
+	}
+
+	if !deviceLogins.approve(userCode, tenantID, userContext.UserID) {
+		return nil, ErrDeviceCodeNotFound
+	}
+
+	log.Ctx(ctx).Info().Str("user_code", userCode).Str("user", userContext.UserID).Msg("approved device login")
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+type pollDeviceLoginReq struct {
+	DeviceCode string `json:"device_code"`
+}
+
+type pollDeviceLoginRsp struct {
+	Status    string     `json:"status"`
+	Token     string     `json:"token,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Interval  int        `json:"interval,omitempty"`
+}
+
+// PollDeviceLogin returns the identity token of a device login once it has been approved.
+// Until then it responds with 202 Accepted and a pending status. A client that polls faster
+// than its interval gets 429 Too Many Requests with a slow_down status and the increased
+// interval it must wait between polls from then on.
+func PollDeviceLogin(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	req := pollDeviceLoginReq{}
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if req.DeviceCode == "" {
+		return nil, ErrInvalidRequest.Msg("device_code is required")
+	}
+
+	dl, slowDown, ok := deviceLogins.take(req.DeviceCode)
+	if !ok {
+		return nil, ErrDeviceCodeExpired
+	}
+	if slowDown {
+		return &httpx.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Response: &pollDeviceLoginRsp{
+				Status:   "slow_down",
+				Interval: int(dl.interval.Seconds()),
+			},
+		}, nil
+	}
+	if !dl.approved {
+		return &httpx.Response{
+			StatusCode: http.StatusAccepted,
+			Response: &pollDeviceLoginRsp{
+				Status: "pending",
+			},
+		}, nil
+	}
+
+	ctx = catcommon.WithTenantID(ctx, dl.tenantID)
+	ctx = catcommon.WithCatalogContext(ctx, &catcommon.CatalogContext{
+		UserContext: &catcommon.UserContext{
+			UserID: dl.userID,
+		},
+		Subject: catcommon.SubjectTypeUser,
+	})
+
+	token, tokenExpiry, err := CreateIdentityToken(ctx, getIdentityTokenClaims(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &pollDeviceLoginRsp{
+			Status:    "approved",
+			Token:     token,
+			ExpiresAt: &tokenExpiry,
+		},
+	}, nil
+}
+
+// verificationURI returns the page on which the user approves the device login
+func verificationURI(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + "/auth/device"
+}
+
+// clientAddress returns the address of the client of a request without its port
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func randomDeviceCode() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", ErrTokenGeneration.MsgErr("unable to generate device code", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// randomUserCode returns a code of the form XXXX-XXXX
+func randomUserCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", ErrTokenGeneration.MsgErr("unable to generate user code", err)
+	}
+	var code strings.Builder
+	for i, c := range b {
+		if i == 4 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(userCodeAlphabet[int(c)%len(userCodeAlphabet)])
+	}
+	return code.String(), nil
+}
+
+// normalizeUserCode accepts user codes typed in lower case or without the separator
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) != 8 {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
+
+func readJSON(r *http.Request, v any) error {
+	if r.Body == nil {
+		return ErrInvalidRequest.Msg("request body is required")
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return httpx.ErrUnableToReadRequest()
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return ErrInvalidRequest.Msg("unable to parse request: " + err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package userauth
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+)
+
+// deviceLoginPage lets a signed in user approve a device login from a browser. The approval
+// is posted to the approve API with the identity token of the user, as the CLI does.
+var deviceLoginPage = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Approve device login</title>
+<style>
+body { font-family: sans-serif; max-width: 32em; margin: 4em auto; padding: 0 1em; }
+label, input, button { display: block; width: 100%; margin-top: 0.5em; }
+input { font-size: 1.2em; padding: 0.3em; box-sizing: border-box; }
+code { background: #eee; padding: 0.1em 0.3em; }
+#result { margin-top: 1em; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Approve device login</h1>
+<p>Approve the login only if you started it and the code matches the one shown on your device.</p>
+<form id="approve">
+<label for="user_code">Code</label>
+<input id="user_code" name="user_code" value="{{.UserCode}}" placeholder="XXXX-XXXX" autocomplete="off" required>
+<label for="token">Your identity token</label>
+<input id="token" name="token" type="password" autocomplete="off" required>
+<button type="submit">Approve</button>
+</form>
+<p id="result"></p>
+<p>You can also approve the login from a signed in session with
+<code>tansive login approve {{if .UserCode}}{{.UserCode}}{{else}}CODE{{end}}</code>.</p>
+<script>
+document.getElementById("approve").addEventListener("submit", async (event) => {
+	event.preventDefault();
+	const result = document.getElementById("result");
+	const rsp = await fetch("device/approve", {
+		method: "POST",
+		headers: {
+			"Content-Type": "application/json",
+			"Authorization": "Bearer " + document.getElementById("token").value.trim(),
+		},
+		body: JSON.stringify({ user_code: document.getElementById("user_code").value }),
+	});
+	if (rsp.ok) {
+		result.textContent = "Login approved. You can return to your device.";
+		return;
+	}
+	const body = await rsp.json().catch(() => ({}));
+	result.textContent = "Approval failed: " + (body.error || rsp.statusText);
+});
+</script>
+</body>
+</html>
+`))
+
+// DeviceLoginPage serves the verification page of device logins, with the user code
+// filled in from the user_code query parameter of verification_uri_complete.
+func DeviceLoginPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Cache-Control", "no-store")
+	data := struct{ UserCode string }{
+		UserCode: normalizeUserCode(r.URL.Query().Get("user_code")),
+	}
+	if err := deviceLoginPage.Execute(w, data); err != nil {
+		log.Ctx(r.Context()).Error().Err(err).Msg("unable to render device login page")
+	}
+}
@@ -19,7 +19,15 @@ var (
 	ErrInvalidToken               apperrors.Error = ErrIDToken.New("invalid token").SetStatusCode(http.StatusUnauthorized)
 )
 
+// Device login errors
+var (
+	ErrDeviceCodeNotFound  apperrors.Error = ErrIDToken.New("no pending device login for code").SetStatusCode(http.StatusNotFound)
+	ErrDeviceCodeExpired   apperrors.Error = ErrIDToken.New("device login expired").SetStatusCode(http.StatusGone)
+	ErrTooManyDeviceLogins apperrors.Error = ErrIDToken.New("too many device logins").SetStatusCode(http.StatusTooManyRequests)
+)
+
 // Misc errors
 var (
 	ErrLoginNotSupported apperrors.Error = ErrIDToken.New("login is not supported").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidRequest    apperrors.Error = ErrIDToken.New("invalid request").SetStatusCode(http.StatusBadRequest)
 )
@@ -50,7 +50,7 @@ func setDefaultSingleUserContext(ctx context.Context) (context.Context, error) {
 	}
 
 	catCtx.UserContext = &catcommon.UserContext{
-		UserID: "default-user",
+		UserID: catcommon.SingleUserID,
 	}
 
 	catCtx.Subject = catcommon.SubjectTypeUser
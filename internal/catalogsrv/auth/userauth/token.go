@@ -9,32 +9,50 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
 	"github.com/tansive/tansive-internal/internal/common/uuid"
 )
 
 // RevocationChecker defines the interface for checking if a token has been revoked
 type RevocationChecker interface {
-	IsRevoked(jti string) bool
+	IsRevoked(ctx context.Context, jti string) bool
 }
 
-// defaultRevocationChecker is a simple implementation that always returns false
-type defaultRevocationChecker struct{}
+// DBRevocationChecker checks the token denylist of the tenant in the context. It fails
+// closed, so a token is treated as revoked if the denylist cannot be read.
+type DBRevocationChecker struct{}
 
-func (c *defaultRevocationChecker) IsRevoked(jti string) bool {
-	return false
+func (c *DBRevocationChecker) IsRevoked(ctx context.Context, jti string) bool {
+	id, err := uuid.Parse(jti)
+	if err != nil {
+		log.Ctx(ctx).Debug().Str("jti", jti).Msg("invalid jti")
+		return true
+	}
+	revoked, dberr := db.DB(ctx).IsTokenRevoked(ctx, id)
+	if dberr != nil {
+		log.Ctx(ctx).Error().Err(dberr).Str("jti", jti).Msg("unable to check token revocation")
+		return true
+	}
+	return revoked
 }
 
-var revocationChecker RevocationChecker = &defaultRevocationChecker{}
+var revocationChecker RevocationChecker = &DBRevocationChecker{}
 
-// SetRevocationChecker sets the revocation checker implementation
+// SetRevocationChecker sets the revocation checker implementation. It is used for both
+// identity tokens and the access tokens validated by the auth package.
 func SetRevocationChecker(checker RevocationChecker) {
 	if checker == nil {
-		checker = &defaultRevocationChecker{}
+		checker = &DBRevocationChecker{}
 	}
 	revocationChecker = checker
 }
 
+// IsTokenRevoked reports whether the token with the given ID has been revoked
+func IsTokenRevoked(ctx context.Context, jti string) bool {
+	return revocationChecker.IsRevoked(ctx, jti)
+}
+
 // RequiredClaims is a list of claims that must be present in the identity token
 var RequiredClaims = []string{
 	"tenant_id",
@@ -186,7 +204,7 @@ func (t *IdentityToken) Validate(ctx context.Context) apperrors.Error {
 		log.Ctx(ctx).Debug().Msg("token missing or invalid jti claim")
 		return ErrInvalidToken.Msg("missing or invalid jti claim")
 	}
-	if revocationChecker.IsRevoked(jti) {
+	if revocationChecker.IsRevoked(ctx, jti) {
 		log.Ctx(ctx).Debug().Str("jti", jti).Msg("token revoked")
 		return ErrInvalidToken.Msg("token revoked")
 	}
@@ -2,17 +2,20 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
 	log "github.com/rs/zerolog"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/oidc"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
 )
 
-// UserAuthMiddleware handles authentication for both normal and single-user modes
+// UserAuthMiddleware handles authentication for tansive tokens, tokens issued by a configured
+// OIDC provider, and single-user mode
 func UserAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
@@ -27,12 +30,42 @@ func UserAuthMiddleware(next http.Handler) http.Handler {
 		token := strings.TrimPrefix(authHeader, "Bearer ")
 		token = strings.TrimSpace(token)
 
+		// API keys act on behalf of a view, not a user
+		if IsAPIKey(token) {
+			log.Ctx(ctx).Warn().Msg("api key used for user authentication")
+			httpx.ErrUnAuthorized("api keys cannot be used for this operation").Send(w)
+			return
+		}
+
 		// First try normal token validation
 		ctx, err := ValidateToken(ctx, token)
 		if err == nil {
 			next.ServeHTTP(w, r.WithContext(ctx))
 			return
 		}
+		if errors.Is(err, ErrTenantSuspended) {
+			log.Ctx(ctx).Warn().Err(err).Msg("token of suspended tenant")
+			httpx.SendError(w, ErrTenantSuspended)
+			return
+		}
+
+		// Tokens from the configured OIDC issuer are verified against the provider's keys
+		if provider := oidc.GetProvider(); provider != nil && provider.Issued(token) {
+			identity, oidcErr := provider.Verify(ctx, token)
+			if oidcErr != nil {
+				log.Ctx(ctx).Warn().Err(oidcErr).Msg("oidc token validation failed")
+				SendTokenError(w, oidcErr)
+				return
+			}
+			ctx = withOIDCIdentity(ctx, identity)
+			if err := checkTenantActive(ctx); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("oidc token refused")
+				SendTokenError(w, err)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
 
 		// If token validation failed and we're in single user mode, try that
 		if config.IsTest() {
@@ -52,6 +85,26 @@ func UserAuthMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// withOIDCIdentity sets the tenant, project and user of a verified OIDC identity in the context.
+// The project is only set when the token carries one.
+func withOIDCIdentity(ctx context.Context, identity *oidc.Identity) context.Context {
+	ctx = catcommon.WithTenantID(ctx, catcommon.TenantId(identity.TenantID))
+	if identity.ProjectID != "" {
+		ctx = catcommon.WithProjectID(ctx, catcommon.ProjectId(identity.ProjectID))
+	}
+
+	catCtx := catcommon.GetCatalogContext(ctx)
+	if catCtx == nil {
+		catCtx = &catcommon.CatalogContext{}
+	}
+	catCtx.UserContext = &catcommon.UserContext{
+		UserID: identity.UserID,
+	}
+	catCtx.Subject = catcommon.SubjectTypeUser
+
+	return catcommon.WithCatalogContext(ctx, catCtx)
+}
+
 // handleSingleUserMode processes authentication in single-user mode
 func handleSingleUserMode(ctx context.Context, token string) (context.Context, error) {
 	if token != config.Config().Auth.TestUserToken {
@@ -74,7 +127,7 @@ func setDefaultSingleUserContext(ctx context.Context) (context.Context, error) {
 	}
 
 	catCtx.UserContext = &catcommon.UserContext{
-		UserID: "default-user",
+		UserID: catcommon.SingleUserID,
 	}
 
 	catCtx.Subject = catcommon.SubjectTypeUser
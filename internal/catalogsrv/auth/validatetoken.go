@@ -2,13 +2,15 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/userauth"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
 	"github.com/tansive/tansive-internal/internal/common/uuid"
@@ -22,6 +24,7 @@ func setCatalogContext(ctx context.Context, viewDef *policy.ViewDefinition, toke
 		Variant:   viewDef.Scope.Variant,
 		Namespace: viewDef.Scope.Namespace,
 		CatalogID: tokenObj.GetCatalogID(),
+		ViewID:    tokenObj.GetViewID(),
 	}
 
 	sub := tokenObj.GetSubject()
@@ -30,6 +33,11 @@ func setCatalogContext(ctx context.Context, viewDef *policy.ViewDefinition, toke
 			UserID: strings.TrimPrefix(sub, "user/"),
 		}
 		catalogContext.Subject = catcommon.SubjectTypeUser
+	} else if strings.HasPrefix(sub, "service/") {
+		catalogContext.ServiceContext = &catcommon.ServiceContext{
+			ServiceName: strings.TrimPrefix(sub, "service/"),
+		}
+		catalogContext.Subject = catcommon.SubjectTypeService
 	} else if strings.HasPrefix(sub, "session/") {
 		catalogContext.Subject = catcommon.SubjectTypeSession
 		sessionID, err := uuid.Parse(strings.TrimPrefix(sub, "session/"))
@@ -44,12 +52,28 @@ func setCatalogContext(ctx context.Context, viewDef *policy.ViewDefinition, toke
 	return catalogContext, nil
 }
 
-// ValidateToken validates the provided token and sets up the appropriate context
+// ValidateToken validates the provided token and sets up the appropriate context. Tokens of
+// suspended tenants are refused with ErrTenantSuspended.
 func ValidateToken(ctx context.Context, token string) (context.Context, error) {
+	ctx, err := validateToken(ctx, token)
+	if err != nil {
+		return ctx, err
+	}
+	if err := checkTenantActive(ctx); err != nil {
+		return ctx, err
+	}
+	return ctx, nil
+}
+
+func validateToken(ctx context.Context, token string) (context.Context, error) {
 	if token == "" {
 		return ctx, ErrInvalidToken.Msg("empty token. login required")
 	}
 
+	if IsAPIKey(token) {
+		return handleAPIKey(ctx, token)
+	}
+
 	tokenType, jwtToken, err := ParseAndValidateToken(ctx, token)
 	if err != nil {
 		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
@@ -65,6 +89,23 @@ func ValidateToken(ctx context.Context, token string) (context.Context, error) {
 	}
 }
 
+// checkTenantActive fails if the tenant of the context is suspended. A tenant that is not found
+// is left to the checks of the token.
+func checkTenantActive(ctx context.Context) error {
+	tenantID := catcommon.GetTenantID(ctx)
+	tenant, err := db.DB(ctx).GetTenant(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if tenant.SuspendedAt != nil {
+		return ErrTenantSuspended.Msg("tenant " + string(tenantID) + " is suspended")
+	}
+	return nil
+}
+
 func handleIdentityToken(ctx context.Context, jwtToken *jwt.Token) (context.Context, error) {
 	if jwtToken == nil {
 		return ctx, ErrInvalidToken.Msg("login expired")
@@ -108,25 +149,32 @@ func handleAccessToken(ctx context.Context, jwtToken *jwt.Token) (context.Contex
 		return ctx, fmt.Errorf("%w: %v", ErrInvalidToken, err)
 	}
 
-	view := tokenObj.GetView()
-	viewDef := policy.ViewDefinition{}
-	if err := json.Unmarshal(view.Rules, &viewDef); err != nil {
-		return ctx, fmt.Errorf("%w: %v", ErrInvalidViewRules, err)
-	}
-
 	tenantID := tokenObj.GetTenantID()
 	if tenantID == "" {
 		return ctx, ErrMissingTenantID
 	}
-
-	ctx = policy.WithViewDefinition(ctx, &viewDef)
 	ctx = catcommon.WithTenantID(ctx, catcommon.TenantId(tenantID))
 
-	catalogContext, err := setCatalogContext(ctx, &viewDef, tokenObj)
+	// included views are loaded from the token's tenant
+	viewDef, loadErr := policy.LoadViewDefinition(ctx, tokenObj.GetView())
+	if loadErr != nil {
+		return ctx, fmt.Errorf("%w: %v", ErrInvalidViewRules, loadErr)
+	}
+
+	ctx = policy.WithViewDefinition(ctx, viewDef)
+
+	catalogContext, err := setCatalogContext(ctx, viewDef, tokenObj)
 	if err != nil {
 		return ctx, err
 	}
 	ctx = catcommon.WithCatalogContext(ctx, catalogContext)
 
+	// Handoff tokens remain bound to the view that adopted their view
+	if _, ok := tokenObj.Get("parent_view_id"); ok {
+		if err := validateHandoff(ctx, tokenObj, viewDef); err != nil {
+			return ctx, err
+		}
+	}
+
 	return ctx, nil
 }
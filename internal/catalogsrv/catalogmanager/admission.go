@@ -0,0 +1,345 @@
+package catalogmanager
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/keymanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tansive/tansive-internal/pkg/types"
+)
+
+// maxAdmissionResponseSize limits the size of the responses read from admission webhooks.
+const maxAdmissionResponseSize = 1 << 20
+
+// Headers of the signature of an admission request. The signature is the Ed25519 signature
+// of the timestamp, a period and the body, made with the key of the JWKS at
+// /.well-known/jwks.json with the given key ID, so that webhooks can verify that a request
+// comes from the server.
+const (
+	AdmissionSignatureHeader          = "X-Tansive-Signature"
+	AdmissionSignatureTimestampHeader = "X-Tansive-Signature-Timestamp"
+	AdmissionSignatureKeyIDHeader     = "X-Tansive-Signature-Key-ID"
+)
+
+// admissionClient calls admission webhooks. It does not follow redirects, and refuses
+// loopback, link-local and private addresses outside the allowed networks of the
+// configuration, so that webhooks cannot be used to reach the internal network of the server.
+// Connections are not reused, so that every call is checked against the allowed networks.
+var admissionClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 10 * time.Second,
+			Control: checkAdmissionAddress,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		DisableKeepAlives:   true,
+	},
+}
+
+// checkAdmissionAddress refuses connections to loopback, link-local, private and unspecified
+// addresses outside the allowed networks. It is called with the resolved address, so a host
+// name resolving to a refused address is refused as well.
+func checkAdmissionAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid address %s", address)
+	}
+	if !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsPrivate() && !ip.IsUnspecified() && !ip.IsMulticast() {
+		return nil
+	}
+	networks, err := config.Config().AdmissionWebhooks.GetAllowedNetworks()
+	if err != nil {
+		return err
+	}
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("address %s is not allowed for admission webhooks", ip)
+}
+
+// AdmissionRequest is posted to an admission webhook for a write to a resource. Object is the
+// resource as it is about to be stored, and OldObject the stored resource, if any. Operations
+// are the operations the write performs. DryRun is set when the write will not be persisted,
+// so webhooks with side effects can skip them.
+type AdmissionRequest struct {
+	UID        string          `json:"uid"`
+	Operations []string        `json:"operations"`
+	Catalog    string          `json:"catalog"`
+	Variant    string          `json:"variant"`
+	Namespace  string          `json:"namespace,omitempty"`
+	Resource   string          `json:"resource"`
+	User       string          `json:"user,omitempty"`
+	Object     json.RawMessage `json:"object"`
+	OldObject  json.RawMessage `json:"oldObject,omitempty"`
+	DryRun     bool            `json:"dryRun,omitempty"`
+}
+
+// AdmissionResponse is the answer of an admission webhook. A write is rejected unless every
+// webhook allows it, with Message explaining why. Mutating webhooks may return a new Value or
+// Schema for the resource; validating webhooks cannot change the write.
+type AdmissionResponse struct {
+	Allowed bool            `json:"allowed"`
+	Message string          `json:"message,omitempty"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Schema  json.RawMessage `json:"schema,omitempty"`
+}
+
+type ctxAdmittedKeyType struct{}
+
+var ctxAdmittedKey ctxAdmittedKeyType
+
+// withAdmitted marks the context as belonging to a write whose admission webhooks were called
+// before its transaction started, so that saving the resource does not call them again.
+func withAdmitted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxAdmittedKey, true)
+}
+
+func isAdmitted(ctx context.Context) bool {
+	admitted, _ := ctx.Value(ctxAdmittedKey).(bool)
+	return admitted
+}
+
+// AdmitWrite calls the admission webhooks for a write of rsrcJSON through h. Webhooks are
+// called over the network, so writes made in a transaction call AdmitWrite before the
+// transaction starts, and then write the returned object with the returned context, with
+// which the webhooks are not called again. Objects of kinds without admission webhooks are
+// returned unchanged.
+func AdmitWrite(ctx context.Context, h interfaces.KindHandler, rsrcJSON []byte) (context.Context, []byte, apperrors.Error) {
+	rh, ok := h.(*resourceKindHandler)
+	if !ok {
+		return ctx, rsrcJSON, nil
+	}
+	admitted, err := rh.admit(ctx, rsrcJSON)
+	if err != nil {
+		return ctx, nil, err
+	}
+	return withAdmitted(ctx), admitted, nil
+}
+
+// admitWrite calls the admission webhooks of the catalog of the resource.
+func (rm *resourceManager) admitWrite(ctx context.Context) apperrors.Error {
+	catalogID, err := rm.catalogID(ctx)
+	if err != nil {
+		return err
+	}
+	return rm.admit(ctx, catalogID)
+}
+
+// admit calls the admission webhooks of the catalog that handle the operations performed by
+// saving the resource, mutating webhooks first, and applies their changes to the resource. It
+// returns an error if a webhook rejects the write, changes the resource into one that does not
+// validate, or fails with the Fail failure policy.
+func (rm *resourceManager) admit(ctx context.Context, catalogID uuid.UUID) apperrors.Error {
+	webhooks, err := db.DB(ctx).ListAdmissionWebhooksByCatalog(ctx, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list admission webhooks")
+		return ErrUnableToLoadObject.Msg("unable to list admission webhooks")
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	m := rm.Metadata()
+	var old *resourceManager
+	if stored, err := LoadResourceManagerByPath(ctx, &m); err == nil {
+		old, _ = stored.(*resourceManager)
+	} else if !isNotFound(err) {
+		return err
+	}
+	operations := rm.admissionOperations(old)
+	if len(operations) == 0 {
+		return nil
+	}
+
+	var mutating, validating []admissionWebhookSpec
+	var mutatingNames, validatingNames []string
+	for _, w := range webhooks {
+		var spec admissionWebhookSpec
+		if err := json.Unmarshal(w.Spec, &spec); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("webhook", w.Label).Msg("failed to unmarshal admission webhook spec")
+			return ErrUnableToLoadObject.Msg("unable to load admission webhook " + w.Label)
+		}
+		if !spec.handles(operations) {
+			continue
+		}
+		if spec.Type == AdmissionWebhookMutating {
+			mutating = append(mutating, spec)
+			mutatingNames = append(mutatingNames, w.Label)
+		} else {
+			validating = append(validating, spec)
+			validatingNames = append(validatingNames, w.Label)
+		}
+	}
+
+	for i, spec := range mutating {
+		rsp, err := rm.callAdmissionWebhook(ctx, mutatingNames[i], spec, operations, old)
+		if err != nil || rsp == nil {
+			return err
+		}
+		if err := rm.applyAdmissionResponse(ctx, mutatingNames[i], spec, rsp); err != nil {
+			return err
+		}
+	}
+	for i, spec := range validating {
+		if _, err := rm.callAdmissionWebhook(ctx, validatingNames[i], spec, operations, old); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// admissionOperations returns the operations performed by saving the resource over old, the
+// stored resource, which is nil if the resource is being created.
+func (rm *resourceManager) admissionOperations(old *resourceManager) []string {
+	if old == nil {
+		return []string{AdmissionOperationSchema, AdmissionOperationValue}
+	}
+	definition := func(r Resource) []byte {
+		r.Spec.Value = types.NullableAny{}
+		j, _ := json.Marshal(r)
+		return j
+	}
+	var operations []string
+	if !bytes.Equal(definition(rm.resource), definition(old.resource)) {
+		operations = append(operations, AdmissionOperationSchema)
+	}
+	value, _ := json.Marshal(rm.resource.Spec.Value)
+	oldValue, _ := json.Marshal(old.resource.Spec.Value)
+	if !bytes.Equal(value, oldValue) {
+		operations = append(operations, AdmissionOperationValue)
+	}
+	return operations
+}
+
+// callAdmissionWebhook posts the write to the webhook and returns its response. A nil response
+// without an error means the webhook failed and its failure policy lets the write through.
+func (rm *resourceManager) callAdmissionWebhook(ctx context.Context, name string, spec admissionWebhookSpec, operations []string, old *resourceManager) (*AdmissionResponse, apperrors.Error) {
+	m := rm.Metadata()
+	req := AdmissionRequest{
+		UID:        uuid.New().String(),
+		Operations: operations,
+		Catalog:    m.Catalog,
+		Variant:    m.Variant.String(),
+		Namespace:  m.Namespace.String(),
+		Resource:   rm.FullyQualifiedName(),
+		DryRun:     catcommon.IsDryRun(ctx),
+	}
+	if userContext := catcommon.GetUserContext(ctx); userContext != nil {
+		req.User = userContext.UserID
+	}
+	req.Object, _ = json.Marshal(rm.resource)
+	if old != nil {
+		req.OldObject, _ = json.Marshal(old.resource)
+	}
+
+	rsp, goErr := postAdmissionRequest(ctx, spec, &req)
+	if goErr != nil {
+		if spec.ignoresFailure() {
+			log.Ctx(ctx).Warn().Err(goErr).Str("webhook", name).Msg("admission webhook failed, ignoring")
+			return nil, nil
+		}
+		log.Ctx(ctx).Error().Err(goErr).Str("webhook", name).Msg("admission webhook failed")
+		return nil, ErrAdmissionWebhookFailed.Msg("admission webhook " + name + " failed: " + goErr.Error())
+	}
+	if !rsp.Allowed {
+		msg := "write rejected by admission webhook " + name
+		if rsp.Message != "" {
+			msg += ": " + rsp.Message
+		}
+		return nil, ErrAdmissionDenied.Msg(msg)
+	}
+	return rsp, nil
+}
+
+// applyAdmissionResponse applies the changes of a mutating webhook to the resource and
+// validates the result.
+func (rm *resourceManager) applyAdmissionResponse(ctx context.Context, name string, spec admissionWebhookSpec, rsp *AdmissionResponse) apperrors.Error {
+	if rsp.Schema != nil && spec.handles([]string{AdmissionOperationSchema}) {
+		rm.resource.Spec.Schema = rsp.Schema
+	}
+	if rsp.Value != nil && spec.handles([]string{AdmissionOperationValue}) {
+		var value types.NullableAny
+		if err := json.Unmarshal(rsp.Value, &value); err != nil {
+			return ErrAdmissionDenied.Msg("admission webhook " + name + " returned an invalid value")
+		}
+		rm.resource.Spec.Value = value
+	}
+	if validationErrors := rm.resource.Validate(); validationErrors != nil {
+		log.Ctx(ctx).Error().Str("webhook", name).Msg("admission webhook produced an invalid resource")
+		return ErrAdmissionDenied.Msg("admission webhook " + name + " produced an invalid resource: " + validationErrors.Error())
+	}
+	return nil
+}
+
+// postAdmissionRequest posts req to the webhook and decodes its response.
+func postAdmissionRequest(ctx context.Context, spec admissionWebhookSpec, req *AdmissionRequest) (*AdmissionResponse, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, spec.timeout())
+	defer cancel()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, spec.URL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if err := signAdmissionRequest(ctx, httpReq, body); err != nil {
+		return nil, err
+	}
+	httpRsp, err := admissionClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpRsp.Body.Close()
+	if httpRsp.StatusCode < 200 || httpRsp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d", httpRsp.StatusCode)
+	}
+
+	var rsp AdmissionResponse
+	if err := json.NewDecoder(io.LimitReader(httpRsp.Body, maxAdmissionResponseSize)).Decode(&rsp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return &rsp, nil
+}
+
+// signAdmissionRequest sets the signature headers of an admission request with the given body.
+func signAdmissionRequest(ctx context.Context, req *http.Request, body []byte) error {
+	key, err := keymanager.GetKeyManager().GetActiveKey(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to get signing key: %w", err)
+	}
+	timestamp := time.Now().UTC().Format(time.RFC3339)
+	signature := ed25519.Sign(key.PrivateKey, append([]byte(timestamp+"."), body...))
+	req.Header.Set(AdmissionSignatureHeader, base64.StdEncoding.EncodeToString(signature))
+	req.Header.Set(AdmissionSignatureTimestampHeader, timestamp)
+	req.Header.Set(AdmissionSignatureKeyIDHeader, key.KeyID.String())
+	return nil
+}
@@ -0,0 +1,328 @@
+package catalogmanager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	schemaerr "github.com/tansive/tansive-internal/internal/catalogsrv/schema/errors"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/schema/schemavalidator"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// Admission webhook types. Mutating webhooks are called before validating ones and may change
+// the schema or value being written; validating webhooks can only accept or reject the write.
+const (
+	AdmissionWebhookValidating = "Validating"
+	AdmissionWebhookMutating   = "Mutating"
+)
+
+// Operations an admission webhook is called for. A write to a resource is a schema operation
+// if it creates the resource or changes its definition, and a value operation if it changes
+// its value; a write can be both.
+const (
+	AdmissionOperationSchema = "schema"
+	AdmissionOperationValue  = "value"
+)
+
+// Failure policies decide what happens to a write when its admission webhook cannot be called
+// or does not answer in time: Fail rejects the write and Ignore lets it through.
+const (
+	AdmissionFailurePolicyFail   = "Fail"
+	AdmissionFailurePolicyIgnore = "Ignore"
+)
+
+const defaultAdmissionTimeoutSeconds = 10
+
+// admissionWebhookSchema represents the structure of an admission webhook. Webhooks, like
+// views, belong to a catalog and apply to the resources of all its variants.
+type admissionWebhookSchema struct {
+	ApiVersion string                   `json:"apiVersion" validate:"required,validateVersion"`
+	Kind       string                   `json:"kind" validate:"required,kindValidator"`
+	Metadata   admissionWebhookMetadata `json:"metadata" validate:"required"`
+	Spec       admissionWebhookSpec     `json:"spec" validate:"required"`
+}
+
+type admissionWebhookMetadata struct {
+	Name        string            `json:"name" validate:"required,resourceNameValidator"`
+	Catalog     string            `json:"catalog,omitempty"`
+	Description string            `json:"description"`
+	Labels      map[string]string `json:"labels,omitempty" validate:"omitempty,labelsValidator"`
+	Annotations map[string]string `json:"annotations,omitempty" validate:"omitempty,annotationsValidator"`
+}
+
+// admissionWebhookSpec is the spec of an admission webhook. TimeoutSeconds defaults to 10 and
+// FailurePolicy to Fail.
+type admissionWebhookSpec struct {
+	URL            string   `json:"url" validate:"required"`
+	Type           string   `json:"type" validate:"required,oneof=Validating Mutating"`
+	Operations     []string `json:"operations" validate:"required,min=1,dive,oneof=schema value"`
+	TimeoutSeconds int      `json:"timeoutSeconds,omitempty" validate:"omitempty,min=1,max=30"`
+	FailurePolicy  string   `json:"failurePolicy,omitempty" validate:"omitempty,oneof=Fail Ignore"`
+}
+
+func (s admissionWebhookSpec) timeout() time.Duration {
+	if s.TimeoutSeconds == 0 {
+		return defaultAdmissionTimeoutSeconds * time.Second
+	}
+	return time.Duration(s.TimeoutSeconds) * time.Second
+}
+
+func (s admissionWebhookSpec) ignoresFailure() bool {
+	return s.FailurePolicy == AdmissionFailurePolicyIgnore
+}
+
+// handles reports whether the webhook is called for a write that performs any of operations.
+func (s admissionWebhookSpec) handles(operations []string) bool {
+	for _, op := range operations {
+		if slices.Contains(s.Operations, op) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate performs validation on the admission webhook and returns any validation errors.
+func (w *admissionWebhookSchema) Validate() schemaerr.ValidationErrors {
+	var validationErrors schemaerr.ValidationErrors
+	if w.Kind != catcommon.AdmissionWebhookKind {
+		validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind("kind"))
+	}
+	if w.Spec.URL != "" {
+		u, err := url.Parse(w.Spec.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("spec.url", "url must be an absolute http or https URL"))
+		}
+	}
+
+	err := schemavalidator.V().Struct(w)
+	if err == nil {
+		return validationErrors
+	}
+
+	validatorErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return append(validationErrors, schemaerr.ErrInvalidSchema)
+	}
+
+	value := reflect.ValueOf(w).Elem()
+	typeOfSchema := value.Type()
+
+	for _, e := range validatorErrors {
+		jsonFieldName := schemavalidator.GetJSONFieldPath(value, typeOfSchema, e.StructField())
+
+		switch e.Tag() {
+		case "required", "min":
+			validationErrors = append(validationErrors, schemaerr.ErrMissingRequiredAttribute(jsonFieldName))
+		case "oneof":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidFieldSchema(jsonFieldName, e.Value()))
+		case "max":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue(jsonFieldName, "must be at most "+e.Param()))
+		case "resourceNameValidator":
+			val, _ := e.Value().(string)
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidNameFormat(jsonFieldName, val))
+		case "kindValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind(jsonFieldName))
+		case "validateVersion":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidVersion(jsonFieldName))
+		case "labelsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidLabel(jsonFieldName))
+		case "annotationsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidAnnotation(jsonFieldName))
+		default:
+			validationErrors = append(validationErrors, schemaerr.ErrValidationFailed(jsonFieldName))
+		}
+	}
+
+	return validationErrors
+}
+
+type admissionWebhookKind struct {
+	reqCtx  interfaces.RequestContext
+	webhook *models.AdmissionWebhook
+}
+
+// Location returns the location path of the admission webhook.
+func (a *admissionWebhookKind) Location() string {
+	return "/admissionwebhooks/" + a.webhook.Label
+}
+
+// model parses and validates an admission webhook and returns its database model.
+func (a *admissionWebhookKind) model(ctx context.Context, resourceJSON []byte) (*models.AdmissionWebhook, apperrors.Error) {
+	webhook := &admissionWebhookSchema{}
+	if err := json.Unmarshal(resourceJSON, webhook); err != nil {
+		return nil, ErrInvalidSchema.Err(err)
+	}
+	if validationErrors := webhook.Validate(); validationErrors != nil {
+		return nil, ErrInvalidSchema.Err(validationErrors)
+	}
+
+	spec, err := json.Marshal(webhook.Spec)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to marshal admission webhook spec")
+		return nil, ErrSchemaSerialization
+	}
+	userContext := catcommon.GetUserContext(ctx)
+	if userContext == nil || userContext.UserID == "" {
+		return nil, dberror.ErrMissingUserContext.Msg("missing user context")
+	}
+	info := interfaces.ObjectInfo{Labels: webhook.Metadata.Labels, Annotations: webhook.Metadata.Annotations}
+	principal := "user/" + userContext.UserID
+	return &models.AdmissionWebhook{
+		Label:       webhook.Metadata.Name,
+		Description: webhook.Metadata.Description,
+		Info:        info.Marshal(),
+		Spec:        spec,
+		CatalogID:   a.reqCtx.CatalogID,
+		CreatedBy:   principal,
+		UpdatedBy:   principal,
+	}, nil
+}
+
+// Create registers a new admission webhook.
+func (a *admissionWebhookKind) Create(ctx context.Context, resourceJSON []byte) (string, apperrors.Error) {
+	w, err := a.model(ctx, resourceJSON)
+	if err != nil {
+		return "", err
+	}
+	if err := db.DB(ctx).CreateAdmissionWebhook(ctx, w); err != nil {
+		if errors.Is(err, dberror.ErrAlreadyExists) {
+			return "", ErrAlreadyExists.New("admission webhook already exists: " + w.Label)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to create admission webhook")
+		return "", ErrUnableToUpdateObject.Msg("unable to create admission webhook")
+	}
+	a.webhook = w
+	return a.Location(), nil
+}
+
+// Get retrieves an admission webhook by its name.
+func (a *admissionWebhookKind) Get(ctx context.Context) ([]byte, apperrors.Error) {
+	if a.reqCtx.ObjectName == "" {
+		return nil, ErrInvalidObject.Msg("admission webhook name is required")
+	}
+
+	w, err := db.DB(ctx).GetAdmissionWebhookByLabel(ctx, a.reqCtx.ObjectName, a.reqCtx.CatalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrObjectNotFound.Msg("admission webhook not found: " + a.reqCtx.ObjectName)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load admission webhook")
+		return nil, ErrUnableToLoadObject.Msg("unable to load admission webhook")
+	}
+	a.webhook = w
+
+	info := interfaces.ObjectInfoFromJSON(w.Info)
+	webhook := &admissionWebhookSchema{
+		ApiVersion: catcommon.ApiVersion,
+		Kind:       catcommon.AdmissionWebhookKind,
+		Metadata: admissionWebhookMetadata{
+			Name:        w.Label,
+			Catalog:     a.reqCtx.Catalog,
+			Description: w.Description,
+			Labels:      info.Labels,
+			Annotations: info.Annotations,
+		},
+	}
+	if err := json.Unmarshal(w.Spec, &webhook.Spec); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to unmarshal admission webhook spec")
+		return nil, ErrUnableToLoadObject.Msg("unable to unmarshal admission webhook spec")
+	}
+
+	jsonData, e := json.Marshal(webhook)
+	if e != nil {
+		log.Ctx(ctx).Error().Err(e).Msg("failed to marshal admission webhook")
+		return nil, ErrUnableToLoadObject.Msg("unable to fetch admission webhook")
+	}
+	return jsonData, nil
+}
+
+// Update modifies an existing admission webhook.
+func (a *admissionWebhookKind) Update(ctx context.Context, resourceJSON []byte) apperrors.Error {
+	w, err := a.model(ctx, resourceJSON)
+	if err != nil {
+		return err
+	}
+	if a.reqCtx.ObjectName != "" && a.reqCtx.ObjectName != w.Label {
+		return ErrInvalidObject.Msg("admission webhook name cannot be changed")
+	}
+	if err := db.DB(ctx).UpdateAdmissionWebhook(ctx, w); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrObjectNotFound.Msg("admission webhook not found: " + w.Label)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to update admission webhook")
+		return ErrUnableToUpdateObject.Msg("unable to update admission webhook")
+	}
+	a.webhook = w
+	return nil
+}
+
+// Delete removes an admission webhook.
+func (a *admissionWebhookKind) Delete(ctx context.Context) apperrors.Error {
+	if a.reqCtx.ObjectName == "" {
+		return ErrInvalidObject.Msg("admission webhook name is required")
+	}
+
+	err := db.DB(ctx).DeleteAdmissionWebhookByLabel(ctx, a.reqCtx.ObjectName, a.reqCtx.CatalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to delete admission webhook")
+		return ErrUnableToDeleteObject.Msg("unable to delete admission webhook")
+	}
+	return nil
+}
+
+// List returns the admission webhooks of the catalog.
+func (a *admissionWebhookKind) List(ctx context.Context) ([]byte, apperrors.Error) {
+	webhooks, err := db.DB(ctx).ListAdmissionWebhooksByCatalog(ctx, a.reqCtx.CatalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load admission webhooks")
+		return nil, ErrUnableToLoadObject.Msg("unable to load admission webhooks")
+	}
+
+	webhooks = interfaces.FilterByLabels(webhooks, func(w *models.AdmissionWebhook) map[string]string {
+		return interfaces.ObjectInfoFromJSON(w.Info).Labels
+	}, a.reqCtx.ListOptions.LabelSelector)
+
+	page, next := interfaces.Paginate(webhooks, func(w *models.AdmissionWebhook) string { return w.Label }, a.reqCtx.ListOptions)
+
+	items := make([]interfaces.ListItem, 0, len(page))
+	for _, w := range page {
+		items = append(items, interfaces.ListItem{
+			Name:        w.Label,
+			Description: w.Description,
+			Labels:      interfaces.ObjectInfoFromJSON(w.Info).Labels,
+		})
+	}
+
+	jsonData, e := interfaces.MarshalList(catcommon.KindNameAdmissionWebhooks, items, next)
+	if e != nil {
+		log.Ctx(ctx).Error().Err(e).Msg("failed to marshal admission webhook list")
+		return nil, ErrUnableToLoadObject.Msg("unable to marshal admission webhook list")
+	}
+	return jsonData, nil
+}
+
+// NewAdmissionWebhookKindHandler creates a new admission webhook resource manager.
+func NewAdmissionWebhookKindHandler(ctx context.Context, reqCtx interfaces.RequestContext) (interfaces.KindHandler, apperrors.Error) {
+	if reqCtx.Catalog == "" || reqCtx.CatalogID == uuid.Nil {
+		return nil, ErrInvalidCatalog
+	}
+	return &admissionWebhookKind{
+		reqCtx: reqCtx,
+	}, nil
+}
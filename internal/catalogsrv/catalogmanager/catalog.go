@@ -39,10 +39,88 @@ type catalogSchema struct {
 	Metadata   catalogMetadata `json:"metadata" validate:"required"`
 }
 
-// catalogMetadata contains metadata about a catalog
+// catalogMetadata contains metadata about a catalog. DefaultVariant is read only; it is
+// changed with SetDefaultVariant.
 type catalogMetadata struct {
-	Name        string `json:"name" validate:"required,resourceNameValidator"`
-	Description string `json:"description"`
+	Name           string            `json:"name" validate:"required,resourceNameValidator"`
+	Description    string            `json:"description"`
+	DefaultVariant string            `json:"defaultVariant,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty" validate:"omitempty,labelsValidator"`
+	Annotations    map[string]string `json:"annotations,omitempty" validate:"omitempty,annotationsValidator"`
+}
+
+func (m catalogMetadata) objectInfo() interfaces.ObjectInfo {
+	return interfaces.ObjectInfo{Labels: m.Labels, Annotations: m.Annotations}
+}
+
+// catalogInfo is stored in the info column of catalogs. Catalogs that do not name a default
+// variant use catcommon.DefaultVariant.
+type catalogInfo struct {
+	interfaces.ObjectInfo
+	DefaultVariant string `json:"defaultVariant,omitempty"`
+}
+
+func (i catalogInfo) jsonb() pgtype.JSONB {
+	if i.DefaultVariant == "" {
+		return infoJSONB(i.ObjectInfo)
+	}
+	b, err := json.Marshal(i)
+	if err != nil {
+		return pgtype.JSONB{Status: pgtype.Null}
+	}
+	return pgtype.JSONB{Bytes: b, Status: pgtype.Present}
+}
+
+func catalogInfoFromJSONB(info pgtype.JSONB) catalogInfo {
+	var ci catalogInfo
+	if info.Status == pgtype.Present && len(info.Bytes) > 0 {
+		_ = json.Unmarshal(info.Bytes, &ci)
+	}
+	return ci
+}
+
+// DefaultVariantOf returns the variant used for requests to the catalog that do not name a
+// variant.
+func DefaultVariantOf(catalog *models.Catalog) string {
+	if catalog == nil {
+		return catcommon.DefaultVariant
+	}
+	if v := catalogInfoFromJSONB(catalog.Info).DefaultVariant; v != "" {
+		return v
+	}
+	return catcommon.DefaultVariant
+}
+
+// SetDefaultVariant makes variant the default variant of the catalog. The variant must exist.
+func SetDefaultVariant(ctx context.Context, catalogID uuid.UUID, variant string) apperrors.Error {
+	if _, err := db.DB(ctx).GetVariant(ctx, catalogID, uuid.Nil, variant); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrVariantNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load variant")
+		return err
+	}
+	catalog, err := db.DB(ctx).GetCatalogByID(ctx, catalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrCatalogNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load catalog")
+		return err
+	}
+
+	info := catalogInfoFromJSONB(catalog.Info)
+	info.DefaultVariant = variant
+	if variant == catcommon.DefaultVariant {
+		info.DefaultVariant = ""
+	}
+	catalog.Info = info.jsonb()
+
+	if err := db.DB(ctx).UpdateCatalog(ctx, catalog); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to update catalog")
+		return ErrUnableToUpdateObject.Msg("failed to update catalog")
+	}
+	return nil
 }
 
 // catalogManager implements the schemamanager.CatalogManager interface
@@ -85,6 +163,10 @@ func (cs *catalogSchema) Validate() schemaerr.ValidationErrors {
 			validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind(jsonFieldName))
 		case "validateVersion":
 			validationErrors = append(validationErrors, schemaerr.ErrInvalidVersion(jsonFieldName))
+		case "labelsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidLabel(jsonFieldName))
+		case "annotationsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidAnnotation(jsonFieldName))
 		default:
 			validationErrors = append(validationErrors, schemaerr.ErrValidationFailed(jsonFieldName))
 		}
@@ -118,7 +200,7 @@ func NewCatalogManager(ctx context.Context, resourceJSON []byte, name string) (C
 		Name:        schema.Metadata.Name,
 		Description: schema.Metadata.Description,
 		ProjectID:   projectID,
-		Info:        pgtype.JSONB{Status: pgtype.Null},
+		Info:        infoJSONB(schema.Metadata.objectInfo()),
 	}
 
 	return &catalogManager{
@@ -171,12 +253,16 @@ func (cm *catalogManager) Save(ctx context.Context) apperrors.Error {
 
 // ToJson converts the catalog to its JSON representation
 func (cm *catalogManager) ToJson(ctx context.Context) ([]byte, apperrors.Error) {
+	info := catalogInfoFromJSONB(cm.catalog.Info)
 	schema := catalogSchema{
 		ApiVersion: catcommon.ApiVersion,
 		Kind:       catcommon.CatalogKind,
 		Metadata: catalogMetadata{
-			Name:        cm.catalog.Name,
-			Description: cm.catalog.Description,
+			Name:           cm.catalog.Name,
+			Description:    cm.catalog.Description,
+			DefaultVariant: info.DefaultVariant,
+			Labels:         info.Labels,
+			Annotations:    info.Annotations,
 		},
 	}
 
@@ -201,6 +287,19 @@ func DeleteCatalogByName(ctx context.Context, name string) apperrors.Error {
 	return nil
 }
 
+// RestoreCatalogByName restores a soft deleted catalog that has not been purged yet
+func RestoreCatalogByName(ctx context.Context, name string) apperrors.Error {
+	err := db.DB(ctx).RestoreCatalog(ctx, name)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrCatalogNotFound.Msg("no deleted catalog named " + name)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to restore catalog")
+		return err
+	}
+	return nil
+}
+
 type VariantObject struct {
 	Name       string   `json:"name"`
 	Namespaces []string `json:"namespaces"`
@@ -342,6 +441,10 @@ func (c *catalogKind) Update(ctx context.Context, resourceJSON []byte) apperrors
 	}
 
 	catalog.Description = schema.Metadata.Description
+	catalog.Info = catalogInfo{
+		ObjectInfo:     schema.Metadata.objectInfo(),
+		DefaultVariant: catalogInfoFromJSONB(catalog.Info).DefaultVariant,
+	}.jsonb()
 
 	err = db.DB(ctx).UpdateCatalog(ctx, catalog)
 	if err != nil {
@@ -358,21 +461,36 @@ func (c *catalogKind) List(ctx context.Context) ([]byte, apperrors.Error) {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to list catalogs")
 		return nil, err
 	}
+	if c.req.ListOptions.IncludeDeleted {
+		deleted, err := db.DB(ctx).ListDeletedCatalogs(ctx)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to list deleted catalogs")
+			return nil, err
+		}
+		catalogs = append(catalogs, deleted...)
+	}
 
-	// Extract just the names from the catalogs
-	var names []string
-	for _, catalog := range catalogs {
-		names = append(names, catalog.Name)
+	catalogs = interfaces.FilterByLabels(catalogs, func(c *models.Catalog) map[string]string {
+		return objectInfoFromJSONB(c.Info).Labels
+	}, c.req.ListOptions.LabelSelector)
+	page, next := interfaces.Paginate(catalogs, func(c *models.Catalog) string { return c.Name }, c.req.ListOptions)
+
+	items := make([]interfaces.ListItem, 0, len(page))
+	for _, catalog := range page {
+		items = append(items, interfaces.ListItem{
+			Name:        catalog.Name,
+			Description: catalog.Description,
+			Labels:      objectInfoFromJSONB(catalog.Info).Labels,
+			DeletedAt:   catalog.DeletedAt,
+		})
 	}
 
-	jsonData, goerr := json.Marshal(names)
+	jsonData, goerr := interfaces.MarshalList(catcommon.KindNameCatalogs, items, next)
 	if goerr != nil {
-		log.Ctx(ctx).Error().Err(goerr).Msg("failed to marshal catalog names to JSON")
+		log.Ctx(ctx).Error().Err(goerr).Msg("failed to marshal catalog list to JSON")
 		return nil, ErrUnableToLoadObject.Msg(goerr.Error())
 	}
 
-	log.Ctx(ctx).Info().Msgf("catalog list: %v", names)
-
 	return jsonData, nil
 }
 
@@ -32,6 +32,10 @@ var (
 var (
 	ErrAlreadyExists         apperrors.Error = ErrCatalogError.New("object already exists").SetStatusCode(http.StatusConflict)
 	ErrEqualToExistingObject apperrors.Error = ErrCatalogError.New("object is identical to existing object").SetStatusCode(http.StatusConflict)
+	ErrPreconditionFailed    apperrors.Error = ErrCatalogError.New("precondition failed").SetStatusCode(http.StatusPreconditionFailed)
+	ErrConcurrentWrite       apperrors.Error = ErrCatalogError.New("object was changed by a concurrent write").SetStatusCode(http.StatusConflict)
+	ErrDefaultVariant        apperrors.Error = ErrCatalogError.New("variant is the default variant of the catalog").SetStatusCode(http.StatusConflict)
+	ErrNotEmpty              apperrors.Error = ErrCatalogError.New("object is not empty").SetStatusCode(http.StatusConflict)
 )
 
 // Validation errors
@@ -73,3 +77,9 @@ var (
 	ErrUnauthorizedToCreateView apperrors.Error = ErrAuthError.New("unauthorized to create view").SetStatusCode(http.StatusForbidden)
 	ErrDisallowedByPolicy       apperrors.Error = ErrAuthError.New("not allowed by policy").SetStatusCode(http.StatusForbidden)
 )
+
+// Admission errors
+var (
+	ErrAdmissionDenied        apperrors.Error = ErrCatalogError.New("denied by admission webhook").SetStatusCode(http.StatusBadRequest)
+	ErrAdmissionWebhookFailed apperrors.Error = ErrCatalogError.New("admission webhook failed").SetStatusCode(http.StatusBadGateway)
+)
@@ -0,0 +1,93 @@
+package catalogmanager
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/eventbus"
+)
+
+// Types of object change events.
+const (
+	ObjectEventCreated = "created"
+	ObjectEventUpdated = "updated"
+	ObjectEventDeleted = "deleted"
+)
+
+// objectEventPublishTimeout bounds how long a publisher waits on a slow watcher
+// before the event is dropped for that watcher.
+const objectEventPublishTimeout = 100 * time.Millisecond
+
+var objectEvents = eventbus.New()
+
+// ObjectEvent describes a change to a catalog object.
+type ObjectEvent struct {
+	Type      string    `json:"type"`
+	Kind      string    `json:"kind"`
+	Catalog   string    `json:"catalog"`
+	Variant   string    `json:"variant,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Path      string    `json:"path,omitempty"`
+	Location  string    `json:"location,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// catalogObject returns the object the event is for, identified by its canonical resource URI.
+func (ev ObjectEvent) catalogObject() policy.CatalogObject {
+	catalogURI := "res://catalogs/" + ev.Catalog
+	variantURI := catalogURI + "/variants/" + ev.Variant
+	switch ev.Kind {
+	case catcommon.CatalogKind:
+		return policy.CatalogObject{Kind: catcommon.KindNameCatalogs, Resource: policy.TargetResource(catalogURI)}
+	case catcommon.VariantKind:
+		return policy.CatalogObject{Kind: catcommon.KindNameVariants, Resource: policy.TargetResource(variantURI)}
+	case catcommon.NamespaceKind:
+		return policy.CatalogObject{Kind: catcommon.KindNameNamespaces, Resource: policy.TargetResource(variantURI + "/namespaces/" + ev.Namespace)}
+	case catcommon.ViewKind:
+		return policy.CatalogObject{Kind: catcommon.KindNameViews, Resource: policy.TargetResource(catalogURI + "/views/" + ev.Name)}
+	}
+	kindName := catcommon.KindNameResources
+	if ev.Kind == catcommon.SkillSetKind {
+		kindName = catcommon.KindNameSkillsets
+	}
+	uri := variantURI
+	if ev.Namespace != "" {
+		uri += "/namespaces/" + ev.Namespace
+	}
+	uri += "/" + kindName + path.Join("/", ev.Path, ev.Name)
+	return policy.CatalogObject{Kind: kindName, Resource: policy.TargetResource(uri)}
+}
+
+// CanReadObjectEvent reports whether the view in the context may read the object the event is
+// for, with the conditions of its rules evaluated in the variant and namespace of the object.
+func CanReadObjectEvent(ctx context.Context, ev ObjectEvent) bool {
+	attrs := policy.RequestAttributes(ctx)
+	attrs["variant"] = ev.Variant
+	attrs["namespace"] = ev.Namespace
+	return policy.CanReadObject(ctx, ev.catalogObject(), attrs)
+}
+
+// ObjectEventTopic returns the event bus topic for changes to objects of a kind in a
+// catalog of the tenant in ctx. Use "*" as the kind to match all kinds.
+func ObjectEventTopic(ctx context.Context, catalog, kind string) string {
+	return fmt.Sprintf("objects.%s.%s.%s", catcommon.GetTenantID(ctx), catalog, kind)
+}
+
+// PublishObjectEvent notifies watchers of a change to a catalog object.
+func PublishObjectEvent(ctx context.Context, ev ObjectEvent) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+	objectEvents.Publish(ObjectEventTopic(ctx, ev.Catalog, ev.Kind), ev, objectEventPublishTimeout)
+}
+
+// SubscribeObjectEvents returns a channel of events for the given topic and a
+// function that must be called to unsubscribe.
+func SubscribeObjectEvents(topic string, bufferSize int) (<-chan eventbus.Event, func()) {
+	return objectEvents.Subscribe(topic, bufferSize)
+}
@@ -0,0 +1,86 @@
+package catalogmanager
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+)
+
+func TestObjectEvents(t *testing.T) {
+	ctx := catcommon.WithTenantID(context.Background(), "TEVENTS")
+	assert.Equal(t, "objects.TEVENTS.cat.Resource", ObjectEventTopic(ctx, "cat", catcommon.ResourceKind))
+
+	all, unsubscribeAll := SubscribeObjectEvents(ObjectEventTopic(ctx, "cat", "*"), 10)
+	defer unsubscribeAll()
+	views, unsubscribeViews := SubscribeObjectEvents(ObjectEventTopic(ctx, "cat", catcommon.ViewKind), 10)
+	defer unsubscribeViews()
+
+	PublishObjectEvent(ctx, ObjectEvent{
+		Type:    ObjectEventCreated,
+		Kind:    catcommon.ResourceKind,
+		Catalog: "cat",
+		Name:    "res",
+	})
+
+	select {
+	case event := <-all:
+		ev, ok := event.Data.(ObjectEvent)
+		require.True(t, ok)
+		assert.Equal(t, ObjectEventCreated, ev.Type)
+		assert.Equal(t, "res", ev.Name)
+		assert.False(t, ev.Time.IsZero())
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	select {
+	case event := <-views:
+		t.Fatalf("unexpected event on view topic: %v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Events for other tenants are not delivered
+	other := catcommon.WithTenantID(context.Background(), "TOTHER")
+	PublishObjectEvent(other, ObjectEvent{Type: ObjectEventDeleted, Kind: catcommon.ResourceKind, Catalog: "cat", Name: "res"})
+	select {
+	case event := <-all:
+		t.Fatalf("unexpected event from another tenant: %v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestObjectEventCatalogObject(t *testing.T) {
+	tests := []struct {
+		ev   ObjectEvent
+		want policy.CatalogObject
+	}{
+		{
+			ev:   ObjectEvent{Kind: catcommon.CatalogKind, Catalog: "cat", Name: "cat"},
+			want: policy.CatalogObject{Kind: catcommon.KindNameCatalogs, Resource: "res://catalogs/cat"},
+		},
+		{
+			ev:   ObjectEvent{Kind: catcommon.ViewKind, Catalog: "cat", Variant: "prod", Name: "ops"},
+			want: policy.CatalogObject{Kind: catcommon.KindNameViews, Resource: "res://catalogs/cat/views/ops"},
+		},
+		{
+			ev:   ObjectEvent{Kind: catcommon.NamespaceKind, Catalog: "cat", Variant: "prod", Namespace: "dev", Name: "dev"},
+			want: policy.CatalogObject{Kind: catcommon.KindNameNamespaces, Resource: "res://catalogs/cat/variants/prod/namespaces/dev"},
+		},
+		{
+			ev:   ObjectEvent{Kind: catcommon.ResourceKind, Catalog: "cat", Variant: "prod", Path: "/db", Name: "password"},
+			want: policy.CatalogObject{Kind: catcommon.KindNameResources, Resource: "res://catalogs/cat/variants/prod/resources/db/password"},
+		},
+		{
+			ev:   ObjectEvent{Kind: catcommon.SkillSetKind, Catalog: "cat", Variant: "prod", Namespace: "dev", Path: "/", Name: "k8s"},
+			want: policy.CatalogObject{Kind: catcommon.KindNameSkillsets, Resource: "res://catalogs/cat/variants/prod/namespaces/dev/skillsets/k8s"},
+		},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, tt.ev.catalogObject())
+	}
+}
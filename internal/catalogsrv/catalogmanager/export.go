@@ -0,0 +1,304 @@
+package catalogmanager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tansive/tansive-internal/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// ExportManifestFile is the name of the manifest entry in an export archive.
+const ExportManifestFile = "manifest.json"
+
+// ExportedObject describes a single object of an exported catalog. Hash is the
+// object store hash of resources and skillsets and is empty for other kinds.
+type ExportedObject struct {
+	Kind      string          `json:"kind"`
+	Name      string          `json:"name"`
+	Variant   string          `json:"variant,omitempty"`
+	Namespace string          `json:"namespace,omitempty"`
+	Path      string          `json:"path,omitempty"`
+	Hash      string          `json:"hash,omitempty"`
+	File      string          `json:"file"`
+	JSON      json.RawMessage `json:"-"`
+}
+
+// ExportManifest is the table of contents of an export archive. Objects are listed
+// in the order in which they must be recreated.
+type ExportManifest struct {
+	ApiVersion string           `json:"apiVersion"`
+	Catalog    string           `json:"catalog"`
+	ExportedAt time.Time        `json:"exportedAt"`
+	Objects    []ExportedObject `json:"objects"`
+}
+
+// ExportCatalog collects every object of a catalog: the catalog itself, then for each
+// variant the variant, its namespaces, skillsets and resources, and finally the views,
+// which may be scoped to any of them.
+// Internal views whose labels start with "_" are not exported.
+func ExportCatalog(ctx context.Context, name string) (*ExportManifest, apperrors.Error) {
+	cm, err := LoadCatalogManagerByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	catalogID := cm.ID()
+
+	manifest := &ExportManifest{
+		ApiVersion: catcommon.ApiVersion,
+		Catalog:    name,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	j, err := cm.ToJson(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manifest.Objects = append(manifest.Objects, ExportedObject{
+		Kind: catcommon.CatalogKind,
+		Name: name,
+		File: "catalog.json",
+		JSON: j,
+	})
+
+	variants, err := db.DB(ctx).ListVariantsByCatalog(ctx, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list variants")
+		return nil, ErrUnableToLoadObject.Msg("unable to list variants")
+	}
+	for _, variant := range variants {
+		objects, err := exportVariant(ctx, name, catalogID, variant)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Objects = append(manifest.Objects, objects...)
+	}
+
+	views, err := db.DB(ctx).ListViewsByCatalog(ctx, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list views")
+		return nil, ErrUnableToLoadObject.Msg("unable to list views")
+	}
+	for _, view := range views {
+		if strings.HasPrefix(view.Label, "_") {
+			continue
+		}
+		vh, err := policy.NewViewKindHandler(ctx, interfaces.RequestContext{
+			Catalog:    name,
+			CatalogID:  catalogID,
+			ObjectName: view.Label,
+		})
+		if err != nil {
+			return nil, err
+		}
+		j, err := vh.Get(ctx)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Objects = append(manifest.Objects, ExportedObject{
+			Kind: catcommon.ViewKind,
+			Name: view.Label,
+			File: path.Join("views", view.Label+".json"),
+			JSON: j,
+		})
+	}
+
+	return manifest, nil
+}
+
+func exportVariant(ctx context.Context, catalog string, catalogID uuid.UUID, variant models.VariantSummary) ([]ExportedObject, apperrors.Error) {
+	var objects []ExportedObject
+	variantDir := path.Join("variants", variant.Name)
+
+	vm, err := LoadVariantManager(ctx, catalogID, variant.VariantID, "")
+	if err != nil {
+		return nil, err
+	}
+	j, err := vm.ToJson(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects = append(objects, ExportedObject{
+		Kind: catcommon.VariantKind,
+		Name: variant.Name,
+		File: path.Join(variantDir, "variant.json"),
+		JSON: j,
+	})
+
+	namespaces, err := db.DB(ctx).ListNamespacesByVariant(ctx, variant.VariantID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list namespaces")
+		return nil, ErrUnableToLoadObject.Msg("unable to list namespaces")
+	}
+	namespaceNames := make(map[string]bool, len(namespaces))
+	for _, namespace := range namespaces {
+		nm, err := LoadNamespaceManagerByName(ctx, variant.VariantID, namespace.Name)
+		if err != nil {
+			return nil, err
+		}
+		j, err := nm.ToJson(ctx)
+		if err != nil {
+			return nil, err
+		}
+		namespaceNames[namespace.Name] = true
+		objects = append(objects, ExportedObject{
+			Kind:    catcommon.NamespaceKind,
+			Name:    namespace.Name,
+			Variant: variant.Name,
+			File:    path.Join(variantDir, "namespaces", namespace.Name+".json"),
+			JSON:    j,
+		})
+	}
+
+	skillsets, err := db.DB(ctx).ListSkillSets(ctx, variant.SkillsetDirectoryID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list skillsets")
+		return nil, ErrUnableToLoadObject.Msg("unable to list skillsets")
+	}
+	for _, skillset := range skillsets {
+		m := exportMetadata(catalog, variant.Name, skillset.Path, namespaceNames)
+		sm, err := LoadSkillSetManagerByHash(ctx, skillset.Hash, m)
+		if err != nil {
+			return nil, err
+		}
+		j, err := sm.JSON(ctx)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, exportedObjectFromMetadata(catcommon.SkillSetKind, m, skillset.Hash,
+			path.Join(variantDir, "skillsets", skillset.Path+".json"), j))
+	}
+
+	resources, err := db.DB(ctx).ListResources(ctx, variant.ResourceDirectoryID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list resources")
+		return nil, ErrUnableToLoadObject.Msg("unable to list resources")
+	}
+	for _, resource := range resources {
+		m := exportMetadata(catalog, variant.Name, resource.Path, namespaceNames)
+		rm, err := LoadResourceManagerByHash(ctx, resource.Hash, m)
+		if err != nil {
+			return nil, err
+		}
+		j, err := rm.JSON(ctx)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, exportedObjectFromMetadata(catcommon.ResourceKind, m, resource.Hash,
+			path.Join(variantDir, "resources", resource.Path+".json"), j))
+	}
+
+	return objects, nil
+}
+
+// exportMetadata recovers the metadata of an object from its storage path. Objects in a
+// namespace are stored under /--root--/<namespace>, so a leading path segment naming a
+// namespace of the variant is taken to be the namespace.
+func exportMetadata(catalog, variant, storagePath string, namespaces map[string]bool) *interfaces.Metadata {
+	m := &interfaces.Metadata{
+		Catalog: catalog,
+		Variant: types.NullableStringFrom(variant),
+	}
+	m.SetNameAndPathFromStoragePath(storagePath)
+	segments := strings.SplitN(strings.TrimPrefix(m.Path, "/"), "/", 2)
+	if namespaces[segments[0]] {
+		m.Namespace = types.NullableStringFrom(segments[0])
+		m.Path = "/"
+		if len(segments) > 1 {
+			m.Path += segments[1]
+		}
+	}
+	return m
+}
+
+func exportedObjectFromMetadata(kind string, m *interfaces.Metadata, hash, file string, j []byte) ExportedObject {
+	obj := ExportedObject{
+		Kind:    kind,
+		Name:    m.Name,
+		Variant: m.Variant.String(),
+		Path:    m.Path,
+		Hash:    hash,
+		File:    file,
+		JSON:    j,
+	}
+	if m.Namespace.Valid {
+		obj.Namespace = m.Namespace.String()
+	}
+	return obj
+}
+
+// WriteArchive writes the export as a gzip-compressed tar archive containing the manifest
+// followed by one JSON file per object.
+func (m *ExportManifest) WriteArchive(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	manifestJSON, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, ExportManifestFile, manifestJSON, m.ExportedAt); err != nil {
+		return err
+	}
+	for _, obj := range m.Objects {
+		if err := writeTarFile(tw, obj.File, obj.JSON, m.ExportedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// WriteYAML writes the export as a multi-document YAML stream that can be sent to /apply.
+// Object store hashes are recorded as comments.
+func (m *ExportManifest) WriteYAML(w io.Writer) error {
+	for i, obj := range m.Objects {
+		y, err := yaml.JSONToYAML(obj.JSON)
+		if err != nil {
+			return err
+		}
+		var b strings.Builder
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		if obj.Hash != "" {
+			b.WriteString("# hash: " + obj.Hash + "\n")
+		}
+		b.Write(y)
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	hdr := &tar.Header{
+		Name:    strings.TrimPrefix(name, "/"),
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
@@ -0,0 +1,133 @@
+package catalogmanager
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportMetadata(t *testing.T) {
+	namespaces := map[string]bool{"ns1": true}
+
+	m := exportMetadata("cat", "var", "/--root--/a/b/res", namespaces)
+	assert.Equal(t, "res", m.Name)
+	assert.Equal(t, "/a/b", m.Path)
+	assert.False(t, m.Namespace.Valid)
+	assert.Equal(t, "var", m.Variant.String())
+
+	m = exportMetadata("cat", "var", "/--root--/ns1/a/res", namespaces)
+	assert.Equal(t, "res", m.Name)
+	assert.Equal(t, "/a", m.Path)
+	assert.Equal(t, "ns1", m.Namespace.String())
+
+	m = exportMetadata("cat", "var", "/--root--/ns1/res", namespaces)
+	assert.Equal(t, "/", m.Path)
+	assert.Equal(t, "ns1", m.Namespace.String())
+
+	m = exportMetadata("cat", "var", "/--root--/res", namespaces)
+	assert.Equal(t, "/", m.Path)
+	assert.False(t, m.Namespace.Valid)
+}
+
+func TestExportManifestWriters(t *testing.T) {
+	manifest := &ExportManifest{
+		ApiVersion: "0.1.0-alpha.1",
+		Catalog:    "cat",
+		ExportedAt: time.Now().UTC(),
+		Objects: []ExportedObject{
+			{Kind: "Catalog", Name: "cat", File: "catalog.json", JSON: []byte(`{"kind":"Catalog","metadata":{"name":"cat"}}`)},
+			{Kind: "Resource", Name: "res", Variant: "default", Path: "/", Hash: "abc", File: "variants/default/resources/--root--/res.json",
+				JSON: []byte(`{"kind":"Resource","metadata":{"name":"res"}}`)},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, manifest.WriteArchive(&buf))
+
+	gr, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		data, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		files[hdr.Name] = data
+	}
+	require.Contains(t, files, ExportManifestFile)
+	var got ExportManifest
+	require.NoError(t, json.Unmarshal(files[ExportManifestFile], &got))
+	assert.Equal(t, "cat", got.Catalog)
+	require.Len(t, got.Objects, 2)
+	assert.Equal(t, "abc", got.Objects[1].Hash)
+	for _, obj := range manifest.Objects {
+		assert.JSONEq(t, string(obj.JSON), string(files[obj.File]))
+	}
+
+	buf.Reset()
+	require.NoError(t, manifest.WriteYAML(&buf))
+	assert.Equal(t, "kind: Catalog\nmetadata:\n  name: cat\n---\n# hash: abc\nkind: Resource\nmetadata:\n  name: res\n", buf.String())
+}
+
+func TestReadExportArchive(t *testing.T) {
+	manifest := &ExportManifest{
+		ApiVersion: "0.1.0-alpha.1",
+		Catalog:    "cat",
+		Objects: []ExportedObject{
+			{Kind: "Catalog", Name: "cat", File: "catalog.json", JSON: []byte(`{"kind":"Catalog"}`)},
+			{Kind: "Variant", Name: "v1", File: "variants/v1/variant.json", JSON: []byte(`{"kind":"Variant"}`)},
+		},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, manifest.WriteArchive(&buf))
+
+	got, err := ReadExportArchive(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, "cat", got.Catalog)
+	require.Len(t, got.Objects, 2)
+	assert.Equal(t, `{"kind":"Variant"}`, string(got.Objects[1].JSON))
+	assert.Nil(t, validateManifest(got))
+
+	_, err = ReadExportArchive(bytes.NewReader([]byte("not an archive")))
+	assert.Error(t, err)
+}
+
+func TestValidateManifest(t *testing.T) {
+	catalog := ExportedObject{Kind: "Catalog", Name: "cat", JSON: []byte(`{"metadata":{"name":"cat"}}`)}
+	tests := []struct {
+		name     string
+		manifest *ExportManifest
+		valid    bool
+	}{
+		{"valid", &ExportManifest{ApiVersion: "0.1.0-alpha.1", Catalog: "cat", Objects: []ExportedObject{catalog}}, true},
+		{"missing catalog name", &ExportManifest{ApiVersion: "0.1.0-alpha.1", Objects: []ExportedObject{catalog}}, false},
+		{"wrong version", &ExportManifest{ApiVersion: "0.0.1", Catalog: "cat", Objects: []ExportedObject{catalog}}, false},
+		{"catalog not first", &ExportManifest{ApiVersion: "0.1.0-alpha.1", Catalog: "cat",
+			Objects: []ExportedObject{{Kind: "Variant", Name: "v1"}, catalog}}, false},
+		{"unknown kind", &ExportManifest{ApiVersion: "0.1.0-alpha.1", Catalog: "cat",
+			Objects: []ExportedObject{catalog, {Kind: "Collection", Name: "c"}}}, false},
+		{"foreign object", &ExportManifest{ApiVersion: "0.1.0-alpha.1", Catalog: "cat",
+			Objects: []ExportedObject{catalog, {Kind: "Variant", Name: "v1", JSON: []byte(`{"metadata":{"catalog":"other"}}`)}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateManifest(tt.manifest)
+			if tt.valid {
+				assert.Nil(t, err)
+			} else {
+				assert.NotNil(t, err)
+			}
+		})
+	}
+}
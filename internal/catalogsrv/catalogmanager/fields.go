@@ -0,0 +1,56 @@
+package catalogmanager
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// fieldPathRegex matches dot-separated field paths such as metadata.name or spec.values.
+var fieldPathRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// ParseFieldList parses a comma-separated list of field paths as accepted by the
+// fields query parameter. Empty entries are ignored.
+func ParseFieldList(s string) ([]string, apperrors.Error) {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !fieldPathRegex.MatchString(f) {
+			return nil, ErrInvalidRequest.Msg("invalid field: " + f)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// SelectFields returns a copy of objJSON containing only the given fields, keeping their
+// position in the object hierarchy. Fields that are not present are omitted. If no fields
+// are given, objJSON is returned unchanged.
+func SelectFields(objJSON []byte, fields []string) ([]byte, apperrors.Error) {
+	if len(fields) == 0 {
+		return objJSON, nil
+	}
+	if !gjson.ValidBytes(objJSON) {
+		return nil, ErrCatalogError.Msg("unable to parse object")
+	}
+
+	out := []byte("{}")
+	for _, f := range fields {
+		v := gjson.GetBytes(objJSON, f)
+		if !v.Exists() {
+			continue
+		}
+		var err error
+		out, err = sjson.SetRawBytes(out, f, []byte(v.Raw))
+		if err != nil {
+			return nil, ErrCatalogError.Msg("unable to select field " + f)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,42 @@
+package catalogmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFieldList(t *testing.T) {
+	fields, err := ParseFieldList("metadata.name, spec.values,,")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"metadata.name", "spec.values"}, fields)
+
+	fields, err = ParseFieldList("")
+	require.Nil(t, err)
+	assert.Empty(t, fields)
+
+	for _, invalid := range []string{"metadata..name", ".metadata", "spec.*", "spec|@reverse"} {
+		_, err = ParseFieldList(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	obj := []byte(`{"version":"v1","kind":"Resource","metadata":{"name":"res","catalog":"cat","path":"/a"},"spec":{"schema":{"type":"string"},"value":"x"}}`)
+
+	got, err := SelectFields(obj, []string{"metadata.name", "spec.value", "spec.missing"})
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"metadata":{"name":"res"},"spec":{"value":"x"}}`, string(got))
+
+	got, err = SelectFields(obj, []string{"kind", "spec.schema"})
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"kind":"Resource","spec":{"schema":{"type":"string"}}}`, string(got))
+
+	got, err = SelectFields(obj, nil)
+	require.Nil(t, err)
+	assert.Equal(t, obj, got)
+
+	_, err = SelectFields([]byte("not json"), []string{"kind"})
+	assert.NotNil(t, err)
+}
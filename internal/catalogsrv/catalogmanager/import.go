@@ -0,0 +1,401 @@
+package catalogmanager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tidwall/gjson"
+)
+
+// Conflict policies for objects of an import that already exist with different content.
+const (
+	ImportConflictFail      = "fail"
+	ImportConflictSkip      = "skip"
+	ImportConflictOverwrite = "overwrite"
+)
+
+// Actions reported for each object of an import.
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionSkip      = "skip"
+	ImportActionUnchanged = "unchanged"
+	ImportActionConflict  = "conflict"
+)
+
+// ImportOptions controls how an import treats existing objects.
+type ImportOptions struct {
+	OnConflict string
+	DryRun     bool
+}
+
+// ImportResult reports the planned or performed action for a single object.
+type ImportResult struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Variant   string `json:"variant,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Action    string `json:"action"`
+	Error     string `json:"error,omitempty"`
+}
+
+// ImportReport is the outcome of an import. With DryRun set, no changes were made and
+// the results describe what an import would do.
+type ImportReport struct {
+	Catalog string         `json:"catalog"`
+	DryRun  bool           `json:"dryRun"`
+	Results []ImportResult `json:"results"`
+}
+
+// ReadExportArchive reads a tar.gz archive written by ExportManifest.WriteArchive and
+// returns its manifest with the JSON of every object loaded.
+func ReadExportArchive(r io.Reader) (*ExportManifest, apperrors.Error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, ErrInvalidRequest.Msg("archive is not gzip compressed")
+	}
+	defer gr.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidRequest.Msg("unable to read archive: " + err.Error())
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, ErrInvalidRequest.Msg("unable to read archive: " + err.Error())
+		}
+		files[path.Clean(hdr.Name)] = data
+	}
+
+	manifestJSON, ok := files[ExportManifestFile]
+	if !ok {
+		return nil, ErrInvalidRequest.Msg("archive does not contain " + ExportManifestFile)
+	}
+	manifest := &ExportManifest{}
+	if err := json.Unmarshal(manifestJSON, manifest); err != nil {
+		return nil, ErrInvalidRequest.Msg("unable to parse " + ExportManifestFile)
+	}
+	for i := range manifest.Objects {
+		data, ok := files[path.Clean(manifest.Objects[i].File)]
+		if !ok {
+			return nil, ErrInvalidRequest.Msg("archive does not contain " + manifest.Objects[i].File)
+		}
+		manifest.Objects[i].JSON = data
+	}
+	return manifest, nil
+}
+
+// ImportCatalog recreates a catalog from an export manifest. The import is planned
+// before anything is written: objects that do not exist are created, objects with
+// identical content are left unchanged, and objects that differ are skipped, overwritten
+// or reported as conflicts according to opts.OnConflict. Any conflict aborts the import
+// before changes are made.
+//
+// Like creating a catalog, importing a new catalog is open to any user. Importing into an
+// existing catalog requires admin rights on it, and the caller must be allowed to write
+// every object the import creates or updates.
+func ImportCatalog(ctx context.Context, manifest *ExportManifest, opts ImportOptions) (*ImportReport, apperrors.Error) {
+	if opts.OnConflict == "" {
+		opts.OnConflict = ImportConflictFail
+	}
+	if !slices.Contains([]string{ImportConflictFail, ImportConflictSkip, ImportConflictOverwrite}, opts.OnConflict) {
+		return nil, ErrInvalidRequest.Msg("invalid conflict policy: " + opts.OnConflict)
+	}
+	if err := validateManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Catalog: manifest.Catalog,
+		DryRun:  opts.DryRun,
+		Results: make([]ImportResult, 0, len(manifest.Objects)),
+	}
+
+	catalogID, err := db.DB(ctx).GetCatalogIDByName(ctx, manifest.Catalog)
+	if err != nil && !errors.Is(err, dberror.ErrNotFound) {
+		return nil, err
+	}
+	catalogExists := err == nil
+	if catalogExists {
+		ctx = importCatalogContext(ctx, manifest.Catalog, catalogID)
+		if !policy.CanWriteObject(ctx, importedObject(manifest.Catalog, manifest.Objects[0])) {
+			return nil, ErrDisallowedByPolicy.Msg("not allowed to import into catalog " + manifest.Catalog)
+		}
+	}
+
+	var conflicts int
+	for _, obj := range manifest.Objects {
+		action := ImportActionCreate
+		if catalogExists {
+			action, err = planImport(ctx, manifest.Catalog, catalogID, obj, opts.OnConflict)
+			if err != nil {
+				return nil, err
+			}
+			if (action == ImportActionCreate || action == ImportActionUpdate) &&
+				!policy.CanWriteObject(ctx, importedObject(manifest.Catalog, obj)) {
+				return nil, ErrDisallowedByPolicy.Msg("not allowed to import " + obj.Kind + " " + obj.Name)
+			}
+		}
+		if action == ImportActionConflict {
+			conflicts++
+		}
+		report.Results = append(report.Results, importResult(obj, action))
+	}
+
+	if conflicts > 0 {
+		return report, ErrAlreadyExists.Msg("import conflicts with existing objects")
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for i, obj := range manifest.Objects {
+		result := &report.Results[i]
+		if result.Action != ImportActionCreate && result.Action != ImportActionUpdate {
+			continue
+		}
+		if obj.Kind == catcommon.CatalogKind && result.Action == ImportActionCreate {
+			if err := importObject(ctx, manifest.Catalog, uuid.Nil, obj, result); err != nil {
+				return report, err
+			}
+			catalogID, err = db.DB(ctx).GetCatalogIDByName(ctx, manifest.Catalog)
+			if err != nil {
+				return report, err
+			}
+			ctx = importCatalogContext(ctx, manifest.Catalog, catalogID)
+			continue
+		}
+		if err := importObject(ctx, manifest.Catalog, catalogID, obj, result); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// importCatalogContext returns a context addressing the catalog being imported on behalf of
+// the caller of the import.
+func importCatalogContext(ctx context.Context, catalog string, catalogID uuid.UUID) context.Context {
+	catalogContext := &catcommon.CatalogContext{
+		Catalog:   catalog,
+		CatalogID: catalogID,
+	}
+	if caller := catcommon.GetCatalogContext(ctx); caller != nil {
+		catalogContext.ViewID = caller.ViewID
+		catalogContext.UserContext = caller.UserContext
+		catalogContext.SessionContext = caller.SessionContext
+		catalogContext.ServiceContext = caller.ServiceContext
+		catalogContext.Subject = caller.Subject
+	}
+	return catcommon.WithCatalogContext(ctx, catalogContext)
+}
+
+// importedObject returns the catalog object that importing obj writes, against which the
+// write policy is checked.
+func importedObject(catalog string, obj ExportedObject) policy.CatalogObject {
+	catalogURI := "res://catalogs/" + catalog
+	variantURI := catalogURI + "/variants/" + obj.Variant
+	var kindName, uri string
+	switch obj.Kind {
+	case catcommon.CatalogKind:
+		kindName, uri = catcommon.KindNameCatalogs, catalogURI
+	case catcommon.VariantKind:
+		kindName, uri = catcommon.KindNameVariants, catalogURI+"/variants/"+obj.Name
+	case catcommon.NamespaceKind:
+		kindName, uri = catcommon.KindNameNamespaces, variantURI+"/namespaces/"+obj.Name
+	case catcommon.ViewKind:
+		kindName, uri = catcommon.KindNameViews, catalogURI+"/views/"+obj.Name
+	case catcommon.ViewTemplateKind:
+		kindName, uri = catcommon.KindNameViewTemplates, catalogURI+"/viewtemplates/"+obj.Name
+	case catcommon.AdmissionWebhookKind:
+		kindName, uri = catcommon.KindNameAdmissionWebhooks, catalogURI+"/admissionwebhooks/"+obj.Name
+	default:
+		kindName = catcommon.KindNameResources
+		if obj.Kind == catcommon.SkillSetKind {
+			kindName = catcommon.KindNameSkillsets
+		}
+		uri = variantURI
+		if obj.Namespace != "" {
+			uri += "/namespaces/" + obj.Namespace
+		}
+		uri += "/" + kindName + path.Join("/", obj.Path, obj.Name)
+	}
+	return policy.CatalogObject{Kind: kindName, Resource: policy.TargetResource(uri)}
+}
+
+func validateManifest(manifest *ExportManifest) apperrors.Error {
+	if manifest == nil || manifest.Catalog == "" {
+		return ErrInvalidRequest.Msg("manifest does not name a catalog")
+	}
+	if manifest.ApiVersion != catcommon.ApiVersion {
+		return ErrInvalidRequest.Msg("unsupported manifest version: " + manifest.ApiVersion)
+	}
+	if len(manifest.Objects) == 0 || manifest.Objects[0].Kind != catcommon.CatalogKind {
+		return ErrInvalidRequest.Msg("manifest must start with the catalog")
+	}
+	for _, obj := range manifest.Objects {
+		if _, ok := kindHandlerFactories[obj.Kind]; !ok {
+			return ErrInvalidRequest.Msg("unsupported kind in manifest: " + obj.Kind)
+		}
+		if catalog := gjson.GetBytes(obj.JSON, "metadata.catalog").String(); catalog != "" && catalog != manifest.Catalog {
+			return ErrInvalidRequest.Msg("object " + obj.Name + " belongs to catalog " + catalog)
+		}
+	}
+	return nil
+}
+
+// planImport decides what to do with an object given the current state of the catalog.
+func planImport(ctx context.Context, catalog string, catalogID uuid.UUID, obj ExportedObject, onConflict string) (string, apperrors.Error) {
+	reqContext, err := importRequestContext(ctx, catalog, catalogID, obj)
+	if err != nil {
+		return "", err
+	}
+	// A variant that does not exist yet has nothing in it
+	if reqContext.VariantID == uuid.Nil && obj.Kind != catcommon.CatalogKind && obj.Kind != catcommon.ViewKind {
+		return ImportActionCreate, nil
+	}
+
+	h, err := ResourceManagerForKind(ctx, obj.Kind, reqContext)
+	if err != nil {
+		return "", err
+	}
+	current, err := h.Get(ctx)
+	if err != nil {
+		if isNotFound(err) {
+			return ImportActionCreate, nil
+		}
+		return "", err
+	}
+	if ETag(current) == ETag(obj.JSON) {
+		return ImportActionUnchanged, nil
+	}
+
+	switch onConflict {
+	case ImportConflictSkip:
+		return ImportActionSkip, nil
+	case ImportConflictOverwrite:
+		return ImportActionUpdate, nil
+	default:
+		return ImportActionConflict, nil
+	}
+}
+
+// importObject creates or updates a single object. Objects planned for creation may
+// already exist when they are created implicitly by their parent, such as the default
+// variant of a new catalog, in which case they are updated instead.
+func importObject(ctx context.Context, catalog string, catalogID uuid.UUID, obj ExportedObject, result *ImportResult) apperrors.Error {
+	reqContext, err := importRequestContext(ctx, catalog, catalogID, obj)
+	if err != nil {
+		result.Error = err.Error()
+		return err
+	}
+
+	if result.Action == ImportActionCreate {
+		h, err := ResourceManagerForKind(ctx, obj.Kind, reqContext)
+		if err != nil {
+			result.Error = err.Error()
+			return err
+		}
+		_, err = h.Create(ctx, obj.JSON)
+		if err == nil {
+			return nil
+		}
+		if err.StatusCode() != http.StatusConflict {
+			log.Ctx(ctx).Error().Err(err).Str("kind", obj.Kind).Str("name", obj.Name).Msg("failed to import object")
+			result.Error = err.Error()
+			return err
+		}
+		result.Action = ImportActionUpdate
+	}
+
+	h, err := ResourceManagerForKind(ctx, obj.Kind, reqContext)
+	if err != nil {
+		result.Error = err.Error()
+		return err
+	}
+	if err := h.Update(ctx, obj.JSON); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("kind", obj.Kind).Str("name", obj.Name).Msg("failed to import object")
+		result.Error = err.Error()
+		return err
+	}
+	return nil
+}
+
+// importRequestContext builds the request context addressing obj within the catalog.
+func importRequestContext(ctx context.Context, catalog string, catalogID uuid.UUID, obj ExportedObject) (interfaces.RequestContext, apperrors.Error) {
+	reqContext := interfaces.RequestContext{
+		Catalog:   catalog,
+		CatalogID: catalogID,
+		Variant:   obj.Variant,
+		Namespace: obj.Namespace,
+	}
+
+	switch obj.Kind {
+	case catcommon.CatalogKind:
+		return reqContext, nil
+	case catcommon.VariantKind:
+		reqContext.Variant = obj.Name
+	case catcommon.NamespaceKind:
+		reqContext.Namespace = obj.Name
+	case catcommon.ViewKind:
+		reqContext.ObjectName = obj.Name
+		reqContext.Variant = gjson.GetBytes(obj.JSON, "metadata.variant").String()
+		reqContext.Namespace = gjson.GetBytes(obj.JSON, "metadata.namespace").String()
+	case catcommon.ResourceKind:
+		reqContext.ObjectName = obj.Name
+		reqContext.ObjectPath = obj.Path
+		reqContext.ObjectType = catcommon.CatalogObjectTypeResource
+		reqContext.ObjectProperty = catcommon.ResourcePropertyDefinition
+	case catcommon.SkillSetKind:
+		reqContext.ObjectName = obj.Name
+		reqContext.ObjectPath = obj.Path
+		reqContext.ObjectType = catcommon.CatalogObjectTypeSkillset
+	}
+
+	if reqContext.Variant != "" && catalogID != uuid.Nil {
+		variant, err := db.DB(ctx).GetVariant(ctx, catalogID, uuid.Nil, reqContext.Variant)
+		if err != nil && !errors.Is(err, dberror.ErrNotFound) {
+			return reqContext, err
+		}
+		if err == nil {
+			reqContext.VariantID = variant.VariantID
+		}
+	}
+	return reqContext, nil
+}
+
+func importResult(obj ExportedObject, action string) ImportResult {
+	return ImportResult{
+		Kind:      obj.Kind,
+		Name:      obj.Name,
+		Variant:   obj.Variant,
+		Namespace: obj.Namespace,
+		Path:      obj.Path,
+		Action:    action,
+	}
+}
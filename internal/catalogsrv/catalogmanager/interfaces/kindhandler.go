@@ -18,6 +18,19 @@ type KindHandler interface {
 	Location() string
 }
 
+// Warner is implemented by kind handlers that have warnings for the user about the objects
+// they served, such as deprecations. They are returned in Warning headers.
+type Warner interface {
+	Warnings() []string
+}
+
+// UnredactedGetter is implemented by kind handlers that redact secrets from the objects they
+// serve. GetUnredacted returns the object with its secrets, for callers that do not return it
+// to the user, such as a merge patch.
+type UnredactedGetter interface {
+	GetUnredacted(ctx context.Context) ([]byte, apperrors.Error)
+}
+
 type RequestContext struct {
 	Catalog        string
 	CatalogID      uuid.UUID
@@ -29,6 +42,7 @@ type RequestContext struct {
 	ObjectPath     string
 	ObjectProperty string
 	QueryParams    url.Values
+	ListOptions    ListOptions
 }
 
 type KindHandlerFactory func(context.Context, RequestContext) (KindHandler, apperrors.Error)
@@ -0,0 +1,148 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/schema/schemavalidator"
+)
+
+var errInvalidLabelSelector = errors.New("invalid label selector")
+
+// Operators supported in label selector requirements.
+const (
+	selectorOpEquals       = "="
+	selectorOpNotEquals    = "!="
+	selectorOpExists       = "exists"
+	selectorOpDoesNotExist = "!exists"
+)
+
+// LabelRequirement is a single condition of a label selector.
+type LabelRequirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// LabelSelector is a comma-separated list of requirements that must all hold, e.g.
+// env=prod,team!=infra. Supported requirements are key=value, key==value, key!=value,
+// key (the label is present) and !key (the label is absent).
+type LabelSelector []LabelRequirement
+
+// ParseLabelSelector parses a label selector. An empty string selects everything.
+func ParseLabelSelector(s string) (LabelSelector, error) {
+	var selector LabelSelector
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var r LabelRequirement
+		switch {
+		case strings.Contains(term, "!="):
+			r.Key, r.Value, _ = strings.Cut(term, "!=")
+			r.Operator = selectorOpNotEquals
+		case strings.Contains(term, "=="):
+			r.Key, r.Value, _ = strings.Cut(term, "==")
+			r.Operator = selectorOpEquals
+		case strings.Contains(term, "="):
+			r.Key, r.Value, _ = strings.Cut(term, "=")
+			r.Operator = selectorOpEquals
+		case strings.HasPrefix(term, "!"):
+			r.Key = strings.TrimPrefix(term, "!")
+			r.Operator = selectorOpDoesNotExist
+		default:
+			r.Key = term
+			r.Operator = selectorOpExists
+		}
+
+		r.Key = strings.TrimSpace(r.Key)
+		r.Value = strings.TrimSpace(r.Value)
+		if !schemavalidator.ValidateLabelKey(r.Key) || !schemavalidator.ValidateLabelValue(r.Value) {
+			return nil, errInvalidLabelSelector
+		}
+		selector = append(selector, r)
+	}
+	return selector, nil
+}
+
+// Empty reports whether the selector has no requirements and therefore matches everything.
+func (s LabelSelector) Empty() bool {
+	return len(s) == 0
+}
+
+// Matches reports whether labels satisfy all requirements of the selector.
+func (s LabelSelector) Matches(labels map[string]string) bool {
+	for _, r := range s {
+		v, ok := labels[r.Key]
+		switch r.Operator {
+		case selectorOpEquals:
+			if !ok || v != r.Value {
+				return false
+			}
+		case selectorOpNotEquals:
+			if ok && v == r.Value {
+				return false
+			}
+		case selectorOpExists:
+			if !ok {
+				return false
+			}
+		case selectorOpDoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// FilterByLabels returns the items whose labels match the selector.
+func FilterByLabels[T any](items []T, labels func(T) map[string]string, s LabelSelector) []T {
+	if s.Empty() {
+		return items
+	}
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if s.Matches(labels(item)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// ObjectInfo holds the labels and annotations of kinds whose metadata is stored in the
+// info column of their table, such as catalogs, variants, namespaces and views.
+type ObjectInfo struct {
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Empty reports whether there is nothing to store.
+func (i ObjectInfo) Empty() bool {
+	return len(i.Labels) == 0 && len(i.Annotations) == 0
+}
+
+// Marshal returns the JSON to store in the info column, or nil when there is nothing to store.
+func (i ObjectInfo) Marshal() []byte {
+	if i.Empty() {
+		return nil
+	}
+	b, err := json.Marshal(i)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// ObjectInfoFromJSON parses the info column of an object. Missing or malformed info yields
+// an empty ObjectInfo.
+func ObjectInfoFromJSON(b []byte) ObjectInfo {
+	var info ObjectInfo
+	if len(b) > 0 {
+		_ = json.Unmarshal(b, &info)
+	}
+	return info
+}
@@ -0,0 +1,135 @@
+package interfaces
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLabelSelector(t *testing.T) {
+	tests := []struct {
+		name        string
+		selector    string
+		want        LabelSelector
+		expectError bool
+	}{
+		{
+			name:     "empty",
+			selector: "",
+		},
+		{
+			name:     "equality and inequality",
+			selector: "env=prod, team!=infra",
+			want: LabelSelector{
+				{Key: "env", Operator: selectorOpEquals, Value: "prod"},
+				{Key: "team", Operator: selectorOpNotEquals, Value: "infra"},
+			},
+		},
+		{
+			name:     "double equals",
+			selector: "env==prod",
+			want:     LabelSelector{{Key: "env", Operator: selectorOpEquals, Value: "prod"}},
+		},
+		{
+			name:     "existence",
+			selector: "tansive.io/owner,!deprecated",
+			want: LabelSelector{
+				{Key: "tansive.io/owner", Operator: selectorOpExists},
+				{Key: "deprecated", Operator: selectorOpDoesNotExist},
+			},
+		},
+		{
+			name:     "empty value",
+			selector: "env=",
+			want:     LabelSelector{{Key: "env", Operator: selectorOpEquals, Value: ""}},
+		},
+		{
+			name:        "invalid key",
+			selector:    "bad key=prod",
+			expectError: true,
+		},
+		{
+			name:        "invalid value",
+			selector:    "env=prod/us",
+			expectError: true,
+		},
+		{
+			name:        "missing key",
+			selector:    "=prod",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLabelSelector(tt.selector)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLabelSelectorMatches(t *testing.T) {
+	labels := map[string]string{"env": "prod", "team": "payments"}
+
+	tests := []struct {
+		selector string
+		want     bool
+	}{
+		{"", true},
+		{"env=prod", true},
+		{"env=dev", false},
+		{"env=prod,team!=infra", true},
+		{"env=prod,team!=payments", false},
+		{"region!=us", true},
+		{"team", true},
+		{"region", false},
+		{"!region", true},
+		{"!env", false},
+	}
+
+	for _, tt := range tests {
+		s, err := ParseLabelSelector(tt.selector)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, s.Matches(labels), tt.selector)
+	}
+
+	s, err := ParseLabelSelector("env=prod")
+	require.NoError(t, err)
+	assert.False(t, s.Matches(nil))
+}
+
+func TestFilterByLabels(t *testing.T) {
+	items := []map[string]string{
+		{"env": "prod"},
+		{"env": "dev"},
+		nil,
+	}
+	identity := func(m map[string]string) map[string]string { return m }
+
+	assert.Len(t, FilterByLabels(items, identity, nil), 3)
+
+	s, err := ParseLabelSelector("env!=dev")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]string{{"env": "prod"}, nil}, FilterByLabels(items, identity, s))
+}
+
+func TestObjectInfo(t *testing.T) {
+	assert.Nil(t, ObjectInfo{}.Marshal())
+
+	info := ObjectInfo{
+		Labels:      map[string]string{"env": "prod"},
+		Annotations: map[string]string{"tansive.io/note": "free form text"},
+	}
+	b := info.Marshal()
+	assert.JSONEq(t, `{"labels":{"env":"prod"},"annotations":{"tansive.io/note":"free form text"}}`, string(b))
+	assert.Equal(t, info, ObjectInfoFromJSON(b))
+
+	assert.Equal(t, ObjectInfo{}, ObjectInfoFromJSON(nil))
+	assert.Equal(t, ObjectInfo{}, ObjectInfoFromJSON([]byte("not json")))
+}
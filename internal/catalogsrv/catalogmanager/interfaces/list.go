@@ -0,0 +1,140 @@
+package interfaces
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultListLimit is the page size used when a list request does not specify one.
+	DefaultListLimit = 100
+	// MaxListLimit is the largest page size a client may request.
+	MaxListLimit = 1000
+)
+
+var (
+	errInvalidListLimit   = errors.New("invalid limit: must be a positive integer")
+	errInvalidListCursor  = errors.New("invalid cursor")
+	errInvalidListDeleted = errors.New("invalid includeDeleted: must be true or false")
+)
+
+// ListOptions holds the pagination and filtering parameters of a list request.
+// Cursor is the sort key of the last item returned in the previous page. IncludeDeleted
+// adds soft deleted objects to the list and is ignored by kinds that are deleted permanently.
+type ListOptions struct {
+	Limit          int
+	Cursor         string
+	LabelSelector  LabelSelector
+	IncludeDeleted bool
+}
+
+// ListOptionsFromQuery parses the limit, cursor, labelSelector and includeDeleted query parameters.
+// A missing limit defaults to DefaultListLimit and limits above MaxListLimit are capped.
+func ListOptionsFromQuery(q url.Values) (ListOptions, error) {
+	opts := ListOptions{Limit: DefaultListLimit}
+	if l := q.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			return opts, errInvalidListLimit
+		}
+		opts.Limit = min(limit, MaxListLimit)
+	}
+	if c := q.Get("cursor"); c != "" {
+		key, err := decodeCursor(c)
+		if err != nil {
+			return opts, errInvalidListCursor
+		}
+		opts.Cursor = key
+	}
+	if ls := q.Get("labelSelector"); ls != "" {
+		selector, err := ParseLabelSelector(ls)
+		if err != nil {
+			return opts, err
+		}
+		opts.LabelSelector = selector
+	}
+	if d := q.Get("includeDeleted"); d != "" {
+		includeDeleted, err := strconv.ParseBool(d)
+		if err != nil {
+			return opts, errInvalidListDeleted
+		}
+		opts.IncludeDeleted = includeDeleted
+	}
+	return opts, nil
+}
+
+// Paginate sorts items by key and returns the page following opts.Cursor along with
+// the cursor for the next page. The returned cursor is empty on the last page.
+//
+// Pagination happens in memory: list handlers load every object of the kind in scope,
+// filter them by label selector and visibility, and then take the page. Limit and cursor
+// bound the size of the response, not the work done by the server or the database.
+// Resources and skillsets are stored as a single directory per variant, and labels and
+// view visibility are evaluated outside the database, so the queries cannot be paged.
+func Paginate[T any](items []T, key func(T) string, opts ListOptions) ([]T, string) {
+	sorted := make([]T, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return key(sorted[i]) < key(sorted[j])
+	})
+
+	start := 0
+	if opts.Cursor != "" {
+		start = sort.Search(len(sorted), func(i int) bool {
+			return key(sorted[i]) > opts.Cursor
+		})
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	end := min(start+limit, len(sorted))
+
+	page := sorted[start:end]
+	var next string
+	if end < len(sorted) && len(page) > 0 {
+		next = EncodeCursor(key(page[len(page)-1]))
+	}
+	return page, next
+}
+
+// ListItem is the summary entry returned for metadata-only kinds such as catalogs and variants.
+// DeletedAt is set for soft deleted objects.
+type ListItem struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	DeletedAt   *time.Time        `json:"deletedAt,omitempty"`
+}
+
+// MarshalList builds the list envelope shared by all kinds. Items are keyed by the
+// plural kind name, e.g. {"variants": [...], "nextCursor": "..."}.
+func MarshalList(kindName string, items any, next string) ([]byte, error) {
+	rsp := map[string]any{
+		kindName: items,
+	}
+	if next != "" {
+		rsp["nextCursor"] = next
+	}
+	return json.Marshal(rsp)
+}
+
+// EncodeCursor returns the opaque cursor for a sort key, for lists that are paginated
+// by the database rather than by Paginate.
+func EncodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
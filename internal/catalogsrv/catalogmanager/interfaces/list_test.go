@@ -0,0 +1,140 @@
+package interfaces
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListOptionsFromQuery(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              url.Values
+		wantLimit          int
+		wantCursor         string
+		wantSelector       LabelSelector
+		wantIncludeDeleted bool
+		expectError        bool
+	}{
+		{
+			name:      "defaults",
+			query:     url.Values{},
+			wantLimit: DefaultListLimit,
+		},
+		{
+			name:      "explicit limit",
+			query:     url.Values{"limit": {"25"}},
+			wantLimit: 25,
+		},
+		{
+			name:      "limit is capped",
+			query:     url.Values{"limit": {"100000"}},
+			wantLimit: MaxListLimit,
+		},
+		{
+			name:        "zero limit",
+			query:       url.Values{"limit": {"0"}},
+			expectError: true,
+		},
+		{
+			name:        "non numeric limit",
+			query:       url.Values{"limit": {"ten"}},
+			expectError: true,
+		},
+		{
+			name:       "cursor",
+			query:      url.Values{"cursor": {EncodeCursor("/a/b")}},
+			wantLimit:  DefaultListLimit,
+			wantCursor: "/a/b",
+		},
+		{
+			name:        "malformed cursor",
+			query:       url.Values{"cursor": {"%%%"}},
+			expectError: true,
+		},
+		{
+			name:      "label selector",
+			query:     url.Values{"labelSelector": {"env=prod"}},
+			wantLimit: DefaultListLimit,
+			wantSelector: LabelSelector{
+				{Key: "env", Operator: selectorOpEquals, Value: "prod"},
+			},
+		},
+		{
+			name:        "malformed label selector",
+			query:       url.Values{"labelSelector": {"env=prod us"}},
+			expectError: true,
+		},
+		{
+			name:               "include deleted",
+			query:              url.Values{"includeDeleted": {"true"}},
+			wantLimit:          DefaultListLimit,
+			wantIncludeDeleted: true,
+		},
+		{
+			name:        "malformed include deleted",
+			query:       url.Values{"includeDeleted": {"yes please"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := ListOptionsFromQuery(tt.query)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLimit, opts.Limit)
+			assert.Equal(t, tt.wantCursor, opts.Cursor)
+			assert.Equal(t, tt.wantSelector, opts.LabelSelector)
+			assert.Equal(t, tt.wantIncludeDeleted, opts.IncludeDeleted)
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	items := []string{"delta", "alpha", "echo", "charlie", "bravo"}
+	identity := func(s string) string { return s }
+
+	page, next := Paginate(items, identity, ListOptions{Limit: 2})
+	assert.Equal(t, []string{"alpha", "bravo"}, page)
+	require.NotEmpty(t, next)
+
+	cursor, err := decodeCursor(next)
+	require.NoError(t, err)
+	page, next = Paginate(items, identity, ListOptions{Limit: 2, Cursor: cursor})
+	assert.Equal(t, []string{"charlie", "delta"}, page)
+	require.NotEmpty(t, next)
+
+	cursor, err = decodeCursor(next)
+	require.NoError(t, err)
+	page, next = Paginate(items, identity, ListOptions{Limit: 2, Cursor: cursor})
+	assert.Equal(t, []string{"echo"}, page)
+	assert.Empty(t, next)
+
+	// The input slice must not be reordered
+	assert.Equal(t, []string{"delta", "alpha", "echo", "charlie", "bravo"}, items)
+
+	// A cursor past the end yields an empty page
+	page, next = Paginate(items, identity, ListOptions{Limit: 2, Cursor: "zulu"})
+	assert.Empty(t, page)
+	assert.Empty(t, next)
+}
+
+func TestMarshalList(t *testing.T) {
+	j, err := MarshalList("variants", []ListItem{{Name: "default", Description: "default variant"}}, "")
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"variants":[{"name":"default","description":"default variant"}]}`, string(j))
+
+	j, err = MarshalList("variants", []ListItem{}, "abc")
+	require.NoError(t, err)
+	var rsp map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(j, &rsp))
+	assert.JSONEq(t, `[]`, string(rsp["variants"]))
+	assert.JSONEq(t, `"abc"`, string(rsp["nextCursor"]))
+}
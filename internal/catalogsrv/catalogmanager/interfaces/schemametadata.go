@@ -24,6 +24,9 @@ type Metadata struct {
 	Namespace   types.NullableString `json:"namespace,omitempty" validate:"omitempty,resourceNameValidator"`
 	Path        string               `json:"path,omitempty" validate:"omitempty,resourcePathValidator"`
 	Description string               `json:"description"`
+	Labels      map[string]string    `json:"labels,omitempty" validate:"omitempty,labelsValidator"`
+	Annotations map[string]string    `json:"annotations,omitempty" validate:"omitempty,annotationsValidator"`
+	Deprecated  bool                 `json:"deprecated,omitempty"` // Only stored for resources
 	IDS         IDS                  `json:"-"`
 }
 
@@ -60,6 +63,10 @@ func (rs *Metadata) Validate() schemaerr.ValidationErrors {
 			ves = append(ves, schemaerr.ErrInvalidNameFormat(jsonFieldName, val))
 		case "resourcePathValidator":
 			ves = append(ves, schemaerr.ErrInvalidObjectPath(jsonFieldName))
+		case "labelsValidator":
+			ves = append(ves, schemaerr.ErrInvalidLabel(jsonFieldName))
+		case "annotationsValidator":
+			ves = append(ves, schemaerr.ErrInvalidAnnotation(jsonFieldName))
 		default:
 			ves = append(ves, schemaerr.ErrValidationFailed(jsonFieldName))
 		}
@@ -83,6 +90,15 @@ func (s Metadata) MarshalJSON() ([]byte, error) {
 	if s.Path != "" {
 		m["path"] = s.Path
 	}
+	if len(s.Labels) > 0 {
+		m["labels"] = s.Labels
+	}
+	if len(s.Annotations) > 0 {
+		m["annotations"] = s.Annotations
+	}
+	if s.Deprecated {
+		m["deprecated"] = true
+	}
 
 	return json.Marshal(m)
 }
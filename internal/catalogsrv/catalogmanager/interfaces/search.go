@@ -0,0 +1,79 @@
+package interfaces
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+)
+
+const (
+	// DefaultSearchLimit is the number of hits returned when a search request does not specify one.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit is the largest number of hits a client may request.
+	MaxSearchLimit = 100
+	// MaxSearchTerms is the largest number of terms in a search query.
+	MaxSearchTerms = 8
+)
+
+var (
+	errMissingSearchQuery = errors.New("invalid q: must contain at least one word")
+	errTooManySearchTerms = errors.New("invalid q: too many words")
+	errInvalidSearchLimit = errors.New("invalid limit: must be a positive integer")
+	errInvalidSearchKind  = errors.New("invalid kind: must be one of Catalog, Variant, Namespace, Resource or SkillSet")
+	searchableKinds       = []string{catcommon.CatalogKind, catcommon.VariantKind, catcommon.NamespaceKind, catcommon.ResourceKind, catcommon.SkillSetKind}
+)
+
+// SearchOptions holds the parameters of a search request. Terms are the words of the query,
+// all of which must match. Kinds restricts the hits to objects of the given kinds, e.g.
+// catcommon.ResourceKind, and is empty to search all kinds.
+type SearchOptions struct {
+	Terms []string
+	Kinds []string
+	Limit int
+}
+
+// SearchOptionsFromQuery parses the q, kind and limit query parameters. The query is split into
+// words of letters and digits, and kind is a comma separated list of kinds.
+func SearchOptionsFromQuery(q url.Values) (SearchOptions, error) {
+	opts := SearchOptions{Limit: DefaultSearchLimit}
+
+	for _, term := range strings.FieldsFunc(q.Get("q"), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !slices.Contains(opts.Terms, term) {
+			opts.Terms = append(opts.Terms, term)
+		}
+	}
+	if len(opts.Terms) == 0 {
+		return opts, errMissingSearchQuery
+	}
+	if len(opts.Terms) > MaxSearchTerms {
+		return opts, errTooManySearchTerms
+	}
+
+	if k := q.Get("kind"); k != "" {
+		for _, kind := range strings.Split(k, ",") {
+			kind = strings.TrimSpace(kind)
+			if !slices.Contains(searchableKinds, kind) {
+				return opts, errInvalidSearchKind
+			}
+			if !slices.Contains(opts.Kinds, kind) {
+				opts.Kinds = append(opts.Kinds, kind)
+			}
+		}
+	}
+
+	if l := q.Get("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 {
+			return opts, errInvalidSearchLimit
+		}
+		opts.Limit = min(limit, MaxSearchLimit)
+	}
+	return opts, nil
+}
@@ -0,0 +1,86 @@
+package interfaces
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+)
+
+func TestSearchOptionsFromQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       url.Values
+		wantTerms   []string
+		wantKinds   []string
+		wantLimit   int
+		expectError bool
+	}{
+		{
+			name:      "single term",
+			query:     url.Values{"q": {"maxRetries"}},
+			wantTerms: []string{"maxRetries"},
+			wantLimit: DefaultSearchLimit,
+		},
+		{
+			name:      "punctuation separates terms",
+			query:     url.Values{"q": {"retry-policy.max & retry"}},
+			wantTerms: []string{"retry", "policy", "max"},
+			wantLimit: DefaultSearchLimit,
+		},
+		{
+			name:      "kinds and limit",
+			query:     url.Values{"q": {"db"}, "kind": {"Resource, SkillSet,Resource"}, "limit": {"5"}},
+			wantTerms: []string{"db"},
+			wantKinds: []string{catcommon.ResourceKind, catcommon.SkillSetKind},
+			wantLimit: 5,
+		},
+		{
+			name:      "limit is capped",
+			query:     url.Values{"q": {"db"}, "limit": {"5000"}},
+			wantTerms: []string{"db"},
+			wantLimit: MaxSearchLimit,
+		},
+		{
+			name:        "missing query",
+			query:       url.Values{},
+			expectError: true,
+		},
+		{
+			name:        "query without words",
+			query:       url.Values{"q": {"*:&!"}},
+			expectError: true,
+		},
+		{
+			name:        "too many terms",
+			query:       url.Values{"q": {"a b c d e f g h i"}},
+			expectError: true,
+		},
+		{
+			name:        "invalid kind",
+			query:       url.Values{"q": {"db"}, "kind": {"View"}},
+			expectError: true,
+		},
+		{
+			name:        "invalid limit",
+			query:       url.Values{"q": {"db"}, "limit": {"-1"}},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := SearchOptionsFromQuery(tt.query)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTerms, opts.Terms)
+			assert.Equal(t, tt.wantKinds, opts.Kinds)
+			assert.Equal(t, tt.wantLimit, opts.Limit)
+		})
+	}
+}
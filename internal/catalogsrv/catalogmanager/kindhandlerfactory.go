@@ -35,12 +35,14 @@ func RequestType(resourceJSON []byte) (kind string, err apperrors.Error) {
 }
 
 var kindHandlerFactories = map[string]interfaces.KindHandlerFactory{
-	catcommon.CatalogKind:   NewCatalogKindHandler,
-	catcommon.VariantKind:   NewVariantKindHandler,
-	catcommon.NamespaceKind: NewNamespaceKindHandler,
-	catcommon.ResourceKind:  NewResourceKindHandler,
-	catcommon.SkillSetKind:  NewSkillSetKindHandler,
-	catcommon.ViewKind:      policy.NewViewKindHandler,
+	catcommon.CatalogKind:          NewCatalogKindHandler,
+	catcommon.VariantKind:          NewVariantKindHandler,
+	catcommon.NamespaceKind:        NewNamespaceKindHandler,
+	catcommon.ResourceKind:         NewResourceKindHandler,
+	catcommon.SkillSetKind:         NewSkillSetKindHandler,
+	catcommon.ViewKind:             policy.NewViewKindHandler,
+	catcommon.ViewTemplateKind:     policy.NewViewTemplateKindHandler,
+	catcommon.AdmissionWebhookKind: NewAdmissionWebhookKindHandler,
 }
 
 func ResourceManagerForKind(ctx context.Context, kind string, name interfaces.RequestContext) (interfaces.KindHandler, apperrors.Error) {
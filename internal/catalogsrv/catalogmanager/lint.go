@@ -0,0 +1,233 @@
+package catalogmanager
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	schemaerr "github.com/tansive/tansive-internal/internal/catalogsrv/schema/errors"
+	"github.com/tidwall/gjson"
+)
+
+// Severities of lint findings. Documents with error findings would fail to apply or are
+// inconsistent; warnings are style issues that do not prevent applying the document.
+const (
+	LintSeverityError   = "error"
+	LintSeverityWarning = "warning"
+)
+
+// Lint rules.
+const (
+	LintRuleInvalid            = "invalid"
+	LintRuleMissingDescription = "missing-description"
+	LintRuleDefaultOutOfBounds = "default-out-of-bounds"
+	LintRuleUnusedProperty     = "unused-property"
+	LintRulePathDepth          = "path-depth"
+)
+
+// LintMaxPathDepth is the number of path segments above which objects are reported as too
+// deeply nested. Deep paths are allowed but make catalogs hard to navigate and views hard to
+// write.
+const LintMaxPathDepth = 6
+
+// LintFinding is a single issue found in a document. Path is the location of the issue in
+// the document in dot notation, such as spec.schema.properties.port.default.
+type LintFinding struct {
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Path     string `json:"path,omitempty"`
+	Message  string `json:"message"`
+}
+
+// LintResult is the outcome of linting a single document.
+type LintResult struct {
+	Kind     string        `json:"kind"`
+	Name     string        `json:"name,omitempty"`
+	Findings []LintFinding `json:"findings"`
+}
+
+// HasErrors reports whether any finding of the result is an error.
+func (r *LintResult) HasErrors() bool {
+	for _, f := range r.Findings {
+		if f.Severity == LintSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *LintResult) add(severity, rule, path, message string) {
+	r.Findings = append(r.Findings, LintFinding{Severity: severity, Rule: rule, Path: path, Message: message})
+}
+
+// LintDocument checks a catalog object document for style and safety issues without applying
+// it: validation errors, missing descriptions, schema defaults that their schema rejects,
+// schema properties that are neither set nor defaulted, and deeply nested paths. Schemas are
+// checked in resources and in the context and skills of skillsets.
+func LintDocument(doc []byte) *LintResult {
+	result := &LintResult{
+		Kind:     gjson.GetBytes(doc, "kind").String(),
+		Name:     gjson.GetBytes(doc, "metadata.name").String(),
+		Findings: []LintFinding{},
+	}
+
+	switch result.Kind {
+	case catcommon.ResourceKind:
+		lintResource(doc, result)
+	case catcommon.SkillSetKind:
+		lintSkillSet(doc, result)
+	case catcommon.CatalogKind, catcommon.VariantKind, catcommon.NamespaceKind, catcommon.ViewKind:
+		lintDescription(doc, result)
+	default:
+		result.add(LintSeverityError, LintRuleInvalid, "kind", "unsupported kind: "+result.Kind)
+	}
+	return result
+}
+
+func lintResource(doc []byte, result *LintResult) {
+	var rsrc Resource
+	if err := json.Unmarshal(doc, &rsrc); err != nil {
+		result.add(LintSeverityError, LintRuleInvalid, "", "unable to parse resource: "+err.Error())
+		return
+	}
+	lintValidationErrors(rsrc.Validate(), result)
+	lintDescription(doc, result)
+	lintPathDepth(doc, result)
+	if len(rsrc.Spec.Schema) > 0 {
+		lintSchema("spec.schema", rsrc.Spec.Schema, result)
+		lintUnusedProperties("spec.schema", rsrc.Spec.Schema, gjson.GetBytes(doc, "spec.value"), result)
+	}
+}
+
+func lintSkillSet(doc []byte, result *LintResult) {
+	var skillset SkillSet
+	if err := json.Unmarshal(doc, &skillset); err != nil {
+		result.add(LintSeverityError, LintRuleInvalid, "", "unable to parse skillset: "+err.Error())
+		return
+	}
+	lintValidationErrors(skillset.Validate(), result)
+	lintDescription(doc, result)
+	lintPathDepth(doc, result)
+	for i, c := range skillset.Spec.Context {
+		if len(c.Schema) == 0 {
+			continue
+		}
+		path := "spec.context." + strconv.Itoa(i) + ".schema"
+		lintSchema(path, c.Schema, result)
+		lintUnusedProperties(path, c.Schema, gjson.GetBytes(doc, "spec.context."+strconv.Itoa(i)+".value"), result)
+	}
+	for i, s := range skillset.Spec.Skills {
+		lintSchema("spec.skills."+strconv.Itoa(i)+".inputSchema", s.InputSchema, result)
+		lintSchema("spec.skills."+strconv.Itoa(i)+".outputSchema", s.OutputSchema, result)
+	}
+}
+
+func lintValidationErrors(verrs schemaerr.ValidationErrors, result *LintResult) {
+	for _, verr := range verrs {
+		result.add(LintSeverityError, LintRuleInvalid, "", verr.Error())
+	}
+}
+
+func lintDescription(doc []byte, result *LintResult) {
+	if strings.TrimSpace(gjson.GetBytes(doc, "metadata.description").String()) == "" {
+		result.add(LintSeverityWarning, LintRuleMissingDescription, "metadata.description", "object has no description")
+	}
+}
+
+func lintPathDepth(doc []byte, result *LintResult) {
+	path := strings.Trim(gjson.GetBytes(doc, "metadata.path").String(), "/")
+	if path == "" {
+		return
+	}
+	if depth := len(strings.Split(path, "/")); depth > LintMaxPathDepth {
+		result.add(LintSeverityWarning, LintRulePathDepth, "metadata.path",
+			fmt.Sprintf("path has %d segments, more than the recommended %d", depth, LintMaxPathDepth))
+	}
+}
+
+// lintSchema checks the properties of a JSON Schema for descriptions and the defaults of the
+// schema and its subschemas against the subschema they belong to.
+func lintSchema(path string, schema json.RawMessage, result *LintResult) {
+	var node any
+	if err := json.Unmarshal(schema, &node); err != nil {
+		return // reported by validation
+	}
+	lintSchemaNode(path, node, result)
+}
+
+func lintSchemaNode(path string, node any, result *LintResult) {
+	schema, ok := node.(map[string]any)
+	if !ok {
+		return
+	}
+
+	if def, ok := schema["default"]; ok {
+		lintDefault(path, schema, def, result)
+	}
+
+	if properties, ok := schema["properties"].(map[string]any); ok {
+		for _, name := range sortedPropertyNames(properties) {
+			propertyPath := path + ".properties." + name
+			property, _ := properties[name].(map[string]any)
+			if property != nil {
+				if description, _ := property["description"].(string); strings.TrimSpace(description) == "" {
+					result.add(LintSeverityWarning, LintRuleMissingDescription, propertyPath, "property "+name+" has no description")
+				}
+			}
+			lintSchemaNode(propertyPath, properties[name], result)
+		}
+	}
+	lintSchemaNode(path+".items", schema["items"], result)
+	lintSchemaNode(path+".additionalProperties", schema["additionalProperties"], result)
+}
+
+// lintDefault reports a default that the schema it belongs to rejects. Subschemas that cannot
+// be compiled on their own, such as those with references to the root schema, are skipped.
+func lintDefault(path string, schema map[string]any, def any, result *LintResult) {
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return
+	}
+	compiled, err := compileSchema(string(raw))
+	if err != nil {
+		return
+	}
+	if err := compiled.Validate(def); err != nil {
+		result.add(LintSeverityError, LintRuleDefaultOutOfBounds, path+".default", "default does not satisfy the schema: "+err.Error())
+	}
+}
+
+// lintUnusedProperties reports the properties of an object schema that the value neither sets
+// nor has a default for.
+func lintUnusedProperties(path string, schema json.RawMessage, value gjson.Result, result *LintResult) {
+	if !value.IsObject() {
+		return
+	}
+	properties := gjson.GetBytes(schema, "properties")
+	if !properties.IsObject() {
+		return
+	}
+	var names []string
+	properties.ForEach(func(name, property gjson.Result) bool {
+		if !value.Get(gjson.Escape(name.String())).Exists() && !property.Get("default").Exists() {
+			names = append(names, name.String())
+		}
+		return true
+	})
+	sort.Strings(names)
+	for _, name := range names {
+		result.add(LintSeverityWarning, LintRuleUnusedProperty, path+".properties."+name, "property "+name+" is neither set in the value nor has a default")
+	}
+}
+
+func sortedPropertyNames(properties map[string]any) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,122 @@
+package catalogmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintDocument(t *testing.T) {
+	tests := []struct {
+		name      string
+		doc       string
+		hasErrors bool
+		findings  []LintFinding
+	}{
+		{
+			name: "clean resource",
+			doc: `{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "Resource",
+				"metadata": {"name": "limits", "catalog": "c", "description": "Request limits"},
+				"spec": {
+					"schema": {
+						"type": "object",
+						"properties": {
+							"rps": {"type": "integer", "description": "Requests per second", "maximum": 1000},
+							"burst": {"type": "integer", "description": "Burst size", "default": 10}
+						}
+					},
+					"value": {"rps": 100}
+				}
+			}`,
+			findings: []LintFinding{},
+		},
+		{
+			name: "style issues",
+			doc: `{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "Resource",
+				"metadata": {"name": "limits", "catalog": "c", "path": "/a/b/c/d/e/f/g"},
+				"spec": {
+					"schema": {
+						"type": "object",
+						"properties": {
+							"rps": {"type": "integer"},
+							"burst": {"type": "integer", "description": "Burst size"}
+						}
+					},
+					"value": {"rps": 100}
+				}
+			}`,
+			findings: []LintFinding{
+				{Severity: LintSeverityWarning, Rule: LintRuleMissingDescription, Path: "metadata.description", Message: "object has no description"},
+				{Severity: LintSeverityWarning, Rule: LintRulePathDepth, Path: "metadata.path", Message: "path has 7 segments, more than the recommended 6"},
+				{Severity: LintSeverityWarning, Rule: LintRuleMissingDescription, Path: "spec.schema.properties.rps", Message: "property rps has no description"},
+				{Severity: LintSeverityWarning, Rule: LintRuleUnusedProperty, Path: "spec.schema.properties.burst", Message: "property burst is neither set in the value nor has a default"},
+			},
+		},
+		{
+			name: "default out of bounds",
+			doc: `{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "Resource",
+				"metadata": {"name": "timeout", "catalog": "c", "description": "Timeout"},
+				"spec": {
+					"schema": {"type": "integer", "minimum": 1, "maximum": 60, "default": 120},
+					"value": 30
+				}
+			}`,
+			hasErrors: true,
+		},
+		{
+			name: "invalid resource",
+			doc: `{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "Resource",
+				"metadata": {"name": "timeout", "catalog": "c", "description": "Timeout"},
+				"spec": {
+					"schema": {"type": "integer"},
+					"value": "soon"
+				}
+			}`,
+			hasErrors: true,
+		},
+		{
+			name:      "unsupported kind",
+			doc:       `{"kind": "Widget", "metadata": {"name": "w"}}`,
+			hasErrors: true,
+		},
+		{
+			name: "catalog without description",
+			doc:  `{"apiVersion": "0.1.0-alpha.1", "kind": "Catalog", "metadata": {"name": "c"}}`,
+			findings: []LintFinding{
+				{Severity: LintSeverityWarning, Rule: LintRuleMissingDescription, Path: "metadata.description", Message: "object has no description"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LintDocument([]byte(tt.doc))
+			assert.Equal(t, tt.hasErrors, result.HasErrors(), "findings: %v", result.Findings)
+			if tt.findings != nil {
+				assert.Equal(t, tt.findings, result.Findings)
+			}
+		})
+	}
+
+	result := LintDocument([]byte(`{
+		"apiVersion": "0.1.0-alpha.1",
+		"kind": "Resource",
+		"metadata": {"name": "timeout", "catalog": "c", "description": "Timeout"},
+		"spec": {
+			"schema": {"type": "object", "properties": {"seconds": {"type": "integer", "description": "Seconds", "maximum": 60, "default": 120}}},
+			"value": {"seconds": 30}
+		}
+	}`))
+	if assert.Len(t, result.Findings, 1) {
+		assert.Equal(t, LintRuleDefaultOutOfBounds, result.Findings[0].Rule)
+		assert.Equal(t, "spec.schema.properties.seconds.default", result.Findings[0].Path)
+	}
+}
@@ -5,8 +5,11 @@ import (
 
 	"encoding/json"
 
+	"github.com/jackc/pgtype"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/objectstore"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
 	"github.com/tansive/tansive-internal/pkg/types"
 	"github.com/tidwall/gjson"
@@ -76,6 +79,12 @@ func canonicalizeMetadata(resourceJSON []byte, kind string, metadata *interfaces
 		if !metadata.Namespace.IsNil() {
 			resourceMetadata.Namespace = metadata.Namespace
 		}
+		if metadata.Labels != nil {
+			resourceMetadata.Labels = metadata.Labels
+		}
+		if metadata.Annotations != nil {
+			resourceMetadata.Annotations = metadata.Annotations
+		}
 	}
 
 	if resourceMetadata.Variant.IsNil() {
@@ -89,3 +98,34 @@ func canonicalizeMetadata(resourceJSON []byte, kind string, metadata *interfaces
 
 	return updatedJSON, &resourceMetadata, nil
 }
+
+// catalogObjectLabels returns the labels stored with a resource or skillset object.
+// Objects that cannot be loaded have no labels.
+func catalogObjectLabels(ctx context.Context, hash string) map[string]string {
+	obj, err := db.DB(ctx).GetCatalogObject(ctx, hash)
+	if err != nil {
+		return nil
+	}
+	var storageRep objectstore.ObjectStorageRepresentation
+	if err := json.Unmarshal(obj.Data, &storageRep); err != nil {
+		return nil
+	}
+	return storageRep.Labels
+}
+
+// infoJSONB returns the value of the info column holding the labels and annotations of
+// catalogs and variants.
+func infoJSONB(info interfaces.ObjectInfo) pgtype.JSONB {
+	b := info.Marshal()
+	if b == nil {
+		return pgtype.JSONB{Status: pgtype.Null}
+	}
+	return pgtype.JSONB{Bytes: b, Status: pgtype.Present}
+}
+
+func objectInfoFromJSONB(info pgtype.JSONB) interfaces.ObjectInfo {
+	if info.Status != pgtype.Present {
+		return interfaces.ObjectInfo{}
+	}
+	return interfaces.ObjectInfoFromJSON(info.Bytes)
+}
@@ -37,10 +37,16 @@ type namespaceSchema struct {
 }
 
 type namespaceMetadata struct {
-	Catalog     string `json:"catalog" validate:"omitempty,resourceNameValidator"`
-	Variant     string `json:"variant" validate:"omitempty,resourceNameValidator"`
-	Name        string `json:"name" validate:"required,resourceNameValidator"`
-	Description string `json:"description"`
+	Catalog     string            `json:"catalog" validate:"omitempty,resourceNameValidator"`
+	Variant     string            `json:"variant" validate:"omitempty,resourceNameValidator"`
+	Name        string            `json:"name" validate:"required,resourceNameValidator"`
+	Description string            `json:"description"`
+	Labels      map[string]string `json:"labels,omitempty" validate:"omitempty,labelsValidator"`
+	Annotations map[string]string `json:"annotations,omitempty" validate:"omitempty,annotationsValidator"`
+}
+
+func (m namespaceMetadata) objectInfo() interfaces.ObjectInfo {
+	return interfaces.ObjectInfo{Labels: m.Labels, Annotations: m.Annotations}
 }
 
 type namespaceManager struct {
@@ -114,7 +120,7 @@ func NewNamespaceManager(ctx context.Context, resourceJSON []byte, catalog strin
 		Name:        ns.Metadata.Name,
 		Catalog:     ns.Metadata.Catalog,
 		Variant:     ns.Metadata.Variant,
-		Info:        nil,
+		Info:        ns.Metadata.objectInfo().Marshal(),
 	}
 
 	return &namespaceManager{
@@ -155,6 +161,10 @@ func (ns *namespaceSchema) Validate() schemaerr.ValidationErrors {
 			validationErrors = append(validationErrors, schemaerr.ErrInvalidObjectPath(jsonFieldName))
 		case "validateVersion":
 			validationErrors = append(validationErrors, schemaerr.ErrInvalidVersion(jsonFieldName))
+		case "labelsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidLabel(jsonFieldName))
+		case "annotationsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidAnnotation(jsonFieldName))
 		default:
 			validationErrors = append(validationErrors, schemaerr.ErrValidationFailed(jsonFieldName))
 		}
@@ -220,6 +230,7 @@ func (nm *namespaceManager) Save(ctx context.Context) apperrors.Error {
 }
 
 func (nm *namespaceManager) ToJson(ctx context.Context) ([]byte, apperrors.Error) {
+	info := interfaces.ObjectInfoFromJSON(nm.namespace.Info)
 	ns := &namespaceSchema{
 		ApiVersion: "0.1.0-alpha.1",
 		Kind:       catcommon.NamespaceKind,
@@ -228,6 +239,8 @@ func (nm *namespaceManager) ToJson(ctx context.Context) ([]byte, apperrors.Error
 			Variant:     nm.namespace.Variant,
 			Name:        nm.namespace.Name,
 			Description: nm.namespace.Description,
+			Labels:      info.Labels,
+			Annotations: info.Annotations,
 		},
 	}
 
@@ -251,16 +264,6 @@ func DeleteNamespace(ctx context.Context, name string, variantID uuid.UUID) appe
 		return err
 	}
 
-	// delete the namespace
-	err = db.DB(ctx).DeleteNamespace(ctx, name, variantID)
-	if err != nil {
-		if errors.Is(err, dberror.ErrNotFound) {
-			return ErrNamespaceNotFound
-		}
-		log.Ctx(ctx).Error().Err(err).Msg("failed to delete namespace")
-		return err
-	}
-
 	//get the resource directory for the variant
 	variant, err := db.DB(ctx).GetVariantByID(ctx, variantID)
 	if err != nil {
@@ -270,13 +273,25 @@ func DeleteNamespace(ctx context.Context, name string, variantID uuid.UUID) appe
 
 	dir := variant.ResourceDirectoryID
 
-	// delete the namespace objects in all directories
-	_, err = db.DB(ctx).DeleteNamespaceObjects(ctx, catcommon.CatalogObjectTypeResource, dir, name)
-	if err != nil {
-		log.Ctx(ctx).Error().Err(err).Msg("failed to delete namespace objects in Resource")
-	}
+	// delete the namespace and its objects together, so that no objects are left behind in a
+	// namespace that no longer exists
+	return db.Tx(ctx, func(ctx context.Context) apperrors.Error {
+		err := db.DB(ctx).DeleteNamespace(ctx, name, variantID)
+		if err != nil {
+			if errors.Is(err, dberror.ErrNotFound) {
+				return ErrNamespaceNotFound
+			}
+			log.Ctx(ctx).Error().Err(err).Msg("failed to delete namespace")
+			return err
+		}
 
-	return nil
+		_, err = db.DB(ctx).DeleteNamespaceObjects(ctx, catcommon.CatalogObjectTypeResource, dir, name)
+		if err != nil && !errors.Is(err, dberror.ErrNotFound) {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to delete namespace objects in Resource")
+			return err
+		}
+		return nil
+	})
 }
 
 type namespaceKind struct {
@@ -363,6 +378,7 @@ func (n *namespaceKind) Update(ctx context.Context, rsrcJson []byte) apperrors.E
 	}
 	namespace.Description = ns.Metadata.Description
 	namespace.Name = ns.Metadata.Name
+	namespace.Info = ns.Metadata.objectInfo().Marshal()
 	err = db.DB(ctx).UpdateNamespace(ctx, namespace)
 	if err != nil {
 		if errors.Is(err, dberror.ErrNotFound) {
@@ -376,7 +392,32 @@ func (n *namespaceKind) Update(ctx context.Context, rsrcJson []byte) apperrors.E
 }
 
 func (n *namespaceKind) List(ctx context.Context) ([]byte, apperrors.Error) {
-	return nil, nil
+	namespaces, err := db.DB(ctx).ListNamespacesByVariant(ctx, n.req.VariantID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list namespaces")
+		return nil, ErrUnableToLoadObject.Msg("unable to list namespaces")
+	}
+
+	namespaces = interfaces.FilterByLabels(namespaces, func(ns *models.Namespace) map[string]string {
+		return interfaces.ObjectInfoFromJSON(ns.Info).Labels
+	}, n.req.ListOptions.LabelSelector)
+	page, next := interfaces.Paginate(namespaces, func(ns *models.Namespace) string { return ns.Name }, n.req.ListOptions)
+
+	items := make([]interfaces.ListItem, 0, len(page))
+	for _, namespace := range page {
+		items = append(items, interfaces.ListItem{
+			Name:        namespace.Name,
+			Description: namespace.Description,
+			Labels:      interfaces.ObjectInfoFromJSON(namespace.Info).Labels,
+		})
+	}
+
+	jsonData, e := interfaces.MarshalList(catcommon.KindNameNamespaces, items, next)
+	if e != nil {
+		log.Ctx(ctx).Error().Err(e).Msg("unable to marshal namespace list")
+		return nil, ErrUnableToLoadObject.Msg("unable to marshal namespace list")
+	}
+	return jsonData, nil
 }
 
 func NewNamespaceKindHandler(ctx context.Context, reqCtx interfaces.RequestContext) (interfaces.KindHandler, apperrors.Error) {
@@ -0,0 +1,119 @@
+package catalogmanager
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+// objectGCBatchSize is the number of catalog objects deleted per statement, so that a large
+// backlog of garbage does not hold locks for long.
+const objectGCBatchSize = 1000
+
+// ObjectGCResult is the outcome of a garbage collection run.
+type ObjectGCResult struct {
+	Objects int64 `json:"objects"`
+	Bytes   int64 `json:"bytes"`
+}
+
+// ObjectGCStats are the statistics of the garbage collection of catalog objects since the
+// server started. Bytes are counted as stored, that is after compression.
+type ObjectGCStats struct {
+	Runs           int64          `json:"runs"`
+	Failures       int64          `json:"failures"`
+	ObjectsDeleted int64          `json:"objectsDeleted"`
+	BytesReclaimed int64          `json:"bytesReclaimed"`
+	LastRunAt      *time.Time     `json:"lastRunAt,omitempty"`
+	LastRun        ObjectGCResult `json:"lastRun"`
+}
+
+var (
+	objectGCMu    sync.Mutex
+	objectGCStats ObjectGCStats
+)
+
+// GetObjectGCStats returns the statistics of the garbage collection of catalog objects.
+func GetObjectGCStats() ObjectGCStats {
+	objectGCMu.Lock()
+	defer objectGCMu.Unlock()
+	return objectGCStats
+}
+
+// CollectUnreferencedObjects deletes the catalog objects that no resource or skillset directory
+// references and that have not changed within the grace period. The grace period protects
+// objects that are being stored but not yet referenced. Objects are collected in the tenant of
+// the context, or in all tenants if the context has no tenant.
+func CollectUnreferencedObjects(ctx context.Context, gracePeriod time.Duration) (ObjectGCResult, apperrors.Error) {
+	before := time.Now().Add(-gracePeriod)
+	var result ObjectGCResult
+	for ctx.Err() == nil {
+		objects, bytes, err := db.DB(ctx).DeleteUnreferencedCatalogObjects(ctx, before, objectGCBatchSize)
+		if err != nil {
+			recordObjectGC(result, err)
+			return result, err
+		}
+		result.Objects += objects
+		result.Bytes += bytes
+		if objects < objectGCBatchSize {
+			break
+		}
+	}
+	recordObjectGC(result, nil)
+
+	if result.Objects > 0 {
+		log.Ctx(ctx).Info().
+			Str("tenant_id", string(catcommon.GetTenantID(ctx))).
+			Int64("objects", result.Objects).
+			Int64("bytes", result.Bytes).
+			Msg("collected unreferenced catalog objects")
+	}
+	return result, nil
+}
+
+func recordObjectGC(result ObjectGCResult, err error) {
+	now := time.Now()
+	objectGCMu.Lock()
+	defer objectGCMu.Unlock()
+	objectGCStats.Runs++
+	if err != nil {
+		objectGCStats.Failures++
+	}
+	objectGCStats.ObjectsDeleted += result.Objects
+	objectGCStats.BytesReclaimed += result.Bytes
+	objectGCStats.LastRunAt = &now
+	objectGCStats.LastRun = result
+}
+
+// RunObjectGCJob collects unreferenced catalog objects of all tenants every interval until the
+// context is done.
+func RunObjectGCJob(ctx context.Context, interval, gracePeriod time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		collectAllUnreferencedObjects(ctx, gracePeriod)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func collectAllUnreferencedObjects(ctx context.Context, gracePeriod time.Duration) {
+	dbCtx, err := db.ConnCtx(db.WithAllTenants(ctx))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("object gc: unable to get db connection")
+		return
+	}
+	defer db.DB(dbCtx).Close(dbCtx)
+
+	if _, err := CollectUnreferencedObjects(dbCtx, gracePeriod); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("object gc: unable to collect unreferenced catalog objects")
+	}
+}
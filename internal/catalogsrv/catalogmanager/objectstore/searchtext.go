@@ -0,0 +1,111 @@
+package objectstore
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// maxSearchTextSize caps the text indexed for a single object, well below the size limit of a
+// Postgres tsvector.
+const maxSearchTextSize = 256 * 1024
+
+// SearchText returns the text by which an object is found in catalog search: its description and
+// annotations, and the names, titles and descriptions in its spec, including the property names
+// of its schemas. Resource values are not indexed.
+func (s *ObjectStorageRepresentation) SearchText() string {
+	var words []string
+	if s.Description != "" {
+		words = append(words, s.Description)
+	}
+	words = appendAnnotations(words, s.Annotations)
+
+	if len(s.Spec) > 0 {
+		var spec any
+		if err := json.Unmarshal(s.Spec, &spec); err == nil {
+			words = collectSearchWords(words, spec)
+		}
+	}
+
+	text := strings.Join(words, " ")
+	if len(text) > maxSearchTextSize {
+		text = strings.ToValidUTF8(text[:maxSearchTextSize], "")
+	}
+	return text
+}
+
+// SearchText returns the search text of a serialized ObjectStorageRepresentation, or an empty
+// string if the data cannot be parsed.
+func SearchText(data []byte) string {
+	var s ObjectStorageRepresentation
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ""
+	}
+	return s.SearchText()
+}
+
+func collectSearchWords(words []string, v any) []string {
+	switch v := v.(type) {
+	case map[string]any:
+		for _, k := range sortedKeys(v) {
+			switch k {
+			case "value":
+				// resource data, not metadata
+				continue
+			case "name", "title", "description":
+				if s, ok := v[k].(string); ok && s != "" {
+					words = append(words, s)
+					continue
+				}
+			case "properties":
+				if props, ok := v[k].(map[string]any); ok {
+					for _, p := range sortedKeys(props) {
+						words = append(words, p)
+						words = collectSearchWords(words, props[p])
+					}
+					continue
+				}
+			case "annotations":
+				if m, ok := v[k].(map[string]any); ok {
+					annotations := make(map[string]string, len(m))
+					for ak, av := range m {
+						s, _ := av.(string)
+						annotations[ak] = s
+					}
+					words = appendAnnotations(words, annotations)
+					continue
+				}
+			}
+			words = collectSearchWords(words, v[k])
+		}
+	case []any:
+		for _, e := range v {
+			words = collectSearchWords(words, e)
+		}
+	}
+	return words
+}
+
+func appendAnnotations(words []string, annotations map[string]string) []string {
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		words = append(words, k)
+		if annotations[k] != "" {
+			words = append(words, annotations[k])
+		}
+	}
+	return words
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,55 @@
+package objectstore
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchText(t *testing.T) {
+	s := ObjectStorageRepresentation{
+		Description: "Retry settings",
+		Annotations: map[string]string{"owner": "platform", "team": ""},
+		Spec: json.RawMessage(`{
+			"schema": {
+				"type": "object",
+				"title": "RetryPolicy",
+				"properties": {
+					"maxRetries": {"type": "integer", "description": "attempts before giving up"},
+					"backoff": {
+						"type": "object",
+						"properties": {"initialDelay": {"type": "string"}}
+					}
+				}
+			},
+			"value": {"maxRetries": 3, "secretToken": "abc"},
+			"annotations": {"tier": "gold"}
+		}`),
+	}
+
+	text := s.SearchText()
+	assert.Equal(t, "Retry settings owner platform team tier gold backoff initialDelay maxRetries attempts before giving up RetryPolicy", text)
+	assert.NotContains(t, text, "secretToken")
+
+	data, err := s.Serialize()
+	assert.Nil(t, err)
+	assert.Equal(t, text, SearchText(data))
+
+	assert.Equal(t, "", SearchText([]byte("not json")))
+}
+
+func TestSearchTextSkillSet(t *testing.T) {
+	s := ObjectStorageRepresentation{
+		Spec: json.RawMessage(`{
+			"skills": [
+				{
+					"name": "listPods",
+					"description": "List pods in a namespace",
+					"inputSchema": {"properties": {"namespace": {"type": "string"}}}
+				}
+			]
+		}`),
+	}
+	assert.Equal(t, "List pods in a namespace namespace listPods", s.SearchText())
+}
@@ -12,6 +12,9 @@ type ObjectStorageRepresentation struct {
 	Version     string                      `json:"version"`
 	Type        catcommon.CatalogObjectType `json:"type"`
 	Description string                      `json:"description"`
+	Labels      map[string]string           `json:"labels,omitempty"`
+	Annotations map[string]string           `json:"annotations,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
 	Spec        json.RawMessage             `json:"spec"`
 	Values      json.RawMessage             `json:"values"`
 	Reserved    json.RawMessage             `json:"reserved"`
@@ -0,0 +1,112 @@
+package catalogmanager
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+// ListCatalogObjects returns the canonical resource URI of every object of a catalog: the
+// catalog, its variants with their namespaces, skillsets and resources, its views and its view
+// templates. Internal views whose labels start with "_" are not listed.
+func ListCatalogObjects(ctx context.Context, name string) ([]policy.CatalogObject, apperrors.Error) {
+	cm, err := LoadCatalogManagerByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	catalogID := cm.ID()
+
+	catalogURI := "res://catalogs/" + name
+	objects := []policy.CatalogObject{
+		{Kind: catcommon.KindNameCatalogs, Resource: policy.TargetResource(catalogURI)},
+	}
+
+	variants, err := db.DB(ctx).ListVariantsByCatalog(ctx, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list variants")
+		return nil, ErrUnableToLoadObject.Msg("unable to list variants")
+	}
+	for _, variant := range variants {
+		variantURI := catalogURI + "/variants/" + variant.Name
+		objects = append(objects, policy.CatalogObject{Kind: catcommon.KindNameVariants, Resource: policy.TargetResource(variantURI)})
+
+		namespaces, err := db.DB(ctx).ListNamespacesByVariant(ctx, variant.VariantID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to list namespaces")
+			return nil, ErrUnableToLoadObject.Msg("unable to list namespaces")
+		}
+		namespaceNames := make(map[string]bool, len(namespaces))
+		for _, namespace := range namespaces {
+			namespaceNames[namespace.Name] = true
+			objects = append(objects, policy.CatalogObject{
+				Kind:     catcommon.KindNameNamespaces,
+				Resource: policy.TargetResource(variantURI + "/namespaces/" + namespace.Name),
+			})
+		}
+
+		skillsets, err := db.DB(ctx).ListSkillSets(ctx, variant.SkillsetDirectoryID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to list skillsets")
+			return nil, ErrUnableToLoadObject.Msg("unable to list skillsets")
+		}
+		for _, skillset := range skillsets {
+			m := exportMetadata(name, variant.Name, skillset.Path, namespaceNames)
+			objects = append(objects, catalogObjectFromMetadata(catcommon.KindNameSkillsets, variantURI, m))
+		}
+
+		resources, err := db.DB(ctx).ListResources(ctx, variant.ResourceDirectoryID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to list resources")
+			return nil, ErrUnableToLoadObject.Msg("unable to list resources")
+		}
+		for _, resource := range resources {
+			m := exportMetadata(name, variant.Name, resource.Path, namespaceNames)
+			objects = append(objects, catalogObjectFromMetadata(catcommon.KindNameResources, variantURI, m))
+		}
+	}
+
+	views, err := db.DB(ctx).ListViewsByCatalog(ctx, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list views")
+		return nil, ErrUnableToLoadObject.Msg("unable to list views")
+	}
+	for _, view := range views {
+		if strings.HasPrefix(view.Label, "_") {
+			continue
+		}
+		objects = append(objects, policy.CatalogObject{
+			Kind:     catcommon.KindNameViews,
+			Resource: policy.TargetResource(catalogURI + "/views/" + view.Label),
+		})
+	}
+
+	templates, err := db.DB(ctx).ListViewTemplatesByCatalog(ctx, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list view templates")
+		return nil, ErrUnableToLoadObject.Msg("unable to list view templates")
+	}
+	for _, template := range templates {
+		objects = append(objects, policy.CatalogObject{
+			Kind:     catcommon.KindNameViewTemplates,
+			Resource: policy.TargetResource(catalogURI + "/viewtemplates/" + template.Label),
+		})
+	}
+
+	return objects, nil
+}
+
+func catalogObjectFromMetadata(kindName, variantURI string, m *interfaces.Metadata) policy.CatalogObject {
+	uri := variantURI
+	if m.Namespace.Valid {
+		uri += "/namespaces/" + m.Namespace.String()
+	}
+	uri += "/" + kindName + path.Join("/", m.Path, m.Name)
+	return policy.CatalogObject{Kind: kindName, Resource: policy.TargetResource(uri)}
+}
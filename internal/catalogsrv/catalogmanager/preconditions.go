@@ -0,0 +1,154 @@
+package catalogmanager
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/objectstore"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// etagHashLength is the number of hex characters of the object hash used in an ETag.
+const etagHashLength = 32
+
+// Preconditions holds the conditional headers sent with a write request.
+// Each field contains the entity tags listed in the corresponding header.
+type Preconditions struct {
+	IfMatch     []string
+	IfNoneMatch []string
+}
+
+// ParseETagList splits an If-Match or If-None-Match header value into its entity tags.
+func ParseETagList(header string) []string {
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// IsEmpty reports whether no conditional headers were sent.
+func (p Preconditions) IsEmpty() bool {
+	return len(p.IfMatch) == 0 && len(p.IfNoneMatch) == 0
+}
+
+// ETag returns the strong entity tag of an object. The tag is derived from the
+// SHA-512 of the object's canonical JSON, the same hashing used by the object store,
+// so it changes whenever the stored representation changes.
+func ETag(objJSON []byte) string {
+	normalized, err := objectstore.NormalizeJSON(objJSON)
+	if err != nil {
+		normalized = objJSON
+	}
+	return `"` + objectstore.HexEncodedSHA512(normalized)[:etagHashLength] + `"`
+}
+
+// CheckPreconditions evaluates If-Match and If-None-Match against the current state
+// of the object served by the handler. It returns ErrPreconditionFailed if a
+// condition does not hold.
+func CheckPreconditions(ctx context.Context, h interfaces.KindHandler, p Preconditions) apperrors.Error {
+	if p.IsEmpty() {
+		return nil
+	}
+
+	exists := true
+	var currentETag string
+	current, err := h.Get(ctx)
+	if err != nil {
+		if !isNotFound(err) {
+			return err
+		}
+		exists = false
+	} else {
+		currentETag = ETag(current)
+	}
+
+	if len(p.IfMatch) > 0 {
+		if !exists || !matchesETag(p.IfMatch, currentETag) {
+			log.Ctx(ctx).Info().Strs("if_match", p.IfMatch).Str("etag", currentETag).Msg("If-Match precondition failed")
+			return ErrPreconditionFailed.Msg("object has been modified")
+		}
+	}
+
+	if len(p.IfNoneMatch) > 0 && exists && matchesETag(p.IfNoneMatch, currentETag) {
+		log.Ctx(ctx).Info().Strs("if_none_match", p.IfNoneMatch).Str("etag", currentETag).Msg("If-None-Match precondition failed")
+		return ErrPreconditionFailed.Msg("object already exists in the requested state")
+	}
+
+	return nil
+}
+
+// WriteWithPreconditions runs write in a transaction after checking the preconditions against
+// the object of kind served by the handler. The object is locked for the transaction, so that
+// no other write through WriteWithPreconditions can change it between the check and the write.
+// It returns the ETag of the object as written, which is empty if the object cannot be read
+// back, as after a delete.
+func WriteWithPreconditions(ctx context.Context, kind string, h interfaces.KindHandler, req interfaces.RequestContext, p Preconditions, write func(ctx context.Context) apperrors.Error) (string, apperrors.Error) {
+	var etag string
+	err := db.Tx(ctx, func(ctx context.Context) apperrors.Error {
+		if err := db.LockKey(ctx, objectLockKey(kind, req)); err != nil {
+			return err
+		}
+		if err := CheckPreconditions(ctx, h, p); err != nil {
+			return err
+		}
+		if err := write(ctx); err != nil {
+			return err
+		}
+		if current, err := h.Get(ctx); err == nil {
+			etag = ETag(current)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return etag, nil
+}
+
+// objectLockKey returns the key of the lock taken on the object of kind addressed by req. The
+// definition and the value of a resource share the key.
+func objectLockKey(kind string, req interfaces.RequestContext) string {
+	catalog := req.Catalog
+	if catalog == "" && req.CatalogID != uuid.Nil {
+		catalog = req.CatalogID.String()
+	}
+	return strings.Join([]string{
+		"object", kind, catalog, req.Variant, req.Namespace, path.Join("/", req.ObjectPath, req.ObjectName),
+	}, ":")
+}
+
+// matchesETag uses the weak comparison function since all ETags we issue are strong;
+// a client echoing one back as weak still refers to the same representation.
+func matchesETag(tags []string, etag string) bool {
+	for _, tag := range tags {
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func isNotFound(err apperrors.Error) bool {
+	for _, notFound := range []apperrors.Error{
+		ErrCatalogNotFound, ErrObjectNotFound, ErrVariantNotFound,
+		ErrNamespaceNotFound, ErrViewNotFound, ErrResourceNotFound,
+		policy.ErrViewNotFound, dberror.ErrNotFound,
+	} {
+		if errors.Is(err, notFound) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package catalogmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestETag(t *testing.T) {
+	a := ETag([]byte(`{"kind": "Variant", "metadata": {"name": "v1"}}`))
+	b := ETag([]byte(`{"metadata":{"name":"v1"},"kind":"Variant"}`))
+	c := ETag([]byte(`{"kind": "Variant", "metadata": {"name": "v2"}}`))
+
+	assert.Equal(t, a, b, "equivalent JSON should yield the same ETag")
+	assert.NotEqual(t, a, c)
+	assert.Len(t, a, etagHashLength+2)
+	assert.True(t, a[0] == '"' && a[len(a)-1] == '"')
+}
+
+func TestParseETagList(t *testing.T) {
+	assert.Nil(t, ParseETagList(""))
+	assert.Equal(t, []string{`"a"`}, ParseETagList(`"a"`))
+	assert.Equal(t, []string{`"a"`, `W/"b"`, "*"}, ParseETagList(` "a", W/"b" ,, *`))
+}
+
+func TestMatchesETag(t *testing.T) {
+	etag := `"abc"`
+	assert.True(t, matchesETag([]string{etag}, etag))
+	assert.True(t, matchesETag([]string{`W/"abc"`}, etag))
+	assert.True(t, matchesETag([]string{"*"}, etag))
+	assert.True(t, matchesETag([]string{`"x"`, etag}, etag))
+	assert.False(t, matchesETag([]string{`"x"`}, etag))
+	assert.False(t, matchesETag(nil, etag))
+}
@@ -0,0 +1,53 @@
+package catalogmanager
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+)
+
+// RunDeletedObjectPurgeJob permanently deletes catalogs and variants that have been soft
+// deleted for longer than retention, every interval until the context is done.
+func RunDeletedObjectPurgeJob(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeDeletedObjects(ctx, time.Now().Add(-retention))
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purgeDeletedObjects(ctx context.Context, before time.Time) {
+	dbCtx, err := db.ConnCtx(db.WithAllTenants(ctx))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("deletion purge: unable to get db connection")
+		return
+	}
+	defer db.DB(dbCtx).Close(dbCtx)
+
+	// Catalogs go first, so that the variants they cascade to are not counted twice
+	catalogs, dbErr := db.DB(dbCtx).PurgeDeletedCatalogs(dbCtx, before)
+	if dbErr != nil {
+		log.Ctx(ctx).Error().Err(dbErr).Msg("deletion purge: unable to purge deleted catalogs")
+		return
+	}
+	variants, dbErr := db.DB(dbCtx).PurgeDeletedVariants(dbCtx, before)
+	if dbErr != nil {
+		log.Ctx(ctx).Error().Err(dbErr).Msg("deletion purge: unable to purge deleted variants")
+		return
+	}
+	if catalogs > 0 || variants > 0 {
+		log.Ctx(ctx).Info().
+			Int64("catalogs", catalogs).
+			Int64("variants", variants).
+			Time("deleted_before", before).
+			Msg("purged deleted objects")
+	}
+}
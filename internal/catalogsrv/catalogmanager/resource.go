@@ -29,6 +29,7 @@ type ResourceManager interface {
 	GetStoragePath() string
 	JSON(ctx context.Context) ([]byte, apperrors.Error)
 	SpecJSON(ctx context.Context) ([]byte, apperrors.Error)
+	DeprecationWarning() string
 }
 
 // NewResourceManager creates a new ResourceManager instance from the provided JSON schema and metadata.
@@ -63,7 +64,7 @@ func NewResourceManager(ctx context.Context, rsrcJSON []byte, m *interfaces.Meta
 
 func LoadResourceManagerByHash(ctx context.Context, hash string, m *interfaces.Metadata) (ResourceManager, apperrors.Error) {
 	// get the object from catalog object store
-	obj, err := db.DB(ctx).GetCatalogObject(ctx, hash)
+	obj, err := db.ReadDB(ctx).GetCatalogObject(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
@@ -81,14 +82,14 @@ func LoadResourceManagerByPath(ctx context.Context, m *interfaces.Metadata) (Res
 	var err apperrors.Error
 
 	if catalogID == uuid.Nil {
-		catalogID, err = db.DB(ctx).GetCatalogIDByName(ctx, m.Catalog)
+		catalogID, err = db.ReadDB(ctx).GetCatalogIDByName(ctx, m.Catalog)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Str("catalog", m.Catalog).Msg("Failed to get catalog ID by name")
 			return nil, err
 		}
 	}
 
-	variant, err := db.DB(ctx).GetVariant(ctx, catalogID, uuid.Nil, m.Variant.String())
+	variant, err := db.ReadDB(ctx).GetVariant(ctx, catalogID, uuid.Nil, m.Variant.String())
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Str("catalogID", catalogID.String()).Str("name", m.Name).Msg("Failed to get variant")
 		return nil, err
@@ -96,7 +97,7 @@ func LoadResourceManagerByPath(ctx context.Context, m *interfaces.Metadata) (Res
 
 	pathWithName := path.Clean(m.GetStoragePath(catcommon.CatalogObjectTypeResource) + "/" + m.Name)
 
-	obj, err := db.DB(ctx).GetResourceObject(ctx, pathWithName, variant.ResourceDirectoryID)
+	obj, err := db.ReadDB(ctx).GetResourceObject(ctx, pathWithName, variant.ResourceDirectoryID)
 	if err != nil {
 		return nil, err
 	}
@@ -121,15 +122,17 @@ func resourceManagerFromObject(ctx context.Context, obj *models.CatalogObject, m
 	}
 
 	rm := &resourceManager{}
-	if err := json.Unmarshal(storageRep.Spec, &rm.resource.Spec); err != nil {
-		log.Ctx(ctx).Error().Err(err).Msg("Failed to unmarshal resource schema spec")
-		return nil, ErrUnableToLoadObject
+	if err := rm.loadStoredSpec(ctx, storageRep.Spec); err != nil {
+		return nil, err
 	}
 
 	rm.resource.Kind = catcommon.ResourceKind
 	rm.resource.ApiVersion = storageRep.Version
 	rm.resource.Metadata = *m
 	rm.resource.Metadata.Description = storageRep.Description
+	rm.resource.Metadata.Labels = storageRep.Labels
+	rm.resource.Metadata.Annotations = storageRep.Annotations
+	rm.resource.Metadata.Deprecated = storageRep.Deprecated
 
 	return rm, nil
 }
@@ -139,8 +142,14 @@ var _ interfaces.KindHandler = &resourceKindHandler{}
 // resourceKindHandler implements the KindHandler interface for managing individual resources.
 // It handles CRUD operations for resources and maintains the request context.
 type resourceKindHandler struct {
-	req interfaces.RequestContext
-	rm  ResourceManager
+	req      interfaces.RequestContext
+	rm       ResourceManager
+	warnings []string
+}
+
+// Warnings returns the deprecation warnings of the resources served by the last Get or List.
+func (h *resourceKindHandler) Warnings() []string {
+	return h.warnings
 }
 
 // Name returns the name of the resource from the request context.
@@ -209,8 +218,19 @@ func (h *resourceKindHandler) Create(ctx context.Context, rsrcJSON []byte) (stri
 }
 
 // Get retrieves a resource by its path and returns it as JSON.
-// It validates the metadata and loads the resource from storage.
+// It validates the metadata and loads the resource from storage. The value of a sensitive
+// resource is redacted unless the caller may reveal it.
 func (h *resourceKindHandler) Get(ctx context.Context) ([]byte, apperrors.Error) {
+	return h.get(ctx, true)
+}
+
+// GetUnredacted retrieves a resource like Get, without redacting the value of a sensitive
+// resource.
+func (h *resourceKindHandler) GetUnredacted(ctx context.Context) ([]byte, apperrors.Error) {
+	return h.get(ctx, false)
+}
+
+func (h *resourceKindHandler) get(ctx context.Context, redact bool) ([]byte, apperrors.Error) {
 	m := &interfaces.Metadata{
 		Catalog:   h.req.Catalog,
 		Variant:   types.NullableStringFrom(h.req.Variant),
@@ -227,11 +247,20 @@ func (h *resourceKindHandler) Get(ctx context.Context) ([]byte, apperrors.Error)
 	if err != nil {
 		return nil, err
 	}
+	if w := rm.DeprecationWarning(); w != "" {
+		h.warnings = append(h.warnings, w)
+	}
 	switch h.req.ObjectProperty {
 	case catcommon.ResourcePropertyDefinition:
-		return rm.JSON(ctx)
+		if !redact {
+			return rm.JSON(ctx)
+		}
+		return readableResourceJSON(ctx, rm)
 	case catcommon.ResourcePropertyValue:
-		return rm.GetValueJSON(ctx)
+		if !redact {
+			return rm.GetValueJSON(ctx)
+		}
+		return resolvedValueJSON(ctx, rm)
 	default:
 		return nil, ErrDisallowedByPolicy
 	}
@@ -263,6 +292,10 @@ func (h *resourceKindHandler) Update(ctx context.Context, rsrcJSON []byte) apper
 
 	switch h.req.ObjectProperty {
 	case catcommon.ResourcePropertyDefinition:
+		rsrcJSON, err := restoreRedactedValue(ctx, rsrcJSON, existing)
+		if err != nil {
+			return err
+		}
 		rm, err := NewResourceManager(ctx, rsrcJSON, m)
 		if err != nil {
 			return err
@@ -276,12 +309,72 @@ func (h *resourceKindHandler) Update(ctx context.Context, rsrcJSON []byte) apper
 		if err := existing.SetValue(ctx, val); err != nil {
 			return err
 		}
+		if w := existing.DeprecationWarning(); w != "" {
+			// Logged so that the remaining users of deprecated resources can be tracked down
+			log.Ctx(ctx).Warn().
+				Str("path", existing.GetStoragePath()).
+				Str("user", catcommon.GetUserID(ctx)).
+				Msg("value written to deprecated resource")
+		}
 		return existing.Save(ctx)
 	default:
 		return ErrDisallowedByPolicy
 	}
 }
 
+// admit calls the admission webhooks for a write of rsrcJSON through the handler, as Create or
+// Update, and returns the object to write in its place.
+func (h *resourceKindHandler) admit(ctx context.Context, rsrcJSON []byte) ([]byte, apperrors.Error) {
+	m := &interfaces.Metadata{
+		Catalog:   h.req.Catalog,
+		Variant:   types.NullableStringFrom(h.req.Variant),
+		Namespace: types.NullableStringFrom(h.req.Namespace),
+	}
+	// Creates address no resource and write its definition
+	if h.req.ObjectName == "" {
+		return h.admitDefinition(ctx, rsrcJSON, m)
+	}
+	m.Path = h.req.ObjectPath
+	m.Name = h.req.ObjectName
+
+	if err := m.Validate(); err != nil {
+		return nil, ErrSchemaValidation.Msg(err.Error())
+	}
+	existing, err := LoadResourceManagerByPath(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	if h.req.ObjectProperty == catcommon.ResourcePropertyValue {
+		val := types.NullableAny{}
+		if err := json.Unmarshal(rsrcJSON, &val); err != nil {
+			return nil, ErrInvalidResourceValue
+		}
+		if err := existing.SetValue(ctx, val); err != nil {
+			return nil, err
+		}
+		if err := existing.(*resourceManager).admitWrite(ctx); err != nil {
+			return nil, err
+		}
+		return existing.GetValueJSON(ctx)
+	}
+	rsrcJSON, err = restoreRedactedValue(ctx, rsrcJSON, existing)
+	if err != nil {
+		return nil, err
+	}
+	return h.admitDefinition(ctx, rsrcJSON, m)
+}
+
+func (h *resourceKindHandler) admitDefinition(ctx context.Context, rsrcJSON []byte, m *interfaces.Metadata) ([]byte, apperrors.Error) {
+	rm, err := NewResourceManager(ctx, rsrcJSON, m)
+	if err != nil {
+		return nil, err
+	}
+	if err := rm.(*resourceManager).admitWrite(ctx); err != nil {
+		return nil, err
+	}
+	return rm.JSON(ctx)
+}
+
 // Delete removes a resource from storage.
 // It validates the metadata and deletes the resource if it exists.
 func (h *resourceKindHandler) Delete(ctx context.Context) apperrors.Error {
@@ -302,18 +395,24 @@ func (h *resourceKindHandler) Delete(ctx context.Context) apperrors.Error {
 }
 
 func (h *resourceKindHandler) List(ctx context.Context) ([]byte, apperrors.Error) {
-	variant, err := db.DB(ctx).GetVariantByID(ctx, h.req.VariantID)
+	variant, err := db.ReadDB(ctx).GetVariantByID(ctx, h.req.VariantID)
 	if err != nil {
 		return nil, ErrInvalidVariant
 	}
 
-	resources, err := db.DB(ctx).ListResources(ctx, variant.ResourceDirectoryID)
+	resources, err := db.ReadDB(ctx).ListResources(ctx, variant.ResourceDirectoryID)
 	if err != nil {
 		return nil, ErrCatalogError.Msg("unable to list resources")
 	}
 
-	resourceList := make(map[string]json.RawMessage)
-	for _, resource := range resources {
+	resources = interfaces.FilterByLabels(resources, func(r models.Resource) map[string]string {
+		return catalogObjectLabels(ctx, r.Hash)
+	}, h.req.ListOptions.LabelSelector)
+
+	page, next := interfaces.Paginate(resources, func(r models.Resource) string { return r.Path }, h.req.ListOptions)
+
+	resourceList := make([]json.RawMessage, 0, len(page))
+	for _, resource := range page {
 		m := &interfaces.Metadata{
 			Catalog:   h.req.Catalog,
 			Variant:   types.NullableStringFrom(h.req.Variant),
@@ -326,15 +425,18 @@ func (h *resourceKindHandler) List(ctx context.Context) ([]byte, apperrors.Error
 			continue
 		}
 
-		j, err := rm.JSON(ctx)
+		j, err := readableResourceJSON(ctx, rm)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Str("path", resource.Path).Msg("Failed to marshal resource")
 			continue
 		}
-		resourceList[path.Clean(m.Path+"/"+m.Name)] = j
+		resourceList = append(resourceList, j)
+		if w := rm.DeprecationWarning(); w != "" {
+			h.warnings = append(h.warnings, w)
+		}
 	}
 
-	j, goErr := json.Marshal(resourceList)
+	j, goErr := interfaces.MarshalList(catcommon.KindNameResources, resourceList, next)
 	if goErr != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("Failed to marshal resource list")
 		return nil, ErrInvalidResourceDefinition
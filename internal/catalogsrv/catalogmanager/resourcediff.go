@@ -0,0 +1,372 @@
+package catalogmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tansive/tansive-internal/pkg/types"
+)
+
+// Status of a resource in a resource diff.
+const (
+	ResourceDiffAdded     = "added"
+	ResourceDiffRemoved   = "removed"
+	ResourceDiffChanged   = "changed"
+	ResourceDiffUnchanged = "unchanged"
+)
+
+// Kinds of schema changes in a resource diff. A tightened change makes the schema accept fewer
+// values, so existing values may no longer validate; a loosened change makes it accept more.
+const (
+	SchemaChangeAdded     = "added"
+	SchemaChangeRemoved   = "removed"
+	SchemaChangeChanged   = "changed"
+	SchemaChangeDefault   = "default"
+	SchemaChangeTightened = "tightened"
+	SchemaChangeLoosened  = "loosened"
+)
+
+// ResourceDiff describes how the definition of a resource in a variant differs from its
+// definition in the variant it is compared against.
+type ResourceDiff struct {
+	Resource     string         `json:"resource"`
+	Variant      string         `json:"variant"`
+	Against      string         `json:"against"`
+	Status       string         `json:"status"`
+	Changes      []SchemaChange `json:"changes"`
+	ValueChanged bool           `json:"valueChanged"`
+}
+
+// SchemaChange is a single change of a resource schema. Path is the JSON pointer of the changed
+// keyword or property in the schema; it is empty if the whole schema changed.
+type SchemaChange struct {
+	Path   string `json:"path"`
+	Change string `json:"change"`
+	From   any    `json:"from,omitempty"`
+	To     any    `json:"to,omitempty"`
+}
+
+// Keywords whose larger values accept fewer values.
+var lowerBoundKeywords = []string{"minimum", "exclusiveMinimum", "minLength", "minItems", "minProperties", "minContains"}
+
+// Keywords whose smaller values accept fewer values.
+var upperBoundKeywords = []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems", "maxProperties", "maxContains"}
+
+// Keywords that restrict values when present.
+var restrictingKeywords = []string{"pattern", "format", "const", "multipleOf", "uniqueItems"}
+
+// DiffResource compares the definition of the resource described by m with its definition in
+// the against variant. The diff describes the changes from the against variant to the variant
+// of m, so a reviewer can see what merging the resource into the against variant would change.
+func DiffResource(ctx context.Context, m *interfaces.Metadata, against string) (*ResourceDiff, apperrors.Error) {
+	if m == nil {
+		return nil, ErrInvalidObject.Msg("unable to infer object metadata")
+	}
+	if against == "" {
+		return nil, ErrInvalidVariant.Msg("variant to compare against is required")
+	}
+
+	catalogID := catcommon.GetCatalogID(ctx)
+	if catalogID == uuid.Nil {
+		var err apperrors.Error
+		if catalogID, err = db.ReadDB(ctx).GetCatalogIDByName(ctx, m.Catalog); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := db.ReadDB(ctx).GetVariant(ctx, catalogID, uuid.Nil, against); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrVariantNotFound.Msg("variant " + against + " not found")
+		}
+		return nil, err
+	}
+
+	diff := &ResourceDiff{
+		Resource: m.GetFullyQualifiedName(),
+		Variant:  m.Variant.String(),
+		Against:  against,
+		Changes:  []SchemaChange{},
+	}
+
+	to, err := loadResourceForDiff(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	againstMetadata := *m
+	againstMetadata.Variant = types.NullableStringFrom(against)
+	from, err := loadResourceForDiff(ctx, &againstMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == nil && to == nil {
+		return nil, ErrResourceNotFound
+	}
+	if err := compareResources(ctx, diff, from, to); err != nil {
+		return nil, err
+	}
+	return diff, nil
+}
+
+// compareResources sets the status and changes of diff from the resource in the against
+// variant, from, to the resource in the compared variant, to. Either may be nil if the
+// resource does not exist in that variant.
+func compareResources(ctx context.Context, diff *ResourceDiff, from, to *resourceManager) apperrors.Error {
+	switch {
+	case from == nil:
+		diff.Status = ResourceDiffAdded
+		return nil
+	case to == nil:
+		diff.Status = ResourceDiffRemoved
+		return nil
+	}
+
+	changes, goErr := DiffResourceSchemas(from.resource.Spec.Schema, to.resource.Spec.Schema)
+	if goErr != nil {
+		log.Ctx(ctx).Error().Err(goErr).Msg("unable to compare resource schemas")
+		return ErrInvalidSchema.Msg("unable to compare resource schemas")
+	}
+	diff.Changes = changes
+
+	fromValue, _ := json.Marshal(from.resource.Spec.Value)
+	toValue, _ := json.Marshal(to.resource.Spec.Value)
+	diff.ValueChanged = !bytes.Equal(fromValue, toValue)
+
+	diff.Status = ResourceDiffUnchanged
+	if len(diff.Changes) > 0 || diff.ValueChanged {
+		diff.Status = ResourceDiffChanged
+	}
+	return nil
+}
+
+// loadResourceForDiff loads a resource, returning nil if it does not exist.
+func loadResourceForDiff(ctx context.Context, m *interfaces.Metadata) (*resourceManager, apperrors.Error) {
+	rm, err := LoadResourceManagerByPath(ctx, m)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rm.(*resourceManager), nil
+}
+
+// DiffResourceSchemas returns the changes from the JSON Schema from to the JSON Schema to.
+// Properties of object schemas and the schemas of array items are compared recursively; the
+// changes are ordered by path.
+func DiffResourceSchemas(from, to json.RawMessage) ([]SchemaChange, error) {
+	var fromSchema, toSchema any
+	if len(from) > 0 {
+		if err := json.Unmarshal(from, &fromSchema); err != nil {
+			return nil, err
+		}
+	}
+	if len(to) > 0 {
+		if err := json.Unmarshal(to, &toSchema); err != nil {
+			return nil, err
+		}
+	}
+	changes := []SchemaChange{}
+	diffSchemas("", fromSchema, toSchema, &changes)
+	return changes, nil
+}
+
+func diffSchemas(ptr string, from, to any, changes *[]SchemaChange) {
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+	fromMap, fromOk := from.(map[string]any)
+	toMap, toOk := to.(map[string]any)
+	if !fromOk || !toOk {
+		// boolean schemas, or a schema replaced by a boolean schema
+		*changes = append(*changes, SchemaChange{Path: ptr, Change: SchemaChangeChanged, From: from, To: to})
+		return
+	}
+
+	for _, keyword := range sortedKeys(fromMap, toMap) {
+		fromValue, inFrom := fromMap[keyword]
+		toValue, inTo := toMap[keyword]
+		if reflect.DeepEqual(fromValue, toValue) {
+			continue
+		}
+		keywordPtr := ptr + "/" + escapePointerToken(keyword)
+		switch {
+		case keyword == "properties":
+			diffProperties(keywordPtr, fromValue, toValue, changes)
+		case keyword == "items" && inFrom && inTo:
+			diffSchemas(keywordPtr, fromValue, toValue, changes)
+		case keyword == "required":
+			diffRequired(keywordPtr, fromValue, toValue, changes)
+		case keyword == "default":
+			*changes = append(*changes, SchemaChange{Path: keywordPtr, Change: SchemaChangeDefault, From: fromValue, To: toValue})
+		case keyword == "enum":
+			*changes = append(*changes, SchemaChange{Path: keywordPtr, Change: enumChange(fromValue, toValue, inFrom, inTo), From: fromValue, To: toValue})
+		case keyword == "additionalProperties":
+			*changes = append(*changes, SchemaChange{Path: keywordPtr, Change: additionalPropertiesChange(fromValue, toValue, inFrom, inTo), From: fromValue, To: toValue})
+		case slices.Contains(lowerBoundKeywords, keyword):
+			*changes = append(*changes, SchemaChange{Path: keywordPtr, Change: boundChange(fromValue, toValue, inFrom, inTo, false), From: fromValue, To: toValue})
+		case slices.Contains(upperBoundKeywords, keyword):
+			*changes = append(*changes, SchemaChange{Path: keywordPtr, Change: boundChange(fromValue, toValue, inFrom, inTo, true), From: fromValue, To: toValue})
+		case slices.Contains(restrictingKeywords, keyword):
+			*changes = append(*changes, SchemaChange{Path: keywordPtr, Change: presenceChange(inFrom, inTo), From: fromValue, To: toValue})
+		default:
+			*changes = append(*changes, SchemaChange{Path: keywordPtr, Change: presenceOrChange(inFrom, inTo), From: fromValue, To: toValue})
+		}
+	}
+}
+
+func diffProperties(ptr string, from, to any, changes *[]SchemaChange) {
+	fromProps, _ := from.(map[string]any)
+	toProps, _ := to.(map[string]any)
+	for _, name := range sortedKeys(fromProps, toProps) {
+		fromProp, inFrom := fromProps[name]
+		toProp, inTo := toProps[name]
+		propPtr := ptr + "/" + escapePointerToken(name)
+		switch {
+		case !inFrom:
+			*changes = append(*changes, SchemaChange{Path: propPtr, Change: SchemaChangeAdded, To: toProp})
+		case !inTo:
+			*changes = append(*changes, SchemaChange{Path: propPtr, Change: SchemaChangeRemoved, From: fromProp})
+		default:
+			diffSchemas(propPtr, fromProp, toProp, changes)
+		}
+	}
+}
+
+func diffRequired(ptr string, from, to any, changes *[]SchemaChange) {
+	fromRequired := stringSet(from)
+	toRequired := stringSet(to)
+	for _, name := range sortedKeys(fromRequired, toRequired) {
+		_, inFrom := fromRequired[name]
+		_, inTo := toRequired[name]
+		switch {
+		case !inFrom:
+			*changes = append(*changes, SchemaChange{Path: ptr, Change: SchemaChangeTightened, To: name})
+		case !inTo:
+			*changes = append(*changes, SchemaChange{Path: ptr, Change: SchemaChangeLoosened, From: name})
+		}
+	}
+}
+
+func enumChange(from, to any, inFrom, inTo bool) string {
+	if !inFrom || !inTo {
+		return presenceChange(inFrom, inTo)
+	}
+	fromValues, _ := from.([]any)
+	toValues, _ := to.([]any)
+	switch {
+	case containsAll(fromValues, toValues):
+		return SchemaChangeTightened
+	case containsAll(toValues, fromValues):
+		return SchemaChangeLoosened
+	default:
+		return SchemaChangeChanged
+	}
+}
+
+func additionalPropertiesChange(from, to any, inFrom, inTo bool) string {
+	// an absent additionalProperties allows any property, like true
+	if !inFrom {
+		from = true
+	}
+	if !inTo {
+		to = true
+	}
+	switch {
+	case from == true && to == false:
+		return SchemaChangeTightened
+	case from == false && to == true:
+		return SchemaChangeLoosened
+	default:
+		return SchemaChangeChanged
+	}
+}
+
+func boundChange(from, to any, inFrom, inTo, upper bool) string {
+	if !inFrom || !inTo {
+		return presenceChange(inFrom, inTo)
+	}
+	fromBound, fromOk := from.(float64)
+	toBound, toOk := to.(float64)
+	if !fromOk || !toOk {
+		return SchemaChangeChanged
+	}
+	if (toBound < fromBound) == upper {
+		return SchemaChangeTightened
+	}
+	return SchemaChangeLoosened
+}
+
+// presenceChange classifies a change of a restricting keyword: adding it tightens the schema
+// and removing it loosens the schema.
+func presenceChange(inFrom, inTo bool) string {
+	switch {
+	case !inFrom:
+		return SchemaChangeTightened
+	case !inTo:
+		return SchemaChangeLoosened
+	default:
+		return SchemaChangeChanged
+	}
+}
+
+func presenceOrChange(inFrom, inTo bool) string {
+	switch {
+	case !inFrom:
+		return SchemaChangeAdded
+	case !inTo:
+		return SchemaChangeRemoved
+	default:
+		return SchemaChangeChanged
+	}
+}
+
+func containsAll(values, subset []any) bool {
+	for _, v := range subset {
+		if !slices.ContainsFunc(values, func(e any) bool { return reflect.DeepEqual(e, v) }) {
+			return false
+		}
+	}
+	return true
+}
+
+func stringSet(v any) map[string]any {
+	set := make(map[string]any)
+	values, _ := v.([]any)
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			set[s] = nil
+		}
+	}
+	return set
+}
+
+func sortedKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,123 @@
+package catalogmanager
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffResourceSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected []SchemaChange
+	}{
+		{
+			name:     "identical schemas",
+			from:     `{"type": "integer", "minimum": 1}`,
+			to:       `{"minimum": 1, "type": "integer"}`,
+			expected: []SchemaChange{},
+		},
+		{
+			name: "type change",
+			from: `{"type": "integer"}`,
+			to:   `{"type": "string"}`,
+			expected: []SchemaChange{
+				{Path: "/type", Change: SchemaChangeChanged, From: "integer", To: "string"},
+			},
+		},
+		{
+			name: "default change",
+			from: `{"type": "integer", "default": 10}`,
+			to:   `{"type": "integer", "default": 20}`,
+			expected: []SchemaChange{
+				{Path: "/default", Change: SchemaChangeDefault, From: 10.0, To: 20.0},
+			},
+		},
+		{
+			name: "bounds",
+			from: `{"type": "integer", "minimum": 1, "maximum": 10}`,
+			to:   `{"type": "integer", "minimum": 5, "maximum": 20}`,
+			expected: []SchemaChange{
+				{Path: "/maximum", Change: SchemaChangeLoosened, From: 10.0, To: 20.0},
+				{Path: "/minimum", Change: SchemaChangeTightened, From: 1.0, To: 5.0},
+			},
+		},
+		{
+			name: "added and removed restrictions",
+			from: `{"type": "string", "pattern": "^[a-z]+$"}`,
+			to:   `{"type": "string", "maxLength": 8}`,
+			expected: []SchemaChange{
+				{Path: "/maxLength", Change: SchemaChangeTightened, To: 8.0},
+				{Path: "/pattern", Change: SchemaChangeLoosened, From: "^[a-z]+$"},
+			},
+		},
+		{
+			name: "enum",
+			from: `{"enum": ["a", "b", "c"]}`,
+			to:   `{"enum": ["a", "b"]}`,
+			expected: []SchemaChange{
+				{Path: "/enum", Change: SchemaChangeTightened, From: []any{"a", "b", "c"}, To: []any{"a", "b"}},
+			},
+		},
+		{
+			name: "properties",
+			from: `{
+				"type": "object",
+				"properties": {
+					"host": {"type": "string"},
+					"port": {"type": "integer", "maximum": 65535},
+					"legacy": {"type": "boolean"}
+				},
+				"required": ["host"]
+			}`,
+			to: `{
+				"type": "object",
+				"properties": {
+					"host": {"type": "string"},
+					"port": {"type": "integer", "maximum": 1024},
+					"tls": {"type": "boolean"}
+				},
+				"required": ["host", "port"],
+				"additionalProperties": false
+			}`,
+			expected: []SchemaChange{
+				{Path: "/additionalProperties", Change: SchemaChangeTightened, To: false},
+				{Path: "/properties/legacy", Change: SchemaChangeRemoved, From: map[string]any{"type": "boolean"}},
+				{Path: "/properties/port/maximum", Change: SchemaChangeTightened, From: 65535.0, To: 1024.0},
+				{Path: "/properties/tls", Change: SchemaChangeAdded, To: map[string]any{"type": "boolean"}},
+				{Path: "/required", Change: SchemaChangeTightened, To: "port"},
+			},
+		},
+		{
+			name: "array items",
+			from: `{"type": "array", "items": {"type": "string", "minLength": 2}}`,
+			to:   `{"type": "array", "items": {"type": "string"}}`,
+			expected: []SchemaChange{
+				{Path: "/items/minLength", Change: SchemaChangeLoosened, From: 2.0},
+			},
+		},
+		{
+			name: "property names are escaped",
+			from: `{"properties": {"a/b": {"type": "string"}}}`,
+			to:   `{"properties": {"a/b": {"type": "integer"}}}`,
+			expected: []SchemaChange{
+				{Path: "/properties/a~1b/type", Change: SchemaChangeChanged, From: "string", To: "integer"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := DiffResourceSchemas(json.RawMessage(tt.from), json.RawMessage(tt.to))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, changes)
+		})
+	}
+
+	_, err := DiffResourceSchemas(json.RawMessage(`{`), json.RawMessage(`{}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package catalogmanager
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// envAnnotation names the environment variable the value of a resource is rendered as, e.g.
+// env: MAX_ATTEMPTS.
+const envAnnotation = "env"
+
+// EnvValue is the value of a resource annotated with the name of an environment variable.
+type EnvValue struct {
+	Name     string
+	Resource string
+	Value    json.RawMessage
+}
+
+// ListEnvValues returns the values of the resources of a namespace of the variant at or below
+// dir that are annotated with an environment variable, ordered by variable name. Resources the
+// view may not read are left out, references in values are resolved, and sensitive values are
+// redacted unless the view may reveal them. Two resources annotated with the same variable are
+// an error.
+func ListEnvValues(ctx context.Context, catalog string, variantID uuid.UUID, namespace, dir string) ([]EnvValue, apperrors.Error) {
+	variant, err := db.ReadDB(ctx).GetVariantByID(ctx, variantID)
+	if err != nil {
+		return nil, ErrInvalidVariant
+	}
+	namespaces, err := db.ReadDB(ctx).ListNamespacesByVariant(ctx, variant.VariantID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list namespaces")
+		return nil, ErrUnableToLoadObject.Msg("unable to list namespaces")
+	}
+	namespaceNames := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		if ns.Name != catcommon.DefaultNamespace {
+			namespaceNames[ns.Name] = true
+		}
+	}
+	resources, err := db.ReadDB(ctx).ListResources(ctx, variant.ResourceDirectoryID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list resources")
+		return nil, ErrUnableToLoadObject.Msg("unable to list resources")
+	}
+
+	dir = path.Clean("/" + dir)
+	values := []EnvValue{}
+	resourceOf := make(map[string]string)
+	for _, resource := range resources {
+		m := exportMetadata(catalog, variant.Name, resource.Path, namespaceNames)
+		if m.Namespace.String() != namespace || (dir != "/" && m.Path != dir && !strings.HasPrefix(m.Path, dir+"/")) {
+			continue
+		}
+		rm, err := LoadResourceManagerByHash(ctx, resource.Hash, m)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("path", resource.Path).Msg("failed to load resource")
+			return nil, err
+		}
+		name, _ := rm.(*resourceManager).resource.Spec.Annotations[envAnnotation].(string)
+		if name == "" {
+			continue
+		}
+		allowed, err := policy.CanGetResourceValue(ctx, rm.FullyQualifiedName())
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			continue
+		}
+		if other, ok := resourceOf[name]; ok {
+			return nil, ErrInvalidInput.Msg("variable " + name + " is set by both " + other + " and " + rm.FullyQualifiedName())
+		}
+		resourceOf[name] = rm.FullyQualifiedName()
+
+		value, err := resolvedValueJSON(ctx, rm)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, EnvValue{Name: name, Resource: rm.FullyQualifiedName(), Value: value})
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i].Name < values[j].Name })
+	return values, nil
+}
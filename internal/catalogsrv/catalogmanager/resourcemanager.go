@@ -45,6 +45,8 @@ type ResourceSpec struct {
 	Schema      json.RawMessage        `json:"schema" validate:"required_without=Provider,omitempty"`
 	Value       types.NullableAny      `json:"value" validate:"omitempty"`
 	Annotations interfaces.Annotations `json:"annotations" validate:"omitempty,dive,keys,noSpaces,endkeys"`
+	// Tells users of a deprecated resource what to use instead
+	DeprecationMessage string `json:"deprecationMessage,omitempty"`
 }
 
 // ResourceProvider is a placeholder for the resource provider.
@@ -71,6 +73,9 @@ func (r *Resource) Validate() schemaerr.ValidationErrors {
 	if r.Kind != catcommon.ResourceKind {
 		validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind("kind"))
 	}
+	if r.Spec.DeprecationMessage != "" && !r.Metadata.Deprecated {
+		validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("spec.deprecationMessage", "deprecationMessage requires metadata.deprecated"))
+	}
 
 	err := schemavalidator.V().Struct(r)
 	if err == nil {
@@ -179,6 +184,9 @@ func compileSchema(schema string) (*jsonschema.Schema, error) {
 // resourceManager implements the ResourceManager interface for managing a single resource.
 type resourceManager struct {
 	resource Resource
+	// The stored ciphertext of the value of a sensitive resource and the value it decrypts to
+	encryptedValue []byte
+	decryptedValue []byte
 }
 
 // Metadata returns the resource's metadata.
@@ -192,8 +200,12 @@ func (rm *resourceManager) FullyQualifiedName() string {
 	return path.Clean(m.Path + "/" + m.Name)
 }
 
-// SetValue sets the resource's value after validating it against the schema.
+// SetValue sets the resource's value after validating it against the schema. Setting the
+// redacted placeholder on a sensitive resource keeps its stored value.
 func (rm *resourceManager) SetValue(ctx context.Context, value types.NullableAny) apperrors.Error {
+	if rm.encryptedValue != nil && isRedactedValue(value) {
+		return nil
+	}
 	// validate the value against the schema
 	if err := rm.resource.ValidateValue(value); err != nil {
 		return ErrInvalidResourceValue.Msg(err.Error())
@@ -202,6 +214,19 @@ func (rm *resourceManager) SetValue(ctx context.Context, value types.NullableAny
 	return nil
 }
 
+// DeprecationWarning returns the warning to give users of the resource if it is deprecated,
+// or an empty string otherwise.
+func (rm *resourceManager) DeprecationWarning() string {
+	if !rm.resource.Metadata.Deprecated {
+		return ""
+	}
+	warning := "resource " + rm.FullyQualifiedName() + " is deprecated"
+	if msg := rm.resource.Spec.DeprecationMessage; msg != "" {
+		warning += ": " + msg
+	}
+	return warning
+}
+
 // GetValue returns the resource's current value.
 func (rm *resourceManager) GetValue(ctx context.Context) types.NullableAny {
 	return rm.resource.Spec.Value
@@ -224,6 +249,9 @@ func (rm *resourceManager) StorageRepresentation() *objectstore.ObjectStorageRep
 	}
 	s.Spec, _ = json.Marshal(rm.resource.Spec)
 	s.Description = rm.resource.Metadata.Description
+	s.Labels = rm.resource.Metadata.Labels
+	s.Annotations = rm.resource.Metadata.Annotations
+	s.Deprecated = rm.resource.Metadata.Deprecated
 	s.Entropy = rm.resource.Metadata.GetEntropyBytes(catcommon.CatalogObjectTypeResource)
 	return &s
 }
@@ -242,6 +270,20 @@ func getResourceStoragePath(m *interfaces.Metadata) string {
 	return pathWithName
 }
 
+// catalogID returns the ID of the catalog of the resource.
+func (rm *resourceManager) catalogID(ctx context.Context) (uuid.UUID, apperrors.Error) {
+	if catalogID := catcommon.GetCatalogID(ctx); catalogID != uuid.Nil {
+		return catalogID, nil
+	}
+	m := rm.Metadata()
+	catalogID, err := db.DB(ctx).GetCatalogIDByName(ctx, m.Catalog)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("catalog", m.Catalog).Msg("Failed to get catalog ID by name")
+		return uuid.Nil, err
+	}
+	return catalogID, nil
+}
+
 // Save saves the resource to the database.
 // It handles the creation or update of both the resource and its associated catalog object.
 func (rm *resourceManager) Save(ctx context.Context) apperrors.Error {
@@ -252,8 +294,28 @@ func (rm *resourceManager) Save(ctx context.Context) apperrors.Error {
 	t := catcommon.CatalogObjectTypeResource
 
 	m := rm.Metadata()
+
+	// Get the directory ID for the resource
+	catalogID, err := rm.catalogID(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Admission webhooks may reject the write or change the resource before it is stored.
+	// Writes made in a transaction call them before the transaction starts.
+	if !isAdmitted(ctx) {
+		if err := rm.admit(ctx, catalogID); err != nil {
+			return err
+		}
+	}
+
 	s := rm.StorageRepresentation()
 	storagePath := rm.GetStoragePath()
+	spec, err := rm.storedSpec(ctx)
+	if err != nil {
+		return err
+	}
+	s.Spec = spec
 
 	data, err := s.Serialize()
 	if err != nil {
@@ -264,21 +326,11 @@ func (rm *resourceManager) Save(ctx context.Context) apperrors.Error {
 
 	// Store this object and update the reference
 	obj := models.CatalogObject{
-		Type:    t,
-		Hash:    newHash,
-		Data:    data,
-		Version: rm.resource.ApiVersion,
-	}
-
-	// Get the directory ID for the resource
-	catalogID := catcommon.GetCatalogID(ctx)
-	if catalogID == uuid.Nil {
-		var err apperrors.Error
-		catalogID, err = db.DB(ctx).GetCatalogIDByName(ctx, m.Catalog)
-		if err != nil {
-			log.Ctx(ctx).Error().Err(err).Str("catalog", m.Catalog).Msg("Failed to get catalog ID by name")
-			return err
-		}
+		Type:       t,
+		Hash:       newHash,
+		Data:       data,
+		Version:    rm.resource.ApiVersion,
+		SearchText: s.SearchText(),
 	}
 
 	variant, err := db.DB(ctx).GetVariant(ctx, catalogID, uuid.Nil, m.Variant.String())
@@ -294,8 +346,10 @@ func (rm *resourceManager) Save(ctx context.Context) apperrors.Error {
 		VariantID: variant.VariantID,
 	}
 
-	// Store the object
-	err = db.DB(ctx).UpsertResourceObject(ctx, rsrc, &obj, variant.ResourceDirectoryID)
+	// Store the object and its directory entry together, so that a failure leaves neither behind
+	err = db.Tx(ctx, func(ctx context.Context) apperrors.Error {
+		return db.DB(ctx).UpsertResourceObject(ctx, rsrc, &obj, variant.ResourceDirectoryID)
+	})
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Str("path", storagePath).Msg("Failed to store object")
 		return err
@@ -329,27 +383,28 @@ func DeleteResource(ctx context.Context, m *interfaces.Metadata) apperrors.Error
 
 	pathWithName := path.Clean(m.GetStoragePath(catcommon.CatalogObjectTypeResource) + "/" + m.Name)
 
-	// Delete the resource
-	hash, err := db.DB(ctx).DeleteResource(ctx, pathWithName, variant.ResourceDirectoryID)
-	if err != nil {
-		if errors.Is(err, dberror.ErrNotFound) {
-			return ErrObjectNotFound
+	// Delete the directory entry and the object it references together
+	return db.Tx(ctx, func(ctx context.Context) apperrors.Error {
+		hash, err := db.DB(ctx).DeleteResource(ctx, pathWithName, variant.ResourceDirectoryID)
+		if err != nil {
+			if errors.Is(err, dberror.ErrNotFound) {
+				return ErrObjectNotFound
+			}
+			log.Ctx(ctx).Error().Err(err).Str("path", pathWithName).Msg("Failed to delete object")
+			return err
 		}
-		log.Ctx(ctx).Error().Err(err).Str("path", pathWithName).Msg("Failed to delete object")
-		return err
-	}
 
-	if hash != "" {
+		if hash == "" {
+			log.Ctx(ctx).Warn().Str("path", pathWithName).Msg("resource not found")
+			return ErrObjectNotFound
+		}
 		err = db.DB(ctx).DeleteCatalogObject(ctx, catcommon.CatalogObjectTypeResource, hash)
-		if !errors.Is(err, dberror.ErrNotFound) {
-			log.Ctx(ctx).Error().Err(err).Str("hash", string(hash)).Msg("failed to delete object from database")
+		if err != nil && !errors.Is(err, dberror.ErrNotFound) {
+			log.Ctx(ctx).Error().Err(err).Str("hash", hash).Msg("failed to delete object from database")
+			return err
 		}
-	} else {
-		log.Ctx(ctx).Warn().Str("path", pathWithName).Msg("resource not found")
-		return ErrObjectNotFound
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // JSON returns the JSON representation of the resource.
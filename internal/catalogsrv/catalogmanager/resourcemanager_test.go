@@ -110,6 +110,42 @@ func TestResourceValidation(t *testing.T) {
 			expectedError: true,
 			errorTypes:    []string{"invalid name"},
 		},
+		{
+			name: "deprecated resource with message",
+			jsonInput: `{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "Resource",
+				"metadata": {
+					"name": "test-resource",
+					"catalog": "test-catalog",
+					"deprecated": true
+				},
+				"spec": {
+					"schema": {"type": "integer"},
+					"value": 42,
+					"deprecationMessage": "use other-resource instead"
+				}
+			}`,
+			expectedError: false,
+		},
+		{
+			name: "deprecation message without deprecated",
+			jsonInput: `{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "Resource",
+				"metadata": {
+					"name": "test-resource",
+					"catalog": "test-catalog"
+				},
+				"spec": {
+					"schema": {"type": "integer"},
+					"value": 42,
+					"deprecationMessage": "use other-resource instead"
+				}
+			}`,
+			expectedError: true,
+			errorTypes:    []string{"requires metadata.deprecated"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,172 @@
+package catalogmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tidwall/gjson"
+)
+
+// valueRefRegex matches references to the values of other resources in string values, e.g.
+// {{ref:/shared/endpoint}} or {{ref:/shared/db#host}}. The part after # is a path into the
+// referenced value. Paths without a leading slash are relative to the referencing resource.
+var valueRefRegex = regexp.MustCompile(`\{\{\s*ref:([^#}\s]+)(?:#([^}\s]+))?\s*\}\}`)
+
+// resolvedValueJSON returns the JSON representation of the value of the resource as it is
+// read: references to other resources of the same variant and namespace are replaced by
+// their values, and sensitive values are redacted unless the caller may reveal them. A string
+// that is only a reference takes the referenced value whatever its type; references within a
+// longer string are replaced by the text of the value.
+func resolvedValueJSON(ctx context.Context, rm ResourceManager) ([]byte, apperrors.Error) {
+	return resolveValueRefs(ctx, rm, nil)
+}
+
+// resolveValueRefs resolves the references in the value of rm. chain holds the resources
+// whose values are being resolved, so that a reference back to one of them is reported as a
+// cycle instead of being followed.
+func resolveValueRefs(ctx context.Context, rm ResourceManager, chain []string) ([]byte, apperrors.Error) {
+	value, err := readableValueJSON(ctx, rm)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Contains(value, []byte("{{")) {
+		return value, nil
+	}
+
+	var v any
+	if err := json.Unmarshal(value, &v); err != nil {
+		return nil, ErrInvalidResourceValue.Msg("unable to read resource value")
+	}
+	chain = append(chain, rm.FullyQualifiedName())
+	resolved, err := resolveRefsIn(ctx, rm.Metadata(), v, chain)
+	if err != nil {
+		return nil, err
+	}
+	j, goErr := json.Marshal(resolved)
+	if goErr != nil {
+		log.Ctx(ctx).Error().Err(goErr).Msg("failed to marshal resolved value")
+		return nil, ErrInvalidResourceValue.Msg("unable to obtain resource value")
+	}
+	return j, nil
+}
+
+// resolveRefsIn resolves the references in the strings of v.
+func resolveRefsIn(ctx context.Context, m interfaces.Metadata, v any, chain []string) (any, apperrors.Error) {
+	switch t := v.(type) {
+	case string:
+		return resolveRefString(ctx, m, t, chain)
+	case []any:
+		for i := range t {
+			resolved, err := resolveRefsIn(ctx, m, t[i], chain)
+			if err != nil {
+				return nil, err
+			}
+			t[i] = resolved
+		}
+		return t, nil
+	case map[string]any:
+		for k := range t {
+			resolved, err := resolveRefsIn(ctx, m, t[k], chain)
+			if err != nil {
+				return nil, err
+			}
+			t[k] = resolved
+		}
+		return t, nil
+	default:
+		return v, nil
+	}
+}
+
+func resolveRefString(ctx context.Context, m interfaces.Metadata, s string, chain []string) (any, apperrors.Error) {
+	matches := valueRefRegex.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, nil
+	}
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(s) {
+		raw, err := loadReferencedValue(ctx, m, s, matches[0], chain)
+		if err != nil {
+			return nil, err
+		}
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, ErrInvalidResourceValue.Msg("unable to read referenced value")
+		}
+		return v, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, match := range matches {
+		raw, err := loadReferencedValue(ctx, m, s, match, chain)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(s[last:match[0]])
+		if r := gjson.ParseBytes(raw); r.Type == gjson.String {
+			b.WriteString(r.String())
+		} else {
+			b.Write(raw)
+		}
+		last = match[1]
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+// loadReferencedValue returns the resolved value of the reference in s at match, a submatch
+// index of valueRefRegex.
+func loadReferencedValue(ctx context.Context, m interfaces.Metadata, s string, match []int, chain []string) ([]byte, apperrors.Error) {
+	ref := s[match[2]:match[3]]
+	var field string
+	if match[4] >= 0 {
+		field = s[match[4]:match[5]]
+	}
+	refPath := ref
+	if !strings.HasPrefix(refPath, "/") {
+		refPath = path.Join("/", m.Path, refPath)
+	}
+	refPath = path.Clean(refPath)
+
+	if slices.Contains(chain, refPath) {
+		return nil, ErrInvalidResourceValue.Msg("reference cycle: " + strings.Join(append(chain, refPath), " -> "))
+	}
+	allowed, err := policy.CanGetResourceValue(ctx, refPath)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, ErrDisallowedByPolicy.Msg("not allowed to read referenced resource " + refPath)
+	}
+
+	refMetadata := m
+	refMetadata.Path, refMetadata.Name = path.Dir(refPath), path.Base(refPath)
+	rm, err := LoadResourceManagerByPath(ctx, &refMetadata)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrInvalidResourceValue.Msg("referenced resource not found: " + refPath)
+		}
+		return nil, err
+	}
+	value, err := resolveValueRefs(ctx, rm, chain)
+	if err != nil {
+		return nil, err
+	}
+	if field == "" {
+		return value, nil
+	}
+	r := gjson.GetBytes(value, field)
+	if !r.Exists() {
+		return nil, ErrInvalidResourceValue.Msg("referenced field not found: " + refPath + "#" + field)
+	}
+	return []byte(r.Raw), nil
+}
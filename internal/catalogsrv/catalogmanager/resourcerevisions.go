@@ -0,0 +1,185 @@
+package catalogmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// ResourceRevision is a revision of a resource. Revisions are identified by the hash of the
+// stored resource; every save of a changed definition or value creates a new revision.
+// UpdatedBy is the principal that saved the revision, such as user/<id>, and Value is the
+// value of the resource in the revision, so consecutive revisions show how the value changed.
+type ResourceRevision struct {
+	Hash       string          `json:"hash"`
+	Current    bool            `json:"current"`
+	UpdatedBy  string          `json:"updatedBy,omitempty"`
+	UpdatedAt  *time.Time      `json:"updatedAt,omitempty"`
+	ReplacedAt *time.Time      `json:"replacedAt,omitempty"`
+	Value      json.RawMessage `json:"value,omitempty"`
+}
+
+// ListResourceRevisions returns the revisions of the resource described by m with their values,
+// the current revision first and then the previous revisions from newest to oldest. Values of
+// sensitive revisions are redacted unless the caller may reveal them.
+func ListResourceRevisions(ctx context.Context, m *interfaces.Metadata) ([]ResourceRevision, apperrors.Error) {
+	revisions, err := listResourceRevisions(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		rm, err := LoadResourceManagerByHash(ctx, revisions[i].Hash, m)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("hash", revisions[i].Hash).Msg("Failed to load resource revision")
+			return nil, err
+		}
+		value, err := readableValueJSON(ctx, rm)
+		if err != nil {
+			return nil, err
+		}
+		revisions[i].Value = value
+	}
+	return revisions, nil
+}
+
+func listResourceRevisions(ctx context.Context, m *interfaces.Metadata) ([]ResourceRevision, apperrors.Error) {
+	if m == nil {
+		return nil, ErrInvalidObject.Msg("unable to infer object metadata")
+	}
+	directoryID, err := resourceDirectoryID(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+
+	pathWithName := getResourceStoragePath(m)
+	objRef, err := db.ReadDB(ctx).GetObjectRefByPath(ctx, catcommon.CatalogObjectTypeResource, directoryID, pathWithName)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []ResourceRevision{{
+		Hash:      objRef.Hash,
+		Current:   true,
+		UpdatedBy: objRef.UpdatedBy,
+		UpdatedAt: objRef.UpdatedAt,
+	}}
+	for _, previous := range objRef.History {
+		replacedAt := previous.ReplacedAt
+		revisions = append(revisions, ResourceRevision{
+			Hash:       previous.Hash,
+			UpdatedBy:  previous.UpdatedBy,
+			UpdatedAt:  previous.UpdatedAt,
+			ReplacedAt: &replacedAt,
+		})
+	}
+	return revisions, nil
+}
+
+// RestoreResourceRevision makes a previous revision of the resource described by m its current
+// revision. The restored resource is validated again before it is saved, and the revision it
+// replaces is kept in the history like on any other save.
+func RestoreResourceRevision(ctx context.Context, m *interfaces.Metadata, hash string) apperrors.Error {
+	if m == nil {
+		return ErrInvalidObject.Msg("unable to infer object metadata")
+	}
+	if hash == "" {
+		return ErrInvalidInput.Msg("revision is required")
+	}
+
+	rm, err := loadPreviousResourceRevision(ctx, m, hash)
+	if err != nil {
+		return err
+	}
+	if validationErrs := rm.resource.Validate(); validationErrs != nil {
+		return ErrSchemaValidation.Msg(validationErrs.Error())
+	}
+	return rm.Save(ctx)
+}
+
+// RestoreResourceValue sets the value of the resource described by m to its value in a
+// previous revision, keeping the current definition. The old value is validated against the
+// current schema before it is saved.
+func RestoreResourceValue(ctx context.Context, m *interfaces.Metadata, hash string) apperrors.Error {
+	if m == nil {
+		return ErrInvalidObject.Msg("unable to infer object metadata")
+	}
+	if hash == "" {
+		return ErrInvalidInput.Msg("revision is required")
+	}
+
+	previous, err := loadPreviousResourceRevision(ctx, m, hash)
+	if err != nil {
+		return err
+	}
+	rm, err := LoadResourceManagerByPath(ctx, m)
+	if err != nil {
+		return err
+	}
+	current := rm.(*resourceManager)
+
+	currentValue, _ := json.Marshal(current.resource.Spec.Value)
+	previousValue, _ := json.Marshal(previous.resource.Spec.Value)
+	if bytes.Equal(currentValue, previousValue) {
+		return ErrEqualToExistingObject.Msg("value of the revision is already the current value")
+	}
+	if err := current.SetValue(ctx, previous.resource.Spec.Value); err != nil {
+		return err
+	}
+	return current.Save(ctx)
+}
+
+// loadPreviousResourceRevision loads a previous revision of the resource described by m. It
+// returns ErrEqualToExistingObject if the hash is the current revision and ErrObjectNotFound
+// if it is not a revision of the resource.
+func loadPreviousResourceRevision(ctx context.Context, m *interfaces.Metadata, hash string) (*resourceManager, apperrors.Error) {
+	revisions, err := listResourceRevisions(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	if revisions[0].Hash == hash {
+		return nil, ErrEqualToExistingObject.Msg("revision is already the current revision")
+	}
+	found := false
+	for _, revision := range revisions[1:] {
+		if revision.Hash == hash {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, ErrObjectNotFound.Msg("revision not found")
+	}
+
+	rm, err := LoadResourceManagerByHash(ctx, hash, m)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("hash", hash).Msg("Failed to load resource revision")
+		return nil, err
+	}
+	return rm.(*resourceManager), nil
+}
+
+// resourceDirectoryID returns the ID of the resource directory of the variant of m.
+func resourceDirectoryID(ctx context.Context, m *interfaces.Metadata) (uuid.UUID, apperrors.Error) {
+	catalogID := catcommon.GetCatalogID(ctx)
+	if catalogID == uuid.Nil {
+		var err apperrors.Error
+		if catalogID, err = db.ReadDB(ctx).GetCatalogIDByName(ctx, m.Catalog); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("catalog", m.Catalog).Msg("Failed to get catalog ID by name")
+			return uuid.Nil, err
+		}
+	}
+	variant, err := db.ReadDB(ctx).GetVariant(ctx, catalogID, uuid.Nil, m.Variant.String())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("catalogID", catalogID.String()).Str("name", m.Name).Msg("Failed to get variant")
+		return uuid.Nil, err
+	}
+	return variant.ResourceDirectoryID, nil
+}
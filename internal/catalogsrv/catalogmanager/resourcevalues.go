@@ -0,0 +1,160 @@
+package catalogmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/objectstore"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/pkg/types"
+)
+
+// ResourceValueUpdate is the new value of a resource in an update of several resources.
+type ResourceValueUpdate struct {
+	Metadata *interfaces.Metadata
+	Value    types.NullableAny
+}
+
+// UpdateResourceValues sets the values of several resources together. Every value is
+// validated against the schema of its resource and passed to the admission webhooks before any
+// is saved, and the values are saved in a single transaction, so either all resources are
+// updated or none is. Errors are
+// prefixed with the path of the resource they concern.
+func UpdateResourceValues(ctx context.Context, updates []ResourceValueUpdate) apperrors.Error {
+	if len(updates) == 0 {
+		return ErrInvalidInput.Msg("no resource values to update")
+	}
+
+	managers := make([]ResourceManager, 0, len(updates))
+	seen := make(map[string]bool, len(updates))
+	for _, update := range updates {
+		m := update.Metadata
+		if m == nil {
+			return ErrInvalidObject.Msg("unable to infer object metadata")
+		}
+		resourcePath := path.Join(m.Path, m.Name)
+		if err := m.Validate(); err != nil {
+			return ErrSchemaValidation.Msg(err.Error()).Prefix(resourcePath)
+		}
+		storagePath := path.Join(m.GetStoragePath(catcommon.CatalogObjectTypeResource), m.Name)
+		if seen[storagePath] {
+			return ErrInvalidInput.Msg("resource is updated more than once").Prefix(resourcePath)
+		}
+		seen[storagePath] = true
+
+		rm, err := LoadResourceManagerByPath(ctx, m)
+		if err != nil {
+			return err.Prefix(resourcePath)
+		}
+		if err := rm.SetValue(ctx, update.Value); err != nil {
+			return err.Prefix(resourcePath)
+		}
+		managers = append(managers, rm)
+	}
+
+	// Admission webhooks are called before the transaction starts
+	for i, rm := range managers {
+		if err := rm.(*resourceManager).admitWrite(ctx); err != nil {
+			m := updates[i].Metadata
+			return err.Prefix(path.Join(m.Path, m.Name))
+		}
+	}
+
+	return db.Tx(withAdmitted(ctx), func(ctx context.Context) apperrors.Error {
+		for i, rm := range managers {
+			if err := rm.Save(ctx); err != nil {
+				m := updates[i].Metadata
+				return err.Prefix(path.Join(m.Path, m.Name))
+			}
+		}
+		return nil
+	})
+}
+
+// ResolvedResourceValue is the value of a resource resolved along its path. Chain lists the
+// resources that were consulted, starting with the resource itself, and ResolvedFrom is the
+// one the value was taken from. ResolvedFrom is empty if none of them has a value.
+type ResolvedResourceValue struct {
+	Value        json.RawMessage `json:"value"`
+	ResolvedFrom string          `json:"resolvedFrom,omitempty"`
+	Chain        []string        `json:"chain"`
+}
+
+// ResolveResourceValue returns the value of the resource described by m. If the resource has
+// no value, the value is inherited from the nearest resource with the same name and schema at
+// an ancestor path, so /prod/db/max-connections falls back to /prod/max-connections and then
+// to /max-connections. Ancestors the view may not read are skipped.
+func ResolveResourceValue(ctx context.Context, m *interfaces.Metadata) (*ResolvedResourceValue, apperrors.Error) {
+	if m == nil {
+		return nil, ErrInvalidObject.Msg("unable to infer object metadata")
+	}
+	rm, err := LoadResourceManagerByPath(ctx, m)
+	if err != nil {
+		return nil, err
+	}
+	schema, goErr := objectstore.NormalizeJSON(rm.(*resourceManager).resource.Spec.Schema)
+	if goErr != nil {
+		return nil, ErrUnableToLoadObject.Msg("unable to read resource schema")
+	}
+
+	resolved := &ResolvedResourceValue{}
+	dir := path.Clean("/" + m.Path)
+	for {
+		if rm != nil {
+			resourcePath := path.Join(dir, m.Name)
+			resolved.Chain = append(resolved.Chain, resourcePath)
+			if !rm.GetValue(ctx).IsNil() {
+				value, err := resolvedValueJSON(ctx, rm)
+				if err != nil {
+					return nil, err
+				}
+				resolved.Value = value
+				resolved.ResolvedFrom = resourcePath
+				return resolved, nil
+			}
+		}
+		if dir == "/" {
+			break
+		}
+		dir = path.Dir(dir)
+		rm, err = loadAncestorResource(ctx, m, dir, schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resolved.Value = json.RawMessage("null")
+	return resolved, nil
+}
+
+// loadAncestorResource loads the resource with the name of m at the ancestor path dir. It
+// returns nil if there is no such resource, its schema differs from schema, or the view may
+// not read it.
+func loadAncestorResource(ctx context.Context, m *interfaces.Metadata, dir string, schema []byte) (ResourceManager, apperrors.Error) {
+	ancestor := *m
+	ancestor.Path = dir
+	allowed, err := policy.CanGetResourceValue(ctx, path.Join(dir, m.Name))
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, nil
+	}
+	rm, err := LoadResourceManagerByPath(ctx, &ancestor)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	ancestorSchema, goErr := objectstore.NormalizeJSON(rm.(*resourceManager).resource.Spec.Schema)
+	if goErr != nil || !bytes.Equal(ancestorSchema, schema) {
+		return nil, nil
+	}
+	return rm, nil
+}
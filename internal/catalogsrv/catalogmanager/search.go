@@ -0,0 +1,164 @@
+package catalogmanager
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/objectstore"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// searchCandidateLimit is the number of hits fetched from the database before hits the view
+// cannot see are removed.
+const searchCandidateLimit = 1000
+
+// searchIndexBatchSize is the number of catalog objects indexed at a time by the backfill.
+const searchIndexBatchSize = 100
+
+// SearchHit is an object of the catalog that matches a search. Resources and skillsets are
+// identified by their namespace, path and name, as in their metadata. Resource is the canonical
+// resource URI of the object.
+type SearchHit struct {
+	Kind        string                `json:"kind"`
+	Catalog     string                `json:"catalog"`
+	Variant     string                `json:"variant,omitempty"`
+	Namespace   string                `json:"namespace,omitempty"`
+	Path        string                `json:"path,omitempty"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Resource    policy.TargetResource `json:"resource"`
+	Rank        float32               `json:"rank"`
+}
+
+// SearchCatalog searches the names, descriptions, annotations and schema property names of the
+// objects of the catalog in the context, or of the variant in the context if there is one. Only
+// objects on which the view in the context grants some action are returned.
+func SearchCatalog(ctx context.Context, opts interfaces.SearchOptions) ([]SearchHit, apperrors.Error) {
+	catalog := catcommon.GetCatalog(ctx)
+	catalogID := catcommon.GetCatalogID(ctx)
+	if catalogID == uuid.Nil {
+		return nil, ErrInvalidCatalog
+	}
+
+	dbHits, err := db.DB(ctx).SearchCatalog(ctx, catalogID, catcommon.GetVariantID(ctx), opts.Terms, opts.Kinds, searchCandidateLimit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to search catalog")
+		return nil, ErrUnableToLoadObject.Msg("unable to search catalog")
+	}
+
+	catalogURI := "res://catalogs/" + catalog
+	namespaceNames := make(map[uuid.UUID]map[string]bool)
+	hits := make([]SearchHit, 0, len(dbHits))
+	objects := make([]policy.CatalogObject, 0, len(dbHits))
+	for _, h := range dbHits {
+		hit := SearchHit{
+			Kind:        h.Kind,
+			Catalog:     catalog,
+			Variant:     h.Variant,
+			Name:        h.Name,
+			Description: h.Description,
+			Rank:        h.Rank,
+		}
+		variantURI := catalogURI + "/variants/" + h.Variant
+
+		var obj policy.CatalogObject
+		switch h.Kind {
+		case catcommon.CatalogKind:
+			obj = policy.CatalogObject{Kind: catcommon.KindNameCatalogs, Resource: policy.TargetResource(catalogURI)}
+		case catcommon.VariantKind:
+			obj = policy.CatalogObject{Kind: catcommon.KindNameVariants, Resource: policy.TargetResource(variantURI)}
+		case catcommon.NamespaceKind:
+			obj = policy.CatalogObject{Kind: catcommon.KindNameNamespaces, Resource: policy.TargetResource(variantURI + "/namespaces/" + h.Name)}
+		case catcommon.ResourceKind, catcommon.SkillSetKind:
+			names, ok := namespaceNames[h.VariantID]
+			if !ok {
+				namespaces, err := db.DB(ctx).ListNamespacesByVariant(ctx, h.VariantID)
+				if err != nil {
+					log.Ctx(ctx).Error().Err(err).Msg("failed to list namespaces")
+					return nil, ErrUnableToLoadObject.Msg("unable to list namespaces")
+				}
+				names = make(map[string]bool, len(namespaces))
+				for _, namespace := range namespaces {
+					names[namespace.Name] = true
+				}
+				namespaceNames[h.VariantID] = names
+			}
+			m := exportMetadata(catalog, h.Variant, h.Path, names)
+			hit.Name = m.Name
+			hit.Path = m.Path
+			if m.Namespace.Valid {
+				hit.Namespace = m.Namespace.String()
+			}
+			kindName := catcommon.KindNameResources
+			if h.Kind == catcommon.SkillSetKind {
+				kindName = catcommon.KindNameSkillsets
+			}
+			obj = catalogObjectFromMetadata(kindName, variantURI, m)
+		default:
+			continue
+		}
+		hit.Resource = obj.Resource
+		hits = append(hits, hit)
+		objects = append(objects, obj)
+	}
+
+	visible := make(map[policy.TargetResource]bool)
+	for _, p := range policy.EffectivePermissions(policy.GetViewDefinition(ctx), objects) {
+		visible[p.Resource] = true
+	}
+
+	result := make([]SearchHit, 0, opts.Limit)
+	for _, hit := range hits {
+		if len(result) == opts.Limit {
+			break
+		}
+		if visible[hit.Resource] {
+			result = append(result, hit)
+		}
+	}
+	return result, nil
+}
+
+// RunSearchIndexBackfill indexes the catalog objects that were stored before catalog search was
+// introduced, until none are left or the context is done.
+func RunSearchIndexBackfill(ctx context.Context) {
+	total := 0
+	for ctx.Err() == nil {
+		n, err := indexCatalogObjects(ctx)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("search index backfill: unable to index catalog objects")
+			return
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+	}
+	if total > 0 {
+		log.Ctx(ctx).Info().Int("objects", total).Msg("indexed catalog objects for search")
+	}
+}
+
+func indexCatalogObjects(ctx context.Context) (int, error) {
+	dbCtx, err := db.ConnCtx(db.WithAllTenants(ctx))
+	if err != nil {
+		return 0, err
+	}
+	defer db.DB(dbCtx).Close(dbCtx)
+
+	objs, dbErr := db.DB(dbCtx).ListUnindexedCatalogObjects(dbCtx, searchIndexBatchSize)
+	if dbErr != nil {
+		return 0, dbErr
+	}
+	for _, obj := range objs {
+		if dbErr := db.DB(dbCtx).SetCatalogObjectSearchText(dbCtx, obj.ID, objectstore.SearchText(obj.Data)); dbErr != nil {
+			return 0, dbErr
+		}
+	}
+	return len(objs), nil
+}
@@ -0,0 +1,167 @@
+package catalogmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/pkg/types"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// sensitiveAnnotation marks a resource whose value is a secret, such as a credential. The value
+// of a sensitive resource is stored encrypted and is redacted when read without the
+// system.resource.reveal action.
+const sensitiveAnnotation = "sensitive"
+
+// redactedValue replaces the value of a sensitive resource the caller may not reveal.
+var redactedValue = []byte(`"<redacted>"`)
+
+// isRedactedValue reports whether value is the placeholder of a redacted value.
+func isRedactedValue(value types.NullableAny) bool {
+	j, err := json.Marshal(value)
+	return err == nil && bytes.Equal(j, redactedValue)
+}
+
+// storedResourceSpec is the spec of a resource as it is stored. The value of a sensitive
+// resource is kept in EncryptedValue instead of Value.
+type storedResourceSpec struct {
+	ResourceSpec
+	EncryptedValue []byte `json:"encryptedValue,omitempty"`
+}
+
+// isSensitive reports whether the resource is annotated as sensitive.
+func (r *Resource) isSensitive() bool {
+	switch v := r.Spec.Annotations[sensitiveAnnotation].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	default:
+		return false
+	}
+}
+
+// storedSpec returns the spec of the resource to store, with the value encrypted if the
+// resource is sensitive. An unchanged value keeps the ciphertext it was loaded with, so saving
+// the resource again does not change its hash. The redacted placeholder is never stored as the
+// value of a sensitive resource.
+func (rm *resourceManager) storedSpec(ctx context.Context) (json.RawMessage, apperrors.Error) {
+	stored := storedResourceSpec{ResourceSpec: rm.resource.Spec}
+	if rm.resource.isSensitive() && !rm.resource.Spec.Value.IsNil() {
+		value, err := json.Marshal(rm.resource.Spec.Value)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to marshal resource value")
+			return nil, ErrInvalidResourceValue
+		}
+		if rm.encryptedValue != nil && bytes.Equal(value, rm.decryptedValue) {
+			stored.EncryptedValue = rm.encryptedValue
+		} else if bytes.Equal(value, redactedValue) {
+			return nil, ErrInvalidResourceValue.Msg("the redacted placeholder cannot be stored as the value of a sensitive resource")
+		} else {
+			stored.EncryptedValue, err = catcommon.Encrypt(value, config.Config().Auth.KeyEncryptionPasswd)
+			if err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("failed to encrypt resource value")
+				return nil, ErrUnableToUpdateObject.Msg("unable to encrypt resource value")
+			}
+		}
+		stored.Value = types.NullableAny{}
+	}
+	spec, err := json.Marshal(stored)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to marshal resource spec")
+		return nil, ErrSchemaSerialization
+	}
+	return spec, nil
+}
+
+// loadStoredSpec sets the spec of the resource from its stored form, decrypting the value of a
+// sensitive resource.
+func (rm *resourceManager) loadStoredSpec(ctx context.Context, spec json.RawMessage) apperrors.Error {
+	var stored storedResourceSpec
+	if err := json.Unmarshal(spec, &stored); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("Failed to unmarshal resource schema spec")
+		return ErrUnableToLoadObject
+	}
+	rm.resource.Spec = stored.ResourceSpec
+	if stored.EncryptedValue == nil {
+		return nil
+	}
+
+	value, err := catcommon.Decrypt(stored.EncryptedValue, config.Config().Auth.KeyEncryptionPasswd)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to decrypt resource value")
+		return ErrUnableToLoadObject.Msg("unable to decrypt resource value")
+	}
+	if err := json.Unmarshal(value, &rm.resource.Spec.Value); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to unmarshal resource value")
+		return ErrUnableToLoadObject
+	}
+	rm.encryptedValue = stored.EncryptedValue
+	rm.decryptedValue = value
+	return nil
+}
+
+// restoreRedactedValue replaces the redacted placeholder in the definition rsrcJSON with the
+// stored value of existing, so that a definition read without the reveal action can be written
+// back without overwriting the secret.
+func restoreRedactedValue(ctx context.Context, rsrcJSON []byte, existing ResourceManager) ([]byte, apperrors.Error) {
+	r, ok := existing.(*resourceManager)
+	if !ok || r.encryptedValue == nil || gjson.GetBytes(rsrcJSON, "spec.value").Raw != string(redactedValue) {
+		return rsrcJSON, nil
+	}
+	rsrcJSON, err := sjson.SetRawBytes(rsrcJSON, "spec.value", r.decryptedValue)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to restore redacted resource value")
+		return nil, ErrInvalidResourceValue
+	}
+	return rsrcJSON, nil
+}
+
+// mustRedactValue reports whether the value of the resource must be redacted for the caller,
+// i.e. whether the resource is sensitive and the view does not allow revealing it.
+func (rm *resourceManager) mustRedactValue(ctx context.Context) bool {
+	if !rm.resource.isSensitive() {
+		return false
+	}
+	allowed, err := policy.CanRevealResourceValue(ctx, rm.FullyQualifiedName())
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("resource", rm.FullyQualifiedName()).Msg("unable to check access to sensitive value")
+		return true
+	}
+	return !allowed
+}
+
+// readableResourceJSON returns the JSON representation of the resource, with the value
+// redacted if the caller may not reveal it.
+func readableResourceJSON(ctx context.Context, rm ResourceManager) ([]byte, apperrors.Error) {
+	r, ok := rm.(*resourceManager)
+	if !ok || !r.mustRedactValue(ctx) {
+		return rm.JSON(ctx)
+	}
+	j, err := rm.JSON(ctx)
+	if err != nil {
+		return nil, err
+	}
+	j, goErr := sjson.SetRawBytes(j, "spec.value", redactedValue)
+	if goErr != nil {
+		log.Ctx(ctx).Error().Err(goErr).Msg("failed to redact resource value")
+		return nil, ErrUnableToLoadObject
+	}
+	return j, nil
+}
+
+// readableValueJSON returns the JSON representation of the value of the resource, redacted if
+// the caller may not reveal it.
+func readableValueJSON(ctx context.Context, rm ResourceManager) ([]byte, apperrors.Error) {
+	if r, ok := rm.(*resourceManager); ok && r.mustRedactValue(ctx) {
+		return redactedValue, nil
+	}
+	return rm.GetValueJSON(ctx)
+}
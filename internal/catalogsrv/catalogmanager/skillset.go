@@ -130,14 +130,14 @@ func LoadSkillSetManagerByPath(ctx context.Context, m *interfaces.Metadata) (Ski
 	var err apperrors.Error
 
 	if catalogID == uuid.Nil {
-		catalogID, err = db.DB(ctx).GetCatalogIDByName(ctx, m.Catalog)
+		catalogID, err = db.ReadDB(ctx).GetCatalogIDByName(ctx, m.Catalog)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Str("catalog", m.Catalog).Msg("Failed to get catalog ID by name")
 			return nil, err
 		}
 	}
 
-	variant, err := db.DB(ctx).GetVariant(ctx, catalogID, uuid.Nil, m.Variant.String())
+	variant, err := db.ReadDB(ctx).GetVariant(ctx, catalogID, uuid.Nil, m.Variant.String())
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Str("catalogID", catalogID.String()).Str("name", m.Name).Msg("Failed to get variant")
 		return nil, err
@@ -145,7 +145,7 @@ func LoadSkillSetManagerByPath(ctx context.Context, m *interfaces.Metadata) (Ski
 
 	pathWithName := path.Clean(m.GetStoragePath(catcommon.CatalogObjectTypeSkillset) + "/" + m.Name)
 
-	obj, err := db.DB(ctx).GetSkillSetObject(ctx, pathWithName, variant.SkillsetDirectoryID)
+	obj, err := db.ReadDB(ctx).GetSkillSetObject(ctx, pathWithName, variant.SkillsetDirectoryID)
 	if err != nil {
 		if errors.Is(err, dberror.ErrNotFound) {
 			return nil, ErrObjectNotFound.Msg("skillset not found")
@@ -159,7 +159,7 @@ func LoadSkillSetManagerByPath(ctx context.Context, m *interfaces.Metadata) (Ski
 // LoadSkillSetManagerByHash loads a skillset manager from the database by hash.
 func LoadSkillSetManagerByHash(ctx context.Context, hash string, m *interfaces.Metadata) (SkillSetManager, apperrors.Error) {
 	// get the object from catalog object store
-	obj, err := db.DB(ctx).GetCatalogObject(ctx, hash)
+	obj, err := db.ReadDB(ctx).GetCatalogObject(ctx, hash)
 	if err != nil {
 		return nil, err
 	}
@@ -192,6 +192,8 @@ func skillSetManagerFromObject(ctx context.Context, obj *models.CatalogObject, m
 	sm.skillSet.ApiVersion = storageRep.Version
 	sm.skillSet.Metadata = *m
 	sm.skillSet.Metadata.Description = storageRep.Description
+	sm.skillSet.Metadata.Labels = storageRep.Labels
+	sm.skillSet.Metadata.Annotations = storageRep.Annotations
 
 	return sm, nil
 }
@@ -392,18 +394,24 @@ func (h *skillsetKindHandler) Delete(ctx context.Context) apperrors.Error {
 }
 
 func (h *skillsetKindHandler) List(ctx context.Context) ([]byte, apperrors.Error) {
-	variant, err := db.DB(ctx).GetVariantByID(ctx, h.req.VariantID)
+	variant, err := db.ReadDB(ctx).GetVariantByID(ctx, h.req.VariantID)
 	if err != nil {
 		return nil, ErrInvalidVariant
 	}
 
-	skillsets, err := db.DB(ctx).ListSkillSets(ctx, variant.SkillsetDirectoryID)
+	skillsets, err := db.ReadDB(ctx).ListSkillSets(ctx, variant.SkillsetDirectoryID)
 	if err != nil {
 		return nil, ErrCatalogError.Msg("unable to list skillsets")
 	}
 
-	skillsetList := make(map[string]json.RawMessage)
-	for _, skillset := range skillsets {
+	skillsets = interfaces.FilterByLabels(skillsets, func(s models.SkillSet) map[string]string {
+		return catalogObjectLabels(ctx, s.Hash)
+	}, h.req.ListOptions.LabelSelector)
+
+	page, next := interfaces.Paginate(skillsets, func(s models.SkillSet) string { return s.Path }, h.req.ListOptions)
+
+	skillsetList := make([]json.RawMessage, 0, len(page))
+	for _, skillset := range page {
 		m := &interfaces.Metadata{
 			Catalog:   h.req.Catalog,
 			Variant:   types.NullableStringFrom(h.req.Variant),
@@ -421,10 +429,10 @@ func (h *skillsetKindHandler) List(ctx context.Context) ([]byte, apperrors.Error
 			log.Ctx(ctx).Error().Err(err).Str("path", skillset.Path).Msg("Failed to marshal skillset")
 			continue
 		}
-		skillsetList[path.Clean(m.Path+"/"+m.Name)] = j
+		skillsetList = append(skillsetList, j)
 	}
 
-	j, goErr := json.Marshal(skillsetList)
+	j, goErr := interfaces.MarshalList(catcommon.KindNameSkillsets, skillsetList, next)
 	if goErr != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("Failed to marshal skillset list")
 		return nil, ErrInvalidSkillSetDefinition
@@ -154,6 +154,8 @@ func (sm *skillSetManager) StorageRepresentation() *objectstore.ObjectStorageRep
 	}
 	s.Spec, _ = json.Marshal(sm.skillSet.Spec)
 	s.Description = sm.skillSet.Metadata.Description
+	s.Labels = sm.skillSet.Metadata.Labels
+	s.Annotations = sm.skillSet.Metadata.Annotations
 	s.Entropy = sm.skillSet.Metadata.GetEntropyBytes(catcommon.CatalogObjectTypeSkillset)
 	return &s
 }
@@ -223,10 +225,11 @@ func (sm *skillSetManager) Save(ctx context.Context) apperrors.Error {
 
 	// Store this object and update the reference
 	obj := models.CatalogObject{
-		Type:    t,
-		Hash:    newHash,
-		Data:    data,
-		Version: sm.skillSet.ApiVersion,
+		Type:       t,
+		Hash:       newHash,
+		Data:       data,
+		Version:    sm.skillSet.ApiVersion,
+		SearchText: s.SearchText(),
 	}
 
 	// Get the directory ID for the skillset
@@ -260,8 +263,10 @@ func (sm *skillSetManager) Save(ctx context.Context) apperrors.Error {
 		Metadata:  skillMetadataJSON,
 	}
 
-	// Store the object
-	err = db.DB(ctx).UpsertSkillSetObject(ctx, ss, &obj, variant.SkillsetDirectoryID)
+	// Store the object and its directory entry together, so that a failure leaves neither behind
+	err = db.Tx(ctx, func(ctx context.Context) apperrors.Error {
+		return db.DB(ctx).UpsertSkillSetObject(ctx, ss, &obj, variant.SkillsetDirectoryID)
+	})
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Str("path", storagePath).Msg("Failed to store object")
 		return err
@@ -426,27 +431,28 @@ func DeleteSkillSet(ctx context.Context, m *interfaces.Metadata) apperrors.Error
 
 	pathWithName := path.Clean(m.GetStoragePath(catcommon.CatalogObjectTypeSkillset) + "/" + m.Name)
 
-	// Delete the skillset
-	hash, err := db.DB(ctx).DeleteSkillSet(ctx, pathWithName, variant.SkillsetDirectoryID)
-	if err != nil {
-		if errors.Is(err, dberror.ErrNotFound) {
-			return ErrObjectNotFound
+	// Delete the directory entry and the object it references together
+	return db.Tx(ctx, func(ctx context.Context) apperrors.Error {
+		hash, err := db.DB(ctx).DeleteSkillSet(ctx, pathWithName, variant.SkillsetDirectoryID)
+		if err != nil {
+			if errors.Is(err, dberror.ErrNotFound) {
+				return ErrObjectNotFound
+			}
+			log.Ctx(ctx).Error().Err(err).Str("path", pathWithName).Msg("Failed to delete object")
+			return err
 		}
-		log.Ctx(ctx).Error().Err(err).Str("path", pathWithName).Msg("Failed to delete object")
-		return err
-	}
 
-	if hash != "" {
+		if hash == "" {
+			log.Ctx(ctx).Warn().Str("path", pathWithName).Msg("skillset not found")
+			return ErrObjectNotFound
+		}
 		err = db.DB(ctx).DeleteCatalogObject(ctx, catcommon.CatalogObjectTypeSkillset, hash)
-		if !errors.Is(err, dberror.ErrNotFound) {
-			log.Ctx(ctx).Error().Err(err).Str("hash", string(hash)).Msg("failed to delete object from database")
+		if err != nil && !errors.Is(err, dberror.ErrNotFound) {
+			log.Ctx(ctx).Error().Err(err).Str("hash", hash).Msg("failed to delete object from database")
+			return err
 		}
-	} else {
-		log.Ctx(ctx).Warn().Str("path", pathWithName).Msg("skillset not found")
-		return ErrObjectNotFound
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // Validate performs validation on the skillset, including:
@@ -0,0 +1,68 @@
+package catalogmanager
+
+import (
+	"context"
+	"path"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// StoredObject identifies a skillset or resource of a variant. Namespace is empty for
+// objects of the default namespace.
+type StoredObject struct {
+	Kind      string
+	Namespace string
+	Path      string
+	Name      string
+}
+
+// FullyQualifiedName returns the path of the object including its name.
+func (o StoredObject) FullyQualifiedName() string {
+	return path.Join("/", o.Path, o.Name)
+}
+
+// ListStoredObjects returns the skillsets and resources of a variant without loading them
+// from the object store.
+func ListStoredObjects(ctx context.Context, catalog string, variantID uuid.UUID) ([]StoredObject, apperrors.Error) {
+	variant, err := db.DB(ctx).GetVariantByID(ctx, variantID)
+	if err != nil {
+		return nil, ErrInvalidVariant
+	}
+	namespaces, err := db.DB(ctx).ListNamespacesByVariant(ctx, variant.VariantID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list namespaces")
+		return nil, ErrUnableToLoadObject.Msg("unable to list namespaces")
+	}
+	namespaceNames := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		if ns.Name != catcommon.DefaultNamespace {
+			namespaceNames[ns.Name] = true
+		}
+	}
+
+	var objects []StoredObject
+	skillsets, err := db.DB(ctx).ListSkillSets(ctx, variant.SkillsetDirectoryID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list skillsets")
+		return nil, ErrUnableToLoadObject.Msg("unable to list skillsets")
+	}
+	for _, skillset := range skillsets {
+		m := exportMetadata(catalog, variant.Name, skillset.Path, namespaceNames)
+		objects = append(objects, StoredObject{Kind: catcommon.SkillSetKind, Namespace: m.Namespace.String(), Path: m.Path, Name: m.Name})
+	}
+
+	resources, err := db.DB(ctx).ListResources(ctx, variant.ResourceDirectoryID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list resources")
+		return nil, ErrUnableToLoadObject.Msg("unable to list resources")
+	}
+	for _, resource := range resources {
+		m := exportMetadata(catalog, variant.Name, resource.Path, namespaceNames)
+		objects = append(objects, StoredObject{Kind: catcommon.ResourceKind, Namespace: m.Namespace.String(), Path: m.Path, Name: m.Name})
+	}
+	return objects, nil
+}
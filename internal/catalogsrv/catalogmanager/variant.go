@@ -8,7 +8,6 @@ import (
 	"encoding/json"
 
 	"github.com/go-playground/validator/v10"
-	"github.com/jackc/pgtype"
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
@@ -38,9 +37,15 @@ type variantSchema struct {
 }
 
 type variantMetadata struct {
-	Name        string `json:"name" validate:"required,resourceNameValidator"`
-	Catalog     string `json:"catalog" validate:"required,resourceNameValidator"`
-	Description string `json:"description"`
+	Name        string            `json:"name" validate:"required,resourceNameValidator"`
+	Catalog     string            `json:"catalog" validate:"required,resourceNameValidator"`
+	Description string            `json:"description"`
+	Labels      map[string]string `json:"labels,omitempty" validate:"omitempty,labelsValidator"`
+	Annotations map[string]string `json:"annotations,omitempty" validate:"omitempty,annotationsValidator"`
+}
+
+func (m variantMetadata) objectInfo() interfaces.ObjectInfo {
+	return interfaces.ObjectInfo{Labels: m.Labels, Annotations: m.Annotations}
 }
 
 type variantManager struct {
@@ -81,6 +86,10 @@ func (vs *variantSchema) Validate() schemaerr.ValidationErrors {
 			validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind(jsonFieldName))
 		case "validateVersion":
 			validationErrors = append(validationErrors, schemaerr.ErrInvalidVersion(jsonFieldName))
+		case "labelsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidLabel(jsonFieldName))
+		case "annotationsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidAnnotation(jsonFieldName))
 		default:
 			validationErrors = append(validationErrors, schemaerr.ErrValidationFailed(jsonFieldName))
 		}
@@ -149,7 +158,7 @@ func NewVariantManager(ctx context.Context, resourceJSON []byte, name string, ca
 		Name:        vs.Metadata.Name,
 		Description: vs.Metadata.Description,
 		CatalogID:   catalogID,
-		Info:        pgtype.JSONB{Status: pgtype.Null},
+		Info:        infoJSONB(vs.Metadata.objectInfo()),
 	}
 
 	return &variantManager{
@@ -219,6 +228,7 @@ func (vm *variantManager) ToJson(ctx context.Context) ([]byte, apperrors.Error)
 		return nil, err
 	}
 
+	info := objectInfoFromJSONB(vm.variant.Info)
 	s := variantSchema{
 		ApiVersion: catcommon.ApiVersion,
 		Kind:       catcommon.VariantKind,
@@ -226,6 +236,8 @@ func (vm *variantManager) ToJson(ctx context.Context) ([]byte, apperrors.Error)
 			Name:        vm.variant.Name,
 			Catalog:     catalog.Name,
 			Description: vm.variant.Description,
+			Labels:      info.Labels,
+			Annotations: info.Annotations,
 		},
 	}
 
@@ -239,6 +251,13 @@ func (vm *variantManager) ToJson(ctx context.Context) ([]byte, apperrors.Error)
 }
 
 func DeleteVariant(ctx context.Context, catalogID, variantID uuid.UUID, name string) apperrors.Error {
+	// A variant made the default of its catalog must be replaced as the default first
+	if name != "" && name != catcommon.DefaultVariant {
+		catalog, err := db.DB(ctx).GetCatalogByID(ctx, catalogID)
+		if err == nil && DefaultVariantOf(catalog) == name {
+			return ErrDefaultVariant
+		}
+	}
 	err := db.DB(ctx).DeleteVariant(ctx, catalogID, variantID, name)
 	if err != nil {
 		if errors.Is(err, dberror.ErrNotFound) {
@@ -250,6 +269,48 @@ func DeleteVariant(ctx context.Context, catalogID, variantID uuid.UUID, name str
 	return nil
 }
 
+// RestoreVariant restores a soft deleted variant of the catalog that has not been purged yet
+func RestoreVariant(ctx context.Context, catalogID uuid.UUID, name string) apperrors.Error {
+	err := db.DB(ctx).RestoreVariant(ctx, catalogID, name)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrVariantNotFound.Msg("no deleted variant named " + name)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to restore variant")
+		return err
+	}
+	return nil
+}
+
+// CloneVariant creates a variant named name in the catalog as a copy of the source variant,
+// with all of its resources, skillsets and namespaces. The clone shares the stored objects of
+// the source, so cloning is cheap regardless of the size of the variant.
+func CloneVariant(ctx context.Context, catalogID uuid.UUID, source string, name string, description string) apperrors.Error {
+	if !schemavalidator.ValidateKindName(name) {
+		return ErrInvalidNameFormat
+	}
+	variant := models.Variant{
+		Name:        name,
+		Description: description,
+		CatalogID:   catalogID,
+	}
+	err := db.DB(ctx).CloneVariant(ctx, source, &variant)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrVariantNotFound
+		}
+		if errors.Is(err, dberror.ErrAlreadyExists) {
+			return ErrAlreadyExists.Msg("variant already exists")
+		}
+		if errors.Is(err, dberror.ErrInvalidInput) {
+			return ErrInvalidVariant.Msg("invalid variant name format")
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to clone variant")
+		return err
+	}
+	return nil
+}
+
 // TODO Handle base variant and copy of data
 
 type variantKind struct {
@@ -320,6 +381,7 @@ func (v *variantKind) Update(ctx context.Context, rsrcJson []byte) apperrors.Err
 	}
 
 	variant.Description = schema.Metadata.Description
+	variant.Info = infoJSONB(schema.Metadata.objectInfo())
 
 	err = db.DB(ctx).UpdateVariant(ctx, uuid.Nil, v.req.Variant, variant)
 	if err != nil {
@@ -330,7 +392,41 @@ func (v *variantKind) Update(ctx context.Context, rsrcJson []byte) apperrors.Err
 }
 
 func (v *variantKind) List(ctx context.Context) ([]byte, apperrors.Error) {
-	return nil, nil
+	variants, err := db.DB(ctx).ListVariantsByCatalog(ctx, v.req.CatalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list variants")
+		return nil, ErrUnableToLoadObject.Msg("unable to list variants")
+	}
+	if v.req.ListOptions.IncludeDeleted {
+		deleted, err := db.DB(ctx).ListDeletedVariantsByCatalog(ctx, v.req.CatalogID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to list deleted variants")
+			return nil, ErrUnableToLoadObject.Msg("unable to list variants")
+		}
+		variants = append(variants, deleted...)
+	}
+
+	variants = interfaces.FilterByLabels(variants, func(vs models.VariantSummary) map[string]string {
+		return objectInfoFromJSONB(vs.Info).Labels
+	}, v.req.ListOptions.LabelSelector)
+	page, next := interfaces.Paginate(variants, func(vs models.VariantSummary) string { return vs.Name }, v.req.ListOptions)
+
+	items := make([]interfaces.ListItem, 0, len(page))
+	for _, variant := range page {
+		items = append(items, interfaces.ListItem{
+			Name:        variant.Name,
+			Description: variant.Description,
+			Labels:      objectInfoFromJSONB(variant.Info).Labels,
+			DeletedAt:   variant.DeletedAt,
+		})
+	}
+
+	j, e := interfaces.MarshalList(catcommon.KindNameVariants, items, next)
+	if e != nil {
+		log.Ctx(ctx).Error().Err(e).Msg("failed to marshal variant list")
+		return nil, ErrUnableToLoadObject
+	}
+	return j, nil
 }
 
 func NewVariantKindHandler(ctx context.Context, reqCtx interfaces.RequestContext) (interfaces.KindHandler, apperrors.Error) {
@@ -0,0 +1,222 @@
+package catalogmanager
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// VariantDiff describes how a variant differs from the variant it is compared against. Only
+// namespaces with differences are included; objects in the root namespace are grouped under an
+// empty namespace, which is listed first.
+type VariantDiff struct {
+	Variant    string          `json:"variant"`
+	Against    string          `json:"against"`
+	Namespaces []NamespaceDiff `json:"namespaces"`
+}
+
+// NamespaceDiff lists the resources and skillsets of a namespace that differ between two
+// variants, ordered by path.
+type NamespaceDiff struct {
+	Namespace string         `json:"namespace,omitempty"`
+	Resources []ResourceDiff `json:"resources"`
+	SkillSets []SkillSetDiff `json:"skillsets"`
+}
+
+// SkillSetDiff describes whether a skillset was added, removed or changed between two variants.
+type SkillSetDiff struct {
+	SkillSet string `json:"skillset"`
+	Status   string `json:"status"`
+}
+
+// DiffVariants compares the resources and skillsets of a variant of the catalog with those of
+// the against variant. Like DiffResource, the diff describes the changes from the against
+// variant to the variant. Objects with the same hash in both variants are unchanged and are
+// not loaded.
+func DiffVariants(ctx context.Context, catalogID uuid.UUID, catalog, variant, against string) (*VariantDiff, apperrors.Error) {
+	if against == "" {
+		return nil, ErrInvalidVariant.Msg("variant to compare against is required")
+	}
+
+	to, err := loadVariantForDiff(ctx, catalogID, variant)
+	if err != nil {
+		return nil, err
+	}
+	from, err := loadVariantForDiff(ctx, catalogID, against)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaceNames := make(map[string]bool)
+	for _, v := range []*models.Variant{from, to} {
+		namespaces, err := db.ReadDB(ctx).ListNamespacesByVariant(ctx, v.VariantID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to list namespaces")
+			return nil, ErrUnableToLoadObject.Msg("unable to list namespaces")
+		}
+		for _, namespace := range namespaces {
+			if namespace.Name != catcommon.DefaultNamespace {
+				namespaceNames[namespace.Name] = true
+			}
+		}
+	}
+
+	diff := &VariantDiff{
+		Variant:    variant,
+		Against:    against,
+		Namespaces: []NamespaceDiff{},
+	}
+	groups := make(map[string]*NamespaceDiff)
+	group := func(m *interfaces.Metadata) *NamespaceDiff {
+		namespace := m.Namespace.String()
+		if groups[namespace] == nil {
+			groups[namespace] = &NamespaceDiff{
+				Namespace: namespace,
+				Resources: []ResourceDiff{},
+				SkillSets: []SkillSetDiff{},
+			}
+		}
+		return groups[namespace]
+	}
+
+	fromResources, err := resourceHashes(ctx, from.ResourceDirectoryID)
+	if err != nil {
+		return nil, err
+	}
+	toResources, err := resourceHashes(ctx, to.ResourceDirectoryID)
+	if err != nil {
+		return nil, err
+	}
+	for _, storagePath := range changedObjectPaths(fromResources, toResources) {
+		m := exportMetadata(catalog, variant, storagePath, namespaceNames)
+		resourceDiff := ResourceDiff{
+			Resource: m.GetFullyQualifiedName(),
+			Variant:  variant,
+			Against:  against,
+			Changes:  []SchemaChange{},
+		}
+		fromResource, err := loadResourceByHashForDiff(ctx, fromResources[storagePath], m)
+		if err != nil {
+			return nil, err
+		}
+		toResource, err := loadResourceByHashForDiff(ctx, toResources[storagePath], m)
+		if err != nil {
+			return nil, err
+		}
+		if err := compareResources(ctx, &resourceDiff, fromResource, toResource); err != nil {
+			return nil, err
+		}
+		if resourceDiff.Status != ResourceDiffUnchanged {
+			g := group(m)
+			g.Resources = append(g.Resources, resourceDiff)
+		}
+	}
+
+	fromSkillSets, err := skillSetHashes(ctx, from.SkillsetDirectoryID)
+	if err != nil {
+		return nil, err
+	}
+	toSkillSets, err := skillSetHashes(ctx, to.SkillsetDirectoryID)
+	if err != nil {
+		return nil, err
+	}
+	for _, storagePath := range changedObjectPaths(fromSkillSets, toSkillSets) {
+		m := exportMetadata(catalog, variant, storagePath, namespaceNames)
+		status := ResourceDiffChanged
+		if fromSkillSets[storagePath] == "" {
+			status = ResourceDiffAdded
+		} else if toSkillSets[storagePath] == "" {
+			status = ResourceDiffRemoved
+		}
+		g := group(m)
+		g.SkillSets = append(g.SkillSets, SkillSetDiff{SkillSet: m.GetFullyQualifiedName(), Status: status})
+	}
+
+	namespaces := make([]string, 0, len(groups))
+	for namespace := range groups {
+		namespaces = append(namespaces, namespace)
+	}
+	sort.Strings(namespaces)
+	for _, namespace := range namespaces {
+		diff.Namespaces = append(diff.Namespaces, *groups[namespace])
+	}
+	return diff, nil
+}
+
+func loadVariantForDiff(ctx context.Context, catalogID uuid.UUID, name string) (*models.Variant, apperrors.Error) {
+	variant, err := db.ReadDB(ctx).GetVariant(ctx, catalogID, uuid.Nil, name)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrVariantNotFound.Msg("variant " + name + " not found")
+		}
+		return nil, err
+	}
+	return variant, nil
+}
+
+// resourceHashes returns the resources of a directory as a map from storage path to hash.
+func resourceHashes(ctx context.Context, directoryID uuid.UUID) (map[string]string, apperrors.Error) {
+	resources, err := db.ReadDB(ctx).ListResources(ctx, directoryID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list resources")
+		return nil, ErrUnableToLoadObject.Msg("unable to list resources")
+	}
+	hashes := make(map[string]string, len(resources))
+	for _, resource := range resources {
+		hashes[resource.Path] = resource.Hash
+	}
+	return hashes, nil
+}
+
+// skillSetHashes returns the skillsets of a directory as a map from storage path to hash.
+func skillSetHashes(ctx context.Context, directoryID uuid.UUID) (map[string]string, apperrors.Error) {
+	skillsets, err := db.ReadDB(ctx).ListSkillSets(ctx, directoryID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list skillsets")
+		return nil, ErrUnableToLoadObject.Msg("unable to list skillsets")
+	}
+	hashes := make(map[string]string, len(skillsets))
+	for _, skillset := range skillsets {
+		hashes[skillset.Path] = skillset.Hash
+	}
+	return hashes, nil
+}
+
+// changedObjectPaths returns the sorted storage paths whose hash differs between two variants,
+// including paths that exist in only one of them.
+func changedObjectPaths(from, to map[string]string) []string {
+	var paths []string
+	for path, hash := range from {
+		if to[path] != hash {
+			paths = append(paths, path)
+		}
+	}
+	for path := range to {
+		if _, ok := from[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// loadResourceByHashForDiff loads a resource by hash, returning nil if the hash is empty.
+func loadResourceByHashForDiff(ctx context.Context, hash string, m *interfaces.Metadata) (*resourceManager, apperrors.Error) {
+	if hash == "" {
+		return nil, nil
+	}
+	rm, err := LoadResourceManagerByHash(ctx, hash, m)
+	if err != nil {
+		return nil, err
+	}
+	return rm.(*resourceManager), nil
+}
@@ -18,6 +18,7 @@ const (
 	ctxTenantIdKey       ctxKeyType = "CatalogTenantId"
 	ctxProjectIdKey      ctxKeyType = "CatalogProjectId"
 	ctxTestContextKey    ctxKeyType = "CatalogTestContext"
+	ctxDryRunKey         ctxKeyType = "CatalogDryRun"
 )
 
 type SubjectType string
@@ -29,6 +30,9 @@ const (
 	SubjectTypeService SubjectType = "service"
 )
 
+// SingleUserID is the ID of the user in single user mode
+const SingleUserID = "default-user"
+
 // CatalogContext represents the complete context for catalog operations.
 // It contains all necessary information about the catalog, variant, and user.
 type CatalogContext struct {
@@ -42,10 +46,14 @@ type CatalogContext struct {
 	Catalog string
 	// Variant is the name of the variant
 	Variant string
+	// ViewID is the unique identifier for the view that authorizes the request
+	ViewID uuid.UUID
 	// UserContext contains information about the authenticated user
 	UserContext *UserContext
 	// SessionContext contains information about the session
 	SessionContext *SessionContext
+	// ServiceContext contains information about the authenticated service account
+	ServiceContext *ServiceContext
 	// Subject is the type of principal that is acting on the catalog
 	Subject SubjectType
 }
@@ -57,6 +65,13 @@ type UserContext struct {
 	UserID string
 }
 
+// ServiceContext represents the context of a service account, such as a CI job or a
+// tangent, acting through an API key or a service token.
+type ServiceContext struct {
+	// ServiceName identifies the service account
+	ServiceName string
+}
+
 // SessionContext represents the context of a session in the system.
 // It contains information about the session's identity and permissions.
 type SessionContext struct {
@@ -214,6 +229,14 @@ func GetVariant(ctx context.Context) string {
 	return ""
 }
 
+// GetViewID retrieves the ID of the view that authorizes the request from the provided context.
+func GetViewID(ctx context.Context) uuid.UUID {
+	if catalogContext, ok := ctx.Value(ctxCatalogContextKey).(*CatalogContext); ok {
+		return catalogContext.ViewID
+	}
+	return uuid.Nil
+}
+
 // GetUserContext retrieves the user context from the provided context.
 func GetUserContext(ctx context.Context) *UserContext {
 	if catalogContext, ok := ctx.Value(ctxCatalogContextKey).(*CatalogContext); ok {
@@ -222,6 +245,14 @@ func GetUserContext(ctx context.Context) *UserContext {
 	return nil
 }
 
+// GetServiceContext retrieves the service context from the provided context.
+func GetServiceContext(ctx context.Context) *ServiceContext {
+	if catalogContext, ok := ctx.Value(ctxCatalogContextKey).(*CatalogContext); ok {
+		return catalogContext.ServiceContext
+	}
+	return nil
+}
+
 func GetUserID(ctx context.Context) string {
 	if catalogContext, ok := ctx.Value(ctxCatalogContextKey).(*CatalogContext); ok {
 		if catalogContext.UserContext != nil {
@@ -254,6 +285,24 @@ func GetSubjectType(ctx context.Context) SubjectType {
 	return SubjectType("")
 }
 
+// GetPrincipal returns the principal acting on the catalog in the form user/<id>,
+// service/<name> or session/<id>, or an empty string if there is none.
+func GetPrincipal(ctx context.Context) string {
+	catalogContext, ok := ctx.Value(ctxCatalogContextKey).(*CatalogContext)
+	if !ok || catalogContext == nil {
+		return ""
+	}
+	switch {
+	case catalogContext.UserContext != nil:
+		return "user/" + catalogContext.UserContext.UserID
+	case catalogContext.ServiceContext != nil:
+		return "service/" + catalogContext.ServiceContext.ServiceName
+	case catalogContext.SessionContext != nil:
+		return "session/" + catalogContext.SessionContext.SessionID.String()
+	}
+	return ""
+}
+
 // WithTestContext sets the test context in the provided context.
 func WithTestContext(ctx context.Context, isTest bool) context.Context {
 	return context.WithValue(ctx, ctxTestContextKey, isTest)
@@ -266,3 +315,14 @@ func GetTestContext(ctx context.Context) bool {
 	}
 	return false
 }
+
+// WithDryRun marks the request of the context as a dry run, whose changes are not persisted.
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxDryRunKey, true)
+}
+
+// IsDryRun reports whether the request of the context is a dry run.
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(ctxDryRunKey).(bool)
+	return dryRun
+}
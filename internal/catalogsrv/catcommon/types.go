@@ -25,22 +25,26 @@ func (u CatalogId) IsNil() bool {
 }
 
 const (
-	CatalogKind   = "Catalog"
-	VariantKind   = "Variant"
-	NamespaceKind = "Namespace"
-	ResourceKind  = "Resource"
-	SkillSetKind  = "SkillSet"
-	ViewKind      = "View"
-	InvalidKind   = "InvalidKind"
+	CatalogKind          = "Catalog"
+	VariantKind          = "Variant"
+	NamespaceKind        = "Namespace"
+	ResourceKind         = "Resource"
+	SkillSetKind         = "SkillSet"
+	ViewKind             = "View"
+	ViewTemplateKind     = "ViewTemplate"
+	AdmissionWebhookKind = "AdmissionWebhook"
+	InvalidKind          = "InvalidKind"
 )
 
 const (
-	KindNameCatalogs   = "catalogs"
-	KindNameVariants   = "variants"
-	KindNameNamespaces = "namespaces"
-	KindNameViews      = "views"
-	KindNameResources  = "resources"
-	KindNameSkillsets  = "skillsets"
+	KindNameCatalogs          = "catalogs"
+	KindNameVariants          = "variants"
+	KindNameNamespaces        = "namespaces"
+	KindNameViews             = "views"
+	KindNameViewTemplates     = "viewtemplates"
+	KindNameAdmissionWebhooks = "admissionwebhooks"
+	KindNameResources         = "resources"
+	KindNameSkillsets         = "skillsets"
 )
 
 func ValidKindNames() []string {
@@ -49,6 +53,8 @@ func ValidKindNames() []string {
 		KindNameVariants,
 		KindNameNamespaces,
 		KindNameViews,
+		KindNameViewTemplates,
+		KindNameAdmissionWebhooks,
 		KindNameResources,
 		KindNameSkillsets,
 	}
@@ -75,6 +81,10 @@ func KindFromKindName(uri string) string {
 		return NamespaceKind
 	case KindNameViews:
 		return ViewKind
+	case KindNameViewTemplates:
+		return ViewTemplateKind
+	case KindNameAdmissionWebhooks:
+		return AdmissionWebhookKind
 	case KindNameResources:
 		return ResourceKind
 	case KindNameSkillsets:
@@ -96,7 +106,7 @@ func KindNameFromObjectType(t CatalogObjectType) string {
 }
 
 func IsCatalogLevelKind(kind string) bool {
-	return kind == KindNameViews
+	return kind == KindNameViews || kind == KindNameViewTemplates || kind == KindNameAdmissionWebhooks
 }
 
 type CatalogObjectType string
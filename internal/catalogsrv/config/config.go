@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -32,13 +35,303 @@ func (s *SessionConfig) GetExpirationTimeOrDefault() time.Duration {
 	return duration
 }
 
+// ViewsConfig holds view-related configuration
+type ViewsConfig struct {
+	ExpiryCheckInterval string `toml:"expiry_check_interval"` // How often expired views are disabled
+}
+
+// DefaultViewExpiryCheckInterval is used when views.expiry_check_interval is not set
+const DefaultViewExpiryCheckInterval = "5m"
+
+// GetExpiryCheckInterval returns the view expiry check interval as time.Duration
+func (v *ViewsConfig) GetExpiryCheckInterval() (time.Duration, error) {
+	return ParseDuration(v.ExpiryCheckInterval)
+}
+
+// GetExpiryCheckIntervalOrDefault returns the view expiry check interval as time.Duration
+// or panics if the value is invalid
+func (v *ViewsConfig) GetExpiryCheckIntervalOrDefault() time.Duration {
+	duration, err := v.GetExpiryCheckInterval()
+	if err != nil {
+		panic(fmt.Sprintf("invalid view expiry check interval: %v", err))
+	}
+	return duration
+}
+
+// DeletionConfig holds the retention of soft deleted catalogs and variants
+type DeletionConfig struct {
+	Retention     string `toml:"retention"`      // How long deleted catalogs and variants can be restored
+	PurgeInterval string `toml:"purge_interval"` // How often objects past the retention window are purged
+}
+
+const (
+	// DefaultDeletionRetention is used when deletion.retention is not set
+	DefaultDeletionRetention = "30d"
+	// DefaultDeletionPurgeInterval is used when deletion.purge_interval is not set
+	DefaultDeletionPurgeInterval = "1h"
+)
+
+// GetRetention returns the retention window of deleted objects as time.Duration
+func (d *DeletionConfig) GetRetention() (time.Duration, error) {
+	return ParseDuration(d.Retention)
+}
+
+// GetRetentionOrDefault returns the retention window of deleted objects as time.Duration
+// or panics if the value is invalid
+func (d *DeletionConfig) GetRetentionOrDefault() time.Duration {
+	duration, err := d.GetRetention()
+	if err != nil {
+		panic(fmt.Sprintf("invalid deletion retention: %v", err))
+	}
+	return duration
+}
+
+// GetPurgeInterval returns the purge interval of deleted objects as time.Duration
+func (d *DeletionConfig) GetPurgeInterval() (time.Duration, error) {
+	return ParseDuration(d.PurgeInterval)
+}
+
+// GetPurgeIntervalOrDefault returns the purge interval of deleted objects as time.Duration
+// or panics if the value is invalid
+func (d *DeletionConfig) GetPurgeIntervalOrDefault() time.Duration {
+	duration, err := d.GetPurgeInterval()
+	if err != nil {
+		panic(fmt.Sprintf("invalid deletion purge interval: %v", err))
+	}
+	return duration
+}
+
+// ObjectGCConfig holds the schedule of the garbage collection of catalog objects that are no
+// longer referenced by any resource or skillset directory
+type ObjectGCConfig struct {
+	Interval    string `toml:"interval"`     // How often unreferenced catalog objects are collected
+	GracePeriod string `toml:"grace_period"` // How long an object must be unchanged before it is collected
+}
+
+// AdmissionWebhooksConfig restricts the addresses admission webhooks may be called at. Webhooks
+// on loopback, link-local and private addresses are refused unless the address is in one of
+// AllowedNetworks.
+type AdmissionWebhooksConfig struct {
+	AllowedNetworks []string `toml:"allowed_networks"` // CIDR blocks of private addresses webhooks may be called at
+}
+
+// GetAllowedNetworks returns the networks of AllowedNetworks.
+func (a *AdmissionWebhooksConfig) GetAllowedNetworks() ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, cidr := range a.AllowedNetworks {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+const (
+	// DefaultObjectGCInterval is used when object_gc.interval is not set
+	DefaultObjectGCInterval = "6h"
+	// DefaultObjectGCGracePeriod is used when object_gc.grace_period is not set
+	DefaultObjectGCGracePeriod = "1h"
+)
+
+// GetInterval returns the garbage collection interval as time.Duration
+func (g *ObjectGCConfig) GetInterval() (time.Duration, error) {
+	return ParseDuration(g.Interval)
+}
+
+// GetIntervalOrDefault returns the garbage collection interval as time.Duration
+// or panics if the value is invalid
+func (g *ObjectGCConfig) GetIntervalOrDefault() time.Duration {
+	duration, err := g.GetInterval()
+	if err != nil {
+		panic(fmt.Sprintf("invalid object gc interval: %v", err))
+	}
+	return duration
+}
+
+// GetGracePeriod returns the garbage collection grace period as time.Duration
+func (g *ObjectGCConfig) GetGracePeriod() (time.Duration, error) {
+	return ParseDuration(g.GracePeriod)
+}
+
+// GetGracePeriodOrDefault returns the garbage collection grace period as time.Duration
+// or panics if the value is invalid
+func (g *ObjectGCConfig) GetGracePeriodOrDefault() time.Duration {
+	duration, err := g.GetGracePeriod()
+	if err != nil {
+		panic(fmt.Sprintf("invalid object gc grace period: %v", err))
+	}
+	return duration
+}
+
+// HTTPConfig holds the timeouts of the HTTP server. Durations of "0s" turn a timeout off.
+type HTTPConfig struct {
+	ReadHeaderTimeout string            `toml:"read_header_timeout"` // Time to read the headers of a request
+	ReadTimeout       string            `toml:"read_timeout"`        // Time to read a whole request, including its body
+	WriteTimeout      string            `toml:"write_timeout"`       // Time to write a response; streaming responses clear it
+	IdleTimeout       string            `toml:"idle_timeout"`        // Time an idle keep-alive connection is kept open
+	HandlerTimeout    string            `toml:"handler_timeout"`     // Time a handler has to start its response before a 408 is sent
+	RouteTimeouts     map[string]string `toml:"route_timeouts"`      // Handler timeouts of routes, keyed by method and route pattern, e.g. "POST /catalogs/import"
+}
+
+const (
+	// DefaultReadHeaderTimeout is used when http.read_header_timeout is not set
+	DefaultReadHeaderTimeout = "5s"
+	// DefaultReadTimeout is used when http.read_timeout is not set
+	DefaultReadTimeout = "10s"
+	// DefaultWriteTimeout is used when http.write_timeout is not set
+	DefaultWriteTimeout = "30s"
+	// DefaultIdleTimeout is used when http.idle_timeout is not set
+	DefaultIdleTimeout = "120s"
+	// DefaultHandlerTimeout is used when http.handler_timeout is not set
+	DefaultHandlerTimeout = "25s"
+	// DefaultMaxRequestBodySize is used when max_request_body_size is not set
+	DefaultMaxRequestBodySize = 1 << 20
+)
+
+// GetReadHeaderTimeout returns the header read timeout as time.Duration
+func (h *HTTPConfig) GetReadHeaderTimeout() (time.Duration, error) {
+	return ParseDuration(h.ReadHeaderTimeout)
+}
+
+// GetReadTimeout returns the request read timeout as time.Duration
+func (h *HTTPConfig) GetReadTimeout() (time.Duration, error) {
+	return ParseDuration(h.ReadTimeout)
+}
+
+// GetWriteTimeout returns the response write timeout as time.Duration
+func (h *HTTPConfig) GetWriteTimeout() (time.Duration, error) {
+	return ParseDuration(h.WriteTimeout)
+}
+
+// GetIdleTimeout returns the idle connection timeout as time.Duration
+func (h *HTTPConfig) GetIdleTimeout() (time.Duration, error) {
+	return ParseDuration(h.IdleTimeout)
+}
+
+// GetHandlerTimeout returns the default handler timeout as time.Duration
+func (h *HTTPConfig) GetHandlerTimeout() (time.Duration, error) {
+	return ParseDuration(h.HandlerTimeout)
+}
+
+// GetRouteTimeouts returns the handler timeouts of routes as time.Duration, keyed by method
+// and route pattern
+func (h *HTTPConfig) GetRouteTimeouts() (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(h.RouteTimeouts))
+	for route, timeout := range h.RouteTimeouts {
+		d, err := ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", route, err)
+		}
+		timeouts[route] = d
+	}
+	return timeouts, nil
+}
+
+// CORSConfig holds the cross-origin policy applied when handle_cors is set, so that a
+// browser-based console served from another origin can call the server directly
+type CORSConfig struct {
+	AllowedOrigins   []string `toml:"allowed_origins"`   // Origins allowed to make requests; "*" allows any, and one "*" may stand for a subdomain
+	AllowedMethods   []string `toml:"allowed_methods"`   // Methods allowed in cross-origin requests
+	AllowedHeaders   []string `toml:"allowed_headers"`   // Request headers allowed in cross-origin requests
+	ExposedHeaders   []string `toml:"exposed_headers"`   // Response headers the browser exposes to the console
+	AllowCredentials bool     `toml:"allow_credentials"` // Whether requests may carry cookies and client certificates
+	MaxAge           int      `toml:"max_age"`           // How long, in seconds, browsers may cache a preflight response
+}
+
+var (
+	// DefaultCORSAllowedOrigins is used when cors.allowed_origins is not set
+	DefaultCORSAllowedOrigins = []string{"http://local.tansive.dev:8190"}
+	// DefaultCORSAllowedMethods is used when cors.allowed_methods is not set
+	DefaultCORSAllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	// DefaultCORSAllowedHeaders is used when cors.allowed_headers is not set
+	DefaultCORSAllowedHeaders = []string{"Accept", "Content-Type", "Content-Length", "Accept-Encoding", "Authorization", "X-Hatch-IDToken", "If-Match", "If-None-Match", "X-Tansive-Request-ID"}
+	// DefaultCORSExposedHeaders is used when cors.exposed_headers is not set
+	DefaultCORSExposedHeaders = []string{"ETag", "Location", "X-Tansive-Request-ID"}
+)
+
+// ReplicasConfig holds the read replica configuration of the database
+type ReplicasConfig struct {
+	DSNs   []string `toml:"dsns"`    // Connection strings of the read replicas
+	MaxLag string   `toml:"max_lag"` // Staleness tolerance; replicas lagging more are not used
+}
+
+// DefaultReplicaMaxLag is used when db.replicas.max_lag is not set
+const DefaultReplicaMaxLag = "5s"
+
+// GetMaxLag returns the replica staleness tolerance as time.Duration
+func (r *ReplicasConfig) GetMaxLag() (time.Duration, error) {
+	return ParseDuration(r.MaxLag)
+}
+
+// PoolConfig holds the database connection pool settings
+type PoolConfig struct {
+	MaxOpenConns      int    `toml:"max_open_conns"`      // Maximum number of open connections
+	MaxIdleConns      int    `toml:"max_idle_conns"`      // Maximum number of idle connections kept open
+	MaxIdleTime       string `toml:"max_idle_time"`       // How long a connection may be idle before it is closed
+	MaxLifetime       string `toml:"max_lifetime"`        // How long a connection may be reused
+	HealthCheckPeriod string `toml:"health_check_period"` // How often the pool pings the database
+}
+
+// Defaults for unset connection pool settings
+const (
+	DefaultPoolMaxOpenConns      = 50
+	DefaultPoolMaxIdleConns      = 10
+	DefaultPoolMaxIdleTime       = "5m"
+	DefaultPoolMaxLifetime       = "30m"
+	DefaultPoolHealthCheckPeriod = "1m"
+)
+
+// GetMaxIdleTime returns the maximum connection idle time as time.Duration
+func (p *PoolConfig) GetMaxIdleTime() (time.Duration, error) {
+	return ParseDuration(p.MaxIdleTime)
+}
+
+// GetMaxLifetime returns the maximum connection lifetime as time.Duration
+func (p *PoolConfig) GetMaxLifetime() (time.Duration, error) {
+	return ParseDuration(p.MaxLifetime)
+}
+
+// GetHealthCheckPeriod returns the pool health check period as time.Duration
+func (p *PoolConfig) GetHealthCheckPeriod() (time.Duration, error) {
+	return ParseDuration(p.HealthCheckPeriod)
+}
+
 // AuthConfig holds authentication-related configuration
 type AuthConfig struct {
-	MaxTokenAge          string `toml:"max_token_age"`          // Maximum age for tokens
-	ClockSkew            string `toml:"clock_skew"`             // Allowed clock skew for time-based claims
-	KeyEncryptionPasswd  string `toml:"key_encryption_passwd"`  // Password for key encryption
-	DefaultTokenValidity string `toml:"default_token_validity"` // Default token validity duration
-	TestUserToken        string `toml:"-"`                      // Token for internal unit test mode
+	MaxTokenAge          string     `toml:"max_token_age"`          // Maximum age for tokens
+	ClockSkew            string     `toml:"clock_skew"`             // Allowed clock skew for time-based claims
+	KeyEncryptionPasswd  string     `toml:"key_encryption_passwd"`  // Password for key encryption
+	DefaultTokenValidity string     `toml:"default_token_validity"` // Default token validity duration
+	TestUserToken        string     `toml:"-"`                      // Token for internal unit test mode
+	PlatformAdmins       []string   `toml:"platform_admins"`        // Users allowed to administer all tenants
+	OIDC                 OIDCConfig `toml:"oidc"`                   // External identity provider configuration
+}
+
+// OIDCConfig holds the settings for authenticating users with tokens issued by an
+// external OpenID Connect provider
+type OIDCConfig struct {
+	Enabled      bool   `toml:"enabled"`        // Whether to accept tokens from the OIDC provider
+	Issuer       string `toml:"issuer"`         // Issuer URL used for discovery and the iss claim
+	Audience     string `toml:"audience"`       // Required value of the aud claim
+	UserClaim    string `toml:"user_claim"`     // Claim holding the user ID
+	TenantClaim  string `toml:"tenant_claim"`   // Claim holding the tenant ID
+	ProjectClaim string `toml:"project_claim"`  // Claim holding the project ID
+	JWKSCacheTTL string `toml:"jwks_cache_ttl"` // How long fetched signing keys are cached
+}
+
+// Defaults for optional OIDC settings
+const (
+	DefaultOIDCUserClaim    = "sub"
+	DefaultOIDCTenantClaim  = "tenant_id"
+	DefaultOIDCProjectClaim = "project_id"
+	DefaultOIDCJWKSCacheTTL = "60m"
+)
+
+// GetJWKSCacheTTL returns the JWKS cache duration as time.Duration
+func (o *OIDCConfig) GetJWKSCacheTTL() (time.Duration, error) {
+	return ParseDuration(o.JWKSCacheTTL)
 }
 
 // GetMaxTokenAge returns the maximum token age as time.Duration
@@ -104,11 +397,13 @@ type ConfigParam struct {
 	ServerHostName     string `toml:"server_hostname"`       // Hostname for the server
 	ServerPort         string `toml:"server_port"`           // Port for the main server
 	EndpointPort       string `toml:"endpoint_port"`         // Port for the endpoint server
+	MetricsPort        string `toml:"metrics_port"`          // Port for Prometheus metrics at /metrics; disabled if empty
 	HandleCORS         bool   `toml:"handle_cors"`           // Whether to handle CORS
 	MaxRequestBodySize int64  `toml:"max_request_body_size"` // Maximum size of request body in bytes
 	SupportTLS         bool   `toml:"support_tls"`           // Whether to support TLS
 	TLSCertFile        string `toml:"tls_cert_file"`         // Path to TLS certificate file
 	TLSKeyFile         string `toml:"tls_key_file"`          // Path to TLS key file
+	TLSClientCAFile    string `toml:"tls_client_ca_file"`    // Path to CA certificates that client certificates must be signed by
 	TLSCertPEM         []byte `toml:"-"`                     // PEM encoded TLS certificate
 	TLSKeyPEM          []byte `toml:"-"`                     // PEM encoded TLS key
 
@@ -118,6 +413,24 @@ type ConfigParam struct {
 	// Audit log configuration
 	AuditLog AuditLogConfig `toml:"audit_log"`
 
+	// View configuration
+	Views ViewsConfig `toml:"views"`
+
+	// Soft deletion configuration
+	Deletion DeletionConfig `toml:"deletion"`
+
+	// Garbage collection of unreferenced catalog objects
+	ObjectGC ObjectGCConfig `toml:"object_gc"`
+
+	// Addresses admission webhooks may be called at
+	AdmissionWebhooks AdmissionWebhooksConfig `toml:"admission_webhooks"`
+
+	// HTTP server timeouts
+	HTTP HTTPConfig `toml:"http"`
+
+	// Cross-origin policy
+	CORS CORSConfig `toml:"cors"`
+
 	// Auth configuration
 	Auth AuthConfig `toml:"auth"`
 
@@ -134,6 +447,14 @@ type ConfigParam struct {
 		User     string `toml:"user"`     // Database user
 		Password string `toml:"password"` // Database password
 		SSLMode  string `toml:"sslmode"`  // SSL mode for database connection
+		// Whether to apply pending schema migrations on startup
+		AutoMigrate bool `toml:"auto_migrate"`
+		// Whether to restrict each connection to the rows of the request tenant
+		RowLevelSecurity bool `toml:"row_level_security"`
+		// Read replicas serving read-only requests
+		Replicas ReplicasConfig `toml:"replicas"`
+		// Connection pool tuning, applied to the primary and each replica
+		Pool PoolConfig `toml:"pool"`
 	} `toml:"db"`
 }
 
@@ -160,6 +481,7 @@ func HatchCatalogDSN() string {
 // - d: days
 // - h: hours
 // - m: minutes
+// - s: seconds
 func ParseDuration(input string) (time.Duration, error) {
 	if len(input) < 2 {
 		return 0, fmt.Errorf("invalid input format")
@@ -182,6 +504,8 @@ func ParseDuration(input string) (time.Duration, error) {
 		duration = time.Duration(value) * time.Hour
 	case "m":
 		duration = time.Duration(value) * time.Minute
+	case "s":
+		duration = time.Duration(value) * time.Second
 	case "y":
 		// Assuming 1 year = 365 days for simplicity
 		duration = time.Duration(value) * 365 * 24 * time.Hour
@@ -215,6 +539,63 @@ func ValidateConfig(cfg *ConfigParam) error {
 		return fmt.Errorf("session.max_variables must be positive")
 	}
 
+	// Views validation
+	if cfg.Views.ExpiryCheckInterval == "" {
+		cfg.Views.ExpiryCheckInterval = DefaultViewExpiryCheckInterval
+	}
+	if d, err := ParseDuration(cfg.Views.ExpiryCheckInterval); err != nil || d <= 0 {
+		return fmt.Errorf("invalid views.expiry_check_interval: %s", cfg.Views.ExpiryCheckInterval)
+	}
+
+	// Deletion validation
+	if cfg.Deletion.Retention == "" {
+		cfg.Deletion.Retention = DefaultDeletionRetention
+	}
+	if d, err := ParseDuration(cfg.Deletion.Retention); err != nil || d < 0 {
+		return fmt.Errorf("invalid deletion.retention: %s", cfg.Deletion.Retention)
+	}
+	if cfg.Deletion.PurgeInterval == "" {
+		cfg.Deletion.PurgeInterval = DefaultDeletionPurgeInterval
+	}
+	if d, err := ParseDuration(cfg.Deletion.PurgeInterval); err != nil || d <= 0 {
+		return fmt.Errorf("invalid deletion.purge_interval: %s", cfg.Deletion.PurgeInterval)
+	}
+
+	// Object GC validation
+	if cfg.ObjectGC.Interval == "" {
+		cfg.ObjectGC.Interval = DefaultObjectGCInterval
+	}
+	if d, err := ParseDuration(cfg.ObjectGC.Interval); err != nil || d <= 0 {
+		return fmt.Errorf("invalid object_gc.interval: %s", cfg.ObjectGC.Interval)
+	}
+	if cfg.ObjectGC.GracePeriod == "" {
+		cfg.ObjectGC.GracePeriod = DefaultObjectGCGracePeriod
+	}
+	if d, err := ParseDuration(cfg.ObjectGC.GracePeriod); err != nil || d < 0 {
+		return fmt.Errorf("invalid object_gc.grace_period: %s", cfg.ObjectGC.GracePeriod)
+	}
+
+	// Admission webhook validation
+	if _, err := cfg.AdmissionWebhooks.GetAllowedNetworks(); err != nil {
+		return fmt.Errorf("invalid admission_webhooks.allowed_networks: %w", err)
+	}
+
+	// HTTP validation
+	if cfg.MaxRequestBodySize == 0 {
+		cfg.MaxRequestBodySize = DefaultMaxRequestBodySize
+	}
+	if cfg.MaxRequestBodySize < 0 {
+		return fmt.Errorf("max_request_body_size must not be negative")
+	}
+	if err := validateHTTPConfig(&cfg.HTTP); err != nil {
+		return err
+	}
+
+	// CORS validation
+	if err := validateCORSConfig(&cfg.CORS); err != nil {
+		return err
+	}
+
 	// Auth validation
 	if cfg.Auth.MaxTokenAge == "" {
 		return fmt.Errorf("auth.max_token_age is required")
@@ -245,6 +626,34 @@ func ValidateConfig(cfg *ConfigParam) error {
 		}
 	}
 
+	// OIDC validation
+	if cfg.Auth.OIDC.Enabled {
+		if cfg.SingleUserMode {
+			return fmt.Errorf("auth.oidc cannot be enabled in single user mode")
+		}
+		if cfg.Auth.OIDC.Issuer == "" {
+			return fmt.Errorf("auth.oidc.issuer is required")
+		}
+		if cfg.Auth.OIDC.Audience == "" {
+			return fmt.Errorf("auth.oidc.audience is required")
+		}
+		if cfg.Auth.OIDC.UserClaim == "" {
+			cfg.Auth.OIDC.UserClaim = DefaultOIDCUserClaim
+		}
+		if cfg.Auth.OIDC.TenantClaim == "" {
+			cfg.Auth.OIDC.TenantClaim = DefaultOIDCTenantClaim
+		}
+		if cfg.Auth.OIDC.ProjectClaim == "" {
+			cfg.Auth.OIDC.ProjectClaim = DefaultOIDCProjectClaim
+		}
+		if cfg.Auth.OIDC.JWKSCacheTTL == "" {
+			cfg.Auth.OIDC.JWKSCacheTTL = DefaultOIDCJWKSCacheTTL
+		}
+		if _, err := ParseDuration(cfg.Auth.OIDC.JWKSCacheTTL); err != nil {
+			return fmt.Errorf("invalid auth.oidc.jwks_cache_ttl: %v", err)
+		}
+	}
+
 	cfg.Auth.TestUserToken = "test-user-token"
 
 	// Database validation
@@ -266,6 +675,17 @@ func ValidateConfig(cfg *ConfigParam) error {
 	if cfg.DB.SSLMode == "" {
 		return fmt.Errorf("db.sslmode is required")
 	}
+	if err := validatePoolConfig(&cfg.DB.Pool); err != nil {
+		return err
+	}
+	if len(cfg.DB.Replicas.DSNs) > 0 {
+		if cfg.DB.Replicas.MaxLag == "" {
+			cfg.DB.Replicas.MaxLag = DefaultReplicaMaxLag
+		}
+		if d, err := ParseDuration(cfg.DB.Replicas.MaxLag); err != nil || d < 0 {
+			return fmt.Errorf("invalid db.replicas.max_lag: %s", cfg.DB.Replicas.MaxLag)
+		}
+	}
 
 	// Audit log validation
 	if cfg.AuditLog.Path == "" {
@@ -302,8 +722,119 @@ func ValidateConfig(cfg *ConfigParam) error {
 		}
 		cfg.TLSCertPEM = certPEM
 		cfg.TLSKeyPEM = keyPEM
+		if cfg.TLSClientCAFile != "" {
+			if _, err := os.Stat(cfg.TLSClientCAFile); err != nil {
+				return fmt.Errorf("error reading tls client ca file: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validatePoolConfig fills in defaults for unset pool settings and checks the others
+// validateHTTPConfig applies the defaults of the server timeouts and checks them.
+func validateHTTPConfig(h *HTTPConfig) error {
+	timeouts := []struct {
+		name  string
+		value *string
+		def   string
+	}{
+		{"read_header_timeout", &h.ReadHeaderTimeout, DefaultReadHeaderTimeout},
+		{"read_timeout", &h.ReadTimeout, DefaultReadTimeout},
+		{"write_timeout", &h.WriteTimeout, DefaultWriteTimeout},
+		{"idle_timeout", &h.IdleTimeout, DefaultIdleTimeout},
+		{"handler_timeout", &h.HandlerTimeout, DefaultHandlerTimeout},
+	}
+	for _, t := range timeouts {
+		if *t.value == "" {
+			*t.value = t.def
+		}
+		if d, err := ParseDuration(*t.value); err != nil || d < 0 {
+			return fmt.Errorf("invalid http.%s: %s", t.name, *t.value)
+		}
+	}
+	// A handler timing out after the write deadline could not send its 408
+	write, _ := h.GetWriteTimeout()
+	handler, _ := h.GetHandlerTimeout()
+	if write > 0 && handler >= write {
+		return fmt.Errorf("http.handler_timeout must be shorter than http.write_timeout")
+	}
+	for route, timeout := range h.RouteTimeouts {
+		method, pattern, ok := strings.Cut(route, " ")
+		if !ok || method == "" || method != strings.ToUpper(method) || !strings.HasPrefix(pattern, "/") {
+			return fmt.Errorf("invalid http.route_timeouts route: %q, expected a method and a route pattern such as \"POST /catalogs/import\"", route)
+		}
+		if d, err := ParseDuration(timeout); err != nil || d < 0 {
+			return fmt.Errorf("invalid http.route_timeouts timeout for %s: %s", route, timeout)
+		}
 	}
+	return nil
+}
+
+// validateCORSConfig applies the defaults of the cross-origin policy and checks its origins.
+func validateCORSConfig(c *CORSConfig) error {
+	if len(c.AllowedOrigins) == 0 {
+		c.AllowedOrigins = DefaultCORSAllowedOrigins
+	}
+	if len(c.AllowedMethods) == 0 {
+		c.AllowedMethods = DefaultCORSAllowedMethods
+	}
+	if len(c.AllowedHeaders) == 0 {
+		c.AllowedHeaders = DefaultCORSAllowedHeaders
+	}
+	if len(c.ExposedHeaders) == 0 {
+		c.ExposedHeaders = DefaultCORSExposedHeaders
+	}
+	if c.MaxAge < 0 {
+		return fmt.Errorf("cors.max_age must not be negative")
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			// Browsers reject credentialed responses that allow any origin
+			if c.AllowCredentials {
+				return fmt.Errorf("cors.allowed_origins must list the origins when cors.allow_credentials is set")
+			}
+			continue
+		}
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || (u.Path != "" && u.Path != "/") || strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("invalid cors.allowed_origins entry: %s", origin)
+		}
+	}
+	return nil
+}
 
+func validatePoolConfig(p *PoolConfig) error {
+	if p.MaxOpenConns == 0 {
+		p.MaxOpenConns = DefaultPoolMaxOpenConns
+	}
+	if p.MaxOpenConns < 0 {
+		return fmt.Errorf("db.pool.max_open_conns must be positive")
+	}
+	if p.MaxIdleConns == 0 {
+		p.MaxIdleConns = min(DefaultPoolMaxIdleConns, p.MaxOpenConns)
+	}
+	if p.MaxIdleConns < 0 || p.MaxIdleConns > p.MaxOpenConns {
+		return fmt.Errorf("db.pool.max_idle_conns must be between 1 and db.pool.max_open_conns")
+	}
+	durations := []struct {
+		name  string
+		value *string
+		def   string
+	}{
+		{"db.pool.max_idle_time", &p.MaxIdleTime, DefaultPoolMaxIdleTime},
+		{"db.pool.max_lifetime", &p.MaxLifetime, DefaultPoolMaxLifetime},
+		{"db.pool.health_check_period", &p.HealthCheckPeriod, DefaultPoolHealthCheckPeriod},
+	}
+	for _, d := range durations {
+		if *d.value == "" {
+			*d.value = d.def
+		}
+		if v, err := ParseDuration(*d.value); err != nil || v <= 0 {
+			return fmt.Errorf("invalid %s: %s", d.name, *d.value)
+		}
+	}
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 )
 
@@ -9,4 +11,29 @@ func HatchCatalogDsn() string {
 	return config.HatchCatalogDSN()
 }
 
+// ReplicaDSNs returns the DSNs of the read replicas of the Hatch Catalog database
+func ReplicaDSNs() []string {
+	return config.Config().DB.Replicas.DSNs
+}
+
+// ReplicaMaxLag returns how far a read replica may lag the primary and still serve reads
+func ReplicaMaxLag() time.Duration {
+	d, err := config.Config().DB.Replicas.GetMaxLag()
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// PoolSettings returns the connection pool settings of the Hatch Catalog database
+func PoolSettings() config.PoolConfig {
+	return config.Config().DB.Pool
+}
+
+// RowLevelSecurity returns whether connections are restricted to the rows of the tenant of
+// their context
+func RowLevelSecurity() bool {
+	return config.Config().DB.RowLevelSecurity
+}
+
 const CompressCatalogObjects = config.CompressCatalogObjects
@@ -9,10 +9,13 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dbmanager"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db/postgresql"
@@ -32,10 +35,35 @@ type MetadataManager interface {
 	//Tenant and Project
 	CreateTenant(ctx context.Context, tenantID catcommon.TenantId) error
 	GetTenant(ctx context.Context, tenantID catcommon.TenantId) (*models.Tenant, error)
+	ListTenants(ctx context.Context) ([]*models.Tenant, error)
+	SetTenantSuspended(ctx context.Context, tenantID catcommon.TenantId, suspended bool) error
 	DeleteTenant(ctx context.Context, tenantID catcommon.TenantId) error
 	CreateProject(ctx context.Context, projectID catcommon.ProjectId) error
 	GetProject(ctx context.Context, projectID catcommon.ProjectId) (*models.Project, error)
 	DeleteProject(ctx context.Context, projectID catcommon.ProjectId) error
+	ListProjects(ctx context.Context) ([]*models.Project, error)
+	DumpTenant(ctx context.Context) (*models.TenantSnapshot, apperrors.Error)
+	RestoreTenant(ctx context.Context, snapshot *models.TenantSnapshot) (map[string]int64, apperrors.Error)
+
+	// TenantRole
+	SetTenantRole(ctx context.Context, role *models.TenantRole) apperrors.Error
+	GetTenantRole(ctx context.Context, userID string) (*models.TenantRole, apperrors.Error)
+	ListTenantRoles(ctx context.Context) ([]*models.TenantRole, apperrors.Error)
+	DeleteTenantRole(ctx context.Context, userID string) apperrors.Error
+
+	// User and Group
+	CreateUser(ctx context.Context, user *models.User) apperrors.Error
+	GetUser(ctx context.Context, userID string) (*models.User, apperrors.Error)
+	ListUsers(ctx context.Context) ([]*models.User, apperrors.Error)
+	DeleteUser(ctx context.Context, userID string) apperrors.Error
+	CreateGroup(ctx context.Context, group *models.Group) apperrors.Error
+	GetGroup(ctx context.Context, name string) (*models.Group, apperrors.Error)
+	ListGroups(ctx context.Context) ([]*models.Group, apperrors.Error)
+	DeleteGroup(ctx context.Context, name string) apperrors.Error
+	AddGroupMember(ctx context.Context, groupName, userID string) apperrors.Error
+	RemoveGroupMember(ctx context.Context, groupName, userID string) apperrors.Error
+	ListGroupMembers(ctx context.Context, groupName string) ([]string, apperrors.Error)
+	ListUserGroups(ctx context.Context, userID string) ([]string, apperrors.Error)
 
 	// Catalog
 	CreateCatalog(ctx context.Context, catalog *models.Catalog) apperrors.Error
@@ -44,7 +72,11 @@ type MetadataManager interface {
 	GetCatalogByName(ctx context.Context, name string) (*models.Catalog, apperrors.Error)
 	ListCatalogs(ctx context.Context) ([]*models.Catalog, apperrors.Error)
 	UpdateCatalog(ctx context.Context, catalog *models.Catalog) apperrors.Error
+	MoveCatalog(ctx context.Context, name string, toProjectID catcommon.ProjectId) apperrors.Error
 	DeleteCatalog(ctx context.Context, catalogID uuid.UUID, name string) apperrors.Error
+	RestoreCatalog(ctx context.Context, name string) apperrors.Error
+	ListDeletedCatalogs(ctx context.Context) ([]*models.Catalog, apperrors.Error)
+	PurgeDeletedCatalogs(ctx context.Context, before time.Time) (int64, apperrors.Error)
 
 	// Variant
 	CreateVariant(ctx context.Context, variant *models.Variant) apperrors.Error
@@ -54,6 +86,10 @@ type MetadataManager interface {
 	ListVariantsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]models.VariantSummary, apperrors.Error)
 	UpdateVariant(ctx context.Context, variantID uuid.UUID, name string, updatedVariant *models.Variant) apperrors.Error
 	DeleteVariant(ctx context.Context, catalogID uuid.UUID, variantID uuid.UUID, name string) apperrors.Error
+	RestoreVariant(ctx context.Context, catalogID uuid.UUID, name string) apperrors.Error
+	CloneVariant(ctx context.Context, source string, variant *models.Variant) apperrors.Error
+	ListDeletedVariantsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]models.VariantSummary, apperrors.Error)
+	PurgeDeletedVariants(ctx context.Context, before time.Time) (int64, apperrors.Error)
 	GetMetadataNames(ctx context.Context, catalogID uuid.UUID, variantID uuid.UUID) (string, string, apperrors.Error)
 
 	// Namespace
@@ -71,11 +107,27 @@ type MetadataManager interface {
 	DeleteView(ctx context.Context, viewID uuid.UUID) apperrors.Error
 	DeleteViewByLabel(ctx context.Context, label string, catalogID uuid.UUID) apperrors.Error
 	ListViewsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.View, apperrors.Error)
+	DisableExpiredViews(ctx context.Context, now time.Time) ([]*models.View, apperrors.Error)
+
+	// ViewTemplate
+	CreateViewTemplate(ctx context.Context, template *models.ViewTemplate) apperrors.Error
+	GetViewTemplateByLabel(ctx context.Context, label string, catalogID uuid.UUID) (*models.ViewTemplate, apperrors.Error)
+	UpdateViewTemplate(ctx context.Context, template *models.ViewTemplate) apperrors.Error
+	DeleteViewTemplateByLabel(ctx context.Context, label string, catalogID uuid.UUID) apperrors.Error
+	ListViewTemplatesByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.ViewTemplate, apperrors.Error)
+
+	// AdmissionWebhook
+	CreateAdmissionWebhook(ctx context.Context, webhook *models.AdmissionWebhook) apperrors.Error
+	GetAdmissionWebhookByLabel(ctx context.Context, label string, catalogID uuid.UUID) (*models.AdmissionWebhook, apperrors.Error)
+	UpdateAdmissionWebhook(ctx context.Context, webhook *models.AdmissionWebhook) apperrors.Error
+	DeleteAdmissionWebhookByLabel(ctx context.Context, label string, catalogID uuid.UUID) apperrors.Error
+	ListAdmissionWebhooksByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.AdmissionWebhook, apperrors.Error)
 
 	// Tangent
 	CreateTangent(ctx context.Context, tangent *models.Tangent) apperrors.Error
 	GetTangent(ctx context.Context, id uuid.UUID) (*models.Tangent, apperrors.Error)
 	UpdateTangent(ctx context.Context, tangent *models.Tangent) apperrors.Error
+	RecordTangentHeartbeat(ctx context.Context, id uuid.UUID, status string, health json.RawMessage) apperrors.Error
 	DeleteTangent(ctx context.Context, id uuid.UUID) apperrors.Error
 	ListTangents(ctx context.Context) ([]*models.Tangent, apperrors.Error)
 
@@ -85,6 +137,23 @@ type MetadataManager interface {
 	UpdateViewTokenExpiry(ctx context.Context, tokenID uuid.UUID, expireAt time.Time) apperrors.Error
 	DeleteViewToken(ctx context.Context, tokenID uuid.UUID) apperrors.Error
 
+	// RevokedToken
+	RevokeToken(ctx context.Context, token *models.RevokedToken) apperrors.Error
+	IsTokenRevoked(ctx context.Context, jti uuid.UUID) (bool, apperrors.Error)
+
+	// AuthzDecision
+	RecordAuthzDecision(ctx context.Context, decision *models.AuthzDecision) apperrors.Error
+	ListAuthzDecisions(ctx context.Context, filter *models.AuthzDecisionFilter) ([]*models.AuthzDecision, apperrors.Error)
+
+	// APIKey
+	CreateAPIKey(ctx context.Context, key *models.APIKey) apperrors.Error
+	GetAPIKey(ctx context.Context, keyID uuid.UUID) (*models.APIKey, apperrors.Error)
+	GetAPIKeyByHash(ctx context.Context, keyHash []byte) (*models.APIKey, apperrors.Error)
+	ListAPIKeysByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.APIKey, apperrors.Error)
+	TouchAPIKey(ctx context.Context, keyID uuid.UUID) apperrors.Error
+	RevokeAPIKey(ctx context.Context, keyID uuid.UUID) apperrors.Error
+	DeleteAPIKey(ctx context.Context, keyID uuid.UUID) apperrors.Error
+
 	// SigningKey
 	CreateSigningKey(ctx context.Context, key *models.SigningKey) apperrors.Error
 	GetSigningKey(ctx context.Context, keyID uuid.UUID) (*models.SigningKey, apperrors.Error)
@@ -100,6 +169,9 @@ type MetadataManager interface {
 	UpdateSessionInfo(ctx context.Context, sessionID uuid.UUID, info json.RawMessage) apperrors.Error
 	DeleteSession(ctx context.Context, sessionID uuid.UUID) apperrors.Error
 	ListSessionsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.Session, apperrors.Error)
+	AppendSessionInvocations(ctx context.Context, invocations []*models.SessionInvocation) apperrors.Error
+	GetLastSessionInvocation(ctx context.Context, sessionID uuid.UUID) (*models.SessionInvocation, apperrors.Error)
+	ListSessionInvocations(ctx context.Context, sessionID uuid.UUID) ([]*models.SessionInvocation, apperrors.Error)
 }
 
 // ObjectManager handles all object-related operations in the catalog service.
@@ -110,6 +182,7 @@ type ObjectManager interface {
 	CreateCatalogObject(ctx context.Context, obj *models.CatalogObject) apperrors.Error
 	GetCatalogObject(ctx context.Context, hash string) (*models.CatalogObject, apperrors.Error)
 	DeleteCatalogObject(ctx context.Context, t catcommon.CatalogObjectType, hash string) apperrors.Error
+	DeleteUnreferencedCatalogObjects(ctx context.Context, before time.Time, limit int) (int64, int64, apperrors.Error)
 
 	// Resources
 	UpsertResource(ctx context.Context, rg *models.Resource, directoryID uuid.UUID) apperrors.Error
@@ -140,6 +213,11 @@ type ObjectManager interface {
 	DeleteObjectByPath(ctx context.Context, t catcommon.CatalogObjectType, directoryID uuid.UUID, path string) (catcommon.Hash, apperrors.Error)
 	PathExists(ctx context.Context, t catcommon.CatalogObjectType, directoryID uuid.UUID, path string) (bool, apperrors.Error)
 	DeleteNamespaceObjects(ctx context.Context, t catcommon.CatalogObjectType, directoryID uuid.UUID, namespace string) ([]string, apperrors.Error)
+
+	// Search
+	SearchCatalog(ctx context.Context, catalogID, variantID uuid.UUID, terms []string, kinds []string, limit int) ([]models.SearchHit, apperrors.Error)
+	ListUnindexedCatalogObjects(ctx context.Context, limit int) ([]models.CatalogObject, apperrors.Error)
+	SetCatalogObjectSearchText(ctx context.Context, id int64, text string) apperrors.Error
 }
 
 // ConnectionManager handles database connection and scope management.
@@ -171,15 +249,22 @@ const (
 	Scope_TenantId string = "tansive.curr_tenantid"
 	// Scope_ProjectId is used to filter data by project
 	Scope_ProjectId string = "tansive.curr_projectid"
+	// Scope_AllTenants is set to "on" to admit the rows of all tenants, for platform
+	// administration and background jobs that are not scoped to a tenant
+	Scope_AllTenants string = "tansive.all_tenants"
 )
 
 var configuredScopes = []string{
 	Scope_TenantId,
 	Scope_ProjectId,
+	Scope_AllTenants,
 }
 
 var pool dbmanager.ScopedDb
 
+// replicas serves reads that tolerate staleness. It is nil if no read replicas are configured.
+var replicas dbmanager.ScopedDb
+
 // init initializes the database connection pool.
 // It attempts to create a new scoped database connection and logs any errors.
 func Init() {
@@ -189,6 +274,16 @@ func Init() {
 		panic("unable to create db pool")
 	}
 	pool = pg
+
+	if dsns := config.ReplicaDSNs(); len(dsns) > 0 {
+		rs, err := dbmanager.NewReplicaSet(dsns, configuredScopes, config.ReplicaMaxLag())
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to create replica pools")
+			panic("unable to create db replica pools")
+		}
+		log.Ctx(ctx).Info().Int("replicas", len(dsns)).Msg("read replicas configured")
+		replicas = rs
+	}
 }
 
 // Conn returns a new database connection from the pool.
@@ -205,38 +300,288 @@ func Conn(ctx context.Context) (dbmanager.ScopedConn, error) {
 	return nil, fmt.Errorf("database pool not initialized")
 }
 
+// PoolStats returns the statistics of the primary connection pool followed by those of the
+// read replica pools.
+func PoolStats() []dbmanager.PoolStats {
+	var stats []dbmanager.PoolStats
+	if pool != nil {
+		stats = append(stats, pool.PoolStats()...)
+	}
+	if replicas != nil {
+		stats = append(stats, replicas.PoolStats()...)
+	}
+	return stats
+}
+
 type ctxDbKeyType string
 
-const ctxDbKey ctxDbKeyType = "TansiveCatalogDb"
+const (
+	ctxDbKey           ctxDbKeyType = "TansiveCatalogDb"
+	ctxReadDbKey       ctxDbKeyType = "TansiveCatalogReadDb"
+	ctxReplicaReadsKey ctxDbKeyType = "TansiveCatalogReplicaReads"
+	ctxTxKey           ctxDbKeyType = "TansiveCatalogTx"
+	ctxAllTenantsKey   ctxDbKeyType = "TansiveCatalogAllTenants"
+)
 
 // ConnCtx adds a database connection to the context.
 // Returns an error if the connection cannot be established.
+// If read replicas are configured, a replica connection is opened on the first ReadDB call
+// and closed together with the primary connection.
 func ConnCtx(ctx context.Context) (context.Context, error) {
 	conn, err := Conn(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return context.WithValue(ctx, ctxDbKey, conn), nil
+	if err := syncTenantScope(ctx, conn); err != nil {
+		conn.Close(ctx)
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, ctxDbKey, conn)
+	if replicas != nil {
+		ctx = context.WithValue(ctx, ctxReadDbKey, &readConn{})
+	}
+	return ctx, nil
+}
+
+// syncTenantScope sets the tenant scope of the connection to the tenant of the context, so that
+// the row-level security policies of the database admit only the rows of that tenant. The scope
+// is synced on every use of the connection because the tenant is known only after the request
+// is authenticated, which happens after the connection is opened. It is left as is inside a
+// transaction, where a rollback would also undo the change. A connection without a tenant sees
+// no rows unless the context is marked with WithAllTenants. If row-level security is disabled,
+// every connection is admitted to the rows of all tenants. An error means the connection may
+// still carry the scope of a previous tenant and must not be used.
+func syncTenantScope(ctx context.Context, conn dbmanager.ScopedConn) error {
+	if _, inTx := ctx.Value(ctxTxKey).(*postgresql.Tx); inTx {
+		return nil
+	}
+	scopes := conn.AuthorizedScopes()
+	if !config.RowLevelSecurity() {
+		if err := syncScope(ctx, conn, scopes, Scope_AllTenants, "on"); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("unable to set all tenants scope")
+			return err
+		}
+		return nil
+	}
+	tenantID := string(catcommon.GetTenantID(ctx))
+	if err := syncScope(ctx, conn, scopes, Scope_TenantId, tenantID); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tenant_id", tenantID).Msg("unable to set tenant scope")
+		return err
+	}
+	allTenants := ""
+	if all, _ := ctx.Value(ctxAllTenantsKey).(bool); all {
+		allTenants = "on"
+	}
+	if err := syncScope(ctx, conn, scopes, Scope_AllTenants, allTenants); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("unable to set all tenants scope")
+		return err
+	}
+	return nil
+}
+
+// syncScope sets scope to value on the connection, or drops it if value is empty.
+func syncScope(ctx context.Context, conn dbmanager.ScopedConn, scopes map[string]string, scope, value string) error {
+	if scopes[scope] == value {
+		return nil
+	}
+	if value == "" {
+		return conn.DropScope(ctx, scope)
+	}
+	return conn.AddScope(ctx, scope, value)
+}
+
+// WithAllTenants marks the context as serving platform administration or a background job
+// that is not scoped to a tenant, so that row-level security admits the rows of all tenants.
+// It must not be used on paths that serve tenant requests.
+func WithAllTenants(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxAllTenantsKey, true)
+}
+
+// WithReplicaReads marks the context as tolerating the staleness of read replicas, so that
+// ReadDB may serve reads from a replica.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxReplicaReadsKey, true)
+}
+
+// readConn is the lazily opened replica connection of a context.
+type readConn struct {
+	once sync.Once
+	conn dbmanager.ScopedConn
+}
+
+func (rc *readConn) get(ctx context.Context) dbmanager.ScopedConn {
+	rc.once.Do(func() {
+		conn, err := replicas.Conn(ctx)
+		if err != nil {
+			log.Ctx(ctx).Debug().Err(err).Msg("reading from primary")
+			return
+		}
+		rc.conn = conn
+	})
+	return rc.conn
+}
+
+func (rc *readConn) close(ctx context.Context) {
+	// Prevent a connection from being opened after close
+	rc.once.Do(func() {})
+	if rc.conn != nil {
+		rc.conn.Close(ctx)
+		rc.conn = nil
+	}
 }
 
 type tansiveCatalogDb struct {
 	MetadataManager
 	ObjectManager
 	ConnectionManager
+	read *readConn
+	// borrowed is set for replica instances, whose connection is closed with the primary
+	borrowed bool
+}
+
+// closeConn returns the connection of the context, and any replica connection opened for it,
+// to the pool. Unlike DB(ctx).Close, it does not depend on the tenant scope being set.
+func closeConn(ctx context.Context, connCtx context.Context) {
+	if conn, ok := connCtx.Value(ctxDbKey).(dbmanager.ScopedConn); ok {
+		conn.Close(ctx)
+	}
+	if read, ok := connCtx.Value(ctxReadDbKey).(*readConn); ok {
+		read.close(ctx)
+	}
+}
+
+// Close returns the connection, and any replica connection opened for it, to the pool.
+func (d *tansiveCatalogDb) Close(ctx context.Context) {
+	if d.borrowed {
+		return
+	}
+	d.ConnectionManager.Close(ctx)
+	if d.read != nil {
+		d.read.close(ctx)
+	}
 }
 
 // DB returns a new database instance from the context.
 // It expects a valid database connection in the context.
-// Returns nil if no connection is found in the context.
+// If no connection is found in the context, or if the tenant scope of the connection cannot
+// be set, it returns a database on which every call fails.
 func DB(ctx context.Context) Database {
 	if conn, ok := ctx.Value(ctxDbKey).(dbmanager.ScopedConn); ok {
-		mm, om, cm := postgresql.NewHatchCatalogDb(conn)
+		if err := syncTenantScope(ctx, conn); err != nil {
+			return unavailableDB()
+		}
+		tx, _ := ctx.Value(ctxTxKey).(*postgresql.Tx)
+		mm, om, cm := postgresql.NewHatchCatalogDb(conn, tx)
+		read, _ := ctx.Value(ctxReadDbKey).(*readConn)
 		return &tansiveCatalogDb{
 			MetadataManager:   mm,
 			ObjectManager:     om,
 			ConnectionManager: cm,
+			read:              read,
 		}
 	}
 	log.Ctx(ctx).Error().Msg("unable to get db connection from context")
+	return unavailableDB()
+}
+
+// ReadDB returns a database instance for reads that tolerate staleness up to the configured
+// replica lag. Reads are served from a read replica only if the context is marked with
+// WithReplicaReads, the context is not in a transaction and a replica within the staleness
+// tolerance is available; otherwise ReadDB returns DB(ctx). The instance must not be used for writes. Closing it is a no-op; the replica
+// connection is closed together with the primary connection.
+func ReadDB(ctx context.Context) Database {
+	rc, ok := ctx.Value(ctxReadDbKey).(*readConn)
+	if !ok || replicas == nil {
+		return DB(ctx)
+	}
+	if replicaReads, _ := ctx.Value(ctxReplicaReadsKey).(bool); !replicaReads {
+		return DB(ctx)
+	}
+	if _, inTx := ctx.Value(ctxTxKey).(*postgresql.Tx); inTx {
+		return DB(ctx)
+	}
+	conn := rc.get(ctx)
+	if conn == nil || syncTenantScope(ctx, conn) != nil {
+		return DB(ctx)
+	}
+	mm, om, cm := postgresql.NewHatchCatalogDb(conn, nil)
+	return &tansiveCatalogDb{
+		MetadataManager:   mm,
+		ObjectManager:     om,
+		ConnectionManager: cm,
+		borrowed:          true,
+	}
+}
+
+// LockKey takes a lock on key that is held until the transaction of the context ends, so that
+// transactions writing the object the key names are serialized. Keys are scoped to the tenant
+// of the context. It returns an error if the context is not in a transaction.
+func LockKey(ctx context.Context, key string) apperrors.Error {
+	tx, inTx := ctx.Value(ctxTxKey).(*postgresql.Tx)
+	if !inTx {
+		log.Ctx(ctx).Error().Str("key", key).Msg("lock taken outside a transaction")
+		return dberror.ErrDatabase.Msg("lock requires a transaction")
+	}
+	if err := tx.Lock(ctx, string(catcommon.GetTenantID(ctx))+":"+key); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("key", key).Msg("failed to take lock")
+		return dberror.ErrDatabase.Err(err)
+	}
+	return nil
+}
+
+// Tx runs fn in a transaction on the connection of the context. The calls fn makes with DB(ctx)
+// on the context passed to it are committed together if fn returns nil, and rolled back
+// otherwise. A Tx inside fn runs as a savepoint of the enclosing transaction, so that its
+// changes are undone on failure without aborting the enclosing transaction.
+func Tx(ctx context.Context, fn func(ctx context.Context) apperrors.Error) apperrors.Error {
+	if tx, inTx := ctx.Value(ctxTxKey).(*postgresql.Tx); inTx {
+		sp, err := tx.Savepoint(ctx)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to start savepoint")
+			return dberror.ErrDatabase.Err(err)
+		}
+		if appErr := fn(ctx); appErr != nil {
+			if err := sp.Rollback(); err != nil {
+				log.Ctx(ctx).Error().Err(err).Msg("failed to rollback savepoint")
+			}
+			return appErr
+		}
+		if err := sp.Commit(); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to release savepoint")
+			return dberror.ErrDatabase.Err(err)
+		}
+		return nil
+	}
+	conn, ok := ctx.Value(ctxDbKey).(dbmanager.ScopedConn)
+	if !ok {
+		log.Ctx(ctx).Error().Msg("unable to get db connection from context")
+		return dberror.ErrDatabase.Msg("no database connection")
+	}
+	if err := syncTenantScope(ctx, conn); err != nil {
+		return dberror.ErrDatabase.Err(err)
+	}
+
+	tx, err := postgresql.BeginTx(ctx, conn)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to begin transaction")
+		return dberror.ErrDatabase.Err(err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if appErr := fn(context.WithValue(ctx, ctxTxKey, tx)); appErr != nil {
+		if err := tx.Rollback(); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to rollback transaction")
+		}
+		return appErr
+	}
+	if err := tx.Commit(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to commit transaction")
+		return dberror.ErrDatabase.Err(err)
+	}
 	return nil
 }
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestAdmissionWebhooks(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	assert.NoError(t, DB(ctx).CreateTenant(ctx, tenantID))
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	assert.NoError(t, DB(ctx).CreateProject(ctx, projectID))
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	var info pgtype.JSONB
+	assert.NoError(t, info.Set(`{"meta": "test"}`))
+	catalog := models.Catalog{
+		Name:        "test_catalog_admission_webhook",
+		Description: "Catalog for admission webhook test",
+		Info:        info,
+	}
+	require.NoError(t, DB(ctx).CreateCatalog(ctx, &catalog))
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	webhook := &models.AdmissionWebhook{
+		Label:       "policy-check",
+		Description: "checks values against org policy",
+		Spec:        []byte(`{"url": "https://policy.example.com/admit", "type": "Validating", "operations": ["value"]}`),
+		CatalogID:   catalog.CatalogID,
+		CreatedBy:   "user/test",
+	}
+	require.NoError(t, DB(ctx).CreateAdmissionWebhook(ctx, webhook))
+	assert.NotEmpty(t, webhook.WebhookID)
+
+	err := DB(ctx).CreateAdmissionWebhook(ctx, &models.AdmissionWebhook{
+		Label:     "policy-check",
+		Spec:      []byte(`{}`),
+		CatalogID: catalog.CatalogID,
+		CreatedBy: "user/test",
+	})
+	assert.True(t, errors.Is(err, dberror.ErrAlreadyExists))
+
+	got, err := DB(ctx).GetAdmissionWebhookByLabel(ctx, "policy-check", catalog.CatalogID)
+	require.NoError(t, err)
+	assert.Equal(t, webhook.WebhookID, got.WebhookID)
+	assert.Equal(t, "checks values against org policy", got.Description)
+	assert.JSONEq(t, string(webhook.Spec), string(got.Spec))
+
+	webhook.Description = ""
+	webhook.Spec = []byte(`{"url": "https://policy.example.com/admit", "type": "Mutating", "operations": ["schema"]}`)
+	webhook.UpdatedBy = "user/other"
+	require.NoError(t, DB(ctx).UpdateAdmissionWebhook(ctx, webhook))
+	got, err = DB(ctx).GetAdmissionWebhookByLabel(ctx, "policy-check", catalog.CatalogID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Description)
+	assert.Equal(t, "user/other", got.UpdatedBy)
+
+	webhooks, err := DB(ctx).ListAdmissionWebhooksByCatalog(ctx, catalog.CatalogID)
+	require.NoError(t, err)
+	assert.Len(t, webhooks, 1)
+
+	require.NoError(t, DB(ctx).DeleteAdmissionWebhookByLabel(ctx, "policy-check", catalog.CatalogID))
+	_, err = DB(ctx).GetAdmissionWebhookByLabel(ctx, "policy-check", catalog.CatalogID)
+	assert.True(t, errors.Is(err, dberror.ErrNotFound))
+	err = DB(ctx).DeleteAdmissionWebhookByLabel(ctx, "policy-check", catalog.CatalogID)
+	assert.True(t, errors.Is(err, dberror.ErrNotFound))
+}
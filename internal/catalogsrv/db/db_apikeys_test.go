@@ -0,0 +1,95 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestAPIKeys(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	require.NoError(t, DB(ctx).CreateTenant(ctx, tenantID))
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	require.NoError(t, DB(ctx).CreateProject(ctx, projectID))
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	catalog := models.Catalog{
+		Name: "test_catalog_apikeys",
+	}
+	require.NoError(t, DB(ctx).CreateCatalog(ctx, &catalog))
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	view := models.View{
+		Label:     "apikey-view",
+		Rules:     []byte(`{"rules": []}`),
+		CatalogID: catalog.CatalogID,
+		CreatedBy: "test_user",
+	}
+	require.NoError(t, DB(ctx).CreateView(ctx, &view))
+
+	hash := sha256.Sum256([]byte("secret"))
+	key := &models.APIKey{
+		Name:      "ci-key",
+		KeyPrefix: "tnsv_ak_abcdefgh",
+		KeyHash:   hash[:],
+		ViewID:    view.ViewID,
+		CatalogID: catalog.CatalogID,
+		ProjectID: projectID,
+		CreatedBy: "test_user",
+		ExpireAt:  time.Now().Add(time.Hour),
+	}
+	require.NoError(t, DB(ctx).CreateAPIKey(ctx, key))
+	assert.NotZero(t, key.CreatedAt)
+
+	// Duplicate name in the same catalog
+	dup := *key
+	dup.KeyHash = []byte("other")
+	assert.ErrorIs(t, DB(ctx).CreateAPIKey(ctx, &dup), dberror.ErrAlreadyExists)
+
+	// Missing fields
+	assert.ErrorIs(t, DB(ctx).CreateAPIKey(ctx, &models.APIKey{Name: "x"}), dberror.ErrInvalidInput)
+
+	got, err := DB(ctx).GetAPIKeyByHash(ctx, hash[:])
+	require.NoError(t, err)
+	assert.Equal(t, key.KeyID, got.KeyID)
+	assert.Equal(t, tenantID, got.TenantID)
+	assert.Nil(t, got.LastUsedAt)
+	assert.True(t, got.IsActive(time.Now()))
+
+	require.NoError(t, DB(ctx).TouchAPIKey(ctx, key.KeyID))
+	got, err = DB(ctx).GetAPIKey(ctx, key.KeyID)
+	require.NoError(t, err)
+	assert.NotNil(t, got.LastUsedAt)
+
+	keys, err := DB(ctx).ListAPIKeysByCatalog(ctx, catalog.CatalogID)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	require.NoError(t, DB(ctx).RevokeAPIKey(ctx, key.KeyID))
+	got, err = DB(ctx).GetAPIKey(ctx, key.KeyID)
+	require.NoError(t, err)
+	require.NotNil(t, got.RevokedAt)
+	assert.False(t, got.IsActive(time.Now()))
+
+	require.NoError(t, DB(ctx).DeleteAPIKey(ctx, key.KeyID))
+	_, err = DB(ctx).GetAPIKey(ctx, key.KeyID)
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+	assert.ErrorIs(t, DB(ctx).RevokeAPIKey(ctx, key.KeyID), dberror.ErrNotFound)
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+func TestAuthzDecisions(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+
+	// Create test tenant
+	err := DB(ctx).CreateTenant(ctx, tenantID)
+	require.NoError(t, err)
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	catalogID := uuid.New()
+	record := func(principal, action, target string, allowed bool) *models.AuthzDecision {
+		d := &models.AuthzDecision{
+			CatalogID:    catalogID,
+			Principal:    principal,
+			Actions:      []string{action},
+			Target:       target,
+			Allowed:      allowed,
+			Enforced:     true,
+			MatchedRules: []byte(`{"Allow":[],"Deny":[]}`),
+		}
+		require.NoError(t, DB(ctx).RecordAuthzDecision(ctx, d))
+		require.NotEqual(t, uuid.Nil, d.DecisionID)
+		return d
+	}
+	record("user/alice", "system.resource.get", "res://catalogs/c1/resources/a", true)
+	record("user/bob", "system.resource.put", "res://catalogs/c1/resources/a", false)
+	last := record("user/bob", "system.resource.get", "res://catalogs/c1/resources/b_c", true)
+
+	decisions, err := DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{CatalogID: catalogID})
+	require.NoError(t, err)
+	require.Len(t, decisions, 3)
+	// Newest first
+	assert.Equal(t, last.DecisionID, decisions[0].DecisionID)
+	assert.Equal(t, catalogID, decisions[0].CatalogID)
+	assert.Equal(t, uuid.Nil, decisions[0].ViewID)
+	assert.Equal(t, []string{"system.resource.get"}, decisions[0].Actions)
+	assert.JSONEq(t, `{"Allow":[],"Deny":[]}`, string(decisions[0].MatchedRules))
+
+	denied := false
+	decisions, err = DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{Allowed: &denied})
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, "user/bob", decisions[0].Principal)
+
+	decisions, err = DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{Principal: "user/bob", Action: "system.resource.get"})
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+
+	// Targets match as a prefix, with LIKE wildcards taken literally
+	decisions, err = DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{Target: "res://catalogs/c1/resources/"})
+	require.NoError(t, err)
+	assert.Len(t, decisions, 3)
+	decisions, err = DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{Target: "res://catalogs/c1/resources/b_"})
+	require.NoError(t, err)
+	assert.Len(t, decisions, 1)
+	decisions, err = DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{Target: "res://catalogs/c1/resources/%"})
+	require.NoError(t, err)
+	assert.Len(t, decisions, 0)
+
+	decisions, err = DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{Since: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Len(t, decisions, 0)
+
+	// Pagination
+	page, err := DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	rest, err := DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{
+		After: &models.AuthzDecisionCursor{CreatedAt: page[1].CreatedAt, DecisionID: page[1].DecisionID},
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.NotContains(t, []uuid.UUID{page[0].DecisionID, page[1].DecisionID}, rest[0].DecisionID)
+
+	// Denials that were let through in audit mode
+	auditCatalogID := uuid.New()
+	require.NoError(t, DB(ctx).RecordAuthzDecision(ctx, &models.AuthzDecision{
+		CatalogID: auditCatalogID,
+		Principal: "user/carol",
+		Actions:   []string{"system.resource.put"},
+		Target:    "res://catalogs/c2/resources/a",
+	}))
+	notEnforced := false
+	decisions, err = DB(ctx).ListAuthzDecisions(ctx, &models.AuthzDecisionFilter{Enforced: &notEnforced})
+	require.NoError(t, err)
+	require.Len(t, decisions, 1)
+	assert.Equal(t, auditCatalogID, decisions[0].CatalogID)
+	assert.False(t, decisions[0].Enforced)
+
+	// Invalid input
+	err = DB(ctx).RecordAuthzDecision(ctx, &models.AuthzDecision{Actions: []string{"a"}, Target: "t"})
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+	err = DB(ctx).RecordAuthzDecision(ctx, &models.AuthzDecision{Principal: "user/alice", Target: "t"})
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+}
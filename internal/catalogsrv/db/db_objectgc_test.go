@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestDeleteUnreferencedCatalogObjects(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	err = DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	catalog := models.Catalog{
+		Name: "test_catalog",
+		Info: pgtype.JSONB{Status: pgtype.Null},
+	}
+	err = DB(ctx).CreateCatalog(ctx, &catalog)
+	require.NoError(t, err)
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	variant := models.Variant{
+		Name:      "test_variant",
+		CatalogID: catalog.CatalogID,
+		Info:      pgtype.JSONB{Status: pgtype.Null},
+	}
+	err = DB(ctx).CreateVariant(ctx, &variant)
+	require.NoError(t, err)
+
+	upsert := func(path, hash string) {
+		err := DB(ctx).UpsertResourceObject(ctx,
+			&models.Resource{Path: path, Hash: hash, VariantID: variant.VariantID},
+			&models.CatalogObject{
+				Hash:    hash,
+				Type:    catcommon.CatalogObjectTypeResource,
+				Version: "0.1.0-alpha.1",
+				Data:    []byte(`{"key": "value"}`),
+			},
+			variant.ResourceDirectoryID)
+		require.NoError(t, err)
+	}
+	upsert("/gc/kept", "gc_kept_hash_0123456789")
+	upsert("/gc/orphaned", "gc_orphaned_hash_0123456789")
+
+	// Deleting the directory entry alone leaves the object behind
+	_, err = DB(ctx).DeleteObjectByPath(ctx, catcommon.CatalogObjectTypeResource, variant.ResourceDirectoryID, "/gc/orphaned")
+	require.NoError(t, err)
+	_, err = DB(ctx).GetCatalogObject(ctx, "gc_orphaned_hash_0123456789")
+	require.NoError(t, err)
+
+	// Objects changed after the cutoff are kept
+	objects, _, err := DB(ctx).DeleteUnreferencedCatalogObjects(ctx, time.Now().Add(-time.Hour), 100)
+	require.NoError(t, err)
+	assert.Zero(t, objects)
+
+	objects, bytes, err := DB(ctx).DeleteUnreferencedCatalogObjects(ctx, time.Now().Add(time.Minute), 100)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, objects)
+	assert.Positive(t, bytes)
+
+	_, err = DB(ctx).GetCatalogObject(ctx, "gc_orphaned_hash_0123456789")
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+	_, err = DB(ctx).GetCatalogObject(ctx, "gc_kept_hash_0123456789")
+	assert.NoError(t, err)
+
+	_, _, err = DB(ctx).DeleteUnreferencedCatalogObjects(ctx, time.Now(), 0)
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+}
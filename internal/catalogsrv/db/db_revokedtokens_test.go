@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+func TestRevokeToken(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+
+	// Create test tenant
+	err := DB(ctx).CreateTenant(ctx, tenantID)
+	require.NoError(t, err)
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	jti := uuid.New()
+	revoked, err := DB(ctx).IsTokenRevoked(ctx, jti)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	token := &models.RevokedToken{
+		JTI:       jti,
+		ExpireAt:  time.Now().Add(time.Hour),
+		RevokedBy: "user/test-user",
+	}
+	err = DB(ctx).RevokeToken(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, tenantID, token.TenantID)
+
+	revoked, err = DB(ctx).IsTokenRevoked(ctx, jti)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// Revoking the same token again is not an error
+	err = DB(ctx).RevokeToken(ctx, token)
+	assert.NoError(t, err)
+
+	// The denylist is scoped to the tenant
+	otherTenantID := catcommon.TenantId("TFGHIJ")
+	err = DB(ctx).CreateTenant(ctx, otherTenantID)
+	require.NoError(t, err)
+	defer DB(ctx).DeleteTenant(ctx, otherTenantID)
+	otherCtx := catcommon.WithTenantID(ctx, otherTenantID)
+	revoked, err = DB(otherCtx).IsTokenRevoked(otherCtx, jti)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Expired entries are purged when another token is revoked
+	expiredJTI := uuid.New()
+	err = DB(ctx).RevokeToken(ctx, &models.RevokedToken{
+		JTI:      expiredJTI,
+		ExpireAt: time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	err = DB(ctx).RevokeToken(ctx, &models.RevokedToken{
+		JTI:      uuid.New(),
+		ExpireAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	revoked, err = DB(ctx).IsTokenRevoked(ctx, expiredJTI)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Invalid input
+	err = DB(ctx).RevokeToken(ctx, &models.RevokedToken{ExpireAt: time.Now().Add(time.Hour)})
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+	err = DB(ctx).RevokeToken(ctx, &models.RevokedToken{JTI: uuid.New()})
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+}
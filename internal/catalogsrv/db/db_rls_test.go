@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dbmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestRowLevelSecurity(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	config.Config().DB.RowLevelSecurity = true
+	defer func() { config.Config().DB.RowLevelSecurity = false }()
+
+	tenantA := catcommon.TenantId("TRLSAA")
+	tenantB := catcommon.TenantId("TRLSBB")
+	projectID := catcommon.ProjectId("P12345")
+
+	ctxA := catcommon.WithProjectID(catcommon.WithTenantID(ctx, tenantA), projectID)
+	ctxB := catcommon.WithProjectID(catcommon.WithTenantID(ctx, tenantB), projectID)
+
+	for _, c := range []context.Context{ctxA, ctxB} {
+		tenantID := catcommon.GetTenantID(c)
+		require.NoError(t, DB(c).CreateTenant(c, tenantID))
+		defer DB(c).DeleteTenant(c, tenantID)
+		require.NoError(t, DB(c).CreateProject(c, projectID))
+	}
+
+	catalog := models.Catalog{
+		Name: "rls_catalog",
+		Info: pgtype.JSONB{Status: pgtype.Null},
+	}
+	require.NoError(t, DB(ctxA).CreateCatalog(ctxA, &catalog))
+
+	// countCatalogs counts the catalogs named rls_catalog without filtering by tenant, as a
+	// query that forgets the tenant filter would
+	countCatalogs := func(ctx context.Context) int {
+		DB(ctx) // syncs the tenant scope of the connection
+		conn := ctx.Value(ctxDbKey).(dbmanager.ScopedConn)
+		var n int
+		err := conn.Conn().QueryRowContext(ctx, "SELECT count(*) FROM catalogs WHERE name = 'rls_catalog'").Scan(&n)
+		require.NoError(t, err)
+		return n
+	}
+
+	assert.Equal(t, 1, countCatalogs(ctxA))
+	assert.Equal(t, 0, countCatalogs(ctxB))
+	// Without a tenant no rows are visible, unless the context admits all tenants as in
+	// background jobs
+	assert.Equal(t, 0, countCatalogs(ctx))
+	assert.Equal(t, 1, countCatalogs(WithAllTenants(ctx)))
+	assert.Equal(t, 1, countCatalogs(ctxA))
+}
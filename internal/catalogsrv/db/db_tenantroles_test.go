@@ -0,0 +1,62 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestTenantRoles(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+
+	// Create test tenant
+	err := DB(ctx).CreateTenant(ctx, tenantID)
+	require.NoError(t, err)
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	_, err = DB(ctx).GetTenantRole(ctx, "alice")
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+
+	role := &models.TenantRole{UserID: "alice", Role: "owner", CreatedBy: "bootstrap"}
+	err = DB(ctx).SetTenantRole(ctx, role)
+	require.NoError(t, err)
+	assert.Equal(t, tenantID, role.TenantID)
+
+	// Setting the role again replaces it and keeps who granted it first
+	err = DB(ctx).SetTenantRole(ctx, &models.TenantRole{UserID: "alice", Role: "admin", CreatedBy: "bob"})
+	require.NoError(t, err)
+	got, err := DB(ctx).GetTenantRole(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "admin", got.Role)
+	assert.Equal(t, "bootstrap", got.CreatedBy)
+
+	err = DB(ctx).SetTenantRole(ctx, &models.TenantRole{UserID: "bob", Role: "viewer"})
+	require.NoError(t, err)
+	roles, err := DB(ctx).ListTenantRoles(ctx)
+	require.NoError(t, err)
+	require.Len(t, roles, 2)
+	assert.Equal(t, "alice", roles[0].UserID)
+	assert.Equal(t, "bob", roles[1].UserID)
+
+	err = DB(ctx).DeleteTenantRole(ctx, "bob")
+	require.NoError(t, err)
+	_, err = DB(ctx).GetTenantRole(ctx, "bob")
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+
+	// Invalid input
+	err = DB(ctx).SetTenantRole(ctx, &models.TenantRole{UserID: "carol", Role: "superuser"})
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+	err = DB(ctx).SetTenantRole(ctx, &models.TenantRole{Role: "viewer"})
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+func TestDumpAndRestoreTenant(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	source := catcommon.TenantId("TSNAPA")
+	target := catcommon.TenantId("TSNAPB")
+	projectID := catcommon.ProjectId("P12345")
+
+	sourceCtx := catcommon.WithProjectID(catcommon.WithTenantID(ctx, source), projectID)
+	targetCtx := catcommon.WithProjectID(catcommon.WithTenantID(ctx, target), projectID)
+
+	require.NoError(t, DB(sourceCtx).CreateTenant(sourceCtx, source))
+	defer DB(sourceCtx).DeleteTenant(sourceCtx, source)
+	require.NoError(t, DB(sourceCtx).CreateProject(sourceCtx, projectID))
+	require.NoError(t, DB(targetCtx).CreateTenant(targetCtx, target))
+	defer DB(targetCtx).DeleteTenant(targetCtx, target)
+
+	catalog := models.Catalog{
+		Name: "snapshot_catalog",
+		Info: pgtype.JSONB{Status: pgtype.Null},
+	}
+	require.NoError(t, DB(sourceCtx).CreateCatalog(sourceCtx, &catalog))
+	variant := models.Variant{
+		Name:      "snapshot_variant",
+		CatalogID: catalog.CatalogID,
+		Info:      pgtype.JSONB{Status: pgtype.Null},
+	}
+	require.NoError(t, DB(sourceCtx).CreateVariant(sourceCtx, &variant))
+	err := DB(sourceCtx).UpsertResourceObject(sourceCtx,
+		&models.Resource{Path: "/snapshot/resource", Hash: "snapshot_hash_0123456789", VariantID: variant.VariantID},
+		&models.CatalogObject{
+			Hash:    "snapshot_hash_0123456789",
+			Type:    catcommon.CatalogObjectTypeResource,
+			Version: "0.1.0-alpha.1",
+			Data:    []byte(`{"key": "value"}`),
+		},
+		variant.ResourceDirectoryID)
+	require.NoError(t, err)
+
+	snapshot, err := DB(sourceCtx).DumpTenant(sourceCtx)
+	require.NoError(t, err)
+	assert.Equal(t, source, snapshot.TenantID)
+	assert.Contains(t, string(snapshot.Tables["catalogs"]), "snapshot_catalog")
+
+	restored, err := DB(targetCtx).RestoreTenant(targetCtx, snapshot)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, restored["projects"])
+	assert.EqualValues(t, 1, restored["catalogs"])
+	assert.EqualValues(t, 1, restored["catalog_objects"])
+
+	// The restored rows belong to the target tenant and keep their IDs
+	c, err := DB(targetCtx).GetCatalogByName(targetCtx, "snapshot_catalog")
+	require.NoError(t, err)
+	assert.Equal(t, catalog.CatalogID, c.CatalogID)
+	v, err := DB(targetCtx).GetVariant(targetCtx, catalog.CatalogID, uuid.Nil, "snapshot_variant")
+	require.NoError(t, err)
+	obj, err := DB(targetCtx).GetResourceObject(targetCtx, "/snapshot/resource", v.ResourceDirectoryID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"key": "value"}`, string(obj.Data))
+
+	// A tenant with catalogs cannot be restored into
+	_, err = DB(targetCtx).RestoreTenant(targetCtx, snapshot)
+	assert.ErrorIs(t, err, dberror.ErrAlreadyExists)
+
+	snapshot.Tables["signing_keys"] = []byte(`[]`)
+	_, err = DB(targetCtx).RestoreTenant(targetCtx, snapshot)
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+}
@@ -33,6 +33,19 @@ func TestCreateTenant(t *testing.T) {
 	assert.ErrorIs(t, err, dberror.ErrAlreadyExists)
 }
 
+func TestDBWithoutConnection(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = catcommon.WithTenantID(ctx, catcommon.TenantId("TABCDE"))
+
+	// Without a connection in the context, every call fails instead of panicking
+	tenant, err := DB(ctx).GetTenant(ctx, catcommon.TenantId("TABCDE"))
+	assert.Error(t, err)
+	assert.Nil(t, tenant)
+	assert.Error(t, DB(ctx).CreateTenant(ctx, catcommon.TenantId("TABCDE")))
+	assert.Error(t, DB(ctx).AddScope(ctx, Scope_TenantId, "TABCDE"))
+	DB(ctx).Close(ctx)
+}
+
 func TestGetTenant(t *testing.T) {
 	// Initialize context with logger and database connection
 	ctx := log.Logger.WithContext(context.Background())
@@ -60,6 +73,53 @@ func TestGetTenant(t *testing.T) {
 	assert.ErrorIs(t, err, dberror.ErrNotFound)
 }
 
+func TestSuspendTenant(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	err := DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+
+	tenant, err := DB(ctx).GetTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	assert.Nil(t, tenant.SuspendedAt)
+
+	// Suspending again keeps the time of the first suspension
+	assert.NoError(t, DB(ctx).SetTenantSuspended(ctx, tenantID, true))
+	tenant, err = DB(ctx).GetTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	if assert.NotNil(t, tenant.SuspendedAt) {
+		suspendedAt := *tenant.SuspendedAt
+		assert.NoError(t, DB(ctx).SetTenantSuspended(ctx, tenantID, true))
+		tenant, err = DB(ctx).GetTenant(ctx, tenantID)
+		assert.NoError(t, err)
+		assert.True(t, suspendedAt.Equal(*tenant.SuspendedAt))
+	}
+
+	tenants, err := DB(ctx).ListTenants(ctx)
+	assert.NoError(t, err)
+	found := false
+	for _, tn := range tenants {
+		if tn.TenantID == tenantID {
+			found = true
+			assert.NotNil(t, tn.SuspendedAt)
+		}
+	}
+	assert.True(t, found)
+
+	assert.NoError(t, DB(ctx).SetTenantSuspended(ctx, tenantID, false))
+	tenant, err = DB(ctx).GetTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	assert.Nil(t, tenant.SuspendedAt)
+
+	err = DB(ctx).SetTenantSuspended(ctx, "nonexistent", true)
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+}
+
 func TestCreateProject(t *testing.T) {
 	// Initialize context with logger and database connection
 	ctx := log.Logger.WithContext(context.Background())
@@ -364,6 +424,54 @@ func TestUpdateCatalog(t *testing.T) {
 	assert.ErrorIs(t, err, dberror.ErrNotFound)
 }
 
+func TestMoveCatalog(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	otherProjectID := catcommon.ProjectId("P67890")
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	assert.NoError(t, DB(ctx).CreateProject(ctx, projectID))
+	assert.NoError(t, DB(ctx).CreateProject(ctx, otherProjectID))
+
+	catalog := models.Catalog{
+		Name:        "test_catalog",
+		Description: "A test catalog",
+		Info:        pgtype.JSONB{Status: pgtype.Null},
+	}
+	assert.NoError(t, DB(ctx).CreateCatalog(ctx, &catalog))
+
+	// The catalog keeps its ID in the other project
+	assert.NoError(t, DB(ctx).MoveCatalog(ctx, "test_catalog", otherProjectID))
+	_, err = DB(ctx).GetCatalogByName(ctx, "test_catalog")
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+	otherCtx := catcommon.WithProjectID(ctx, otherProjectID)
+	moved, err := DB(otherCtx).GetCatalogByName(otherCtx, "test_catalog")
+	assert.NoError(t, err)
+	assert.Equal(t, catalog.CatalogID, moved.CatalogID)
+	assert.Equal(t, otherProjectID, moved.ProjectID)
+
+	err = DB(ctx).MoveCatalog(ctx, "test_catalog", otherProjectID)
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+
+	// Names are unique within a project
+	assert.NoError(t, DB(ctx).CreateCatalog(ctx, &models.Catalog{Name: "test_catalog", Info: pgtype.JSONB{Status: pgtype.Null}}))
+	err = DB(ctx).MoveCatalog(ctx, "test_catalog", otherProjectID)
+	assert.ErrorIs(t, err, dberror.ErrAlreadyExists)
+
+	err = DB(ctx).MoveCatalog(ctx, "test_catalog", "PNONE")
+	assert.ErrorIs(t, err, dberror.ErrInvalidInput)
+}
+
 func TestDeleteCatalog(t *testing.T) {
 	// Initialize context with logger and database connection
 	ctx := log.Logger.WithContext(context.Background())
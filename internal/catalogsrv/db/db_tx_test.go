@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+func TestTx(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	err = DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	catalog := models.Catalog{
+		Name: "test_catalog",
+		Info: pgtype.JSONB{Status: pgtype.Null},
+	}
+	err = DB(ctx).CreateCatalog(ctx, &catalog)
+	require.NoError(t, err)
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	variant := models.Variant{
+		Name:      "test_variant",
+		CatalogID: catalog.CatalogID,
+		Info:      pgtype.JSONB{Status: pgtype.Null},
+	}
+	err = DB(ctx).CreateVariant(ctx, &variant)
+	require.NoError(t, err)
+
+	newResource := func(path, hash string) (*models.Resource, *models.CatalogObject) {
+		return &models.Resource{Path: path, Hash: hash, VariantID: variant.VariantID},
+			&models.CatalogObject{
+				Hash:    hash,
+				Type:    catcommon.CatalogObjectTypeResource,
+				Version: "0.1.0-alpha.1",
+				Data:    []byte(`{"key": "value"}`),
+			}
+	}
+	errAbort := dberror.ErrInvalidInput.Msg("abort")
+
+	// A failed transaction leaves neither the object nor its directory entry behind
+	rg, obj := newResource("/tx/rolled-back", "tx_rolled_back_hash_0123456789")
+	err = Tx(ctx, func(ctx context.Context) apperrors.Error {
+		if err := DB(ctx).UpsertResourceObject(ctx, rg, obj, variant.ResourceDirectoryID); err != nil {
+			return err
+		}
+		// Manager methods with transactions of their own are undone too
+		if err := DB(ctx).CreateNamespace(ctx, &models.Namespace{Name: "tx-namespace", VariantID: variant.VariantID}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+	_, err = DB(ctx).GetResource(ctx, rg.Path, variant.VariantID, variant.ResourceDirectoryID)
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+	_, err = DB(ctx).GetCatalogObject(ctx, obj.Hash)
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+	_, err = DB(ctx).GetNamespace(ctx, "tx-namespace", variant.VariantID)
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+
+	// A successful transaction commits all its changes, including those of nested transactions
+	rg, obj = newResource("/tx/committed", "tx_committed_hash_0123456789")
+	err = Tx(ctx, func(ctx context.Context) apperrors.Error {
+		return Tx(ctx, func(ctx context.Context) apperrors.Error {
+			return DB(ctx).UpsertResourceObject(ctx, rg, obj, variant.ResourceDirectoryID)
+		})
+	})
+	require.NoError(t, err)
+	_, err = DB(ctx).GetResource(ctx, rg.Path, variant.VariantID, variant.ResourceDirectoryID)
+	assert.NoError(t, err)
+	_, err = DB(ctx).GetCatalogObject(ctx, obj.Hash)
+	assert.NoError(t, err)
+
+	// A method that fails inside a transaction is undone without aborting the transaction
+	err = Tx(ctx, func(ctx context.Context) apperrors.Error {
+		ns := &models.Namespace{Name: "tx-namespace", VariantID: variant.VariantID}
+		if err := DB(ctx).CreateNamespace(ctx, ns); err != nil {
+			return err
+		}
+		assert.Error(t, DB(ctx).CreateNamespace(ctx, ns))
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = DB(ctx).GetNamespace(ctx, "tx-namespace", variant.VariantID)
+	assert.NoError(t, err)
+
+	// A nested transaction that fails is undone without undoing the enclosing transaction
+	rg, obj = newResource("/tx/outer", "tx_outer_hash_0123456789")
+	nestedRg, nestedObj := newResource("/tx/nested", "tx_nested_hash_0123456789")
+	err = Tx(ctx, func(ctx context.Context) apperrors.Error {
+		if err := DB(ctx).UpsertResourceObject(ctx, rg, obj, variant.ResourceDirectoryID); err != nil {
+			return err
+		}
+		err := Tx(ctx, func(ctx context.Context) apperrors.Error {
+			if err := DB(ctx).UpsertResourceObject(ctx, nestedRg, nestedObj, variant.ResourceDirectoryID); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		assert.ErrorIs(t, err, errAbort)
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = DB(ctx).GetResource(ctx, rg.Path, variant.VariantID, variant.ResourceDirectoryID)
+	assert.NoError(t, err)
+	_, err = DB(ctx).GetResource(ctx, nestedRg.Path, variant.VariantID, variant.ResourceDirectoryID)
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+}
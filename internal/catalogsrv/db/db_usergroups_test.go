@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestUsersAndGroups(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+
+	// Create test tenant
+	err := DB(ctx).CreateTenant(ctx, tenantID)
+	require.NoError(t, err)
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	user := &models.User{UserID: "alice", DisplayName: "Alice", Email: "alice@example.com"}
+	err = DB(ctx).CreateUser(ctx, user)
+	require.NoError(t, err)
+	assert.Equal(t, tenantID, user.TenantID)
+	err = DB(ctx).CreateUser(ctx, &models.User{UserID: "alice"})
+	assert.ErrorIs(t, err, dberror.ErrAlreadyExists)
+	err = DB(ctx).CreateUser(ctx, &models.User{UserID: "bob"})
+	require.NoError(t, err)
+
+	got, err := DB(ctx).GetUser(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", got.DisplayName)
+	assert.Equal(t, "alice@example.com", got.Email)
+	_, err = DB(ctx).GetUser(ctx, "carol")
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+
+	users, err := DB(ctx).ListUsers(ctx)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "alice", users[0].UserID)
+
+	err = DB(ctx).CreateGroup(ctx, &models.Group{Name: "analysts", Description: "Data analysts"})
+	require.NoError(t, err)
+	err = DB(ctx).CreateGroup(ctx, &models.Group{Name: "analysts"})
+	assert.ErrorIs(t, err, dberror.ErrAlreadyExists)
+	err = DB(ctx).CreateGroup(ctx, &models.Group{Name: "engineers"})
+	require.NoError(t, err)
+
+	group, err := DB(ctx).GetGroup(ctx, "analysts")
+	require.NoError(t, err)
+	assert.Equal(t, "Data analysts", group.Description)
+	groups, err := DB(ctx).ListGroups(ctx)
+	require.NoError(t, err)
+	assert.Len(t, groups, 2)
+
+	// Membership
+	require.NoError(t, DB(ctx).AddGroupMember(ctx, "analysts", "alice"))
+	require.NoError(t, DB(ctx).AddGroupMember(ctx, "engineers", "alice"))
+	require.NoError(t, DB(ctx).AddGroupMember(ctx, "analysts", "bob"))
+	// Adding a member again is not an error
+	require.NoError(t, DB(ctx).AddGroupMember(ctx, "analysts", "bob"))
+	err = DB(ctx).AddGroupMember(ctx, "analysts", "carol")
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+	err = DB(ctx).AddGroupMember(ctx, "designers", "alice")
+	assert.ErrorIs(t, err, dberror.ErrNotFound)
+
+	members, err := DB(ctx).ListGroupMembers(ctx, "analysts")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, members)
+	userGroups, err := DB(ctx).ListUserGroups(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"analysts", "engineers"}, userGroups)
+
+	require.NoError(t, DB(ctx).RemoveGroupMember(ctx, "engineers", "alice"))
+	userGroups, err = DB(ctx).ListUserGroups(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"analysts"}, userGroups)
+
+	// Deleting a group or user removes its memberships
+	require.NoError(t, DB(ctx).DeleteUser(ctx, "bob"))
+	members, err = DB(ctx).ListGroupMembers(ctx, "analysts")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice"}, members)
+	require.NoError(t, DB(ctx).DeleteGroup(ctx, "analysts"))
+	userGroups, err = DB(ctx).ListUserGroups(ctx, "alice")
+	require.NoError(t, err)
+	assert.Empty(t, userGroups)
+
+	assert.ErrorIs(t, DB(ctx).DeleteUser(ctx, "bob"), dberror.ErrNotFound)
+	assert.ErrorIs(t, DB(ctx).DeleteGroup(ctx, "analysts"), dberror.ErrNotFound)
+}
@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgtype"
 	"github.com/rs/zerolog/log"
@@ -380,3 +381,67 @@ func TestListViewsByCatalog(t *testing.T) {
 	assert.Equal(t, "view2", retrieved[2].Label)
 	assert.Equal(t, "view3", retrieved[3].Label)
 }
+
+func TestDisableExpiredViews(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	assert.NoError(t, DB(ctx).CreateTenant(ctx, tenantID))
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	assert.NoError(t, DB(ctx).CreateProject(ctx, projectID))
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	var info pgtype.JSONB
+	assert.NoError(t, info.Set(`{"meta": "expiry_test"}`))
+
+	catalog := models.Catalog{Name: "catalog_expiry", Info: info}
+	assert.NoError(t, DB(ctx).CreateCatalog(ctx, &catalog))
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	now := time.Now()
+	views := []models.View{
+		{Label: "expired", Info: []byte(`{"expiresAt": "` + now.Add(-time.Hour).Format(time.RFC3339) + `"}`)},
+		{Label: "current", Info: []byte(`{"expiresAt": "` + now.Add(time.Hour).Format(time.RFC3339) + `"}`)},
+		{Label: "forever", Info: []byte(`{"labels": {"team": "a"}}`)},
+	}
+	for i := range views {
+		views[i].Rules = []byte(`{}`)
+		views[i].CatalogID = catalog.CatalogID
+		views[i].CreatedBy = "test_user"
+		require.NoError(t, DB(ctx).CreateView(ctx, &views[i]))
+	}
+
+	ownViews := func(views []*models.View) []string {
+		labels := []string{}
+		for _, v := range views {
+			if v.TenantID == tenantID {
+				labels = append(labels, v.Label)
+			}
+		}
+		return labels
+	}
+
+	disabled, err := DB(ctx).DisableExpiredViews(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"expired"}, ownViews(disabled))
+
+	view, err := DB(ctx).GetViewByLabel(ctx, "expired", catalog.CatalogID)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"expiresAt": "`+now.Add(-time.Hour).Format(time.RFC3339)+`", "disabled": true}`, string(view.Info))
+
+	// Views that are already disabled are not disabled again
+	disabled, err = DB(ctx).DisableExpiredViews(ctx, now)
+	require.NoError(t, err)
+	assert.Empty(t, ownViews(disabled))
+
+	disabled, err = DB(ctx).DisableExpiredViews(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"current"}, ownViews(disabled))
+}
@@ -0,0 +1,85 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgtype"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestViewTemplates(t *testing.T) {
+	ctx := log.Logger.WithContext(context.Background())
+	ctx = newDb(ctx)
+	defer DB(ctx).Close(ctx)
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("P12345")
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	assert.NoError(t, DB(ctx).CreateTenant(ctx, tenantID))
+	defer DB(ctx).DeleteTenant(ctx, tenantID)
+
+	assert.NoError(t, DB(ctx).CreateProject(ctx, projectID))
+	defer DB(ctx).DeleteProject(ctx, projectID)
+
+	var info pgtype.JSONB
+	assert.NoError(t, info.Set(`{"meta": "test"}`))
+	catalog := models.Catalog{
+		Name:        "test_catalog_view_template",
+		Description: "Catalog for view template test",
+		Info:        info,
+	}
+	require.NoError(t, DB(ctx).CreateCatalog(ctx, &catalog))
+	defer DB(ctx).DeleteCatalog(ctx, catalog.CatalogID, "")
+
+	template := &models.ViewTemplate{
+		Label:       "ns-reader",
+		Description: "read access to a namespace",
+		Spec:        []byte(`{"rules": [{"intent": "Allow", "actions": ["system.catalog.list"], "targets": ["res://namespaces/{{namespace}}"]}]}`),
+		CatalogID:   catalog.CatalogID,
+		CreatedBy:   "user/test",
+	}
+	require.NoError(t, DB(ctx).CreateViewTemplate(ctx, template))
+	assert.NotEmpty(t, template.TemplateID)
+
+	err := DB(ctx).CreateViewTemplate(ctx, &models.ViewTemplate{
+		Label:     "ns-reader",
+		Spec:      []byte(`{}`),
+		CatalogID: catalog.CatalogID,
+		CreatedBy: "user/test",
+	})
+	assert.True(t, errors.Is(err, dberror.ErrAlreadyExists))
+
+	got, err := DB(ctx).GetViewTemplateByLabel(ctx, "ns-reader", catalog.CatalogID)
+	require.NoError(t, err)
+	assert.Equal(t, template.TemplateID, got.TemplateID)
+	assert.Equal(t, "read access to a namespace", got.Description)
+	assert.JSONEq(t, string(template.Spec), string(got.Spec))
+
+	template.Description = ""
+	template.Spec = []byte(`{"rules": []}`)
+	template.UpdatedBy = "user/other"
+	require.NoError(t, DB(ctx).UpdateViewTemplate(ctx, template))
+	got, err = DB(ctx).GetViewTemplateByLabel(ctx, "ns-reader", catalog.CatalogID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Description)
+	assert.Equal(t, "user/other", got.UpdatedBy)
+
+	templates, err := DB(ctx).ListViewTemplatesByCatalog(ctx, catalog.CatalogID)
+	require.NoError(t, err)
+	assert.Len(t, templates, 1)
+
+	require.NoError(t, DB(ctx).DeleteViewTemplateByLabel(ctx, "ns-reader", catalog.CatalogID))
+	_, err = DB(ctx).GetViewTemplateByLabel(ctx, "ns-reader", catalog.CatalogID)
+	assert.True(t, errors.Is(err, dberror.ErrNotFound))
+	err = DB(ctx).DeleteViewTemplateByLabel(ctx, "ns-reader", catalog.CatalogID)
+	assert.True(t, errors.Is(err, dberror.ErrNotFound))
+}
@@ -3,6 +3,7 @@ package dbmanager
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/rs/zerolog/log"
 )
@@ -13,6 +14,25 @@ type ScopedDb interface {
 	Conn(ctx context.Context) (ScopedConn, error)
 	// Stats returns the number of connection requests and returns.
 	Stats() (requests, returns uint64)
+	// PoolStats returns the statistics of each underlying connection pool.
+	PoolStats() []PoolStats
+}
+
+// PoolStats are the statistics of a connection pool.
+type PoolStats struct {
+	Name              string        // Name of the pool, "primary" or "replica-<n>"
+	MaxOpenConns      int           // Maximum number of open connections
+	OpenConns         int           // Established connections, in use or idle
+	AcquiredConns     int           // Connections currently in use
+	IdleConns         int           // Idle connections
+	WaitCount         int64         // Total number of waits for a connection
+	WaitDuration      time.Duration // Total time spent waiting for a connection
+	MaxIdleClosed     int64         // Connections closed for being idle
+	MaxLifetimeClosed int64         // Connections closed for reaching their maximum lifetime
+	ConnRequests      uint64        // Scoped connections handed out
+	ConnReturns       uint64        // Scoped connections returned
+	Healthy           bool          // Whether the last health check succeeded
+	LastHealthCheck   time.Time     // Time of the last health check
 }
 
 type ScopedConn interface {
@@ -26,6 +46,8 @@ type ScopedConn interface {
 	DropScope(ctx context.Context, scope string) error
 	// DropAllScopes drops all scopes from the connection.
 	DropAllScopes(ctx context.Context) error
+	// AuthorizedScopes returns the scopes currently set on the connection.
+	AuthorizedScopes() map[string]string
 	// Conn returns the underlying *sql.Conn. Do not close this directly.
 	// Use ScopedConn.Close(ctx) to ensure scopes are dropped safely.
 	Conn() *sql.Conn
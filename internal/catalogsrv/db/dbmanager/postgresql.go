@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"regexp"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -27,10 +28,15 @@ type postgresConn struct {
 
 // postgresPool represents a pool of PostgreSQL database connections.
 type postgresPool struct {
+	name             string
 	configuredScopes []string
 	connRequests     uint64
 	connReturns      uint64
 	db               *sql.DB
+
+	mu              sync.Mutex
+	healthy         bool
+	lastHealthCheck time.Time
 }
 
 // validScopeNameRegex ensures scope names are valid PostgreSQL identifiers
@@ -44,14 +50,32 @@ func formatSQLIdentifier(name string) string {
 // NewPostgresqlDb creates a new PostgreSQL database connection pool with the given configured scopes.
 // It returns a pointer to the PostgresPool and an error, if any.
 func NewPostgresqlDb(configuredScopes []string) (ScopedDb, error) {
+	p, err := newPostgresPool("primary", config.HatchCatalogDsn(), configuredScopes)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.db.Ping()
+	if err != nil {
+		log.Error().Err(err).Msg("failed to ping db")
+		p.db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	p.setHealthy(true)
+
+	return p, nil
+}
+
+// newPostgresPool opens a connection pool for the given DSN without connecting to the database.
+// The pool is tuned with the configured pool settings and pings the database every health
+// check period.
+func newPostgresPool(name, dsn string, configuredScopes []string) (*postgresPool, error) {
 	for _, scope := range configuredScopes {
 		if !validScopeNameRegex.MatchString(scope) {
 			return nil, fmt.Errorf("invalid scope name: %s", scope)
 		}
 	}
 
-	dsn := config.HatchCatalogDsn()
-
 	sqlDB, err := sql.Open("pgx", dsn)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to open db")
@@ -59,21 +83,70 @@ func NewPostgresqlDb(configuredScopes []string) (ScopedDb, error) {
 	}
 
 	// Configure connection pool settings
-	sqlDB.SetMaxOpenConns(50)
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetConnMaxLifetime(30 * time.Minute)
-	sqlDB.SetConnMaxIdleTime(5 * time.Minute)
+	settings := config.PoolSettings()
+	maxIdleTime, _ := settings.GetMaxIdleTime()
+	maxLifetime, _ := settings.GetMaxLifetime()
+	healthCheckPeriod, _ := settings.GetHealthCheckPeriod()
+	sqlDB.SetMaxOpenConns(settings.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(settings.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(maxLifetime)
+	sqlDB.SetConnMaxIdleTime(maxIdleTime)
+
+	p := &postgresPool{
+		name:             name,
+		configuredScopes: configuredScopes,
+		db:               sqlDB,
+	}
+	if healthCheckPeriod > 0 {
+		go p.runHealthChecks(healthCheckPeriod)
+	}
+	return p, nil
+}
 
-	err = sqlDB.Ping()
-	if err != nil {
-		log.Error().Err(err).Msg("failed to ping db")
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+// runHealthChecks pings the database every period and records whether it responded. Pools
+// live as long as the process, so the checks are never stopped.
+func (p *postgresPool) runHealthChecks(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), period)
+		err := p.db.PingContext(ctx)
+		cancel()
+		if err != nil {
+			log.Warn().Err(err).Str("pool", p.name).Msg("database health check failed")
+		}
+		p.setHealthy(err == nil)
 	}
+}
 
-	return &postgresPool{
-		configuredScopes: configuredScopes,
-		db:               sqlDB,
-	}, nil
+func (p *postgresPool) setHealthy(healthy bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.healthy = healthy
+	p.lastHealthCheck = time.Now()
+}
+
+// PoolStats returns the statistics of the pool.
+func (p *postgresPool) PoolStats() []PoolStats {
+	s := p.db.Stats()
+	requests, returns := p.Stats()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return []PoolStats{{
+		Name:              p.name,
+		MaxOpenConns:      s.MaxOpenConnections,
+		OpenConns:         s.OpenConnections,
+		AcquiredConns:     s.InUse,
+		IdleConns:         s.Idle,
+		WaitCount:         s.WaitCount,
+		WaitDuration:      s.WaitDuration,
+		MaxIdleClosed:     s.MaxIdleClosed + s.MaxIdleTimeClosed,
+		MaxLifetimeClosed: s.MaxLifetimeClosed,
+		ConnRequests:      requests,
+		ConnReturns:       returns,
+		Healthy:           p.healthy,
+		LastHealthCheck:   p.lastHealthCheck,
+	}}
 }
 
 // Conn returns a new connection to the PostgreSQL database from the connection pool.
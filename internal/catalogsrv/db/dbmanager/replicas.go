@@ -0,0 +1,131 @@
+package dbmanager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// lagCheckInterval is how long the measured lag of a replica is trusted before it is measured again
+const lagCheckInterval = time.Second
+
+// replicaLagQuery returns how far a replica is behind the primary in seconds. A replica that has
+// replayed everything it received is not lagging, however long ago the last transaction was.
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+
+// ErrNoReplicaAvailable is returned when no replica is reachable and within the staleness tolerance.
+var ErrNoReplicaAvailable = errors.New("no read replica available")
+
+// ReplicaSet is a ScopedDb that hands out connections to read replicas in round-robin order.
+// Replicas that cannot be reached, or that lag the primary by more than the staleness tolerance,
+// are skipped until they recover.
+type ReplicaSet struct {
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+}
+
+type replica struct {
+	pool *postgresPool
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	available bool
+}
+
+// NewReplicaSet creates connection pools for the replicas with the given DSNs. Replicas are not
+// contacted until a connection is requested, so that an unavailable replica does not prevent
+// startup.
+func NewReplicaSet(dsns []string, configuredScopes []string, maxLag time.Duration) (*ReplicaSet, error) {
+	if len(dsns) == 0 {
+		return nil, fmt.Errorf("no replica DSNs configured")
+	}
+	rs := &ReplicaSet{maxLag: maxLag}
+	for i, dsn := range dsns {
+		p, err := newPostgresPool(fmt.Sprintf("replica-%d", i), dsn, configuredScopes)
+		if err != nil {
+			return nil, err
+		}
+		rs.replicas = append(rs.replicas, &replica{pool: p})
+	}
+	return rs, nil
+}
+
+// Conn returns a connection to the next available replica. It returns ErrNoReplicaAvailable if
+// no replica is reachable and within the staleness tolerance.
+func (rs *ReplicaSet) Conn(ctx context.Context) (ScopedConn, error) {
+	start := rs.next.Add(1)
+	for i := range rs.replicas {
+		r := rs.replicas[(int(start)+i)%len(rs.replicas)]
+		if !r.isAvailable(ctx, rs.maxLag) {
+			continue
+		}
+		conn, err := r.pool.Conn(ctx)
+		if err != nil {
+			r.markUnavailable()
+			continue
+		}
+		return conn, nil
+	}
+	return nil, ErrNoReplicaAvailable
+}
+
+// Stats returns the number of connection requests and returns across all replicas.
+func (rs *ReplicaSet) Stats() (requests, returns uint64) {
+	for _, r := range rs.replicas {
+		req, ret := r.pool.Stats()
+		requests += req
+		returns += ret
+	}
+	return requests, returns
+}
+
+// PoolStats returns the statistics of each replica pool.
+func (rs *ReplicaSet) PoolStats() []PoolStats {
+	var stats []PoolStats
+	for _, r := range rs.replicas {
+		stats = append(stats, r.pool.PoolStats()...)
+	}
+	return stats
+}
+
+// isAvailable reports whether the replica is reachable and within maxLag, measuring the lag if
+// the last measurement is older than lagCheckInterval.
+func (r *replica) isAvailable(ctx context.Context, maxLag time.Duration) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.checkedAt) < lagCheckInterval {
+		return r.available
+	}
+	r.checkedAt = time.Now()
+
+	var lagSeconds float64
+	if err := r.pool.db.QueryRowContext(ctx, replicaLagQuery).Scan(&lagSeconds); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("failed to check replica lag")
+		r.available = false
+		return false
+	}
+	lag := time.Duration(lagSeconds * float64(time.Second))
+	r.available = lag <= maxLag
+	if !r.available {
+		log.Ctx(ctx).Warn().Dur("lag", lag).Dur("max_lag", maxLag).Msg("replica lag exceeds staleness tolerance")
+	}
+	return r.available
+}
+
+func (r *replica) markUnavailable() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.available = false
+	r.checkedAt = time.Now()
+}
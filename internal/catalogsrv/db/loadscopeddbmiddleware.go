@@ -9,7 +9,7 @@ import (
 )
 
 // LoadScopedDBMiddleware is a middleware that loads a scoped db connection from the request context
-// and closes it after the request is served.
+// and closes it after the request is served. GET and HEAD requests may read from replicas.
 func LoadScopedDBMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx, err := ConnCtx(r.Context())
@@ -18,11 +18,10 @@ func LoadScopedDBMiddleware(next http.Handler) http.Handler {
 			httpx.ErrApplicationError("unable to service request at this time").Send(w)
 			return
 		}
-		defer func() {
-			if dbConn := DB(ctx); dbConn != nil {
-				dbConn.Close(context.Background()) // use background to avoid canceled context
-			}
-		}()
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			ctx = WithReplicaReads(ctx)
+		}
+		defer closeConn(context.Background(), ctx) // use background to avoid canceled context
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
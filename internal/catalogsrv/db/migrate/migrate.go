@@ -0,0 +1,363 @@
+// Package migrate applies the versioned SQL migrations of the catalog database.
+//
+// Migrations are embedded in the binary from the sql directory. Each migration is a pair of
+// files named <version>_<name>.up.sql and <version>_<name>.down.sql, where version is a
+// positive integer. Applied versions are recorded in the schema_migrations table, and each
+// migration runs in its own transaction together with its bookkeeping, so a failed migration
+// leaves no trace. A PostgreSQL advisory lock serializes migrators, so that several servers
+// starting at once apply each migration exactly once.
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed sql/*.sql
+var migrationFiles embed.FS
+
+// lockID is the key of the advisory lock held while migrating
+const lockID = 0x7461_6e73_6976_65 // "tansive"
+
+// baselineTable is a table created by the initial migration. A database that has it but no
+// schema_migrations table was set up before migrations existed and is at version 1.
+const baselineTable = "tenants"
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is the state of a migration in the database.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+	// Unknown is set for versions applied to the database that this binary has no migration
+	// for, which happens when the database was migrated by a newer release.
+	Unknown bool `json:"unknown,omitempty"`
+}
+
+// Migrator applies migrations to a database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+var migrationFileRegex = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// New returns a Migrator for the embedded migrations.
+func New(db *sql.DB) (*Migrator, error) {
+	migrations, err := loadMigrations(migrationFiles, "sql")
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Open connects to the database with the given DSN and returns a Migrator for it. The
+// connection is closed by Close.
+func Open(dsn string) (*Migrator, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connecting to database: %w", err)
+	}
+	m, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+// Close closes the database connection of the Migrator.
+func (m *Migrator) Close() error {
+	return m.db.Close()
+}
+
+// Migrations returns the known migrations in version order.
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Up applies all pending migrations in version order and returns the ones applied.
+// It fails without applying anything if the database has a version this binary does not know.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for v := range versions {
+			if m.find(v) == nil {
+				return fmt.Errorf("database schema version %d is unknown to this release", v)
+			}
+		}
+		for _, mg := range m.migrations {
+			if _, ok := versions[mg.Version]; ok {
+				continue
+			}
+			log.Ctx(ctx).Info().Int64("version", mg.Version).Str("name", mg.Name).Msg("applying migration")
+			if err := m.apply(ctx, conn, mg.Up, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, mg.Version, mg.Name)
+				return err
+			}); err != nil {
+				return fmt.Errorf("applying migration %d_%s: %w", mg.Version, mg.Name, err)
+			}
+			applied = append(applied, mg)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the given number of most recently applied migrations, newest first, and
+// returns the ones reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, fmt.Errorf("steps must be positive")
+	}
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		applied := make([]int64, 0, len(versions))
+		for v := range versions {
+			applied = append(applied, v)
+		}
+		slices.Sort(applied)
+		slices.Reverse(applied)
+
+		for _, v := range applied[:min(steps, len(applied))] {
+			mg := m.find(v)
+			if mg == nil {
+				return fmt.Errorf("database schema version %d is unknown to this release", v)
+			}
+			log.Ctx(ctx).Info().Int64("version", mg.Version).Str("name", mg.Name).Msg("reverting migration")
+			if err := m.apply(ctx, conn, mg.Down, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mg.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("reverting migration %d_%s: %w", mg.Version, mg.Name, err)
+			}
+			reverted = append(reverted, *mg)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status returns the state of every known migration, and of every applied version that is
+// not known, in version order.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var status []Status
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		versions, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, mg := range m.migrations {
+			s := Status{Version: mg.Version, Name: mg.Name}
+			if a, ok := versions[mg.Version]; ok {
+				s.Applied = true
+				s.AppliedAt = &a.appliedAt
+				delete(versions, mg.Version)
+			}
+			status = append(status, s)
+		}
+		for v, a := range versions {
+			status = append(status, Status{Version: v, Name: a.name, Applied: true, AppliedAt: &a.appliedAt, Unknown: true})
+		}
+		slices.SortFunc(status, func(a, b Status) int {
+			return cmp.Compare(a.Version, b.Version)
+		})
+		return nil
+	})
+	return status, err
+}
+
+func (m *Migrator) find(version int64) *Migration {
+	for i := range m.migrations {
+		if m.migrations[i].Version == version {
+			return &m.migrations[i]
+		}
+	}
+	return nil
+}
+
+// apply runs a migration script and its bookkeeping in a single transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, script string, record func(*sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// withLock runs fn on a dedicated connection while holding the migration lock. The
+// schema_migrations table is created, and a pre-migration database baselined, first.
+func (m *Migrator) withLock(ctx context.Context, fn func(*sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("obtaining database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	defer func() {
+		// The lock is released with the session if this fails
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, lockID); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to release migration lock")
+		}
+	}()
+
+	if err := ensureMigrationsTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func ensureMigrationsTable(ctx context.Context, conn *sql.Conn) error {
+	var exists, baseline bool
+	err := conn.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL, to_regclass($1) IS NOT NULL`, baselineTable).
+		Scan(&exists, &baseline)
+	if err != nil {
+		return fmt.Errorf("checking schema_migrations: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version BIGINT PRIMARY KEY,
+			name VARCHAR(256) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`)
+	if err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+	if baseline {
+		log.Ctx(ctx).Info().Msg("existing schema found, recording it as version 1")
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (1, 'initial_schema') ON CONFLICT DO NOTHING`); err != nil {
+			return fmt.Errorf("recording baseline version: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+type appliedVersion struct {
+	name      string
+	appliedAt time.Time
+}
+
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int64]appliedVersion, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]appliedVersion)
+	for rows.Next() {
+		var v int64
+		var a appliedVersion
+		if err := rows.Scan(&v, &a.name, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("reading schema_migrations: %w", err)
+		}
+		versions[v] = a
+	}
+	return versions, rows.Err()
+}
+
+// loadMigrations reads the migrations in dir of fsys. Every version must have both an up and
+// a down file, and versions must be unique.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+		script, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("reading migration %s: %w", entry.Name(), err)
+		}
+
+		mg, ok := byVersion[version]
+		if !ok {
+			mg = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = mg
+		} else if mg.Name != match[2] {
+			return nil, fmt.Errorf("conflicting names for migration version %d: %s and %s", version, mg.Name, match[2])
+		}
+		if match[3] == "up" {
+			mg.Up = string(script)
+		} else {
+			mg.Down = string(script)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, mg := range byVersion {
+		if mg.Up == "" || mg.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both up and down files", mg.Version, mg.Name)
+		}
+		migrations = append(migrations, *mg)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
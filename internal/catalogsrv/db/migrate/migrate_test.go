@@ -0,0 +1,94 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	m, err := New(nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, m.Migrations())
+
+	// Versions start at 1 and have no gaps, so that the baseline of a pre-migration
+	// database is the initial schema
+	for i, mg := range m.Migrations() {
+		assert.Equal(t, int64(i+1), mg.Version)
+		assert.NotEmpty(t, mg.Up)
+		assert.NotEmpty(t, mg.Down)
+	}
+	assert.Equal(t, "initial_schema", m.Migrations()[0].Name)
+	assert.Contains(t, m.Migrations()[0].Up, "CREATE TABLE IF NOT EXISTS "+baselineTable)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	file := func(s string) *fstest.MapFile { return &fstest.MapFile{Data: []byte(s)} }
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []int64
+		wantErr string
+	}{
+		{
+			name: "sorted by version",
+			files: fstest.MapFS{
+				"sql/0010_ten.up.sql":   file("up10"),
+				"sql/0010_ten.down.sql": file("down10"),
+				"sql/0002_two.up.sql":   file("up2"),
+				"sql/0002_two.down.sql": file("down2"),
+			},
+			want: []int64{2, 10},
+		},
+		{
+			name: "missing down",
+			files: fstest.MapFS{
+				"sql/0001_one.up.sql": file("up1"),
+			},
+			wantErr: "needs both up and down files",
+		},
+		{
+			name: "invalid name",
+			files: fstest.MapFS{
+				"sql/one.up.sql": file("up1"),
+			},
+			wantErr: "invalid migration file name",
+		},
+		{
+			name: "zero version",
+			files: fstest.MapFS{
+				"sql/0000_zero.up.sql":   file("up0"),
+				"sql/0000_zero.down.sql": file("down0"),
+			},
+			wantErr: "invalid migration version",
+		},
+		{
+			name: "conflicting names",
+			files: fstest.MapFS{
+				"sql/0001_one.up.sql":   file("up1"),
+				"sql/0001_uno.down.sql": file("down1"),
+			},
+			wantErr: "conflicting names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := loadMigrations(tt.files, "sql")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			var versions []int64
+			for _, mg := range migrations {
+				versions = append(versions, mg.Version)
+			}
+			assert.Equal(t, tt.want, versions)
+		})
+	}
+}
@@ -33,6 +33,65 @@ BEFORE UPDATE ON projects
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+CREATE TABLE IF NOT EXISTS tenant_roles (
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  user_id VARCHAR(128) NOT NULL,
+  role VARCHAR(16) NOT NULL,
+  created_by VARCHAR(128),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, user_id),
+  CHECK (role IN ('owner', 'admin', 'viewer'))
+);
+
+CREATE TRIGGER update_tenant_roles_updated_at
+BEFORE UPDATE ON tenant_roles
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS users (
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  user_id VARCHAR(128) NOT NULL,
+  display_name VARCHAR(256),
+  email VARCHAR(320),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, user_id)
+);
+
+CREATE TRIGGER update_users_updated_at
+BEFORE UPDATE ON users
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS groups (
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  name VARCHAR(128) NOT NULL,
+  description VARCHAR(1024),
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, name),
+  CHECK (name ~ '^[A-Za-z0-9_-]+$') -- CHECK constraint to allow only alphanumeric and underscore in name
+);
+
+CREATE TRIGGER update_groups_updated_at
+BEFORE UPDATE ON groups
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+CREATE TABLE IF NOT EXISTS group_members (
+  tenant_id VARCHAR(10) NOT NULL,
+  group_name VARCHAR(128) NOT NULL,
+  user_id VARCHAR(128) NOT NULL,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, group_name, user_id),
+  FOREIGN KEY (tenant_id, group_name) REFERENCES groups(tenant_id, name) ON DELETE CASCADE,
+  FOREIGN KEY (tenant_id, user_id) REFERENCES users(tenant_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_members_user
+ON group_members (tenant_id, user_id);
+
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 CREATE TABLE IF NOT EXISTS catalogs (
@@ -174,6 +233,31 @@ BEFORE UPDATE ON views
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+-- view_templates hold views whose targets contain placeholders such as {{namespace}}, which
+-- are rendered into concrete views on instantiation
+CREATE TABLE IF NOT EXISTS view_templates (
+  template_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+  label VARCHAR(128) NOT NULL,
+  description VARCHAR(1024),
+  info JSONB,
+  spec JSONB NOT NULL,
+  catalog_id UUID NOT NULL,
+  created_by VARCHAR(128) NOT NULL,
+  updated_by VARCHAR(128) NOT NULL,
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (tenant_id, catalog_id, label),
+  PRIMARY KEY (tenant_id, template_id),
+  FOREIGN KEY (tenant_id, catalog_id) REFERENCES catalogs(tenant_id, catalog_id) ON DELETE CASCADE,
+  CHECK (label ~ '^[A-Za-z0-9_-]+$')
+);
+
+CREATE TRIGGER update_view_templates_updated_at
+BEFORE UPDATE ON view_templates
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 CREATE TABLE IF NOT EXISTS view_tokens (
   token_id UUID NOT NULL DEFAULT uuid_generate_v4(),
   view_id UUID NOT NULL,
@@ -189,6 +273,49 @@ BEFORE UPDATE ON view_tokens
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+  jti UUID NOT NULL,
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  expire_at TIMESTAMPTZ NOT NULL,
+  revoked_by VARCHAR(128),
+  revoked_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, jti)
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expire_at
+ON revoked_tokens (expire_at);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+  key_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+  name VARCHAR(128) NOT NULL,
+  description VARCHAR(1024),
+  key_prefix VARCHAR(16) NOT NULL,
+  key_hash BYTEA NOT NULL,
+  view_id UUID NOT NULL,
+  catalog_id UUID NOT NULL,
+  project_id VARCHAR(10) NOT NULL,
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  created_by VARCHAR(128) NOT NULL,
+  expire_at TIMESTAMPTZ NOT NULL,
+  last_used_at TIMESTAMPTZ,
+  revoked_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, key_id),
+  UNIQUE (tenant_id, catalog_id, name),
+  FOREIGN KEY (tenant_id, view_id) REFERENCES views(tenant_id, view_id) ON DELETE CASCADE,
+  FOREIGN KEY (tenant_id, catalog_id) REFERENCES catalogs(tenant_id, catalog_id) ON DELETE CASCADE,
+  CHECK (name ~ '^[A-Za-z0-9_-]+$') -- CHECK constraint to allow only alphanumeric and underscore in name
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_key_hash
+ON api_keys (key_hash);
+
+CREATE TRIGGER update_api_keys_updated_at
+BEFORE UPDATE ON api_keys
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
 CREATE TABLE IF NOT EXISTS signing_keys (
   key_id UUID NOT NULL DEFAULT uuid_generate_v4(),
   public_key BYTEA NOT NULL,
@@ -254,6 +381,30 @@ BEFORE UPDATE ON tangents
 FOR EACH ROW
 EXECUTE FUNCTION set_updated_at();
 
+-- authz_decisions is an audit log of policy decisions. It is not tied to catalogs or views
+-- so that the record survives their deletion.
+CREATE TABLE IF NOT EXISTS authz_decisions (
+  decision_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  catalog_id UUID,
+  view_id UUID,
+  principal VARCHAR(256) NOT NULL,
+  actions JSONB NOT NULL,
+  target VARCHAR(1024) NOT NULL,
+  allowed BOOLEAN NOT NULL,
+  -- false for denials that were not enforced because the view was in audit mode
+  enforced BOOLEAN NOT NULL DEFAULT TRUE,
+  matched_rules JSONB,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, decision_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_authz_decisions_tenant_created_at
+ON authz_decisions (tenant_id, created_at DESC, decision_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_authz_decisions_tenant_principal
+ON authz_decisions (tenant_id, principal, created_at DESC);
+
 GRANT ALL PRIVILEGES ON TABLE
 	tenants,
 	projects,
@@ -264,10 +415,18 @@ GRANT ALL PRIVILEGES ON TABLE
   skillset_directory,
   namespaces,
   views,
+  view_templates,
   view_tokens,
+  revoked_tokens,
+  api_keys,
   signing_keys,
   sessions,
-  tangents
+  tangents,
+  authz_decisions,
+  tenant_roles,
+  users,
+  groups,
+  group_members
 TO catalogrw;
 
 GRANT USAGE, SELECT ON SEQUENCE catalog_objects_id_seq TO catalogrw;
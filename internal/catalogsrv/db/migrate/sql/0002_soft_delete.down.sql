@@ -0,0 +1,9 @@
+-- Soft deleted rows would become live again, so they are removed first
+DELETE FROM variants WHERE deleted_at IS NOT NULL;
+DELETE FROM catalogs WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_variants_deleted_at;
+DROP INDEX IF EXISTS idx_catalogs_deleted_at;
+
+ALTER TABLE variants DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE catalogs DROP COLUMN IF EXISTS deleted_at;
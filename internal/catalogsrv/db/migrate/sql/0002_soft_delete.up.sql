@@ -0,0 +1,7 @@
+-- Catalogs and variants are soft deleted: deleted_at is set on delete and the row is
+-- purged once the retention window has passed.
+ALTER TABLE catalogs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE variants ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_catalogs_deleted_at ON catalogs (deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_variants_deleted_at ON variants (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_namespaces_search;
+DROP INDEX IF EXISTS idx_variants_search;
+DROP INDEX IF EXISTS idx_catalogs_search;
+
+DROP INDEX IF EXISTS idx_catalog_objects_search;
+ALTER TABLE catalog_objects DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS search_words(TEXT);
@@ -0,0 +1,17 @@
+-- search_words returns the text followed by its camelCase words split apart, so that
+-- maxRetries is found by both "maxRetries" and "retries".
+CREATE OR REPLACE FUNCTION search_words(t TEXT) RETURNS TEXT AS $$
+  SELECT COALESCE(t, '') || ' ' || regexp_replace(COALESCE(t, ''), '([a-z0-9])([A-Z])', '\1 \2', 'g')
+$$ LANGUAGE sql IMMUTABLE;
+
+-- Catalog objects are stored compressed, so their search text is extracted by the server when
+-- they are written. Objects written before this column existed are indexed in the background.
+ALTER TABLE catalog_objects ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+CREATE INDEX IF NOT EXISTS idx_catalog_objects_search ON catalog_objects USING GIN (search_vector);
+
+CREATE INDEX IF NOT EXISTS idx_catalogs_search ON catalogs
+  USING GIN (to_tsvector('simple', search_words(name || ' ' || COALESCE(description, ''))));
+CREATE INDEX IF NOT EXISTS idx_variants_search ON variants
+  USING GIN (to_tsvector('simple', search_words(name || ' ' || COALESCE(description, ''))));
+CREATE INDEX IF NOT EXISTS idx_namespaces_search ON namespaces
+  USING GIN (to_tsvector('simple', search_words(name || ' ' || COALESCE(description, ''))));
@@ -0,0 +1,17 @@
+DO $$
+DECLARE
+  t TEXT;
+BEGIN
+  FOREACH t IN ARRAY ARRAY[
+    'tenants', 'projects', 'catalogs', 'variants', 'namespaces', 'catalog_objects',
+    'resource_directory', 'skillset_directory', 'views', 'view_tokens', 'view_templates',
+    'tangents', 'sessions', 'api_keys', 'revoked_tokens', 'users', 'groups', 'group_members',
+    'tenant_roles', 'authz_decisions'
+  ]
+  LOOP
+    EXECUTE format('ALTER TABLE %I NO FORCE ROW LEVEL SECURITY', t);
+    EXECUTE format('ALTER TABLE %I DISABLE ROW LEVEL SECURITY', t);
+    EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+  END LOOP;
+END
+$$;
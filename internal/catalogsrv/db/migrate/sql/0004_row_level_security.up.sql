@@ -0,0 +1,24 @@
+-- Row-level security limits each connection to the rows of the tenant in the
+-- tansive.curr_tenantid setting. The server sets it only if db.row_level_security is
+-- enabled; connections without a tenant, such as those of background jobs, see all rows.
+-- FORCE applies the policies to the table owner as well.
+DO $$
+DECLARE
+  t TEXT;
+BEGIN
+  FOREACH t IN ARRAY ARRAY[
+    'tenants', 'projects', 'catalogs', 'variants', 'namespaces', 'catalog_objects',
+    'resource_directory', 'skillset_directory', 'views', 'view_tokens', 'view_templates',
+    'tangents', 'sessions', 'api_keys', 'revoked_tokens', 'users', 'groups', 'group_members',
+    'tenant_roles', 'authz_decisions'
+  ]
+  LOOP
+    EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+    EXECUTE format(
+      'CREATE POLICY tenant_isolation ON %I '
+      'USING (COALESCE(current_setting(''tansive.curr_tenantid'', true), '''') IN ('''', tenant_id))', t);
+    EXECUTE format('ALTER TABLE %I ENABLE ROW LEVEL SECURITY', t);
+    EXECUTE format('ALTER TABLE %I FORCE ROW LEVEL SECURITY', t);
+  END LOOP;
+END
+$$;
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS update_admission_webhooks_updated_at ON admission_webhooks;
+DROP TABLE IF EXISTS admission_webhooks CASCADE;
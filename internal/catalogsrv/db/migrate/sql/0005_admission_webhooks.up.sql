@@ -0,0 +1,32 @@
+-- admission_webhooks hold the external services that validate or mutate writes to the
+-- resources of a catalog before they are stored
+CREATE TABLE IF NOT EXISTS admission_webhooks (
+  webhook_id UUID NOT NULL DEFAULT uuid_generate_v4(),
+  label VARCHAR(128) NOT NULL,
+  description VARCHAR(1024),
+  info JSONB,
+  spec JSONB NOT NULL,
+  catalog_id UUID NOT NULL,
+  created_by VARCHAR(128) NOT NULL,
+  updated_by VARCHAR(128) NOT NULL,
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ DEFAULT NOW(),
+  updated_at TIMESTAMPTZ DEFAULT NOW(),
+  UNIQUE (tenant_id, catalog_id, label),
+  PRIMARY KEY (tenant_id, webhook_id),
+  FOREIGN KEY (tenant_id, catalog_id) REFERENCES catalogs(tenant_id, catalog_id) ON DELETE CASCADE,
+  CHECK (label ~ '^[A-Za-z0-9_-]+$')
+);
+
+CREATE TRIGGER update_admission_webhooks_updated_at
+BEFORE UPDATE ON admission_webhooks
+FOR EACH ROW
+EXECUTE FUNCTION set_updated_at();
+
+GRANT ALL PRIVILEGES ON TABLE admission_webhooks TO catalogrw;
+
+DROP POLICY IF EXISTS tenant_isolation ON admission_webhooks;
+CREATE POLICY tenant_isolation ON admission_webhooks
+  USING (COALESCE(current_setting('tansive.curr_tenantid', true), '') IN ('', tenant_id));
+ALTER TABLE admission_webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE admission_webhooks FORCE ROW LEVEL SECURITY;
@@ -0,0 +1,2 @@
+ALTER TABLE tangents DROP COLUMN IF EXISTS health;
+ALTER TABLE tangents DROP COLUMN IF EXISTS last_heartbeat_at;
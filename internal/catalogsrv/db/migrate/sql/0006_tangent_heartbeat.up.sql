@@ -0,0 +1,4 @@
+-- Tangents send heartbeats so that the server can tell which runtimes are connected.
+-- health holds the last heartbeat as reported by the tangent.
+ALTER TABLE tangents ADD COLUMN IF NOT EXISTS last_heartbeat_at TIMESTAMPTZ;
+ALTER TABLE tangents ADD COLUMN IF NOT EXISTS health JSONB;
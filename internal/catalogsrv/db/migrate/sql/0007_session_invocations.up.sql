@@ -0,0 +1,26 @@
+-- session_invocations is the audit trail of the skill invocations of a session, shipped by
+-- the tangent that ran it. Records form a hash chain signed by the tangent; record holds the
+-- record as signed so that the chain can be verified later. It is not tied to sessions so
+-- that the trail survives their deletion.
+CREATE TABLE IF NOT EXISTS session_invocations (
+  session_id UUID NOT NULL,
+  seq BIGINT NOT NULL,
+  invocation_id VARCHAR(128) NOT NULL,
+  skill VARCHAR(128) NOT NULL,
+  decision VARCHAR(32) NOT NULL,
+  outcome VARCHAR(32) NOT NULL,
+  hash VARCHAR(64) NOT NULL,
+  verification_key BYTEA NOT NULL,
+  record JSONB NOT NULL,
+  tenant_id VARCHAR(10) NOT NULL REFERENCES tenants(tenant_id) ON DELETE CASCADE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (tenant_id, session_id, seq)
+);
+
+GRANT ALL PRIVILEGES ON TABLE session_invocations TO catalogrw;
+
+DROP POLICY IF EXISTS tenant_isolation ON session_invocations;
+CREATE POLICY tenant_isolation ON session_invocations
+  USING (COALESCE(current_setting('tansive.curr_tenantid', true), '') IN ('', tenant_id));
+ALTER TABLE session_invocations ENABLE ROW LEVEL SECURITY;
+ALTER TABLE session_invocations FORCE ROW LEVEL SECURITY;
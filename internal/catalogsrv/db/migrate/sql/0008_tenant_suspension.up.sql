@@ -0,0 +1,3 @@
+-- Suspended tenants keep their data but are refused by authentication until they are
+-- resumed. A tenant must be suspended before it can be deleted.
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ;
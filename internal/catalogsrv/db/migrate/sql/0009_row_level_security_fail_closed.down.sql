@@ -0,0 +1,18 @@
+DO $$
+DECLARE
+  t TEXT;
+BEGIN
+  FOREACH t IN ARRAY ARRAY[
+    'tenants', 'projects', 'catalogs', 'variants', 'namespaces', 'catalog_objects',
+    'resource_directory', 'skillset_directory', 'views', 'view_tokens', 'view_templates',
+    'tangents', 'sessions', 'api_keys', 'revoked_tokens', 'users', 'groups', 'group_members',
+    'tenant_roles', 'authz_decisions', 'admission_webhooks', 'session_invocations'
+  ]
+  LOOP
+    EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+    EXECUTE format(
+      'CREATE POLICY tenant_isolation ON %I '
+      'USING (COALESCE(current_setting(''tansive.curr_tenantid'', true), '''') IN ('''', tenant_id))', t);
+  END LOOP;
+END
+$$;
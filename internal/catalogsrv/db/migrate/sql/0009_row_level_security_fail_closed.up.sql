@@ -0,0 +1,23 @@
+-- Row-level security fails closed: a connection without a tenant in the
+-- tansive.curr_tenantid setting sees no rows. Platform administration and background jobs
+-- that are not scoped to a tenant set tansive.all_tenants to 'on' to see the rows of all
+-- tenants.
+DO $$
+DECLARE
+  t TEXT;
+BEGIN
+  FOREACH t IN ARRAY ARRAY[
+    'tenants', 'projects', 'catalogs', 'variants', 'namespaces', 'catalog_objects',
+    'resource_directory', 'skillset_directory', 'views', 'view_tokens', 'view_templates',
+    'tangents', 'sessions', 'api_keys', 'revoked_tokens', 'users', 'groups', 'group_members',
+    'tenant_roles', 'authz_decisions', 'admission_webhooks', 'session_invocations'
+  ]
+  LOOP
+    EXECUTE format('DROP POLICY IF EXISTS tenant_isolation ON %I', t);
+    EXECUTE format(
+      'CREATE POLICY tenant_isolation ON %I '
+      'USING (tenant_id = current_setting(''tansive.curr_tenantid'', true) '
+      'OR current_setting(''tansive.all_tenants'', true) = ''on'')', t);
+  END LOOP;
+END
+$$;
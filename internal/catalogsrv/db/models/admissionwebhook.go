@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// AdmissionWebhook is an external service that is called to validate or mutate writes to the
+// resources of a catalog. Spec holds its URL, operations, timeout and failure policy as JSON,
+// and Info its labels and annotations.
+type AdmissionWebhook struct {
+	WebhookID   uuid.UUID          `db:"webhook_id"`
+	Label       string             `db:"label"`
+	Description string             `db:"description"`
+	Info        []byte             `db:"info"`
+	Spec        []byte             `db:"spec"`
+	CatalogID   uuid.UUID          `db:"catalog_id"`
+	TenantID    catcommon.TenantId `db:"tenant_id"`
+	CreatedBy   string             `db:"created_by"`
+	UpdatedBy   string             `db:"updated_by"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+/*
+    Column    |           Type           | Collation | Nullable | Default
+--------------+--------------------------+-----------+----------+--------------------
+ key_id       | uuid                     |           | not null | uuid_generate_v4()
+ name         | character varying(128)   |           | not null |
+ description  | character varying(1024)  |           |          |
+ key_prefix   | character varying(16)    |           | not null |
+ key_hash     | bytea                    |           | not null |
+ view_id      | uuid                     |           | not null |
+ catalog_id   | uuid                     |           | not null |
+ project_id   | character varying(10)    |           | not null |
+ tenant_id    | character varying(10)    |           | not null |
+ created_by   | character varying(128)   |           | not null |
+ expire_at    | timestamp with time zone |           | not null |
+ last_used_at | timestamp with time zone |           |          |
+ revoked_at   | timestamp with time zone |           |          |
+ created_at   | timestamp with time zone |           |          | now()
+ updated_at   | timestamp with time zone |           |          | now()
+*/
+
+// APIKey is a long-lived credential for non-interactive clients. Only a hash of the key
+// is stored; the key itself is returned once, when it is created.
+type APIKey struct {
+	KeyID       uuid.UUID           `db:"key_id"`
+	Name        string              `db:"name"`
+	Description string              `db:"description"`
+	KeyPrefix   string              `db:"key_prefix"`
+	KeyHash     []byte              `db:"key_hash"`
+	ViewID      uuid.UUID           `db:"view_id"`
+	CatalogID   uuid.UUID           `db:"catalog_id"`
+	ProjectID   catcommon.ProjectId `db:"project_id"`
+	TenantID    catcommon.TenantId  `db:"tenant_id"`
+	CreatedBy   string              `db:"created_by"`
+	ExpireAt    time.Time           `db:"expire_at"`
+	LastUsedAt  *time.Time          `db:"last_used_at"`
+	RevokedAt   *time.Time          `db:"revoked_at"`
+	CreatedAt   time.Time           `db:"created_at"`
+	UpdatedAt   time.Time           `db:"updated_at"`
+}
+
+func (k *APIKey) Validate() error {
+	if k.Name == "" {
+		return dberror.ErrInvalidInput.Msg("name is required")
+	}
+	if len(k.KeyHash) == 0 {
+		return dberror.ErrInvalidInput.Msg("key_hash is required")
+	}
+	if k.ViewID == uuid.Nil {
+		return dberror.ErrInvalidInput.Msg("view_id is required")
+	}
+	if k.CatalogID == uuid.Nil {
+		return dberror.ErrInvalidInput.Msg("catalog_id is required")
+	}
+	if k.ProjectID == "" {
+		return dberror.ErrInvalidInput.Msg("project_id is required")
+	}
+	if k.CreatedBy == "" {
+		return dberror.ErrInvalidInput.Msg("created_by is required")
+	}
+	return nil
+}
+
+// IsActive reports whether the key can still be used to authenticate.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && now.Before(k.ExpireAt)
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// AuthzDecision records the outcome of a single policy evaluation: which principal asked
+// to perform which actions on which target, whether it was allowed and the rules that
+// decided it. CatalogID and ViewID are uuid.Nil when they could not be resolved. Enforced is
+// false for denials that were let through because the view was in audit mode.
+type AuthzDecision struct {
+	DecisionID   uuid.UUID          `db:"decision_id"`
+	TenantID     catcommon.TenantId `db:"tenant_id"`
+	CatalogID    uuid.UUID          `db:"catalog_id"`
+	ViewID       uuid.UUID          `db:"view_id"`
+	Principal    string             `db:"principal"`
+	Actions      []string           `db:"actions"`
+	Target       string             `db:"target"`
+	Allowed      bool               `db:"allowed"`
+	Enforced     bool               `db:"enforced"`
+	MatchedRules json.RawMessage    `db:"matched_rules"`
+	CreatedAt    time.Time          `db:"created_at"`
+}
+
+func (d *AuthzDecision) Validate() error {
+	if d.Principal == "" {
+		return dberror.ErrInvalidInput.Msg("principal is required")
+	}
+	if len(d.Actions) == 0 {
+		return dberror.ErrInvalidInput.Msg("actions are required")
+	}
+	if d.Target == "" {
+		return dberror.ErrInvalidInput.Msg("target is required")
+	}
+	return nil
+}
+
+// AuthzDecisionFilter selects decisions from the audit log. Zero values match everything.
+// Decisions are returned newest first; After continues a listing from the last decision
+// of the previous page.
+type AuthzDecisionFilter struct {
+	CatalogID uuid.UUID
+	Principal string
+	Action    string
+	Target    string
+	Allowed   *bool
+	Enforced  *bool
+	Since     time.Time
+	Until     time.Time
+	After     *AuthzDecisionCursor
+	Limit     int
+}
+
+// AuthzDecisionCursor is the position of a decision in the audit log
+type AuthzDecisionCursor struct {
+	CreatedAt  time.Time
+	DecisionID uuid.UUID
+}
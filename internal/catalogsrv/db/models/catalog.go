@@ -19,6 +19,7 @@ import (
  tenant_id   | character varying(10)   |           | not null |
  created_at  | timestamptz            |           | not null | now()
  updated_at  | timestamptz            |           | not null | now()
+ deleted_at  | timestamptz            |           |          |
 */
 
 // Catalog model definition
@@ -30,4 +31,5 @@ type Catalog struct {
 	ProjectID   catcommon.ProjectId `db:"project_id"`
 	CreatedAt   time.Time           `db:"created_at"`
 	UpdatedAt   time.Time           `db:"updated_at"`
+	DeletedAt   *time.Time          `db:"deleted_at"`
 }
@@ -13,6 +13,7 @@ import (
 	type      | character varying(64) |           | not null |
 	tenant_id | character varying(10) |           | not null |
 	data      | bytea                 |           | not null |
+	search_vector | tsvector          |           |          |
 	created_at| timestamptz          |           | not null | now()
 	updated_at| timestamptz          |           | not null | now()
 */
@@ -27,4 +28,8 @@ type CatalogObject struct {
 	Data      []byte                      `db:"data"`
 	CreatedAt time.Time                   `db:"created_at"`
 	UpdatedAt time.Time                   `db:"updated_at"`
+
+	// SearchText is the text the object is found by in catalog search. It is stored only as
+	// the search_vector of the object.
+	SearchText string `db:"-"`
 }
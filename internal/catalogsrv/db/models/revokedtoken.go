@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// RevokedToken is an entry in the token denylist. Entries are only needed until the
+// token would have expired on its own.
+type RevokedToken struct {
+	JTI       uuid.UUID          `db:"jti"`
+	TenantID  catcommon.TenantId `db:"tenant_id"`
+	ExpireAt  time.Time          `db:"expire_at"`
+	RevokedBy string             `db:"revoked_by"`
+	RevokedAt time.Time          `db:"revoked_at"`
+}
+
+func (rt *RevokedToken) Validate() error {
+	if rt.JTI == uuid.Nil {
+		return dberror.ErrInvalidInput.Msg("jti is required")
+	}
+	if rt.ExpireAt.IsZero() {
+		return dberror.ErrInvalidInput.Msg("expire_at is required")
+	}
+	return nil
+}
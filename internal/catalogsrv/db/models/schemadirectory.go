@@ -47,9 +47,22 @@ type DirectoryID struct {
 type DirectoryIDs []DirectoryID
 
 type ObjectRef struct {
-	Hash       string          `json:"hash"`
-	References References      `json:"references"` // used for objects that reference other objects, e.g. schemas
-	Metadata   json.RawMessage `json:"metadata"`
+	Hash       string           `json:"hash"`
+	References References       `json:"references"` // used for objects that reference other objects, e.g. schemas
+	Metadata   json.RawMessage  `json:"metadata"`
+	History    []ObjectRevision `json:"history,omitempty"`   // previous hashes of the object, newest first
+	UpdatedBy  string           `json:"updatedBy,omitempty"` // principal that saved the current hash
+	UpdatedAt  *time.Time       `json:"updatedAt,omitempty"`
+}
+
+// ObjectRevision is a previous revision of an object in a directory. UpdatedBy and UpdatedAt
+// record who saved the revision and when; they are missing for revisions saved before they
+// were recorded.
+type ObjectRevision struct {
+	Hash       string     `json:"hash"`
+	UpdatedBy  string     `json:"updatedBy,omitempty"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	ReplacedAt time.Time  `json:"replacedAt"`
 }
 
 // we'll keep Reference as a struct for future extensibility at the cost of increased storage space
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// SearchHit is an object of a catalog that matches a search. Kind is one of the catcommon kinds.
+// Catalogs, variants and namespaces are identified by Name, while resources and skillsets are
+// identified by their storage Path in the variant.
+type SearchHit struct {
+	Kind        string    `db:"kind"`
+	VariantID   uuid.UUID `db:"variant_id"`
+	Variant     string    `db:"variant"`
+	Name        string    `db:"name"`
+	Path        string    `db:"path"`
+	Description string    `db:"description"`
+	Rank        float32   `db:"rank"`
+}
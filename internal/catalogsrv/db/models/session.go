@@ -27,3 +27,20 @@ type Session struct {
 	UpdatedAt     time.Time          `db:"updated_at"`
 	ExpiresAt     time.Time          `db:"expires_at"`
 }
+
+// SessionInvocation is a record of the audit trail of a session's skill invocations.
+// Record holds the record as signed by the tangent; the other fields are copied from it
+// for querying.
+type SessionInvocation struct {
+	SessionID       uuid.UUID          `db:"session_id"`
+	Seq             int64              `db:"seq"`
+	InvocationID    string             `db:"invocation_id"`
+	Skill           string             `db:"skill"`
+	Decision        string             `db:"decision"`
+	Outcome         string             `db:"outcome"`
+	Hash            string             `db:"hash"`
+	VerificationKey []byte             `db:"verification_key"`
+	Record          json.RawMessage    `db:"record"`
+	TenantID        catcommon.TenantId `db:"tenant_id"`
+	CreatedAt       time.Time          `db:"created_at"`
+}
@@ -8,11 +8,13 @@ import (
 )
 
 type Tangent struct {
-	ID        uuid.UUID       `db:"id"`
-	Info      json.RawMessage `db:"info"`
-	PublicKey []byte          `db:"public_key"`
-	Status    string          `db:"status"`
-	TenantID  string          `db:"tenant_id"`
-	CreatedAt time.Time       `db:"created_at"`
-	UpdatedAt time.Time       `db:"updated_at"`
+	ID              uuid.UUID       `db:"id"`
+	Info            json.RawMessage `db:"info"`
+	PublicKey       []byte          `db:"public_key"`
+	Status          string          `db:"status"`
+	Health          json.RawMessage `db:"health"`
+	LastHeartbeatAt *time.Time      `db:"last_heartbeat_at"`
+	TenantID        string          `db:"tenant_id"`
+	CreatedAt       time.Time       `db:"created_at"`
+	UpdatedAt       time.Time       `db:"updated_at"`
 }
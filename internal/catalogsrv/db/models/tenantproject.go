@@ -7,9 +7,10 @@ import (
 )
 
 type Tenant struct {
-	TenantID  catcommon.TenantId
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	TenantID    catcommon.TenantId
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	SuspendedAt *time.Time
 }
 
 type Project struct {
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+)
+
+/*
+   Column   |           Type           | Collation | Nullable | Default
+------------+--------------------------+-----------+----------+---------
+ tenant_id  | character varying(10)    |           | not null |
+ user_id    | character varying(128)   |           | not null |
+ role       | character varying(16)    |           | not null |
+ created_by | character varying(128)   |           |          |
+ created_at | timestamp with time zone |           |          | now()
+ updated_at | timestamp with time zone |           |          | now()
+*/
+
+// TenantRole grants a user an administrative role in a tenant. Tenant roles govern the
+// tenant and its projects; access to catalog resources is governed by views.
+type TenantRole struct {
+	TenantID  catcommon.TenantId `db:"tenant_id"`
+	UserID    string             `db:"user_id"`
+	Role      string             `db:"role"`
+	CreatedBy string             `db:"created_by"`
+	CreatedAt time.Time          `db:"created_at"`
+	UpdatedAt time.Time          `db:"updated_at"`
+}
+
+func (tr *TenantRole) Validate() error {
+	if tr.UserID == "" {
+		return dberror.ErrInvalidInput.Msg("user_id is required")
+	}
+	if tr.Role == "" {
+		return dberror.ErrInvalidInput.Msg("role is required")
+	}
+	return nil
+}
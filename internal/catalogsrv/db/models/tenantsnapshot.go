@@ -0,0 +1,18 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+)
+
+// TenantSnapshot is a logical backup of the rows of a tenant. Tables maps the name of each table
+// to a JSON array of the tenant's rows in it, each row an object keyed by column name.
+// SchemaVersion is the schema migration version of the database the snapshot was taken from.
+type TenantSnapshot struct {
+	SchemaVersion int64                      `json:"schema_version"`
+	TenantID      catcommon.TenantId         `json:"tenant_id"`
+	TakenAt       time.Time                  `json:"taken_at"`
+	Tables        map[string]json.RawMessage `json:"tables"`
+}
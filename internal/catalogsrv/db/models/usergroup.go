@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+)
+
+// User is a user provisioned in a tenant. The user ID is the ID the user authenticates
+// with, such as the subject of an identity token.
+type User struct {
+	TenantID    catcommon.TenantId `db:"tenant_id"`
+	UserID      string             `db:"user_id"`
+	DisplayName string             `db:"display_name"`
+	Email       string             `db:"email"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+}
+
+func (u *User) Validate() error {
+	if u.UserID == "" {
+		return dberror.ErrInvalidInput.Msg("user_id is required")
+	}
+	return nil
+}
+
+// Group is a named set of users in a tenant. Views are assigned to groups to let their
+// members adopt the view.
+type Group struct {
+	TenantID    catcommon.TenantId `db:"tenant_id"`
+	Name        string             `db:"name"`
+	Description string             `db:"description"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+}
+
+func (g *Group) Validate() error {
+	if g.Name == "" {
+		return dberror.ErrInvalidInput.Msg("name is required")
+	}
+	return nil
+}
@@ -21,6 +21,7 @@ import (
  tenant_id                | character varying(10)    |           | not null |
  created_at               | timestamp with time zone |           |          | now()
  updated_at               | timestamp with time zone |           |          | now()
+ deleted_at               | timestamp with time zone |           |          |
 Indexes:
     "variants_pkey" PRIMARY KEY, btree (variant_id, tenant_id)
     "variants_name_catalog_id_tenant_id_key" UNIQUE CONSTRAINT, btree (name, catalog_id, tenant_id)
@@ -46,12 +47,16 @@ type Variant struct {
 	SkillsetDirectoryID uuid.UUID    `db:"skillset_directory"`
 	CreatedAt           time.Time    `db:"created_at"`
 	UpdatedAt           time.Time    `db:"updated_at"`
+	DeletedAt           *time.Time   `db:"deleted_at"`
 }
 
-// VariantSummary represents a simplified variant with just name, ID, and directory IDs
+// VariantSummary represents a simplified variant with just name, description, info, ID, and directory IDs
 type VariantSummary struct {
-	VariantID           uuid.UUID `db:"variant_id"`
-	Name                string    `db:"name"`
-	ResourceDirectoryID uuid.UUID `db:"resource_directory"`
-	SkillsetDirectoryID uuid.UUID `db:"skillset_directory"`
+	VariantID           uuid.UUID    `db:"variant_id"`
+	Name                string       `db:"name"`
+	Description         string       `db:"description"`
+	Info                pgtype.JSONB `db:"info"`
+	ResourceDirectoryID uuid.UUID    `db:"resource_directory"`
+	SkillsetDirectoryID uuid.UUID    `db:"skillset_directory"`
+	DeletedAt           *time.Time   `db:"deleted_at"`
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// ViewTemplate is a view whose rule targets contain placeholders. Spec holds the rules and
+// groups of the template as JSON, and Info its labels and annotations.
+type ViewTemplate struct {
+	TemplateID  uuid.UUID          `db:"template_id"`
+	Label       string             `db:"label"`
+	Description string             `db:"description"`
+	Info        []byte             `db:"info"`
+	Spec        []byte             `db:"spec"`
+	CatalogID   uuid.UUID          `db:"catalog_id"`
+	TenantID    catcommon.TenantId `db:"tenant_id"`
+	CreatedBy   string             `db:"created_by"`
+	UpdatedBy   string             `db:"updated_by"`
+	CreatedAt   time.Time          `db:"created_at"`
+	UpdatedAt   time.Time          `db:"updated_at"`
+}
@@ -0,0 +1,191 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+func (mm *metadataManager) CreateAdmissionWebhook(ctx context.Context, webhook *models.AdmissionWebhook) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	if webhook.CreatedBy == "" {
+		return dberror.ErrMissingUserContext.Msg("missing user context")
+	}
+	webhook.TenantID = tenantID
+	webhook.UpdatedBy = webhook.CreatedBy
+
+	query := `
+		INSERT INTO admission_webhooks (label, description, info, spec, catalog_id, tenant_id, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING webhook_id, created_at, updated_at`
+
+	errDb := mm.conn().QueryRowContext(ctx, query,
+		webhook.Label,
+		sql.NullString{String: webhook.Description, Valid: webhook.Description != ""},
+		webhook.Info,
+		webhook.Spec,
+		webhook.CatalogID,
+		tenantID,
+		webhook.CreatedBy,
+		webhook.UpdatedBy,
+	).Scan(&webhook.WebhookID, &webhook.CreatedAt, &webhook.UpdatedAt)
+	if errDb != nil {
+		if pgErr, ok := errDb.(*pgconn.PgError); ok {
+			switch pgErr.Code {
+			case "23505":
+				return dberror.ErrAlreadyExists.Msg("admission webhook already exists")
+			case "23514":
+				return dberror.ErrInvalidInput.Msg("invalid admission webhook label format")
+			}
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("label", webhook.Label).Msg("failed to create admission webhook")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) GetAdmissionWebhookByLabel(ctx context.Context, label string, catalogID uuid.UUID) (*models.AdmissionWebhook, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT webhook_id, label, description, info, spec, catalog_id, tenant_id, created_by, updated_by, created_at, updated_at
+		FROM admission_webhooks
+		WHERE tenant_id = $1 AND catalog_id = $2 AND label = $3`
+
+	webhook, err := scanAdmissionWebhook(mm.conn().QueryRowContext(ctx, query, tenantID, catalogID, label))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dberror.ErrNotFound.Msg("admission webhook not found")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("label", label).Msg("failed to get admission webhook")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return webhook, nil
+}
+
+func (mm *metadataManager) UpdateAdmissionWebhook(ctx context.Context, webhook *models.AdmissionWebhook) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	if webhook.UpdatedBy == "" {
+		return dberror.ErrMissingUserContext.Msg("missing user context")
+	}
+
+	query := `
+		UPDATE admission_webhooks
+		SET description = $4,
+			info = $5,
+			spec = $6,
+			updated_by = $7,
+			updated_at = NOW()
+		WHERE tenant_id = $1 AND catalog_id = $2 AND label = $3`
+
+	result, err := mm.conn().ExecContext(ctx, query, tenantID, webhook.CatalogID, webhook.Label,
+		sql.NullString{String: webhook.Description, Valid: webhook.Description != ""},
+		webhook.Info, webhook.Spec, webhook.UpdatedBy)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("label", webhook.Label).Msg("failed to update admission webhook")
+		return dberror.ErrDatabase.Err(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberror.ErrDatabase.Err(err)
+	}
+	if rowsAffected == 0 {
+		return dberror.ErrNotFound.Msg("admission webhook not found")
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) DeleteAdmissionWebhookByLabel(ctx context.Context, label string, catalogID uuid.UUID) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		DELETE FROM admission_webhooks
+		WHERE tenant_id = $1 AND catalog_id = $2 AND label = $3`
+
+	result, err := mm.conn().ExecContext(ctx, query, tenantID, catalogID, label)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("label", label).Msg("failed to delete admission webhook")
+		return dberror.ErrDatabase.Err(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberror.ErrDatabase.Err(err)
+	}
+	if rowsAffected == 0 {
+		return dberror.ErrNotFound.Msg("admission webhook not found")
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) ListAdmissionWebhooksByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.AdmissionWebhook, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT webhook_id, label, description, info, spec, catalog_id, tenant_id, created_by, updated_by, created_at, updated_at
+		FROM admission_webhooks
+		WHERE tenant_id = $1 AND catalog_id = $2
+		ORDER BY label ASC`
+
+	rows, err := mm.conn().QueryContext(ctx, query, tenantID, catalogID)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var result []*models.AdmissionWebhook
+	for rows.Next() {
+		webhook, err := scanAdmissionWebhook(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan admission webhook row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		result = append(result, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return result, nil
+}
+
+func scanAdmissionWebhook(row rowScanner) (*models.AdmissionWebhook, error) {
+	var webhook models.AdmissionWebhook
+	var description sql.NullString
+	err := row.Scan(&webhook.WebhookID, &webhook.Label, &description, &webhook.Info, &webhook.Spec,
+		&webhook.CatalogID, &webhook.TenantID, &webhook.CreatedBy, &webhook.UpdatedBy,
+		&webhook.CreatedAt, &webhook.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Description = description.String
+	return &webhook, nil
+}
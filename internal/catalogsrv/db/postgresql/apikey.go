@@ -0,0 +1,222 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgconn"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+const apiKeyColumns = `key_id, name, description, key_prefix, key_hash, view_id, catalog_id, project_id, tenant_id,
+		created_by, expire_at, last_used_at, revoked_at, created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	key := &models.APIKey{}
+	var description sql.NullString
+	err := row.Scan(&key.KeyID, &key.Name, &description, &key.KeyPrefix, &key.KeyHash, &key.ViewID, &key.CatalogID,
+		&key.ProjectID, &key.TenantID, &key.CreatedBy, &key.ExpireAt, &key.LastUsedAt, &key.RevokedAt,
+		&key.CreatedAt, &key.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	key.Description = description.String
+	return key, nil
+}
+
+func (mm *metadataManager) CreateAPIKey(ctx context.Context, key *models.APIKey) apperrors.Error {
+	if err := key.Validate(); err != nil {
+		return dberror.ErrInvalidInput.Err(err)
+	}
+
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	key.TenantID = tenantID
+
+	description := sql.NullString{String: key.Description, Valid: key.Description != ""}
+
+	query := `
+		INSERT INTO api_keys (name, description, key_prefix, key_hash, view_id, catalog_id, project_id, tenant_id, created_by, expire_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING key_id, created_at, updated_at`
+
+	errDb := mm.conn().QueryRowContext(ctx, query,
+		key.Name, description, key.KeyPrefix, key.KeyHash, key.ViewID, key.CatalogID, key.ProjectID, key.TenantID,
+		key.CreatedBy, key.ExpireAt).
+		Scan(&key.KeyID, &key.CreatedAt, &key.UpdatedAt)
+
+	if errDb != nil {
+		if pgErr, ok := errDb.(*pgconn.PgError); ok {
+			switch {
+			case pgErr.Code == "23505":
+				return dberror.ErrAlreadyExists.Msg("api key already exists")
+			case pgErr.Code == "23514" && pgErr.ConstraintName == "api_keys_name_check":
+				return dberror.ErrInvalidInput.Msg("invalid api key name format")
+			case pgErr.Code == "23503":
+				return dberror.ErrInvalidInput.Msg("view or catalog does not exist")
+			}
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("name", key.Name).Msg("failed to create api key")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) GetAPIKey(ctx context.Context, keyID uuid.UUID) (*models.APIKey, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE tenant_id = $1 AND key_id = $2`
+
+	key, errDb := scanAPIKey(mm.conn().QueryRowContext(ctx, query, tenantID, keyID))
+	if errDb != nil {
+		if errDb == sql.ErrNoRows {
+			return nil, dberror.ErrNotFound.Msg("api key not found")
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("key_id", keyID.String()).Msg("failed to get api key")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+
+	return key, nil
+}
+
+// GetAPIKeyByHash looks up a key by the hash of its secret. The lookup is not scoped to a
+// tenant since the tenant is only known once the key has been found.
+func (mm *metadataManager) GetAPIKeyByHash(ctx context.Context, keyHash []byte) (*models.APIKey, apperrors.Error) {
+	query := `SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE key_hash = $1`
+
+	key, errDb := scanAPIKey(mm.conn().QueryRowContext(ctx, query, keyHash))
+	if errDb != nil {
+		if errDb == sql.ErrNoRows {
+			return nil, dberror.ErrNotFound.Msg("api key not found")
+		}
+		log.Ctx(ctx).Error().Err(errDb).Msg("failed to get api key by hash")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+
+	return key, nil
+}
+
+func (mm *metadataManager) ListAPIKeysByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.APIKey, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE tenant_id = $1 AND catalog_id = $2
+		ORDER BY name ASC`
+
+	rows, errDb := mm.conn().QueryContext(ctx, query, tenantID, catalogID)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Msg("failed to list api keys")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+	defer rows.Close()
+
+	var keys []*models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan api key row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return keys, nil
+}
+
+// TouchAPIKey records that the key was used. To avoid a write on every request, the
+// timestamp is only moved forward once a minute.
+func (mm *metadataManager) TouchAPIKey(ctx context.Context, keyID uuid.UUID) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		UPDATE api_keys
+		SET last_used_at = NOW()
+		WHERE tenant_id = $1 AND key_id = $2
+		  AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')`
+
+	if _, errDb := mm.conn().ExecContext(ctx, query, tenantID, keyID); errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("key_id", keyID.String()).Msg("failed to update api key last used time")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+// RevokeAPIKey marks the key as revoked. Revoking an already revoked key keeps the
+// original revocation time.
+func (mm *metadataManager) RevokeAPIKey(ctx context.Context, keyID uuid.UUID) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		UPDATE api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE tenant_id = $1 AND key_id = $2`
+
+	return mm.execAPIKeyUpdate(ctx, query, "failed to revoke api key", tenantID, keyID)
+}
+
+func (mm *metadataManager) DeleteAPIKey(ctx context.Context, keyID uuid.UUID) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		DELETE FROM api_keys
+		WHERE tenant_id = $1 AND key_id = $2`
+
+	return mm.execAPIKeyUpdate(ctx, query, "failed to delete api key", tenantID, keyID)
+}
+
+func (mm *metadataManager) execAPIKeyUpdate(ctx context.Context, query, errMsg string, tenantID catcommon.TenantId, keyID uuid.UUID) apperrors.Error {
+	result, errDb := mm.conn().ExecContext(ctx, query, tenantID, keyID)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("key_id", keyID.String()).Msg(errMsg)
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	rowsAffected, errDb := result.RowsAffected()
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Msg("failed to get rows affected")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	if rowsAffected == 0 {
+		log.Ctx(ctx).Info().Str("key_id", keyID.String()).Msg("api key not found")
+		return dberror.ErrNotFound.Msg("api key not found")
+	}
+
+	return nil
+}
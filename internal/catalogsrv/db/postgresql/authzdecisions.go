@@ -0,0 +1,157 @@
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+func (mm *metadataManager) RecordAuthzDecision(ctx context.Context, decision *models.AuthzDecision) apperrors.Error {
+	if err := decision.Validate(); err != nil {
+		return dberror.ErrInvalidInput.Err(err)
+	}
+
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	decision.TenantID = tenantID
+
+	actions, err := json.Marshal(decision.Actions)
+	if err != nil {
+		return dberror.ErrInvalidInput.Msg("invalid actions")
+	}
+	var matchedRules any
+	if len(decision.MatchedRules) > 0 {
+		matchedRules = []byte(decision.MatchedRules)
+	}
+
+	query := `
+		INSERT INTO authz_decisions (tenant_id, catalog_id, view_id, principal, actions, target, allowed, enforced, matched_rules)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING decision_id, created_at`
+
+	errDb := mm.conn().QueryRowContext(ctx, query,
+		tenantID, nullUUID(decision.CatalogID), nullUUID(decision.ViewID), decision.Principal, actions,
+		decision.Target, decision.Allowed, decision.Enforced, matchedRules).
+		Scan(&decision.DecisionID, &decision.CreatedAt)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("principal", decision.Principal).Msg("failed to record authz decision")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+// ListAuthzDecisions returns the decisions matching the filter, newest first. Target is
+// matched as a prefix, so that all decisions under a catalog or namespace can be listed.
+func (mm *metadataManager) ListAuthzDecisions(ctx context.Context, filter *models.AuthzDecisionFilter) ([]*models.AuthzDecision, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+	if filter == nil {
+		filter = &models.AuthzDecisionFilter{}
+	}
+
+	args := []any{tenantID}
+	where := []string{"tenant_id = $1"}
+	addCond := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, strings.ReplaceAll(cond, "$?", "$"+strconv.Itoa(len(args))))
+	}
+	if filter.CatalogID != uuid.Nil {
+		addCond("catalog_id = $?", filter.CatalogID)
+	}
+	if filter.Principal != "" {
+		addCond("principal = $?", filter.Principal)
+	}
+	if filter.Action != "" {
+		addCond("actions ? $?", filter.Action)
+	}
+	if filter.Target != "" {
+		addCond(`target LIKE $? || '%' ESCAPE '\'`, escapeLike(filter.Target))
+	}
+	if filter.Allowed != nil {
+		addCond("allowed = $?", *filter.Allowed)
+	}
+	if filter.Enforced != nil {
+		addCond("enforced = $?", *filter.Enforced)
+	}
+	if !filter.Since.IsZero() {
+		addCond("created_at >= $?", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		addCond("created_at < $?", filter.Until)
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.CreatedAt, filter.After.DecisionID)
+		where = append(where, "(created_at, decision_id) < ($"+strconv.Itoa(len(args)-1)+", $"+strconv.Itoa(len(args))+")")
+	}
+
+	query := `
+		SELECT decision_id, tenant_id, catalog_id, view_id, principal, actions, target, allowed, enforced, matched_rules, created_at
+		FROM authz_decisions
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY created_at DESC, decision_id DESC`
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += ` LIMIT $` + strconv.Itoa(len(args))
+	}
+
+	rows, errDb := mm.conn().QueryContext(ctx, query, args...)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Msg("failed to list authz decisions")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+	defer rows.Close()
+
+	var decisions []*models.AuthzDecision
+	for rows.Next() {
+		d := &models.AuthzDecision{}
+		var catalogID, viewID *uuid.UUID
+		var actions, matchedRules []byte
+		if err := rows.Scan(&d.DecisionID, &d.TenantID, &catalogID, &viewID, &d.Principal, &actions,
+			&d.Target, &d.Allowed, &d.Enforced, &matchedRules, &d.CreatedAt); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan authz decision row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		if catalogID != nil {
+			d.CatalogID = *catalogID
+		}
+		if viewID != nil {
+			d.ViewID = *viewID
+		}
+		if err := json.Unmarshal(actions, &d.Actions); err != nil {
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		if len(matchedRules) > 0 {
+			d.MatchedRules = matchedRules
+		}
+		decisions = append(decisions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return decisions, nil
+}
+
+func nullUUID(id uuid.UUID) any {
+	if id == uuid.Nil {
+		return nil
+	}
+	return id
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
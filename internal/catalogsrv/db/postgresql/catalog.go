@@ -3,7 +3,9 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgtype"
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
@@ -38,7 +40,7 @@ func (mm *metadataManager) CreateCatalog(ctx context.Context, catalog *models.Ca
 	}
 
 	// create a transaction
-	tx, errdb := mm.conn().BeginTx(ctx, &sql.TxOptions{})
+	tx, errdb := mm.beginTx(ctx, &sql.TxOptions{})
 	if errdb != nil {
 		log.Ctx(ctx).Error().Err(errdb).Msg("failed to start transaction")
 		return dberror.ErrDatabase.Err(errdb)
@@ -140,7 +142,7 @@ func (mm *metadataManager) GetCatalogIDByName(ctx context.Context, catalogName s
 	// Query to get the catalog_id by catalog name and tenant ID
 	query := `
 		SELECT catalog_id FROM catalogs 
-		WHERE tenant_id = $1 AND project_id = $2 AND name = $3;
+		WHERE tenant_id = $1 AND project_id = $2 AND name = $3 AND deleted_at IS NULL;
 	`
 	errDb := mm.conn().QueryRowContext(ctx, query, tenantID, projectID, catalogName).Scan(&catalogID)
 	if errDb != nil {
@@ -165,7 +167,7 @@ func (mm *metadataManager) GetCatalogByID(ctx context.Context, catalogID uuid.UU
 	query := `
         SELECT catalog_id, name, description, info, project_id
         FROM catalogs
-        WHERE tenant_id = $1 AND catalog_id = $2;
+        WHERE tenant_id = $1 AND catalog_id = $2 AND deleted_at IS NULL;
     `
 
 	row := mm.conn().QueryRowContext(ctx, query, tenantID, catalogID)
@@ -200,7 +202,7 @@ func (mm *metadataManager) GetCatalogByName(ctx context.Context, name string) (*
 	query := `
         SELECT catalog_id, name, description, info, project_id
         FROM catalogs
-        WHERE tenant_id = $1 AND project_id = $2 AND name = $3;
+        WHERE tenant_id = $1 AND project_id = $2 AND name = $3 AND deleted_at IS NULL;
     `
 
 	row := mm.conn().QueryRowContext(ctx, query, tenantID, projectID, name)
@@ -248,10 +250,10 @@ func (mm *metadataManager) UpdateCatalog(ctx context.Context, catalog *models.Ca
 
 	var row *sql.Row
 	if catalog.CatalogID != uuid.Nil {
-		query += "tenant_id = $1 AND project_id = $2 AND catalog_id = $3 RETURNING catalog_id, name;"
+		query += "tenant_id = $1 AND project_id = $2 AND catalog_id = $3 AND deleted_at IS NULL RETURNING catalog_id, name;"
 		row = mm.conn().QueryRowContext(ctx, query, tenantID, projectID, catalog.CatalogID, catalog.Description, catalog.Info)
 	} else {
-		query += "tenant_id = $1 AND project_id = $2 AND name = $3 RETURNING catalog_id, name;"
+		query += "tenant_id = $1 AND project_id = $2 AND name = $3 AND deleted_at IS NULL RETURNING catalog_id, name;"
 		row = mm.conn().QueryRowContext(ctx, query, tenantID, projectID, catalog.Name, catalog.Description, catalog.Info)
 	}
 
@@ -270,7 +272,58 @@ func (mm *metadataManager) UpdateCatalog(ctx context.Context, catalog *models.Ca
 	return nil
 }
 
-// DeleteCatalog deletes a catalog from the database.
+// MoveCatalog moves a catalog of the current project to another project of the tenant. The
+// api keys of the catalog are moved along with it, so that they keep resolving the catalog.
+func (mm *metadataManager) MoveCatalog(ctx context.Context, name string, toProjectID catcommon.ProjectId) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	projectID := catcommon.GetProjectID(ctx)
+	if projectID == "" {
+		return dberror.ErrInvalidInput.Msg("project ID is required")
+	}
+
+	query := `
+		WITH moved AS (
+			UPDATE catalogs
+			SET project_id = $4
+			WHERE tenant_id = $1 AND project_id = $2 AND name = $3 AND deleted_at IS NULL
+			RETURNING catalog_id
+		), keys AS (
+			UPDATE api_keys
+			SET project_id = $4
+			WHERE tenant_id = $1 AND catalog_id IN (SELECT catalog_id FROM moved)
+		)
+		SELECT catalog_id FROM moved;
+	`
+
+	var catalogID uuid.UUID
+	errDb := mm.conn().QueryRowContext(ctx, query, tenantID, projectID, name, toProjectID).Scan(&catalogID)
+	if errDb != nil {
+		if errDb == sql.ErrNoRows {
+			log.Ctx(ctx).Info().Str("name", name).Msg("catalog not found for move")
+			return dberror.ErrNotFound.Msg("catalog not found")
+		}
+		if pgErr, ok := errDb.(*pgconn.PgError); ok {
+			switch pgErr.Code {
+			case "23505":
+				return dberror.ErrAlreadyExists.Msg("catalog already exists in the target project")
+			case "23503":
+				return dberror.ErrInvalidInput.Msg("target project does not exist")
+			}
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("name", name).Msg("failed to move catalog")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+// DeleteCatalog soft deletes a catalog by setting its deleted_at time. The catalog and its
+// variants are no longer visible, and the catalog can be restored until it is purged. The name
+// stays reserved until then.
 // If both catalogID and name are provided, catalogID takes precedence.
 func (mm *metadataManager) DeleteCatalog(ctx context.Context, catalogID uuid.UUID, name string) apperrors.Error {
 	// Retrieve tenant and project IDs from context
@@ -291,23 +344,65 @@ func (mm *metadataManager) DeleteCatalog(ctx context.Context, catalogID uuid.UUI
 	}
 
 	query := `
-		DELETE FROM catalogs
-		WHERE `
+		UPDATE catalogs
+		SET deleted_at = NOW()
+		WHERE deleted_at IS NULL AND `
 
+	var result sql.Result
+	var err error
 	if catalogID != uuid.Nil {
 		query += "tenant_id = $1 AND project_id = $2 AND catalog_id = $3;"
-		_, err := mm.conn().ExecContext(ctx, query, tenantID, projectID, catalogID)
-		if err != nil {
-			log.Ctx(ctx).Error().Err(err).Str("catalog_id", catalogID.String()).Msg("failed to delete catalog")
-			return dberror.ErrDatabase.Err(err)
-		}
+		result, err = mm.conn().ExecContext(ctx, query, tenantID, projectID, catalogID)
 	} else {
 		query += "tenant_id = $1 AND project_id = $2 AND name = $3;"
-		_, err := mm.conn().ExecContext(ctx, query, tenantID, projectID, name)
-		if err != nil {
-			log.Ctx(ctx).Error().Err(err).Str("name", name).Msg("failed to delete catalog")
-			return dberror.ErrDatabase.Err(err)
+		result, err = mm.conn().ExecContext(ctx, query, tenantID, projectID, name)
+	}
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("name", name).Str("catalog_id", catalogID.String()).Msg("failed to delete catalog")
+		return dberror.ErrDatabase.Err(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to retrieve result information")
+		return dberror.ErrDatabase.Err(err)
+	}
+	if rowsAffected == 0 {
+		log.Ctx(ctx).Info().Str("name", name).Str("catalog_id", catalogID.String()).Msg("catalog not found")
+	}
+
+	return nil
+}
+
+// RestoreCatalog undoes the soft delete of a catalog. It returns ErrNotFound if there is no
+// deleted catalog with the name.
+func (mm *metadataManager) RestoreCatalog(ctx context.Context, name string) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	projectID := catcommon.GetProjectID(ctx)
+	if projectID == "" {
+		return dberror.ErrInvalidInput.Msg("project ID is required")
+	}
+
+	query := `
+		UPDATE catalogs
+		SET deleted_at = NULL
+		WHERE tenant_id = $1 AND project_id = $2 AND name = $3 AND deleted_at IS NOT NULL
+		RETURNING catalog_id;
+	`
+
+	var catalogID uuid.UUID
+	errDb := mm.conn().QueryRowContext(ctx, query, tenantID, projectID, name).Scan(&catalogID)
+	if errDb != nil {
+		if errDb == sql.ErrNoRows {
+			log.Ctx(ctx).Info().Str("name", name).Msg("deleted catalog not found")
+			return dberror.ErrNotFound.Msg("deleted catalog not found")
 		}
+		log.Ctx(ctx).Error().Err(errDb).Str("name", name).Msg("failed to restore catalog")
+		return dberror.ErrDatabase.Err(errDb)
 	}
 
 	return nil
@@ -328,7 +423,7 @@ func (mm *metadataManager) ListCatalogs(ctx context.Context) ([]*models.Catalog,
 	query := `
 		SELECT catalog_id, name, description, info, project_id
 		FROM catalogs
-		WHERE tenant_id = $1 AND project_id = $2
+		WHERE tenant_id = $1 AND project_id = $2 AND deleted_at IS NULL
 		ORDER BY name ASC
 	`
 
@@ -356,3 +451,68 @@ func (mm *metadataManager) ListCatalogs(ctx context.Context) ([]*models.Catalog,
 
 	return catalogs, nil
 }
+
+// ListDeletedCatalogs retrieves the soft deleted catalogs of the current tenant and project
+// that have not been purged yet.
+func (mm *metadataManager) ListDeletedCatalogs(ctx context.Context) ([]*models.Catalog, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	projectID := catcommon.GetProjectID(ctx)
+	if projectID == "" {
+		return nil, dberror.ErrInvalidInput.Msg("project ID is required")
+	}
+
+	query := `
+		SELECT catalog_id, name, description, info, project_id, deleted_at
+		FROM catalogs
+		WHERE tenant_id = $1 AND project_id = $2 AND deleted_at IS NOT NULL
+		ORDER BY name ASC
+	`
+
+	rows, err := mm.conn().QueryContext(ctx, query, tenantID, projectID)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var catalogs []*models.Catalog
+	for rows.Next() {
+		var catalog models.Catalog
+		err := rows.Scan(&catalog.CatalogID, &catalog.Name, &catalog.Description, &catalog.Info, &catalog.ProjectID, &catalog.DeletedAt)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan catalog row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		catalogs = append(catalogs, &catalog)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return catalogs, nil
+}
+
+// PurgeDeletedCatalogs permanently deletes catalogs, across all tenants, that were soft deleted
+// before the given time, together with everything they contain. It returns the number of
+// catalogs purged.
+func (mm *metadataManager) PurgeDeletedCatalogs(ctx context.Context, before time.Time) (int64, apperrors.Error) {
+	query := `
+		DELETE FROM catalogs
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1;
+	`
+
+	result, err := mm.conn().ExecContext(ctx, query, before)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to purge deleted catalogs")
+		return 0, dberror.ErrDatabase.Err(err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, dberror.ErrDatabase.Err(err)
+	}
+	return purged, nil
+}
@@ -3,6 +3,7 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/golang/snappy"
 	"github.com/rs/zerolog/log"
@@ -50,10 +51,10 @@ func (om *objectManager) CreateCatalogObject(ctx context.Context, obj *models.Ca
 
 	// Insert the catalog object into the database
 	query := `
-		INSERT INTO catalog_objects (hash_id, hash, type, version, tenant_id, data)
-		VALUES ($1, $2, $3, $4, $5, $6);
+		INSERT INTO catalog_objects (hash_id, hash, type, version, tenant_id, data, search_vector)
+		VALUES ($1, $2, $3, $4, $5, $6, to_tsvector('simple', search_words($7)));
 	`
-	result, err := om.conn().ExecContext(ctx, query, obj.HashID, obj.Hash, obj.Type, obj.Version, tenantID, dataZ)
+	result, err := om.conn().ExecContext(ctx, query, obj.HashID, obj.Hash, obj.Type, obj.Version, tenantID, dataZ, obj.SearchText)
 	if err != nil {
 		return dberror.ErrDatabase.Err(err)
 	}
@@ -143,11 +144,12 @@ func (om *objectManager) DeleteCatalogObject(ctx context.Context, t catcommon.Ca
 		return dberror.ErrInvalidInput.Msg("invalid catalog object type")
 	}
 
-	// look for references in this table for this hash
+	// look for references in this table for this hash, including previous revisions
 	query := `
 		SELECT 1
 		FROM ` + table + `
-		WHERE tenant_id = $1 AND jsonb_path_query_array(directory, '$.*.hash') @> to_jsonb($2::text)
+		WHERE tenant_id = $1 AND (jsonb_path_query_array(directory, '$.*.hash') @> to_jsonb($2::text)
+			OR jsonb_path_query_array(directory, '$.*.history[*].hash') @> to_jsonb($2::text))
 		LIMIT 1;
 	`
 	var exists bool // we'll probably just hit the ErrNoRows case in case of false
@@ -184,3 +186,51 @@ func (om *objectManager) DeleteCatalogObject(ctx context.Context, t catcommon.Ca
 
 	return nil
 }
+
+// DeleteUnreferencedCatalogObjects deletes up to limit catalog objects that are not referenced
+// by any directory of their type and were last changed before the given time. Objects are
+// deleted in the tenant of the context, or across all tenants if the context has no tenant.
+// Directories of soft deleted variants still hold references, so their objects are kept until
+// the variant is purged. Previous revisions of resources are references too. It returns the number of objects deleted and the bytes they stored.
+func (om *objectManager) DeleteUnreferencedCatalogObjects(ctx context.Context, before time.Time, limit int) (int64, int64, apperrors.Error) {
+	if limit <= 0 {
+		return 0, 0, dberror.ErrInvalidInput.Msg("limit must be positive")
+	}
+	tenantID := catcommon.GetTenantID(ctx)
+
+	query := `
+		WITH garbage AS (
+			SELECT o.id
+			FROM catalog_objects o
+			WHERE ($1 = '' OR o.tenant_id = $1)
+				AND o.updated_at < $2
+				AND NOT EXISTS (
+					SELECT 1 FROM resource_directory d
+					WHERE o.type = 'resource' AND d.tenant_id = o.tenant_id
+						AND (jsonb_path_query_array(d.directory, '$.*.hash') @> to_jsonb(o.hash::text)
+							OR jsonb_path_query_array(d.directory, '$.*.history[*].hash') @> to_jsonb(o.hash::text))
+				)
+				AND NOT EXISTS (
+					SELECT 1 FROM skillset_directory d
+					WHERE o.type = 'skillset' AND d.tenant_id = o.tenant_id
+						AND jsonb_path_query_array(d.directory, '$.*.hash') @> to_jsonb(o.hash::text)
+				)
+			ORDER BY o.id
+			LIMIT $3
+		),
+		deleted AS (
+			DELETE FROM catalog_objects o
+			USING garbage g
+			WHERE o.id = g.id
+			RETURNING octet_length(o.data) AS size
+		)
+		SELECT COUNT(*), COALESCE(SUM(size), 0) FROM deleted;
+	`
+	var objects, bytes int64
+	err := om.conn().QueryRowContext(ctx, query, tenantID, before, limit).Scan(&objects, &bytes)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to delete unreferenced catalog objects")
+		return 0, 0, dberror.ErrDatabase.Err(err)
+	}
+	return objects, bytes, nil
+}
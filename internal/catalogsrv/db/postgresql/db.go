@@ -11,10 +11,12 @@ type hatchCatalogDb struct {
 	cm *connectionManager
 }
 
-func NewHatchCatalogDb(c dbmanager.ScopedConn) (*metadataManager, *objectManager, *connectionManager) {
+// NewHatchCatalogDb returns the managers of the connection. If tx is not nil, the managers run
+// their statements in the transaction.
+func NewHatchCatalogDb(c dbmanager.ScopedConn, tx *Tx) (*metadataManager, *objectManager, *connectionManager) {
 	h := &hatchCatalogDb{}
-	h.mm = newMetadataManager(c)
-	h.om = newObjectManager(c)
+	h.mm = newMetadataManager(c, tx)
+	h.om = newObjectManager(c, tx)
 	h.cm = newConnectionManager(c)
 	h.om.m = h.mm
 	return h.mm, h.om, h.cm
@@ -9,29 +9,45 @@ import (
 
 // Metadata Manager
 type metadataManager struct {
-	c dbmanager.ScopedConn
+	c  dbmanager.ScopedConn
+	tx *Tx
 }
 
-func (mm *metadataManager) conn() *sql.Conn {
+func (mm *metadataManager) conn() queryer {
+	if mm.tx != nil {
+		return mm.tx.tx
+	}
 	return mm.c.Conn()
 }
 
-func newMetadataManager(c dbmanager.ScopedConn) *metadataManager {
-	return &metadataManager{c: c}
+func (mm *metadataManager) beginTx(ctx context.Context, opts *sql.TxOptions) (transaction, error) {
+	return beginTx(ctx, mm.c, mm.tx, opts)
+}
+
+func newMetadataManager(c dbmanager.ScopedConn, tx *Tx) *metadataManager {
+	return &metadataManager{c: c, tx: tx}
 }
 
 // Object Manager
 type objectManager struct {
-	c dbmanager.ScopedConn
-	m *metadataManager
+	c  dbmanager.ScopedConn
+	tx *Tx
+	m  *metadataManager
 }
 
-func (om *objectManager) conn() *sql.Conn {
+func (om *objectManager) conn() queryer {
+	if om.tx != nil {
+		return om.tx.tx
+	}
 	return om.c.Conn()
 }
 
-func newObjectManager(c dbmanager.ScopedConn) *objectManager {
-	return &objectManager{c: c}
+func (om *objectManager) beginTx(ctx context.Context, opts *sql.TxOptions) (transaction, error) {
+	return beginTx(ctx, om.c, om.tx, opts)
+}
+
+func newObjectManager(c dbmanager.ScopedConn, tx *Tx) *objectManager {
+	return &objectManager{c: c, tx: tx}
 }
 
 // Connection Manager
@@ -21,7 +21,7 @@ func (mm *metadataManager) CreateNamespace(ctx context.Context, ns *models.Names
 
 	ns.TenantID = tenantID
 
-	tx, errStd := mm.conn().BeginTx(ctx, nil)
+	tx, errStd := mm.beginTx(ctx, nil)
 	if errStd != nil {
 		log.Ctx(ctx).Error().Err(errStd).Msg("failed to begin transaction")
 		return dberror.ErrDatabase.Err(errStd)
@@ -48,7 +48,7 @@ func (mm *metadataManager) CreateNamespace(ctx context.Context, ns *models.Names
 	return nil
 }
 
-func (mm *metadataManager) createNamespaceWithTransaction(ctx context.Context, ns *models.Namespace, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) createNamespaceWithTransaction(ctx context.Context, ns *models.Namespace, tx transaction) apperrors.Error {
 	if ns.Name == "" {
 		ns.Name = catcommon.DefaultNamespace
 	}
@@ -106,9 +106,9 @@ func (mm *metadataManager) GetNamespace(ctx context.Context, name string, varian
 		FROM 
 			namespaces n
 		JOIN 
-			variants v ON n.variant_id = v.variant_id AND n.tenant_id = v.tenant_id
+			variants v ON n.variant_id = v.variant_id AND n.tenant_id = v.tenant_id AND v.deleted_at IS NULL
 		JOIN 
-			catalogs c ON v.catalog_id = c.catalog_id AND v.tenant_id = c.tenant_id
+			catalogs c ON v.catalog_id = c.catalog_id AND v.tenant_id = c.tenant_id AND c.deleted_at IS NULL
 		WHERE 
 			n.tenant_id = $1 AND 
 			n.variant_id = $2 AND 
@@ -3,6 +3,7 @@ package postgresql
 import (
 	"context"
 	"errors"
+	"time"
 
 	"encoding/json"
 
@@ -13,6 +14,10 @@ import (
 	"github.com/tansive/tansive-internal/internal/common/uuid"
 )
 
+// resourceRevisionLimit is the number of previous revisions kept for each resource. Older
+// revisions are dropped from the history and their objects are garbage collected.
+const resourceRevisionLimit = 20
+
 func (om *objectManager) UpsertResource(ctx context.Context, rg *models.Resource, directoryID uuid.UUID) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
@@ -144,14 +149,41 @@ func (om *objectManager) UpsertResourceObject(ctx context.Context, rg *models.Re
 		}
 	}
 
-	// Then add/update the directory entry
+	// Then add/update the directory entry, keeping the replaced hash in the history
+	now := time.Now().UTC()
+	objRef := models.ObjectRef{
+		Hash:      rg.Hash,
+		UpdatedBy: catcommon.GetPrincipal(ctx),
+		UpdatedAt: &now,
+	}
+	existing, err := om.GetObjectRefByPath(ctx, catcommon.CatalogObjectTypeResource, directoryID, rg.Path)
+	if err != nil && !errors.Is(err, dberror.ErrNotFound) {
+		return err
+	}
+	if existing != nil {
+		objRef.History = existing.History
+		if existing.Hash != rg.Hash {
+			replaced := models.ObjectRevision{
+				Hash:       existing.Hash,
+				UpdatedBy:  existing.UpdatedBy,
+				UpdatedAt:  existing.UpdatedAt,
+				ReplacedAt: now,
+			}
+			objRef.History = append([]models.ObjectRevision{replaced}, objRef.History...)
+		} else {
+			// Saving an unchanged resource does not make a new revision
+			objRef.UpdatedBy = existing.UpdatedBy
+			objRef.UpdatedAt = existing.UpdatedAt
+		}
+		if len(objRef.History) > resourceRevisionLimit {
+			objRef.History = objRef.History[:resourceRevisionLimit]
+		}
+	}
 	err = om.AddOrUpdateObjectByPath(ctx,
 		catcommon.CatalogObjectTypeResource,
 		directoryID,
 		rg.Path,
-		models.ObjectRef{
-			Hash: rg.Hash,
-		},
+		objRef,
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,70 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// RevokeToken adds a token to the denylist. Revoking a token twice is not an error. Entries
+// of the tenant whose tokens have since expired are removed along the way.
+func (mm *metadataManager) RevokeToken(ctx context.Context, token *models.RevokedToken) apperrors.Error {
+	if err := token.Validate(); err != nil {
+		return dberror.ErrInvalidInput.Err(err)
+	}
+
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	token.TenantID = tenantID
+
+	revokedBy := sql.NullString{String: token.RevokedBy, Valid: token.RevokedBy != ""}
+
+	query := `
+		INSERT INTO revoked_tokens (jti, tenant_id, expire_at, revoked_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, jti) DO NOTHING`
+
+	if _, errDb := mm.conn().ExecContext(ctx, query, token.JTI, tenantID, token.ExpireAt, revokedBy); errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("jti", token.JTI.String()).Msg("failed to revoke token")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	query = `
+		DELETE FROM revoked_tokens
+		WHERE tenant_id = $1 AND expire_at < NOW()`
+
+	if _, errDb := mm.conn().ExecContext(ctx, query, tenantID); errDb != nil {
+		log.Ctx(ctx).Warn().Err(errDb).Msg("failed to purge expired revoked tokens")
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) IsTokenRevoked(ctx context.Context, jti uuid.UUID) (bool, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return false, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM revoked_tokens
+			WHERE tenant_id = $1 AND jti = $2
+		)`
+
+	var revoked bool
+	if errDb := mm.conn().QueryRowContext(ctx, query, tenantID, jti).Scan(&revoked); errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("jti", jti.String()).Msg("failed to check token revocation")
+		return false, dberror.ErrDatabase.Err(errDb)
+	}
+
+	return revoked, nil
+}
@@ -38,7 +38,7 @@ func (om *objectManager) CreateSchemaDirectory(ctx context.Context, t catcommon.
 
 	dir.TenantID = tenantID
 
-	tx, err := om.conn().BeginTx(ctx, &sql.TxOptions{})
+	tx, err := om.beginTx(ctx, &sql.TxOptions{})
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Msg("failed to start transaction")
 		return dberror.ErrDatabase.Err(err)
@@ -110,7 +110,7 @@ func (om *objectManager) GetDirectory(ctx context.Context, t catcommon.CatalogOb
 	return dir, nil
 }
 
-func (om *objectManager) createSchemaDirectoryWithTransaction(ctx context.Context, t catcommon.CatalogObjectType, dir *models.SchemaDirectory, tx *sql.Tx) apperrors.Error {
+func (om *objectManager) createSchemaDirectoryWithTransaction(ctx context.Context, t catcommon.CatalogObjectType, dir *models.SchemaDirectory, tx transaction) apperrors.Error {
 	tableName := getSchemaDirectoryTableName(t)
 	if tableName == "" {
 		return dberror.ErrInvalidInput.Msg("invalid catalog object type")
@@ -403,7 +403,7 @@ func (om *objectManager) DeleteNamespaceObjects(ctx context.Context, t catcommon
 		return nil, dberror.ErrInvalidInput.Msg("invalid catalog object type")
 	}
 
-	tx, err := om.conn().BeginTx(ctx, &sql.TxOptions{
+	tx, err := om.beginTx(ctx, &sql.TxOptions{
 		Isolation: sql.LevelSerializable,
 	})
 	if err != nil {
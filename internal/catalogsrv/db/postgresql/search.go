@@ -0,0 +1,187 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang/snappy"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// searchQuery matches the catalog, its variants and namespaces by name and description, and the
+// resources and skillsets in the directories of its variants by path or by the search vector of
+// their catalog object. Parameters:
+//
+//	$1 tenant ID, $2 catalog ID, $3 variant ID or uuid_nil() for all variants,
+//	$4 tsquery, $5 storage prefix of the default namespace, $6 comma separated kinds or '' for
+//	all kinds, $7 limit
+const searchQuery = `
+	WITH q AS (
+		SELECT to_tsquery('simple', $4) AS query
+	),
+	scoped_variants AS (
+		SELECT variant_id, name, description, resource_directory, skillset_directory
+		FROM variants
+		WHERE tenant_id = $1 AND catalog_id = $2 AND deleted_at IS NULL
+			AND ($3 = uuid_nil() OR variant_id = $3)
+	),
+	matched_objects AS (
+		SELECT o.hash::text AS hash, o.type, MAX(ts_rank(o.search_vector, q.query)) AS rank
+		FROM catalog_objects o CROSS JOIN q
+		WHERE o.tenant_id = $1 AND o.search_vector @@ q.query
+		GROUP BY o.hash, o.type
+	),
+	entries AS (
+		SELECT 'Resource' AS kind, 'resource' AS type, sv.variant_id, sv.name AS variant, e.key AS path, e.value->>'hash' AS hash
+		FROM scoped_variants sv
+		JOIN resource_directory d ON d.tenant_id = $1 AND d.directory_id = sv.resource_directory
+		CROSS JOIN LATERAL jsonb_each(d.directory) e
+		UNION ALL
+		SELECT 'SkillSet', 'skillset', sv.variant_id, sv.name, e.key, e.value->>'hash'
+		FROM scoped_variants sv
+		JOIN skillset_directory d ON d.tenant_id = $1 AND d.directory_id = sv.skillset_directory
+		CROSS JOIN LATERAL jsonb_each(d.directory) e
+	),
+	hits AS (
+		SELECT 'Catalog' AS kind, uuid_nil() AS variant_id, '' AS variant, c.name, '' AS path,
+			COALESCE(c.description, '') AS description,
+			ts_rank(to_tsvector('simple', search_words(c.name || ' ' || COALESCE(c.description, ''))), q.query) AS rank
+		FROM catalogs c CROSS JOIN q
+		WHERE c.tenant_id = $1 AND c.catalog_id = $2 AND c.deleted_at IS NULL
+			AND to_tsvector('simple', search_words(c.name || ' ' || COALESCE(c.description, ''))) @@ q.query
+		UNION ALL
+		SELECT 'Variant', sv.variant_id, sv.name, sv.name, '', COALESCE(sv.description, ''),
+			ts_rank(to_tsvector('simple', search_words(sv.name || ' ' || COALESCE(sv.description, ''))), q.query)
+		FROM scoped_variants sv CROSS JOIN q
+		WHERE to_tsvector('simple', search_words(sv.name || ' ' || COALESCE(sv.description, ''))) @@ q.query
+		UNION ALL
+		SELECT 'Namespace', sv.variant_id, sv.name, n.name, '', COALESCE(n.description, ''),
+			ts_rank(to_tsvector('simple', search_words(n.name || ' ' || COALESCE(n.description, ''))), q.query)
+		FROM namespaces n
+		JOIN scoped_variants sv ON n.variant_id = sv.variant_id
+		CROSS JOIN q
+		WHERE n.tenant_id = $1
+			AND to_tsvector('simple', search_words(n.name || ' ' || COALESCE(n.description, ''))) @@ q.query
+		UNION ALL
+		SELECT e.kind, e.variant_id, e.variant, '', e.path, '',
+			GREATEST(COALESCE(m.rank, 0),
+				ts_rank(to_tsvector('simple', search_words(translate(replace(e.path, $5, ''), '/', ' '))), q.query))
+		FROM entries e
+		CROSS JOIN q
+		LEFT JOIN matched_objects m ON m.hash = e.hash AND m.type = e.type
+		WHERE m.hash IS NOT NULL
+			OR to_tsvector('simple', search_words(translate(replace(e.path, $5, ''), '/', ' '))) @@ q.query
+	)
+	SELECT kind, variant_id, variant, name, path, description, rank
+	FROM hits
+	WHERE $6 = '' OR kind = ANY(string_to_array($6, ','))
+	ORDER BY rank DESC, kind, variant, name, path
+	LIMIT $7;
+`
+
+// SearchCatalog returns up to limit objects in the catalog that match all of the given terms,
+// best matches first. Terms match words by prefix, and camelCase words also by their parts.
+// If variantID is uuid.Nil all variants of the catalog are searched, and if kinds is empty
+// objects of all kinds are returned.
+func (om *objectManager) SearchCatalog(ctx context.Context, catalogID, variantID uuid.UUID, terms []string, kinds []string, limit int) ([]models.SearchHit, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+	if len(terms) == 0 {
+		return nil, dberror.ErrInvalidInput.Msg("search terms cannot be empty")
+	}
+	if limit <= 0 {
+		return nil, dberror.ErrInvalidInput.Msg("limit must be positive")
+	}
+
+	// Terms are words of letters and digits, so they are safe to use as tsquery lexemes
+	lexemes := make([]string, 0, len(terms))
+	for _, t := range terms {
+		lexemes = append(lexemes, strings.ToLower(t)+":*")
+	}
+	tsQuery := strings.Join(lexemes, " & ")
+	defaultNamespacePrefix := "/" + catcommon.DefaultNamespace
+
+	rows, err := om.conn().QueryContext(ctx, searchQuery,
+		tenantID, catalogID, variantID, tsQuery, defaultNamespacePrefix, strings.Join(kinds, ","), limit)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to search catalog")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var hits []models.SearchHit
+	for rows.Next() {
+		var h models.SearchHit
+		if err := rows.Scan(&h.Kind, &h.VariantID, &h.Variant, &h.Name, &h.Path, &h.Description, &h.Rank); err != nil {
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		hits = append(hits, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return hits, nil
+}
+
+// ListUnindexedCatalogObjects returns up to limit catalog objects, across all tenants, that have
+// not been indexed for search. The data of the returned objects is uncompressed.
+func (om *objectManager) ListUnindexedCatalogObjects(ctx context.Context, limit int) ([]models.CatalogObject, apperrors.Error) {
+	query := `
+		SELECT id, hash_id, hash, type, version, tenant_id, data
+		FROM catalog_objects
+		WHERE search_vector IS NULL
+		ORDER BY id
+		LIMIT $1;
+	`
+	rows, err := om.conn().QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var objs []models.CatalogObject
+	for rows.Next() {
+		var obj models.CatalogObject
+		if err := rows.Scan(&obj.ID, &obj.HashID, &obj.Hash, &obj.Type, &obj.Version, &obj.TenantID, &obj.Data); err != nil {
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		if config.CompressCatalogObjects {
+			data, err := snappy.Decode(nil, obj.Data)
+			if err != nil {
+				// Index the object as empty, so that it is not returned again
+				log.Ctx(ctx).Error().Err(err).Int64("id", obj.ID).Msg("failed to uncompress catalog object data")
+				data = nil
+			}
+			obj.Data = data
+		}
+		objs = append(objs, obj)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return objs, nil
+}
+
+// SetCatalogObjectSearchText indexes the catalog object with the given ID for search by the
+// given text.
+func (om *objectManager) SetCatalogObjectSearchText(ctx context.Context, id int64, text string) apperrors.Error {
+	query := `
+		UPDATE catalog_objects
+		SET search_vector = to_tsvector('simple', search_words($2))
+		WHERE id = $1;
+	`
+	if _, err := om.conn().ExecContext(ctx, query, id, text); err != nil {
+		return dberror.ErrDatabase.Err(err)
+	}
+	return nil
+}
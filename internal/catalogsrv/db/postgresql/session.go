@@ -15,7 +15,7 @@ import (
 
 // UpsertSession creates a new session in the database.
 func (mm *metadataManager) UpsertSession(ctx context.Context, session *models.Session) (err apperrors.Error) {
-	tx, errStd := mm.conn().BeginTx(ctx, nil)
+	tx, errStd := mm.beginTx(ctx, nil)
 	if errStd != nil {
 		log.Ctx(ctx).Error().Err(errStd).Msg("failed to begin transaction")
 		return dberror.ErrDatabase.Err(errStd)
@@ -43,7 +43,7 @@ func (mm *metadataManager) UpsertSession(ctx context.Context, session *models.Se
 }
 
 // upsertSessionWithTransaction handles the actual session creation within a transaction.
-func (mm *metadataManager) upsertSessionWithTransaction(ctx context.Context, session *models.Session, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) upsertSessionWithTransaction(ctx context.Context, session *models.Session, tx transaction) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
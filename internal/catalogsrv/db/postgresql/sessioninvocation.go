@@ -0,0 +1,137 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgconn"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// AppendSessionInvocations adds records to the audit trail of a session. The records are
+// added together or not at all; a record whose sequence number is already taken fails the
+// append with ErrAlreadyExists.
+func (mm *metadataManager) AppendSessionInvocations(ctx context.Context, invocations []*models.SessionInvocation) (err apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	if len(invocations) == 0 {
+		return nil
+	}
+
+	tx, errStd := mm.beginTx(ctx, nil)
+	if errStd != nil {
+		log.Ctx(ctx).Error().Err(errStd).Msg("failed to begin transaction")
+		return dberror.ErrDatabase.Err(errStd)
+	}
+	defer func() {
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				log.Ctx(ctx).Error().Err(rollbackErr).Msg("failed to rollback transaction")
+			}
+		}
+	}()
+
+	query := `
+		INSERT INTO session_invocations (
+			session_id, seq, invocation_id, skill, decision, outcome,
+			hash, verification_key, record, tenant_id
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING created_at
+	`
+	for _, inv := range invocations {
+		inv.TenantID = tenantID
+		errDb := tx.QueryRowContext(ctx, query,
+			inv.SessionID, inv.Seq, inv.InvocationID, inv.Skill, inv.Decision, inv.Outcome,
+			inv.Hash, inv.VerificationKey, []byte(inv.Record), tenantID,
+		).Scan(&inv.CreatedAt)
+		if errDb != nil {
+			if pgErr, ok := errDb.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+				return dberror.ErrAlreadyExists.Msg("invocation record already exists")
+			}
+			log.Ctx(ctx).Error().Err(errDb).Str("session_id", inv.SessionID.String()).Msg("failed to append session invocation")
+			return dberror.ErrDatabase.Err(errDb)
+		}
+	}
+
+	if errStd := tx.Commit(); errStd != nil {
+		log.Ctx(ctx).Error().Err(errStd).Msg("failed to commit transaction")
+		return dberror.ErrDatabase.Err(errStd)
+	}
+	return nil
+}
+
+// GetLastSessionInvocation returns the record of the audit trail of a session with the
+// highest sequence number. Returns ErrNotFound if the trail is empty.
+func (mm *metadataManager) GetLastSessionInvocation(ctx context.Context, sessionID uuid.UUID) (*models.SessionInvocation, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT session_id, seq, invocation_id, skill, decision, outcome,
+			hash, verification_key, record, tenant_id, created_at
+		FROM session_invocations
+		WHERE tenant_id = $1 AND session_id = $2
+		ORDER BY seq DESC
+		LIMIT 1
+	`
+	inv := &models.SessionInvocation{}
+	err := mm.conn().QueryRowContext(ctx, query, tenantID, sessionID).Scan(
+		&inv.SessionID, &inv.Seq, &inv.InvocationID, &inv.Skill, &inv.Decision, &inv.Outcome,
+		&inv.Hash, &inv.VerificationKey, &inv.Record, &inv.TenantID, &inv.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, dberror.ErrNotFound.Msg("no invocation records")
+		}
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	return inv, nil
+}
+
+// ListSessionInvocations returns the audit trail of a session in sequence order.
+func (mm *metadataManager) ListSessionInvocations(ctx context.Context, sessionID uuid.UUID) ([]*models.SessionInvocation, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT session_id, seq, invocation_id, skill, decision, outcome,
+			hash, verification_key, record, tenant_id, created_at
+		FROM session_invocations
+		WHERE tenant_id = $1 AND session_id = $2
+		ORDER BY seq
+	`
+	rows, err := mm.conn().QueryContext(ctx, query, tenantID, sessionID)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var result []*models.SessionInvocation
+	for rows.Next() {
+		inv := &models.SessionInvocation{}
+		if err := rows.Scan(
+			&inv.SessionID, &inv.Seq, &inv.InvocationID, &inv.Skill, &inv.Decision, &inv.Outcome,
+			&inv.Hash, &inv.VerificationKey, &inv.Record, &inv.TenantID, &inv.CreatedAt,
+		); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan session invocation row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		result = append(result, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	return result, nil
+}
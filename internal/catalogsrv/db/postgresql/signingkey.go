@@ -14,7 +14,7 @@ import (
 // CreateSigningKey creates a new signing key in the database.
 func (mm *metadataManager) CreateSigningKey(ctx context.Context, key *models.SigningKey) apperrors.Error {
 	// If key is active, we need to deactivate any existing active keys in a transaction
-	tx, errdb := mm.conn().BeginTx(ctx, &sql.TxOptions{})
+	tx, errdb := mm.beginTx(ctx, &sql.TxOptions{})
 	if errdb != nil {
 		log.Ctx(ctx).Error().Err(errdb).Msg("failed to start transaction")
 		return dberror.ErrDatabase.Err(errdb)
@@ -112,7 +112,7 @@ func (mm *metadataManager) GetActiveSigningKey(ctx context.Context) (*models.Sig
 // UpdateSigningKeyActive updates the active status of a signing key.
 func (mm *metadataManager) UpdateSigningKeyActive(ctx context.Context, keyID uuid.UUID, isActive bool) apperrors.Error {
 	// Start a transaction since we need to handle the one-active-key rule
-	tx, errdb := mm.conn().BeginTx(ctx, &sql.TxOptions{})
+	tx, errdb := mm.beginTx(ctx, &sql.TxOptions{})
 	if errdb != nil {
 		log.Ctx(ctx).Error().Err(errdb).Msg("failed to start transaction")
 		return dberror.ErrDatabase.Err(errdb)
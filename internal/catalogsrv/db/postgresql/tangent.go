@@ -3,6 +3,7 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/jackc/pgconn"
 	"github.com/rs/zerolog/log"
@@ -14,7 +15,7 @@ import (
 )
 
 func (mm *metadataManager) CreateTangent(ctx context.Context, tangent *models.Tangent) (err apperrors.Error) {
-	tx, errStd := mm.conn().BeginTx(ctx, nil)
+	tx, errStd := mm.beginTx(ctx, nil)
 	if errStd != nil {
 		log.Ctx(ctx).Error().Err(errStd).Msg("failed to begin transaction")
 		return dberror.ErrDatabase.Err(errStd)
@@ -41,7 +42,7 @@ func (mm *metadataManager) CreateTangent(ctx context.Context, tangent *models.Ta
 	return nil
 }
 
-func (mm *metadataManager) createTangentWithTransaction(ctx context.Context, tangent *models.Tangent, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) createTangentWithTransaction(ctx context.Context, tangent *models.Tangent, tx transaction) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
@@ -85,14 +86,14 @@ func (mm *metadataManager) GetTangent(ctx context.Context, id uuid.UUID) (*model
 	tenantID := catcommon.GetTenantID(ctx)
 
 	query := `
-		SELECT id, info, public_key, status, tenant_id, created_at, updated_at
+		SELECT id, info, public_key, status, health, last_heartbeat_at, tenant_id, created_at, updated_at
 		FROM tangents
 		WHERE id = $1
 	`
 
 	var tangent models.Tangent
 	err := mm.conn().QueryRowContext(ctx, query, id).
-		Scan(&tangent.ID, &tangent.Info, &tangent.PublicKey, &tangent.Status, &tangent.TenantID, &tangent.CreatedAt, &tangent.UpdatedAt)
+		Scan(&tangent.ID, &tangent.Info, &tangent.PublicKey, &tangent.Status, &tangent.Health, &tangent.LastHeartbeatAt, &tangent.TenantID, &tangent.CreatedAt, &tangent.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -143,6 +144,39 @@ func (mm *metadataManager) UpdateTangent(ctx context.Context, tangent *models.Ta
 	return nil
 }
 
+// RecordTangentHeartbeat stores the status and health reported by a tangent and the time
+// of its heartbeat.
+func (mm *metadataManager) RecordTangentHeartbeat(ctx context.Context, id uuid.UUID, status string, health json.RawMessage) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		UPDATE tangents
+		SET status = $3,
+			health = $4,
+			last_heartbeat_at = NOW()
+		WHERE tenant_id = $1 AND id = $2
+	`
+
+	result, err := mm.conn().ExecContext(ctx, query, tenantID, id, status, health)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to record tangent heartbeat")
+		return dberror.ErrDatabase.Err(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberror.ErrDatabase.Err(err)
+	}
+	if rowsAffected == 0 {
+		return dberror.ErrNotFound.Msg("tangent not found")
+	}
+
+	return nil
+}
+
 func (mm *metadataManager) DeleteTangent(ctx context.Context, id uuid.UUID) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
@@ -177,7 +211,7 @@ func (mm *metadataManager) ListTangents(ctx context.Context) ([]*models.Tangent,
 	}
 
 	query := `
-		SELECT id, info, public_key, status, tenant_id, created_at, updated_at
+		SELECT id, info, public_key, status, health, last_heartbeat_at, tenant_id, created_at, updated_at
 		FROM tangents
 		WHERE tenant_id = $1
 		ORDER BY updated_at DESC
@@ -193,7 +227,7 @@ func (mm *metadataManager) ListTangents(ctx context.Context) ([]*models.Tangent,
 
 	for rows.Next() {
 		var tangent models.Tangent
-		err := rows.Scan(&tangent.ID, &tangent.Info, &tangent.PublicKey, &tangent.Status, &tangent.TenantID, &tangent.CreatedAt, &tangent.UpdatedAt)
+		err := rows.Scan(&tangent.ID, &tangent.Info, &tangent.PublicKey, &tangent.Status, &tangent.Health, &tangent.LastHeartbeatAt, &tangent.TenantID, &tangent.CreatedAt, &tangent.UpdatedAt)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to scan tangent row")
 			return nil, dberror.ErrDatabase.Err(err)
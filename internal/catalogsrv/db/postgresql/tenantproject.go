@@ -62,7 +62,7 @@ func (mm *metadataManager) CreateTenant(ctx context.Context, tenantID catcommon.
 // GetTenant retrieves a tenant from the database.
 func (mm *metadataManager) GetTenant(ctx context.Context, tenantID catcommon.TenantId) (*models.Tenant, error) {
 	query := `
-		SELECT tenant_id
+		SELECT tenant_id, created_at, updated_at, suspended_at
 		FROM tenants
 		WHERE tenant_id = $1;
 	`
@@ -71,7 +71,7 @@ func (mm *metadataManager) GetTenant(ctx context.Context, tenantID catcommon.Ten
 	row := mm.conn().QueryRowContext(ctx, query, string(tenantID))
 
 	var tenant models.Tenant
-	err := row.Scan(&tenant.TenantID)
+	err := row.Scan(&tenant.TenantID, &tenant.CreatedAt, &tenant.UpdatedAt, &tenant.SuspendedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Ctx(ctx).Info().Str("tenant_id", string(tenantID)).Msg("tenant not found")
@@ -84,6 +84,61 @@ func (mm *metadataManager) GetTenant(ctx context.Context, tenantID catcommon.Ten
 	return &tenant, nil
 }
 
+// ListTenants retrieves the tenants visible to the connection, ordered by tenant ID. Without a
+// tenant scope, these are all tenants.
+func (mm *metadataManager) ListTenants(ctx context.Context) ([]*models.Tenant, error) {
+	query := `
+		SELECT tenant_id, created_at, updated_at, suspended_at
+		FROM tenants
+		ORDER BY tenant_id ASC;
+	`
+
+	rows, err := mm.conn().QueryContext(ctx, query)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list tenants")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var tenants []*models.Tenant
+	for rows.Next() {
+		var tenant models.Tenant
+		if err := rows.Scan(&tenant.TenantID, &tenant.CreatedAt, &tenant.UpdatedAt, &tenant.SuspendedAt); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan tenant row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		tenants = append(tenants, &tenant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return tenants, nil
+}
+
+// SetTenantSuspended suspends or resumes a tenant. Suspending a suspended tenant keeps the time
+// it was first suspended.
+func (mm *metadataManager) SetTenantSuspended(ctx context.Context, tenantID catcommon.TenantId, suspended bool) error {
+	query := `
+		UPDATE tenants
+		SET suspended_at = CASE WHEN $2 THEN COALESCE(suspended_at, NOW()) ELSE NULL END
+		WHERE tenant_id = $1;
+	`
+	result, err := mm.conn().ExecContext(ctx, query, string(tenantID), suspended)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tenant_id", string(tenantID)).Msg("failed to update tenant")
+		return dberror.ErrDatabase.Err(err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return dberror.ErrDatabase.Err(err)
+	}
+	if n == 0 {
+		return dberror.ErrNotFound.Msg("tenant not found")
+	}
+	return nil
+}
+
 // DeleteTenant deletes a tenant from the database.
 func (mm *metadataManager) DeleteTenant(ctx context.Context, tenantID catcommon.TenantId) error {
 	query := `
@@ -142,7 +197,7 @@ func (mm *metadataManager) GetProject(ctx context.Context, projectID catcommon.P
 	}
 
 	query := `
-		SELECT project_id, tenant_id
+		SELECT project_id, tenant_id, created_at, updated_at
 		FROM projects
 		WHERE tenant_id = $1 AND project_id = $2;
 	`
@@ -151,7 +206,7 @@ func (mm *metadataManager) GetProject(ctx context.Context, projectID catcommon.P
 	row := mm.conn().QueryRowContext(ctx, query, string(tenantID), string(projectID))
 
 	var project models.Project
-	err := row.Scan(&project.ProjectID, &project.TenantID)
+	err := row.Scan(&project.ProjectID, &project.TenantID, &project.CreatedAt, &project.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Ctx(ctx).Info().
@@ -168,6 +223,46 @@ func (mm *metadataManager) GetProject(ctx context.Context, projectID catcommon.P
 	return &project, nil
 }
 
+// ListProjects retrieves the projects of the tenant, ordered by project ID.
+func (mm *metadataManager) ListProjects(ctx context.Context) ([]*models.Project, error) {
+	tenantID := catcommon.GetTenantID(ctx)
+
+	// Validate tenantID to ensure it is not empty
+	if tenantID == "" {
+		log.Ctx(ctx).Error().Msg("tenant ID is missing from context")
+		return nil, dberror.ErrInvalidInput.Msg("tenant ID is required")
+	}
+
+	query := `
+		SELECT project_id, tenant_id, created_at, updated_at
+		FROM projects
+		WHERE tenant_id = $1
+		ORDER BY project_id ASC;
+	`
+
+	rows, err := mm.conn().QueryContext(ctx, query, string(tenantID))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to list projects")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var projects []*models.Project
+	for rows.Next() {
+		var project models.Project
+		if err := rows.Scan(&project.ProjectID, &project.TenantID, &project.CreatedAt, &project.UpdatedAt); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan project row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		projects = append(projects, &project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return projects, nil
+}
+
 // DeleteProject deletes a project from the database. If the project does not exist, it does nothing.
 func (mm *metadataManager) DeleteProject(ctx context.Context, projectID catcommon.ProjectId) error {
 	tenantID := catcommon.GetTenantID(ctx)
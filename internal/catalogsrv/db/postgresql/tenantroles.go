@@ -0,0 +1,137 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+// SetTenantRole grants the user a role in the tenant, replacing any role the user had.
+func (mm *metadataManager) SetTenantRole(ctx context.Context, role *models.TenantRole) apperrors.Error {
+	if err := role.Validate(); err != nil {
+		return dberror.ErrInvalidInput.Err(err)
+	}
+
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	role.TenantID = tenantID
+
+	createdBy := sql.NullString{String: role.CreatedBy, Valid: role.CreatedBy != ""}
+
+	query := `
+		INSERT INTO tenant_roles (tenant_id, user_id, role, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, user_id) DO UPDATE
+		SET role = EXCLUDED.role
+		RETURNING created_by, created_at, updated_at`
+
+	var storedCreatedBy sql.NullString
+	errDb := mm.conn().QueryRowContext(ctx, query, tenantID, role.UserID, role.Role, createdBy).
+		Scan(&storedCreatedBy, &role.CreatedAt, &role.UpdatedAt)
+	if errDb != nil {
+		if pgErr, ok := errDb.(*pgconn.PgError); ok && pgErr.Code == "23514" {
+			return dberror.ErrInvalidInput.Msg("invalid role: " + role.Role)
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("user_id", role.UserID).Msg("failed to set tenant role")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+	role.CreatedBy = storedCreatedBy.String
+
+	return nil
+}
+
+func (mm *metadataManager) GetTenantRole(ctx context.Context, userID string) (*models.TenantRole, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT tenant_id, user_id, role, created_by, created_at, updated_at
+		FROM tenant_roles
+		WHERE tenant_id = $1 AND user_id = $2`
+
+	role, err := scanTenantRole(mm.conn().QueryRowContext(ctx, query, tenantID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dberror.ErrNotFound.Msg("tenant role not found")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID).Msg("failed to get tenant role")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return role, nil
+}
+
+func (mm *metadataManager) ListTenantRoles(ctx context.Context) ([]*models.TenantRole, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT tenant_id, user_id, role, created_by, created_at, updated_at
+		FROM tenant_roles
+		WHERE tenant_id = $1
+		ORDER BY user_id ASC`
+
+	rows, errDb := mm.conn().QueryContext(ctx, query, tenantID)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Msg("failed to list tenant roles")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+	defer rows.Close()
+
+	var roles []*models.TenantRole
+	for rows.Next() {
+		role, err := scanTenantRole(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan tenant role row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return roles, nil
+}
+
+// DeleteTenantRole removes the user's role in the tenant. If the user has no role, it does nothing.
+func (mm *metadataManager) DeleteTenantRole(ctx context.Context, userID string) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		DELETE FROM tenant_roles
+		WHERE tenant_id = $1 AND user_id = $2`
+
+	if _, errDb := mm.conn().ExecContext(ctx, query, tenantID, userID); errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("user_id", userID).Msg("failed to delete tenant role")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+func scanTenantRole(row rowScanner) (*models.TenantRole, error) {
+	role := &models.TenantRole{}
+	var createdBy sql.NullString
+	if err := row.Scan(&role.TenantID, &role.UserID, &role.Role, &createdBy, &role.CreatedAt, &role.UpdatedAt); err != nil {
+		return nil, err
+	}
+	role.CreatedBy = createdBy.String
+	return role, nil
+}
@@ -0,0 +1,226 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+// snapshotTables are the tables included in tenant snapshots, in an order that satisfies their
+// foreign keys on restore. Credentials, tokens, sessions and the audit log are left out, as
+// they are bound to the signing keys and runtime state of the database they were created in.
+var snapshotTables = []string{
+	"projects",
+	"users",
+	"groups",
+	"group_members",
+	"tenant_roles",
+	"catalogs",
+	"variants",
+	"resource_directory",
+	"skillset_directory",
+	"namespaces",
+	"catalog_objects",
+	"views",
+	"view_templates",
+}
+
+// snapshotSkippedColumns are columns that are left out of snapshots because the restoring
+// database generates them: serial IDs, and search vectors, which the search backfill rebuilds.
+var snapshotSkippedColumns = map[string][]string{
+	"catalog_objects": {"id", "search_vector"},
+}
+
+// DumpTenant returns a snapshot of the rows of the tenant of the context. The rows of all tables
+// are read in a single repeatable read transaction, so the snapshot is consistent.
+func (mm *metadataManager) DumpTenant(ctx context.Context) (*models.TenantSnapshot, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	tx, err := mm.beginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer tx.Rollback()
+
+	snapshot := &models.TenantSnapshot{
+		TenantID: tenantID,
+		Tables:   make(map[string]json.RawMessage, len(snapshotTables)),
+	}
+	if snapshot.SchemaVersion, err = schemaVersion(ctx, tx); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	if err := tx.QueryRowContext(ctx, `SELECT NOW()`).Scan(&snapshot.TakenAt); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	for _, table := range snapshotTables {
+		row := "to_jsonb(t)"
+		for _, column := range snapshotSkippedColumns[table] {
+			row += " - " + pq.QuoteLiteral(column)
+		}
+		query := `
+			SELECT COALESCE(jsonb_agg(` + row + `), '[]'::jsonb)
+			FROM ` + table + ` t
+			WHERE t.tenant_id = $1;
+		`
+		var rows []byte
+		if err := tx.QueryRowContext(ctx, query, tenantID).Scan(&rows); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("table", table).Msg("failed to dump tenant rows")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		snapshot.Tables[table] = rows
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	return snapshot, nil
+}
+
+// RestoreTenant inserts the rows of the snapshot into the tenant of the context, which may
+// differ from the tenant the snapshot was taken from. The tenant must not have any catalogs.
+// Rows that already exist, such as the default project, are kept as they are. It returns the
+// number of rows inserted into each table.
+func (mm *metadataManager) RestoreTenant(ctx context.Context, snapshot *models.TenantSnapshot) (map[string]int64, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+	if snapshot == nil {
+		return nil, dberror.ErrInvalidInput.Msg("snapshot cannot be nil")
+	}
+	known := make(map[string]bool, len(snapshotTables))
+	for _, table := range snapshotTables {
+		known[table] = true
+	}
+	for table := range snapshot.Tables {
+		if !known[table] {
+			return nil, dberror.ErrInvalidInput.Msg("unknown table in snapshot: " + table)
+		}
+	}
+
+	tx, err := mm.beginTx(ctx, nil)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer tx.Rollback()
+
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	if snapshot.SchemaVersion > version {
+		return nil, dberror.ErrInvalidInput.Msg("snapshot is from a newer schema version")
+	}
+
+	var hasCatalogs bool
+	err = tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM catalogs WHERE tenant_id = $1)`, tenantID).Scan(&hasCatalogs)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	if hasCatalogs {
+		return nil, dberror.ErrAlreadyExists.Msg("tenant already has catalogs")
+	}
+
+	restored := make(map[string]int64, len(snapshotTables))
+	for _, table := range snapshotTables {
+		var rows []map[string]json.RawMessage
+		if data := snapshot.Tables[table]; len(data) > 0 {
+			if err := json.Unmarshal(data, &rows); err != nil {
+				return nil, dberror.ErrInvalidInput.Msg("invalid rows for table " + table)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		columns, err := snapshotColumns(ctx, tx, table, rows[0])
+		if err != nil {
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		// Columns missing from the snapshot are left to their defaults, and tenant_id is
+		// replaced so that the rows land in the restoring tenant
+		query := `
+			INSERT INTO ` + table + ` (` + columns + `)
+			SELECT ` + columns + `
+			FROM jsonb_populate_recordset(NULL::` + table + `, (
+				SELECT jsonb_agg(e || jsonb_build_object('tenant_id', $2::text))
+				FROM jsonb_array_elements($1::jsonb) e
+			))
+			ON CONFLICT DO NOTHING;
+		`
+		result, err := tx.ExecContext(ctx, query, string(snapshot.Tables[table]), tenantID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("table", table).Msg("failed to restore tenant rows")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		if restored[table], err = result.RowsAffected(); err != nil {
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	return restored, nil
+}
+
+// snapshotColumns returns the quoted, comma separated columns of the table that are present in
+// the given snapshot row and not generated by the database.
+func snapshotColumns(ctx context.Context, q queryer, table string, row map[string]json.RawMessage) (string, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1
+		ORDER BY ordinal_position;
+	`, table)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	skipped := make(map[string]bool)
+	for _, column := range snapshotSkippedColumns[table] {
+		skipped[column] = true
+	}
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return "", err
+		}
+		if _, ok := row[column]; ok && !skipped[column] {
+			columns = append(columns, pq.QuoteIdentifier(column))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+	return strings.Join(columns, ", "), nil
+}
+
+// schemaVersion returns the latest applied schema migration, or 0 if migrations have never been
+// recorded in the database.
+func schemaVersion(ctx context.Context, q queryer) (int64, error) {
+	var recorded bool
+	if err := q.QueryRowContext(ctx, `SELECT to_regclass('schema_migrations') IS NOT NULL`).Scan(&recorded); err != nil {
+		return 0, err
+	}
+	if !recorded {
+		return 0, nil
+	}
+	var version int64
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dbmanager"
+)
+
+// queryer runs statements on the connection of a manager, or on its transaction if the manager
+// was created for one.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// transaction is a transaction started by a single manager method.
+type transaction interface {
+	queryer
+	Commit() error
+	Rollback() error
+}
+
+// Tx is a transaction spanning the calls of the managers created for it. Manager methods that
+// use a transaction of their own run it as a savepoint of Tx, so that their changes are undone
+// on failure without ending Tx. Such savepoints run at the isolation level of Tx.
+type Tx struct {
+	tx         *sql.Tx
+	savepoints int
+}
+
+// BeginTx starts a transaction on the connection.
+func BeginTx(ctx context.Context, c dbmanager.ScopedConn) (*Tx, error) {
+	tx, err := c.Conn().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx}, nil
+}
+
+// Commit commits the transaction.
+func (t *Tx) Commit() error {
+	return t.tx.Commit()
+}
+
+// Rollback aborts the transaction.
+func (t *Tx) Rollback() error {
+	return t.tx.Rollback()
+}
+
+// Lock takes an advisory lock on key that is held until the transaction ends, waiting for the
+// transaction holding it, if any, to end first.
+func (t *Tx) Lock(ctx context.Context, key string) error {
+	_, err := t.tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(hashtextextended($1, 0))", key)
+	return err
+}
+
+// Savepoint starts a savepoint of the transaction. Committing the savepoint releases it and
+// rolling it back undoes the changes made since it was started, without ending Tx.
+func (t *Tx) Savepoint(ctx context.Context) (driver.Tx, error) {
+	return t.savepoint(ctx)
+}
+
+func (t *Tx) savepoint(ctx context.Context) (transaction, error) {
+	t.savepoints++
+	sp := &savepoint{Tx: t.tx, ctx: ctx, name: fmt.Sprintf("sp_%d", t.savepoints)}
+	if _, err := t.tx.ExecContext(ctx, "SAVEPOINT "+sp.name); err != nil {
+		return nil, err
+	}
+	return sp, nil
+}
+
+// savepoint is a transaction nested in a Tx. Like sql.Tx, it can be ended only once.
+type savepoint struct {
+	*sql.Tx
+	ctx  context.Context
+	name string
+	done bool
+}
+
+func (s *savepoint) Commit() error {
+	if s.done {
+		return sql.ErrTxDone
+	}
+	s.done = true
+	_, err := s.Tx.ExecContext(s.ctx, "RELEASE SAVEPOINT "+s.name)
+	return err
+}
+
+func (s *savepoint) Rollback() error {
+	if s.done {
+		return sql.ErrTxDone
+	}
+	s.done = true
+	_, err := s.Tx.ExecContext(s.ctx, "ROLLBACK TO SAVEPOINT "+s.name)
+	return err
+}
+
+func beginTx(ctx context.Context, c dbmanager.ScopedConn, t *Tx, opts *sql.TxOptions) (transaction, error) {
+	if t != nil {
+		return t.savepoint(ctx)
+	}
+	tx, err := c.Conn().BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
@@ -0,0 +1,358 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+func (mm *metadataManager) CreateUser(ctx context.Context, user *models.User) apperrors.Error {
+	if err := user.Validate(); err != nil {
+		return dberror.ErrInvalidInput.Err(err)
+	}
+
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	user.TenantID = tenantID
+
+	query := `
+		INSERT INTO users (tenant_id, user_id, display_name, email)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, updated_at`
+
+	errDb := mm.conn().QueryRowContext(ctx, query, tenantID, user.UserID,
+		sql.NullString{String: user.DisplayName, Valid: user.DisplayName != ""},
+		sql.NullString{String: user.Email, Valid: user.Email != ""}).
+		Scan(&user.CreatedAt, &user.UpdatedAt)
+	if errDb != nil {
+		if pgErr, ok := errDb.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return dberror.ErrAlreadyExists.Msg("user already exists")
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("user_id", user.UserID).Msg("failed to create user")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) GetUser(ctx context.Context, userID string) (*models.User, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT tenant_id, user_id, display_name, email, created_at, updated_at
+		FROM users
+		WHERE tenant_id = $1 AND user_id = $2`
+
+	user, err := scanUser(mm.conn().QueryRowContext(ctx, query, tenantID, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dberror.ErrNotFound.Msg("user not found")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID).Msg("failed to get user")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return user, nil
+}
+
+func (mm *metadataManager) ListUsers(ctx context.Context) ([]*models.User, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT tenant_id, user_id, display_name, email, created_at, updated_at
+		FROM users
+		WHERE tenant_id = $1
+		ORDER BY user_id ASC`
+
+	rows, errDb := mm.conn().QueryContext(ctx, query, tenantID)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Msg("failed to list users")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan user row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return users, nil
+}
+
+// DeleteUser deletes the user along with the user's group memberships.
+func (mm *metadataManager) DeleteUser(ctx context.Context, userID string) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		DELETE FROM users
+		WHERE tenant_id = $1 AND user_id = $2`
+
+	result, errDb := mm.conn().ExecContext(ctx, query, tenantID, userID)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("user_id", userID).Msg("failed to delete user")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return dberror.ErrNotFound.Msg("user not found")
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) CreateGroup(ctx context.Context, group *models.Group) apperrors.Error {
+	if err := group.Validate(); err != nil {
+		return dberror.ErrInvalidInput.Err(err)
+	}
+
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	group.TenantID = tenantID
+
+	query := `
+		INSERT INTO groups (tenant_id, name, description)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at`
+
+	errDb := mm.conn().QueryRowContext(ctx, query, tenantID, group.Name,
+		sql.NullString{String: group.Description, Valid: group.Description != ""}).
+		Scan(&group.CreatedAt, &group.UpdatedAt)
+	if errDb != nil {
+		if pgErr, ok := errDb.(*pgconn.PgError); ok {
+			switch pgErr.Code {
+			case "23505":
+				return dberror.ErrAlreadyExists.Msg("group already exists")
+			case "23514":
+				return dberror.ErrInvalidInput.Msg("invalid group name format")
+			}
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("name", group.Name).Msg("failed to create group")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) GetGroup(ctx context.Context, name string) (*models.Group, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT tenant_id, name, description, created_at, updated_at
+		FROM groups
+		WHERE tenant_id = $1 AND name = $2`
+
+	group, err := scanGroup(mm.conn().QueryRowContext(ctx, query, tenantID, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dberror.ErrNotFound.Msg("group not found")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("name", name).Msg("failed to get group")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return group, nil
+}
+
+func (mm *metadataManager) ListGroups(ctx context.Context) ([]*models.Group, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT tenant_id, name, description, created_at, updated_at
+		FROM groups
+		WHERE tenant_id = $1
+		ORDER BY name ASC`
+
+	rows, errDb := mm.conn().QueryContext(ctx, query, tenantID)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Msg("failed to list groups")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+	defer rows.Close()
+
+	var groups []*models.Group
+	for rows.Next() {
+		group, err := scanGroup(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan group row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		groups = append(groups, group)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return groups, nil
+}
+
+// DeleteGroup deletes the group along with its memberships.
+func (mm *metadataManager) DeleteGroup(ctx context.Context, name string) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		DELETE FROM groups
+		WHERE tenant_id = $1 AND name = $2`
+
+	result, errDb := mm.conn().ExecContext(ctx, query, tenantID, name)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("name", name).Msg("failed to delete group")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return dberror.ErrNotFound.Msg("group not found")
+	}
+
+	return nil
+}
+
+// AddGroupMember adds the user to the group. Adding an existing member is not an error.
+func (mm *metadataManager) AddGroupMember(ctx context.Context, groupName, userID string) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		INSERT INTO group_members (tenant_id, group_name, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id, group_name, user_id) DO NOTHING`
+
+	if _, errDb := mm.conn().ExecContext(ctx, query, tenantID, groupName, userID); errDb != nil {
+		if pgErr, ok := errDb.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			return dberror.ErrNotFound.Msg("group or user does not exist")
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("group", groupName).Str("user_id", userID).Msg("failed to add group member")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+// RemoveGroupMember removes the user from the group. If the user is not a member, it does nothing.
+func (mm *metadataManager) RemoveGroupMember(ctx context.Context, groupName, userID string) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		DELETE FROM group_members
+		WHERE tenant_id = $1 AND group_name = $2 AND user_id = $3`
+
+	if _, errDb := mm.conn().ExecContext(ctx, query, tenantID, groupName, userID); errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Str("group", groupName).Str("user_id", userID).Msg("failed to remove group member")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) ListGroupMembers(ctx context.Context, groupName string) ([]string, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT user_id
+		FROM group_members
+		WHERE tenant_id = $1 AND group_name = $2
+		ORDER BY user_id ASC`
+
+	return mm.listStrings(ctx, query, tenantID, groupName)
+}
+
+func (mm *metadataManager) ListUserGroups(ctx context.Context, userID string) ([]string, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT group_name
+		FROM group_members
+		WHERE tenant_id = $1 AND user_id = $2
+		ORDER BY group_name ASC`
+
+	return mm.listStrings(ctx, query, tenantID, userID)
+}
+
+func (mm *metadataManager) listStrings(ctx context.Context, query string, args ...any) ([]string, apperrors.Error) {
+	rows, errDb := mm.conn().QueryContext(ctx, query, args...)
+	if errDb != nil {
+		log.Ctx(ctx).Error().Err(errDb).Msg("failed to run list query")
+		return nil, dberror.ErrDatabase.Err(errDb)
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		values = append(values, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return values, nil
+}
+
+func scanUser(row rowScanner) (*models.User, error) {
+	user := &models.User{}
+	var displayName, email sql.NullString
+	if err := row.Scan(&user.TenantID, &user.UserID, &displayName, &email, &user.CreatedAt, &user.UpdatedAt); err != nil {
+		return nil, err
+	}
+	user.DisplayName = displayName.String
+	user.Email = email.String
+	return user, nil
+}
+
+func scanGroup(row rowScanner) (*models.Group, error) {
+	group := &models.Group{}
+	var description sql.NullString
+	if err := row.Scan(&group.TenantID, &group.Name, &description, &group.CreatedAt, &group.UpdatedAt); err != nil {
+		return nil, err
+	}
+	group.Description = description.String
+	return group, nil
+}
@@ -3,6 +3,7 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/rs/zerolog/log"
@@ -20,7 +21,7 @@ import (
 // the catalog ID is invalid, or there is a database error.
 func (mm *metadataManager) CreateVariant(ctx context.Context, variant *models.Variant) (err apperrors.Error) {
 	// Start a transaction
-	tx, errdb := mm.conn().BeginTx(ctx, &sql.TxOptions{})
+	tx, errdb := mm.beginTx(ctx, &sql.TxOptions{})
 	if errdb != nil {
 		log.Ctx(ctx).Error().Err(errdb).Msg("failed to start transaction")
 		return dberror.ErrDatabase.Err(errdb)
@@ -48,7 +49,7 @@ func (mm *metadataManager) CreateVariant(ctx context.Context, variant *models.Va
 	return nil
 }
 
-func (mm *metadataManager) createVariantWithTransaction(ctx context.Context, variant *models.Variant, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) createVariantWithTransaction(ctx context.Context, variant *models.Variant, tx transaction) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
@@ -192,14 +193,14 @@ func (mm *metadataManager) GetVariant(ctx context.Context, catalogID uuid.UUID,
 		query = `
 			SELECT variant_id, name, description, info, catalog_id, resource_directory, skillset_directory
 			FROM variants
-			WHERE tenant_id = $1 AND variant_id = $2;
+			WHERE tenant_id = $1 AND variant_id = $2 AND deleted_at IS NULL;
 		`
 		row = mm.conn().QueryRowContext(ctx, query, tenantID, variantID)
 	} else if name != "" {
 		query = `
 			SELECT variant_id, name, description, info, catalog_id, resource_directory, skillset_directory
 			FROM variants
-			WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3;
+			WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3 AND deleted_at IS NULL;
 		`
 		row = mm.conn().QueryRowContext(ctx, query, tenantID, catalogID, name)
 	} else {
@@ -231,7 +232,7 @@ func (mm *metadataManager) GetVariantByID(ctx context.Context, variantID uuid.UU
 	query := `
 		SELECT variant_id, name, description, info, catalog_id, resource_directory, skillset_directory
 		FROM variants
-		WHERE tenant_id = $1 AND variant_id = $2;
+		WHERE tenant_id = $1 AND variant_id = $2 AND deleted_at IS NULL;
 	`
 	row := mm.conn().QueryRowContext(ctx, query, tenantID, variantID)
 	variant := &models.Variant{}
@@ -257,7 +258,7 @@ func (mm *metadataManager) GetVariantIDFromName(ctx context.Context, catalogID u
 	query := `
 		SELECT variant_id
 		FROM variants
-		WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3;
+		WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3 AND deleted_at IS NULL;
 	`
 
 	var variantID uuid.UUID
@@ -292,7 +293,7 @@ func (mm *metadataManager) UpdateVariant(ctx context.Context, variantID uuid.UUI
 		query = `
 			UPDATE variants
 			SET name = $4, description = $5, info = $6
-			WHERE tenant_id = $1 AND catalog_id = $2 AND variant_id = $3
+			WHERE tenant_id = $1 AND catalog_id = $2 AND variant_id = $3 AND deleted_at IS NULL
 			RETURNING variant_id;
 		`
 		row = mm.conn().QueryRowContext(ctx, query, tenantID, updatedVariant.CatalogID, variantID, updatedVariant.Name, updatedVariant.Description, updatedVariant.Info)
@@ -300,7 +301,7 @@ func (mm *metadataManager) UpdateVariant(ctx context.Context, variantID uuid.UUI
 		query = `
 			UPDATE variants
 			SET name = $4, description = $5, info = $6
-			WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3
+			WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3 AND deleted_at IS NULL
 			RETURNING variant_id;
 		`
 		row = mm.conn().QueryRowContext(ctx, query, tenantID, updatedVariant.CatalogID, name, updatedVariant.Name, updatedVariant.Description, updatedVariant.Info)
@@ -333,7 +334,8 @@ func (mm *metadataManager) UpdateVariant(ctx context.Context, variantID uuid.UUI
 	return nil
 }
 
-// DeleteVariant deletes a variant from the database based on the variant ID or name.
+// DeleteVariant soft deletes a variant based on the variant ID or name by setting its
+// deleted_at time. The variant can be restored until it is purged.
 // If both variantID and name are provided, variantID takes precedence.
 // Returns an error if the variant is not found or there is a database error.
 func (mm *metadataManager) DeleteVariant(ctx context.Context, catalogID, variantID uuid.UUID, name string) apperrors.Error {
@@ -352,14 +354,16 @@ func (mm *metadataManager) DeleteVariant(ctx context.Context, catalogID, variant
 
 	if variantID != uuid.Nil {
 		query = `
-			DELETE FROM variants
-			WHERE tenant_id = $1 AND catalog_id = $2 AND variant_id = $3;
+			UPDATE variants
+			SET deleted_at = NOW()
+			WHERE tenant_id = $1 AND catalog_id = $2 AND variant_id = $3 AND deleted_at IS NULL;
 		`
 		result, err = mm.conn().ExecContext(ctx, query, tenantID, catalogID, variantID)
 	} else {
 		query = `
-			DELETE FROM variants
-			WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3;
+			UPDATE variants
+			SET deleted_at = NOW()
+			WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3 AND deleted_at IS NULL;
 		`
 		result, err = mm.conn().ExecContext(ctx, query, tenantID, catalogID, name)
 	}
@@ -411,7 +415,7 @@ func (mm *metadataManager) GetMetadataNames(ctx context.Context, catalogID uuid.
 }
 
 // ListVariantsByCatalog retrieves all variants for a given catalog ID.
-// Returns an array of VariantSummary containing just the variant ID, name, and directory IDs.
+// Returns an array of VariantSummary containing just the variant ID, name, description, and directory IDs.
 // Returns an error if there is a database error or if the tenant ID is missing.
 func (mm *metadataManager) ListVariantsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]models.VariantSummary, apperrors.Error) {
 	tenantID := catcommon.GetTenantID(ctx)
@@ -420,9 +424,9 @@ func (mm *metadataManager) ListVariantsByCatalog(ctx context.Context, catalogID
 	}
 
 	query := `
-		SELECT variant_id, name, resource_directory, skillset_directory
+		SELECT variant_id, name, description, info, resource_directory, skillset_directory
 		FROM variants
-		WHERE tenant_id = $1 AND catalog_id = $2
+		WHERE tenant_id = $1 AND catalog_id = $2 AND deleted_at IS NULL
 		ORDER BY name;
 	`
 
@@ -436,7 +440,48 @@ func (mm *metadataManager) ListVariantsByCatalog(ctx context.Context, catalogID
 	var variants []models.VariantSummary
 	for rows.Next() {
 		var variant models.VariantSummary
-		err := rows.Scan(&variant.VariantID, &variant.Name, &variant.ResourceDirectoryID, &variant.SkillsetDirectoryID)
+		err := rows.Scan(&variant.VariantID, &variant.Name, &variant.Description, &variant.Info, &variant.ResourceDirectoryID, &variant.SkillsetDirectoryID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan variant row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		variants = append(variants, variant)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("error iterating over variant rows")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return variants, nil
+}
+
+// ListDeletedVariantsByCatalog retrieves the soft deleted variants of a catalog that have not
+// been purged yet.
+func (mm *metadataManager) ListDeletedVariantsByCatalog(ctx context.Context, catalogID uuid.UUID) ([]models.VariantSummary, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT variant_id, name, description, info, resource_directory, skillset_directory, deleted_at
+		FROM variants
+		WHERE tenant_id = $1 AND catalog_id = $2 AND deleted_at IS NOT NULL
+		ORDER BY name;
+	`
+
+	rows, err := mm.conn().QueryContext(ctx, query, tenantID, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("catalog_id", catalogID.String()).Msg("failed to query deleted variants")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var variants []models.VariantSummary
+	for rows.Next() {
+		var variant models.VariantSummary
+		err := rows.Scan(&variant.VariantID, &variant.Name, &variant.Description, &variant.Info, &variant.ResourceDirectoryID, &variant.SkillsetDirectoryID, &variant.DeletedAt)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("failed to scan variant row")
 			return nil, dberror.ErrDatabase.Err(err)
@@ -451,3 +496,144 @@ func (mm *metadataManager) ListVariantsByCatalog(ctx context.Context, catalogID
 
 	return variants, nil
 }
+
+// RestoreVariant undoes the soft delete of a variant. It returns ErrNotFound if the catalog has
+// no deleted variant with the name.
+func (mm *metadataManager) RestoreVariant(ctx context.Context, catalogID uuid.UUID, name string) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		UPDATE variants
+		SET deleted_at = NULL
+		WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3 AND deleted_at IS NOT NULL
+		RETURNING variant_id;
+	`
+
+	var variantID uuid.UUID
+	err := mm.conn().QueryRowContext(ctx, query, tenantID, catalogID, name).Scan(&variantID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Ctx(ctx).Info().Str("name", name).Str("catalog_id", catalogID.String()).Msg("deleted variant not found")
+			return dberror.ErrNotFound.Msg("deleted variant not found")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("name", name).Msg("failed to restore variant")
+		return dberror.ErrDatabase.Err(err)
+	}
+
+	return nil
+}
+
+// CloneVariant creates variant as a copy of the source variant in the catalog of variant. The
+// resource and skillset directories of the source are copied to the new variant together with
+// its namespaces. Objects are content addressed, so the copied directories refer to the same
+// stored objects as the source and nothing is duplicated until either variant changes them.
+// It returns ErrNotFound if the catalog has no variant with the source name.
+func (mm *metadataManager) CloneVariant(ctx context.Context, source string, variant *models.Variant) (err apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	tx, errdb := mm.beginTx(ctx, &sql.TxOptions{})
+	if errdb != nil {
+		log.Ctx(ctx).Error().Err(errdb).Msg("failed to start transaction")
+		return dberror.ErrDatabase.Err(errdb)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the source variant so that it is not deleted while it is copied
+	query := `
+		SELECT variant_id, resource_directory, skillset_directory
+		FROM variants
+		WHERE tenant_id = $1 AND catalog_id = $2 AND name = $3 AND deleted_at IS NULL
+		FOR SHARE;
+	`
+	var sourceID, sourceResourceDir, sourceSkillsetDir uuid.UUID
+	errdb = tx.QueryRowContext(ctx, query, tenantID, variant.CatalogID, source).Scan(&sourceID, &sourceResourceDir, &sourceSkillsetDir)
+	if errdb != nil {
+		if errdb == sql.ErrNoRows {
+			log.Ctx(ctx).Info().Str("name", source).Str("catalog_id", variant.CatalogID.String()).Msg("source variant not found")
+			return dberror.ErrNotFound.Msg("variant not found")
+		}
+		log.Ctx(ctx).Error().Err(errdb).Str("name", source).Msg("failed to retrieve source variant")
+		return dberror.ErrDatabase.Err(errdb)
+	}
+
+	if err = mm.createVariantWithTransaction(ctx, variant, tx); err != nil {
+		return err
+	}
+
+	directories := []struct {
+		objectType catcommon.CatalogObjectType
+		source     uuid.UUID
+		target     uuid.UUID
+	}{
+		{catcommon.CatalogObjectTypeResource, sourceResourceDir, variant.ResourceDirectoryID},
+		{catcommon.CatalogObjectTypeSkillset, sourceSkillsetDir, variant.SkillsetDirectoryID},
+	}
+	for _, dir := range directories {
+		tableName := getSchemaDirectoryTableName(dir.objectType)
+		if tableName == "" {
+			return dberror.ErrInvalidInput.Msg("invalid catalog object type")
+		}
+		query = `
+			UPDATE ` + tableName + ` AS target
+			SET directory = source.directory
+			FROM ` + tableName + ` AS source
+			WHERE target.tenant_id = $1 AND target.directory_id = $2
+			AND source.tenant_id = $1 AND source.directory_id = $3;
+		`
+		if _, errdb = tx.ExecContext(ctx, query, tenantID, dir.target, dir.source); errdb != nil {
+			log.Ctx(ctx).Error().Err(errdb).Str("directory_id", dir.source.String()).Msg("failed to copy directory")
+			return dberror.ErrDatabase.Err(errdb)
+		}
+	}
+
+	// The default namespace was created with the variant
+	query = `
+		INSERT INTO namespaces (name, variant_id, tenant_id, description, info)
+		SELECT name, $3, tenant_id, description, info
+		FROM namespaces
+		WHERE tenant_id = $1 AND variant_id = $2
+		ON CONFLICT (tenant_id, variant_id, name) DO NOTHING;
+	`
+	if _, errdb = tx.ExecContext(ctx, query, tenantID, sourceID, variant.VariantID); errdb != nil {
+		log.Ctx(ctx).Error().Err(errdb).Str("variant_id", sourceID.String()).Msg("failed to copy namespaces")
+		return dberror.ErrDatabase.Err(errdb)
+	}
+
+	if errdb = tx.Commit(); errdb != nil {
+		log.Ctx(ctx).Error().Err(errdb).Msg("failed to commit transaction")
+		return dberror.ErrDatabase.Err(errdb)
+	}
+
+	return nil
+}
+
+// PurgeDeletedVariants permanently deletes variants, across all tenants, that were soft deleted
+// before the given time, together with everything they contain. It returns the number of
+// variants purged.
+func (mm *metadataManager) PurgeDeletedVariants(ctx context.Context, before time.Time) (int64, apperrors.Error) {
+	query := `
+		DELETE FROM variants
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1;
+	`
+
+	result, err := mm.conn().ExecContext(ctx, query, before)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to purge deleted variants")
+		return 0, dberror.ErrDatabase.Err(err)
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, dberror.ErrDatabase.Err(err)
+	}
+	return purged, nil
+}
@@ -3,6 +3,7 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/rs/zerolog/log"
@@ -20,7 +21,7 @@ func (mm *metadataManager) CreateView(ctx context.Context, view *models.View) (e
 	}
 	view.UpdatedBy = view.CreatedBy
 
-	tx, errStd := mm.conn().BeginTx(ctx, nil)
+	tx, errStd := mm.beginTx(ctx, nil)
 	if errStd != nil {
 		log.Ctx(ctx).Error().Err(errStd).Msg("failed to begin transaction")
 		return dberror.ErrDatabase.Err(errStd)
@@ -47,7 +48,7 @@ func (mm *metadataManager) CreateView(ctx context.Context, view *models.View) (e
 	return nil
 }
 
-func (mm *metadataManager) createViewWithTransaction(ctx context.Context, view *models.View, tx *sql.Tx) apperrors.Error {
+func (mm *metadataManager) createViewWithTransaction(ctx context.Context, view *models.View, tx transaction) apperrors.Error {
 	tenantID := catcommon.GetTenantID(ctx)
 	if tenantID == "" {
 		return dberror.ErrMissingTenantID
@@ -113,7 +114,7 @@ func (mm *metadataManager) GetView(ctx context.Context, viewID uuid.UUID) (*mode
 		FROM 
 			views v
 		JOIN 
-			catalogs c ON v.catalog_id = c.catalog_id AND v.tenant_id = c.tenant_id
+			catalogs c ON v.catalog_id = c.catalog_id AND v.tenant_id = c.tenant_id AND c.deleted_at IS NULL
 		WHERE 
 			v.tenant_id = $1 AND 
 			v.view_id = $2;
@@ -152,7 +153,7 @@ func (mm *metadataManager) GetViewByLabel(ctx context.Context, label string, cat
 		FROM 
 			views v
 		JOIN 
-			catalogs c ON v.catalog_id = c.catalog_id AND v.tenant_id = c.tenant_id
+			catalogs c ON v.catalog_id = c.catalog_id AND v.tenant_id = c.tenant_id AND c.deleted_at IS NULL
 		WHERE 
 			v.tenant_id = $1 AND 
 			v.catalog_id = $2 AND 
@@ -338,3 +339,37 @@ func (mm *metadataManager) ListViewsByCatalog(ctx context.Context, catalogID uui
 
 	return result, nil
 }
+
+// DisableExpiredViews flags every view whose expiresAt is at or before now as disabled and
+// returns the views it disabled. It runs across all tenants, for the background expiry job.
+func (mm *metadataManager) DisableExpiredViews(ctx context.Context, now time.Time) ([]*models.View, apperrors.Error) {
+	query := `
+		UPDATE views
+		SET info = COALESCE(info, '{}'::jsonb) || '{"disabled": true}'::jsonb
+		WHERE info ? 'expiresAt'
+			AND (info->>'expiresAt')::timestamptz <= $1
+			AND COALESCE((info->>'disabled')::boolean, false) = false
+		RETURNING view_id, label, catalog_id, tenant_id
+	`
+
+	rows, err := mm.conn().QueryContext(ctx, query, now)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var result []*models.View
+	for rows.Next() {
+		var view models.View
+		if err := rows.Scan(&view.ViewID, &view.Label, &view.CatalogID, &view.TenantID); err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan view row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		result = append(result, &view)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,191 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+func (mm *metadataManager) CreateViewTemplate(ctx context.Context, template *models.ViewTemplate) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	if template.CreatedBy == "" {
+		return dberror.ErrMissingUserContext.Msg("missing user context")
+	}
+	template.TenantID = tenantID
+	template.UpdatedBy = template.CreatedBy
+
+	query := `
+		INSERT INTO view_templates (label, description, info, spec, catalog_id, tenant_id, created_by, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING template_id, created_at, updated_at`
+
+	errDb := mm.conn().QueryRowContext(ctx, query,
+		template.Label,
+		sql.NullString{String: template.Description, Valid: template.Description != ""},
+		template.Info,
+		template.Spec,
+		template.CatalogID,
+		tenantID,
+		template.CreatedBy,
+		template.UpdatedBy,
+	).Scan(&template.TemplateID, &template.CreatedAt, &template.UpdatedAt)
+	if errDb != nil {
+		if pgErr, ok := errDb.(*pgconn.PgError); ok {
+			switch pgErr.Code {
+			case "23505":
+				return dberror.ErrAlreadyExists.Msg("view template already exists")
+			case "23514":
+				return dberror.ErrInvalidInput.Msg("invalid view template label format")
+			}
+		}
+		log.Ctx(ctx).Error().Err(errDb).Str("label", template.Label).Msg("failed to create view template")
+		return dberror.ErrDatabase.Err(errDb)
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) GetViewTemplateByLabel(ctx context.Context, label string, catalogID uuid.UUID) (*models.ViewTemplate, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT template_id, label, description, info, spec, catalog_id, tenant_id, created_by, updated_by, created_at, updated_at
+		FROM view_templates
+		WHERE tenant_id = $1 AND catalog_id = $2 AND label = $3`
+
+	template, err := scanViewTemplate(mm.conn().QueryRowContext(ctx, query, tenantID, catalogID, label))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, dberror.ErrNotFound.Msg("view template not found")
+		}
+		log.Ctx(ctx).Error().Err(err).Str("label", label).Msg("failed to get view template")
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return template, nil
+}
+
+func (mm *metadataManager) UpdateViewTemplate(ctx context.Context, template *models.ViewTemplate) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+	if template.UpdatedBy == "" {
+		return dberror.ErrMissingUserContext.Msg("missing user context")
+	}
+
+	query := `
+		UPDATE view_templates
+		SET description = $4,
+			info = $5,
+			spec = $6,
+			updated_by = $7,
+			updated_at = NOW()
+		WHERE tenant_id = $1 AND catalog_id = $2 AND label = $3`
+
+	result, err := mm.conn().ExecContext(ctx, query, tenantID, template.CatalogID, template.Label,
+		sql.NullString{String: template.Description, Valid: template.Description != ""},
+		template.Info, template.Spec, template.UpdatedBy)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("label", template.Label).Msg("failed to update view template")
+		return dberror.ErrDatabase.Err(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberror.ErrDatabase.Err(err)
+	}
+	if rowsAffected == 0 {
+		return dberror.ErrNotFound.Msg("view template not found")
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) DeleteViewTemplateByLabel(ctx context.Context, label string, catalogID uuid.UUID) apperrors.Error {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return dberror.ErrMissingTenantID
+	}
+
+	query := `
+		DELETE FROM view_templates
+		WHERE tenant_id = $1 AND catalog_id = $2 AND label = $3`
+
+	result, err := mm.conn().ExecContext(ctx, query, tenantID, catalogID, label)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("label", label).Msg("failed to delete view template")
+		return dberror.ErrDatabase.Err(err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return dberror.ErrDatabase.Err(err)
+	}
+	if rowsAffected == 0 {
+		return dberror.ErrNotFound.Msg("view template not found")
+	}
+
+	return nil
+}
+
+func (mm *metadataManager) ListViewTemplatesByCatalog(ctx context.Context, catalogID uuid.UUID) ([]*models.ViewTemplate, apperrors.Error) {
+	tenantID := catcommon.GetTenantID(ctx)
+	if tenantID == "" {
+		return nil, dberror.ErrMissingTenantID
+	}
+
+	query := `
+		SELECT template_id, label, description, info, spec, catalog_id, tenant_id, created_by, updated_by, created_at, updated_at
+		FROM view_templates
+		WHERE tenant_id = $1 AND catalog_id = $2
+		ORDER BY label ASC`
+
+	rows, err := mm.conn().QueryContext(ctx, query, tenantID, catalogID)
+	if err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+	defer rows.Close()
+
+	var result []*models.ViewTemplate
+	for rows.Next() {
+		template, err := scanViewTemplate(rows)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Msg("failed to scan view template row")
+			return nil, dberror.ErrDatabase.Err(err)
+		}
+		result = append(result, template)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, dberror.ErrDatabase.Err(err)
+	}
+
+	return result, nil
+}
+
+func scanViewTemplate(row rowScanner) (*models.ViewTemplate, error) {
+	var template models.ViewTemplate
+	var description sql.NullString
+	err := row.Scan(&template.TemplateID, &template.Label, &description, &template.Info, &template.Spec,
+		&template.CatalogID, &template.TenantID, &template.CreatedBy, &template.UpdatedBy,
+		&template.CreatedAt, &template.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	template.Description = description.String
+	return &template, nil
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/postgresql"
+)
+
+// errConnUnavailable is returned by every call on the database that DB returns when the
+// context has no usable connection.
+var errConnUnavailable = errors.New("database connection unavailable")
+
+var (
+	unavailableConnOnce sync.Once
+	unavailableConn     *sql.Conn
+)
+
+// unavailableDB returns a database on which every call fails with errConnUnavailable. DB
+// returns it instead of nil so that callers, which chain calls on DB(ctx), get an error
+// rather than a panic.
+func unavailableDB() Database {
+	mm, om, cm := postgresql.NewHatchCatalogDb(unavailableScopedConn{}, nil)
+	return &tansiveCatalogDb{
+		MetadataManager:   mm,
+		ObjectManager:     om,
+		ConnectionManager: cm,
+	}
+}
+
+// unavailableScopedConn is a scoped connection whose statements and scope changes all fail.
+type unavailableScopedConn struct{}
+
+func (unavailableScopedConn) AddScopes(ctx context.Context, scopes map[string]string) error {
+	return errConnUnavailable
+}
+
+func (unavailableScopedConn) DropScopes(ctx context.Context, scopes []string) error {
+	return errConnUnavailable
+}
+
+func (unavailableScopedConn) AddScope(ctx context.Context, scope, value string) error {
+	return errConnUnavailable
+}
+
+func (unavailableScopedConn) DropScope(ctx context.Context, scope string) error {
+	return errConnUnavailable
+}
+
+func (unavailableScopedConn) DropAllScopes(ctx context.Context) error {
+	return errConnUnavailable
+}
+
+func (unavailableScopedConn) AuthorizedScopes() map[string]string {
+	return nil
+}
+
+// Conn returns a connection shared by all unavailable databases. It is never closed, and
+// as its driver fails every statement, it never reaches a server.
+func (unavailableScopedConn) Conn() *sql.Conn {
+	unavailableConnOnce.Do(func() {
+		conn, err := sql.OpenDB(unavailableConnector{}).Conn(context.Background())
+		if err != nil {
+			panic("unable to open unavailable connection: " + err.Error())
+		}
+		unavailableConn = conn
+	})
+	return unavailableConn
+}
+
+func (unavailableScopedConn) Close(ctx context.Context) {}
+
+// unavailableConnector opens driver connections that fail every statement and transaction.
+type unavailableConnector struct{}
+
+func (unavailableConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return unavailableDriverConn{}, nil
+}
+
+func (unavailableConnector) Driver() driver.Driver {
+	return unavailableDriver{}
+}
+
+type unavailableDriver struct{}
+
+func (unavailableDriver) Open(name string) (driver.Conn, error) {
+	return unavailableDriverConn{}, nil
+}
+
+type unavailableDriverConn struct{}
+
+func (unavailableDriverConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errConnUnavailable
+}
+
+func (unavailableDriverConn) Close() error {
+	return nil
+}
+
+func (unavailableDriverConn) Begin() (driver.Tx, error) {
+	return nil, errConnUnavailable
+}
@@ -0,0 +1,68 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+// RecordDecision writes a policy decision to the authorization audit log, so that operators
+// can later find out who was allowed or denied what, and by which rules. The principal,
+// catalog and view are taken from the catalog context. Failing to record a decision is
+// logged but does not change the decision.
+func RecordDecision(ctx context.Context, actions []Action, target string, allowed bool, matchedRules map[Intent][]Rule) {
+	recordDecision(ctx, actions, target, allowed, true, matchedRules)
+}
+
+// EnforceDecision records a decision made by the rules of vd like RecordDecision and returns
+// whether the request may proceed. If vd is in audit mode, a denied request proceeds and the
+// denial is recorded as not enforced.
+func EnforceDecision(ctx context.Context, vd *ViewDefinition, actions []Action, target string, allowed bool, matchedRules map[Intent][]Rule) bool {
+	enforced := allowed || !vd.AuditOnly()
+	recordDecision(ctx, actions, target, allowed, enforced, matchedRules)
+	return allowed || !enforced
+}
+
+func recordDecision(ctx context.Context, actions []Action, target string, allowed, enforced bool, matchedRules map[Intent][]Rule) {
+	if catcommon.GetTenantID(ctx) == "" {
+		return
+	}
+
+	principal := catcommon.GetPrincipal(ctx)
+	if principal == "" {
+		principal = "unknown"
+	}
+	actionNames := make([]string, 0, len(actions))
+	for _, action := range actions {
+		actionNames = append(actionNames, string(action))
+	}
+	var rules []byte
+	if len(matchedRules) > 0 {
+		var err error
+		if rules, err = json.Marshal(matchedRules); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Msg("unable to marshal matched rules")
+		}
+	}
+
+	decision := &models.AuthzDecision{
+		CatalogID:    catcommon.GetCatalogID(ctx),
+		ViewID:       catcommon.GetViewID(ctx),
+		Principal:    principal,
+		Actions:      actionNames,
+		Target:       target,
+		Allowed:      allowed,
+		Enforced:     enforced,
+		MatchedRules: rules,
+	}
+	dbConn := db.DB(ctx)
+	if dbConn == nil {
+		return
+	}
+	if err := dbConn.RecordAuthzDecision(ctx, decision); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("principal", principal).Str("target", target).Msg("unable to record policy decision")
+	}
+}
@@ -0,0 +1,585 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+)
+
+// Attributes are the request attributes that the `when` condition of a rule is evaluated
+// against. Conditions reference them by name, e.g. namespace == "dev" or caller.type == "user".
+type Attributes map[string]any
+
+// RequestAttributes returns the attributes of the current request:
+//   - catalog, variant, namespace: the scope of the request
+//   - time.hour, time.weekday: the UTC hour (0-23) and day of the week (0 is Sunday)
+//   - caller.type, caller.id, caller.principal: who is making the request
+func RequestAttributes(ctx context.Context) Attributes {
+	attrs := Attributes{
+		"catalog":   "",
+		"variant":   "",
+		"namespace": "",
+	}
+	if c := catcommon.GetCatalogContext(ctx); c != nil {
+		attrs["catalog"] = c.Catalog
+		attrs["variant"] = c.Variant
+		attrs["namespace"] = c.Namespace
+	}
+
+	now := time.Now().UTC()
+	attrs["time"] = map[string]any{
+		"hour":    int64(now.Hour()),
+		"weekday": int64(now.Weekday()),
+	}
+
+	caller := map[string]any{
+		"type":      string(catcommon.GetSubjectType(ctx)),
+		"id":        "",
+		"principal": catcommon.GetPrincipal(ctx),
+	}
+	if _, id, ok := strings.Cut(catcommon.GetPrincipal(ctx), "/"); ok {
+		caller["id"] = id
+	}
+	attrs["caller"] = caller
+
+	return attrs
+}
+
+// ruleEnv is what the conditions of rules are evaluated in. Without attributes, a condition
+// cannot be evaluated. A condition equal to assume is taken to hold, which is how a conditional
+// rule in a derived view is covered by the same conditional rule in its parent. When permissive
+// is set, conditions are taken to hold for allow rules and not for deny rules, which yields
+// everything the rules could grant.
+type ruleEnv struct {
+	attrs      Attributes
+	assume     string
+	permissive bool
+}
+
+// applies reports whether the rule's condition holds in env. Rules without a condition always
+// apply. Conditions fail closed: a deny rule whose condition cannot be evaluated applies, and
+// an allow rule whose condition cannot be evaluated does not.
+func (r Rule) applies(env ruleEnv) bool {
+	if r.When == "" || r.When == env.assume {
+		return true
+	}
+	if env.permissive {
+		return r.Intent == IntentAllow
+	}
+	if env.attrs == nil {
+		return r.Intent == IntentDeny
+	}
+	holds, err := evaluateCondition(r.When, env.attrs)
+	if err != nil {
+		return r.Intent == IntentDeny
+	}
+	return holds
+}
+
+// A condition is a boolean expression written in a subset of CEL:
+//   - literals: strings in single or double quotes, integers, true, false, null and lists [a, b]
+//   - attributes and member access: namespace, caller.type
+//   - comparison: ==, !=, <, <=, >, >=, and membership with in
+//   - logic: &&, || and !, grouped with parentheses
+//   - string methods: startsWith, endsWith, contains and matches (RE2)
+type condition interface {
+	eval(attrs Attributes) (any, error)
+}
+
+var conditionCache sync.Map
+
+// parseCondition parses a condition expression. Parsed conditions are cached.
+func parseCondition(expr string) (condition, error) {
+	if c, ok := conditionCache.Load(expr); ok {
+		return c.(condition), nil
+	}
+	tokens, err := tokenizeCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &conditionParser{tokens: tokens}
+	c, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.peek().text, p.peek().pos)
+	}
+	conditionCache.Store(expr, c)
+	return c, nil
+}
+
+// evaluateCondition evaluates a condition expression to a boolean
+func evaluateCondition(expr string, attrs Attributes) (bool, error) {
+	c, err := parseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	v, err := c.eval(attrs)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition does not evaluate to a boolean")
+	}
+	return b, nil
+}
+
+// ValidateCondition reports whether a condition expression is well formed
+func ValidateCondition(expr string) error {
+	_, err := parseCondition(expr)
+	return err
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokInt
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+var conditionOps = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ",", "."}
+
+func tokenizeCondition(expr string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for ; i < len(expr) && expr[i] != c; i++ {
+				if expr[i] == '\\' && i+1 < len(expr) {
+					i++
+				}
+				sb.WriteByte(expr[i])
+			}
+			if i >= len(expr) {
+				return nil, fmt.Errorf("unterminated string at position %d", start)
+			}
+			i++
+			tokens = append(tokens, token{kind: tokString, text: sb.String(), pos: start})
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9':
+			start := i
+			i++
+			for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokInt, text: expr[start:i], pos: start})
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			start := i
+			for i < len(expr) && (expr[i] == '_' || expr[i] >= 'a' && expr[i] <= 'z' || expr[i] >= 'A' && expr[i] <= 'Z' || expr[i] >= '0' && expr[i] <= '9') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: expr[start:i], pos: start})
+		default:
+			matched := false
+			for _, op := range conditionOps {
+				if strings.HasPrefix(expr[i:], op) {
+					tokens = append(tokens, token{kind: tokOp, text: op, pos: i})
+					i += len(op)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", c, i)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(expr)}), nil
+}
+
+type conditionParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *conditionParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *conditionParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *conditionParser) isOp(op string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.text == op
+}
+
+func (p *conditionParser) expectOp(op string) error {
+	if !p.isOp(op) {
+		return fmt.Errorf("expected %q at position %d", op, p.peek().pos)
+	}
+	p.next()
+	return nil
+}
+
+func (p *conditionParser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("||") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (condition, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("&&") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &logicalExpr{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseUnary() (condition, error) {
+	if p.isOp("!") {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand: operand}, nil
+	}
+	return p.parseRelation()
+}
+
+func (p *conditionParser) parseRelation() (condition, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind == tokOp && slices.Contains([]string{"==", "!=", "<", "<=", ">", ">="}, t.text) ||
+		t.kind == tokIdent && t.text == "in" {
+		p.next()
+		right, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		return &compareExpr{op: t.text, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parsePrimary() (condition, error) {
+	var expr condition
+	t := p.next()
+	switch {
+	case t.kind == tokString:
+		expr = &literalExpr{value: t.text}
+	case t.kind == tokInt:
+		n, err := strconv.ParseInt(t.text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q at position %d", t.text, t.pos)
+		}
+		expr = &literalExpr{value: n}
+	case t.kind == tokIdent && t.text == "true":
+		expr = &literalExpr{value: true}
+	case t.kind == tokIdent && t.text == "false":
+		expr = &literalExpr{value: false}
+	case t.kind == tokIdent && t.text == "null":
+		expr = &literalExpr{value: nil}
+	case t.kind == tokIdent && t.text != "in":
+		expr = &attributeExpr{name: t.text}
+	case t.kind == tokOp && t.text == "(":
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		expr = inner
+	case t.kind == tokOp && t.text == "[":
+		list := &listExpr{}
+		for !p.isOp("]") {
+			item, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			list.items = append(list.items, item)
+			if !p.isOp(",") {
+				break
+			}
+			p.next()
+		}
+		if err := p.expectOp("]"); err != nil {
+			return nil, err
+		}
+		expr = list
+	default:
+		if t.kind == tokEOF {
+			return nil, fmt.Errorf("unexpected end of condition")
+		}
+		return nil, fmt.Errorf("unexpected %q at position %d", t.text, t.pos)
+	}
+
+	// Member access and method calls
+	for p.isOp(".") {
+		p.next()
+		member := p.next()
+		if member.kind != tokIdent {
+			return nil, fmt.Errorf("expected a member name at position %d", member.pos)
+		}
+		if !p.isOp("(") {
+			expr = &memberExpr{operand: expr, name: member.text}
+			continue
+		}
+		p.next()
+		call := &methodExpr{receiver: expr, name: member.text}
+		if !slices.Contains([]string{"startsWith", "endsWith", "contains", "matches"}, call.name) {
+			return nil, fmt.Errorf("unknown function %q at position %d", call.name, member.pos)
+		}
+		arg, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		call.arg = arg
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		expr = call
+	}
+	return expr, nil
+}
+
+type literalExpr struct {
+	value any
+}
+
+func (e *literalExpr) eval(Attributes) (any, error) {
+	return e.value, nil
+}
+
+type attributeExpr struct {
+	name string
+}
+
+func (e *attributeExpr) eval(attrs Attributes) (any, error) {
+	v, ok := attrs[e.name]
+	if !ok {
+		return nil, fmt.Errorf("unknown attribute %q", e.name)
+	}
+	return v, nil
+}
+
+type memberExpr struct {
+	operand condition
+	name    string
+}
+
+func (e *memberExpr) eval(attrs Attributes) (any, error) {
+	v, err := e.operand.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("no member %q", e.name)
+	}
+	member, ok := m[e.name]
+	if !ok {
+		return nil, fmt.Errorf("no member %q", e.name)
+	}
+	return member, nil
+}
+
+type listExpr struct {
+	items []condition
+}
+
+func (e *listExpr) eval(attrs Attributes) (any, error) {
+	list := make([]any, 0, len(e.items))
+	for _, item := range e.items {
+		v, err := item.eval(attrs)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, v)
+	}
+	return list, nil
+}
+
+type notExpr struct {
+	operand condition
+}
+
+func (e *notExpr) eval(attrs Attributes) (any, error) {
+	v, err := e.operand.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operand of ! is not a boolean")
+	}
+	return !b, nil
+}
+
+type logicalExpr struct {
+	op          string
+	left, right condition
+}
+
+func (e *logicalExpr) eval(attrs Attributes) (any, error) {
+	l, err := e.left.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+	lb, ok := l.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operand of %s is not a boolean", e.op)
+	}
+	if e.op == "&&" && !lb || e.op == "||" && lb {
+		return lb, nil
+	}
+	r, err := e.right.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+	rb, ok := r.(bool)
+	if !ok {
+		return nil, fmt.Errorf("operand of %s is not a boolean", e.op)
+	}
+	return rb, nil
+}
+
+type compareExpr struct {
+	op          string
+	left, right condition
+}
+
+func (e *compareExpr) eval(attrs Attributes) (any, error) {
+	l, err := e.left.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+	r, err := e.right.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch e.op {
+	case "==":
+		return l == r, nil
+	case "!=":
+		return l != r, nil
+	case "in":
+		list, ok := r.([]any)
+		if !ok {
+			return nil, fmt.Errorf("right operand of in is not a list")
+		}
+		return slices.Contains(list, l), nil
+	}
+
+	switch lv := l.(type) {
+	case int64:
+		rv, ok := r.(int64)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare integer with %T", r)
+		}
+		return compareOrdered(e.op, lv, rv), nil
+	case string:
+		rv, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare string with %T", r)
+		}
+		return compareOrdered(e.op, lv, rv), nil
+	}
+	return nil, fmt.Errorf("operands of %s must be integers or strings", e.op)
+}
+
+func compareOrdered[T int64 | string](op string, l, r T) bool {
+	switch op {
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case ">":
+		return l > r
+	default:
+		return l >= r
+	}
+}
+
+type methodExpr struct {
+	receiver condition
+	name     string
+	arg      condition
+}
+
+func (e *methodExpr) eval(attrs Attributes) (any, error) {
+	recv, err := e.receiver.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+	arg, err := e.arg.eval(attrs)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := recv.(string)
+	if !ok {
+		return nil, fmt.Errorf("%s can only be called on a string", e.name)
+	}
+	a, ok := arg.(string)
+	if !ok {
+		return nil, fmt.Errorf("argument of %s must be a string", e.name)
+	}
+
+	switch e.name {
+	case "startsWith":
+		return strings.HasPrefix(s, a), nil
+	case "endsWith":
+		return strings.HasSuffix(s, a), nil
+	case "contains":
+		return strings.Contains(s, a), nil
+	default:
+		re, err := regexp.Compile(a)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %v", err)
+		}
+		return re.MatchString(s), nil
+	}
+}
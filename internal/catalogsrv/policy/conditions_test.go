@@ -0,0 +1,173 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	attrs := Attributes{
+		"catalog":   "test-catalog",
+		"variant":   "prod",
+		"namespace": "dev",
+		"time": map[string]any{
+			"hour":    int64(10),
+			"weekday": int64(3),
+		},
+		"caller": map[string]any{
+			"type":      "user",
+			"id":        "alice",
+			"principal": "user/alice",
+		},
+	}
+
+	tests := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{expr: `namespace == "dev"`, want: true},
+		{expr: `namespace != 'dev'`, want: false},
+		{expr: `caller.type == "user" && caller.id == "alice"`, want: true},
+		{expr: `caller.type == "session" || variant == "prod"`, want: true},
+		{expr: `!(namespace == "dev")`, want: false},
+		{expr: `time.hour >= 9 && time.hour < 17`, want: true},
+		{expr: `time.weekday in [0, 6]`, want: false},
+		{expr: `namespace in ["dev", "test"]`, want: true},
+		{expr: `caller.principal.startsWith("user/")`, want: true},
+		{expr: `catalog.endsWith("-catalog")`, want: true},
+		{expr: `catalog.contains("test")`, want: true},
+		{expr: `caller.id.matches("^a[a-z]+$")`, want: true},
+		{expr: `true`, want: true},
+		{expr: `unknown == "x"`, wantErr: true},
+		{expr: `caller.missing == "x"`, wantErr: true},
+		{expr: `namespace`, wantErr: true},
+		{expr: `time.hour < "ten"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evaluateCondition(tt.expr, attrs)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateCondition(t *testing.T) {
+	for _, expr := range []string{
+		`namespace == "dev"`,
+		`caller.type in ["user", "session"] && !(time.hour < 9)`,
+		`catalog.startsWith('prod-')`,
+	} {
+		assert.NoError(t, ValidateCondition(expr), expr)
+	}
+	for _, expr := range []string{
+		``,
+		`namespace ==`,
+		`namespace == "dev`,
+		`(namespace == "dev"`,
+		`namespace == "dev" &`,
+		`catalog.size()`,
+		`namespace == "dev" "prod"`,
+	} {
+		assert.Error(t, ValidateCondition(expr), expr)
+	}
+}
+
+func TestConditionalRules(t *testing.T) {
+	rules := Rules{
+		{
+			Intent:  IntentAllow,
+			Actions: []Action{ActionResourceRead},
+			Targets: []TargetResource{"res://catalogs/c/resources/*"},
+			When:    `namespace == "dev"`,
+		},
+		{
+			Intent:  IntentAllow,
+			Actions: []Action{ActionResourceEdit},
+			Targets: []TargetResource{"res://catalogs/c/resources/*"},
+		},
+		{
+			Intent:  IntentDeny,
+			Actions: []Action{ActionResourceEdit},
+			Targets: []TargetResource{"res://catalogs/c/resources/*"},
+			When:    `caller.type == "session"`,
+		},
+	}
+	target := TargetResource("res://catalogs/c/resources/config")
+	dev := Attributes{"namespace": "dev", "caller": map[string]any{"type": "user"}}
+	prod := Attributes{"namespace": "prod", "caller": map[string]any{"type": "session"}}
+
+	allowed, matched := rules.IsActionAllowedWithAttributes(ActionResourceRead, target, dev)
+	assert.True(t, allowed)
+	assert.Len(t, matched[IntentAllow], 1)
+
+	allowed, _ = rules.IsActionAllowedWithAttributes(ActionResourceRead, target, prod)
+	assert.False(t, allowed)
+
+	allowed, _ = rules.IsActionAllowedWithAttributes(ActionResourceEdit, target, dev)
+	assert.True(t, allowed)
+
+	allowed, matched = rules.IsActionAllowedWithAttributes(ActionResourceEdit, target, prod)
+	assert.False(t, allowed)
+	assert.Len(t, matched[IntentDeny], 1)
+
+	// Without attributes conditional allows do not apply and conditional denies do
+	allowed, _ = rules.IsActionAllowedOnResource(ActionResourceRead, target)
+	assert.False(t, allowed)
+	allowed, _ = rules.IsActionAllowedOnResource(ActionResourceEdit, target)
+	assert.False(t, allowed)
+
+	// A condition that fails to evaluate does not allow
+	allowed, _ = rules.IsActionAllowedWithAttributes(ActionResourceRead, target, Attributes{})
+	assert.False(t, allowed)
+}
+
+func TestConditionalRulesSubset(t *testing.T) {
+	parent := Rules{
+		{
+			Intent:  IntentAllow,
+			Actions: []Action{ActionResourceRead},
+			Targets: []TargetResource{"res://catalogs/c/resources/*"},
+			When:    `namespace == "dev"`,
+		},
+	}
+
+	sameCondition := Rules{
+		{
+			Intent:  IntentAllow,
+			Actions: []Action{ActionResourceRead},
+			Targets: []TargetResource{"res://catalogs/c/resources/config"},
+			When:    `namespace == "dev"`,
+		},
+	}
+	assert.True(t, sameCondition.IsSubsetOf(parent))
+
+	unconditional := Rules{
+		{
+			Intent:  IntentAllow,
+			Actions: []Action{ActionResourceRead},
+			Targets: []TargetResource{"res://catalogs/c/resources/config"},
+		},
+	}
+	assert.False(t, unconditional.IsSubsetOf(parent))
+
+	otherCondition := Rules{
+		{
+			Intent:  IntentAllow,
+			Actions: []Action{ActionResourceRead},
+			Targets: []TargetResource{"res://catalogs/c/resources/config"},
+			When:    `namespace == "prod"`,
+		},
+	}
+	assert.False(t, otherCondition.IsSubsetOf(parent))
+
+	// A conditional child is covered by an unconditional parent
+	assert.True(t, sameCondition.IsSubsetOf(unconditional))
+}
@@ -17,7 +17,7 @@ import (
 // 2. Determines the target scope (catalog, variant, namespace)
 // 3. Resolves the target resource from the request URL path
 // 4. Evaluates each allowed action against the policy rules
-// 5. Logs the policy decision with detailed information
+// 5. Logs the policy decision with detailed information and records it in the audit log
 //
 // Parameters:
 //   - handler: ResponseHandlerParam containing the allowed actions and the actual request handler
@@ -28,7 +28,9 @@ import (
 // Note: The middleware implements a first-match policy where access is granted if any
 // of the allowed actions are permitted. It logs detailed policy decisions including
 // matched allow and deny rules for auditing purposes.
-// Returns ErrDisallowedByPolicy if no allowed actions are permitted by the policy.
+// Returns ErrDisallowedByPolicy if no allowed actions are permitted by the policy, unless the
+// view is in audit mode, in which case the denial is only logged and recorded. If the
+// request sets the ExplainHeader, the 403 response also explains how the rules decided it.
 func EnforceViewPolicyMiddleware(handler ResponseHandlerParam) httpx.RequestHandler {
 	return func(r *http.Request) (*httpx.Response, error) {
 		ctx := r.Context()
@@ -69,8 +71,9 @@ func EnforceViewPolicyMiddleware(handler ResponseHandlerParam) httpx.RequestHand
 			IntentAllow: {},
 			IntentDeny:  {},
 		}
+		attrs := RequestAttributes(ctx)
 		for _, action := range handler.AllowedActions {
-			isAllowed, ruleSet := authorizedViewDef.Rules.IsActionAllowedOnResource(action, targetResource)
+			isAllowed, ruleSet := authorizedViewDef.Rules.IsActionAllowedWithAttributes(action, targetResource, attrs)
 
 			// Track rules
 			for intent, rules := range ruleSet {
@@ -93,11 +96,25 @@ func EnforceViewPolicyMiddleware(handler ResponseHandlerParam) httpx.RequestHand
 			Interface("matched_deny_rules", matchedRules[IntentDeny]).
 			Logger()
 
-		if !allowed {
+		if !EnforceDecision(ctx, authorizedViewDef, handler.AllowedActions, string(targetResource), allowed, matchedRules) {
 			logger.Warn().Msg("access denied")
+			if wantsExplanation(r) {
+				return &httpx.Response{
+					StatusCode: ErrDisallowedByPolicy.StatusCode(),
+					Response: &explainedErrorRsp{
+						Result:      httpx.Failure,
+						Error:       ErrDisallowedByPolicy.ErrorAll(),
+						Explanation: explainDecision(authorizedViewDef.Rules, handler.AllowedActions, targetResource, allowed, matchedRules),
+					},
+				}, nil
+			}
 			return nil, ErrDisallowedByPolicy
 		}
-		logger.Info().Msg("access allowed")
+		if !allowed {
+			logger.Warn().Msg("access denied but not enforced: view is in audit mode")
+		} else {
+			logger.Info().Msg("access allowed")
+		}
 
 		// If we get here, we are good to go, so call the handler
 		return handler.Handler(r)
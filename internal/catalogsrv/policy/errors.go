@@ -13,11 +13,12 @@ var (
 
 // Not found errors
 var (
-	ErrCatalogNotFound   apperrors.Error = ErrViewError.New("catalog not found").SetExpandError(true).SetStatusCode(http.StatusBadRequest)
-	ErrObjectNotFound    apperrors.Error = ErrViewError.New("object not found").SetStatusCode(http.StatusBadRequest)
-	ErrVariantNotFound   apperrors.Error = ErrViewError.New("variant not found").SetStatusCode(http.StatusBadRequest)
-	ErrNamespaceNotFound apperrors.Error = ErrViewError.New("namespace not found").SetStatusCode(http.StatusBadRequest)
-	ErrViewNotFound      apperrors.Error = ErrViewError.New("view not found").SetStatusCode(http.StatusBadRequest)
+	ErrCatalogNotFound      apperrors.Error = ErrViewError.New("catalog not found").SetExpandError(true).SetStatusCode(http.StatusBadRequest)
+	ErrObjectNotFound       apperrors.Error = ErrViewError.New("object not found").SetStatusCode(http.StatusBadRequest)
+	ErrVariantNotFound      apperrors.Error = ErrViewError.New("variant not found").SetStatusCode(http.StatusBadRequest)
+	ErrNamespaceNotFound    apperrors.Error = ErrViewError.New("namespace not found").SetStatusCode(http.StatusBadRequest)
+	ErrViewNotFound         apperrors.Error = ErrViewError.New("view not found").SetStatusCode(http.StatusBadRequest)
+	ErrViewTemplateNotFound apperrors.Error = ErrViewError.New("view template not found").SetStatusCode(http.StatusNotFound)
 )
 
 // Operation errors
@@ -29,6 +30,7 @@ var (
 // Conflict errors
 var (
 	ErrAlreadyExists apperrors.Error = ErrViewError.New("object already exists").SetStatusCode(http.StatusConflict)
+	ErrViewInUse     apperrors.Error = ErrViewError.New("view is in use").SetStatusCode(http.StatusConflict)
 )
 
 // Validation errors
@@ -50,6 +52,7 @@ var (
 	ErrAuthError                apperrors.Error = ErrViewError.New("authorization error").SetStatusCode(http.StatusForbidden)
 	ErrUnauthorizedToCreateView apperrors.Error = ErrAuthError.New("unauthorized to create view").SetStatusCode(http.StatusForbidden)
 	ErrDisallowedByPolicy       apperrors.Error = ErrAuthError.New("not allowed by policy").SetStatusCode(http.StatusForbidden)
+	ErrViewNotActive            apperrors.Error = ErrAuthError.New("view is not active").SetStatusCode(http.StatusForbidden)
 )
 
 var (
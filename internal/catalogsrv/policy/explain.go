@@ -0,0 +1,64 @@
+package policy
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ExplainHeader is the request header that opts in to an explanation of a denied request.
+// When it is set to true, a request denied by policy returns the evaluated rules along
+// with the 403 error.
+const ExplainHeader = "X-Tansive-Explain"
+
+// Explanation describes how a view's rules decided an access decision.
+type Explanation struct {
+	Actions []Action       `json:"actions"`
+	Target  TargetResource `json:"target"`
+	Allowed bool           `json:"allowed"`
+	Reason  string         `json:"reason"`
+	// EvaluatedRules are the view's rules after canonicalization, as they were evaluated
+	EvaluatedRules Rules `json:"evaluatedRules"`
+	// MatchedAllowRules are the rules that allowed one of the actions on the target
+	MatchedAllowRules Rules `json:"matchedAllowRules"`
+	// MatchedDenyRules are the rules that denied one of the actions on the target
+	MatchedDenyRules Rules `json:"matchedDenyRules"`
+	// DenyOverridesAllow is true when a deny rule overrode a matching allow rule
+	DenyOverridesAllow bool `json:"denyOverridesAllow"`
+}
+
+type explainedErrorRsp struct {
+	Result      int          `json:"result"`
+	Error       string       `json:"error"`
+	Explanation *Explanation `json:"explanation"`
+}
+
+// explainDecision builds the explanation of a decision from the canonicalized rules that
+// were evaluated and the rules that matched.
+func explainDecision(rules Rules, actions []Action, target TargetResource, allowed bool, matchedRules map[Intent][]Rule) *Explanation {
+	e := &Explanation{
+		Actions:           actions,
+		Target:            target,
+		Allowed:           allowed,
+		EvaluatedRules:    append(Rules{}, rules...),
+		MatchedAllowRules: append(Rules{}, matchedRules[IntentAllow]...),
+		MatchedDenyRules:  append(Rules{}, matchedRules[IntentDeny]...),
+	}
+	switch {
+	case allowed:
+		e.Reason = "allowed by a matching allow rule"
+	case len(e.MatchedDenyRules) > 0 && len(e.MatchedAllowRules) > 0:
+		e.DenyOverridesAllow = true
+		e.Reason = "a matching deny rule overrides a matching allow rule"
+	case len(e.MatchedDenyRules) > 0:
+		e.Reason = "denied by a matching deny rule"
+	default:
+		e.Reason = "no rule allows the action on the target"
+	}
+	return e
+}
+
+// wantsExplanation reports whether the request opted in to explanations
+func wantsExplanation(r *http.Request) bool {
+	explain, _ := strconv.ParseBool(r.Header.Get(ExplainHeader))
+	return explain
+}
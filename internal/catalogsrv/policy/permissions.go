@@ -0,0 +1,142 @@
+package policy
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+)
+
+// CatalogObject is a concrete object of a catalog, identified by its canonical resource URI,
+// e.g. res://catalogs/my-catalog/variants/prod/resources/config. Kind is the kind name of the
+// object, such as catcommon.KindNameResources.
+type CatalogObject struct {
+	Kind     string
+	Resource TargetResource
+}
+
+// EffectivePermission is an action that a view grants on a concrete object. Conditional
+// permissions are only granted when the conditions of the rules that grant them hold.
+type EffectivePermission struct {
+	Action      Action         `json:"action"`
+	Resource    TargetResource `json:"resource"`
+	Conditional bool           `json:"conditional,omitempty"`
+}
+
+// objectActions are the actions that apply to a single object of each kind. Actions on
+// collections, such as creating or listing resources, are not part of the report.
+var objectActions = map[string][]Action{
+	catcommon.KindNameCatalogs:      {ActionCatalogAdmin, ActionCatalogList, ActionCatalogCreateView},
+	catcommon.KindNameVariants:      {ActionVariantAdmin, ActionVariantClone, ActionVariantList},
+	catcommon.KindNameNamespaces:    {ActionNamespaceAdmin, ActionNamespaceList},
+	catcommon.KindNameViews:         {ActionCatalogAdoptView, ActionViewAdmin},
+	catcommon.KindNameViewTemplates: {ActionViewAdmin},
+	catcommon.KindNameResources:     {ActionResourceRead, ActionResourceEdit, ActionResourceDelete, ActionResourceGet, ActionResourcePut, ActionResourceReveal},
+	catcommon.KindNameSkillsets:     {ActionSkillSetAdmin, ActionSkillSetRead, ActionSkillSetEdit, ActionSkillSetDelete, ActionSkillSetUse, ActionSkillSetApprove},
+}
+
+// objectReadActions are the actions that allow reading a single object of each kind, as
+// required by the routes that get it.
+var objectReadActions = map[string][]Action{
+	catcommon.KindNameCatalogs:   {ActionCatalogList},
+	catcommon.KindNameVariants:   {ActionVariantList},
+	catcommon.KindNameNamespaces: {ActionNamespaceList},
+	catcommon.KindNameViews:      {ActionCatalogList},
+	catcommon.KindNameResources:  {ActionResourceRead, ActionResourceEdit, ActionResourceGet, ActionResourcePut},
+	catcommon.KindNameSkillsets:  {ActionSkillSetRead, ActionSkillSetUse},
+}
+
+// objectWriteActions are the actions that allow writing a single object of each kind, as
+// required by the routes that update it.
+var objectWriteActions = map[string][]Action{
+	catcommon.KindNameCatalogs:          {ActionCatalogAdmin},
+	catcommon.KindNameVariants:          {ActionVariantAdmin},
+	catcommon.KindNameNamespaces:        {ActionNamespaceAdmin},
+	catcommon.KindNameViews:             {ActionViewAdmin},
+	catcommon.KindNameViewTemplates:     {ActionViewAdmin},
+	catcommon.KindNameAdmissionWebhooks: {ActionCatalogAdmin},
+	catcommon.KindNameResources:         {ActionResourceEdit},
+	catcommon.KindNameSkillsets:         {ActionSkillSetAdmin},
+}
+
+// CanWriteObject reports whether the caller may write obj, checking the actions that write
+// it against the view in the context. Unlike CanReadObject, the decision is recorded and
+// views in audit mode are only allowed through EnforceDecision. A user without a view may
+// write objects only in single user mode, where the user owns every catalog.
+func CanWriteObject(ctx context.Context, obj CatalogObject) bool {
+	actions := objectWriteActions[obj.Kind]
+	vd := canonicalizeViewDefinition(GetViewDefinition(ctx))
+	if vd == nil {
+		allowed := catcommon.GetSubjectType(ctx) == catcommon.SubjectTypeUser && config.Config().SingleUserMode
+		RecordDecision(ctx, actions, string(obj.Resource), allowed, nil)
+		return allowed
+	}
+	var matchedRules map[Intent][]Rule
+	allowed := false
+	for _, action := range actions {
+		if allowed, matchedRules = vd.Rules.IsActionAllowedWithAttributes(action, obj.Resource, RequestAttributes(ctx)); allowed {
+			break
+		}
+	}
+	return EnforceDecision(ctx, vd, actions, string(obj.Resource), allowed, matchedRules)
+}
+
+// CanReadObject reports whether the view in the context grants one of the actions that read
+// obj, evaluating the conditions of its rules with attrs. Views in audit mode may read any
+// object. The decision is not recorded, so that filtering a stream of objects does not flood
+// the audit log.
+func CanReadObject(ctx context.Context, obj CatalogObject, attrs Attributes) bool {
+	vd, err := ResolveAuthorizedViewDef(ctx)
+	if err != nil {
+		return false
+	}
+	if vd.AuditOnly() {
+		return true
+	}
+	for _, action := range objectReadActions[obj.Kind] {
+		if allowed, _ := vd.Rules.IsActionAllowedWithAttributes(action, obj.Resource, attrs); allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectivePermissions expands the rules of a view against the given objects and returns the
+// concrete (action, resource) pairs the view grants, in the order of the objects. Actions
+// defined by skillsets, i.e. those named in the rules outside the system namespace, are
+// evaluated on skillsets.
+func EffectivePermissions(vd *ViewDefinition, objects []CatalogObject) []EffectivePermission {
+	vd = canonicalizeViewDefinition(vd)
+	if vd == nil {
+		return nil
+	}
+
+	var customActions []Action
+	for _, rule := range vd.Rules {
+		for _, action := range rule.Actions {
+			if !strings.HasPrefix(string(action), "system.") && !slices.Contains(customActions, action) {
+				customActions = append(customActions, action)
+			}
+		}
+	}
+
+	permissions := []EffectivePermission{}
+	for _, obj := range objects {
+		actions := objectActions[obj.Kind]
+		if obj.Kind == catcommon.KindNameSkillsets {
+			actions = append(slices.Clone(actions), customActions...)
+		}
+		for _, action := range actions {
+			if allowed, _ := vd.Rules.IsActionAllowedOnResource(action, obj.Resource); allowed {
+				permissions = append(permissions, EffectivePermission{Action: action, Resource: obj.Resource})
+				continue
+			}
+			if allowed, _ := vd.Rules.isActionAllowed(action, obj.Resource, ruleEnv{permissive: true}); allowed {
+				permissions = append(permissions, EffectivePermission{Action: action, Resource: obj.Resource, Conditional: true})
+			}
+		}
+	}
+	return permissions
+}
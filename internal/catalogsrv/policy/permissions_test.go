@@ -0,0 +1,144 @@
+package policy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+)
+
+func TestEffectivePermissions(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{Catalog: "my-catalog", Variant: "prod"},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionResourceGet, ActionResourcePut},
+				Targets: []TargetResource{"res://resources/*"},
+			},
+			{
+				Intent:  IntentDeny,
+				Actions: []Action{ActionResourcePut},
+				Targets: []TargetResource{"res://resources/secrets/*"},
+			},
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionResourceEdit},
+				Targets: []TargetResource{"res://resources/config"},
+				When:    `namespace == "dev"`,
+			},
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionSkillSetUse, "kubernetes.pods.list"},
+				Targets: []TargetResource{"res://skillsets/*"},
+			},
+		},
+	}
+
+	objects := []CatalogObject{
+		{Kind: catcommon.KindNameCatalogs, Resource: "res://catalogs/my-catalog"},
+		{Kind: catcommon.KindNameVariants, Resource: "res://catalogs/my-catalog/variants/prod"},
+		{Kind: catcommon.KindNameResources, Resource: "res://catalogs/my-catalog/variants/prod/resources/config"},
+		{Kind: catcommon.KindNameResources, Resource: "res://catalogs/my-catalog/variants/prod/resources/secrets/db"},
+		{Kind: catcommon.KindNameResources, Resource: "res://catalogs/my-catalog/variants/dev/resources/config"},
+		{Kind: catcommon.KindNameSkillsets, Resource: "res://catalogs/my-catalog/variants/prod/skillsets/k8s"},
+	}
+
+	assert.Equal(t, []EffectivePermission{
+		{Action: ActionResourceEdit, Resource: "res://catalogs/my-catalog/variants/prod/resources/config", Conditional: true},
+		{Action: ActionResourceGet, Resource: "res://catalogs/my-catalog/variants/prod/resources/config"},
+		{Action: ActionResourcePut, Resource: "res://catalogs/my-catalog/variants/prod/resources/config"},
+		{Action: ActionResourceGet, Resource: "res://catalogs/my-catalog/variants/prod/resources/secrets/db"},
+		{Action: ActionSkillSetUse, Resource: "res://catalogs/my-catalog/variants/prod/skillsets/k8s"},
+		{Action: "kubernetes.pods.list", Resource: "res://catalogs/my-catalog/variants/prod/skillsets/k8s"},
+	}, EffectivePermissions(vd, objects))
+
+	assert.Nil(t, EffectivePermissions(nil, objects))
+	assert.Empty(t, EffectivePermissions(vd, nil))
+}
+
+func TestCanReadObject(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{Catalog: "my-catalog", Variant: "prod"},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionResourceGet},
+				Targets: []TargetResource{"res://resources/*"},
+			},
+			{
+				Intent:  IntentDeny,
+				Actions: []Action{ActionResourceGet},
+				Targets: []TargetResource{"res://resources/secrets/*"},
+			},
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionSkillSetUse},
+				Targets: []TargetResource{"res://skillsets/*"},
+				When:    `namespace == "dev"`,
+			},
+		},
+	}
+	ctx := catcommon.WithCatalogContext(context.Background(), &catcommon.CatalogContext{Catalog: "my-catalog", Variant: "prod"})
+	ctx = WithViewDefinition(ctx, vd)
+	attrs := RequestAttributes(ctx)
+
+	assert.True(t, CanReadObject(ctx, CatalogObject{Kind: catcommon.KindNameResources, Resource: "res://catalogs/my-catalog/variants/prod/resources/config"}, attrs))
+	assert.False(t, CanReadObject(ctx, CatalogObject{Kind: catcommon.KindNameResources, Resource: "res://catalogs/my-catalog/variants/prod/resources/secrets/db"}, attrs))
+	assert.False(t, CanReadObject(ctx, CatalogObject{Kind: catcommon.KindNameResources, Resource: "res://catalogs/my-catalog/variants/dev/resources/config"}, attrs))
+	assert.False(t, CanReadObject(ctx, CatalogObject{Kind: catcommon.KindNameViews, Resource: "res://catalogs/my-catalog/views/admin"}, attrs))
+
+	// Conditions are evaluated with the given attributes
+	skillset := CatalogObject{Kind: catcommon.KindNameSkillsets, Resource: "res://catalogs/my-catalog/variants/prod/skillsets/k8s"}
+	assert.False(t, CanReadObject(ctx, skillset, attrs))
+	attrs["namespace"] = "dev"
+	assert.True(t, CanReadObject(ctx, skillset, attrs))
+
+	// Views in audit mode read everything
+	vd.Mode = EnforcementModeAudit
+	assert.True(t, CanReadObject(ctx, CatalogObject{Kind: catcommon.KindNameViews, Resource: "res://catalogs/my-catalog/views/admin"}, attrs))
+}
+
+func TestCanWriteObject(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{Catalog: "my-catalog"},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionCatalogAdmin},
+			},
+			{
+				Intent:  IntentDeny,
+				Actions: []Action{ActionResourceEdit},
+				Targets: []TargetResource{"res://variants/prod/resources/secrets/*"},
+			},
+		},
+	}
+	config.TestInit()
+	ctx := catcommon.WithCatalogContext(context.Background(), &catcommon.CatalogContext{Catalog: "my-catalog"})
+	ctx = WithViewDefinition(ctx, vd)
+
+	assert.True(t, CanWriteObject(ctx, CatalogObject{Kind: catcommon.KindNameCatalogs, Resource: "res://catalogs/my-catalog"}))
+	assert.True(t, CanWriteObject(ctx, CatalogObject{Kind: catcommon.KindNameResources, Resource: "res://catalogs/my-catalog/variants/prod/resources/config"}))
+	assert.False(t, CanWriteObject(ctx, CatalogObject{Kind: catcommon.KindNameResources, Resource: "res://catalogs/my-catalog/variants/prod/resources/secrets/db"}))
+	assert.False(t, CanWriteObject(ctx, CatalogObject{Kind: catcommon.KindNameCatalogs, Resource: "res://catalogs/other-catalog"}))
+
+	// Views in audit mode are not enforced
+	vd.Mode = EnforcementModeAudit
+	assert.True(t, CanWriteObject(ctx, CatalogObject{Kind: catcommon.KindNameCatalogs, Resource: "res://catalogs/other-catalog"}))
+
+	// Without a view, only the user of single user mode may write
+	ctx = catcommon.WithCatalogContext(context.Background(), &catcommon.CatalogContext{
+		Catalog:     "my-catalog",
+		UserContext: &catcommon.UserContext{UserID: "user-1"},
+		Subject:     catcommon.SubjectTypeUser,
+	})
+	singleUserMode := config.Config().SingleUserMode
+	t.Cleanup(func() { config.Config().SingleUserMode = singleUserMode })
+	config.Config().SingleUserMode = false
+	assert.False(t, CanWriteObject(ctx, CatalogObject{Kind: catcommon.KindNameCatalogs, Resource: "res://catalogs/my-catalog"}))
+	config.Config().SingleUserMode = true
+	assert.True(t, CanWriteObject(ctx, CatalogObject{Kind: catcommon.KindNameCatalogs, Resource: "res://catalogs/my-catalog"}))
+}
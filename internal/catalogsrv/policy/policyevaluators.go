@@ -5,10 +5,13 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
 )
 
 // IsActionAllowedOnResource evaluates whether a given action is permitted on a specific resource based on the rule set.
@@ -23,22 +26,33 @@ import (
 //   - map[Intent][]Rule: A map containing matched rules grouped by their intent (allow/deny)
 //
 // Note: This function first checks for admin matches, then evaluates regular rules.
-// Deny rules take precedence over allow rules in case of conflicts.
+// Deny rules take precedence over allow rules in case of conflicts. Without request attributes,
+// conditional allow rules do not apply and conditional deny rules always do.
 func (ruleSet Rules) IsActionAllowedOnResource(action Action, target TargetResource) (bool, map[Intent][]Rule) {
+	return ruleSet.isActionAllowed(action, target, ruleEnv{})
+}
+
+// IsActionAllowedWithAttributes is IsActionAllowedOnResource for a request with the given
+// attributes, against which the conditions of the rules are evaluated.
+func (ruleSet Rules) IsActionAllowedWithAttributes(action Action, target TargetResource, attrs Attributes) (bool, map[Intent][]Rule) {
+	return ruleSet.isActionAllowed(action, target, ruleEnv{attrs: attrs})
+}
+
+func (ruleSet Rules) isActionAllowed(action Action, target TargetResource, env ruleEnv) (bool, map[Intent][]Rule) {
 	matchedRulesAllow := []Rule{}
 	matchedRulesDeny := []Rule{}
 
 	allowMatch := action == ActionAllow
 	var matchedRule Rule
 	// check if there is an admin match
-	adminMatch, matchedRule := ruleSet.matchesAdmin(string(target))
+	adminMatch, matchedRule := ruleSet.matchesAdmin(string(target), env)
 	if adminMatch {
 		allowMatch = true
 		matchedRulesAllow = append(matchedRulesAllow, matchedRule)
 	}
 	// check if there is a match for the action
 	for _, rule := range ruleSet {
-		if slices.Contains(rule.Actions, action) {
+		if slices.Contains(rule.Actions, action) && rule.applies(env) {
 			for _, res := range rule.Targets {
 				switch rule.Intent {
 				case IntentAllow:
@@ -47,8 +61,8 @@ func (ruleSet Rules) IsActionAllowedOnResource(action Action, target TargetResou
 						matchedRulesAllow = append(matchedRulesAllow, rule)
 					}
 				case IntentDeny:
-					if res.matches(string(target)) || // target is allowed by the rule
-						target.matches(string(res)) { // target is more permissive than the rule when we evaluate rule subsets
+					// when we evaluate rule subsets, target may be a pattern that is only partly denied
+					if res.overlaps(string(target)) {
 						allowMatch = false
 						matchedRulesDeny = append(matchedRulesDeny, rule)
 					}
@@ -74,20 +88,38 @@ func (ruleSet Rules) IsActionAllowedOnResource(action Action, target TargetResou
 //
 // Note: This function only considers allow rules in the comparison.
 // All actions and targets in this set must be explicitly allowed by the other set.
+// A conditional rule is only covered by unconditional rules or rules with the same condition.
 func (ruleSet Rules) IsSubsetOf(other Rules) bool {
-	for _, rule := range ruleSet {
+	return len(ruleSet.subsetViolations(other)) == 0
+}
+
+// RuleViolation is an action on a target that a rule of a derived view allows but the parent
+// view does not. Rule is the index of the rule in the derived view.
+type RuleViolation struct {
+	Rule   int            `json:"rule"`
+	Action Action         `json:"action"`
+	Target TargetResource `json:"target"`
+}
+
+// subsetViolations returns every action and target allowed by this RuleSet that the other
+// set does not allow, in rule order.
+func (ruleSet Rules) subsetViolations(other Rules) []RuleViolation {
+	var violations []RuleViolation
+	for i, rule := range ruleSet {
+		if rule.Intent != IntentAllow {
+			continue
+		}
 		for _, action := range rule.Actions {
 			for _, target := range rule.Targets {
-				if rule.Intent == IntentAllow {
-					allow, _ := other.IsActionAllowedOnResource(action, target)
-					if !allow {
-						return false
-					}
+				allow, basis := other.isActionAllowed(action, target, ruleEnv{assume: rule.When})
+				// A derived view cannot drop the approval the other set requires
+				if !allow || (!rule.RequiresApproval && slices.ContainsFunc(basis[IntentAllow], func(r Rule) bool { return r.RequiresApproval })) {
+					violations = append(violations, RuleViolation{Rule: i, Action: action, Target: target})
 				}
 			}
 		}
 	}
-	return true
+	return violations
 }
 
 // ValidateDerivedView ensures that a derived view is valid with respect to its parent view.
@@ -137,6 +169,12 @@ func ValidateDerivedView(ctx context.Context, parent *ViewDefinition, child *Vie
 // It resolves the target scope and resource before performing the permission check.
 // All actions must be allowed for the function to return true.
 func AreActionsAllowedOnResource(vd *ViewDefinition, resource string, actions []Action) (bool, map[Intent][]Rule, apperrors.Error) {
+	return AreActionsAllowedWithAttributes(vd, resource, actions, nil)
+}
+
+// AreActionsAllowedWithAttributes is AreActionsAllowedOnResource for a request with the given
+// attributes, against which the conditions of the rules are evaluated.
+func AreActionsAllowedWithAttributes(vd *ViewDefinition, resource string, actions []Action, attrs Attributes) (bool, map[Intent][]Rule, apperrors.Error) {
 	if vd == nil {
 		return false, nil, ErrInvalidView.Msg("view definition is nil")
 	}
@@ -157,7 +195,7 @@ func AreActionsAllowedOnResource(vd *ViewDefinition, resource string, actions []
 
 	for _, action := range actions {
 		allowed := false
-		allowed, basis = vd.Rules.IsActionAllowedOnResource(action, targetResource)
+		allowed, basis = vd.Rules.isActionAllowed(action, targetResource, ruleEnv{attrs: attrs})
 		if !allowed {
 			return false, basis, nil
 		}
@@ -165,6 +203,37 @@ func AreActionsAllowedOnResource(vd *ViewDefinition, resource string, actions []
 	return true, basis, nil
 }
 
+// ApprovalRequired reports whether using the actions on the resource needs a person's
+// approval, which is the case if any allow rule that matches one of the actions requires
+// approval. It returns those rules. It does not check that the actions are allowed; use
+// AreActionsAllowedOnResource for that.
+func ApprovalRequired(vd *ViewDefinition, resource string, actions []Action) (bool, []Rule, apperrors.Error) {
+	if vd == nil {
+		return false, nil, ErrInvalidView.Msg("view definition is nil")
+	}
+	targetResource, err := resolveTargetResource(vd.Scope, resource)
+	if err != nil {
+		return false, nil, ErrInvalidView.New(err.Error())
+	}
+
+	vd = canonicalizeViewDefinition(vd)
+	var rules []Rule
+	for _, action := range actions {
+		_, basis := vd.Rules.isActionAllowed(action, targetResource, ruleEnv{})
+		for _, rule := range basis[IntentAllow] {
+			if rule.RequiresApproval && !slices.ContainsFunc(rules, rule.sameAs) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return len(rules) > 0, rules, nil
+}
+
+// sameAs reports whether two rules grant the same actions on the same targets.
+func (r Rule) sameAs(other Rule) bool {
+	return r.Intent == other.Intent && slices.Equal(r.Actions, other.Actions) && slices.Equal(r.Targets, other.Targets)
+}
+
 // CanAdoptView determines if the current view has permission to adopt another view
 // within the catalog context.
 //
@@ -191,7 +260,8 @@ func CanAdoptView(ctx context.Context, view string) (bool, apperrors.Error) {
 	if ourViewDef == nil {
 		return false, ErrInvalidView.Msg("unable to resolve view definition")
 	}
-	allowed, _ := ourViewDef.Rules.IsActionAllowedOnResource(ActionCatalogAdoptView, viewResource)
+	allowed, matchedRules := ourViewDef.Rules.IsActionAllowedWithAttributes(ActionCatalogAdoptView, viewResource, RequestAttributes(ctx))
+	RecordDecision(ctx, []Action{ActionCatalogAdoptView}, string(viewResource), allowed, matchedRules)
 	return allowed, nil
 }
 
@@ -208,6 +278,7 @@ func CanAdoptView(ctx context.Context, view string) (bool, apperrors.Error) {
 //
 // Note: This function requires a valid catalog context and an authorized view definition.
 // It checks if the current view has the ActionSkillSetUse permission for the target skill set.
+// A view in audit mode may use any skill set; denials are recorded but not enforced.
 func CanUseSkillSet(ctx context.Context, skillSetPath string) (bool, apperrors.Error) {
 	vd := GetViewDefinition(ctx)
 	if vd == nil {
@@ -221,24 +292,165 @@ func CanUseSkillSet(ctx context.Context, skillSetPath string) (bool, apperrors.E
 	if ourViewDef == nil {
 		return false, ErrInvalidView.Msg("unable to resolve view definition")
 	}
-	allowed, _ := ourViewDef.Rules.IsActionAllowedOnResource(ActionSkillSetUse, skillSetResource)
-	return allowed, nil
+	allowed, matchedRules := ourViewDef.Rules.IsActionAllowedWithAttributes(ActionSkillSetUse, skillSetResource, RequestAttributes(ctx))
+	return EnforceDecision(ctx, ourViewDef, []Action{ActionSkillSetUse}, string(skillSetResource), allowed, matchedRules), nil
+}
+
+// CanApproveSkillSet checks if the current view has permission to approve the invocations
+// of a skill set's skills that wait for approval.
+func CanApproveSkillSet(ctx context.Context, skillSetPath string) (bool, apperrors.Error) {
+	vd := GetViewDefinition(ctx)
+	if vd == nil {
+		return false, ErrInvalidView.Msg("unable to resolve view definition")
+	}
+	skillSetResource, _ := resolveTargetResource(vd.Scope, "/skillsets/"+strings.TrimPrefix(skillSetPath, "/"))
+	ourViewDef, err := ResolveAuthorizedViewDef(ctx)
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	if ourViewDef == nil {
+		return false, ErrInvalidView.Msg("unable to resolve view definition")
+	}
+	allowed, matchedRules := ourViewDef.Rules.IsActionAllowedWithAttributes(ActionSkillSetApprove, skillSetResource, RequestAttributes(ctx))
+	return EnforceDecision(ctx, ourViewDef, []Action{ActionSkillSetApprove}, string(skillSetResource), allowed, matchedRules), nil
+}
+
+// CanReadResourceInVariant checks if the current view has permission to read the definition of
+// a resource in another variant of the catalog, such as the variant a resource is compared
+// against. The resource is resolved in the namespace of the request.
+func CanReadResourceInVariant(ctx context.Context, variant, resourcePath string) (bool, apperrors.Error) {
+	scope, err := resolveTargetScope(ctx)
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	scope.Variant = variant
+	resource, err := resolveTargetResource(scope, "/resources/"+strings.TrimPrefix(resourcePath, "/"))
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	ourViewDef, err := ResolveAuthorizedViewDef(ctx)
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	actions := []Action{ActionResourceRead, ActionResourceEdit}
+	var matchedRules map[Intent][]Rule
+	allowed := false
+	for _, action := range actions {
+		if allowed, matchedRules = ourViewDef.Rules.IsActionAllowedWithAttributes(action, resource, RequestAttributes(ctx)); allowed {
+			break
+		}
+	}
+	return EnforceDecision(ctx, ourViewDef, actions, string(resource), allowed, matchedRules), nil
+}
+
+// CanGetResourceValue checks if the current view has permission to read the value of a
+// resource in the namespace of the request, for reads that do not address the resource in
+// the request path.
+func CanGetResourceValue(ctx context.Context, resourcePath string) (bool, apperrors.Error) {
+	return isActionAllowedOnResourcePath(ctx, resourcePath, ActionResourceGet, ActionResourcePut)
+}
+
+// CanPutResourceValue checks if the current view has permission to write the value of a
+// resource in the namespace of the request, for writes that do not address the resource in
+// the request path.
+func CanPutResourceValue(ctx context.Context, resourcePath string) (bool, apperrors.Error) {
+	return isActionAllowedOnResourcePath(ctx, resourcePath, ActionResourcePut)
+}
+
+// CanRevealResourceValue checks if the current view has permission to read the value of a
+// sensitive resource in the namespace of the request. Without it the value is redacted.
+func CanRevealResourceValue(ctx context.Context, resourcePath string) (bool, apperrors.Error) {
+	return isActionAllowedOnResourcePath(ctx, resourcePath, ActionResourceReveal)
+}
+
+// isActionAllowedOnResourcePath checks if the current view allows any of the actions on a
+// resource in the namespace of the request.
+func isActionAllowedOnResourcePath(ctx context.Context, resourcePath string, actions ...Action) (bool, apperrors.Error) {
+	scope, err := resolveTargetScope(ctx)
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	resource, err := resolveTargetResource(scope, "/resources/"+strings.TrimPrefix(resourcePath, "/"))
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	ourViewDef, err := ResolveAuthorizedViewDef(ctx)
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	var matchedRules map[Intent][]Rule
+	allowed := false
+	for _, action := range actions {
+		if allowed, matchedRules = ourViewDef.Rules.IsActionAllowedWithAttributes(action, resource, RequestAttributes(ctx)); allowed {
+			break
+		}
+	}
+	return EnforceDecision(ctx, ourViewDef, actions, string(resource), allowed, matchedRules), nil
+}
+
+// IsActionAllowedOnVariant checks if the current view allows an action on another variant of
+// the catalog, such as the variant a variant is compared against.
+func IsActionAllowedOnVariant(ctx context.Context, action Action, variant string) (bool, apperrors.Error) {
+	catalog := catcommon.GetCatalog(ctx)
+	if catalog == "" {
+		return false, ErrInvalidView.Msg("unable to resolve catalog")
+	}
+	variantResource, err := resolveTargetResource(Scope{Catalog: catalog}, "/variants/"+variant)
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	ourViewDef, err := ResolveAuthorizedViewDef(ctx)
+	if err != nil {
+		return false, ErrInvalidView.Msg(err.Error())
+	}
+	allowed, matchedRules := ourViewDef.Rules.IsActionAllowedWithAttributes(action, variantResource, RequestAttributes(ctx))
+	return EnforceDecision(ctx, ourViewDef, []Action{action}, string(variantResource), allowed, matchedRules), nil
 }
 
 // CanAdoptViewAsUser checks if the current user has permission to adopt a view
-// within the catalog context. We current allow by default in single user mode.
+// within the catalog context. In single user mode the user may adopt any view. Otherwise
+// the user must be a member of one of the groups the view is assigned to.
 //
 // Parameters:
 //   - ctx: The context for the operation
+//   - catalogID: The catalog the view belongs to
 //   - view: The name of the view to check adoption permissions for
-func CanAdoptViewAsUser(ctx context.Context, view string) bool {
+func CanAdoptViewAsUser(ctx context.Context, catalogID uuid.UUID, view string) bool {
 	if catcommon.GetSubjectType(ctx) != catcommon.SubjectTypeUser {
 		return false
 	}
 	if config.Config().SingleUserMode {
 		return true
 	}
-	return false
+	userID := catcommon.GetUserID(ctx)
+	if userID == "" {
+		return false
+	}
+
+	v, err := db.DB(ctx).GetViewByLabel(ctx, view, catalogID)
+	if err != nil {
+		return false
+	}
+	viewGroups := viewInfoFromJSON(v.Info).Groups
+	if len(viewGroups) == 0 {
+		return false
+	}
+	userGroups, err := db.DB(ctx).ListUserGroups(ctx, userID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", userID).Msg("unable to load user groups")
+		return false
+	}
+
+	allowed := false
+	for _, group := range userGroups {
+		if slices.Contains(viewGroups, group) {
+			allowed = true
+			break
+		}
+	}
+	viewResource, _ := resolveTargetResource(Scope{Catalog: catcommon.GetCatalog(ctx)}, "/views/"+view)
+	RecordDecision(ctx, []Action{ActionCatalogAdoptView}, string(viewResource), allowed, nil)
+	return allowed
 }
 
 func getResourceKindFromPath(resourcePath string) string {
@@ -253,10 +465,11 @@ func getResourceKindFromPath(resourcePath string) string {
 
 func normalizeResourcePath(resourceKind string, resource TargetResource) TargetResource {
 	if resourceKind == catcommon.KindNameResources {
-		const prefix = "/resources/definition"
-		if strings.HasPrefix(string(resource), prefix) {
-			// Rewrite /resources/definition/... → /resources/...
-			return TargetResource("/resources" + strings.TrimPrefix(string(resource), prefix))
+		for _, prefix := range []string{"/resources/definition", "/resources/diff", "/resources/revisions", "/resources/render"} {
+			if strings.HasPrefix(string(resource), prefix) {
+				// Rewrite /resources/{definition,diff,revisions,render}/... → /resources/...
+				return TargetResource("/resources" + strings.TrimPrefix(string(resource), prefix))
+			}
 		}
 	}
 	return resource
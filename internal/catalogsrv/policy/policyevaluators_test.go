@@ -3,6 +3,7 @@ package policy
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
@@ -579,7 +580,7 @@ func TestRules_IsSubsetOf(t *testing.T) {
 			expected: true,
 		},
 		{
-			name: "child has namespace rules within parent scope",
+			name: "child has namespace rules matched by a wildcard segment in parent",
 			child: Rules{
 				{
 					Intent:  IntentAllow,
@@ -599,7 +600,7 @@ func TestRules_IsSubsetOf(t *testing.T) {
 					Targets: []TargetResource{"res://catalogs/my-catalog/namespaces/*/test"},
 				},
 			},
-			expected: false,
+			expected: true,
 		},
 		{
 			name: "child has subset of namespace rules within parent scope",
@@ -682,6 +683,147 @@ func TestRules_IsSubsetOf(t *testing.T) {
 	}
 }
 
+func TestValidateDerivedView(t *testing.T) {
+	ctx := context.Background()
+	view := func(actions []Action, targets ...TargetResource) *ViewDefinition {
+		return &ViewDefinition{
+			Rules: Rules{{Intent: IntentAllow, Actions: actions, Targets: targets}},
+		}
+	}
+	read := []Action{ActionResourceRead}
+
+	tests := []struct {
+		name    string
+		parent  *ViewDefinition
+		child   *ViewDefinition
+		wantErr bool
+	}{
+		{
+			name:   "same target",
+			parent: view(read, "res://catalogs/my-catalog"),
+			child:  view(read, "res://catalogs/my-catalog"),
+		},
+		{
+			name:    "child wildcard is wider than specific parent",
+			parent:  view(read, "res://catalogs/my-catalog"),
+			child:   view(read, "res://catalogs/*"),
+			wantErr: true,
+		},
+		{
+			name:   "child wildcard within parent wildcard",
+			parent: view(read, "res://catalogs/*"),
+			child:  view(read, "res://catalogs/my-catalog/variants/*"),
+		},
+		{
+			name:   "child double wildcard within parent trailing wildcard",
+			parent: view(read, "res://catalogs/my-catalog/*"),
+			child:  view(read, "res://catalogs/my-catalog/variants/**"),
+		},
+		{
+			name:    "child double wildcard is wider than parent single segment wildcard",
+			parent:  view(read, "res://catalogs/my-catalog/variants/*/resources/config"),
+			child:   view(read, "res://catalogs/my-catalog/**/resources/config"),
+			wantErr: true,
+		},
+		{
+			name:   "child glob within parent wildcard segment",
+			parent: view(read, "res://catalogs/my-catalog/variants/*/resources/config"),
+			child:  view(read, "res://catalogs/my-catalog/variants/prod-*/resources/config"),
+		},
+		{
+			name:    "child wildcard segment is wider than parent glob",
+			parent:  view(read, "res://catalogs/my-catalog/variants/prod-*/resources/config"),
+			child:   view(read, "res://catalogs/my-catalog/variants/*/resources/config"),
+			wantErr: true,
+		},
+		{
+			name:    "child glob extends parent admin target",
+			parent:  view([]Action{ActionVariantAdmin}, "res://catalogs/my-catalog/variants/prod"),
+			child:   view([]Action{ActionVariantAdmin}, "res://catalogs/my-catalog/variants/prod*"),
+			wantErr: true,
+		},
+		{
+			name: "child wildcard overlaps parent deny",
+			parent: &ViewDefinition{
+				Rules: Rules{
+					{Intent: IntentAllow, Actions: read, Targets: []TargetResource{"res://catalogs/my-catalog/*"}},
+					{Intent: IntentDeny, Actions: read, Targets: []TargetResource{"res://catalogs/my-catalog/variants/prod/resources/secrets"}},
+				},
+			},
+			child:   view(read, "res://catalogs/my-catalog/variants/*/resources/secrets"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDerivedView(ctx, tt.parent, tt.child)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateDerivedView() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRules_SubsetViolations(t *testing.T) {
+	parent := Rules{
+		{Intent: IntentAllow, Actions: []Action{ActionResourceGet}, Targets: []TargetResource{"res://catalogs/my-catalog/*"}},
+	}
+	child := Rules{
+		{Intent: IntentAllow, Actions: []Action{ActionResourceGet}, Targets: []TargetResource{"res://catalogs/my-catalog/variants/dev"}},
+		{Intent: IntentDeny, Actions: []Action{ActionResourcePut}, Targets: []TargetResource{"res://catalogs/my-catalog/*"}},
+		{Intent: IntentAllow, Actions: []Action{ActionResourceGet, ActionResourcePut}, Targets: []TargetResource{"res://catalogs/my-catalog/variants/dev", "res://catalogs/other"}},
+	}
+
+	want := []RuleViolation{
+		{Rule: 2, Action: ActionResourceGet, Target: "res://catalogs/other"},
+		{Rule: 2, Action: ActionResourcePut, Target: "res://catalogs/my-catalog/variants/dev"},
+		{Rule: 2, Action: ActionResourcePut, Target: "res://catalogs/other"},
+	}
+	if got := child.subsetViolations(parent); !reflect.DeepEqual(got, want) {
+		t.Errorf("subsetViolations() = %v, want %v", got, want)
+	}
+	if child.IsSubsetOf(parent) {
+		t.Errorf("IsSubsetOf() = true, want false")
+	}
+	if got := child[:2].subsetViolations(parent); len(got) != 0 {
+		t.Errorf("subsetViolations() = %v, want none", got)
+	}
+}
+
+func TestApprovalRequired(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{Catalog: "test-catalog", Variant: "test-variant"},
+		Rules: Rules{
+			{Intent: IntentAllow, Actions: []Action{"kubernetes.pods.list"}, Targets: []TargetResource{"res://skillsets/ops/*"}},
+			{Intent: IntentAllow, Actions: []Action{"kubernetes.deployments.restart"}, Targets: []TargetResource{"res://skillsets/ops/*"}, RequiresApproval: true},
+		},
+	}
+
+	required, rules, err := ApprovalRequired(vd, "/skillsets/ops/k8s", []Action{"kubernetes.pods.list"})
+	if err != nil || required || len(rules) != 0 {
+		t.Errorf("ApprovalRequired() = %v, %v, %v, want false, none, nil", required, rules, err)
+	}
+
+	required, rules, err = ApprovalRequired(vd, "/skillsets/ops/k8s", []Action{"kubernetes.pods.list", "kubernetes.deployments.restart"})
+	if err != nil || !required || len(rules) != 1 || !reflect.DeepEqual(rules[0].Actions, []Action{"kubernetes.deployments.restart"}) {
+		t.Errorf("ApprovalRequired() = %v, %v, %v, want true, [restart rule], nil", required, rules, err)
+	}
+
+	// A derived view may not drop the approval its parent requires
+	child := Rules{
+		{Intent: IntentAllow, Actions: []Action{"kubernetes.deployments.restart"}, Targets: []TargetResource{"res://catalogs/test-catalog/variants/test-variant/skillsets/ops/k8s"}},
+	}
+	parent := canonicalizeViewDefinition(vd).Rules
+	if got := child.subsetViolations(parent); len(got) != 1 {
+		t.Errorf("subsetViolations() = %v, want one violation", got)
+	}
+	child[0].RequiresApproval = true
+	if got := child.subsetViolations(parent); len(got) != 0 {
+		t.Errorf("subsetViolations() = %v, want none", got)
+	}
+}
+
 func TestAreActionsAllowedOnResource(t *testing.T) {
 	tests := []struct {
 		name           string
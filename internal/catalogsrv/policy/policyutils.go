@@ -1,6 +1,10 @@
 package policy
 
-import "strings"
+import (
+	"path"
+	"slices"
+	"strings"
+)
 
 // adminActionMap represents a set of admin actions
 type adminActionMap map[Action]bool
@@ -36,9 +40,9 @@ func checkAdminMatch(resourceType string, ruleSegments []string) bool {
 	return ruleSegments[lenRule-2] == resourceType
 }
 
-func (r Rules) matchesAdmin(resource string) (bool, Rule) {
+func (r Rules) matchesAdmin(resource string, env ruleEnv) (bool, Rule) {
 	for _, rule := range r {
-		if rule.Intent != IntentAllow {
+		if rule.Intent != IntentAllow || !rule.applies(env) {
 			continue
 		}
 
@@ -63,7 +67,7 @@ func (r Rules) matchesAdmin(resource string) (bool, Rule) {
 			if adminActions[ActionNamespaceAdmin] && checkAdminMatch("namespaces", ruleSegments) {
 				isMatch = true
 			}
-			if isMatch && (strings.HasPrefix(resource, string(res)) || res.matches(resource)) {
+			if isMatch && (strings.HasPrefix(resource, string(res)+"/") || res.matches(resource)) {
 				return true, rule
 			}
 		}
@@ -71,36 +75,138 @@ func (r Rules) matchesAdmin(resource string) (bool, Rule) {
 	return false, Rule{}
 }
 
+// matches reports whether the target pattern covers actualRes. actualRes is usually a concrete
+// resource, but it may itself be a pattern, e.g. the target of a derived view's rule, in which
+// case every resource it matches must also be matched by r.
+//
+// Targets are matched segment by segment. A "**" segment matches zero or more segments, and a
+// "*" in the last segment matches one or more segments. Any other segment is matched with
+// path.Match, so "*" matches exactly one segment and "?", "prod-*" and "[a-c]" work as in shell
+// globs. The resource kind that follows res:// is never matched by a pattern unless it is the
+// only segment of the target.
 func (r TargetResource) matches(actualRes string) bool {
-	if r == "" || actualRes == "" {
+	ruleSegments, ok := targetSegments(string(r))
+	if !ok {
 		return false
 	}
+	actualSegments, ok := targetSegments(actualRes)
+	if !ok {
+		return false
+	}
+	if len(ruleSegments) > 1 && isGlobSegment(ruleSegments[0]) {
+		return false
+	}
+	return coversSegments(expandTrailingWildcard(ruleSegments), expandTrailingWildcard(actualSegments))
+}
 
-	ruleSegments := strings.Split(string(r), "/")
-	actualSegments := strings.Split(actualRes, "/")
-	ruleLen := len(ruleSegments)
-	actualLen := len(actualSegments)
-
-	if ruleLen > actualLen {
+// overlaps reports whether the target patterns r and other match at least one common resource.
+// Segment patterns other than "*" and "**" are assumed to overlap unless one of them is literal,
+// so the result errs on the side of reporting an overlap.
+func (r TargetResource) overlaps(other string) bool {
+	ruleSegments, ok := targetSegments(string(r))
+	if !ok {
 		return false
 	}
+	otherSegments, ok := targetSegments(other)
+	if !ok {
+		return false
+	}
+	return overlapSegments(expandTrailingWildcard(ruleSegments), expandTrailingWildcard(otherSegments))
+}
 
-	if ruleLen < actualLen {
-		if ruleSegments[ruleLen-1] != "*" {
-			return false
-		}
+// targetSegments splits a resource URI into its path segments. It returns false if the URI is
+// empty or does not start with res://.
+func targetSegments(uri string) ([]string, bool) {
+	const prefix = "res://"
+	if !strings.HasPrefix(uri, prefix) {
+		return nil, false
 	}
+	rest := uri[len(prefix):]
+	if rest == "" {
+		return []string{}, true
+	}
+	return strings.Split(rest, "/"), true
+}
+
+// expandTrailingWildcard rewrites a trailing "*", which matches one or more segments, as "*"
+// followed by "**".
+func expandTrailingWildcard(segments []string) []string {
+	if len(segments) == 0 || segments[len(segments)-1] != "*" {
+		return segments
+	}
+	return append(slices.Clone(segments), "**")
+}
 
-	for i := range ruleLen {
-		// this case should have been caught by the validator
-		if ruleSegments[i] == "*" && i != ruleLen-1 {
+// onlyRecursiveWildcards reports whether segments can match an empty path.
+func onlyRecursiveWildcards(segments []string) bool {
+	for _, segment := range segments {
+		if segment != "**" {
 			return false
 		}
-		if ruleSegments[i] == "*" || ruleSegments[i] == actualSegments[i] {
-			continue
-		}
+	}
+	return true
+}
+
+func isGlobSegment(segment string) bool {
+	return strings.ContainsAny(segment, "*?[")
+}
+
+// coversSegments reports whether every path matched by the segments in actual is matched by
+// the segments in rule.
+func coversSegments(rule, actual []string) bool {
+	if len(actual) == 0 {
+		return onlyRecursiveWildcards(rule)
+	}
+	if len(rule) == 0 {
+		return false
+	}
+	if rule[0] == "**" {
+		return coversSegments(rule[1:], actual) || coversSegments(rule, actual[1:])
+	}
+	if actual[0] == "**" || !coversSegment(rule[0], actual[0]) {
 		return false
 	}
+	return coversSegments(rule[1:], actual[1:])
+}
 
-	return true
+// coversSegment reports whether the single-segment pattern covers segment, which may itself be
+// a pattern.
+func coversSegment(pattern, segment string) bool {
+	if segment == "" {
+		return pattern == ""
+	}
+	if isGlobSegment(segment) {
+		return pattern == "*" || pattern == segment
+	}
+	matched, err := path.Match(pattern, segment)
+	return err == nil && matched
+}
+
+func overlapSegments(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return onlyRecursiveWildcards(a) && onlyRecursiveWildcards(b)
+	}
+	if a[0] == "**" {
+		return overlapSegments(a[1:], b) || overlapSegments(a, b[1:])
+	}
+	if b[0] == "**" {
+		return overlapSegments(a, b[1:]) || overlapSegments(a[1:], b)
+	}
+	if !overlapSegment(a[0], b[0]) {
+		return false
+	}
+	return overlapSegments(a[1:], b[1:])
+}
+
+func overlapSegment(a, b string) bool {
+	switch {
+	case a == "" || b == "":
+		return a == b
+	case !isGlobSegment(b):
+		return coversSegment(a, b)
+	case !isGlobSegment(a):
+		return coversSegment(b, a)
+	default:
+		return true
+	}
 }
@@ -329,7 +329,7 @@ func TestRules_MatchesAdmin(t *testing.T) {
 			if tt.name == "matching catalog admin rule" {
 				fmt.Println("matching catalog admin rule")
 			}
-			if got, _ := tt.rules.matchesAdmin(tt.resource); got != tt.want {
+			if got, _ := tt.rules.matchesAdmin(tt.resource, ruleEnv{}); got != tt.want {
 				t.Errorf("Rules.matchesAdmin() = %v, want %v", got, tt.want)
 			}
 		})
@@ -371,7 +371,7 @@ func TestTargetResource_Matches(t *testing.T) {
 			name:      "wildcard in middle matches single segment",
 			target:    "res://catalogs/*/variants/my-variant",
 			actualRes: "res://catalogs/my-catalog/variants/my-variant",
-			want:      false,
+			want:      true,
 		},
 		{
 			name:      "no match - different segments",
@@ -455,7 +455,7 @@ func TestTargetResource_Matches(t *testing.T) {
 			name:      "wildcard matches any single segment",
 			target:    "res://catalogs/*/variants/my-variant",
 			actualRes: "res://catalogs/any-catalog/variants/my-variant",
-			want:      false,
+			want:      true,
 		},
 		{
 			name:      "wildcard at end matches exactly one segment",
@@ -469,6 +469,84 @@ func TestTargetResource_Matches(t *testing.T) {
 			actualRes: "res://catalogs/my-catalog/variants/my-variant/namespaces/my-namespace",
 			want:      true,
 		},
+		{
+			name:      "double wildcard matches multiple segments",
+			target:    "res://catalogs/my-catalog/**/resources/config",
+			actualRes: "res://catalogs/my-catalog/variants/my-variant/namespaces/my-namespace/resources/config",
+			want:      true,
+		},
+		{
+			name:      "double wildcard matches zero segments",
+			target:    "res://catalogs/my-catalog/**/resources/config",
+			actualRes: "res://catalogs/my-catalog/resources/config",
+			want:      true,
+		},
+		{
+			name:      "trailing double wildcard matches the parent",
+			target:    "res://catalogs/my-catalog/**",
+			actualRes: "res://catalogs/my-catalog",
+			want:      true,
+		},
+		{
+			name:      "trailing wildcard does not match the parent",
+			target:    "res://catalogs/my-catalog/*",
+			actualRes: "res://catalogs/my-catalog",
+			want:      false,
+		},
+		{
+			name:      "no match - double wildcard with different suffix",
+			target:    "res://catalogs/my-catalog/**/resources/config",
+			actualRes: "res://catalogs/my-catalog/variants/my-variant/resources/secrets",
+			want:      false,
+		},
+		{
+			name:      "segment glob",
+			target:    "res://catalogs/my-catalog/variants/prod-*",
+			actualRes: "res://catalogs/my-catalog/variants/prod-eu",
+			want:      true,
+		},
+		{
+			name:      "no match - segment glob",
+			target:    "res://catalogs/my-catalog/variants/prod-*",
+			actualRes: "res://catalogs/my-catalog/variants/dev-eu",
+			want:      false,
+		},
+		{
+			name:      "character class",
+			target:    "res://catalogs/my-catalog/variants/v[1-3]",
+			actualRes: "res://catalogs/my-catalog/variants/v2",
+			want:      true,
+		},
+		{
+			name:      "no match - character class",
+			target:    "res://catalogs/my-catalog/variants/v[1-3]",
+			actualRes: "res://catalogs/my-catalog/variants/v4",
+			want:      false,
+		},
+		{
+			name:      "single character wildcard",
+			target:    "res://catalogs/my-catalog/variants/v?",
+			actualRes: "res://catalogs/my-catalog/variants/v9",
+			want:      true,
+		},
+		{
+			name:      "pattern covers narrower pattern",
+			target:    "res://catalogs/my-catalog/variants/*",
+			actualRes: "res://catalogs/my-catalog/variants/prod-*",
+			want:      true,
+		},
+		{
+			name:      "no match - pattern does not cover wider pattern",
+			target:    "res://catalogs/my-catalog/variants/prod-*",
+			actualRes: "res://catalogs/my-catalog/variants/*",
+			want:      false,
+		},
+		{
+			name:      "no match - single segment wildcard does not cover double wildcard",
+			target:    "res://catalogs/*/variants/my-variant",
+			actualRes: "res://catalogs/**/variants/my-variant",
+			want:      false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"slices"
+	"strings"
+)
+
+// SimulationResult is the outcome of evaluating an action on a target against a view's
+// rules without performing it.
+type SimulationResult struct {
+	Action  Action         `json:"action"`
+	Target  TargetResource `json:"target"`
+	Allowed bool           `json:"allowed"`
+	// MatchedRules are the rules that decided the outcome: the allow rules that matched if the
+	// action is allowed, otherwise the deny rules that matched. No matched rules on a denied
+	// action means that no rule allows it.
+	MatchedRules Rules `json:"matchedRules"`
+	// Explanation is only set when an explanation is requested
+	Explanation *Explanation `json:"explanation,omitempty"`
+}
+
+// SimulateAction evaluates whether the view definition allows the action on the target, the
+// same way the policy middleware does, and reports the rules that decided it. The target is
+// a resource path, with or without the res:// prefix, resolved against the view's scope.
+// Nothing is recorded in the authorization audit log. With explain set, the result also
+// explains how the rules decided it.
+func SimulateAction(vd *ViewDefinition, action Action, target string, explain bool) (*SimulationResult, error) {
+	if vd == nil {
+		return nil, ErrInvalidView.Msg("view definition is nil")
+	}
+	if action == "" {
+		return nil, ErrInvalidView.Msg("action is required")
+	}
+	if strings.HasPrefix(string(action), "system.") && !slices.Contains(ValidActions, action) {
+		return nil, ErrInvalidView.Msg("unknown action: " + string(action))
+	}
+	if target == "" {
+		return nil, ErrInvalidView.Msg("target is required")
+	}
+	if err := validateResourceURI("res://" + strings.TrimPrefix(strings.TrimPrefix(target, "res://"), "/")); err != nil {
+		return nil, ErrInvalidView.Msg(err.Error())
+	}
+
+	allowed, basis, err := AreActionsAllowedOnResource(vd, target, []Action{action})
+	if err != nil {
+		return nil, err
+	}
+	targetResource, _ := resolveTargetResource(vd.Scope, target)
+
+	result := &SimulationResult{
+		Action:       action,
+		Target:       targetResource,
+		Allowed:      allowed,
+		MatchedRules: Rules{},
+	}
+	if allowed {
+		result.MatchedRules = append(result.MatchedRules, basis[IntentAllow]...)
+	} else {
+		result.MatchedRules = append(result.MatchedRules, basis[IntentDeny]...)
+	}
+	if explain {
+		result.Explanation = explainDecision(canonicalizeViewDefinition(vd).Rules, []Action{action}, targetResource, allowed, basis)
+	}
+	return result, nil
+}
@@ -0,0 +1,72 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateAction(t *testing.T) {
+	vd := &ViewDefinition{
+		Scope: Scope{
+			Catalog: "test-catalog",
+			Variant: "test-variant",
+		},
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionResourceRead, ActionResourceEdit},
+				Targets: []TargetResource{"res://resources/*"},
+			},
+			{
+				Intent:  IntentDeny,
+				Actions: []Action{ActionResourceEdit},
+				Targets: []TargetResource{"res://resources/secrets"},
+			},
+		},
+	}
+
+	// Allowed by the allow rule
+	result, err := SimulateAction(vd, ActionResourceRead, "/resources/config", false)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, TargetResource("res://catalogs/test-catalog/variants/test-variant/resources/config"), result.Target)
+	require.Len(t, result.MatchedRules, 1)
+	assert.Equal(t, IntentAllow, result.MatchedRules[0].Intent)
+
+	// Denied by the deny rule
+	result, err = SimulateAction(vd, ActionResourceEdit, "res://resources/secrets", false)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	require.Len(t, result.MatchedRules, 1)
+	assert.Equal(t, IntentDeny, result.MatchedRules[0].Intent)
+
+	// The explanation shows that the deny rule overrode the allow rule
+	result, err = SimulateAction(vd, ActionResourceEdit, "res://resources/secrets", true)
+	require.NoError(t, err)
+	require.NotNil(t, result.Explanation)
+	assert.True(t, result.Explanation.DenyOverridesAllow)
+	assert.Len(t, result.Explanation.EvaluatedRules, 2)
+	assert.Len(t, result.Explanation.MatchedAllowRules, 1)
+	assert.Len(t, result.Explanation.MatchedDenyRules, 1)
+	assert.Equal(t, TargetResource("res://catalogs/test-catalog/variants/test-variant/resources/*"), result.Explanation.EvaluatedRules[0].Targets[0])
+
+	// Denied because no rule allows it
+	result, err = SimulateAction(vd, ActionResourceDelete, "/resources/config", false)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Empty(t, result.MatchedRules)
+
+	// Invalid checks
+	_, err = SimulateAction(vd, "", "/resources/config", false)
+	assert.Error(t, err)
+	_, err = SimulateAction(vd, "system.resource.unknown", "/resources/config", false)
+	assert.Error(t, err)
+	_, err = SimulateAction(vd, ActionResourceRead, "", false)
+	assert.Error(t, err)
+	_, err = SimulateAction(vd, ActionResourceRead, "/unknown/config", false)
+	assert.Error(t, err)
+	_, err = SimulateAction(nil, ActionResourceRead, "/resources/config", false)
+	assert.Error(t, err)
+}
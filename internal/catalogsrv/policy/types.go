@@ -2,6 +2,7 @@ package policy
 
 import (
 	"encoding/json"
+	"slices"
 
 	"github.com/tansive/tansive-internal/internal/common/httpx"
 )
@@ -35,6 +36,7 @@ const (
 	ActionResourceGet       Action = "system.resource.get"
 	ActionResourcePut       Action = "system.resource.put"
 	ActionResourceList      Action = "system.resource.list"
+	ActionResourceReveal    Action = "system.resource.reveal"
 	ActionSkillSetAdmin     Action = "system.skillset.admin"
 	ActionSkillSetCreate    Action = "system.skillset.create"
 	ActionSkillSetRead      Action = "system.skillset.read"
@@ -42,6 +44,7 @@ const (
 	ActionSkillSetDelete    Action = "system.skillset.delete"
 	ActionSkillSetList      Action = "system.skillset.list"
 	ActionSkillSetUse       Action = "system.skillset.use"
+	ActionSkillSetApprove   Action = "system.skillset.approve"
 	ActionTangentCreate     Action = "system.tangent.create"
 	ActionTangentDelete     Action = "system.tangent.delete"
 )
@@ -64,18 +67,34 @@ var ValidActions = []Action{
 	ActionResourceGet,
 	ActionResourcePut,
 	ActionResourceList,
+	ActionResourceReveal,
 	ActionSkillSetCreate,
 	ActionSkillSetRead,
 	ActionSkillSetEdit,
 	ActionSkillSetDelete,
 	ActionSkillSetList,
 	ActionSkillSetUse,
+	ActionSkillSetApprove,
 }
 
+// EnforcementMode says whether the decisions of a view are enforced. In audit mode, requests the
+// view denies are recorded in the audit log but still succeed.
+type EnforcementMode string
+
+const (
+	EnforcementModeEnforce EnforcementMode = "enforce"
+	EnforcementModeAudit   EnforcementMode = "audit"
+)
+
 type Rule struct {
 	Intent  Intent           `json:"intent" validate:"required,viewRuleIntentValidator"`
 	Actions []Action         `json:"actions" validate:"required,dive,viewRuleActionValidator"`
 	Targets []TargetResource `json:"targets" validate:"-"`
+	// When is an optional condition on the request attributes; the rule only applies when it holds
+	When string `json:"when,omitempty" validate:"-"`
+	// RequiresApproval marks an allow rule whose actions a person must approve each time a
+	// skill uses them; see ApprovalRequired
+	RequiresApproval bool `json:"requiresApproval,omitempty" validate:"-"`
 }
 
 type TargetResource string
@@ -92,18 +111,29 @@ func (v Scope) Equals(other Scope) bool {
 		v.Namespace == other.Namespace
 }
 
+// ViewDefinition is the scope and rules of a view. Includes names the views whose rules are
+// merged into the view when it is loaded; see LoadViewDefinition. An empty Mode is enforced.
 type ViewDefinition struct {
-	Scope Scope `json:"scope" validate:"required"`
-	Rules Rules `json:"rules" validate:"required,dive"`
+	Scope    Scope           `json:"scope" validate:"required"`
+	Rules    Rules           `json:"rules" validate:"required,dive"`
+	Includes []string        `json:"includes,omitempty"`
+	Mode     EnforcementMode `json:"mode,omitempty"`
 }
 
 func (v ViewDefinition) DeepCopy() ViewDefinition {
 	return ViewDefinition{
-		Scope: v.Scope, // Scope is a struct of strings (safe to copy)
-		Rules: v.Rules.DeepCopy(),
+		Scope:    v.Scope, // Scope is a struct of strings (safe to copy)
+		Rules:    v.Rules.DeepCopy(),
+		Includes: slices.Clone(v.Includes),
+		Mode:     v.Mode,
 	}
 }
 
+// AuditOnly reports whether the view is in audit mode.
+func (v *ViewDefinition) AuditOnly() bool {
+	return v != nil && v.Mode == EnforcementModeAudit
+}
+
 func (r Rules) DeepCopy() Rules {
 	copied := make(Rules, len(r))
 	for i, rule := range r {
@@ -120,9 +150,11 @@ func (r Rule) DeepCopy() Rule {
 	copy(targetsCopy, r.Targets)
 
 	return Rule{
-		Intent:  r.Intent,
-		Actions: actionsCopy,
-		Targets: targetsCopy,
+		Intent:           r.Intent,
+		Actions:          actionsCopy,
+		Targets:          targetsCopy,
+		When:             r.When,
+		RequiresApproval: r.RequiresApproval,
 	}
 }
 
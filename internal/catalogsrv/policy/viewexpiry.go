@@ -0,0 +1,69 @@
+package policy
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+// CheckViewActive returns ErrViewNotActive if the view cannot be used at the given time,
+// either because its notBefore is still ahead, it has expired, or it has been disabled.
+func CheckViewActive(view *models.View, now time.Time) apperrors.Error {
+	if view == nil {
+		return ErrInvalidView
+	}
+	info := viewInfoFromJSON(view.Info)
+	if info.Disabled {
+		return ErrViewNotActive.Msg("view " + view.Label + " is disabled")
+	}
+	if info.NotBefore != nil && now.Before(*info.NotBefore) {
+		return ErrViewNotActive.Msg("view " + view.Label + " is not active until " + info.NotBefore.Format(time.RFC3339))
+	}
+	if info.ExpiresAt != nil && !now.Before(*info.ExpiresAt) {
+		return ErrViewNotActive.Msg("view " + view.Label + " expired at " + info.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// RunViewExpiryJob disables views whose expiresAt has passed, every interval until the
+// context is done. Expired views are already rejected when they are used; disabling them
+// flags them so that they stay unusable until they are updated with a new window.
+func RunViewExpiryJob(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		disableExpiredViews(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func disableExpiredViews(ctx context.Context) {
+	dbCtx, err := db.ConnCtx(db.WithAllTenants(ctx))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("view expiry: unable to get db connection")
+		return
+	}
+	defer db.DB(dbCtx).Close(dbCtx)
+
+	views, dbErr := db.DB(dbCtx).DisableExpiredViews(dbCtx, time.Now())
+	if dbErr != nil {
+		log.Ctx(ctx).Error().Err(dbErr).Msg("view expiry: unable to disable expired views")
+		return
+	}
+	for _, view := range views {
+		log.Ctx(ctx).Info().
+			Str("tenant_id", string(view.TenantID)).
+			Str("view_id", view.ViewID.String()).
+			Str("view", view.Label).
+			Msg("disabled expired view")
+	}
+}
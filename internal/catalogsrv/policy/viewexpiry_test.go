@@ -0,0 +1,40 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestCheckViewActive(t *testing.T) {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+	after := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		info    viewInfo
+		wantErr bool
+	}{
+		{name: "no window", info: viewInfo{}},
+		{name: "within window", info: viewInfo{NotBefore: &before, ExpiresAt: &after}},
+		{name: "not yet active", info: viewInfo{NotBefore: &after}, wantErr: true},
+		{name: "expired", info: viewInfo{ExpiresAt: &before}, wantErr: true},
+		{name: "expires now", info: viewInfo{ExpiresAt: &now}, wantErr: true},
+		{name: "disabled", info: viewInfo{Disabled: true}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckViewActive(&models.View{Label: "test-view", Info: tt.info.marshal()}, now)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrViewNotActive)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Error(t, CheckViewActive(nil, now))
+}
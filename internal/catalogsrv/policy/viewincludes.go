@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// LoadViewDefinition returns the definition of a view with the rules of the views it includes
+// merged in. Includes are resolved recursively; an include cycle, a missing included view or an
+// included view with a different scope is an error, so that a view never loads with only part
+// of its rules. The returned definition has no includes left to resolve.
+func LoadViewDefinition(ctx context.Context, view *models.View) (*ViewDefinition, apperrors.Error) {
+	viewDef, err := unmarshalViewDefinition(view)
+	if err != nil {
+		return nil, err
+	}
+	if len(viewDef.Includes) == 0 {
+		return viewDef, nil
+	}
+	rules, err := resolveIncludes(ctx, view.CatalogID, viewDef.Scope, viewDef.Includes, []string{view.Label})
+	if err != nil {
+		return nil, err
+	}
+	viewDef.Rules = deduplicateRules(append(rules, viewDef.Rules...))
+	viewDef.Includes = nil
+	return viewDef, nil
+}
+
+// validateIncludes checks that the views included by the view with the given name exist, have
+// the same scope and do not include the view back.
+func validateIncludes(ctx context.Context, catalogID uuid.UUID, name string, scope Scope, includes []string) apperrors.Error {
+	if len(includes) == 0 {
+		return nil
+	}
+	_, err := resolveIncludes(ctx, catalogID, scope, includes, []string{name})
+	return err
+}
+
+// resolveIncludes returns the rules of the included views and, recursively, of the views they
+// include. path holds the labels of the views being resolved, outermost first.
+func resolveIncludes(ctx context.Context, catalogID uuid.UUID, scope Scope, includes []string, path []string) (Rules, apperrors.Error) {
+	var rules Rules
+	for _, label := range includes {
+		if slices.Contains(path, label) {
+			return nil, ErrInvalidView.New("view include cycle: " + strings.Join(append(path, label), " -> "))
+		}
+		view, err := db.DB(ctx).GetViewByLabel(ctx, label, catalogID)
+		if err != nil {
+			if errors.Is(err, dberror.ErrNotFound) {
+				return nil, ErrViewNotFound.New("included view not found: " + label)
+			}
+			log.Ctx(ctx).Error().Err(err).Str("view", label).Msg("failed to load included view")
+			return nil, ErrUnableToLoadObject.Msg("unable to load included view")
+		}
+		viewDef, err := unmarshalViewDefinition(view)
+		if err != nil {
+			return nil, err
+		}
+		if !viewDef.Scope.Equals(scope) {
+			return nil, ErrInvalidView.New("included view " + label + " has a different scope")
+		}
+		included, err := resolveIncludes(ctx, catalogID, scope, viewDef.Includes, append(slices.Clone(path), label))
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, included...)
+		rules = append(rules, viewDef.Rules...)
+	}
+	return rules, nil
+}
+
+// includingViews returns the labels of the views in the catalog that include the given view.
+func includingViews(ctx context.Context, catalogID uuid.UUID, label string) ([]string, apperrors.Error) {
+	views, err := db.DB(ctx).ListViewsByCatalog(ctx, catalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load views")
+		return nil, ErrUnableToLoadObject.Msg("unable to load views")
+	}
+	var labels []string
+	for _, view := range views {
+		var viewDef ViewDefinition
+		if err := json.Unmarshal(view.Rules, &viewDef); err != nil {
+			continue
+		}
+		if slices.Contains(viewDef.Includes, label) {
+			labels = append(labels, view.Label)
+		}
+	}
+	return labels, nil
+}
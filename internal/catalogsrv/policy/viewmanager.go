@@ -44,7 +44,7 @@ func NewViewManagerByViewLabel(ctx context.Context, viewLabel string) (ViewManag
 		log.Ctx(ctx).Error().Err(err).Msg("failed to load view")
 		return nil, ErrUnableToLoadObject.Msg("unable to load view")
 	}
-	viewDef, err := unmarshalViewDefinition(view)
+	viewDef, err := LoadViewDefinition(ctx, view)
 	if err != nil {
 		return nil, err
 	}
@@ -62,7 +62,7 @@ func NewViewManagerByViewID(ctx context.Context, viewID uuid.UUID) (ViewManager,
 		log.Ctx(ctx).Error().Err(err).Msg("failed to load view")
 		return nil, ErrUnableToLoadObject.Msg("unable to load view")
 	}
-	viewDef, err := unmarshalViewDefinition(view)
+	viewDef, err := LoadViewDefinition(ctx, view)
 	if err != nil {
 		return nil, err
 	}
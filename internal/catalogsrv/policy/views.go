@@ -3,8 +3,10 @@ package policy
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"encoding/json"
 
@@ -30,9 +32,47 @@ type viewSchema struct {
 	Spec       viewSpec            `json:"spec" validate:"required"`
 }
 
-// viewSpec contains the spec of a view
+// viewSpec contains the spec of a view. Members of the listed groups may adopt the view.
+// A view with notBefore or expiresAt can only be used within that time window. The rules of
+// the included views, which must have the same scope, are merged into the view's rules. Mode
+// is "enforce", the default, or "audit".
 type viewSpec struct {
-	Rules Rules `json:"rules" validate:"required,dive"`
+	Rules     Rules           `json:"rules" validate:"omitempty,dive"`
+	Includes  []string        `json:"includes,omitempty" validate:"omitempty,dive,resourceNameValidator"`
+	Mode      EnforcementMode `json:"mode,omitempty"`
+	Groups    []string        `json:"groups,omitempty" validate:"omitempty,dive,resourceNameValidator"`
+	NotBefore *time.Time      `json:"notBefore,omitempty"`
+	ExpiresAt *time.Time      `json:"expiresAt,omitempty"`
+}
+
+// viewInfo is stored in the info column of a view. Besides labels and annotations, it holds
+// the groups the view is assigned to and the window in which the view can be used. Disabled
+// is set by the view expiry job once a view has expired, and cleared when the view is updated.
+type viewInfo struct {
+	interfaces.ObjectInfo
+	Groups    []string   `json:"groups,omitempty"`
+	NotBefore *time.Time `json:"notBefore,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Disabled  bool       `json:"disabled,omitempty"`
+}
+
+func (i viewInfo) marshal() []byte {
+	if len(i.Groups) == 0 && i.NotBefore == nil && i.ExpiresAt == nil && !i.Disabled {
+		return i.ObjectInfo.Marshal()
+	}
+	b, err := json.Marshal(i)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+func viewInfoFromJSON(b []byte) viewInfo {
+	var info viewInfo
+	if len(b) > 0 {
+		_ = json.Unmarshal(b, &info)
+	}
+	return info
 }
 
 // Validate performs validation on the view schema and returns any validation errors.
@@ -43,17 +83,33 @@ func (v *viewSchema) Validate() schemaerr.ValidationErrors {
 	}
 	err := schemavalidator.V().Struct(v)
 	if err == nil {
-		// Check for empty rules after struct validation
-		if len(v.Spec.Rules) == 0 {
+		// Check for empty rules after struct validation. A view may consist of includes only.
+		if len(v.Spec.Rules) == 0 && len(v.Spec.Includes) == 0 {
 			validationErrors = append(validationErrors, schemaerr.ErrMissingRequiredAttribute("spec.rules"))
 		}
-		for _, rule := range v.Spec.Rules {
+		for i, rule := range v.Spec.Rules {
 			for _, target := range rule.Targets {
 				err := validateResourceURI(string(target))
 				if err != nil {
 					validationErrors = append(validationErrors, schemaerr.ErrInvalidResourceURI(string(target)+": "+err.Error()))
 				}
 			}
+			if rule.When != "" {
+				if err := ValidateCondition(rule.When); err != nil {
+					validationErrors = append(validationErrors, schemaerr.ErrInvalidValue(fmt.Sprintf("spec.rules[%d].when", i), err.Error()))
+				}
+			}
+			if rule.RequiresApproval && rule.Intent != IntentAllow {
+				validationErrors = append(validationErrors, schemaerr.ErrInvalidValue(fmt.Sprintf("spec.rules[%d].requiresApproval", i), "only allow rules can require approval"))
+			}
+		}
+		switch v.Spec.Mode {
+		case "", EnforcementModeEnforce, EnforcementModeAudit:
+		default:
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("spec.mode", "mode must be enforce or audit"))
+		}
+		if v.Spec.NotBefore != nil && v.Spec.ExpiresAt != nil && !v.Spec.ExpiresAt.After(*v.Spec.NotBefore) {
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidValue("spec.expiresAt", "expiresAt must be after notBefore"))
 		}
 		return validationErrors
 	}
@@ -79,6 +135,10 @@ func (v *viewSchema) Validate() schemaerr.ValidationErrors {
 			validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind(jsonFieldName))
 		case "validateVersion":
 			validationErrors = append(validationErrors, schemaerr.ErrInvalidVersion(jsonFieldName))
+		case "labelsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidLabel(jsonFieldName))
+		case "annotationsValidator":
+			validationErrors = append(validationErrors, schemaerr.ErrInvalidAnnotation(jsonFieldName))
 		case "viewRuleIntentValidator":
 			validationErrors = append(validationErrors, schemaerr.ErrInvalidViewRuleIntent(jsonFieldName))
 		case "viewRuleActionValidator":
@@ -164,10 +224,10 @@ const (
 func createViewModel(ctx context.Context, view *viewSchema, purpose string) (*models.View, apperrors.Error) {
 	viewDef := ViewDefinition{}
 
-	viewDef.Scope.Catalog = view.Metadata.Catalog
-	viewDef.Scope.Variant = view.Metadata.Variant.String()
-	viewDef.Scope.Namespace = view.Metadata.Namespace.String()
+	viewDef.Scope = viewScope(view)
 	viewDef.Rules = view.Spec.Rules
+	viewDef.Includes = removeDuplicates(view.Spec.Includes)
+	viewDef.Mode = view.Spec.Mode
 
 	rulesJSON, err := viewDef.ToJSON()
 	if err != nil {
@@ -180,10 +240,16 @@ func createViewModel(ctx context.Context, view *viewSchema, purpose string) (*mo
 	}
 	principal := "user/" + userContext.UserID
 
+	info := viewInfo{
+		ObjectInfo: interfaces.ObjectInfo{Labels: view.Metadata.Labels, Annotations: view.Metadata.Annotations},
+		Groups:     view.Spec.Groups,
+		NotBefore:  view.Spec.NotBefore,
+		ExpiresAt:  view.Spec.ExpiresAt,
+	}
 	viewModel := &models.View{
 		Label:       view.Metadata.Name,
 		Description: view.Metadata.Description,
-		Info:        nil,
+		Info:        info.marshal(),
 		Rules:       rulesJSON,
 		CatalogID:   view.Metadata.IDS.CatalogID,
 	}
@@ -196,6 +262,15 @@ func createViewModel(ctx context.Context, view *viewSchema, purpose string) (*mo
 	return viewModel, nil
 }
 
+// viewScope returns the scope of the view definition bound to the view metadata.
+func viewScope(view *viewSchema) Scope {
+	return Scope{
+		Catalog:   view.Metadata.Catalog,
+		Variant:   view.Metadata.Variant.String(),
+		Namespace: view.Metadata.Namespace.String(),
+	}
+}
+
 // CreateView creates a new view in the database.
 func CreateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (*models.View, apperrors.Error) {
 	view, err := parseAndValidateView(ctx, resourceJSON, m)
@@ -206,6 +281,10 @@ func CreateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata
 	// Remove duplicates from rules
 	view.Spec.Rules = deduplicateRules(view.Spec.Rules)
 
+	if err := validateIncludes(ctx, view.Metadata.IDS.CatalogID, view.Metadata.Name, viewScope(view), view.Spec.Includes); err != nil {
+		return nil, err
+	}
+
 	v, err := createViewModel(ctx, view, ViewPurposeCreate)
 	if err != nil {
 		return nil, err
@@ -231,6 +310,10 @@ func UpdateView(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata
 
 	view.Spec.Rules = deduplicateRules(view.Spec.Rules)
 
+	if err := validateIncludes(ctx, view.Metadata.IDS.CatalogID, view.Metadata.Name, viewScope(view), view.Spec.Includes); err != nil {
+		return nil, err
+	}
+
 	v, err := createViewModel(ctx, view, ViewPurposeUpdate)
 	if err != nil {
 		return nil, err
@@ -301,12 +384,15 @@ func (v *viewKind) Get(ctx context.Context) ([]byte, apperrors.Error) {
 	v.view = view
 
 	// Convert the view model to JSON
+	info := viewInfoFromJSON(view.Info)
 	viewSchema := &viewSchema{
 		ApiVersion: catcommon.ApiVersion,
 		Kind:       catcommon.ViewKind,
 		Metadata: interfaces.Metadata{
 			Name:        view.Label,
 			Description: view.Description,
+			Labels:      info.Labels,
+			Annotations: info.Annotations,
 		},
 	}
 
@@ -318,6 +404,11 @@ func (v *viewKind) Get(ctx context.Context) ([]byte, apperrors.Error) {
 	}
 
 	viewSchema.Spec.Rules = viewDef.Rules
+	viewSchema.Spec.Includes = viewDef.Includes
+	viewSchema.Spec.Mode = viewDef.Mode
+	viewSchema.Spec.Groups = info.Groups
+	viewSchema.Spec.NotBefore = info.NotBefore
+	viewSchema.Spec.ExpiresAt = info.ExpiresAt
 
 	if viewDef.Scope.Catalog != v.reqCtx.Catalog {
 		return nil, ErrInvalidView.New("view catalog does not match request catalog")
@@ -346,7 +437,16 @@ func (v *viewKind) Delete(ctx context.Context) apperrors.Error {
 		return ErrInvalidView
 	}
 
-	err := db.DB(ctx).DeleteViewByLabel(ctx, v.reqCtx.ObjectName, v.reqCtx.CatalogID)
+	// Deleting an included view would leave the views that include it unable to load
+	including, err := includingViews(ctx, v.reqCtx.CatalogID, v.reqCtx.ObjectName)
+	if err != nil {
+		return err
+	}
+	if len(including) > 0 {
+		return ErrViewInUse.New("view is included by " + strings.Join(including, ", "))
+	}
+
+	err = db.DB(ctx).DeleteViewByLabel(ctx, v.reqCtx.ObjectName, v.reqCtx.CatalogID)
 	if err != nil {
 		if errors.Is(err, dberror.ErrNotFound) {
 			return nil
@@ -390,29 +490,29 @@ func (v *viewKind) List(ctx context.Context) ([]byte, apperrors.Error) {
 		return nil, ErrUnableToLoadObject.Msg("unable to load view")
 	}
 
-	type viewItem struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
-	}
-
-	type viewListRsp struct {
-		Views []viewItem `json:"views"`
-	}
-
-	viewsRsp := viewListRsp{
-		Views: []viewItem{},
-	}
+	visible := make([]*models.View, 0, len(views))
 	for _, view := range views {
 		if strings.HasPrefix(view.Label, "_") {
 			continue
 		}
-		viewsRsp.Views = append(viewsRsp.Views, viewItem{
+		visible = append(visible, view)
+	}
+	visible = interfaces.FilterByLabels(visible, func(view *models.View) map[string]string {
+		return interfaces.ObjectInfoFromJSON(view.Info).Labels
+	}, v.reqCtx.ListOptions.LabelSelector)
+
+	page, next := interfaces.Paginate(visible, func(view *models.View) string { return view.Label }, v.reqCtx.ListOptions)
+
+	items := make([]interfaces.ListItem, 0, len(page))
+	for _, view := range page {
+		items = append(items, interfaces.ListItem{
 			Name:        view.Label,
 			Description: view.Description,
+			Labels:      interfaces.ObjectInfoFromJSON(view.Info).Labels,
 		})
 	}
 
-	jsonData, e := json.Marshal(viewsRsp)
+	jsonData, e := interfaces.MarshalList(catcommon.KindNameViews, items, next)
 	if e != nil {
 		log.Ctx(ctx).Error().Err(e).Msg("failed to marshal view list")
 		return nil, ErrUnableToLoadObject.Msg("unable to marshal view list")
@@ -2,6 +2,7 @@ package policy
 
 import (
 	"fmt"
+	"path"
 	"slices"
 	"strings"
 
@@ -48,17 +49,21 @@ func validateViewRuleAction(fl validator.FieldLevel) bool {
 //
 // Returns an error if the URI is invalid, with a descriptive message about what went wrong.
 //
+// Path segments may be the wildcards "*" and "**" or glob patterns such as "prod-*" or
+// "team-[a-c]", but the kind may not.
+//
 // Example valid inputs:
 //   - "res://catalogs/my-catalog"
 //   - "res://variants/my-variant/namespaces/my-namespace"
 //   - "res://resources/my-resource/properties/definition"
+//   - "res://variants/*/resources/**"
 func validateResourceURI(uri string) error {
 	const prefix = "res://"
 	if len(uri) < len(prefix) || uri[:len(prefix)] != prefix {
 		return fmt.Errorf("invalid resource URI: must start with %s", prefix)
 	}
 	rest := uri[len(prefix):]
-	if rest == "" || rest == "*" || rest == "." {
+	if rest == "" || rest == "*" || rest == "**" || rest == "." {
 		return nil
 	}
 
@@ -86,10 +91,13 @@ func validateResourceURI(uri string) error {
 				return fmt.Errorf("invalid resource URI: empty path segment at position %d", i+1)
 			}
 
-			// Wildcard is only allowed as the last segment
-			if segment == "*" {
-				if i != len(segments)-1 {
-					return fmt.Errorf("invalid resource URI: wildcard (*) is only allowed as the last path segment")
+			// Wildcards and glob patterns
+			if segment == "**" || segment == "*" {
+				continue
+			}
+			if isGlobSegment(segment) {
+				if !validateGlobSegment(segment) {
+					return fmt.Errorf("invalid resource URI: invalid pattern %q at position %d", segment, i+1)
 				}
 				continue
 			}
@@ -103,3 +111,23 @@ func validateResourceURI(uri string) error {
 
 	return nil
 }
+
+// validateGlobSegment checks that a glob pattern is well formed and otherwise only contains
+// characters allowed in names. "**" must be a segment on its own.
+func validateGlobSegment(segment string) bool {
+	if strings.Contains(segment, "**") {
+		return false
+	}
+	if _, err := path.Match(segment, ""); err != nil {
+		return false
+	}
+	for _, c := range segment {
+		switch {
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		case strings.ContainsRune("-*?[]^", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
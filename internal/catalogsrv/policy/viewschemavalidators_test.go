@@ -156,16 +156,46 @@ func TestResourceURIValidator(t *testing.T) {
 		{
 			name:    "wildcard in middle of path",
 			input:   "res://catalogs/*/variants",
-			isValid: false,
+			isValid: true,
 		},
 		{
 			name:    "wildcard followed by segments",
 			input:   "res://catalogs/*/my-catalog",
-			isValid: false,
+			isValid: true,
 		},
 		{
 			name:    "multiple wildcards",
 			input:   "res://catalogs/*/variants/*",
+			isValid: true,
+		},
+		{
+			name:    "double wildcard",
+			input:   "res://catalogs/my-catalog/**/resources/config",
+			isValid: true,
+		},
+		{
+			name:    "segment glob with character class",
+			input:   "res://catalogs/my-catalog/variants/prod-[a-c]*",
+			isValid: true,
+		},
+		{
+			name:    "double wildcard within a segment",
+			input:   "res://catalogs/my-catalog/variants/prod-**",
+			isValid: false,
+		},
+		{
+			name:    "unterminated character class",
+			input:   "res://catalogs/my-catalog/variants/v[1-3",
+			isValid: false,
+		},
+		{
+			name:    "glob with invalid characters",
+			input:   "res://catalogs/my-catalog/variants/my_*",
+			isValid: false,
+		},
+		{
+			name:    "wildcard as kind",
+			input:   "res://*/my-catalog",
 			isValid: false,
 		},
 		{
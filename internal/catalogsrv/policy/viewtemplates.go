@@ -0,0 +1,372 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	schemaerr "github.com/tansive/tansive-internal/internal/catalogsrv/schema/errors"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tansive/tansive-internal/pkg/types"
+)
+
+// viewTemplateSchema represents the structure of a view template. The targets of its rules may
+// contain the placeholders {{catalog}}, {{variant}} and {{namespace}}, which are filled in when a
+// view is instantiated from the template. Templates, like views, belong to a catalog.
+type viewTemplateSchema struct {
+	ApiVersion string              `json:"apiVersion" validate:"required,validateVersion"`
+	Kind       string              `json:"kind" validate:"required,kindValidator"`
+	Metadata   interfaces.Metadata `json:"metadata" validate:"required"`
+	Spec       viewTemplateSpec    `json:"spec" validate:"required"`
+}
+
+// viewTemplateSpec is the spec of a view template, which becomes the spec of the views
+// instantiated from it
+type viewTemplateSpec struct {
+	Rules  Rules    `json:"rules"`
+	Groups []string `json:"groups,omitempty"`
+}
+
+// ViewTemplateParams are the values of the placeholders when a view is instantiated from a template
+type ViewTemplateParams struct {
+	Catalog   string `json:"-"`
+	Variant   string `json:"variant,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+var templatePlaceholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]*)\s*\}\}`)
+
+// render returns the rules of the template with the placeholders in their targets replaced by params
+func (s viewTemplateSpec) render(params ViewTemplateParams) (Rules, error) {
+	values := map[string]string{
+		"catalog":   params.Catalog,
+		"variant":   params.Variant,
+		"namespace": params.Namespace,
+	}
+	rules := s.Rules.DeepCopy()
+	for i := range rules {
+		for j, target := range rules[i].Targets {
+			var renderErr error
+			rendered := templatePlaceholderRegex.ReplaceAllStringFunc(string(target), func(placeholder string) string {
+				name := templatePlaceholderRegex.FindStringSubmatch(placeholder)[1]
+				value, ok := values[name]
+				if !ok {
+					renderErr = errors.New("unknown placeholder " + placeholder)
+				} else if value == "" && renderErr == nil {
+					renderErr = errors.New("no value for placeholder " + placeholder)
+				}
+				return value
+			})
+			if renderErr != nil {
+				return nil, renderErr
+			}
+			rules[i].Targets[j] = TargetResource(rendered)
+		}
+	}
+	return rules, nil
+}
+
+// Validate performs validation on the view template and returns any validation errors. The
+// rules are validated as those of a view, with sample values in place of the placeholders.
+func (t *viewTemplateSchema) Validate() schemaerr.ValidationErrors {
+	var validationErrors schemaerr.ValidationErrors
+	if t.Kind != catcommon.ViewTemplateKind {
+		validationErrors = append(validationErrors, schemaerr.ErrUnsupportedKind("kind"))
+	}
+
+	rules, err := t.Spec.render(ViewTemplateParams{
+		Catalog:   "catalog",
+		Variant:   "variant",
+		Namespace: "namespace",
+	})
+	if err != nil {
+		return append(validationErrors, schemaerr.ErrInvalidValue("spec.rules", err.Error()))
+	}
+
+	view := &viewSchema{
+		ApiVersion: t.ApiVersion,
+		Kind:       catcommon.ViewKind,
+		Metadata:   t.Metadata,
+		Spec: viewSpec{
+			Rules:  rules,
+			Groups: t.Spec.Groups,
+		},
+	}
+	return append(validationErrors, view.Validate()...)
+}
+
+// parseAndValidateViewTemplate parses a JSON byte slice into a viewTemplateSchema, validates it,
+// and binds it to the catalog in m.
+func parseAndValidateViewTemplate(ctx context.Context, resourceJSON []byte, m *interfaces.Metadata) (*viewTemplateSchema, apperrors.Error) {
+	template := &viewTemplateSchema{}
+	if err := json.Unmarshal(resourceJSON, template); err != nil {
+		return nil, ErrInvalidView.Msg("failed to parse view template spec")
+	}
+
+	if m.Catalog != "" {
+		template.Metadata.Catalog = m.Catalog
+	}
+	template.Metadata.Variant = types.NullableString{}
+	template.Metadata.Namespace = types.NullableString{}
+
+	if err := template.Validate(); err != nil {
+		return nil, ErrInvalidSchema.Err(err)
+	}
+
+	template.Metadata.IDS.CatalogID = m.IDS.CatalogID
+	if err := resolveMetadataIDS(ctx, &template.Metadata); err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func createViewTemplateModel(ctx context.Context, template *viewTemplateSchema, purpose string) (*models.ViewTemplate, apperrors.Error) {
+	template.Spec.Rules = deduplicateRules(template.Spec.Rules)
+	specJSON, err := json.Marshal(template.Spec)
+	if err != nil {
+		return nil, ErrInvalidView.New("failed to marshal view template spec: " + err.Error())
+	}
+
+	userContext := catcommon.GetUserContext(ctx)
+	if userContext == nil || userContext.UserID == "" {
+		return nil, dberror.ErrMissingUserContext.Msg("missing user context")
+	}
+	principal := "user/" + userContext.UserID
+
+	info := interfaces.ObjectInfo{Labels: template.Metadata.Labels, Annotations: template.Metadata.Annotations}
+	templateModel := &models.ViewTemplate{
+		Label:       template.Metadata.Name,
+		Description: template.Metadata.Description,
+		Info:        info.Marshal(),
+		Spec:        specJSON,
+		CatalogID:   template.Metadata.IDS.CatalogID,
+	}
+	if purpose == ViewPurposeCreate {
+		templateModel.CreatedBy = principal
+	}
+	if purpose == ViewPurposeUpdate {
+		templateModel.UpdatedBy = principal
+	}
+	return templateModel, nil
+}
+
+// InstantiateViewTemplate renders a view template with params and creates the resulting view
+// with the given name. The view is scoped to the catalog, so that the rendered targets alone
+// determine which variants and namespaces it covers.
+func InstantiateViewTemplate(ctx context.Context, catalogID uuid.UUID, templateName, viewName string, params ViewTemplateParams) (*models.View, apperrors.Error) {
+	if params.Catalog == "" || catalogID == uuid.Nil {
+		return nil, ErrInvalidCatalog
+	}
+
+	t, err := db.DB(ctx).GetViewTemplateByLabel(ctx, templateName, catalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrViewTemplateNotFound.New("view template not found: " + templateName)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load view template")
+		return nil, ErrUnableToLoadObject.Msg("unable to load view template")
+	}
+
+	spec := viewTemplateSpec{}
+	if err := json.Unmarshal(t.Spec, &spec); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to unmarshal view template spec")
+		return nil, ErrUnableToLoadObject.Msg("unable to unmarshal view template spec")
+	}
+	rules, goerr := spec.render(params)
+	if goerr != nil {
+		return nil, ErrInvalidView.Msg(goerr.Error())
+	}
+
+	info := interfaces.ObjectInfoFromJSON(t.Info)
+	view := &viewSchema{
+		ApiVersion: catcommon.ApiVersion,
+		Kind:       catcommon.ViewKind,
+		Metadata: interfaces.Metadata{
+			Name:        viewName,
+			Catalog:     params.Catalog,
+			Description: t.Description,
+			Labels:      info.Labels,
+			Annotations: info.Annotations,
+		},
+		Spec: viewSpec{
+			Rules:  rules,
+			Groups: spec.Groups,
+		},
+	}
+	viewJSON, goerr := json.Marshal(view)
+	if goerr != nil {
+		return nil, ErrInvalidView.Msg("failed to render view: " + goerr.Error())
+	}
+
+	m := &interfaces.Metadata{Catalog: params.Catalog}
+	m.IDS.CatalogID = catalogID
+	return CreateView(ctx, viewJSON, m)
+}
+
+type viewTemplateKind struct {
+	reqCtx   interfaces.RequestContext
+	template *models.ViewTemplate
+}
+
+// Location returns the location path of the view template.
+func (v *viewTemplateKind) Location() string {
+	return "/viewtemplates/" + v.template.Label
+}
+
+func (v *viewTemplateKind) metadata() *interfaces.Metadata {
+	m := &interfaces.Metadata{Catalog: v.reqCtx.Catalog}
+	m.IDS.CatalogID = v.reqCtx.CatalogID
+	return m
+}
+
+// Create creates a new view template.
+func (v *viewTemplateKind) Create(ctx context.Context, resourceJSON []byte) (string, apperrors.Error) {
+	template, err := parseAndValidateViewTemplate(ctx, resourceJSON, v.metadata())
+	if err != nil {
+		return "", err
+	}
+	t, err := createViewTemplateModel(ctx, template, ViewPurposeCreate)
+	if err != nil {
+		return "", err
+	}
+
+	if err := db.DB(ctx).CreateViewTemplate(ctx, t); err != nil {
+		if errors.Is(err, dberror.ErrAlreadyExists) {
+			return "", ErrAlreadyExists.New("view template already exists: " + t.Label)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to create view template")
+		return "", ErrViewError.New("failed to create view template: " + err.Error())
+	}
+	v.template = t
+	return v.Location(), nil
+}
+
+// Get retrieves a view template by its name.
+func (v *viewTemplateKind) Get(ctx context.Context) ([]byte, apperrors.Error) {
+	if v.reqCtx.ObjectName == "" {
+		return nil, ErrInvalidView
+	}
+
+	t, err := db.DB(ctx).GetViewTemplateByLabel(ctx, v.reqCtx.ObjectName, v.reqCtx.CatalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrViewTemplateNotFound
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load view template")
+		return nil, ErrUnableToLoadObject.Msg("unable to load view template")
+	}
+	v.template = t
+
+	info := interfaces.ObjectInfoFromJSON(t.Info)
+	template := &viewTemplateSchema{
+		ApiVersion: catcommon.ApiVersion,
+		Kind:       catcommon.ViewTemplateKind,
+		Metadata: interfaces.Metadata{
+			Name:        t.Label,
+			Catalog:     v.reqCtx.Catalog,
+			Description: t.Description,
+			Labels:      info.Labels,
+			Annotations: info.Annotations,
+		},
+	}
+	if err := json.Unmarshal(t.Spec, &template.Spec); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to unmarshal view template spec")
+		return nil, ErrUnableToLoadObject.Msg("unable to unmarshal view template spec")
+	}
+
+	jsonData, e := json.Marshal(template)
+	if e != nil {
+		log.Ctx(ctx).Error().Err(e).Msg("failed to marshal view template")
+		return nil, ErrUnableToLoadObject.Msg("unable to fetch view template")
+	}
+	return jsonData, nil
+}
+
+// Update modifies an existing view template. Views already instantiated from it are not changed.
+func (v *viewTemplateKind) Update(ctx context.Context, resourceJSON []byte) apperrors.Error {
+	template, err := parseAndValidateViewTemplate(ctx, resourceJSON, v.metadata())
+	if err != nil {
+		return err
+	}
+	t, err := createViewTemplateModel(ctx, template, ViewPurposeUpdate)
+	if err != nil {
+		return err
+	}
+
+	if err := db.DB(ctx).UpdateViewTemplate(ctx, t); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return ErrViewTemplateNotFound.New("view template not found: " + t.Label)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to update view template")
+		return ErrViewError.New("failed to update view template: " + err.Error())
+	}
+	v.template = t
+	return nil
+}
+
+// Delete removes a view template. Views instantiated from it are not deleted.
+func (v *viewTemplateKind) Delete(ctx context.Context) apperrors.Error {
+	if v.reqCtx.ObjectName == "" {
+		return ErrInvalidView
+	}
+
+	err := db.DB(ctx).DeleteViewTemplateByLabel(ctx, v.reqCtx.ObjectName, v.reqCtx.CatalogID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("failed to delete view template")
+		return ErrUnableToDeleteObject.Msg("unable to delete view template")
+	}
+	return nil
+}
+
+// List returns the view templates of the catalog.
+func (v *viewTemplateKind) List(ctx context.Context) ([]byte, apperrors.Error) {
+	templates, err := db.DB(ctx).ListViewTemplatesByCatalog(ctx, v.reqCtx.CatalogID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to load view templates")
+		return nil, ErrUnableToLoadObject.Msg("unable to load view templates")
+	}
+
+	templates = interfaces.FilterByLabels(templates, func(t *models.ViewTemplate) map[string]string {
+		return interfaces.ObjectInfoFromJSON(t.Info).Labels
+	}, v.reqCtx.ListOptions.LabelSelector)
+
+	page, next := interfaces.Paginate(templates, func(t *models.ViewTemplate) string { return t.Label }, v.reqCtx.ListOptions)
+
+	items := make([]interfaces.ListItem, 0, len(page))
+	for _, t := range page {
+		items = append(items, interfaces.ListItem{
+			Name:        t.Label,
+			Description: t.Description,
+			Labels:      interfaces.ObjectInfoFromJSON(t.Info).Labels,
+		})
+	}
+
+	jsonData, e := interfaces.MarshalList(catcommon.KindNameViewTemplates, items, next)
+	if e != nil {
+		log.Ctx(ctx).Error().Err(e).Msg("failed to marshal view template list")
+		return nil, ErrUnableToLoadObject.Msg("unable to marshal view template list")
+	}
+	return jsonData, nil
+}
+
+// NewViewTemplateKindHandler creates a new view template resource manager.
+func NewViewTemplateKindHandler(ctx context.Context, reqCtx interfaces.RequestContext) (interfaces.KindHandler, apperrors.Error) {
+	if reqCtx.Catalog == "" || reqCtx.CatalogID == uuid.Nil {
+		return nil, ErrInvalidCatalog
+	}
+	return &viewTemplateKind{
+		reqCtx: reqCtx,
+	}, nil
+}
@@ -0,0 +1,72 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager/interfaces"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+)
+
+func TestViewTemplateRender(t *testing.T) {
+	spec := viewTemplateSpec{
+		Rules: Rules{
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionResourceRead},
+				Targets: []TargetResource{"res://variants/{{variant}}/namespaces/{{ namespace }}/resources/*"},
+			},
+			{
+				Intent:  IntentAllow,
+				Actions: []Action{ActionCatalogAdoptView},
+				Targets: []TargetResource{"res://views/{{namespace}}-reader"},
+			},
+		},
+	}
+
+	rules, err := spec.render(ViewTemplateParams{Catalog: "c", Variant: "prod", Namespace: "dev"})
+	require.NoError(t, err)
+	assert.Equal(t, []TargetResource{"res://variants/prod/namespaces/dev/resources/*"}, rules[0].Targets)
+	assert.Equal(t, []TargetResource{"res://views/dev-reader"}, rules[1].Targets)
+	// The template itself is not modified
+	assert.Equal(t, TargetResource("res://views/{{namespace}}-reader"), spec.Rules[1].Targets[0])
+
+	_, err = spec.render(ViewTemplateParams{Catalog: "c", Variant: "prod"})
+	assert.Error(t, err)
+
+	spec.Rules[0].Targets[0] = "res://variants/{{project}}/resources/*"
+	_, err = spec.render(ViewTemplateParams{Catalog: "c", Variant: "prod", Namespace: "dev"})
+	assert.Error(t, err)
+}
+
+func TestViewTemplateValidate(t *testing.T) {
+	template := &viewTemplateSchema{
+		ApiVersion: catcommon.ApiVersion,
+		Kind:       catcommon.ViewTemplateKind,
+		Metadata: interfaces.Metadata{
+			Name:    "ns-reader",
+			Catalog: "test-catalog",
+		},
+		Spec: viewTemplateSpec{
+			Rules: Rules{
+				{
+					Intent:  IntentAllow,
+					Actions: []Action{ActionResourceRead},
+					Targets: []TargetResource{"res://namespaces/{{namespace}}/resources/*"},
+				},
+			},
+		},
+	}
+	assert.Empty(t, template.Validate())
+
+	template.Spec.Rules[0].Targets[0] = "res://namespaces/{{unknown}}"
+	assert.NotEmpty(t, template.Validate())
+
+	template.Spec.Rules[0].Targets[0] = "namespaces/{{namespace}}"
+	assert.NotEmpty(t, template.Validate())
+
+	template.Spec.Rules[0].Targets[0] = "res://namespaces/{{namespace}}"
+	template.Kind = catcommon.ViewKind
+	assert.NotEmpty(t, template.Validate())
+}
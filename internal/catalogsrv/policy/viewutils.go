@@ -42,9 +42,11 @@ func deduplicateRules(rules Rules) Rules {
 	result := make(Rules, len(rules))
 	for i, rule := range rules {
 		result[i] = Rule{
-			Intent:  rule.Intent,
-			Actions: removeDuplicates(rule.Actions),
-			Targets: removeDuplicates(rule.Targets),
+			Intent:           rule.Intent,
+			Actions:          removeDuplicates(rule.Actions),
+			Targets:          removeDuplicates(rule.Targets),
+			When:             rule.When,
+			RequiresApproval: rule.RequiresApproval,
 		}
 	}
 	return result
@@ -75,6 +77,10 @@ func canonicalizeResourcePath(scope Scope, resource TargetResource) TargetResour
 	if canonicalized == "." || canonicalized == "/" {
 		canonicalized = ""
 	}
+	// consecutive "**" segments match the same paths as one
+	for strings.Contains(canonicalized+"/", "**/**/") {
+		canonicalized = strings.TrimSuffix(strings.Replace(canonicalized+"/", "**/**/", "**/", 1), "/")
+	}
 	canonicalized = "res://" + canonicalized
 
 	return TargetResource(canonicalized)
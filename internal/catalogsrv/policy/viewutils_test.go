@@ -220,6 +220,14 @@ func TestCanonicalizeResourcePath(t *testing.T) {
 			resource: "res://resources/my-resource/properties/definition/schema",
 			want:     "res://catalogs/my-catalog/variants/my-variant/namespaces/my-namespace/resources/my-resource/properties/definition/schema",
 		},
+		{
+			name: "resource with consecutive double wildcards",
+			scope: Scope{
+				Catalog: "my-catalog",
+			},
+			resource: "res://resources/**/**/config",
+			want:     "res://catalogs/my-catalog/resources/**/config",
+		},
 		{
 			name: "resource without res:// prefix",
 			scope: Scope{
@@ -0,0 +1,86 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+)
+
+// ViewValidationResult is the outcome of validating a view document without applying it.
+// Errors lists why the view could not be created. Violations lists what the view allows
+// beyond its parent.
+type ViewValidationResult struct {
+	Name       string              `json:"name,omitempty"`
+	Valid      bool                `json:"valid"`
+	Errors     []string            `json:"errors,omitempty"`
+	Violations []ViewRuleViolation `json:"violations,omitempty"`
+}
+
+// ViewRuleViolation is a RuleViolation of a view document. Rule indexes spec.rules, or, for
+// rules merged from an included view, the merged rules of the view named by Include.
+type ViewRuleViolation struct {
+	RuleViolation
+	Include string `json:"include,omitempty"`
+}
+
+// ValidateViewDocument validates a view document for the catalog in ctx the way creating it
+// would, without creating it. If parent is not nil, the rules of the view, including those
+// of the views it includes, are also checked against the parent as ValidateDerivedView does,
+// and every action and target the parent does not allow is reported.
+func ValidateViewDocument(ctx context.Context, doc []byte, parent *ViewDefinition) *ViewValidationResult {
+	result := &ViewValidationResult{}
+	view := &viewSchema{}
+	if err := json.Unmarshal(doc, view); err != nil {
+		result.Errors = append(result.Errors, "failed to parse view spec: "+err.Error())
+		return result
+	}
+	result.Name = view.Metadata.Name
+
+	catalog := catcommon.GetCatalog(ctx)
+	if view.Metadata.Catalog != "" && view.Metadata.Catalog != catalog {
+		result.Errors = append(result.Errors, "view targets catalog "+view.Metadata.Catalog+" outside the current catalog "+catalog)
+		return result
+	}
+	view.Metadata.Catalog = catalog
+	view.Metadata.IDS.CatalogID = catcommon.GetCatalogID(ctx)
+
+	if verrs := view.Validate(); len(verrs) > 0 {
+		for _, verr := range verrs {
+			result.Errors = append(result.Errors, verr.Error())
+		}
+		return result
+	}
+	if err := resolveMetadataIDS(ctx, &view.Metadata); err != nil {
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	scope := viewScope(view)
+	included := make([]Rules, len(view.Spec.Includes))
+	for i, label := range view.Spec.Includes {
+		rules, err := resolveIncludes(ctx, view.Metadata.IDS.CatalogID, scope, []string{label}, []string{view.Metadata.Name})
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			return result
+		}
+		included[i] = rules
+	}
+
+	if parent != nil {
+		parentRules := canonicalizeViewDefinition(parent).Rules
+		violations := func(rules Rules, include string) {
+			child := canonicalizeViewDefinition(&ViewDefinition{Scope: scope, Rules: rules})
+			for _, v := range child.Rules.subsetViolations(parentRules) {
+				result.Violations = append(result.Violations, ViewRuleViolation{RuleViolation: v, Include: include})
+			}
+		}
+		violations(view.Spec.Rules, "")
+		for i, label := range view.Spec.Includes {
+			violations(included[i], label)
+		}
+	}
+
+	result.Valid = len(result.Violations) == 0
+	return result
+}
@@ -123,7 +123,15 @@ func ErrInvalidAnnotation(attr string, value ...string) ValidationError {
 	return ValidationError{
 		Field:  attr,
 		Value:  value,
-		ErrStr: "annotation keys cannot contain spaces",
+		ErrStr: "invalid annotation key",
+	}
+}
+
+func ErrInvalidLabel(attr string, value ...string) ValidationError {
+	return ValidationError{
+		Field:  attr,
+		Value:  value,
+		ErrStr: "invalid label key or value",
 	}
 }
 
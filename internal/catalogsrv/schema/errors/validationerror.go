@@ -4,6 +4,8 @@ package errors
 import (
 	"bytes"
 	"strings"
+
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
 )
 
 // ValidationError represents an error that occurs during validation.
@@ -22,6 +24,11 @@ func (ve ValidationError) Error() string {
 	}
 }
 
+// FieldErrors reports the error as a field error of the request.
+func (ve ValidationError) FieldErrors() []apperrors.FieldError {
+	return []apperrors.FieldError{{Field: ve.Field, Message: ve.ErrStr}}
+}
+
 // ErrInvalidSchema is an error indicating that the schema is invalid.
 var ErrInvalidSchema = ValidationError{
 	Field:  "invalid input",
@@ -44,6 +51,15 @@ func (ves ValidationErrors) Error() string {
 	return strings.TrimSpace(buff.String())
 }
 
+// FieldErrors reports each error as a field error of the request.
+func (ves ValidationErrors) FieldErrors() []apperrors.FieldError {
+	fields := make([]apperrors.FieldError, 0, len(ves))
+	for _, ve := range ves {
+		fields = append(fields, ve.FieldErrors()...)
+	}
+	return fields
+}
+
 // inQuotes returns the string s surrounded by double quotes.
 func InQuotes(s string) string {
 	return "'" + s + "'"
@@ -23,6 +23,8 @@ var validKinds = []string{
 	catcommon.ResourceKind,
 	catcommon.SkillSetKind,
 	catcommon.ViewKind,
+	catcommon.ViewTemplateKind,
+	catcommon.AdmissionWebhookKind,
 }
 
 // kindValidator checks if the given kind is a valid resource kind.
@@ -196,6 +198,66 @@ func JsonSchemaValidator(fl validator.FieldLevel) bool {
 	return err == nil
 }
 
+// Label and annotation keys follow the Kubernetes convention: an optional DNS subdomain
+// prefix followed by a slash, and a name of at most 63 characters.
+const (
+	labelNameRegex       = `^[A-Za-z0-9]([-A-Za-z0-9_.]*[A-Za-z0-9])?$`
+	labelPrefixRegex     = `^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`
+	labelNameMaxLength   = 63
+	labelPrefixMaxLength = 253
+	labelValueMaxLength  = 63
+)
+
+var (
+	labelNameRe   = regexp.MustCompile(labelNameRegex)
+	labelPrefixRe = regexp.MustCompile(labelPrefixRegex)
+)
+
+// ValidateLabelKey reports whether key is a valid label or annotation key.
+func ValidateLabelKey(key string) bool {
+	name := key
+	if prefix, n, ok := strings.Cut(key, "/"); ok {
+		if len(prefix) == 0 || len(prefix) > labelPrefixMaxLength || !labelPrefixRe.MatchString(prefix) {
+			return false
+		}
+		name = n
+	}
+	return len(name) > 0 && len(name) <= labelNameMaxLength && labelNameRe.MatchString(name)
+}
+
+// ValidateLabelValue reports whether value is a valid label value. Empty values are allowed.
+func ValidateLabelValue(value string) bool {
+	return value == "" || (len(value) <= labelValueMaxLength && labelNameRe.MatchString(value))
+}
+
+// labelsValidator checks the keys and values of a label map.
+func labelsValidator(fl validator.FieldLevel) bool {
+	labels, ok := fl.Field().Interface().(map[string]string)
+	if !ok {
+		return false
+	}
+	for k, v := range labels {
+		if !ValidateLabelKey(k) || !ValidateLabelValue(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// annotationsValidator checks the keys of an annotation map. Annotation values are not restricted.
+func annotationsValidator(fl validator.FieldLevel) bool {
+	annotations, ok := fl.Field().Interface().(map[string]string)
+	if !ok {
+		return false
+	}
+	for k := range annotations {
+		if !ValidateLabelKey(k) {
+			return false
+		}
+	}
+	return true
+}
+
 func init() {
 	V().RegisterValidation("kindValidator", kindValidator)
 	V().RegisterValidation("resourceNameValidator", resourceNameValidator)
@@ -206,4 +268,6 @@ func init() {
 	V().RegisterValidation("skillPathValidator", skillPathValidator)
 	V().RegisterValidation("jsonSchemaValidator", JsonSchemaValidator)
 	V().RegisterValidation("validateVersion", validateVersion)
+	V().RegisterValidation("labelsValidator", labelsValidator)
+	V().RegisterValidation("annotationsValidator", annotationsValidator)
 }
@@ -1,6 +1,7 @@
 package schemavalidator
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/go-playground/validator/v10"
@@ -60,3 +61,31 @@ func TestNoSpacesValidator(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateLabelKeyAndValue(t *testing.T) {
+	validKeys := []string{"env", "Team_1", "app.kubernetes.io", "tansive.io/owner", "a"}
+	invalidKeys := []string{"", "-env", "env-", "has space", "/env", "Tansive.IO/owner", "tansive.io/", strings.Repeat("a", 64)}
+	for _, k := range validKeys {
+		if !ValidateLabelKey(k) {
+			t.Errorf("expected key %q to be valid", k)
+		}
+	}
+	for _, k := range invalidKeys {
+		if ValidateLabelKey(k) {
+			t.Errorf("expected key %q to be invalid", k)
+		}
+	}
+
+	validValues := []string{"", "prod", "v1.2.3", "a_b-c"}
+	invalidValues := []string{"prod us", "-prod", "a/b", strings.Repeat("a", 64)}
+	for _, v := range validValues {
+		if !ValidateLabelValue(v) {
+			t.Errorf("expected value %q to be valid", v)
+		}
+	}
+	for _, v := range invalidValues {
+		if ValidateLabelValue(v) {
+			t.Errorf("expected value %q to be invalid", v)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tidwall/gjson"
+)
+
+func TestAdmissionWebhooks(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	// The webhooks listen on the loopback address
+	config.Config().AdmissionWebhooks.AllowedNetworks = []string{"127.0.0.0/8"}
+	defer func() { config.Config().AdmissionWebhooks.AllowedNetworks = nil }()
+
+	// Requests to webhooks are signed with the key served in the JWKS
+	httpReq, _ := http.NewRequest("GET", "/.well-known/jwks.json", nil)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	publicKey, err := base64.RawURLEncoding.DecodeString(gjson.Get(response.Body.String(), "keys.0.x").String())
+	require.NoError(t, err)
+	signed := func(r *http.Request, body []byte) bool {
+		signature, err := base64.StdEncoding.DecodeString(r.Header.Get("X-Tansive-Signature"))
+		if err != nil {
+			return false
+		}
+		message := append([]byte(r.Header.Get("X-Tansive-Signature-Timestamp")+"."), body...)
+		return ed25519.Verify(ed25519.PublicKey(publicKey), message, signature)
+	}
+
+	// The mutating webhook upper-cases string values and the validating webhook rejects the
+	// value FORBIDDEN
+	var mu sync.Mutex
+	var operations [][]string
+	mutating := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := gjson.ParseBytes(body)
+		value := req.Get("object.spec.value")
+		rsp := map[string]any{"allowed": true}
+		if value.Type == gjson.String {
+			rsp["value"] = strings.ToUpper(value.String())
+		}
+		json.NewEncoder(w).Encode(rsp)
+	}))
+	defer mutating.Close()
+	validating := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !signed(r, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		req := gjson.ParseBytes(body)
+		var ops []string
+		for _, op := range req.Get("operations").Array() {
+			ops = append(ops, op.String())
+		}
+		mu.Lock()
+		operations = append(operations, ops)
+		mu.Unlock()
+		if req.Get("object.spec.value").String() == "FORBIDDEN" {
+			json.NewEncoder(w).Encode(map[string]any{"allowed": false, "message": "forbidden value"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"allowed": true})
+	}))
+	defer validating.Close()
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	request := func(method, path, body string) (int, string) {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		if body != "" {
+			setRequestBodyAndHeader(t, httpReq, body)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		return response.Code, response.Body.String()
+	}
+	webhook := func(name, url, webhookType, operations, failurePolicy string) string {
+		return `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "AdmissionWebhook",
+				"metadata": {
+					"name": "` + name + `",
+					"catalog": "test-catalog"
+				},
+				"spec": {
+					"url": "` + url + `",
+					"type": "` + webhookType + `",
+					"operations": ` + operations + `,
+					"timeoutSeconds": 1,
+					"failurePolicy": "` + failurePolicy + `"
+				}
+			}`
+	}
+
+	code, body := request(http.MethodPost, "/admissionwebhooks", webhook("upper", mutating.URL, "Mutating", `["value"]`, "Fail"))
+	require.Equal(t, http.StatusCreated, code, body)
+	code, body = request(http.MethodPost, "/admissionwebhooks", webhook("no-forbidden", validating.URL, "Validating", `["schema", "value"]`, "Fail"))
+	require.Equal(t, http.StatusCreated, code, body)
+	code, _ = request(http.MethodPost, "/admissionwebhooks", webhook("upper", mutating.URL, "Mutating", `["value"]`, "Fail"))
+	assert.Equal(t, http.StatusConflict, code)
+	code, _ = request(http.MethodPost, "/admissionwebhooks", webhook("bad-url", "ftp://example.com", "Validating", `["value"]`, "Fail"))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = request(http.MethodPost, "/admissionwebhooks", webhook("bad-type", validating.URL, "Auditing", `["value"]`, "Fail"))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = request(http.MethodPost, "/admissionwebhooks", webhook("bad-operation", validating.URL, "Validating", `["delete"]`, "Fail"))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, body = request(http.MethodGet, "/admissionwebhooks/upper", "")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, "AdmissionWebhook", gjson.Get(body, "kind").String())
+	assert.Equal(t, mutating.URL, gjson.Get(body, "spec.url").String())
+	code, body = request(http.MethodGet, "/admissionwebhooks", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "no-forbidden")
+
+	// Creating a resource is a schema and value operation, and its value is mutated
+	code, body = request(http.MethodPost, "/resources", `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "region",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"path": "/"
+			},
+			"spec": {
+				"schema": {"type": "string"},
+				"value": "us-east"
+			}
+		}`)
+	require.Equal(t, http.StatusCreated, code, body)
+	code, body = request(http.MethodGet, "/resources/region?variant=test-variant", "")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, `"US-EAST"`, body)
+
+	// Setting the value is a value operation; the mutated value is rejected
+	code, body = request(http.MethodPut, "/resources/region?variant=test-variant", `"forbidden"`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "forbidden value")
+	code, body = request(http.MethodGet, "/resources/region?variant=test-variant", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `"US-EAST"`, body)
+
+	mu.Lock()
+	require.Len(t, operations, 2)
+	assert.Equal(t, []string{"schema", "value"}, operations[0])
+	assert.Equal(t, []string{"value"}, operations[1])
+	mu.Unlock()
+
+	// A webhook that cannot be reached rejects writes unless its failure policy ignores failures
+	code, body = request(http.MethodPost, "/admissionwebhooks", webhook("unreachable", unreachable.URL, "Validating", `["value"]`, "Ignore"))
+	require.Equal(t, http.StatusCreated, code, body)
+	code, body = request(http.MethodPut, "/resources/region?variant=test-variant", `"eu-west"`)
+	require.Equal(t, http.StatusOK, code, body)
+
+	code, body = request(http.MethodPut, "/admissionwebhooks/unreachable", webhook("unreachable", unreachable.URL, "Validating", `["value"]`, "Fail"))
+	require.Equal(t, http.StatusOK, code, body)
+	code, _ = request(http.MethodPut, "/resources/region?variant=test-variant", `"eu-central"`)
+	assert.Equal(t, http.StatusBadGateway, code)
+
+	code, _ = request(http.MethodDelete, "/admissionwebhooks/unreachable", "")
+	require.Equal(t, http.StatusNoContent, code)
+	code, body = request(http.MethodPut, "/resources/region?variant=test-variant", `"eu-central"`)
+	require.Equal(t, http.StatusOK, code, body)
+	code, body = request(http.MethodGet, "/resources/region?variant=test-variant", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, `"EU-CENTRAL"`, body)
+
+	// Webhooks on loopback addresses are refused unless their network is allowed
+	config.Config().AdmissionWebhooks.AllowedNetworks = nil
+	code, _ = request(http.MethodPut, "/resources/region?variant=test-variant", `"eu-north"`)
+	assert.Equal(t, http.StatusBadGateway, code)
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeys(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	// Create a key bound to the read-only view
+	httpReq, _ := http.NewRequest("POST", "/apikeys", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "ci-key", "description": "CI pipeline", "view": "read-only-view", "expires_in": "30d"}`)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	var created struct {
+		KeyID     string `json:"key_id"`
+		Key       string `json:"key"`
+		KeyPrefix string `json:"key_prefix"`
+		View      string `json:"view"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+	require.NotEmpty(t, created.Key)
+	assert.Equal(t, "read-only-view", created.View)
+	assert.Contains(t, created.Key, created.KeyPrefix)
+	assert.Equal(t, "/apikeys/"+created.KeyID, response.Header().Get("Location"))
+
+	// Duplicate names are rejected
+	httpReq, _ = http.NewRequest("POST", "/apikeys", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "ci-key", "view": "read-only-view"}`)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusConflict, response.Code)
+
+	// Invalid name and unknown view
+	httpReq, _ = http.NewRequest("POST", "/apikeys", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "bad name", "view": "read-only-view"}`)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/apikeys", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "other-key", "view": "no-such-view"}`)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusNotFound, response.Code)
+
+	// The key authenticates with the permissions of its view
+	httpReq, _ = http.NewRequest("GET", "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+created.Key)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	httpReq, _ = http.NewRequest("PUT", "/resources/resource1", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "resource1", "value": 100}`)
+	httpReq.Header.Set("Authorization", "Bearer "+created.Key)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// Keys cannot manage other keys or act as a user
+	httpReq, _ = http.NewRequest("GET", "/apikeys", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+created.Key)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/catalogs", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+created.Key)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// Usage is recorded and the secret is never returned again
+	httpReq, _ = http.NewRequest("GET", "/apikeys/"+created.KeyID, nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &got))
+	assert.NotContains(t, got, "key")
+	assert.Contains(t, got, "last_used_at")
+	assert.Equal(t, "CI pipeline", got["description"])
+
+	httpReq, _ = http.NewRequest("GET", "/apikeys", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var list struct {
+		Items []map[string]any `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &list))
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "ci-key", list.Items[0]["name"])
+
+	// A revoked key no longer authenticates
+	httpReq, _ = http.NewRequest("POST", "/apikeys/"+created.KeyID+"/revoke", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &got))
+	assert.Contains(t, got, "revoked_at")
+
+	httpReq, _ = http.NewRequest("GET", "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+created.Key)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// Delete the key
+	httpReq, _ = http.NewRequest("DELETE", "/apikeys/"+created.KeyID, nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/apikeys/"+created.KeyID, nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusNotFound, response.Code)
+}
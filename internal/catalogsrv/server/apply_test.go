@@ -0,0 +1,664 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+)
+
+func setYAMLRequestBody(req *http.Request, data string) {
+	req.Body = io.NopCloser(bytes.NewReader([]byte(data)))
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", "application/yaml")
+}
+
+func TestApply(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	// Set the tenant ID and project ID in the context
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	// Create the tenant for testing
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	// Create the project for testing
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:       tenantID,
+		ProjectId:      projectID,
+		CatalogContext: catcommon.CatalogContext{},
+	}
+
+	// Create a catalog
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "valid-catalog",
+				"description": "This is a valid catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	if !assert.Equal(t, http.StatusCreated, response.Code) {
+		t.Logf("Response: %v", response.Body.String())
+		t.FailNow()
+	}
+	testContext.CatalogContext.Catalog = "valid-catalog"
+
+	// Documents are deliberately out of dependency order
+	manifest := `
+apiVersion: 0.1.0-alpha.1
+kind: Namespace
+metadata:
+  name: valid-namespace
+  variant: valid-variant
+  description: This is a valid namespace
+---
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: valid-variant
+  description: This is a valid variant
+---
+apiVersion: 0.1.0-alpha.1
+kind: Catalog
+metadata:
+  name: valid-catalog
+  description: This is an applied catalog
+`
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, manifest)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	if !assert.Equal(t, http.StatusOK, response.Code) {
+		t.Logf("Response: %v", response.Body.String())
+		t.FailNow()
+	}
+
+	var rsp struct {
+		Results []struct {
+			Kind   string `json:"kind"`
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Results, 3)
+	assert.Equal(t, "Catalog", rsp.Results[0].Kind)
+	assert.Equal(t, "updated", rsp.Results[0].Status)
+	assert.Equal(t, "Variant", rsp.Results[1].Kind)
+	assert.Equal(t, "created", rsp.Results[1].Status)
+	assert.Equal(t, "Namespace", rsp.Results[2].Kind)
+	assert.Equal(t, "created", rsp.Results[2].Status)
+
+	// Applying the same manifest again updates the existing objects
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, manifest)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	if !assert.Equal(t, http.StatusOK, response.Code) {
+		t.Logf("Response: %v", response.Body.String())
+		t.FailNow()
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Results, 3)
+	for _, result := range rsp.Results {
+		assert.Equal(t, "updated", result.Status, result.Kind)
+	}
+
+	// The namespace was created in the applied variant
+	testContext.CatalogContext.Variant = "valid-variant"
+	httpReq, _ = http.NewRequest("GET", "/namespaces/valid-namespace", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Unsupported kinds are rejected before anything is applied
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, `
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: another-variant
+---
+apiVersion: 0.1.0-alpha.1
+kind: Unknown
+metadata:
+  name: unknown
+`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/variants/another-variant", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.NotEqual(t, http.StatusOK, response.Code)
+
+	// Documents for other catalogs are rejected
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, `
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: foreign-variant
+  catalog: other-catalog
+`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestApplyPrune(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:       tenantID,
+		ProjectId:      projectID,
+		CatalogContext: catcommon.CatalogContext{},
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "prune-catalog",
+				"description": "Catalog for pruning"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "prune-catalog"
+
+	resource := func(name string) string {
+		return `
+---
+apiVersion: 0.1.0-alpha.1
+kind: Resource
+metadata:
+  name: ` + name + `
+  variant: prune-variant
+  path: /config
+spec:
+  schema:
+    type: integer
+  value: 10
+`
+	}
+	manifest := `
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: prune-variant
+` + resource("retries") + resource("timeout")
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, manifest)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var rsp struct {
+		Results []struct {
+			Kind   string `json:"kind"`
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"results"`
+	}
+
+	// Without prune, resources left out of the manifest are kept
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, resource("retries"))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Results, 1)
+
+	testContext.CatalogContext.Variant = "prune-variant"
+	httpReq, _ = http.NewRequest("GET", "/resources/definition/config/timeout", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// With prune, they are deleted and reported
+	httpReq, _ = http.NewRequest("POST", "/apply?prune=true", nil)
+	setYAMLRequestBody(httpReq, resource("retries"))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Results, 2)
+	assert.Equal(t, "updated", rsp.Results[0].Status)
+	assert.Equal(t, "Resource", rsp.Results[1].Kind)
+	assert.Equal(t, "timeout", rsp.Results[1].Name)
+	assert.Equal(t, "pruned", rsp.Results[1].Status)
+
+	httpReq, _ = http.NewRequest("GET", "/resources/definition/config/timeout", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	httpReq, _ = http.NewRequest("GET", "/resources/definition/config/retries", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/apply?prune=maybe", nil)
+	setYAMLRequestBody(httpReq, resource("retries"))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestDryRun(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:       tenantID,
+		ProjectId:      projectID,
+		CatalogContext: catcommon.CatalogContext{},
+	}
+
+	catalog := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "dryrun-catalog",
+				"description": "Catalog for dry runs"
+			}
+		}`
+
+	// A dry run create returns the catalog without creating it
+	httpReq, _ := http.NewRequest("POST", "/catalogs?dryRun=true", nil)
+	setRequestBodyAndHeader(t, httpReq, catalog)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	assert.Contains(t, response.Body.String(), "dryrun-catalog")
+
+	httpReq, _ = http.NewRequest("GET", "/catalogs/dryrun-catalog", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/catalogs", nil)
+	setRequestBodyAndHeader(t, httpReq, catalog)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "dryrun-catalog"
+
+	manifest := `
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: dryrun-variant
+---
+apiVersion: 0.1.0-alpha.1
+kind: Resource
+metadata:
+  name: timeout
+  variant: dryrun-variant
+  path: /config
+spec:
+  schema:
+    type: integer
+  value: 10
+`
+
+	// A dry run apply reports what would be created, including objects that depend on
+	// objects created earlier in the same request
+	var rsp struct {
+		DryRun  bool `json:"dryRun"`
+		Results []struct {
+			Kind   string `json:"kind"`
+			Status string `json:"status"`
+		} `json:"results"`
+	}
+	httpReq, _ = http.NewRequest("POST", "/apply?dryRun=true", nil)
+	setYAMLRequestBody(httpReq, manifest)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	assert.True(t, rsp.DryRun)
+	require.Len(t, rsp.Results, 2)
+	assert.Equal(t, "created", rsp.Results[0].Status)
+	assert.Equal(t, "created", rsp.Results[1].Status)
+
+	httpReq, _ = http.NewRequest("GET", "/variants/dryrun-variant", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Validation still runs
+	httpReq, _ = http.NewRequest("POST", "/apply?dryRun=true", nil)
+	setYAMLRequestBody(httpReq, strings.Replace(manifest, "value: 10", "value: ten", 1))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code, response.Body.String())
+
+	// Once applied, a dry run update returns the would-be object and leaves the stored one
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, manifest)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	testContext.CatalogContext.Variant = "dryrun-variant"
+	httpReq, _ = http.NewRequest("POST", "/apply?dryRun=true", nil)
+	setYAMLRequestBody(httpReq, strings.Replace(manifest, "value: 10", "value: 20", 1))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Results, 2)
+	assert.Equal(t, "updated", rsp.Results[1].Status)
+
+	httpReq, _ = http.NewRequest("GET", "/resources/config/timeout", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "10")
+	assert.NotContains(t, response.Body.String(), "20")
+
+	httpReq, _ = http.NewRequest("POST", "/apply?dryRun=maybe", nil)
+	setYAMLRequestBody(httpReq, manifest)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestBulkDelete(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:       tenantID,
+		ProjectId:      projectID,
+		CatalogContext: catcommon.CatalogContext{},
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "delete-catalog",
+				"description": "Catalog for bulk deletes"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "delete-catalog"
+
+	namespace := `
+apiVersion: 0.1.0-alpha.1
+kind: Namespace
+metadata:
+  name: payments
+  variant: delete-variant
+  labels:
+    team: payments
+`
+	manifest := `
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: delete-variant
+---` + namespace + `---
+apiVersion: 0.1.0-alpha.1
+kind: Resource
+metadata:
+  name: limit
+  variant: delete-variant
+  namespace: payments
+  path: /config
+spec:
+  schema:
+    type: integer
+  value: 10
+`
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, manifest)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var rsp struct {
+		DryRun  bool `json:"dryRun"`
+		Results []struct {
+			Kind   string `json:"kind"`
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"results"`
+	}
+
+	// A namespace holding resources is only deleted with cascade
+	httpReq, _ = http.NewRequest("POST", "/delete", nil)
+	setYAMLRequestBody(httpReq, namespace)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusConflict, response.Code, response.Body.String())
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Results, 1)
+	assert.Equal(t, "failed", rsp.Results[0].Status)
+
+	// A dry run reports the contents that would be deleted first
+	httpReq, _ = http.NewRequest("POST", "/delete?cascade=true&dryRun=true", nil)
+	setYAMLRequestBody(httpReq, namespace)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	assert.True(t, rsp.DryRun)
+	require.Len(t, rsp.Results, 2)
+	assert.Equal(t, "Resource", rsp.Results[0].Kind)
+	assert.Equal(t, "limit", rsp.Results[0].Name)
+	assert.Equal(t, "Namespace", rsp.Results[1].Kind)
+	assert.Equal(t, "deleted", rsp.Results[1].Status)
+
+	testContext.CatalogContext.Variant = "delete-variant"
+	httpReq, _ = http.NewRequest("GET", "/namespaces/payments", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/delete?cascade=true", nil)
+	setYAMLRequestBody(httpReq, namespace)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Results, 2)
+	assert.False(t, rsp.DryRun)
+
+	httpReq, _ = http.NewRequest("GET", "/namespaces/payments", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Catalogs cannot be deleted in bulk
+	httpReq, _ = http.NewRequest("POST", "/delete", nil)
+	setYAMLRequestBody(httpReq, "kind: Catalog\nmetadata:\n  name: delete-catalog\n")
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestLint(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:       tenantID,
+		ProjectId:      projectID,
+		CatalogContext: catcommon.CatalogContext{},
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "lint-catalog",
+				"description": "Catalog for linting"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "lint-catalog"
+
+	manifest := `
+apiVersion: 0.1.0-alpha.1
+kind: Resource
+metadata:
+  name: timeout
+  description: Request timeout in seconds
+spec:
+  schema:
+    type: integer
+    maximum: 60
+    default: 120
+  value: 30
+---
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: dev
+`
+	httpReq, _ = http.NewRequest("POST", "/lint", nil)
+	setYAMLRequestBody(httpReq, manifest)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	var rsp struct {
+		Valid   bool `json:"valid"`
+		Results []struct {
+			Kind     string `json:"kind"`
+			Name     string `json:"name"`
+			Findings []struct {
+				Severity string `json:"severity"`
+				Rule     string `json:"rule"`
+				Path     string `json:"path"`
+			} `json:"findings"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	assert.False(t, rsp.Valid)
+	require.Len(t, rsp.Results, 2)
+	require.Len(t, rsp.Results[0].Findings, 1)
+	assert.Equal(t, "error", rsp.Results[0].Findings[0].Severity)
+	assert.Equal(t, "default-out-of-bounds", rsp.Results[0].Findings[0].Rule)
+	assert.Equal(t, "spec.schema.default", rsp.Results[0].Findings[0].Path)
+	require.Len(t, rsp.Results[1].Findings, 1)
+	assert.Equal(t, "missing-description", rsp.Results[1].Findings[0].Rule)
+
+	// Nothing is applied
+	httpReq, _ = http.NewRequest("GET", "/variants/dev", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/lint", nil)
+	setYAMLRequestBody(httpReq, "")
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
@@ -3,14 +3,19 @@ package server
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"encoding/json"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/userauth"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tidwall/gjson"
 )
 
 type testSetup struct {
@@ -430,3 +435,480 @@ func adoptView(t *testing.T, catalog, viewLabel, token string) string {
 
 	return adoptResponse.Token
 }
+
+func TestServiceTokens(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	issue := func(body string, bearer string) *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest("POST", "/auth/service-tokens", nil)
+		setRequestBodyAndHeader(t, httpReq, body)
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		return executeTestRequest(t, httpReq, nil)
+	}
+
+	// Invalid requests
+	response := issue(`{"service_account": "ci", "view": "no-such-view"}`, token)
+	require.Equal(t, http.StatusNotFound, response.Code)
+	response = issue(`{"service_account": "bad name", "view": "read-only-view"}`, token)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+	response = issue(`{"service_account": "ci", "view": "read-only-view", "expires_in": "1y"}`, token)
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	response = issue(`{"service_account": "ci", "view": "read-only-view", "expires_in": "10m"}`, token)
+	require.Equal(t, http.StatusOK, response.Code)
+	var tokenResponse struct {
+		Token     string    `json:"token"`
+		View      string    `json:"view"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &tokenResponse))
+	require.NotEmpty(t, tokenResponse.Token)
+	require.Equal(t, "read-only-view", tokenResponse.View)
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), tokenResponse.ExpiresAt, time.Minute)
+	serviceToken := tokenResponse.Token
+
+	// The token carries the view's permissions
+	httpReq, _ := http.NewRequest("GET", "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+serviceToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	httpReq, _ = http.NewRequest("PUT", "/resources/resource1", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "resource1", "value": 100}`)
+	httpReq.Header.Set("Authorization", "Bearer "+serviceToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// A read-only service cannot mint tokens for other views
+	response = issue(`{"service_account": "ci", "view": "full-access-view"}`, serviceToken)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// Changes to the view's rules apply to tokens already issued
+	httpReq, _ = http.NewRequest("PUT", "/views/read-only-view", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "read-only-view",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"description": "View with no resource access"
+			},
+			"spec": {
+				"rules": [{
+					"intent": "Deny",
+					"actions": ["system.resource.get"],
+					"targets": ["res://resources/*"]
+				}]
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+serviceToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	// Deleting the view invalidates the token
+	httpReq, _ = http.NewRequest("DELETE", "/views/read-only-view", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+serviceToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+}
+
+func TestTokenRefreshAndRevoke(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	post := func(path, body, bearer string) *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest("POST", path, nil)
+		if body != "" {
+			setRequestBodyAndHeader(t, httpReq, body)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		return executeTestRequest(t, httpReq, nil)
+	}
+	getResource := func(bearer string) int {
+		httpReq, _ := http.NewRequest("GET", "/resources/resource1", nil)
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		return executeTestRequest(t, httpReq, nil).Code
+	}
+	refresh := func(bearer string) string {
+		response := post("/auth/token/refresh", "", bearer)
+		require.Equal(t, http.StatusOK, response.Code)
+		var refreshResponse struct {
+			Token     string    `json:"token"`
+			ExpiresAt time.Time `json:"expires_at"`
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &refreshResponse))
+		require.NotEmpty(t, refreshResponse.Token)
+		require.True(t, refreshResponse.ExpiresAt.After(time.Now()))
+		return refreshResponse.Token
+	}
+
+	// Refreshing an access token revokes the old one
+	require.Equal(t, http.StatusOK, getResource(token))
+	newToken := refresh(token)
+	require.Equal(t, http.StatusOK, getResource(newToken))
+	require.Equal(t, http.StatusUnauthorized, getResource(token))
+
+	// Refreshed identity tokens can still adopt views
+	newUserToken := refresh(setup.userToken)
+	adoptDefaultView(t, "test-catalog", newUserToken)
+	response := post("/auth/token/refresh", "", setup.userToken)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// Refreshed identity tokens keep the lifetime of the token they replace
+	shortToken, _, err := userauth.CreateIdentityTokenWithValidity(setup.ctx, 10*time.Minute, map[string]any{
+		"token_use": catcommon.IdentityTokenType,
+		"sub":       "user/bob",
+	})
+	require.NoError(t, err)
+	response = post("/auth/token/refresh", "", shortToken)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	expiresAt, err := time.Parse(time.RFC3339, gjson.Get(response.Body.String(), "expires_at").String())
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(10*time.Minute), expiresAt, time.Minute)
+
+	// Revoke another token
+	otherToken := adoptDefaultView(t, "test-catalog", newUserToken)
+	require.Equal(t, http.StatusOK, getResource(otherToken))
+	response = post("/auth/token/revoke", `{"token": "`+otherToken+`"}`, newToken)
+	require.Equal(t, http.StatusNoContent, response.Code)
+	require.Equal(t, http.StatusUnauthorized, getResource(otherToken))
+
+	// Invalid tokens are ignored
+	response = post("/auth/token/revoke", `{"token": "not-a-token"}`, newToken)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	// Revoke the caller's own token
+	response = post("/auth/token/revoke", "", newToken)
+	require.Equal(t, http.StatusNoContent, response.Code)
+	require.Equal(t, http.StatusUnauthorized, getResource(newToken))
+	response = post("/auth/token/refresh", "", newToken)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+}
+
+func TestDeviceLogin(t *testing.T) {
+	setup := setupTest(t)
+
+	post := func(path, body, bearer string) *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest("POST", path, nil)
+		setRequestBodyAndHeader(t, httpReq, body)
+		if bearer != "" {
+			httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		return executeTestRequest(t, httpReq, nil)
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/auth/device", nil)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var deviceResponse struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		ExpiresIn       int    `json:"expires_in"`
+		Interval        int    `json:"interval"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &deviceResponse))
+	require.NotEmpty(t, deviceResponse.DeviceCode)
+	require.Regexp(t, `^[A-Z]{4}-[A-Z]{4}$`, deviceResponse.UserCode)
+	require.Positive(t, deviceResponse.ExpiresIn)
+	require.Positive(t, deviceResponse.Interval)
+	require.True(t, strings.HasSuffix(deviceResponse.VerificationURI, "/auth/device"), deviceResponse.VerificationURI)
+
+	// The verification URI is a page that can be opened in a browser
+	httpReq, _ = http.NewRequest("GET", "/auth/device?user_code="+strings.ToLower(deviceResponse.UserCode), nil)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Contains(t, response.Header().Get("Content-Type"), "text/html")
+	require.Contains(t, response.Body.String(), deviceResponse.UserCode)
+
+	pollBody := `{"device_code": "` + deviceResponse.DeviceCode + `"}`
+
+	// Pending until approved
+	response = post("/auth/device/token", pollBody, "")
+	require.Equal(t, http.StatusAccepted, response.Code)
+
+	// Polling faster than the interval is answered with a longer interval
+	response = post("/auth/device/token", pollBody, "")
+	require.Equal(t, http.StatusTooManyRequests, response.Code)
+	var slowDownResponse struct {
+		Status   string `json:"status"`
+		Interval int    `json:"interval"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &slowDownResponse))
+	require.Equal(t, "slow_down", slowDownResponse.Status)
+	require.Greater(t, slowDownResponse.Interval, deviceResponse.Interval)
+
+	// Approval requires a signed in user and a pending code
+	response = post("/auth/device/approve", `{"user_code": "`+deviceResponse.UserCode+`"}`, "")
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+	response = post("/auth/device/approve", `{"user_code": "BBBB-BBBB"}`, setup.userToken)
+	require.Equal(t, http.StatusNotFound, response.Code)
+
+	// Tokens narrowed to a view cannot approve a device login
+	accessToken := adoptDefaultView(t, "test-catalog", setup.userToken)
+	response = post("/auth/device/approve", `{"user_code": "`+deviceResponse.UserCode+`"}`, accessToken)
+	require.Equal(t, http.StatusUnauthorized, response.Code)
+
+	// User codes are accepted in lower case and without the separator
+	userCode := strings.ToLower(strings.ReplaceAll(deviceResponse.UserCode, "-", ""))
+	response = post("/auth/device/approve", `{"user_code": "`+userCode+`"}`, setup.userToken)
+	require.Equal(t, http.StatusNoContent, response.Code)
+
+	response = post("/auth/device/token", pollBody, "")
+	require.Equal(t, http.StatusOK, response.Code)
+	var tokenResponse struct {
+		Status    string    `json:"status"`
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &tokenResponse))
+	require.Equal(t, "approved", tokenResponse.Status)
+	require.NotEmpty(t, tokenResponse.Token)
+	require.True(t, tokenResponse.ExpiresAt.After(time.Now()))
+
+	// The token acts as the approving user
+	adoptDefaultView(t, "test-catalog", tokenResponse.Token)
+
+	// The device code can only be used once
+	response = post("/auth/device/token", pollBody, "")
+	require.Equal(t, http.StatusGone, response.Code)
+}
+
+func TestViewHandoff(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	handoff := func(viewLabel, bearer string) *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest("POST", "/auth/view-handoffs/test-catalog/"+viewLabel, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		return executeTestRequest(t, httpReq, nil)
+	}
+	handoffToken := func(response *httptest.ResponseRecorder) string {
+		var handoffResponse struct {
+			Token     string    `json:"token"`
+			View      string    `json:"view"`
+			ExpiresAt time.Time `json:"expires_at"`
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &handoffResponse))
+		require.NotEmpty(t, handoffResponse.Token)
+		require.WithinDuration(t, time.Now().Add(15*time.Minute), handoffResponse.ExpiresAt, time.Minute)
+		return handoffResponse.Token
+	}
+	putView := func(method, path, rules string) {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "delegator-view",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"description": "View that hands off read access"
+			},
+			"spec": {
+				"rules": ` + rules + `
+			}
+		}`
+		setRequestBodyAndHeader(t, httpReq, req)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		require.Contains(t, []int{http.StatusCreated, http.StatusOK}, response.Code, response.Body.String())
+	}
+	status := func(method, bearer string) int {
+		httpReq, _ := http.NewRequest(method, "/resources/resource1", nil)
+		if method == http.MethodPut {
+			setRequestBodyAndHeader(t, httpReq, `{"name": "resource1", "value": 100}`)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		return executeTestRequest(t, httpReq, nil).Code
+	}
+
+	// The admin view can hand off any view
+	response := handoff("read-write-view", token)
+	require.Equal(t, http.StatusOK, response.Code)
+	readWriteToken := handoffToken(response)
+	require.Equal(t, http.StatusOK, status(http.MethodGet, readWriteToken))
+	require.Equal(t, http.StatusOK, status(http.MethodPut, readWriteToken))
+
+	// Views without catalog.adoptView cannot hand off
+	response = handoff("read-only-view", readWriteToken)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	putView(http.MethodPost, "/views", `[
+		{
+			"intent": "Allow",
+			"actions": ["system.resource.get"],
+			"targets": ["res://resources/*"]
+		},
+		{
+			"intent": "Allow",
+			"actions": ["system.catalog.adoptView"],
+			"targets": ["res://views/read-only-view", "res://views/read-write-view"]
+		}
+	]`)
+	delegatorToken := adoptView(t, "test-catalog", "delegator-view", setup.userToken)
+
+	// A view cannot be handed off if it grants more than the adopting view
+	response = handoff("read-write-view", delegatorToken)
+	require.Equal(t, http.StatusForbidden, response.Code)
+
+	response = handoff("read-only-view", delegatorToken)
+	require.Equal(t, http.StatusOK, response.Code)
+	readOnlyToken := handoffToken(response)
+	require.Equal(t, http.StatusOK, status(http.MethodGet, readOnlyToken))
+	require.Equal(t, http.StatusForbidden, status(http.MethodPut, readOnlyToken))
+
+	// Handoff tokens stop working once the adopting view no longer permits the adoption
+	putView(http.MethodPut, "/views/delegator-view", `[
+		{
+			"intent": "Allow",
+			"actions": ["system.resource.get"],
+			"targets": ["res://resources/*"]
+		}
+	]`)
+	require.Equal(t, http.StatusUnauthorized, status(http.MethodGet, readOnlyToken))
+}
+
+func TestSensitiveResourceValue(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	httpReq, _ := http.NewRequest("POST", "/resources", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "api-key",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"path": "/"
+			},
+			"spec": {
+				"schema": {"type": "string"},
+				"value": "s3cr3t",
+				"annotations": {"sensitive": "true"}
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("POST", "/views", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "reveal-view",
+				"catalog": "test-catalog",
+				"variant": "test-variant"
+			},
+			"spec": {
+				"rules": [{
+					"intent": "Allow",
+					"actions": ["system.resource.get", "system.resource.reveal"],
+					"targets": ["res://resources/*"]
+				}]
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	get := func(path, bearer string) string {
+		httpReq, _ := http.NewRequest("GET", path, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		response := executeTestRequest(t, httpReq, nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		return response.Body.String()
+	}
+
+	// The value is redacted without the reveal action
+	readWriteToken := adoptView(t, "test-catalog", "read-write-view", token)
+	require.Equal(t, `"<redacted>"`, get("/resources/api-key", readWriteToken))
+	revealToken := adoptView(t, "test-catalog", "reveal-view", token)
+	require.Equal(t, `"s3cr3t"`, get("/resources/api-key", revealToken))
+
+	// It can still be written
+	httpReq, _ = http.NewRequest("PUT", "/resources/api-key", nil)
+	setRequestBodyAndHeader(t, httpReq, `"n3w-s3cr3t"`)
+	httpReq.Header.Set("Authorization", "Bearer "+readWriteToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.Equal(t, `"n3w-s3cr3t"`, get("/resources/api-key", revealToken))
+
+	// Definitions and revisions are redacted too
+	fullAccessToken := adoptView(t, "test-catalog", "full-access-view", token)
+	definition := get("/resources/definition/api-key", fullAccessToken)
+	require.Equal(t, "<redacted>", gjson.Get(definition, "spec.value").String())
+	require.Equal(t, "true", gjson.Get(definition, "spec.annotations.sensitive").String())
+	revisions := gjson.Get(get("/resources/revisions/api-key", fullAccessToken), "revisions").Array()
+	require.Len(t, revisions, 2)
+	require.Equal(t, "<redacted>", revisions[0].Get("value").String())
+
+	// The value is stored encrypted
+	obj, err := db.DB(setup.ctx).GetCatalogObject(setup.ctx, revisions[0].Get("hash").String())
+	require.NoError(t, err)
+	require.NotContains(t, string(obj.Data), "n3w-s3cr3t")
+	require.Contains(t, string(obj.Data), "encryptedValue")
+
+	put := func(path, body, bearer string) *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest("PUT", path, nil)
+		setRequestBodyAndHeader(t, httpReq, body)
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		return executeTestRequest(t, httpReq, nil)
+	}
+
+	// Writing back a redacted value or definition keeps the secret
+	response = put("/resources/api-key", get("/resources/api-key", readWriteToken), readWriteToken)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.Equal(t, `"n3w-s3cr3t"`, get("/resources/api-key", revealToken))
+	response = put("/resources/definition/api-key", definition, fullAccessToken)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.Equal(t, `"n3w-s3cr3t"`, get("/resources/api-key", revealToken))
+
+	// The placeholder is not stored as the value of a new sensitive resource
+	httpReq, _ = http.NewRequest("POST", "/resources", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "other-key",
+				"catalog": "test-catalog",
+				"variant": "test-variant",
+				"path": "/"
+			},
+			"spec": {
+				"schema": {"type": "string"},
+				"value": "<redacted>",
+				"annotations": {"sensitive": "true"}
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusBadRequest, response.Code, response.Body.String())
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthzDecisions(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+	readOnlyToken := adoptView(t, "test-catalog", "read-only-view", setup.userToken)
+
+	request := func(method, path, bearer string) int {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		if method == http.MethodPut {
+			setRequestBodyAndHeader(t, httpReq, `{"name": "resource1", "value": 100}`)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		return executeTestRequest(t, httpReq, nil).Code
+	}
+	type decision struct {
+		DecisionID   string          `json:"decisionID"`
+		Principal    string          `json:"principal"`
+		Actions      []string        `json:"actions"`
+		Target       string          `json:"target"`
+		Allowed      bool            `json:"allowed"`
+		MatchedRules json.RawMessage `json:"matchedRules"`
+	}
+	list := func(query url.Values, bearer string) ([]decision, string) {
+		httpReq, _ := http.NewRequest("GET", "/catalogs/test-catalog/authz-decisions?"+query.Encode(), nil)
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		response := executeTestRequest(t, httpReq, nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var rsp struct {
+			AuthzDecisions []decision `json:"authzDecisions"`
+			NextCursor     string     `json:"nextCursor"`
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+		return rsp.AuthzDecisions, rsp.NextCursor
+	}
+
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/resources/resource1", readOnlyToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPut, "/resources/resource1", readOnlyToken))
+
+	// The denial is recorded with the principal, the actions and the target
+	denied, _ := list(url.Values{"allowed": {"false"}, "action": {"system.resource.put"}}, token)
+	require.NotEmpty(t, denied)
+	require.True(t, strings.HasPrefix(denied[0].Principal, "user/"))
+	require.False(t, denied[0].Allowed)
+	require.Contains(t, denied[0].Actions, "system.resource.put")
+	require.Contains(t, denied[0].Target, "resource1")
+
+	allowed, _ := list(url.Values{"allowed": {"true"}, "action": {"system.resource.get"}}, token)
+	require.NotEmpty(t, allowed)
+	require.True(t, allowed[0].Allowed)
+	require.NotEmpty(t, allowed[0].MatchedRules)
+
+	// Pages follow each other without overlap
+	first, next := list(url.Values{"limit": {"1"}}, token)
+	require.Len(t, first, 1)
+	require.NotEmpty(t, next)
+	second, _ := list(url.Values{"limit": {"1"}, "cursor": {next}}, token)
+	require.Len(t, second, 1)
+	require.NotEqual(t, first[0].DecisionID, second[0].DecisionID)
+
+	// Filters are validated
+	httpReq, _ := http.NewRequest("GET", "/catalogs/test-catalog/authz-decisions?since=yesterday", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	require.Equal(t, http.StatusBadRequest, executeTestRequest(t, httpReq, nil).Code)
+
+	// Reading the audit log requires catalog admin
+	require.Equal(t, http.StatusForbidden, request(http.MethodGet, "/catalogs/test-catalog/authz-decisions", readOnlyToken))
+}
+
+func TestAuditModeView(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	createView := func(name, mode string) int {
+		httpReq, _ := http.NewRequest(http.MethodPost, "/views", nil)
+		setRequestBodyAndHeader(t, httpReq, `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "View",
+				"metadata": {
+					"name": "`+name+`",
+					"catalog": "test-catalog",
+					"variant": "test-variant"
+				},
+				"spec": {
+					"mode": "`+mode+`",
+					"rules": [{
+						"intent": "Allow",
+						"actions": ["system.resource.get"],
+						"targets": ["res://resources/*"]
+					}]
+				}
+			}`)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		return executeTestRequest(t, httpReq, nil).Code
+	}
+	require.Equal(t, http.StatusCreated, createView("shadow-view", "audit"))
+	require.Equal(t, http.StatusBadRequest, createView("bad-mode-view", "permissive"))
+
+	// The mode is part of the view spec
+	httpReq, _ := http.NewRequest(http.MethodGet, "/views/shadow-view", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Contains(t, response.Body.String(), `"mode":"audit"`)
+
+	// Requests the view denies still succeed
+	shadowToken := adoptView(t, "test-catalog", "shadow-view", token)
+	httpReq, _ = http.NewRequest(http.MethodPut, "/resources/resource1", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "resource1", "value": 100}`)
+	httpReq.Header.Set("Authorization", "Bearer "+shadowToken)
+	require.Equal(t, http.StatusOK, executeTestRequest(t, httpReq, nil).Code)
+
+	// ... and are recorded as denials that were not enforced
+	httpReq, _ = http.NewRequest(http.MethodGet, "/catalogs/test-catalog/authz-decisions?enforced=false", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	var rsp struct {
+		AuthzDecisions []struct {
+			Actions  []string `json:"actions"`
+			Allowed  bool     `json:"allowed"`
+			Enforced bool     `json:"enforced"`
+		} `json:"authzDecisions"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.Len(t, rsp.AuthzDecisions, 1)
+	require.False(t, rsp.AuthzDecisions[0].Allowed)
+	require.False(t, rsp.AuthzDecisions[0].Enforced)
+	require.Contains(t, rsp.AuthzDecisions[0].Actions, "system.resource.put")
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+)
+
+func TestCatalogExport(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	// Set the tenant ID and project ID in the context
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	// Create the tenant for testing
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	// Create the project for testing
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:       tenantID,
+		ProjectId:      projectID,
+		CatalogContext: catcommon.CatalogContext{},
+	}
+
+	// Create a catalog
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "valid-catalog",
+				"description": "This is a valid catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	if !assert.Equal(t, http.StatusCreated, response.Code) {
+		t.Logf("Response: %v", response.Body.String())
+		t.FailNow()
+	}
+	testContext.CatalogContext.Catalog = "valid-catalog"
+
+	// Create a variant with a namespace
+	httpReq, _ = http.NewRequest("POST", "/apply", nil)
+	setYAMLRequestBody(httpReq, `
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: valid-variant
+---
+apiVersion: 0.1.0-alpha.1
+kind: Namespace
+metadata:
+  name: valid-namespace
+  variant: valid-variant
+`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	// Export as multi-document YAML
+	httpReq, _ = http.NewRequest("GET", "/catalogs/valid-catalog/export?format=yaml", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "application/yaml", response.Header().Get("Content-Type"))
+	body := response.Body.String()
+	assert.Contains(t, body, "kind: Catalog")
+	assert.Contains(t, body, "name: valid-variant")
+	assert.Contains(t, body, "name: valid-namespace")
+
+	// Export as an archive
+	httpReq, _ = http.NewRequest("GET", "/catalogs/valid-catalog/export", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/gzip", response.Header().Get("Content-Type"))
+	gr, err := gzip.NewReader(response.Body)
+	require.NoError(t, err)
+	hdr, err := tar.NewReader(gr).Next()
+	require.NoError(t, err)
+	assert.Equal(t, catalogmanager.ExportManifestFile, hdr.Name)
+
+	// Unknown formats are rejected
+	httpReq, _ = http.NewRequest("GET", "/catalogs/valid-catalog/export?format=zip", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/catalogs/valid-catalog/export", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	archive := response.Body.Bytes()
+
+	importArchive := func(query string) (int, catalogmanager.ImportReport) {
+		httpReq, _ := http.NewRequest("POST", "/catalogs/import"+query, nil)
+		httpReq.Body = io.NopCloser(bytes.NewReader(archive))
+		httpReq.ContentLength = int64(len(archive))
+		httpReq.Header.Set("Content-Type", "application/gzip")
+		httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+		response := executeTestRequest(t, httpReq, nil, TestContext{TenantId: tenantID, ProjectId: projectID})
+		var report catalogmanager.ImportReport
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &report), response.Body.String())
+		return response.Code, report
+	}
+	actions := func(report catalogmanager.ImportReport) map[string]string {
+		m := make(map[string]string)
+		for _, result := range report.Results {
+			m[result.Kind+"/"+result.Name] = result.Action
+		}
+		return m
+	}
+
+	// Importing an unchanged catalog is a no-op
+	code, report := importArchive("")
+	require.Equal(t, http.StatusOK, code)
+	for _, result := range report.Results {
+		assert.Equal(t, catalogmanager.ImportActionUnchanged, result.Action, result.Kind+"/"+result.Name)
+	}
+
+	// A changed variant conflicts unless told how to resolve it
+	httpReq, _ = http.NewRequest("PATCH", "/variants/valid-variant", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"metadata": {"description": "changed"}}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	code, report = importArchive("?on_conflict=fail")
+	assert.Equal(t, http.StatusConflict, code)
+	assert.Equal(t, catalogmanager.ImportActionConflict, actions(report)["Variant/valid-variant"])
+
+	code, report = importArchive("?on_conflict=skip")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, catalogmanager.ImportActionSkip, actions(report)["Variant/valid-variant"])
+
+	code, report = importArchive("?on_conflict=overwrite&dry_run=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, catalogmanager.ImportActionUpdate, actions(report)["Variant/valid-variant"])
+
+	code, _ = importArchive("?on_conflict=overwrite")
+	assert.Equal(t, http.StatusOK, code)
+	code, report = importArchive("")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, catalogmanager.ImportActionUnchanged, actions(report)["Variant/valid-variant"])
+
+	// A deleted catalog is recreated with all of its objects
+	httpReq, _ = http.NewRequest("DELETE", "/catalogs/valid-catalog", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	code, report = importArchive("?dry_run=true")
+	assert.Equal(t, http.StatusOK, code)
+	for _, result := range report.Results {
+		assert.Equal(t, catalogmanager.ImportActionCreate, result.Action, result.Kind+"/"+result.Name)
+	}
+
+	code, _ = importArchive("")
+	require.Equal(t, http.StatusOK, code)
+	testContext.CatalogContext.Variant = "valid-variant"
+	httpReq, _ = http.NewRequest("GET", "/namespaces/valid-namespace", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 )
 
@@ -257,6 +258,152 @@ func TestGetUpdateDeleteCatalog(t *testing.T) {
 	require.NotEqual(t, http.StatusOK, response.Code)
 }
 
+func TestSoftDeleteAndRestore(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	listNames := func(path string) map[string]bool {
+		httpReq, _ := http.NewRequest("GET", path, nil)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var rsp map[string][]struct {
+			Name      string  `json:"name"`
+			DeletedAt *string `json:"deletedAt"`
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+		names := make(map[string]bool)
+		for _, items := range rsp {
+			for _, item := range items {
+				names[item.Name] = item.DeletedAt != nil
+			}
+		}
+		return names
+	}
+
+	// Create a catalog with a variant
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "restorable-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	testContext.CatalogContext.Catalog = "restorable-catalog"
+	httpReq, _ = http.NewRequest("POST", "/variants", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Variant",
+			"metadata": {
+				"name": "restorable-variant",
+				"catalog": "restorable-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	// Delete and restore the variant
+	httpReq, _ = http.NewRequest("DELETE", "/variants/restorable-variant", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/variants/restorable-variant", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.NotEqual(t, http.StatusOK, response.Code)
+
+	assert.NotContains(t, listNames("/variants"), "restorable-variant")
+	variants := listNames("/variants?includeDeleted=true")
+	if assert.Contains(t, variants, "restorable-variant") {
+		assert.True(t, variants["restorable-variant"], "deleted variant has deletedAt")
+	}
+	assert.False(t, variants[catcommon.DefaultVariant])
+
+	// The name stays reserved until the variant is purged
+	httpReq, _ = http.NewRequest("POST", "/variants", nil)
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/variants/restorable-variant/restore", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/variants/restorable-variant", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// Restoring a variant that is not deleted fails
+	httpReq, _ = http.NewRequest("POST", "/variants/restorable-variant/restore", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Delete and restore the catalog
+	httpReq, _ = http.NewRequest("DELETE", "/catalogs/restorable-catalog", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	testContext.CatalogContext.Catalog = ""
+	assert.NotContains(t, listNames("/catalogs"), "restorable-catalog")
+	catalogs := listNames("/catalogs?includeDeleted=true")
+	if assert.Contains(t, catalogs, "restorable-catalog") {
+		assert.True(t, catalogs["restorable-catalog"], "deleted catalog has deletedAt")
+	}
+
+	httpReq, _ = http.NewRequest("GET", "/catalogs?includeDeleted=maybe", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/catalogs/restorable-catalog/restore", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("POST", "/catalogs/no-such-catalog/restore", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// The variant comes back with the catalog
+	testContext.CatalogContext.Catalog = "restorable-catalog"
+	httpReq, _ = http.NewRequest("GET", "/variants/restorable-variant", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
+
 func TestVariantCrud(t *testing.T) {
 	ctx := newDb()
 	t.Cleanup(func() {
@@ -443,6 +590,93 @@ func TestVariantCrud(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, reqType, rspType)
 
+	// Updates conditioned on a stale ETag are rejected
+	etag := response.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+	httpReq, _ = http.NewRequest("PUT", "/variants/valid-variant", nil)
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("If-Match", `"stale"`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusPreconditionFailed, response.Code)
+
+	// Patch only the description of the variant
+	httpReq, _ = http.NewRequest("PATCH", "/variants/valid-variant", nil)
+	httpReq.Header.Set("If-Match", etag)
+	setRequestBodyAndHeader(t, httpReq, `{"metadata": {"description": "This is a patched description"}}`)
+	httpReq.Header.Set("Content-Type", "application/merge-patch+json")
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	if !assert.Equal(t, http.StatusOK, response.Code) {
+		t.Logf("Response: %v", response.Body.String())
+		t.FailNow()
+	}
+	// The patch returns the ETag of the patched variant
+	patchedETag := response.Header().Get("ETag")
+	assert.NotEmpty(t, patchedETag)
+	assert.NotEqual(t, etag, patchedETag)
+
+	httpReq, _ = http.NewRequest("GET", "/variants/valid-variant", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, patchedETag, response.Header().Get("ETag"))
+	rspType = make(map[string]any)
+	err = json.Unmarshal(response.Body.Bytes(), &rspType)
+	assert.NoError(t, err)
+	reqType["metadata"].(map[string]any)["description"] = "This is a patched description"
+	assert.Equal(t, reqType, rspType)
+
+	// Fetch only selected fields of the variant
+	httpReq, _ = http.NewRequest("GET", "/variants/valid-variant?fields=metadata.name,metadata.description", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"metadata": {"name": "valid-variant", "description": "This is a patched description"}}`, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/variants/valid-variant?fields=metadata..name", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// A patch with an unsupported content type is rejected
+	httpReq, _ = http.NewRequest("PATCH", "/variants/valid-variant", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"metadata": {"description": "ignored"}}`)
+	httpReq.Header.Set("Content-Type", "text/plain")
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusUnsupportedMediaType, response.Code)
+
+	// List the variants in the catalog
+	httpReq, _ = http.NewRequest("GET", "/variants?c=valid-catalog", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	var variantList struct {
+		Variants []struct {
+			Name string `json:"name"`
+		} `json:"variants"`
+	}
+	err = json.Unmarshal(response.Body.Bytes(), &variantList)
+	assert.NoError(t, err)
+	assert.Len(t, variantList.Variants, 3) // default, valid-variant, valid-variant2
+
+	// Label a variant and select it
+	httpReq, _ = http.NewRequest("PATCH", "/variants/valid-variant", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"metadata": {"labels": {"env": "prod"}}}`)
+	httpReq.Header.Set("Content-Type", "application/merge-patch+json")
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/variants?c=valid-catalog&labelSelector=env=prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	variantList.Variants = nil
+	err = json.Unmarshal(response.Body.Bytes(), &variantList)
+	assert.NoError(t, err)
+	require.Len(t, variantList.Variants, 1)
+	assert.Equal(t, "valid-variant", variantList.Variants[0].Name)
+
+	// Invalid labels are rejected
+	httpReq, _ = http.NewRequest("PATCH", "/variants/valid-variant", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"metadata": {"labels": {"bad key": "prod"}}}`)
+	httpReq.Header.Set("Content-Type", "application/merge-patch+json")
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
 	// Delete the variant
 	httpReq, _ = http.NewRequest("DELETE", "/variants/valid-variant", nil)
 	response = executeTestRequest(t, httpReq, nil, testContext)
@@ -574,3 +808,457 @@ func TestNamespaceCrud(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestVariantClone(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "clone-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "clone-catalog"
+
+	httpReq, _ = http.NewRequest("POST", "/variants", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Variant",
+			"metadata": {
+				"name": "staging"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	testContext.CatalogContext.Variant = "staging"
+
+	httpReq, _ = http.NewRequest("POST", "/namespaces", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Namespace",
+			"metadata": {
+				"name": "team-a"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("POST", "/resources", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "max-connections"
+			},
+			"spec": {
+				"schema": {"type": "integer"},
+				"value": 10
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	// Clone the variant
+	testContext.CatalogContext.Variant = ""
+	httpReq, _ = http.NewRequest("POST", "/variants/staging/clone", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "prod", "description": "Production"}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	assert.Equal(t, "/variants/prod", response.Header().Get("Location"))
+
+	httpReq, _ = http.NewRequest("GET", "/variants/prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	// The clone has the resources and namespaces of the source
+	testContext.CatalogContext.Variant = "prod"
+	httpReq, _ = http.NewRequest("GET", "/resources/max-connections", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Contains(t, response.Body.String(), "10")
+
+	httpReq, _ = http.NewRequest("GET", "/namespaces/team-a", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	// Changes to the clone do not affect the source
+	httpReq, _ = http.NewRequest("PUT", "/resources/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `20`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	testContext.CatalogContext.Variant = "staging"
+	httpReq, _ = http.NewRequest("GET", "/resources/max-connections", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.NotContains(t, response.Body.String(), "20")
+
+	// Cloning into an existing variant fails
+	testContext.CatalogContext.Variant = ""
+	httpReq, _ = http.NewRequest("POST", "/variants/staging/clone", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "prod"}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/variants/staging/clone", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "not a name"}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/variants/no-such-variant/clone", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "copy"}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestVariantDiff(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "variant-diff-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "variant-diff-catalog"
+
+	httpReq, _ = http.NewRequest("POST", "/variants", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Variant",
+			"metadata": {
+				"name": "prod"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	resource := func(name string, schema string, value string) string {
+		return `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "` + name + `"
+			},
+			"spec": {
+				"schema": ` + schema + `,
+				"value": ` + value + `
+			}
+		}`
+	}
+
+	testContext.CatalogContext.Variant = "prod"
+	for _, r := range []string{
+		resource("max-connections", `{"type": "integer", "maximum": 100}`, `10`),
+		resource("timeout", `{"type": "integer"}`, `30`),
+		resource("retries", `{"type": "integer"}`, `3`),
+	} {
+		httpReq, _ = http.NewRequest("POST", "/resources", nil)
+		setRequestBodyAndHeader(t, httpReq, r)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+
+	testContext.CatalogContext.Variant = ""
+	httpReq, _ = http.NewRequest("POST", "/variants/prod/clone", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"name": "staging"}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	// Identical variants have no differences
+	httpReq, _ = http.NewRequest("GET", "/variants/staging/diff?against=prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.JSONEq(t, `{"variant": "staging", "against": "prod", "namespaces": []}`, response.Body.String())
+
+	// Change staging: tighten a schema, change a value, remove a resource and add a resource
+	// in a namespace
+	testContext.CatalogContext.Variant = "staging"
+	httpReq, _ = http.NewRequest("PUT", "/resources/definition/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, resource("max-connections", `{"type": "integer", "maximum": 50}`, `10`))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("PUT", "/resources/timeout", nil)
+	setRequestBodyAndHeader(t, httpReq, `60`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("DELETE", "/resources/definition/retries", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("POST", "/namespaces", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"apiVersion": "0.1.0-alpha.1", "kind": "Namespace", "metadata": {"name": "team-a"}}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	testContext.CatalogContext.Namespace = "team-a"
+	httpReq, _ = http.NewRequest("POST", "/resources", nil)
+	setRequestBodyAndHeader(t, httpReq, resource("batch-size", `{"type": "integer"}`, `5`))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	testContext.CatalogContext.Namespace = ""
+
+	testContext.CatalogContext.Variant = ""
+	httpReq, _ = http.NewRequest("GET", "/variants/staging/diff?against=prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.JSONEq(t, `{
+		"variant": "staging",
+		"against": "prod",
+		"namespaces": [
+			{
+				"resources": [
+					{"resource": "/max-connections", "variant": "staging", "against": "prod", "status": "changed",
+						"changes": [{"path": "/maximum", "change": "tightened", "from": 100, "to": 50}], "valueChanged": false},
+					{"resource": "/retries", "variant": "staging", "against": "prod", "status": "removed", "changes": [], "valueChanged": false},
+					{"resource": "/timeout", "variant": "staging", "against": "prod", "status": "changed", "changes": [], "valueChanged": true}
+				],
+				"skillsets": []
+			},
+			{
+				"namespace": "team-a",
+				"resources": [
+					{"resource": "/batch-size", "variant": "staging", "against": "prod", "status": "added", "changes": [], "valueChanged": false}
+				],
+				"skillsets": []
+			}
+		]
+	}`, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/variants/staging/diff", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/variants/staging/diff?against=no-such-variant", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestDefaultVariant(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "default-variant-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "default-variant-catalog"
+
+	httpReq, _ = http.NewRequest("POST", "/variants", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Variant",
+			"metadata": {
+				"name": "prod"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	resource := func(value string) string {
+		return `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "region"
+			},
+			"spec": {
+				"schema": {"type": "string"},
+				"value": "` + value + `"
+			}
+		}`
+	}
+	for variant, value := range map[string]string{catcommon.DefaultVariant: "us-west", "prod": "eu-central"} {
+		testContext.CatalogContext.Variant = variant
+		httpReq, _ = http.NewRequest("POST", "/resources", nil)
+		setRequestBodyAndHeader(t, httpReq, resource(value))
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+	testContext.CatalogContext.Variant = ""
+
+	// Requests that do not name a variant use the default variant of the catalog
+	httpReq, _ = http.NewRequest("GET", "/resources/region", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Contains(t, response.Body.String(), "us-west")
+
+	httpReq, _ = http.NewRequest("POST", "/variants/prod/default", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/resources/region", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Contains(t, response.Body.String(), "eu-central")
+
+	httpReq, _ = http.NewRequest("GET", "/resources/region?v=default", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Contains(t, response.Body.String(), "us-west")
+
+	httpReq, _ = http.NewRequest("GET", "/catalogs/default-variant-catalog", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "prod", gjson.Get(response.Body.String(), "metadata.defaultVariant").String())
+
+	// Updating the catalog keeps its default variant
+	httpReq, _ = http.NewRequest("PUT", "/catalogs/default-variant-catalog", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "default-variant-catalog",
+				"description": "Updated"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/catalogs/default-variant-catalog", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "prod", gjson.Get(response.Body.String(), "metadata.defaultVariant").String())
+
+	// The default variant cannot be deleted
+	httpReq, _ = http.NewRequest("DELETE", "/variants/prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/variants/no-such-variant/default", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Making the built-in default variant the default again clears the setting
+	httpReq, _ = http.NewRequest("POST", "/variants/default/default", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusNoContent, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/catalogs/default-variant-catalog", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.False(t, gjson.Get(response.Body.String(), "metadata.defaultVariant").Exists())
+
+	httpReq, _ = http.NewRequest("DELETE", "/variants/prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
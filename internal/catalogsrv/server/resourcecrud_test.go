@@ -12,6 +12,7 @@ import (
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tidwall/gjson"
 )
 
 func TestResourceCrud(t *testing.T) {
@@ -298,11 +299,13 @@ func TestResourceList(t *testing.T) {
 	resources := []struct {
 		Name        string
 		Description string
+		Labels      string
 		Value       map[string]any
 	}{
 		{
 			"resource1",
 			"First test resource",
+			`{"env": "prod", "team": "payments"}`,
 			map[string]any{
 				"name":  "test1",
 				"value": 1,
@@ -311,6 +314,7 @@ func TestResourceList(t *testing.T) {
 		{
 			"resource2",
 			"Second test resource",
+			`{"env": "dev"}`,
 			map[string]any{
 				"name":  "test2",
 				"value": 2,
@@ -319,6 +323,7 @@ func TestResourceList(t *testing.T) {
 		{
 			"internal",
 			"Internal resource",
+			`{}`,
 			map[string]any{
 				"name":  "internal",
 				"value": 3,
@@ -335,7 +340,8 @@ func TestResourceList(t *testing.T) {
 				"name": "` + r.Name + `",
 				"catalog": "list-catalog",
 				"variant": "list-variant",
-				"description": "` + r.Description + `"
+				"description": "` + r.Description + `",
+				"labels": ` + r.Labels + `
 			},
 			"spec": {
 				"schema": {
@@ -367,12 +373,69 @@ func TestResourceList(t *testing.T) {
 	response = executeTestRequest(t, httpReq, nil, testContext)
 	require.Equal(t, http.StatusOK, response.Code)
 
-	var result = make(map[string]json.RawMessage)
+	var result struct {
+		Items      []json.RawMessage `json:"resources"`
+		NextCursor string            `json:"nextCursor"`
+	}
 	err = json.Unmarshal(response.Body.Bytes(), &result)
 	assert.NoError(t, err)
 
 	// All resources should be present
-	assert.Len(t, result, 3)
+	assert.Len(t, result.Items, 3)
+	assert.Empty(t, result.NextCursor)
+
+	// Page through the list two at a time
+	httpReq, _ = http.NewRequest("GET", "/resources?catalog=list-catalog&variant=list-variant&limit=2", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	result.NextCursor = ""
+	err = json.Unmarshal(response.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 2)
+	require.NotEmpty(t, result.NextCursor)
+
+	httpReq, _ = http.NewRequest("GET", "/resources?catalog=list-catalog&variant=list-variant&limit=2&cursor="+result.NextCursor, nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	result.NextCursor = ""
+	err = json.Unmarshal(response.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 1)
+	assert.Empty(t, result.NextCursor)
+
+	// Select resources by label
+	httpReq, _ = http.NewRequest("GET", "/resources?catalog=list-catalog&variant=list-variant&labelSelector=env=prod", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	result.Items = nil
+	err = json.Unmarshal(response.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	require.Len(t, result.Items, 1)
+	assert.Equal(t, "resource1", gjson.GetBytes(result.Items[0], "metadata.name").String())
+	assert.Equal(t, "payments", gjson.GetBytes(result.Items[0], "metadata.labels.team").String())
+
+	httpReq, _ = http.NewRequest("GET", "/resources?catalog=list-catalog&variant=list-variant&labelSelector=env!=prod", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	result.Items = nil
+	err = json.Unmarshal(response.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 2)
+
+	httpReq, _ = http.NewRequest("GET", "/resources?catalog=list-catalog&variant=list-variant&labelSelector=env=bad%20value", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// List parameters only apply to lists
+	httpReq, _ = http.NewRequest("GET", "/resources/definition/resource1?catalog=list-catalog&variant=list-variant&limit=none", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
 }
 
 func TestResourceValue(t *testing.T) {
@@ -533,3 +596,930 @@ func TestResourceValue(t *testing.T) {
 	response = executeTestRequest(t, httpReq, nil, testContext)
 	assert.Equal(t, http.StatusNotFound, response.Code)
 }
+
+func TestResourceDeprecation(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "deprecation-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "deprecation-catalog"
+
+	httpReq, _ = http.NewRequest("POST", "/variants", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Variant",
+			"metadata": {
+				"name": "deprecation-variant"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Variant = "deprecation-variant"
+
+	for _, req := range []string{`
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "timeout-ms",
+				"deprecated": true
+			},
+			"spec": {
+				"schema": {"type": "integer"},
+				"value": 5000,
+				"deprecationMessage": "use timeout instead"
+			}
+		}`, `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "timeout"
+			},
+			"spec": {
+				"schema": {"type": "string"},
+				"value": "5s"
+			}
+		}`} {
+		httpReq, _ = http.NewRequest("POST", "/resources", nil)
+		setRequestBodyAndHeader(t, httpReq, req)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+
+	// Deprecated resources are flagged in the definition and in a Warning header
+	httpReq, _ = http.NewRequest("GET", "/resources/definition/timeout-ms", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.True(t, gjson.Get(response.Body.String(), "metadata.deprecated").Bool())
+	assert.Equal(t, "use timeout instead", gjson.Get(response.Body.String(), "spec.deprecationMessage").String())
+	assert.Equal(t, []string{`299 - "resource /timeout-ms is deprecated: use timeout instead"`}, response.Header().Values("Warning"))
+
+	httpReq, _ = http.NewRequest("GET", "/resources/timeout-ms", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Len(t, response.Header().Values("Warning"), 1)
+
+	httpReq, _ = http.NewRequest("GET", "/resources/definition/timeout", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Empty(t, response.Header().Values("Warning"))
+
+	httpReq, _ = http.NewRequest("GET", "/resources", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Len(t, response.Header().Values("Warning"), 1)
+
+	// Values of deprecated resources can still be written
+	httpReq, _ = http.NewRequest("PUT", "/resources/timeout-ms", nil)
+	setRequestBodyAndHeader(t, httpReq, `6000`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	// A deprecation message requires the resource to be deprecated
+	httpReq, _ = http.NewRequest("POST", "/resources", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "retries"
+			},
+			"spec": {
+				"schema": {"type": "integer"},
+				"value": 3,
+				"deprecationMessage": "no longer used"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestResourceDiff(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "diff-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "diff-catalog"
+
+	for _, variant := range []string{"prod", "dev"} {
+		httpReq, _ = http.NewRequest("POST", "/variants", nil)
+		setRequestBodyAndHeader(t, httpReq, `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "Variant",
+				"metadata": {
+					"name": "`+variant+`"
+				}
+			}`)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code)
+	}
+
+	createResource := func(variant, name, schema, value string) {
+		testContext.CatalogContext.Variant = variant
+		httpReq, _ := http.NewRequest("POST", "/resources", nil)
+		setRequestBodyAndHeader(t, httpReq, `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "Resource",
+				"metadata": {
+					"name": "`+name+`"
+				},
+				"spec": {
+					"schema": `+schema+`,
+					"value": `+value+`
+				}
+			}`)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+	createResource("prod", "limits", `{"type": "object", "properties": {"rps": {"type": "integer", "maximum": 1000}}}`, `{"rps": 100}`)
+	createResource("prod", "region", `{"type": "string"}`, `"us-east-1"`)
+	createResource("dev", "limits", `{"type": "object", "properties": {"rps": {"type": "integer", "maximum": 500}, "burst": {"type": "integer"}}, "required": ["rps"]}`, `{"rps": 100}`)
+	createResource("dev", "region", `{"type": "string"}`, `"us-east-1"`)
+	createResource("dev", "sandbox", `{"type": "boolean"}`, `true`)
+	testContext.CatalogContext.Variant = "dev"
+
+	// Changed schema
+	httpReq, _ = http.NewRequest("GET", "/resources/diff/limits?against=prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	rsp := response.Body.String()
+	assert.Equal(t, "changed", gjson.Get(rsp, "status").String())
+	assert.Equal(t, "prod", gjson.Get(rsp, "against").String())
+	assert.False(t, gjson.Get(rsp, "valueChanged").Bool())
+	changes := gjson.Get(rsp, "changes").Array()
+	require.Len(t, changes, 3)
+	assert.Equal(t, "/properties/burst", changes[0].Get("path").String())
+	assert.Equal(t, "added", changes[0].Get("change").String())
+	assert.Equal(t, "/properties/rps/maximum", changes[1].Get("path").String())
+	assert.Equal(t, "tightened", changes[1].Get("change").String())
+	assert.Equal(t, "/required", changes[2].Get("path").String())
+	assert.Equal(t, "tightened", changes[2].Get("change").String())
+
+	// Unchanged resource
+	httpReq, _ = http.NewRequest("GET", "/resources/diff/region?against=prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "unchanged", gjson.Get(response.Body.String(), "status").String())
+	assert.Empty(t, gjson.Get(response.Body.String(), "changes").Array())
+
+	// Resource that only exists in this variant
+	httpReq, _ = http.NewRequest("GET", "/resources/diff/sandbox?against=prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "added", gjson.Get(response.Body.String(), "status").String())
+
+	// Resource that only exists in the other variant
+	testContext.CatalogContext.Variant = "prod"
+	httpReq, _ = http.NewRequest("GET", "/resources/diff/sandbox?against=dev", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "removed", gjson.Get(response.Body.String(), "status").String())
+	testContext.CatalogContext.Variant = "dev"
+
+	// Missing or unknown variant to compare against
+	httpReq, _ = http.NewRequest("GET", "/resources/diff/limits", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/resources/diff/limits?against=staging", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Resource that exists in neither variant
+	httpReq, _ = http.NewRequest("GET", "/resources/diff/missing?against=prod", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestResourceRevisions(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "revisions-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "revisions-catalog"
+
+	httpReq, _ = http.NewRequest("POST", "/variants", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Variant",
+			"metadata": {
+				"name": "revisions-variant"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Variant = "revisions-variant"
+
+	resource := func(maximum int, value int) string {
+		return `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "max-connections"
+			},
+			"spec": {
+				"schema": {"type": "integer", "maximum": ` + strconv.Itoa(maximum) + `},
+				"value": ` + strconv.Itoa(value) + `
+			}
+		}`
+	}
+
+	httpReq, _ = http.NewRequest("POST", "/resources", nil)
+	setRequestBodyAndHeader(t, httpReq, resource(100, 10))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("PUT", "/resources/definition/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, resource(50, 20))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("PUT", "/resources/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `30`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	// Every change creates a revision, the current one first
+	httpReq, _ = http.NewRequest("GET", "/resources/revisions/max-connections", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	revisions := gjson.Get(response.Body.String(), "revisions").Array()
+	require.Len(t, revisions, 3)
+	assert.True(t, revisions[0].Get("current").Bool())
+	assert.False(t, revisions[0].Get("replacedAt").Exists())
+	assert.False(t, revisions[2].Get("current").Bool())
+	assert.True(t, revisions[2].Get("replacedAt").Exists())
+	for i, value := range []int64{30, 20, 10} {
+		assert.Equal(t, value, revisions[i].Get("value").Int())
+		assert.Contains(t, revisions[i].Get("updatedBy").String(), "test_user")
+		assert.True(t, revisions[i].Get("updatedAt").Exists())
+	}
+	current := revisions[0].Get("hash").String()
+	original := revisions[2].Get("hash").String()
+
+	// Restore the original revision
+	httpReq, _ = http.NewRequest("POST", "/resources/revisions/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"revision": "`+original+`"}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/resources/definition/max-connections", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, int64(100), gjson.Get(response.Body.String(), "spec.schema.maximum").Int())
+	assert.Equal(t, int64(10), gjson.Get(response.Body.String(), "spec.value").Int())
+
+	// The replaced revision is kept and can be restored in turn
+	httpReq, _ = http.NewRequest("GET", "/resources/revisions/max-connections", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	revisions = gjson.Get(response.Body.String(), "revisions").Array()
+	require.Len(t, revisions, 4)
+	assert.Equal(t, current, revisions[1].Get("hash").String())
+
+	httpReq, _ = http.NewRequest("POST", "/resources/revisions/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"revision": "`+revisions[0].Get("hash").String()+`"}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/resources/revisions/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"revision": "0123456789abcdef0123456789abcdef"}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	httpReq, _ = http.NewRequest("POST", "/resources/revisions/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `{}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/resources/revisions/missing", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	// Restore only the value of a revision, keeping the current definition
+	revisionWithValue := func(value int64) string {
+		httpReq, _ := http.NewRequest("GET", "/resources/revisions/max-connections", nil)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code)
+		for _, revision := range gjson.Get(response.Body.String(), "revisions").Array()[1:] {
+			if revision.Get("value").Int() == value {
+				return revision.Get("hash").String()
+			}
+		}
+		t.Fatalf("no previous revision with value %d", value)
+		return ""
+	}
+
+	httpReq, _ = http.NewRequest("POST", "/resources/revisions/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"revision": "`+revisionWithValue(30)+`", "valueOnly": true}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/resources/definition/max-connections", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, int64(100), gjson.Get(response.Body.String(), "spec.schema.maximum").Int())
+	assert.Equal(t, int64(30), gjson.Get(response.Body.String(), "spec.value").Int())
+
+	// The old value is validated against the current schema
+	httpReq, _ = http.NewRequest("PUT", "/resources/definition/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, resource(15, 5))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("POST", "/resources/revisions/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"revision": "`+revisionWithValue(20)+`", "valueOnly": true}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestResourceValues(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "values-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "values-catalog"
+	testContext.CatalogContext.Variant = "default"
+
+	resource := func(path, name string, value int) string {
+		return `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "` + name + `",
+				"path": "` + path + `"
+			},
+			"spec": {
+				"schema": {"type": "integer", "maximum": 100},
+				"value": ` + strconv.Itoa(value) + `
+			}
+		}`
+	}
+	for _, r := range []string{resource("/db", "max-connections", 10), resource("/cache", "ttl", 60)} {
+		httpReq, _ = http.NewRequest("POST", "/resources", nil)
+		setRequestBodyAndHeader(t, httpReq, r)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+
+	value := func(resourcePath string) int64 {
+		httpReq, _ := http.NewRequest("GET", "/resources"+resourcePath, nil)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		return gjson.Parse(response.Body.String()).Int()
+	}
+
+	// Values are updated together
+	httpReq, _ = http.NewRequest("PATCH", "/resources", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"values": [
+		{"resource": "/db/max-connections", "value": 20},
+		{"resource": "/cache/ttl", "value": 90}
+	]}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, int64(20), value("/db/max-connections"))
+	assert.Equal(t, int64(90), value("/cache/ttl"))
+
+	// A value that fails validation leaves every resource unchanged
+	httpReq, _ = http.NewRequest("PATCH", "/resources", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"values": [
+		{"resource": "/db/max-connections", "value": 30},
+		{"resource": "/cache/ttl", "value": 500}
+	]}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "/cache/ttl")
+	assert.Equal(t, int64(20), value("/db/max-connections"))
+	assert.Equal(t, int64(90), value("/cache/ttl"))
+
+	// So does a missing resource
+	httpReq, _ = http.NewRequest("PATCH", "/resources", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"values": [
+		{"resource": "/db/max-connections", "value": 30},
+		{"resource": "/cache/missing", "value": 1}
+	]}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+	assert.Equal(t, int64(20), value("/db/max-connections"))
+
+	httpReq, _ = http.NewRequest("PATCH", "/resources", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"values": [
+		{"resource": "/db/max-connections", "value": 30},
+		{"resource": "/db/max-connections", "value": 40}
+	]}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("PATCH", "/resources", nil)
+	setRequestBodyAndHeader(t, httpReq, `{"values": []}`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestResourceValueInheritance(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "inheritance-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "inheritance-catalog"
+	testContext.CatalogContext.Variant = "default"
+
+	const integerSchema = `{"type": ["integer", "null"]}`
+	resource := func(path, schema, value string) string {
+		return `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "max-connections",
+				"path": "` + path + `"
+			},
+			"spec": {
+				"schema": ` + schema + `,
+				"value": ` + value + `
+			}
+		}`
+	}
+	for _, r := range []string{
+		resource("/", integerSchema, "10"),
+		resource("/prod", integerSchema, "null"),
+		resource("/prod/db", integerSchema, "null"),
+		resource("/dev", `{"type": "string"}`, `"dev"`),
+		resource("/dev/db", integerSchema, "null"),
+	} {
+		httpReq, _ = http.NewRequest("POST", "/resources", nil)
+		setRequestBodyAndHeader(t, httpReq, r)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+
+	resolve := func(resourcePath string) gjson.Result {
+		httpReq, _ := http.NewRequest("GET", "/resources"+resourcePath+"?inherit=true", nil)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		return gjson.Parse(response.Body.String())
+	}
+	chain := func(resolved gjson.Result) []string {
+		var paths []string
+		for _, p := range resolved.Get("chain").Array() {
+			paths = append(paths, p.String())
+		}
+		return paths
+	}
+
+	// An unset value is inherited from the nearest ancestor with a value
+	resolved := resolve("/prod/db/max-connections")
+	assert.Equal(t, int64(10), resolved.Get("value").Int())
+	assert.Equal(t, "/max-connections", resolved.Get("resolvedFrom").String())
+	assert.Equal(t, []string{"/prod/db/max-connections", "/prod/max-connections", "/max-connections"}, chain(resolved))
+
+	httpReq, _ = http.NewRequest("PUT", "/resources/prod/max-connections", nil)
+	setRequestBodyAndHeader(t, httpReq, `20`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	resolved = resolve("/prod/db/max-connections")
+	assert.Equal(t, int64(20), resolved.Get("value").Int())
+	assert.Equal(t, "/prod/max-connections", resolved.Get("resolvedFrom").String())
+
+	// A value that is set is not inherited
+	resolved = resolve("/prod/max-connections")
+	assert.Equal(t, []string{"/prod/max-connections"}, chain(resolved))
+
+	// Ancestors with a different schema are skipped
+	resolved = resolve("/dev/db/max-connections")
+	assert.Equal(t, int64(10), resolved.Get("value").Int())
+	assert.Equal(t, []string{"/dev/db/max-connections", "/max-connections"}, chain(resolved))
+
+	// Without inherit the value is returned as is
+	httpReq, _ = http.NewRequest("GET", "/resources/dev/db/max-connections", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, gjson.Null, gjson.Parse(response.Body.String()).Type)
+
+	httpReq, _ = http.NewRequest("GET", "/resources/dev/db/max-connections?inherit=maybe", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestResourceRender(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "render-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "render-catalog"
+	testContext.CatalogContext.Variant = "default"
+
+	resource := func(path, name, schema, value, annotations string) string {
+		return `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "` + name + `",
+				"path": "` + path + `"
+			},
+			"spec": {
+				"schema": ` + schema + `,
+				"value": ` + value + `,
+				"annotations": ` + annotations + `
+			}
+		}`
+	}
+	for _, r := range []string{
+		resource("/app", "max-attempts", `{"type": "integer"}`, "3", `{"env": "MAX_ATTEMPTS"}`),
+		resource("/app", "greeting", `{"type": "string"}`, `"hello world"`, `{"env": "GREETING"}`),
+		resource("/app/db", "host", `{"type": "string"}`, `"db.local"`, `{"env": "DB_HOST"}`),
+		resource("/app", "internal", `{"type": "string"}`, `"x"`, `{"team": "infra"}`),
+		resource("/other", "host", `{"type": "string"}`, `"other.local"`, `{"env": "OTHER_HOST"}`),
+	} {
+		httpReq, _ = http.NewRequest("POST", "/resources", nil)
+		setRequestBodyAndHeader(t, httpReq, r)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+
+	// dotenv is the default format; only annotated resources under the path are rendered
+	httpReq, _ = http.NewRequest("GET", "/resources/render/app", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "DB_HOST=db.local\nGREETING=\"hello world\"\nMAX_ATTEMPTS=3\n", response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/resources/render/app?format=json", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.JSONEq(t, `{"DB_HOST": "db.local", "GREETING": "hello world", "MAX_ATTEMPTS": 3}`, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/resources/render/app/db?format=yaml", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	assert.Equal(t, "DB_HOST: db.local\n", response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/resources/render/app?format=toml", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Two resources rendered as the same variable are rejected
+	httpReq, _ = http.NewRequest("POST", "/resources", nil)
+	setRequestBodyAndHeader(t, httpReq, resource("/app/db", "attempts", `{"type": "integer"}`, "5", `{"env": "MAX_ATTEMPTS"}`))
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	httpReq, _ = http.NewRequest("GET", "/resources/render/app", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
+
+func TestResourceValueReferences(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "references-catalog"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code)
+	testContext.CatalogContext.Catalog = "references-catalog"
+	testContext.CatalogContext.Variant = "default"
+
+	resource := func(path, name, schema, value string) string {
+		return `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "` + name + `",
+				"path": "` + path + `"
+			},
+			"spec": {
+				"schema": ` + schema + `,
+				"value": ` + value + `
+			}
+		}`
+	}
+	for _, r := range []string{
+		resource("/shared", "endpoint", `{"type": "string"}`, `"https://api.example.com"`),
+		resource("/shared", "db", `{"type": "object"}`, `{"host": "db.local", "port": 5432}`),
+		resource("/app", "url", `{"type": "string"}`, `"{{ref:/shared/endpoint}}/v1"`),
+		resource("/app", "config", `{"type": "object"}`, `{"endpoint": "{{ref:/shared/endpoint}}", "dbPort": "{{ref:/shared/db#port}}", "url": "{{ref:url}}"}`),
+		resource("/app", "missing", `{"type": "string"}`, `"{{ref:/shared/nothing}}"`),
+		resource("/cycle", "a", `{"type": "string"}`, `"{{ref:/cycle/b}}"`),
+		resource("/cycle", "b", `{"type": "string"}`, `"{{ref:/cycle/a}}"`),
+	} {
+		httpReq, _ = http.NewRequest("POST", "/resources", nil)
+		setRequestBodyAndHeader(t, httpReq, r)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	}
+
+	get := func(path string) (int, string) {
+		httpReq, _ := http.NewRequest("GET", path, nil)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		return response.Code, response.Body.String()
+	}
+
+	// References within a string are replaced by the text of the value
+	code, body := get("/resources/app/url")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, `"https://api.example.com/v1"`, body)
+
+	// A string that is only a reference takes the referenced value, including fields of
+	// objects, and references are resolved transitively
+	code, body = get("/resources/app/config")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.JSONEq(t, `{"endpoint": "https://api.example.com", "dbPort": 5432, "url": "https://api.example.com/v1"}`, body)
+
+	// The definition keeps the references
+	code, body = get("/resources/definition/app/url")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, "{{ref:/shared/endpoint}}/v1", gjson.Get(body, "spec.value").String())
+
+	// Values follow the referenced resource
+	httpReq, _ = http.NewRequest("PUT", "/resources/shared/endpoint", nil)
+	setRequestBodyAndHeader(t, httpReq, `"https://api2.example.com"`)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	code, body = get("/resources/app/url")
+	require.Equal(t, http.StatusOK, code, body)
+	assert.Equal(t, `"https://api2.example.com/v1"`, body)
+
+	// Missing resources and cycles are errors
+	code, body = get("/resources/app/missing")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "/shared/nothing")
+	code, body = get("/resources/cycle/a")
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Contains(t, body, "/cycle/a -> /cycle/b -> /cycle/a")
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+)
+
+func TestSearchCatalog(t *testing.T) {
+	ctx := newDb()
+	t.Cleanup(func() {
+		db.DB(ctx).Close(ctx)
+	})
+
+	tenantID := catcommon.TenantId("TABCDE")
+	projectID := catcommon.ProjectId("PABCDE")
+
+	config.Config().DefaultProjectID = string(projectID)
+	config.Config().DefaultTenantID = string(tenantID)
+
+	ctx = catcommon.WithTenantID(ctx, tenantID)
+	ctx = catcommon.WithProjectID(ctx, projectID)
+
+	err := db.DB(ctx).CreateTenant(ctx, tenantID)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.DB(ctx).DeleteTenant(ctx, tenantID)
+	})
+
+	err = db.DB(ctx).CreateProject(ctx, projectID)
+	assert.NoError(t, err)
+	defer db.DB(ctx).DeleteProject(ctx, projectID)
+
+	testContext := TestContext{
+		TenantId:  tenantID,
+		ProjectId: projectID,
+	}
+
+	httpReq, _ := http.NewRequest("POST", "/catalogs", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Catalog",
+			"metadata": {
+				"name": "search-catalog",
+				"description": "Payment services"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response := executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	testContext.CatalogContext.Catalog = "search-catalog"
+
+	httpReq, _ = http.NewRequest("POST", "/variants", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Variant",
+			"metadata": {
+				"name": "search-variant"
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+	testContext.CatalogContext.Variant = "search-variant"
+
+	httpReq, _ = http.NewRequest("POST", "/resources", nil)
+	req = `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "Resource",
+			"metadata": {
+				"name": "http-client",
+				"path": "/payments",
+				"description": "Client settings"
+			},
+			"spec": {
+				"schema": {
+					"type": "object",
+					"properties": {
+						"maxRetries": {
+							"type": "integer"
+						}
+					}
+				},
+				"value": {
+					"maxRetries": 3
+				}
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	search := func(query string) []map[string]any {
+		httpReq, _ := http.NewRequest("GET", "/search?"+query, nil)
+		response := executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+		var rsp struct {
+			Hits []map[string]any `json:"hits"`
+		}
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+		return rsp.Hits
+	}
+
+	// Schema property names are found whole and by their camelCase parts
+	for _, q := range []string{"q=maxRetries", "q=retries", "q=MAXRET"} {
+		hits := search(q)
+		require.Len(t, hits, 1, q)
+		assert.Equal(t, catcommon.ResourceKind, hits[0]["kind"])
+		assert.Equal(t, "search-catalog", hits[0]["catalog"])
+		assert.Equal(t, "search-variant", hits[0]["variant"])
+		assert.Equal(t, "/payments", hits[0]["path"])
+		assert.Equal(t, "http-client", hits[0]["name"])
+		assert.Equal(t, "res://catalogs/search-catalog/variants/search-variant/resources/payments/http-client", hits[0]["resource"])
+	}
+
+	// Names, paths and descriptions are searched, and all words must match
+	hits := search("q=payment")
+	require.Len(t, hits, 2)
+	assert.ElementsMatch(t, []any{catcommon.CatalogKind, catcommon.ResourceKind}, []any{hits[0]["kind"], hits[1]["kind"]})
+	assert.Len(t, search("q=payment+client"), 1)
+	assert.Empty(t, search("q=payment+nothing"))
+
+	// Resource values are not searched
+	assert.Empty(t, search("q=3"))
+
+	// Hits can be narrowed by kind
+	hits = search("q=payment&kind=Catalog")
+	require.Len(t, hits, 1)
+	assert.Equal(t, catcommon.CatalogKind, hits[0]["kind"])
+
+	httpReq, _ = http.NewRequest("GET", "/search", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	httpReq, _ = http.NewRequest("GET", "/search?q=payment&kind=View", nil)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
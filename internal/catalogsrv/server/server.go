@@ -1,27 +1,38 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/cors"
 	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/apikeys"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/apis"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/auth"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/keymanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/session"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/tangent"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/tenants"
+	"github.com/tansive/tansive-internal/internal/common/health"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
 	"github.com/tansive/tansive-internal/internal/common/logtrace"
+	"github.com/tansive/tansive-internal/internal/common/metrics"
 	commonmiddleware "github.com/tansive/tansive-internal/internal/common/middleware"
 )
 
 type CatalogServer struct {
-	Router *chi.Mux
-	km     keymanager.KeyManager
+	Router  *chi.Mux
+	Probes  *health.Probes    // checks of the /healthz, /livez and /readyz endpoints
+	Metrics *metrics.Registry // metrics served on the metrics port
+	km      keymanager.KeyManager
 }
 
 func CreateNewServer() (*CatalogServer, error) {
@@ -31,16 +42,34 @@ func CreateNewServer() (*CatalogServer, error) {
 	// Use the singleton key manager instance
 	s.km = keymanager.GetKeyManager()
 
+	s.Probes = health.NewProbes()
+	s.Probes.AddReadiness("config", checkConfig)
+	s.Probes.AddReadiness("database", checkDatabase)
+
+	s.Metrics = metrics.NewRegistry("catalogsrv")
+	s.Metrics.AddCollector(collectDBMetrics)
+	// Pool and collector statistics are served with the metrics, so they are not exposed
+	// with the API
+	s.Metrics.Handle("/metrics/db", http.HandlerFunc(s.getDBMetrics))
+	s.Metrics.Handle("/metrics/objectgc", http.HandlerFunc(s.getObjectGCMetrics))
+	s.Metrics.AddCollector(collectObjectGCMetrics)
+
 	return s, nil
 }
 
 func (s *CatalogServer) MountHandlers() {
+	if config.Config().MetricsPort != "" {
+		s.Router.Use(s.Metrics.Middleware)
+	}
 	s.Router.Use(commonmiddleware.RequestLogger)
 	s.Router.Use(commonmiddleware.PanicHandler)
+	s.Router.Use(commonmiddleware.LimitRequestBody(config.Config().MaxRequestBodySize))
+	s.Router.Use(commonmiddleware.HandlerTimeout(s.Router, handlerTimeouts()))
 	s.Router.Use(db.LoadScopedDBMiddleware)
 	if config.Config().HandleCORS {
 		s.Router.Use(s.HandleCORS)
 	}
+	s.Router.Use(commonmiddleware.NegotiateYAML)
 	//s.Router.Route("/", s.mountResourceHandlers)
 	s.mountResourceHandlers(s.Router)
 	if logtrace.IsTraceEnabled() {
@@ -56,13 +85,26 @@ func (s *CatalogServer) MountHandlers() {
 	}
 }
 
+// handlerTimeouts returns the handler timeouts of the configuration, which were checked when
+// it was loaded. Timeouts that are not set are off.
+func handlerTimeouts() commonmiddleware.RouteTimeouts {
+	timeout, _ := config.Config().HTTP.GetHandlerTimeout()
+	routes, _ := config.Config().HTTP.GetRouteTimeouts()
+	return commonmiddleware.RouteTimeouts{Default: timeout, Routes: routes}
+}
+
 func (s *CatalogServer) mountResourceHandlers(r chi.Router) {
 	apis.Router(r)
 	r.Mount("/auth", auth.Router(r))
+	r.Mount("/apikeys", apikeys.Router())
 	r.Mount("/sessions", session.Router())
 	r.Mount("/tangents", tangent.Router())
+	r.Mount("/tenant", tenants.Router())
+	r.Mount("/tenants", tenants.PlatformRouter())
+	r.Mount("/projects", tenants.ProjectsRouter())
 	r.Get("/version", s.getVersion)
 	r.Get("/ready", s.getReadiness)
+	s.Probes.Router(r)
 	r.Get("/.well-known/jwks.json", auth.GetJWKSHandler(s.km))
 }
 
@@ -84,8 +126,7 @@ func (s *CatalogServer) getReadiness(w http.ResponseWriter, r *http.Request) {
 	log.Ctx(r.Context()).Debug().Msg("Readiness check")
 
 	// Check if we can get a database connection
-	ctx, err := db.ConnCtx(r.Context())
-	if err != nil {
+	if err := checkDatabase(r.Context()); err != nil {
 		log.Ctx(r.Context()).Error().Err(err).Msg("Database connection failed during readiness check")
 		httpx.SendJsonRsp(r.Context(), w, http.StatusServiceUnavailable, map[string]string{
 			"status": "not ready",
@@ -93,7 +134,6 @@ func (s *CatalogServer) getReadiness(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	defer db.DB(ctx).Close(ctx)
 
 	// If we get here, the server is ready
 	httpx.SendJsonRsp(r.Context(), w, http.StatusOK, map[string]string{
@@ -101,20 +141,114 @@ func (s *CatalogServer) getReadiness(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-func (s *CatalogServer) HandleCORS(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Set CORS headers
-		w.Header().Set("Access-Control-Allow-Origin", "http://local.tansive.dev:8190")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")                                                       // Allowed methods
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, Authorization, X-Hatch-IDToken") // Allowed headers
-
-		// Check if the request method is OPTIONS
-		if r.Method == "OPTIONS" {
-			log.Ctx(r.Context()).Debug().Msg("OPTIONS request")
-			// Respond with appropriate headers and no body
-			w.WriteHeader(http.StatusOK)
-			return
+// checkDatabase reports an error if a database connection cannot be acquired.
+func checkDatabase(ctx context.Context) error {
+	ctx, err := db.ConnCtx(ctx)
+	if err != nil {
+		return err
+	}
+	db.DB(ctx).Close(ctx)
+	return nil
+}
+
+// checkConfig reports an error if the configuration was not loaded.
+func checkConfig(ctx context.Context) error {
+	if config.Config() == nil {
+		return errors.New("configuration not loaded")
+	}
+	return nil
+}
+
+type DBPoolMetrics struct {
+	Name              string     `json:"name"`
+	MaxOpenConns      int        `json:"maxOpenConns"`
+	OpenConns         int        `json:"openConns"`
+	AcquiredConns     int        `json:"acquiredConns"`
+	IdleConns         int        `json:"idleConns"`
+	WaitCount         int64      `json:"waitCount"`
+	WaitDurationMs    int64      `json:"waitDurationMs"`
+	MaxIdleClosed     int64      `json:"maxIdleClosed"`
+	MaxLifetimeClosed int64      `json:"maxLifetimeClosed"`
+	ConnRequests      uint64     `json:"connRequests"`
+	ConnReturns       uint64     `json:"connReturns"`
+	Healthy           bool       `json:"healthy"`
+	LastHealthCheck   *time.Time `json:"lastHealthCheck,omitempty"`
+}
+
+type GetDBMetricsRsp struct {
+	Pools []DBPoolMetrics `json:"pools"`
+}
+
+// getDBMetrics reports the statistics of the database connection pools, so that pool
+// exhaustion and waits can be monitored and the pool settings tuned.
+func (s *CatalogServer) getDBMetrics(w http.ResponseWriter, r *http.Request) {
+	rsp := &GetDBMetricsRsp{Pools: []DBPoolMetrics{}}
+	for _, p := range db.PoolStats() {
+		m := DBPoolMetrics{
+			Name:              p.Name,
+			MaxOpenConns:      p.MaxOpenConns,
+			OpenConns:         p.OpenConns,
+			AcquiredConns:     p.AcquiredConns,
+			IdleConns:         p.IdleConns,
+			WaitCount:         p.WaitCount,
+			WaitDurationMs:    p.WaitDuration.Milliseconds(),
+			MaxIdleClosed:     p.MaxIdleClosed,
+			MaxLifetimeClosed: p.MaxLifetimeClosed,
+			ConnRequests:      p.ConnRequests,
+			ConnReturns:       p.ConnReturns,
+			Healthy:           p.Healthy,
 		}
-		next.ServeHTTP(w, r)
-	})
+		if !p.LastHealthCheck.IsZero() {
+			m.LastHealthCheck = &p.LastHealthCheck
+		}
+		rsp.Pools = append(rsp.Pools, m)
+	}
+	httpx.SendJsonRsp(r.Context(), w, http.StatusOK, rsp)
+}
+
+// collectDBMetrics adds the statistics of the database connection pools to the metrics.
+func collectDBMetrics(w *metrics.Writer) {
+	for _, p := range db.PoolStats() {
+		healthy := 0.0
+		if p.Healthy {
+			healthy = 1
+		}
+		w.Gauge("catalogsrv_db_pool_max_open_conns", "Maximum number of open connections.", float64(p.MaxOpenConns), "pool", p.Name)
+		w.Gauge("catalogsrv_db_pool_open_conns", "Established connections, in use or idle.", float64(p.OpenConns), "pool", p.Name)
+		w.Gauge("catalogsrv_db_pool_acquired_conns", "Connections currently in use.", float64(p.AcquiredConns), "pool", p.Name)
+		w.Gauge("catalogsrv_db_pool_idle_conns", "Idle connections.", float64(p.IdleConns), "pool", p.Name)
+		w.Counter("catalogsrv_db_pool_waits_total", "Waits for a connection.", float64(p.WaitCount), "pool", p.Name)
+		w.Counter("catalogsrv_db_pool_wait_seconds_total", "Time spent waiting for a connection.", p.WaitDuration.Seconds(), "pool", p.Name)
+		w.Gauge("catalogsrv_db_pool_healthy", "Whether the last health check of the pool succeeded.", healthy, "pool", p.Name)
+	}
+}
+
+// collectObjectGCMetrics adds the statistics of the catalog object collector to the metrics.
+func collectObjectGCMetrics(w *metrics.Writer) {
+	stats := catalogmanager.GetObjectGCStats()
+	w.Counter("catalogsrv_objectgc_runs_total", "Runs of the catalog object collector.", float64(stats.Runs))
+	w.Counter("catalogsrv_objectgc_failures_total", "Failed runs of the catalog object collector.", float64(stats.Failures))
+	w.Counter("catalogsrv_objectgc_objects_deleted_total", "Unreferenced catalog objects deleted.", float64(stats.ObjectsDeleted))
+	w.Counter("catalogsrv_objectgc_bytes_reclaimed_total", "Storage reclaimed from deleted catalog objects.", float64(stats.BytesReclaimed))
+}
+
+// getObjectGCMetrics reports how many unreferenced catalog objects have been collected and
+// how much storage they used.
+func (s *CatalogServer) getObjectGCMetrics(w http.ResponseWriter, r *http.Request) {
+	httpx.SendJsonRsp(r.Context(), w, http.StatusOK, catalogmanager.GetObjectGCStats())
+}
+
+// HandleCORS applies the cross-origin policy of the configuration, so that a browser-based
+// console on another origin can call the server. Preflight requests are answered without
+// reaching the API.
+func (s *CatalogServer) HandleCORS(next http.Handler) http.Handler {
+	c := config.Config().CORS
+	return cors.Handler(cors.Options{
+		AllowedOrigins:   c.AllowedOrigins,
+		AllowedMethods:   c.AllowedMethods,
+		AllowedHeaders:   c.AllowedHeaders,
+		ExposedHeaders:   c.ExposedHeaders,
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	})(next)
 }
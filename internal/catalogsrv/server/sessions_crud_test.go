@@ -339,6 +339,26 @@ func TestSessionCrud(t *testing.T) {
 		err = json.Unmarshal(response.Body.Bytes(), &executionState)
 		assert.NoError(t, err)
 
+		// The execution state names the user who created the session, which a tangent
+		// serves the session to; a session token does not identify a user
+		assert.NotEmpty(t, executionState.UserID)
+		httpReq, _ = http.NewRequest("GET", "/sessions/caller", nil)
+		response = executeTestRequest(t, httpReq, nil, testContext)
+		require.Equal(t, http.StatusOK, response.Code)
+		var caller session.SessionCaller
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &caller))
+		assert.Equal(t, executionState.UserID, caller.UserID)
+		httpReq, _ = http.NewRequest("GET", "/sessions/caller", nil)
+		httpReq.Header.Set("Authorization", "Bearer "+tokenResp.Token)
+		response = executeTestRequest(t, httpReq, nil)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+
+		// A session cannot approve its own invocations
+		httpReq, _ = http.NewRequest("GET", "/sessions/"+executionState.SessionID.String()+"/approver", nil)
+		httpReq.Header.Set("Authorization", "Bearer "+tokenResp.Token)
+		response = executeTestRequest(t, httpReq, nil)
+		assert.Equal(t, http.StatusForbidden, response.Code)
+
 		// Update execution state - this uses tangentAuthMiddleware
 		httpReq, _ = http.NewRequest("PUT", "/sessions/execution-state", nil)
 		httpReq.Header.Set("Authorization", "Bearer "+tokenResp.Token)
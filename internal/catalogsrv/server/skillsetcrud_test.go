@@ -564,10 +564,35 @@ func TestSkillSetList(t *testing.T) {
 	response = executeTestRequest(t, httpReq, nil, testContext)
 	require.Equal(t, http.StatusOK, response.Code)
 
-	var result = make(map[string]json.RawMessage)
+	var result struct {
+		Items      []json.RawMessage `json:"skillsets"`
+		NextCursor string            `json:"nextCursor"`
+	}
 	err = json.Unmarshal(response.Body.Bytes(), &result)
 	assert.NoError(t, err)
 
 	// All skillsets should be present
-	assert.Len(t, result, 3)
+	assert.Len(t, result.Items, 3)
+	assert.Empty(t, result.NextCursor)
+
+	// Page through the list two at a time
+	httpReq, _ = http.NewRequest("GET", "/skillsets?catalog=list-catalog&variant=list-variant&limit=2", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	result.NextCursor = ""
+	err = json.Unmarshal(response.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 2)
+	require.NotEmpty(t, result.NextCursor)
+
+	httpReq, _ = http.NewRequest("GET", "/skillsets?catalog=list-catalog&variant=list-variant&limit=2&cursor="+result.NextCursor, nil)
+	httpReq.Header.Set("Authorization", "Bearer "+config.Config().Auth.TestUserToken)
+	response = executeTestRequest(t, httpReq, nil, testContext)
+	require.Equal(t, http.StatusOK, response.Code)
+	result.NextCursor = ""
+	err = json.Unmarshal(response.Body.Bytes(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result.Items, 1)
+	assert.Empty(t, result.NextCursor)
 }
@@ -90,6 +90,43 @@ func TestTangentCrud(t *testing.T) {
 		assert.Equal(t, req.PublicKeyAccessKey, createdTangent.PublicKey)
 	})
 
+	// Test heartbeats
+	t.Run("heartbeat", func(t *testing.T) {
+		id := uuid.New()
+		httpReq, _ := http.NewRequest("POST", "/tangents", nil)
+		body, err := json.Marshal(&tangent.TangentInfo{
+			ID:                 id,
+			URL:                "http://test.tansive.dev:8468",
+			Capabilities:       []catcommon.RunnerID{catcommon.StdioRunnerID},
+			PublicKeyAccessKey: []byte("test-access-key"),
+		})
+		require.NoError(t, err)
+		setRequestBodyAndHeader(t, httpReq, string(body))
+		response := executeTestRequest(t, httpReq, nil)
+		require.Equal(t, http.StatusCreated, response.Code)
+
+		httpReq, _ = http.NewRequest("POST", "/tangents/"+id.String()+"/heartbeat", nil)
+		setRequestBodyAndHeader(t, httpReq, `{"status": "healthy", "version": "0.1.0", "activeSessions": 2, "intervalSeconds": 30}`)
+		response = executeTestRequest(t, httpReq, nil)
+		assert.Equal(t, http.StatusNoContent, response.Code)
+
+		stored, err := db.DB(ctx).GetTangent(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, tangent.HealthHealthy, stored.Status)
+		require.NotNil(t, stored.LastHeartbeatAt)
+
+		// A tangent that is not registered is told so
+		httpReq, _ = http.NewRequest("POST", "/tangents/"+uuid.New().String()+"/heartbeat", nil)
+		setRequestBodyAndHeader(t, httpReq, `{"status": "healthy", "intervalSeconds": 30}`)
+		response = executeTestRequest(t, httpReq, nil)
+		assert.Equal(t, http.StatusNotFound, response.Code)
+
+		httpReq, _ = http.NewRequest("POST", "/tangents/"+id.String()+"/heartbeat", nil)
+		setRequestBodyAndHeader(t, httpReq, `{"status": "sleepy", "intervalSeconds": 30}`)
+		response = executeTestRequest(t, httpReq, nil)
+		assert.Equal(t, http.StatusBadRequest, response.Code)
+	})
+
 	// Test invalid tangent creation
 	tests := []struct {
 		name        string
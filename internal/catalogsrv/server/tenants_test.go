@@ -0,0 +1,299 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/userauth"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestTenantRoles(t *testing.T) {
+	setup := setupTest(t)
+
+	bobToken, _, err := userauth.CreateIdentityToken(setup.ctx, map[string]any{
+		"token_use": catcommon.IdentityTokenType,
+		"sub":       "user/bob",
+	})
+	require.NoError(t, err)
+
+	request := func(method, path, body, bearer string) int {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		if body != "" {
+			setRequestBodyAndHeader(t, httpReq, body)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		response := executeTestRequest(t, httpReq, nil)
+		return response.Code
+	}
+
+	// Users without a role cannot administer the tenant
+	require.Equal(t, http.StatusForbidden, request(http.MethodGet, "/tenant", "", setup.userToken))
+
+	err = db.DB(setup.ctx).SetTenantRole(setup.ctx, &models.TenantRole{
+		UserID: catcommon.SingleUserID,
+		Role:   "owner",
+	})
+	require.NoError(t, err)
+
+	httpReq, _ := http.NewRequest(http.MethodGet, "/tenant", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+setup.userToken)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var tenant struct {
+		TenantID string `json:"tenant_id"`
+		Role     string `json:"role"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &tenant))
+	require.Equal(t, string(setup.tenantID), tenant.TenantID)
+	require.Equal(t, "owner", tenant.Role)
+
+	// Tokens narrowed to a view do not carry the user's tenant role
+	accessToken := adoptDefaultView(t, "test-catalog", setup.userToken)
+	require.Equal(t, http.StatusForbidden, request(http.MethodGet, "/tenant", "", accessToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPut, "/tenant/roles/bob", `{"role": "owner"}`, accessToken))
+
+	// Viewers can read but not create projects
+	require.Equal(t, http.StatusOK, request(http.MethodPut, "/tenant/roles/bob", `{"role": "viewer"}`, setup.userToken))
+	require.Equal(t, http.StatusOK, request(http.MethodGet, "/tenant/projects", "", bobToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPost, "/tenant/projects", `{"project_id": "P2"}`, bobToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPost, "/tenant/gc", "", bobToken))
+
+	// Admins can create and delete projects but not grant roles
+	require.Equal(t, http.StatusOK, request(http.MethodPut, "/tenant/roles/bob", `{"role": "admin"}`, setup.userToken))
+	require.Equal(t, http.StatusCreated, request(http.MethodPost, "/tenant/projects", `{"project_id": "P2"}`, bobToken))
+	require.Equal(t, http.StatusOK, request(http.MethodPost, "/tenant/gc", "", bobToken))
+	require.Equal(t, http.StatusConflict, request(http.MethodPost, "/tenant/projects", `{"project_id": "P2"}`, bobToken))
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/tenant/projects", `{"project_id": "not a project"}`, bobToken))
+
+	httpReq, _ = http.NewRequest(http.MethodGet, "/tenant/projects", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+bobToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var projects struct {
+		Items []struct {
+			ProjectID string `json:"project_id"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &projects))
+	require.Len(t, projects.Items, 2)
+
+	require.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/tenant/projects/P2", "", bobToken))
+	require.Equal(t, http.StatusNotFound, request(http.MethodDelete, "/tenant/projects/P2", "", bobToken))
+	require.Equal(t, http.StatusBadRequest, request(http.MethodDelete, "/tenant/projects/"+string(setup.projectID), "", bobToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPut, "/tenant/roles/carol", `{"role": "viewer"}`, bobToken))
+
+	// Roles are validated and the last owner cannot be removed
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPut, "/tenant/roles/bob", `{"role": "superuser"}`, setup.userToken))
+	require.Equal(t, http.StatusConflict, request(http.MethodPut, "/tenant/roles/"+catcommon.SingleUserID, `{"role": "admin"}`, setup.userToken))
+	require.Equal(t, http.StatusConflict, request(http.MethodDelete, "/tenant/roles/"+catcommon.SingleUserID, "", setup.userToken))
+
+	// Once there is another owner, the first one can step down
+	require.Equal(t, http.StatusOK, request(http.MethodPut, "/tenant/roles/bob", `{"role": "owner"}`, setup.userToken))
+	require.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/tenant/roles/"+catcommon.SingleUserID, "", setup.userToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodGet, "/tenant/roles", "", setup.userToken))
+	require.Equal(t, http.StatusNotFound, request(http.MethodDelete, "/tenant/roles/"+catcommon.SingleUserID, "", bobToken))
+}
+
+func TestProjects(t *testing.T) {
+	setup := setupTest(t)
+
+	bobToken, _, err := userauth.CreateIdentityToken(setup.ctx, map[string]any{
+		"token_use": catcommon.IdentityTokenType,
+		"sub":       "user/bob",
+	})
+	require.NoError(t, err)
+
+	request := func(method, path, body, bearer string) (int, []byte) {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		if body != "" {
+			setRequestBodyAndHeader(t, httpReq, body)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		response := executeTestRequest(t, httpReq, nil)
+		return response.Code, response.Body.Bytes()
+	}
+	catalogNames := func(projectID string) []string {
+		code, body := request(http.MethodGet, "/projects/"+projectID+"/catalogs", "", bobToken)
+		require.Equal(t, http.StatusOK, code)
+		var catalogs struct {
+			Items []struct {
+				Name string `json:"name"`
+			} `json:"items"`
+		}
+		require.NoError(t, json.Unmarshal(body, &catalogs))
+		names := []string{}
+		for _, item := range catalogs.Items {
+			names = append(names, item.Name)
+		}
+		return names
+	}
+
+	err = db.DB(setup.ctx).SetTenantRole(setup.ctx, &models.TenantRole{
+		UserID: catcommon.SingleUserID,
+		Role:   "owner",
+	})
+	require.NoError(t, err)
+	code, _ := request(http.MethodPut, "/tenant/roles/bob", `{"role": "viewer"}`, setup.userToken)
+	require.Equal(t, http.StatusOK, code)
+
+	code, _ = request(http.MethodPost, "/projects", `{"project_id": "P2"}`, setup.userToken)
+	require.Equal(t, http.StatusCreated, code)
+	code, _ = request(http.MethodGet, "/projects/P2", "", bobToken)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = request(http.MethodGet, "/projects/PNONE", "", bobToken)
+	require.Equal(t, http.StatusNotFound, code)
+	require.Equal(t, []string{"test-catalog"}, catalogNames(string(setup.projectID)))
+	require.Empty(t, catalogNames("P2"))
+
+	// Viewers cannot move catalogs
+	movePath := "/projects/" + string(setup.projectID) + "/catalogs/test-catalog/move"
+	code, _ = request(http.MethodPost, movePath, `{"to_project_id": "P2"}`, bobToken)
+	require.Equal(t, http.StatusForbidden, code)
+
+	code, body := request(http.MethodPost, movePath, `{"to_project_id": "P2"}`, setup.userToken)
+	require.Equal(t, http.StatusOK, code)
+	var moved struct {
+		Name      string `json:"name"`
+		ProjectID string `json:"project_id"`
+	}
+	require.NoError(t, json.Unmarshal(body, &moved))
+	require.Equal(t, "test-catalog", moved.Name)
+	require.Equal(t, "P2", moved.ProjectID)
+	require.Empty(t, catalogNames(string(setup.projectID)))
+	require.Equal(t, []string{"test-catalog"}, catalogNames("P2"))
+
+	code, _ = request(http.MethodPost, movePath, `{"to_project_id": "P2"}`, setup.userToken)
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = request(http.MethodPost, "/projects/P2/catalogs/test-catalog/move", `{"to_project_id": "PNONE"}`, setup.userToken)
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = request(http.MethodPost, "/projects/P2/catalogs/test-catalog/move", `{"to_project_id": "P2"}`, setup.userToken)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	// Projects with catalogs cannot be deleted
+	code, _ = request(http.MethodDelete, "/projects/P2", "", setup.userToken)
+	require.Equal(t, http.StatusConflict, code)
+	code, _ = request(http.MethodPost, "/projects/P2/catalogs/test-catalog/move", `{"to_project_id": "`+string(setup.projectID)+`"}`, setup.userToken)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = request(http.MethodDelete, "/projects/P2", "", setup.userToken)
+	require.Equal(t, http.StatusNoContent, code)
+}
+
+func TestPlatformTenants(t *testing.T) {
+	setup := setupTest(t)
+	t.Cleanup(func() {
+		_ = db.DB(setup.ctx).DeleteTenant(setup.ctx, "TPLAT1")
+	})
+
+	bobToken, _, err := userauth.CreateIdentityToken(setup.ctx, map[string]any{
+		"token_use": catcommon.IdentityTokenType,
+		"sub":       "user/bob",
+	})
+	require.NoError(t, err)
+
+	request := func(method, path, body, bearer string) (int, map[string]any) {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		if body != "" {
+			setRequestBodyAndHeader(t, httpReq, body)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		response := executeTestRequest(t, httpReq, nil)
+		var rsp map[string]any
+		_ = json.Unmarshal(response.Body.Bytes(), &rsp)
+		return response.Code, rsp
+	}
+
+	// Only platform admins can administer tenants; the single user is one
+	code, _ := request(http.MethodGet, "/tenants", "", bobToken)
+	require.Equal(t, http.StatusForbidden, code)
+	code, _ = request(http.MethodGet, "/tenants", "", adoptDefaultView(t, "test-catalog", setup.userToken))
+	require.Equal(t, http.StatusForbidden, code)
+
+	code, rsp := request(http.MethodPost, "/tenants", `{"tenant_id": "TPLAT1", "project_id": "P1", "owner": "bob"}`, setup.userToken)
+	require.Equal(t, http.StatusCreated, code)
+	require.Equal(t, "TPLAT1", rsp["tenant_id"])
+	require.Equal(t, "active", rsp["status"])
+	code, _ = request(http.MethodPost, "/tenants", `{"tenant_id": "TPLAT1"}`, setup.userToken)
+	require.Equal(t, http.StatusConflict, code)
+	code, _ = request(http.MethodPost, "/tenants", `{"tenant_id": "not a tenant"}`, setup.userToken)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	// The tenant is created with its project and owner
+	tctx := catcommon.WithTenantID(setup.ctx, "TPLAT1")
+	_, err = db.DB(tctx).GetProject(tctx, "P1")
+	require.NoError(t, err)
+	role, err := db.DB(tctx).GetTenantRole(tctx, "bob")
+	require.NoError(t, err)
+	require.Equal(t, "owner", role.Role)
+
+	config.Config().Auth.PlatformAdmins = []string{"bob"}
+	t.Cleanup(func() {
+		config.Config().Auth.PlatformAdmins = nil
+	})
+	httpReq, _ := http.NewRequest(http.MethodGet, "/tenants", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+bobToken)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var tenants struct {
+		Items []struct {
+			TenantID string `json:"tenant_id"`
+		} `json:"items"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &tenants))
+	var ids []string
+	for _, item := range tenants.Items {
+		ids = append(ids, item.TenantID)
+	}
+	require.Contains(t, ids, "TPLAT1")
+	require.Contains(t, ids, string(setup.tenantID))
+
+	// Tenants must be suspended before they are deleted, and callers cannot suspend or
+	// delete their own tenant
+	code, _ = request(http.MethodDelete, "/tenants/TPLAT1", "", bobToken)
+	require.Equal(t, http.StatusConflict, code)
+	code, _ = request(http.MethodPost, "/tenants/"+string(setup.tenantID)+"/suspend", "", bobToken)
+	require.Equal(t, http.StatusBadRequest, code)
+	code, _ = request(http.MethodDelete, "/tenants/"+string(setup.tenantID), "", bobToken)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	code, rsp = request(http.MethodPost, "/tenants/TPLAT1/suspend", "", bobToken)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "suspended", rsp["status"])
+	require.NotEmpty(t, rsp["suspended_at"])
+	code, rsp = request(http.MethodPost, "/tenants/TPLAT1/resume", "", bobToken)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "active", rsp["status"])
+
+	code, _ = request(http.MethodPost, "/tenants/TPLAT1/suspend", "", bobToken)
+	require.Equal(t, http.StatusOK, code)
+	code, _ = request(http.MethodDelete, "/tenants/TPLAT1", "", bobToken)
+	require.Equal(t, http.StatusNoContent, code)
+	code, _ = request(http.MethodGet, "/tenants/TPLAT1", "", bobToken)
+	require.Equal(t, http.StatusNotFound, code)
+	code, _ = request(http.MethodPost, "/tenants/TPLAT1/resume", "", bobToken)
+	require.Equal(t, http.StatusNotFound, code)
+}
+
+func TestSuspendedTenantRefused(t *testing.T) {
+	setup := setupTest(t)
+
+	request := func() *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest(http.MethodGet, "/tenants", nil)
+		httpReq.Header.Set("Authorization", "Bearer "+setup.userToken)
+		return executeTestRequest(t, httpReq, nil)
+	}
+
+	require.NoError(t, db.DB(setup.ctx).SetTenantSuspended(setup.ctx, setup.tenantID, true))
+	response := request()
+	require.Equal(t, http.StatusForbidden, response.Code)
+	require.Contains(t, response.Body.String(), "tenant_suspended")
+
+	require.NoError(t, db.DB(setup.ctx).SetTenantSuspended(setup.ctx, setup.tenantID, false))
+	require.Equal(t, http.StatusOK, request().Code)
+}
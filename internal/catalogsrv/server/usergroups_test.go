@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth/userauth"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+func TestUserGroups(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	require.NoError(t, db.DB(setup.ctx).SetTenantRole(setup.ctx, &models.TenantRole{
+		UserID: catcommon.SingleUserID,
+		Role:   "owner",
+	}))
+
+	request := func(method, path, body, bearer string) int {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		if body != "" {
+			setRequestBodyAndHeader(t, httpReq, body)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+bearer)
+		response := executeTestRequest(t, httpReq, nil)
+		return response.Code
+	}
+
+	// Create a view assigned to the analysts group
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "analyst-view",
+				"catalog": "test-catalog",
+				"variant": "test-variant"
+			},
+			"spec": {
+				"groups": ["analysts"],
+				"rules": [{
+					"intent": "Allow",
+					"actions": ["system.resource.get"],
+					"targets": ["res://resources/*"]
+				}]
+			}
+		}`
+	require.Equal(t, http.StatusCreated, request(http.MethodPost, "/views", req, token))
+
+	httpReq, _ := http.NewRequest(http.MethodGet, "/views/analyst-view", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var view struct {
+		Spec struct {
+			Groups []string `json:"groups"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &view))
+	require.Equal(t, []string{"analysts"}, view.Spec.Groups)
+
+	// Provision users and groups
+	require.Equal(t, http.StatusCreated, request(http.MethodPost, "/tenant/users", `{"user_id": "alice", "email": "alice@example.com"}`, setup.userToken))
+	require.Equal(t, http.StatusConflict, request(http.MethodPost, "/tenant/users", `{"user_id": "alice"}`, setup.userToken))
+	require.Equal(t, http.StatusBadRequest, request(http.MethodPost, "/tenant/users", `{"user_id": "not a user"}`, setup.userToken))
+	require.Equal(t, http.StatusCreated, request(http.MethodPost, "/tenant/users", `{"user_id": "bob"}`, setup.userToken))
+	require.Equal(t, http.StatusCreated, request(http.MethodPost, "/tenant/groups", `{"name": "analysts"}`, setup.userToken))
+	require.Equal(t, http.StatusConflict, request(http.MethodPost, "/tenant/groups", `{"name": "analysts"}`, setup.userToken))
+	require.Equal(t, http.StatusNoContent, request(http.MethodPut, "/tenant/groups/analysts/members/alice", "", setup.userToken))
+	require.Equal(t, http.StatusNotFound, request(http.MethodPut, "/tenant/groups/analysts/members/carol", "", setup.userToken))
+	require.Equal(t, http.StatusNotFound, request(http.MethodPut, "/tenant/groups/engineers/members/alice", "", setup.userToken))
+
+	httpReq, _ = http.NewRequest(http.MethodGet, "/tenant/users/alice", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+setup.userToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var user struct {
+		UserID string   `json:"user_id"`
+		Email  string   `json:"email"`
+		Groups []string `json:"groups"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &user))
+	require.Equal(t, "alice", user.UserID)
+	require.Equal(t, "alice@example.com", user.Email)
+	require.Equal(t, []string{"analysts"}, user.Groups)
+
+	httpReq, _ = http.NewRequest(http.MethodGet, "/tenant/groups/analysts", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+setup.userToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var group struct {
+		Name    string   `json:"name"`
+		Members []string `json:"members"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &group))
+	require.Equal(t, []string{"alice"}, group.Members)
+
+	// Users without a tenant role cannot provision users
+	bobToken, _, err := userauth.CreateIdentityToken(setup.ctx, map[string]any{
+		"token_use": catcommon.IdentityTokenType,
+		"sub":       "user/bob",
+	})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, request(http.MethodPost, "/tenant/users", `{"user_id": "carol"}`, bobToken))
+
+	// Outside single user mode, only members of the view's groups can adopt it
+	config.Config().SingleUserMode = false
+	t.Cleanup(func() {
+		config.Config().SingleUserMode = true
+	})
+
+	aliceToken, _, err := userauth.CreateIdentityToken(setup.ctx, map[string]any{
+		"token_use": catcommon.IdentityTokenType,
+		"sub":       "user/alice",
+	})
+	require.NoError(t, err)
+
+	adoptPath := "/auth/view-adoptions/test-catalog/analyst-view?project=" + string(setup.projectID)
+	require.Equal(t, http.StatusOK, request(http.MethodPost, adoptPath, "", aliceToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPost, adoptPath, "", bobToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPost, "/auth/view-adoptions/test-catalog/read-only-view?project="+string(setup.projectID), "", aliceToken))
+
+	// Group membership does not extend to tokens narrowed to a view
+	httpReq, _ = http.NewRequest(http.MethodPost, adoptPath, nil)
+	httpReq.Header.Set("Authorization", "Bearer "+aliceToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var adoptResponse struct {
+		Token string `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &adoptResponse))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPost, adoptPath, "", adoptResponse.Token))
+
+	// Removing alice from the group revokes her access to the view
+	require.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/tenant/groups/analysts/members/alice", "", setup.userToken))
+	require.Equal(t, http.StatusForbidden, request(http.MethodPost, adoptPath, "", aliceToken))
+
+	require.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/tenant/groups/analysts", "", setup.userToken))
+	require.Equal(t, http.StatusNotFound, request(http.MethodDelete, "/tenant/groups/analysts", "", setup.userToken))
+	require.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/tenant/users/alice", "", setup.userToken))
+	require.Equal(t, http.StatusNotFound, request(http.MethodGet, "/tenant/users/alice", "", setup.userToken))
+}
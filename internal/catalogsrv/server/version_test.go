@@ -2,6 +2,7 @@ package server
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"encoding/json"
@@ -9,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/common/metrics"
 )
 
 func TestGetVersion(t *testing.T) {
@@ -98,3 +100,43 @@ func TestGetJWKS(t *testing.T) {
 	require.Equal(t, "Ed25519", jwks.Keys[0].Crv)
 	require.NotEmpty(t, jwks.Keys[0].X)
 }
+
+func TestGetDBMetrics(t *testing.T) {
+	newDb()
+	// Pool statistics are not served with the API
+	req, _ := http.NewRequest("GET", "/metrics/db", nil)
+	testContext := TestContext{
+		TenantId:  "tenant1",
+		ProjectId: "project1",
+	}
+	response := executeTestRequest(t, req, nil, testContext)
+	require.Equal(t, http.StatusNotFound, response.Code)
+
+	s, err := CreateNewServer()
+	require.NoError(t, err)
+	response = httptest.NewRecorder()
+	metrics.NewServer("0", s.Metrics).Handler.ServeHTTP(response, httptest.NewRequest("GET", "/metrics/db", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var rsp GetDBMetricsRsp
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+	require.NotEmpty(t, rsp.Pools)
+	primary := rsp.Pools[0]
+	require.Equal(t, "primary", primary.Name)
+	require.Equal(t, config.Config().DB.Pool.MaxOpenConns, primary.MaxOpenConns)
+	require.True(t, primary.Healthy)
+}
+
+func TestGetObjectGCMetrics(t *testing.T) {
+	newDb()
+	// Collector statistics are not served with the API
+	req, _ := http.NewRequest("GET", "/metrics/objectgc", nil)
+	response := executeTestRequest(t, req, nil)
+	require.Equal(t, http.StatusNotFound, response.Code)
+
+	s, err := CreateNewServer()
+	require.NoError(t, err)
+	response = httptest.NewRecorder()
+	metrics.NewServer("0", s.Metrics).Handler.ServeHTTP(response, httptest.NewRequest("GET", "/metrics/objectgc", nil))
+	require.Equal(t, http.StatusOK, response.Code)
+}
@@ -2,7 +2,9 @@ package server
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"encoding/json"
 
@@ -307,3 +309,443 @@ func TestViewList(t *testing.T) {
 
 	assert.Len(t, result.Views, 3)
 }
+
+func TestViewSimulate(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	simulate := func(view, body string) *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest(http.MethodPost, "/views/"+view+"/simulate", nil)
+		setRequestBodyAndHeader(t, httpReq, body)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		return executeTestRequest(t, httpReq, nil)
+	}
+
+	type simulationResult struct {
+		Action       string `json:"action"`
+		Target       string `json:"target"`
+		Allowed      bool   `json:"allowed"`
+		MatchedRules []struct {
+			Intent string `json:"intent"`
+		} `json:"matchedRules"`
+	}
+	var result struct {
+		View    string             `json:"view"`
+		Results []simulationResult `json:"results"`
+	}
+
+	// A single check
+	response := simulate("read-only-view", `{"action": "system.resource.get", "target": "res://resources/resource1"}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Equal(t, "read-only-view", result.View)
+	require.Len(t, result.Results, 1)
+	assert.True(t, result.Results[0].Allowed)
+	require.Len(t, result.Results[0].MatchedRules, 1)
+	assert.Equal(t, "Allow", result.Results[0].MatchedRules[0].Intent)
+
+	// A batch of checks
+	response = simulate("read-only-view", `
+		{
+			"checks": [
+				{"action": "system.resource.get", "target": "/resources/resource1"},
+				{"action": "system.resource.put", "target": "/resources/resource1"}
+			]
+		}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	require.Len(t, result.Results, 2)
+	assert.True(t, result.Results[0].Allowed)
+	assert.False(t, result.Results[1].Allowed)
+	assert.Empty(t, result.Results[1].MatchedRules)
+
+	// Simulating does not perform the action
+	httpReq, _ := http.NewRequest(http.MethodGet, "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	// Simulation with an explanation
+	response = simulate("read-only-view?explain=true", `{"action": "system.resource.put", "target": "/resources/resource1"}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	var explained struct {
+		Results []struct {
+			Explanation struct {
+				Reason         string `json:"reason"`
+				EvaluatedRules []any  `json:"evaluatedRules"`
+			} `json:"explanation"`
+		} `json:"results"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &explained))
+	require.Len(t, explained.Results, 1)
+	assert.Equal(t, "no rule allows the action on the target", explained.Results[0].Explanation.Reason)
+	assert.Len(t, explained.Results[0].Explanation.EvaluatedRules, 1)
+
+	// Denied requests are explained when the caller opts in
+	readOnlyToken := adoptView(t, "test-catalog", "read-only-view", token)
+	httpReq, _ = http.NewRequest(http.MethodDelete, "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+readOnlyToken)
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusForbidden, response.Code)
+	assert.NotContains(t, response.Body.String(), "explanation")
+
+	httpReq, _ = http.NewRequest(http.MethodDelete, "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+readOnlyToken)
+	httpReq.Header.Set("X-Tansive-Explain", "true")
+	response = executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusForbidden, response.Code)
+	var denied struct {
+		Error       string `json:"error"`
+		Explanation struct {
+			Allowed bool   `json:"allowed"`
+			Target  string `json:"target"`
+			Reason  string `json:"reason"`
+		} `json:"explanation"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &denied))
+	assert.NotEmpty(t, denied.Error)
+	assert.False(t, denied.Explanation.Allowed)
+	assert.Contains(t, denied.Explanation.Target, "/resources/resource1")
+	assert.Equal(t, "no rule allows the action on the target", denied.Explanation.Reason)
+
+	// Invalid requests
+	assert.Equal(t, http.StatusBadRequest, simulate("read-only-view?explain=maybe", `{"action": "system.resource.get", "target": "/resources/resource1"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, simulate("read-only-view", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, simulate("read-only-view", `{"action": "system.resource.unknown", "target": "/resources/resource1"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, simulate("read-only-view", `{"action": "system.resource.get"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, simulate("no-such-view", `{"action": "system.resource.get", "target": "/resources/resource1"}`).Code)
+}
+
+func TestTimeBoundedViews(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	createView := func(name, window string) int {
+		httpReq, _ := http.NewRequest(http.MethodPost, "/views", nil)
+		req := `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "View",
+				"metadata": {
+					"name": "` + name + `",
+					"catalog": "test-catalog",
+					"variant": "test-variant"
+				},
+				"spec": {
+					` + window + `
+					"rules": [{
+						"intent": "Allow",
+						"actions": ["system.resource.get"],
+						"targets": ["res://resources/*"]
+					}]
+				}
+			}`
+		setRequestBodyAndHeader(t, httpReq, req)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		return executeTestRequest(t, httpReq, nil).Code
+	}
+	adopt := func(name string) int {
+		httpReq, _ := http.NewRequest(http.MethodPost, "/auth/view-adoptions/test-catalog/"+name, nil)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		return executeTestRequest(t, httpReq, nil).Code
+	}
+
+	now := time.Now().UTC()
+	past := now.Add(-time.Hour).Format(time.RFC3339)
+	future := now.Add(time.Hour).Format(time.RFC3339)
+
+	require.Equal(t, http.StatusCreated, createView("break-glass", `"notBefore": "`+past+`", "expiresAt": "`+future+`",`))
+	require.Equal(t, http.StatusCreated, createView("expired-view", `"expiresAt": "`+past+`",`))
+	require.Equal(t, http.StatusCreated, createView("future-view", `"notBefore": "`+future+`",`))
+	require.Equal(t, http.StatusBadRequest, createView("inverted-view", `"notBefore": "`+future+`", "expiresAt": "`+past+`",`))
+
+	// The window is returned with the view
+	httpReq, _ := http.NewRequest(http.MethodGet, "/views/break-glass", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	response := executeTestRequest(t, httpReq, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+	var view struct {
+		Spec struct {
+			NotBefore *time.Time `json:"notBefore"`
+			ExpiresAt *time.Time `json:"expiresAt"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &view))
+	require.NotNil(t, view.Spec.NotBefore)
+	require.NotNil(t, view.Spec.ExpiresAt)
+	assert.Equal(t, future, view.Spec.ExpiresAt.UTC().Format(time.RFC3339))
+
+	// Only views within their window can be adopted
+	assert.Equal(t, http.StatusOK, adopt("break-glass"))
+	assert.Equal(t, http.StatusForbidden, adopt("expired-view"))
+	assert.Equal(t, http.StatusForbidden, adopt("future-view"))
+
+	// Tokens of a view stop working once it expires
+	breakGlassToken := adoptView(t, "test-catalog", "break-glass", token)
+	httpReq, _ = http.NewRequest(http.MethodGet, "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+breakGlassToken)
+	require.Equal(t, http.StatusOK, executeTestRequest(t, httpReq, nil).Code)
+
+	httpReq, _ = http.NewRequest(http.MethodPut, "/views/break-glass", nil)
+	req := `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {
+				"name": "break-glass",
+				"catalog": "test-catalog",
+				"variant": "test-variant"
+			},
+			"spec": {
+				"expiresAt": "` + past + `",
+				"rules": [{
+					"intent": "Allow",
+					"actions": ["system.resource.get"],
+					"targets": ["res://resources/*"]
+				}]
+			}
+		}`
+	setRequestBodyAndHeader(t, httpReq, req)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	require.Equal(t, http.StatusOK, executeTestRequest(t, httpReq, nil).Code)
+
+	httpReq, _ = http.NewRequest(http.MethodGet, "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+breakGlassToken)
+	assert.NotEqual(t, http.StatusOK, executeTestRequest(t, httpReq, nil).Code)
+}
+
+func TestViewPermissions(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	getPermissions := func(view string) *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest(http.MethodGet, "/views/"+view+"/permissions", nil)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		return executeTestRequest(t, httpReq, nil)
+	}
+
+	type permission struct {
+		Action      string `json:"action"`
+		Resource    string `json:"resource"`
+		Conditional bool   `json:"conditional"`
+	}
+	var result struct {
+		View        string       `json:"view"`
+		Permissions []permission `json:"permissions"`
+	}
+
+	response := getPermissions("read-only-view")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Equal(t, "read-only-view", result.View)
+	assert.ElementsMatch(t, []permission{
+		{Action: "system.resource.get", Resource: "res://catalogs/test-catalog/variants/test-variant/resources/resource1"},
+		{Action: "system.resource.get", Resource: "res://catalogs/test-catalog/variants/test-variant/resources/resource2"},
+	}, result.Permissions)
+
+	response = getPermissions("read-write-view")
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	assert.Len(t, result.Permissions, 4)
+
+	assert.Equal(t, http.StatusBadRequest, getPermissions("no-such-view").Code)
+}
+
+func TestViewIncludes(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		if body != "" {
+			setRequestBodyAndHeader(t, httpReq, body)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		return executeTestRequest(t, httpReq, nil)
+	}
+	view := func(name, spec string) string {
+		return `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "View",
+				"metadata": {
+					"name": "` + name + `",
+					"catalog": "test-catalog",
+					"variant": "test-variant"
+				},
+				"spec": ` + spec + `
+			}`
+	}
+
+	// A view with only includes gets the rules of the included view
+	response := do(http.MethodPost, "/views", view("reader", `{"includes": ["read-only-view"]}`))
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	// A view can add its own rules to those it includes
+	response = do(http.MethodPost, "/views", view("editor", `{
+		"includes": ["reader"],
+		"rules": [{"intent": "Allow", "actions": ["system.resource.put"], "targets": ["res://resources/*"]}]
+	}`))
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	// The spec keeps the includes
+	response = do(http.MethodGet, "/views/editor", "")
+	require.Equal(t, http.StatusOK, response.Code)
+	var got struct {
+		Spec struct {
+			Includes []string `json:"includes"`
+			Rules    []any    `json:"rules"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &got))
+	assert.Equal(t, []string{"reader"}, got.Spec.Includes)
+	assert.Len(t, got.Spec.Rules, 1)
+
+	// The rules of included views are merged when the view is loaded
+	var result struct {
+		Results []struct {
+			Allowed bool `json:"allowed"`
+		} `json:"results"`
+	}
+	response = do(http.MethodPost, "/views/editor/simulate", `{"checks": [
+		{"action": "system.resource.get", "target": "res://resources/resource1"},
+		{"action": "system.resource.put", "target": "res://resources/resource1"},
+		{"action": "system.resource.delete", "target": "res://resources/resource1"}
+	]}`)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &result))
+	require.Len(t, result.Results, 3)
+	assert.True(t, result.Results[0].Allowed)
+	assert.True(t, result.Results[1].Allowed)
+	assert.False(t, result.Results[2].Allowed)
+
+	// An adopted view carries the included rules
+	editorToken := adoptView(t, "test-catalog", "editor", token)
+	httpReq, _ := http.NewRequest(http.MethodGet, "/resources/resource1", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+editorToken)
+	assert.Equal(t, http.StatusOK, executeTestRequest(t, httpReq, nil).Code)
+
+	// Cycles are rejected
+	response = do(http.MethodPut, "/views/reader", view("reader", `{"includes": ["read-only-view", "editor"]}`))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	assert.Contains(t, response.Body.String(), "cycle")
+	response = do(http.MethodPost, "/views", view("self", `{"includes": ["self"]}`))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// Included views must exist and have the same scope
+	response = do(http.MethodPost, "/views", view("dangling", `{"includes": ["no-such-view"]}`))
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+	response = do(http.MethodPost, "/views", `
+		{
+			"apiVersion": "0.1.0-alpha.1",
+			"kind": "View",
+			"metadata": {"name": "catalog-reader", "catalog": "test-catalog"},
+			"spec": {"includes": ["read-only-view"]}
+		}`)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+
+	// A view that is included by another view cannot be deleted
+	response = do(http.MethodDelete, "/views/reader", "")
+	assert.Equal(t, http.StatusConflict, response.Code)
+	response = do(http.MethodDelete, "/views/editor", "")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+	response = do(http.MethodDelete, "/views/reader", "")
+	assert.Equal(t, http.StatusNoContent, response.Code)
+}
+
+func TestValidateViews(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	type validateRsp struct {
+		Valid   bool `json:"valid"`
+		Results []struct {
+			Name       string   `json:"name"`
+			Valid      bool     `json:"valid"`
+			Errors     []string `json:"errors"`
+			Violations []struct {
+				Rule    int    `json:"rule"`
+				Action  string `json:"action"`
+				Target  string `json:"target"`
+				Include string `json:"include"`
+			} `json:"violations"`
+		} `json:"results"`
+	}
+	validate := func(query, body string) (int, validateRsp) {
+		httpReq, _ := http.NewRequest(http.MethodPost, "/views:validate"+query, nil)
+		setRequestBodyAndHeader(t, httpReq, body)
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		var rsp validateRsp
+		if response.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(response.Body.Bytes(), &rsp))
+		}
+		return response.Code, rsp
+	}
+	view := func(name, spec string) string {
+		return `
+apiVersion: 0.1.0-alpha.1
+kind: View
+metadata:
+  name: ` + name + `
+  catalog: test-catalog
+  variant: test-variant
+spec: ` + spec + `
+`
+	}
+	getRule := `{"rules": [{"intent": "Allow", "actions": ["system.resource.get"], "targets": ["res://resources/*"]}]}`
+	putRule := `{"rules": [{"intent": "Allow", "actions": ["system.resource.put"], "targets": ["res://resources/resource1"]}]}`
+
+	// Without a parent only the documents themselves are checked
+	code, rsp := validate("", view("valid-view", getRule)+"---"+view("bad-view", `{"rules": [{"intent": "Maybe", "actions": ["system.resource.get"], "targets": ["res://resources/*"]}]}`))
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, rsp.Valid)
+	require.Len(t, rsp.Results, 2)
+	assert.Equal(t, "valid-view", rsp.Results[0].Name)
+	assert.True(t, rsp.Results[0].Valid)
+	assert.Equal(t, "bad-view", rsp.Results[1].Name)
+	assert.False(t, rsp.Results[1].Valid)
+	assert.NotEmpty(t, rsp.Results[1].Errors)
+
+	// Against a parent, rules the parent does not allow are reported
+	code, rsp = validate("?parent=read-only-view", view("reader", getRule)+"---"+view("writer", putRule))
+	require.Equal(t, http.StatusOK, code)
+	assert.False(t, rsp.Valid)
+	require.Len(t, rsp.Results, 2)
+	assert.True(t, rsp.Results[0].Valid)
+	assert.False(t, rsp.Results[1].Valid)
+	require.Len(t, rsp.Results[1].Violations, 1)
+	assert.Equal(t, 0, rsp.Results[1].Violations[0].Rule)
+	assert.Equal(t, "system.resource.put", rsp.Results[1].Violations[0].Action)
+	assert.Empty(t, rsp.Results[1].Violations[0].Include)
+
+	// Rules merged from included views are checked and attributed to the include
+	code, rsp = validate("?parent=read-only-view", view("includer", `{"includes": ["read-write-view"]}`))
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, rsp.Results, 1)
+	assert.False(t, rsp.Results[0].Valid)
+	require.NotEmpty(t, rsp.Results[0].Violations)
+	assert.Equal(t, "read-write-view", rsp.Results[0].Violations[0].Include)
+	assert.Equal(t, "system.resource.put", rsp.Results[0].Violations[0].Action)
+
+	// All documents valid
+	code, rsp = validate("?parent=read-write-view", view("reader", getRule)+"---"+view("writer", putRule))
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, rsp.Valid)
+
+	// Nothing is created by validation
+	httpReq, _ := http.NewRequest(http.MethodGet, "/views/writer", nil)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	assert.Equal(t, http.StatusBadRequest, executeTestRequest(t, httpReq, nil).Code)
+
+	// Unknown parents and empty requests are rejected
+	code, _ = validate("?parent=no-such-view", view("reader", getRule))
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = validate("", "")
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestViewTemplates(t *testing.T) {
+	setup := setupTest(t)
+	token := adoptDefaultView(t, "test-catalog", setup.userToken)
+	setupObjects(t, token)
+
+	request := func(method, path, body string) (int, []byte) {
+		httpReq, _ := http.NewRequest(method, path, nil)
+		if body != "" {
+			setRequestBodyAndHeader(t, httpReq, body)
+		}
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+		response := executeTestRequest(t, httpReq, nil)
+		return response.Code, response.Body.Bytes()
+	}
+	template := func(name, target string) string {
+		return `
+			{
+				"apiVersion": "0.1.0-alpha.1",
+				"kind": "ViewTemplate",
+				"metadata": {
+					"name": "` + name + `",
+					"catalog": "test-catalog",
+					"description": "read access to a namespace"
+				},
+				"spec": {
+					"rules": [{
+						"intent": "Allow",
+						"actions": ["system.resource.get", "system.resource.list"],
+						"targets": ["` + target + `"]
+					}]
+				}
+			}`
+	}
+
+	code, _ := request(http.MethodPost, "/viewtemplates", template("ns-reader", "res://variants/{{variant}}/namespaces/{{namespace}}/resources/*"))
+	require.Equal(t, http.StatusCreated, code)
+	code, _ = request(http.MethodPost, "/viewtemplates", template("ns-reader", "res://variants/{{variant}}/resources/*"))
+	assert.Equal(t, http.StatusConflict, code)
+	code, _ = request(http.MethodPost, "/viewtemplates", template("bad-template", "res://variants/{{tenant}}/resources/*"))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	// Get and list
+	code, body := request(http.MethodGet, "/viewtemplates/ns-reader", "")
+	require.Equal(t, http.StatusOK, code)
+	var got struct {
+		Kind string `json:"kind"`
+		Spec struct {
+			Rules []struct {
+				Targets []string `json:"targets"`
+			} `json:"rules"`
+		} `json:"spec"`
+	}
+	require.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, "ViewTemplate", got.Kind)
+	require.Len(t, got.Spec.Rules, 1)
+	assert.Equal(t, []string{"res://variants/{{variant}}/namespaces/{{namespace}}/resources/*"}, got.Spec.Rules[0].Targets)
+
+	code, body = request(http.MethodGet, "/viewtemplates", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, string(body), "ns-reader")
+
+	// Instantiate the template for two namespaces
+	code, _ = request(http.MethodPost, "/viewtemplates/ns-reader/instantiate", `{"name": "dev-reader", "variant": "test-variant", "namespace": "dev"}`)
+	require.Equal(t, http.StatusCreated, code)
+	code, _ = request(http.MethodPost, "/viewtemplates/ns-reader/instantiate", `{"name": "test-reader", "variant": "test-variant", "namespace": "test"}`)
+	require.Equal(t, http.StatusCreated, code)
+
+	code, body = request(http.MethodGet, "/views/dev-reader", "")
+	require.Equal(t, http.StatusOK, code)
+	require.NoError(t, json.Unmarshal(body, &got))
+	require.Len(t, got.Spec.Rules, 1)
+	assert.Equal(t, []string{"res://variants/test-variant/namespaces/dev/resources/*"}, got.Spec.Rules[0].Targets)
+
+	// Every placeholder needs a value, and the view must not exist yet
+	code, _ = request(http.MethodPost, "/viewtemplates/ns-reader/instantiate", `{"name": "other-reader", "variant": "test-variant"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = request(http.MethodPost, "/viewtemplates/ns-reader/instantiate", `{"name": "dev-reader", "variant": "test-variant", "namespace": "dev"}`)
+	assert.Equal(t, http.StatusConflict, code)
+	code, _ = request(http.MethodPost, "/viewtemplates/no-template/instantiate", `{"name": "other-reader", "namespace": "dev"}`)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	// Updating or deleting the template leaves instantiated views alone
+	code, _ = request(http.MethodPut, "/viewtemplates/ns-reader", template("ns-reader", "res://variants/{{variant}}/resources/*"))
+	require.Equal(t, http.StatusOK, code)
+	code, _ = request(http.MethodDelete, "/viewtemplates/ns-reader", "")
+	require.Equal(t, http.StatusNoContent, code)
+	code, _ = request(http.MethodGet, "/viewtemplates/ns-reader", "")
+	assert.Equal(t, http.StatusNotFound, code)
+	code, _ = request(http.MethodGet, "/views/dev-reader", "")
+	assert.Equal(t, http.StatusOK, code)
+}
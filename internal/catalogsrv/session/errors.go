@@ -16,4 +16,6 @@ var (
 	ErrNotAuthorized      apperrors.Error = ErrSessionError.New("not authorized").SetStatusCode(http.StatusForbidden)
 	ErrInvalidRequest     apperrors.Error = ErrSessionError.New("invalid request").SetStatusCode(http.StatusBadRequest)
 	ErrUnableToGetSession apperrors.Error = ErrSessionError.New("unable to get session").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidInvocation  apperrors.Error = ErrSessionError.New("invalid invocation record").SetStatusCode(http.StatusBadRequest)
+	ErrInvocationConflict apperrors.Error = ErrSessionError.New("invocation record conflicts with the audit trail").SetStatusCode(http.StatusConflict)
 )
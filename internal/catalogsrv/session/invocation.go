@@ -0,0 +1,135 @@
+package session
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Policy decisions and outcomes of a skill invocation.
+const (
+	InvocationDecisionAllowed = "allowed"
+	InvocationDecisionBlocked = "blocked"
+	InvocationDecisionDenied  = "denied" // allowed by policy, but denied by the approver
+
+	InvocationOutcomeSuccess = "success"
+	InvocationOutcomeFailed  = "failed"
+	InvocationOutcomeBlocked = "blocked"
+)
+
+// InvocationRecord is the audit record of a single skill invocation in a session, as
+// recorded by tangent. Records of a session are numbered from 1 and form a hash chain:
+// each record's hash covers the hash of the previous record, and is signed with the
+// tangent's log signing key. A record changed, removed or reordered after it was signed
+// breaks the chain.
+type InvocationRecord struct {
+	Seq          int64     `json:"seq"`
+	InvocationID string    `json:"invocationID"`
+	InvokerID    string    `json:"invokerID,omitempty"` // invocation that called this skill, if any
+	Skill        string    `json:"skill"`
+	InputsHash   string    `json:"inputsHash"` // SHA-256 of the JSON encoding of the input arguments
+	Actions      []string  `json:"actions,omitempty"`
+	Decision     string    `json:"decision,omitempty"` // empty if the invocation failed before the policy check
+	Outcome      string    `json:"outcome"`
+	Error        string    `json:"error,omitempty"`
+	StartedAt    time.Time `json:"startedAt"`
+	EndedAt      time.Time `json:"endedAt"`
+	PrevHash     string    `json:"prevHash"`
+	Hash         string    `json:"hash"`
+	Signature    string    `json:"signature"`
+}
+
+// InvocationBatch is a batch of records shipped by tangent. Records are in sequence order
+// and continue the session's trail.
+type InvocationBatch struct {
+	VerificationKey []byte             `json:"verificationKey"`
+	Records         []InvocationRecord `json:"records"`
+}
+
+// SessionInvocationsRsp is the audit trail of a session. Verified is false if the chain
+// or a signature does not check out, with the reason in Error.
+type SessionInvocationsRsp struct {
+	Records  []InvocationRecord `json:"records"`
+	Verified bool               `json:"verified"`
+	Error    string             `json:"error,omitempty"`
+}
+
+// HashInputs returns the hash recorded for a skill's input arguments.
+func HashInputs(inputArgs map[string]any) string {
+	b, err := json.Marshal(inputArgs)
+	if err != nil {
+		b = []byte(fmt.Sprint(inputArgs))
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// computeHash returns the hash of the record, which covers all of its fields but the hash
+// and signature.
+func (r InvocationRecord) computeHash() (string, error) {
+	r.Hash, r.Signature = "", ""
+	b, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Sign chains the record to the previous record's hash, and sets its hash and signature.
+func (r *InvocationRecord) Sign(prevHash string, privKey ed25519.PrivateKey) error {
+	if len(privKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("invalid private key: must be %d bytes, got %d", ed25519.PrivateKeySize, len(privKey))
+	}
+	r.PrevHash = prevHash
+	hash, err := r.computeHash()
+	if err != nil {
+		return err
+	}
+	r.Hash = hash
+	r.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(privKey, []byte(hash)))
+	return nil
+}
+
+// Verify checks the record's hash and signature.
+func (r *InvocationRecord) Verify(pubKey ed25519.PublicKey) error {
+	if len(pubKey) != ed25519.PublicKeySize {
+		return errors.New("invalid verification key")
+	}
+	hash, err := r.computeHash()
+	if err != nil {
+		return err
+	}
+	if hash != r.Hash {
+		return fmt.Errorf("record %d: hash mismatch", r.Seq)
+	}
+	sig, err := base64.StdEncoding.DecodeString(r.Signature)
+	if err != nil || !ed25519.Verify(pubKey, []byte(r.Hash), sig) {
+		return fmt.Errorf("record %d: invalid signature", r.Seq)
+	}
+	return nil
+}
+
+// VerifyInvocationChain checks that records continue a chain whose last record has
+// sequence number prevSeq and hash prevHash, which are 0 and empty for a new chain.
+func VerifyInvocationChain(records []InvocationRecord, prevSeq int64, prevHash string, pubKey ed25519.PublicKey) error {
+	for i := range records {
+		r := &records[i]
+		if r.Seq != prevSeq+1 {
+			return fmt.Errorf("record %d: expected sequence number %d", r.Seq, prevSeq+1)
+		}
+		if r.PrevHash != prevHash {
+			return fmt.Errorf("record %d: does not follow the previous record", r.Seq)
+		}
+		if err := r.Verify(pubKey); err != nil {
+			return err
+		}
+		prevSeq, prevHash = r.Seq, r.Hash
+	}
+	return nil
+}
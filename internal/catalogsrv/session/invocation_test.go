@@ -0,0 +1,59 @@
+package session
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvocationChain(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	var records []InvocationRecord
+	prevHash := ""
+	for i, skill := range []string{"list-pods", "restart-deployment", "list-pods"} {
+		rec := InvocationRecord{
+			Seq:          int64(i + 1),
+			InvocationID: skill + "-call",
+			Skill:        skill,
+			InputsHash:   HashInputs(map[string]any{"namespace": "default", "n": i}),
+			Decision:     InvocationDecisionAllowed,
+			Outcome:      InvocationOutcomeSuccess,
+			StartedAt:    time.Now().UTC(),
+			EndedAt:      time.Now().UTC(),
+		}
+		require.NoError(t, rec.Sign(prevHash, privKey))
+		prevHash = rec.Hash
+		records = append(records, rec)
+	}
+	require.NoError(t, VerifyInvocationChain(records, 0, "", pubKey))
+
+	// A record survives a round trip through JSON, as when it is stored
+	b, err := json.Marshal(records)
+	require.NoError(t, err)
+	var decoded []InvocationRecord
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.NoError(t, VerifyInvocationChain(decoded, 0, "", pubKey))
+
+	// A batch continues the chain from the last stored record
+	assert.NoError(t, VerifyInvocationChain(records[1:], 1, records[0].Hash, pubKey))
+	assert.Error(t, VerifyInvocationChain(records[1:], 0, "", pubKey))
+
+	tampered := append([]InvocationRecord(nil), records...)
+	tampered[1].Outcome = InvocationOutcomeFailed
+	assert.ErrorContains(t, VerifyInvocationChain(tampered, 0, "", pubKey), "hash mismatch")
+
+	removed := []InvocationRecord{records[0], records[2]}
+	assert.Error(t, VerifyInvocationChain(removed, 0, "", pubKey))
+
+	otherKey, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	assert.ErrorContains(t, VerifyInvocationChain(records, 0, "", otherKey), "invalid signature")
+
+	assert.Equal(t, HashInputs(map[string]any{"a": 1, "b": 2}), HashInputs(map[string]any{"b": 2, "a": 1}))
+}
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/tangent"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// maxInvocationBatch bounds the number of records tangent ships in one request
+const maxInvocationBatch = 500
+
+// recordInvocations appends a batch of invocation records shipped by tangent to the
+// session's audit trail. The batch must continue the trail: each record is checked against
+// the hash of the one before it and the log signing key the session's tangent registered
+// with. The verification key in the batch is not trusted and must match that key.
+// Records already in the trail are skipped, so that tangent can retry a batch whose
+// response it did not receive.
+func recordInvocations(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionID := catcommon.GetSessionID(ctx)
+	if sessionID == uuid.Nil {
+		return nil, ErrInvalidRequest.Msg("invalid session ID")
+	}
+	session, apperr := db.DB(ctx).GetSession(ctx, sessionID)
+	if apperr != nil {
+		log.Ctx(ctx).Error().Err(apperr).Msg("failed to get session")
+		return nil, ErrUnableToGetSession
+	}
+	verificationKey, apperr := sessionVerificationKey(ctx, session)
+	if apperr != nil {
+		return nil, apperr
+	}
+	if r.Body == nil {
+		return nil, ErrInvalidRequest.Msg("request body is required")
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, ErrInvalidRequest.Msg("invalid request body")
+	}
+	var batch InvocationBatch
+	if err := json.Unmarshal(body, &batch); err != nil {
+		return nil, ErrInvalidRequest.Msg("invalid request body")
+	}
+	if len(batch.Records) > maxInvocationBatch {
+		return nil, ErrInvalidRequest.Msg("too many records in batch")
+	}
+	if len(batch.VerificationKey) > 0 && !bytes.Equal(batch.VerificationKey, verificationKey) {
+		return nil, ErrInvalidInvocation.Msg("verification key is not the log signing key of the session's tangent")
+	}
+
+	var prevSeq int64
+	var prevHash string
+	last, apperr := db.DB(ctx).GetLastSessionInvocation(ctx, sessionID)
+	switch {
+	case apperr == nil:
+		if !bytes.Equal(last.VerificationKey, verificationKey) {
+			return nil, ErrInvocationConflict.Msg("verification key does not match the audit trail")
+		}
+		prevSeq, prevHash = last.Seq, last.Hash
+	case !errors.Is(apperr, dberror.ErrNotFound):
+		return nil, apperr
+	}
+
+	records := batch.Records
+	for len(records) > 0 && records[0].Seq <= prevSeq {
+		if records[0].Seq == prevSeq && records[0].Hash != prevHash {
+			return nil, ErrInvocationConflict.Msg(fmt.Sprintf("record %d differs from the audit trail", prevSeq))
+		}
+		records = records[1:]
+	}
+	if err := VerifyInvocationChain(records, prevSeq, prevHash, verificationKey); err != nil {
+		return nil, ErrInvalidInvocation.Msg(err.Error())
+	}
+
+	invocations := make([]*models.SessionInvocation, len(records))
+	for i, rec := range records {
+		recJSON, err := json.Marshal(rec)
+		if err != nil {
+			return nil, ErrInvalidInvocation.Msg(err.Error())
+		}
+		invocations[i] = &models.SessionInvocation{
+			SessionID:       sessionID,
+			Seq:             rec.Seq,
+			InvocationID:    rec.InvocationID,
+			Skill:           rec.Skill,
+			Decision:        rec.Decision,
+			Outcome:         rec.Outcome,
+			Hash:            rec.Hash,
+			VerificationKey: verificationKey,
+			Record:          recJSON,
+		}
+	}
+	if err := db.DB(ctx).AppendSessionInvocations(ctx, invocations); err != nil {
+		if errors.Is(err, dberror.ErrAlreadyExists) {
+			return nil, ErrInvocationConflict.Msg("records were appended concurrently")
+		}
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+// sessionVerificationKey returns the key that the invocation records of session are signed
+// with: the log signing key its tangent registered with.
+func sessionVerificationKey(ctx context.Context, session *models.Session) (ed25519.PublicKey, apperrors.Error) {
+	t, err := db.DB(ctx).GetTangent(ctx, session.TangentID)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", session.TangentID.String()).Msg("failed to get tangent of session")
+		return nil, ErrInvalidInvocation.Msg("tangent of the session is not registered")
+	}
+	var info tangent.TangentInfo
+	if err := json.Unmarshal(t.Info, &info); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", session.TangentID.String()).Msg("failed to unmarshal tangent info")
+		return nil, ErrInvalidInvocation.Msg("unable to read tangent info")
+	}
+	if len(info.PublicKeyLogSigningKey) != ed25519.PublicKeySize {
+		return nil, ErrInvalidInvocation.Msg("tangent of the session has no valid log signing key")
+	}
+	return ed25519.PublicKey(info.PublicKeyLogSigningKey), nil
+}
+
+// getSessionInvocations returns the audit trail of a session's skill invocations, and
+// whether its hash chain and signatures verify.
+func getSessionInvocations(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionUUID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid sessionID")
+	}
+	session, apperr := db.DB(ctx).GetSession(ctx, sessionUUID)
+	if apperr != nil || session.CatalogID != catcommon.GetCatalogID(ctx) {
+		return nil, ErrUnableToGetSession
+	}
+
+	invocations, apperr := db.DB(ctx).ListSessionInvocations(ctx, sessionUUID)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	rsp := SessionInvocationsRsp{Records: make([]InvocationRecord, 0, len(invocations)), Verified: true}
+	var key []byte
+	for _, inv := range invocations {
+		var rec InvocationRecord
+		if err := json.Unmarshal(inv.Record, &rec); err != nil {
+			return nil, ErrInvalidInvocation.Msg("unable to read invocation record")
+		}
+		rsp.Records = append(rsp.Records, rec)
+		if key == nil {
+			key = inv.VerificationKey
+		} else if !bytes.Equal(key, inv.VerificationKey) && rsp.Verified {
+			rsp.Verified, rsp.Error = false, "verification key changed within the audit trail"
+		}
+	}
+	if rsp.Verified {
+		if err := VerifyInvocationChain(rsp.Records, 0, "", ed25519.PublicKey(key)); err != nil {
+			rsp.Verified, rsp.Error = false, err.Error()
+		}
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
@@ -26,6 +26,11 @@ var sessionHandlers = []policy.ResponseHandlerParam{
 		Path:    "/execution-state",
 		Handler: getExecutionState,
 	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/execution-state/watch",
+		Handler: watchExecutionState,
+	},
 }
 
 var sessionTangentHandlers = []policy.ResponseHandlerParam{
@@ -39,6 +44,11 @@ var sessionTangentHandlers = []policy.ResponseHandlerParam{
 		Path:    "/execution-state",
 		Handler: updateExecutionState,
 	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/execution-state/invocations",
+		Handler: recordInvocations,
+	},
 }
 
 var sessionUserHandlers = []policy.ResponseHandlerParam{
@@ -52,6 +62,11 @@ var sessionUserHandlers = []policy.ResponseHandlerParam{
 		Path:    "/",
 		Handler: getSessions,
 	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/caller",
+		Handler: getSessionCaller,
+	},
 	{
 		Method:  http.MethodGet,
 		Path:    "/{sessionID}",
@@ -67,6 +82,16 @@ var sessionUserHandlers = []policy.ResponseHandlerParam{
 		Path:    "/{sessionID}/auditlog/verification-key",
 		Handler: getAuditLogVerificationKeyByID,
 	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/invocations",
+		Handler: getSessionInvocations,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/approver",
+		Handler: getSessionApprover,
+	},
 }
 
 func Router() chi.Router {
@@ -122,7 +147,9 @@ func validateTangentSignature(ctx context.Context, r *http.Request) error {
 		return ErrInvalidRequest.Msg("invalid tangent ID format")
 	}
 
-	tangent, err := db.DB(ctx).GetTangent(ctx, tangentID)
+	// The tangent is not yet authenticated, so the lookup is not scoped to a tenant
+	lookupCtx := db.WithAllTenants(ctx)
+	tangent, err := db.DB(lookupCtx).GetTangent(lookupCtx, tangentID)
 	if err != nil {
 		log.Ctx(ctx).Error().Err(err).Str("tangent_id", tangentIDStr).Msg("failed to get tangent from database")
 		return ErrInvalidRequest.Msg("tangent not found")
@@ -211,7 +238,7 @@ func sessionContextMiddleware(next http.Handler) http.Handler {
 		ctx, err = auth.ValidateToken(ctx, token)
 		if err != nil {
 			log.Ctx(ctx).Error().Err(err).Msg("token validation failed")
-			httpx.ErrUnAuthorized(auth.GenericAuthError).Send(w)
+			auth.SendTokenError(w, err)
 			return
 		}
 
@@ -175,11 +175,11 @@ func NewSession(ctx context.Context, rsrcSpec []byte, opts ...RequestOptions) (S
 
 	// Validate action permissions
 	exportedActions := skillObj.GetExportedActions()
-	allowed, _, err := policy.AreActionsAllowedOnResource(viewDef, skillSetManager.GetResourcePath(), exportedActions)
+	allowed, matchedRules, err := policy.AreActionsAllowedWithAttributes(viewDef, skillSetManager.GetResourcePath(), exportedActions, policy.RequestAttributes(ctx))
 	if err != nil {
 		return nil, nil, err
 	}
-	if !allowed {
+	if !policy.EnforceDecision(ctx, viewDef, exportedActions, skillSetManager.GetResourcePath(), allowed, matchedRules) {
 		return nil, nil, ErrDisallowedByPolicy.Msg("use of skill is blocked by policy")
 	}
 
@@ -13,6 +13,7 @@ import (
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
 	"github.com/tansive/tansive-internal/internal/common/uuid"
 )
@@ -306,6 +307,52 @@ func getSessionSummaryByID(r *http.Request) (*httpx.Response, error) {
 	}, nil
 }
 
+// getSessionApprover returns the caller if the caller may decide the approvals of the
+// session's skill invocations. Tangent calls it with the credentials of each decision: the
+// caller must be a user whose view allows ActionSkillSetApprove on the session's skill set.
+func getSessionApprover(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionUUID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid sessionID")
+	}
+	userID := catcommon.GetUserID(ctx)
+	if catcommon.GetSubjectType(ctx) != catcommon.SubjectTypeUser || userID == "" {
+		return nil, ErrNotAuthorized.Msg("approvals can only be decided by users")
+	}
+	session, apperr := db.DB(ctx).GetSession(ctx, sessionUUID)
+	if apperr != nil || session.CatalogID != catcommon.GetCatalogID(ctx) {
+		return nil, ErrUnableToGetSession
+	}
+
+	allowed, apperr := policy.CanApproveSkillSet(ctx, session.SkillSet)
+	if apperr != nil {
+		return nil, apperr
+	}
+	if !allowed {
+		return nil, ErrDisallowedByPolicy.Msg("view does not allow approving invocations of the skillset")
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   SessionApprover{UserID: userID},
+	}, nil
+}
+
+// getSessionCaller identifies the user of the request for a tangent, which serves the
+// state, output and artifacts of a session only to the user who created it.
+func getSessionCaller(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	userID := catcommon.GetUserID(ctx)
+	if catcommon.GetSubjectType(ctx) != catcommon.SubjectTypeUser || userID == "" {
+		return nil, ErrNotAuthorized.Msg("sessions can only be accessed by users")
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   SessionCaller{UserID: userID, TenantID: catcommon.GetTenantID(ctx)},
+	}, nil
+}
+
 func getAuditLogByID(r *http.Request) (*httpx.Response, error) {
 	ctx := r.Context()
 	sessionID := chi.URLParam(r, "sessionID")
@@ -48,6 +48,7 @@ func (s *sessionManager) GetExecutionState(ctx context.Context) *ExecutionState
 		Variant:          s.viewManager.Scope().Variant,
 		Namespace:        s.viewManager.Scope().Namespace,
 		TenantID:         catcommon.GetTenantID(ctx),
+		UserID:           s.session.UserID,
 	}
 }
 
@@ -63,12 +63,24 @@ type ExecutionState struct {
 	Variant          string                 `json:"variant"`
 	Namespace        string                 `json:"namespace"`
 	TenantID         catcommon.TenantId     `json:"tenantID"`
+	UserID           string                 `json:"userID"`
 }
 
 type ExecutionStatus struct {
 	AuditLog                string         `json:"auditLog"`
 	AuditLogVerificationKey []byte         `json:"auditLogVerificationKey"`
 	Error                   map[string]any `json:"error"`
+	Artifacts               []ArtifactRef  `json:"artifacts,omitempty"`
+}
+
+// ArtifactRef refers to a file the session deposited in its tangent. The content stays in
+// the tangent, which serves it until ExpiresAt.
+type ArtifactRef struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	SHA256    string    `json:"sha256"`
+	ExpiresAt time.Time `json:"expiresAt"`
 }
 
 type ExecutionStatusUpdate struct {
@@ -86,6 +98,18 @@ type SessionSummaryInfo struct {
 	Error         map[string]any `json:"error"`
 }
 
+// SessionApprover identifies a user who may decide the approvals a session waits for.
+type SessionApprover struct {
+	UserID string `json:"userID"`
+}
+
+// SessionCaller identifies the user of a request to a tangent. A tangent serves a session
+// only to the user who created it.
+type SessionCaller struct {
+	UserID   string             `json:"userID"`
+	TenantID catcommon.TenantId `json:"tenantID"`
+}
+
 type AuditLogVerificationKey struct {
 	Key []byte `json:"key"`
 }
@@ -0,0 +1,95 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+const (
+	viewWatchBufferSize        = 100
+	viewWatchHeartbeatInterval = 30 * time.Second
+)
+
+// watchExecutionState streams changes to the views of the session's catalog as Server-Sent
+// Events. Tangent caches the view definitions of its sessions and drops them when a view
+// changes, since a change to any view may change the rules a view includes. The stream is
+// authorized by the session token, so it does not need the view to grant catalog access.
+func watchExecutionState(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+	sessionID := catcommon.GetSessionID(ctx)
+	if sessionID == uuid.Nil {
+		return nil, httpx.ErrInvalidRequest("invalid session ID")
+	}
+	session, err := GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	catalog := session.GetExecutionState(ctx).Catalog
+	if catalog == "" {
+		return nil, httpx.ErrInvalidRequest("session has no catalog")
+	}
+	topic := catalogmanager.ObjectEventTopic(ctx, catalog, catcommon.ViewKind)
+
+	return &httpx.Response{
+		StatusCode:  http.StatusOK,
+		ContentType: "text/event-stream",
+		Header:      http.Header{"Cache-Control": {"no-cache"}},
+		Chunked:     true,
+		WriteChunks: func(w http.ResponseWriter) error {
+			rc := http.NewResponseController(w)
+			if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msg("unable to clear write deadline")
+			}
+
+			events, unsubscribe := catalogmanager.SubscribeObjectEvents(topic, viewWatchBufferSize)
+			defer unsubscribe()
+
+			if _, err := fmt.Fprint(w, ": watching\n\n"); err != nil {
+				return err
+			}
+			if err := rc.Flush(); err != nil {
+				return err
+			}
+
+			heartbeat := time.NewTicker(viewWatchHeartbeatInterval)
+			defer heartbeat.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-heartbeat.C:
+					if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+						return err
+					}
+				case event, ok := <-events:
+					if !ok {
+						return nil
+					}
+					ev, ok := event.Data.(catalogmanager.ObjectEvent)
+					if !ok {
+						continue
+					}
+					data, err := json.Marshal(ev)
+					if err != nil {
+						log.Ctx(ctx).Error().Err(err).Msg("unable to marshal view event")
+						continue
+					}
+					if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, data); err != nil {
+						return err
+					}
+				}
+				if err := rc.Flush(); err != nil {
+					return err
+				}
+			}
+		},
+	}, nil
+}
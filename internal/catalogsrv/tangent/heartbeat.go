@@ -0,0 +1,122 @@
+package tangent
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+// recordHeartbeat stores a heartbeat from a registered tangent. A tangent that is not
+// registered gets a 404 and should register again.
+func recordHeartbeat(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "tangentID"))
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid tangent id")
+	}
+
+	if r.Body == nil {
+		return nil, httpx.ErrInvalidRequest("request body is required")
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, httpx.ErrUnableToReadRequest()
+	}
+
+	hb := &Heartbeat{}
+	if err := json.Unmarshal(body, hb); err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid request body")
+	}
+	if hb.Status != HealthHealthy && hb.Status != HealthDegraded {
+		return nil, httpx.ErrInvalidRequest("status must be healthy or degraded")
+	}
+	if hb.IntervalSeconds <= 0 {
+		return nil, httpx.ErrInvalidRequest("intervalSeconds must be positive")
+	}
+
+	health, err := json.Marshal(hb)
+	if err != nil {
+		return nil, httpx.ErrInvalidRequest("invalid request body")
+	}
+	if err := db.DB(ctx).RecordTangentHeartbeat(ctx, id, hb.Status, health); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("tangent_id", id.String()).Msg("failed to record heartbeat")
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+// listTangents lists the registered tangents of the tenant and their health.
+func listTangents(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	tangents, err := db.DB(ctx).ListTangents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := ListTangentsResponse{Tangents: []TangentStatus{}}
+	now := time.Now()
+	for _, t := range tangents {
+		status, err := tangentStatus(t, now)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("tangent_id", t.ID.String()).Msg("skipping tangent with invalid info")
+			continue
+		}
+		rsp.Tangents = append(rsp.Tangents, status)
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// tangentStatus returns the status of a tangent as of now. A tangent that has missed
+// several heartbeats, or never sent one, is disconnected.
+func tangentStatus(t *models.Tangent, now time.Time) (TangentStatus, error) {
+	info := TangentInfo{}
+	if err := json.Unmarshal(t.Info, &info); err != nil {
+		return TangentStatus{}, err
+	}
+	status := TangentStatus{
+		ID:              t.ID,
+		URL:             info.URL,
+		Version:         info.Version,
+		Capabilities:    info.Capabilities,
+		Health:          HealthDisconnected,
+		LastHeartbeatAt: t.LastHeartbeatAt,
+		RegisteredAt:    t.CreatedAt,
+	}
+	if t.LastHeartbeatAt == nil || len(t.Health) == 0 {
+		return status, nil
+	}
+
+	hb := Heartbeat{}
+	if err := json.Unmarshal(t.Health, &hb); err != nil {
+		return TangentStatus{}, err
+	}
+	if hb.Version != "" {
+		status.Version = hb.Version
+	}
+	if hb.Capabilities != nil {
+		status.Capabilities = hb.Capabilities
+	}
+	timeout := time.Duration(missedHeartbeats*hb.IntervalSeconds) * time.Second
+	if now.Sub(*t.LastHeartbeatAt) <= timeout {
+		status.Health = hb.Status
+		status.ActiveSessions = hb.ActiveSessions
+	}
+	return status, nil
+}
@@ -0,0 +1,83 @@
+package tangent
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+)
+
+func TestTangentStatus(t *testing.T) {
+	now := time.Now()
+	info, err := json.Marshal(&TangentInfo{
+		URL:          "http://test.tansive.dev:8468",
+		Version:      "0.1.0",
+		Capabilities: []catcommon.RunnerID{catcommon.StdioRunnerID},
+	})
+	require.NoError(t, err)
+	health, err := json.Marshal(&Heartbeat{
+		Status:          HealthDegraded,
+		Version:         "0.2.0",
+		Capabilities:    []catcommon.RunnerID{},
+		ActiveSessions:  3,
+		IntervalSeconds: 30,
+	})
+	require.NoError(t, err)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	tests := []struct {
+		name          string
+		tangent       models.Tangent
+		wantHealth    string
+		wantSessions  int
+		wantVersion   string
+		wantRunnerIDs []catcommon.RunnerID
+	}{
+		{
+			name:          "never sent a heartbeat",
+			tangent:       models.Tangent{Info: info},
+			wantHealth:    HealthDisconnected,
+			wantVersion:   "0.1.0",
+			wantRunnerIDs: []catcommon.RunnerID{catcommon.StdioRunnerID},
+		},
+		{
+			name:          "recent heartbeat",
+			tangent:       models.Tangent{Info: info, Health: health, LastHeartbeatAt: at(10 * time.Second)},
+			wantHealth:    HealthDegraded,
+			wantSessions:  3,
+			wantVersion:   "0.2.0",
+			wantRunnerIDs: []catcommon.RunnerID{},
+		},
+		{
+			name:          "missed heartbeats",
+			tangent:       models.Tangent{Info: info, Health: health, LastHeartbeatAt: at(2 * time.Minute)},
+			wantHealth:    HealthDisconnected,
+			wantVersion:   "0.2.0",
+			wantRunnerIDs: []catcommon.RunnerID{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tangent.ID = uuid.New()
+			status, err := tangentStatus(&tt.tangent, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.tangent.ID, status.ID)
+			assert.Equal(t, tt.wantHealth, status.Health)
+			assert.Equal(t, tt.wantSessions, status.ActiveSessions)
+			assert.Equal(t, tt.wantVersion, status.Version)
+			assert.Equal(t, tt.wantRunnerIDs, status.Capabilities)
+		})
+	}
+
+	_, err = tangentStatus(&models.Tangent{Info: []byte("{")}, now)
+	assert.Error(t, err)
+}
@@ -18,9 +18,20 @@ var tangentHandlers = []policy.ResponseHandlerParam{
 		Path:    "/",
 		Handler: createTangent,
 	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{tangentID}/heartbeat",
+		Handler: recordHeartbeat,
+	},
 }
 
 var tangentUserHandlers = []policy.ResponseHandlerParam{
+	{
+		Method:         http.MethodGet,
+		Path:           "/",
+		Handler:        listTangents,
+		AllowedActions: []policy.Action{policy.ActionCatalogAdmin},
+	},
 	{
 		Method: http.MethodGet,
 		Path:   "/onboardingKey",
@@ -55,7 +66,7 @@ func tangentContextMiddleware(next http.Handler) http.Handler {
 			ctx = catcommon.WithTenantID(ctx, catcommon.TenantId(config.Config().DefaultTenantID))
 			ctx = catcommon.WithCatalogContext(ctx, &catcommon.CatalogContext{
 				UserContext: &catcommon.UserContext{
-					UserID: "default-user",
+					UserID: catcommon.SingleUserID,
 				},
 			})
 		} else {
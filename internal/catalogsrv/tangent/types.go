@@ -3,6 +3,7 @@ package tangent
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
@@ -15,11 +16,51 @@ type TangentInfo struct {
 	ID                     uuid.UUID            `json:"id"`
 	CreatedBy              string               `json:"createdBy"`
 	URL                    string               `json:"url"`
+	Version                string               `json:"version,omitempty"`
 	Capabilities           []catcommon.RunnerID `json:"capabilities"`
 	PublicKeyAccessKey     []byte               `json:"publicKeyAccessKey"`
 	PublicKeyLogSigningKey []byte               `json:"publicKeyLogSigningKey"`
 }
 
+// Health states of a tangent. A tangent reports healthy or degraded in its heartbeats;
+// one whose heartbeats have stopped is disconnected.
+const (
+	HealthHealthy      = "healthy"
+	HealthDegraded     = "degraded"
+	HealthDisconnected = "disconnected"
+)
+
+// missedHeartbeats is how many heartbeat intervals may pass before a tangent is
+// considered disconnected.
+const missedHeartbeats = 3
+
+// Heartbeat is sent periodically by a registered tangent to report that it is connected
+// and how it is doing.
+type Heartbeat struct {
+	Status          string               `json:"status"`          // healthy or degraded
+	Version         string               `json:"version"`         // version of the tangent
+	Capabilities    []catcommon.RunnerID `json:"capabilities"`    // runners the tangent can run skills with
+	ActiveSessions  int                  `json:"activeSessions"`  // sessions created or running
+	IntervalSeconds int                  `json:"intervalSeconds"` // seconds until the next heartbeat
+}
+
+// TangentStatus is a registered tangent as listed by GET /tangents.
+type TangentStatus struct {
+	ID              uuid.UUID            `json:"id"`
+	URL             string               `json:"url"`
+	Version         string               `json:"version,omitempty"`
+	Capabilities    []catcommon.RunnerID `json:"capabilities"`
+	Health          string               `json:"health"` // healthy, degraded or disconnected
+	ActiveSessions  int                  `json:"activeSessions"`
+	LastHeartbeatAt *time.Time           `json:"lastHeartbeatAt,omitempty"`
+	RegisteredAt    time.Time            `json:"registeredAt"`
+}
+
+// ListTangentsResponse is the response of GET /tangents.
+type ListTangentsResponse struct {
+	Tangents []TangentStatus `json:"tangents"`
+}
+
 type Tangent struct {
 	ID uuid.UUID `json:"id"`
 	TangentInfo
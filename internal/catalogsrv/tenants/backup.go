@@ -0,0 +1,72 @@
+package tenants
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// backupTenant returns a consistent snapshot of the tenant's projects, users, groups, roles,
+// catalogs with their contents, and views. The snapshot can be restored into a tenant of another
+// database to move the tenant between clusters.
+func backupTenant(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleOwner); err != nil {
+		return nil, err
+	}
+
+	snapshot, err := db.DB(ctx).DumpTenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user", catcommon.GetUserID(ctx)).Msg("backed up tenant")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   snapshot,
+	}, nil
+}
+
+// restoreTenant restores a snapshot taken by backupTenant into the caller's tenant, which must
+// not have any catalogs yet.
+func restoreTenant(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleOwner); err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.TenantSnapshot{}
+	if err := readJSON(r, snapshot); err != nil {
+		return nil, err
+	}
+
+	restored, err := db.DB(ctx).RestoreTenant(ctx, snapshot)
+	if err != nil {
+		if errors.Is(err, dberror.ErrAlreadyExists) {
+			return nil, ErrTenantNotEmpty
+		}
+		if errors.Is(err, dberror.ErrInvalidInput) {
+			return nil, ErrInvalidSnapshot.Msg(err.Error())
+		}
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().
+		Str("source_tenant_id", string(snapshot.TenantID)).
+		Str("user", catcommon.GetUserID(ctx)).
+		Msg("restored tenant")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   restoreTenantRsp{Rows: restored},
+	}, nil
+}
@@ -0,0 +1,34 @@
+package tenants
+
+import (
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+var (
+	ErrTenantError      apperrors.Error = apperrors.New("tenant error").SetStatusCode(http.StatusInternalServerError)
+	ErrInvalidRequest   apperrors.Error = ErrTenantError.New("invalid request").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidTenantID  apperrors.Error = ErrTenantError.New("invalid tenant ID").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidProjectID apperrors.Error = ErrTenantError.New("invalid project ID").SetStatusCode(http.StatusBadRequest)
+	ErrInvalidRole      apperrors.Error = ErrTenantError.New("invalid role").SetStatusCode(http.StatusBadRequest)
+	ErrTenantExists     apperrors.Error = ErrTenantError.New("tenant already exists").SetStatusCode(http.StatusConflict)
+	ErrTenantNotFound   apperrors.Error = ErrTenantError.New("tenant not found").SetStatusCode(http.StatusNotFound)
+	ErrTenantActive     apperrors.Error = ErrTenantError.New("tenant is active").SetStatusCode(http.StatusConflict)
+	ErrProjectExists    apperrors.Error = ErrTenantError.New("project already exists").SetStatusCode(http.StatusConflict)
+	ErrProjectNotFound  apperrors.Error = ErrTenantError.New("project not found").SetStatusCode(http.StatusNotFound)
+	ErrProjectNotEmpty  apperrors.Error = ErrTenantError.New("project has catalogs").SetStatusCode(http.StatusConflict)
+	ErrCatalogExists    apperrors.Error = ErrTenantError.New("catalog already exists").SetStatusCode(http.StatusConflict)
+	ErrCatalogNotFound  apperrors.Error = ErrTenantError.New("catalog not found").SetStatusCode(http.StatusNotFound)
+	ErrUserExists       apperrors.Error = ErrTenantError.New("user already exists").SetStatusCode(http.StatusConflict)
+	ErrUserNotFound     apperrors.Error = ErrTenantError.New("user not found").SetStatusCode(http.StatusNotFound)
+	ErrGroupExists      apperrors.Error = ErrTenantError.New("group already exists").SetStatusCode(http.StatusConflict)
+	ErrGroupNotFound    apperrors.Error = ErrTenantError.New("group not found").SetStatusCode(http.StatusNotFound)
+	ErrRoleNotFound     apperrors.Error = ErrTenantError.New("role not found").SetStatusCode(http.StatusNotFound)
+	ErrTenantNotEmpty   apperrors.Error = ErrTenantError.New("tenant already has catalogs").SetStatusCode(http.StatusConflict)
+	ErrInvalidSnapshot  apperrors.Error = ErrTenantError.New("invalid tenant snapshot").SetStatusCode(http.StatusBadRequest)
+	ErrLastOwner        apperrors.Error = ErrTenantError.New("tenant must have an owner").SetStatusCode(http.StatusConflict)
+	ErrNotAuthorized    apperrors.Error = ErrTenantError.New("not authorized").SetStatusCode(http.StatusForbidden)
+	ErrInsufficientRole apperrors.Error = ErrNotAuthorized.New("insufficient tenant role").SetStatusCode(http.StatusForbidden)
+	ErrNotPlatformAdmin apperrors.Error = ErrNotAuthorized.New("not a platform admin").SetStatusCode(http.StatusForbidden)
+)
@@ -0,0 +1,33 @@
+package tenants
+
+import (
+	"net/http"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// collectGarbage deletes the catalog objects of the tenant that are no longer referenced,
+// without waiting for the next scheduled collection.
+func collectGarbage(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	result, err := catalogmanager.CollectUnreferencedObjects(ctx, config.Config().ObjectGC.GetGracePeriodOrDefault())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user", catcommon.GetUserID(ctx)).Int64("objects", result.Objects).Msg("collected garbage")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   result,
+	}, nil
+}
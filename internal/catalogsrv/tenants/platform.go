@@ -0,0 +1,249 @@
+package tenants
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+var tenantIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,10}$`)
+
+// requirePlatformAdmin fails unless the caller is a user listed in auth.platform_admins and
+// signed in with an identity token. In single user mode, the single user is a platform admin.
+func requirePlatformAdmin(ctx context.Context) apperrors.Error {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return err
+	}
+	if config.Config().SingleUserMode && userID == catcommon.SingleUserID {
+		return nil
+	}
+	if !slices.Contains(config.Config().Auth.PlatformAdmins, userID) {
+		return ErrNotPlatformAdmin
+	}
+	return nil
+}
+
+// withTargetTenant returns the context for acting on the tenant named in the request path,
+// in place of the caller's tenant.
+func withTargetTenant(r *http.Request) (context.Context, catcommon.TenantId) {
+	tenantID := catcommon.TenantId(chi.URLParam(r, "tenantID"))
+	return catcommon.WithTenantID(r.Context(), tenantID), tenantID
+}
+
+func listTenants(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if err := requirePlatformAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Without a tenant, the connection is not scoped to the caller's tenant
+	ctx = db.WithAllTenants(catcommon.WithTenantID(ctx, ""))
+	tenants, goerr := db.DB(ctx).ListTenants(ctx)
+	if goerr != nil {
+		return nil, goerr
+	}
+
+	rsp := listTenantsRsp{Items: make([]tenantInfoRsp, 0, len(tenants))}
+	for _, t := range tenants {
+		rsp.Items = append(rsp.Items, newTenantInfoRsp(t))
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+func createTenant(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if err := requirePlatformAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	req := createTenantReq{}
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if !tenantIDRegex.MatchString(req.TenantID) {
+		return nil, ErrInvalidTenantID.Msg("tenant_id must be 1-10 alphanumeric, underscore or hyphen characters")
+	}
+	if req.ProjectID != "" && !projectIDRegex.MatchString(req.ProjectID) {
+		return nil, ErrInvalidProjectID.Msg("project_id must be 1-10 alphanumeric, underscore or hyphen characters")
+	}
+	owner := req.Owner
+	if owner == "" {
+		owner = catcommon.GetUserID(ctx)
+	}
+	if !userIDRegex.MatchString(owner) {
+		return nil, ErrInvalidRequest.Msg("owner must be 1-128 alphanumeric, underscore, hyphen, period, plus or @ characters")
+	}
+
+	tenantID := catcommon.TenantId(req.TenantID)
+	createdBy := catcommon.GetUserID(ctx)
+	tctx := catcommon.WithTenantID(ctx, tenantID)
+
+	// The lookup also scopes the connection to the new tenant before the transaction starts
+	if _, goerr := db.DB(tctx).GetTenant(tctx, tenantID); goerr == nil {
+		return nil, ErrTenantExists
+	} else if !errors.Is(goerr, dberror.ErrNotFound) {
+		return nil, goerr
+	}
+
+	// The tenant is created with its project and owner, so that it can be administered
+	// through /tenant by the owner
+	err := db.Tx(tctx, func(ctx context.Context) apperrors.Error {
+		if goerr := db.DB(ctx).CreateTenant(ctx, tenantID); goerr != nil {
+			if errors.Is(goerr, dberror.ErrAlreadyExists) {
+				return ErrTenantExists
+			}
+			return ErrTenantError.MsgErr("unable to create tenant", goerr)
+		}
+		if req.ProjectID != "" {
+			if goerr := db.DB(ctx).CreateProject(ctx, catcommon.ProjectId(req.ProjectID)); goerr != nil {
+				return ErrTenantError.MsgErr("unable to create project", goerr)
+			}
+		}
+		return db.DB(ctx).SetTenantRole(ctx, &models.TenantRole{
+			UserID:    owner,
+			Role:      string(RoleOwner),
+			CreatedBy: createdBy,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, goerr := db.DB(tctx).GetTenant(tctx, tenantID)
+	if goerr != nil {
+		return nil, goerr
+	}
+
+	log.Ctx(ctx).Info().Str("tenant_id", req.TenantID).Str("owner", owner).Str("by", createdBy).Msg("created tenant")
+
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   "/tenants/" + req.TenantID,
+		Response:   newTenantInfoRsp(tenant),
+	}, nil
+}
+
+func getTenantInfo(r *http.Request) (*httpx.Response, error) {
+	if err := requirePlatformAdmin(r.Context()); err != nil {
+		return nil, err
+	}
+
+	ctx, tenantID := withTargetTenant(r)
+	tenant, err := lookupTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   newTenantInfoRsp(tenant),
+	}, nil
+}
+
+func suspendTenant(r *http.Request) (*httpx.Response, error) {
+	return setTenantSuspended(r, true)
+}
+
+func resumeTenant(r *http.Request) (*httpx.Response, error) {
+	return setTenantSuspended(r, false)
+}
+
+// setTenantSuspended suspends or resumes the tenant named in the request path. Callers cannot
+// suspend their own tenant, which would lock them out.
+func setTenantSuspended(r *http.Request, suspended bool) (*httpx.Response, error) {
+	if err := requirePlatformAdmin(r.Context()); err != nil {
+		return nil, err
+	}
+
+	ctx, tenantID := withTargetTenant(r)
+	if suspended && tenantID == catcommon.GetTenantID(r.Context()) {
+		return nil, ErrInvalidRequest.Msg("the caller's own tenant cannot be suspended")
+	}
+	if _, err := lookupTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	if goerr := db.DB(ctx).SetTenantSuspended(ctx, tenantID, suspended); goerr != nil {
+		if errors.Is(goerr, dberror.ErrNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		return nil, goerr
+	}
+	tenant, err := lookupTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := "resumed tenant"
+	if suspended {
+		msg = "suspended tenant"
+	}
+	log.Ctx(ctx).Info().Str("tenant_id", string(tenantID)).Str("by", catcommon.GetUserID(ctx)).Msg(msg)
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   newTenantInfoRsp(tenant),
+	}, nil
+}
+
+// deleteTenant deletes the tenant named in the request path with all its data. To guard
+// against mistakes, the tenant must have been suspended first, and neither the default tenant
+// nor the caller's own tenant can be deleted.
+func deleteTenant(r *http.Request) (*httpx.Response, error) {
+	if err := requirePlatformAdmin(r.Context()); err != nil {
+		return nil, err
+	}
+
+	ctx, tenantID := withTargetTenant(r)
+	if string(tenantID) == config.Config().DefaultTenantID {
+		return nil, ErrInvalidRequest.Msg("the default tenant cannot be deleted")
+	}
+	if tenantID == catcommon.GetTenantID(r.Context()) {
+		return nil, ErrInvalidRequest.Msg("the caller's own tenant cannot be deleted")
+	}
+	tenant, err := lookupTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	if tenant.SuspendedAt == nil {
+		return nil, ErrTenantActive.Msg("suspend the tenant before deleting it")
+	}
+	if goerr := db.DB(ctx).DeleteTenant(ctx, tenantID); goerr != nil {
+		return nil, goerr
+	}
+
+	log.Ctx(ctx).Info().Str("tenant_id", string(tenantID)).Str("by", catcommon.GetUserID(ctx)).Msg("deleted tenant")
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+func lookupTenant(ctx context.Context, tenantID catcommon.TenantId) (*models.Tenant, error) {
+	tenant, goerr := db.DB(ctx).GetTenant(ctx, tenantID)
+	if goerr != nil {
+		if errors.Is(goerr, dberror.ErrNotFound) {
+			return nil, ErrTenantNotFound
+		}
+		return nil, goerr
+	}
+	return tenant, nil
+}
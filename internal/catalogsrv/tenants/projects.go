@@ -0,0 +1,128 @@
+package tenants
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+func getProject(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	project, err := lookupProject(ctx, catcommon.ProjectId(chi.URLParam(r, "projectID")))
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   projectRsp{ProjectID: project.ProjectID, CreatedAt: project.CreatedAt},
+	}, nil
+}
+
+func listProjectCatalogs(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	projectID := catcommon.ProjectId(chi.URLParam(r, "projectID"))
+	if _, err := lookupProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	ctx = catcommon.WithProjectID(ctx, projectID)
+	catalogs, err := db.DB(ctx).ListCatalogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := listCatalogsRsp{Items: make([]catalogRsp, 0, len(catalogs))}
+	for _, c := range catalogs {
+		rsp.Items = append(rsp.Items, newCatalogRsp(c))
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// moveCatalog moves a catalog to another project of the tenant. Catalog names are unique
+// within a project, so the move fails if the target project has a catalog of the same name.
+func moveCatalog(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	req := moveCatalogReq{}
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	projectID := catcommon.ProjectId(chi.URLParam(r, "projectID"))
+	toProjectID := catcommon.ProjectId(req.ToProjectID)
+	if toProjectID == "" {
+		return nil, ErrInvalidProjectID.Msg("to_project_id is required")
+	}
+	if toProjectID == projectID {
+		return nil, ErrInvalidRequest.Msg("the catalog is already in project " + string(projectID))
+	}
+	if _, err := lookupProject(ctx, projectID); err != nil {
+		return nil, err
+	}
+	if _, err := lookupProject(ctx, toProjectID); err != nil {
+		return nil, err
+	}
+
+	catalogName := chi.URLParam(r, "catalogName")
+	ctx = catcommon.WithProjectID(ctx, projectID)
+	if err := db.DB(ctx).MoveCatalog(ctx, catalogName, toProjectID); err != nil {
+		switch {
+		case errors.Is(err, dberror.ErrNotFound):
+			return nil, ErrCatalogNotFound
+		case errors.Is(err, dberror.ErrAlreadyExists):
+			return nil, ErrCatalogExists.Msg("project " + string(toProjectID) + " already has a catalog named " + catalogName)
+		}
+		return nil, err
+	}
+
+	ctx = catcommon.WithProjectID(ctx, toProjectID)
+	catalog, err := db.DB(ctx).GetCatalogByName(ctx, catalogName)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("catalog", catalogName).Str("from", string(projectID)).Str("to", string(toProjectID)).
+		Str("user", catcommon.GetUserID(ctx)).Msg("moved catalog")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   newCatalogRsp(catalog),
+	}, nil
+}
+
+func lookupProject(ctx context.Context, projectID catcommon.ProjectId) (*models.Project, error) {
+	project, goerr := db.DB(ctx).GetProject(ctx, projectID)
+	if goerr != nil {
+		if errors.Is(goerr, dberror.ErrNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, goerr
+	}
+	return project, nil
+}
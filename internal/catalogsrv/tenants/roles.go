@@ -0,0 +1,81 @@
+package tenants
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+// Role is an administrative role in a tenant. Each role includes the permissions of the
+// roles below it: viewers can read the tenant, its projects and roles; admins can also
+// create and delete projects and move catalogs between them; owners can also grant and
+// revoke roles.
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleAdmin  Role = "admin"
+	RoleViewer Role = "viewer"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleAdmin:  2,
+	RoleOwner:  3,
+}
+
+// IsValid reports whether r is a known role
+func (r Role) IsValid() bool {
+	_, ok := roleRank[r]
+	return ok
+}
+
+// Includes reports whether r grants at least the permissions of other
+func (r Role) Includes(other Role) bool {
+	return roleRank[r] >= roleRank[other]
+}
+
+// requireUser returns the ID of the user making the request. Tenant administration acts with
+// the full authority of the user, so the user must be signed in with an identity token; access
+// and handoff tokens are narrowed to a view and are refused.
+func requireUser(ctx context.Context) (string, apperrors.Error) {
+	if catcommon.GetSubjectType(ctx) != catcommon.SubjectTypeUser {
+		return "", ErrNotAuthorized.Msg("tenant administration requires a user")
+	}
+	if policy.GetViewDefinition(ctx) != nil {
+		return "", ErrNotAuthorized.Msg("tenant administration requires an identity token")
+	}
+	userID := catcommon.GetUserID(ctx)
+	if userID == "" {
+		return "", ErrNotAuthorized.Msg("unable to resolve user")
+	}
+	return userID, nil
+}
+
+// requireRole returns the caller's role in the tenant, or ErrInsufficientRole if the caller
+// is not a user holding at least the minimum role.
+func requireRole(ctx context.Context, minimum Role) (Role, apperrors.Error) {
+	userID, err := requireUser(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	tenantRole, dberr := db.DB(ctx).GetTenantRole(ctx, userID)
+	if dberr != nil {
+		if errors.Is(dberr, dberror.ErrNotFound) {
+			return "", ErrInsufficientRole.Msg("user has no role in the tenant")
+		}
+		return "", dberr
+	}
+
+	role := Role(tenantRole.Role)
+	if !role.Includes(minimum) {
+		return role, ErrInsufficientRole.Msg("requires the " + string(minimum) + " role")
+	}
+	return role, nil
+}
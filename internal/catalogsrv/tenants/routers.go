@@ -0,0 +1,219 @@
+package tenants
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/auth"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+var tenantHandlers = []policy.ResponseHandlerParam{
+	{
+		Method:  http.MethodGet,
+		Path:    "/",
+		Handler: getTenant,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/backup",
+		Handler: backupTenant,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/restore",
+		Handler: restoreTenant,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/projects",
+		Handler: listProjects,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/projects",
+		Handler: createProject,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/projects/{projectID}",
+		Handler: deleteProject,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/roles",
+		Handler: listRoles,
+	},
+	{
+		Method:  http.MethodPut,
+		Path:    "/roles/{userID}",
+		Handler: setRole,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/roles/{userID}",
+		Handler: deleteRole,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/users",
+		Handler: listUsers,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/users",
+		Handler: createUser,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/users/{userID}",
+		Handler: getUser,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/users/{userID}",
+		Handler: deleteUser,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/groups",
+		Handler: listGroups,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/groups",
+		Handler: createGroup,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/groups/{groupName}",
+		Handler: getGroup,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/groups/{groupName}",
+		Handler: deleteGroup,
+	},
+	{
+		Method:  http.MethodPut,
+		Path:    "/groups/{groupName}/members/{userID}",
+		Handler: addGroupMember,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/groups/{groupName}/members/{userID}",
+		Handler: removeGroupMember,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/gc",
+		Handler: collectGarbage,
+	},
+}
+
+var projectHandlers = []policy.ResponseHandlerParam{
+	{
+		Method:  http.MethodGet,
+		Path:    "/",
+		Handler: listProjects,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/",
+		Handler: createProject,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{projectID}",
+		Handler: getProject,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/{projectID}",
+		Handler: deleteProject,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{projectID}/catalogs",
+		Handler: listProjectCatalogs,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{projectID}/catalogs/{catalogName}/move",
+		Handler: moveCatalog,
+	},
+}
+
+var platformHandlers = []policy.ResponseHandlerParam{
+	{
+		Method:  http.MethodGet,
+		Path:    "/",
+		Handler: listTenants,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/",
+		Handler: createTenant,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{tenantID}",
+		Handler: getTenantInfo,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/{tenantID}",
+		Handler: deleteTenant,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{tenantID}/suspend",
+		Handler: suspendTenant,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{tenantID}/resume",
+		Handler: resumeTenant,
+	},
+}
+
+// Router creates the router for tenant administration. Requests are made by users with
+// their identity token and are authorized by the user's tenant role.
+func Router() chi.Router {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(auth.UserAuthMiddleware)
+		for _, handler := range tenantHandlers {
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
+		}
+	})
+	return r
+}
+
+// ProjectsRouter creates the router for the projects of the caller's tenant and the catalogs
+// in them. Requests are authorized by the user's tenant role, as for Router.
+func ProjectsRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(auth.UserAuthMiddleware)
+		for _, handler := range projectHandlers {
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
+		}
+	})
+	return r
+}
+
+// PlatformRouter creates the router for administering all tenants of the deployment.
+// Requests are made by users with their identity token and are authorized by
+// auth.platform_admins.
+func PlatformRouter() chi.Router {
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(auth.UserAuthMiddleware)
+		for _, handler := range platformHandlers {
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
+		}
+	})
+	return r
+}
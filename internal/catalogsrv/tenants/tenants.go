@@ -0,0 +1,257 @@
+// Package tenants implements administration of the caller's tenant: its projects, the
+// administrative roles of its users, and the users and groups provisioned in it. Tenant
+// roles are separate from catalog views, which only govern access to catalog resources;
+// views are assigned to groups to let the group's members adopt them. Platform admins,
+// listed in the configuration, can also create, suspend and delete tenants.
+package tenants
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/config"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+var projectIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,10}$`)
+
+func getTenant(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	role, err := requireRole(ctx, RoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response: &tenantRsp{
+			TenantID: catcommon.GetTenantID(ctx),
+			Role:     role,
+		},
+	}, nil
+}
+
+func listProjects(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	projects, goerr := db.DB(ctx).ListProjects(ctx)
+	if goerr != nil {
+		return nil, goerr
+	}
+
+	rsp := listProjectsRsp{Items: make([]projectRsp, 0, len(projects))}
+	for _, p := range projects {
+		rsp.Items = append(rsp.Items, projectRsp{ProjectID: p.ProjectID, CreatedAt: p.CreatedAt})
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+func createProject(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	req := createProjectReq{}
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if !projectIDRegex.MatchString(req.ProjectID) {
+		return nil, ErrInvalidProjectID.Msg("project_id must be 1-10 alphanumeric, underscore or hyphen characters")
+	}
+
+	projectID := catcommon.ProjectId(req.ProjectID)
+	if goerr := db.DB(ctx).CreateProject(ctx, projectID); goerr != nil {
+		if errors.Is(goerr, dberror.ErrAlreadyExists) {
+			return nil, ErrProjectExists
+		}
+		return nil, goerr
+	}
+
+	log.Ctx(ctx).Info().Str("project_id", req.ProjectID).Str("user", catcommon.GetUserID(ctx)).Msg("created project")
+
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   "/projects/" + req.ProjectID,
+	}, nil
+}
+
+func deleteProject(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	projectID := catcommon.ProjectId(chi.URLParam(r, "projectID"))
+	if string(projectID) == config.Config().DefaultProjectID {
+		return nil, ErrInvalidRequest.Msg("the default project cannot be deleted")
+	}
+	if _, goerr := lookupProject(ctx, projectID); goerr != nil {
+		return nil, goerr
+	}
+	// Deleting a project deletes its catalogs; they must be moved or deleted first
+	catalogs, err := db.DB(ctx).ListCatalogs(catcommon.WithProjectID(ctx, projectID))
+	if err != nil {
+		return nil, err
+	}
+	if len(catalogs) > 0 {
+		return nil, ErrProjectNotEmpty.Msg("move or delete the catalogs of the project before deleting it")
+	}
+	if goerr := db.DB(ctx).DeleteProject(ctx, projectID); goerr != nil {
+		return nil, goerr
+	}
+
+	log.Ctx(ctx).Info().Str("project_id", string(projectID)).Str("user", catcommon.GetUserID(ctx)).Msg("deleted project")
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+func listRoles(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	roles, err := db.DB(ctx).ListTenantRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := listRolesRsp{Items: make([]roleRsp, 0, len(roles))}
+	for _, role := range roles {
+		rsp.Items = append(rsp.Items, newRoleRsp(role))
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+func setRole(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleOwner); err != nil {
+		return nil, err
+	}
+
+	userID := chi.URLParam(r, "userID")
+	req := setRoleReq{}
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if !req.Role.IsValid() {
+		return nil, ErrInvalidRole.Msg("role must be one of owner, admin or viewer")
+	}
+	if req.Role != RoleOwner {
+		if err := checkNotLastOwner(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	role := &models.TenantRole{
+		UserID:    userID,
+		Role:      string(req.Role),
+		CreatedBy: catcommon.GetUserID(ctx),
+	}
+	if err := db.DB(ctx).SetTenantRole(ctx, role); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Str("role", role.Role).Str("by", catcommon.GetUserID(ctx)).Msg("set tenant role")
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   newRoleRsp(role),
+	}, nil
+}
+
+func deleteRole(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleOwner); err != nil {
+		return nil, err
+	}
+
+	userID := chi.URLParam(r, "userID")
+	if _, err := db.DB(ctx).GetTenantRole(ctx, userID); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	if err := checkNotLastOwner(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := db.DB(ctx).DeleteTenantRole(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Str("by", catcommon.GetUserID(ctx)).Msg("deleted tenant role")
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+// checkNotLastOwner fails if the user is the only owner of the tenant, so that a tenant
+// cannot be left without anyone able to administer it.
+func checkNotLastOwner(ctx context.Context, userID string) apperrors.Error {
+	roles, err := db.DB(ctx).ListTenantRoles(ctx)
+	if err != nil {
+		return err
+	}
+	isOwner := false
+	owners := 0
+	for _, role := range roles {
+		if Role(role.Role) == RoleOwner {
+			owners++
+			if role.UserID == userID {
+				isOwner = true
+			}
+		}
+	}
+	if isOwner && owners == 1 {
+		return ErrLastOwner.Msg("cannot remove the last owner of the tenant")
+	}
+	return nil
+}
+
+func readJSON(r *http.Request, v any) apperrors.Error {
+	if r.Body == nil {
+		return ErrInvalidRequest.Msg("request body is required")
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return ErrInvalidRequest.Msg("unable to read request")
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return ErrInvalidRequest.Msg("unable to parse request: " + err.Error())
+	}
+	return nil
+}
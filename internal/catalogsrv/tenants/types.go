@@ -0,0 +1,171 @@
+package tenants
+
+import (
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+)
+
+type tenantRsp struct {
+	TenantID catcommon.TenantId `json:"tenant_id"`
+	Role     Role               `json:"role"`
+}
+
+// Status of a tenant
+const (
+	TenantActive    = "active"
+	TenantSuspended = "suspended"
+)
+
+type createTenantReq struct {
+	TenantID  string `json:"tenant_id"`
+	ProjectID string `json:"project_id,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+}
+
+type tenantInfoRsp struct {
+	TenantID    catcommon.TenantId `json:"tenant_id"`
+	Status      string             `json:"status"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	SuspendedAt *time.Time         `json:"suspended_at,omitempty"`
+}
+
+type listTenantsRsp struct {
+	Items []tenantInfoRsp `json:"items"`
+}
+
+func newTenantInfoRsp(t *models.Tenant) tenantInfoRsp {
+	status := TenantActive
+	if t.SuspendedAt != nil {
+		status = TenantSuspended
+	}
+	return tenantInfoRsp{
+		TenantID:    t.TenantID,
+		Status:      status,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+		SuspendedAt: t.SuspendedAt,
+	}
+}
+
+type createProjectReq struct {
+	ProjectID string `json:"project_id"`
+}
+
+type projectRsp struct {
+	ProjectID catcommon.ProjectId `json:"project_id"`
+	CreatedAt time.Time           `json:"created_at"`
+}
+
+type listProjectsRsp struct {
+	Items []projectRsp `json:"items"`
+}
+
+type catalogRsp struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	ProjectID   catcommon.ProjectId `json:"project_id"`
+}
+
+type listCatalogsRsp struct {
+	Items []catalogRsp `json:"items"`
+}
+
+func newCatalogRsp(c *models.Catalog) catalogRsp {
+	return catalogRsp{
+		Name:        c.Name,
+		Description: c.Description,
+		ProjectID:   c.ProjectID,
+	}
+}
+
+type moveCatalogReq struct {
+	ToProjectID string `json:"to_project_id"`
+}
+
+type restoreTenantRsp struct {
+	Rows map[string]int64 `json:"rows"`
+}
+
+type setRoleReq struct {
+	Role Role `json:"role"`
+}
+
+type roleRsp struct {
+	UserID    string    `json:"user_id"`
+	Role      Role      `json:"role"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type listRolesRsp struct {
+	Items []roleRsp `json:"items"`
+}
+
+func newRoleRsp(r *models.TenantRole) roleRsp {
+	return roleRsp{
+		UserID:    r.UserID,
+		Role:      Role(r.Role),
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+type createUserReq struct {
+	UserID      string `json:"user_id"`
+	DisplayName string `json:"display_name,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+type userRsp struct {
+	UserID      string    `json:"user_id"`
+	DisplayName string    `json:"display_name,omitempty"`
+	Email       string    `json:"email,omitempty"`
+	Groups      []string  `json:"groups,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type listUsersRsp struct {
+	Items []userRsp `json:"items"`
+}
+
+type createGroupReq struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type groupRsp struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Members     []string  `json:"members,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type listGroupsRsp struct {
+	Items []groupRsp `json:"items"`
+}
+
+// newUserRsp builds the response for a user. Groups are only listed for a single user.
+func newUserRsp(u *models.User, groups []string) userRsp {
+	return userRsp{
+		UserID:      u.UserID,
+		DisplayName: u.DisplayName,
+		Email:       u.Email,
+		Groups:      groups,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+// newGroupRsp builds the response for a group. Members are only listed for a single group.
+func newGroupRsp(g *models.Group, members []string) groupRsp {
+	return groupRsp{
+		Name:        g.Name,
+		Description: g.Description,
+		Members:     members,
+		CreatedAt:   g.CreatedAt,
+	}
+}
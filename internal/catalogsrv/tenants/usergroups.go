@@ -0,0 +1,276 @@
+package tenants
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/dberror"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/db/models"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+var (
+	userIDRegex    = regexp.MustCompile(`^[A-Za-z0-9_.@+-]{1,128}$`)
+	groupNameRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+)
+
+func createUser(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	req := createUserReq{}
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if !userIDRegex.MatchString(req.UserID) {
+		return nil, ErrInvalidRequest.Msg("user_id must be 1-128 alphanumeric, underscore, hyphen, period, plus or @ characters")
+	}
+
+	user := &models.User{
+		UserID:      req.UserID,
+		DisplayName: req.DisplayName,
+		Email:       req.Email,
+	}
+	if err := db.DB(ctx).CreateUser(ctx, user); err != nil {
+		if errors.Is(err, dberror.ErrAlreadyExists) {
+			return nil, ErrUserExists
+		}
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", user.UserID).Str("by", catcommon.GetUserID(ctx)).Msg("created user")
+
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   "/tenant/users/" + user.UserID,
+		Response:   newUserRsp(user, []string{}),
+	}, nil
+}
+
+func getUser(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	userID := chi.URLParam(r, "userID")
+	user, err := db.DB(ctx).GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	groups, err := db.DB(ctx).ListUserGroups(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   newUserRsp(user, groups),
+	}, nil
+}
+
+func listUsers(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	users, err := db.DB(ctx).ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := listUsersRsp{Items: make([]userRsp, 0, len(users))}
+	for _, user := range users {
+		rsp.Items = append(rsp.Items, newUserRsp(user, nil))
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+func deleteUser(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	userID := chi.URLParam(r, "userID")
+	if err := db.DB(ctx).DeleteUser(ctx, userID); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("user_id", userID).Str("by", catcommon.GetUserID(ctx)).Msg("deleted user")
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+func createGroup(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	req := createGroupReq{}
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if !groupNameRegex.MatchString(req.Name) {
+		return nil, ErrInvalidRequest.Msg("name must be 1-128 alphanumeric, underscore or hyphen characters")
+	}
+
+	group := &models.Group{
+		Name:        req.Name,
+		Description: req.Description,
+	}
+	if err := db.DB(ctx).CreateGroup(ctx, group); err != nil {
+		if errors.Is(err, dberror.ErrAlreadyExists) {
+			return nil, ErrGroupExists
+		}
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("group", group.Name).Str("by", catcommon.GetUserID(ctx)).Msg("created group")
+
+	return &httpx.Response{
+		StatusCode: http.StatusCreated,
+		Location:   "/tenant/groups/" + group.Name,
+		Response:   newGroupRsp(group, []string{}),
+	}, nil
+}
+
+func getGroup(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	name := chi.URLParam(r, "groupName")
+	group, err := db.DB(ctx).GetGroup(ctx, name)
+	if err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, err
+	}
+	members, err := db.DB(ctx).ListGroupMembers(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   newGroupRsp(group, members),
+	}, nil
+}
+
+func listGroups(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleViewer); err != nil {
+		return nil, err
+	}
+
+	groups, err := db.DB(ctx).ListGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := listGroupsRsp{Items: make([]groupRsp, 0, len(groups))}
+	for _, group := range groups {
+		rsp.Items = append(rsp.Items, newGroupRsp(group, nil))
+	}
+
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+func deleteGroup(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	name := chi.URLParam(r, "groupName")
+	if err := db.DB(ctx).DeleteGroup(ctx, name); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("group", name).Str("by", catcommon.GetUserID(ctx)).Msg("deleted group")
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+func addGroupMember(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	name := chi.URLParam(r, "groupName")
+	userID := chi.URLParam(r, "userID")
+	if err := db.DB(ctx).AddGroupMember(ctx, name, userID); err != nil {
+		if errors.Is(err, dberror.ErrNotFound) {
+			return nil, ErrGroupNotFound.Msg("group or user not found")
+		}
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("group", name).Str("user_id", userID).Str("by", catcommon.GetUserID(ctx)).Msg("added group member")
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
+
+func removeGroupMember(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	if _, err := requireRole(ctx, RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	name := chi.URLParam(r, "groupName")
+	userID := chi.URLParam(r, "userID")
+	if err := db.DB(ctx).RemoveGroupMember(ctx, name, userID); err != nil {
+		return nil, err
+	}
+
+	log.Ctx(ctx).Info().Str("group", name).Str("user_id", userID).Str("by", catcommon.GetUserID(ctx)).Msg("removed group member")
+
+	return &httpx.Response{
+		StatusCode: http.StatusNoContent,
+	}, nil
+}
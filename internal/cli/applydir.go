@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+)
+
+// applyOrder lists the kinds in the order they are applied, so that the parent of every
+// object exists before the object itself.
+var applyOrder = []string{
+	KindCatalog,
+	KindVariant,
+	KindNamespace,
+	KindView,
+	KindViewTemplate,
+	KindAdmissionWebhook,
+	KindSkillset,
+	KindResource,
+}
+
+// manifestExtensions are the extensions of the files read from a manifest directory.
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// Manifest is the set of objects read from a file or a directory of files, in apply order.
+type Manifest struct {
+	Files     []string
+	Resources []Resource
+}
+
+// applyResult is the outcome of applying a single object, as reported by /apply.
+type applyResult struct {
+	Kind     string `json:"kind"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Location string `json:"location,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+type applyRsp struct {
+	DryRun  bool          `json:"dryRun,omitempty"`
+	Results []applyResult `json:"results"`
+}
+
+// LoadManifest reads the objects in filename, or in every YAML or JSON file below it if it
+// is a directory. Files are read in lexical order and objects are ordered by kind,
+// keeping the order in which objects of the same kind were read.
+func LoadManifest(filename string) (*Manifest, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", filename, err)
+	}
+
+	manifest := &Manifest{}
+	if info.IsDir() {
+		err = filepath.WalkDir(filename, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && slices.Contains(manifestExtensions, strings.ToLower(filepath.Ext(path))) {
+				manifest.Files = append(manifest.Files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read directory %s: %v", filename, err)
+		}
+		if len(manifest.Files) == 0 {
+			return nil, fmt.Errorf("no YAML or JSON files found in %s", filename)
+		}
+	} else {
+		manifest.Files = []string{filename}
+	}
+
+	byKind := make(map[string][]Resource)
+	for _, file := range manifest.Files {
+		resources, err := LoadResourceFromMultiYAMLFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		for kind, list := range resources {
+			byKind[kind] = append(byKind[kind], list...)
+		}
+	}
+	for _, kind := range applyOrder {
+		manifest.Resources = append(manifest.Resources, byKind[kind]...)
+	}
+	return manifest, nil
+}
+
+// Summary returns a one line description of the objects in the manifest, e.g.
+// "3 objects in 2 files: 1 Variant, 2 Resource".
+func (m *Manifest) Summary() string {
+	var counts []string
+	for _, kind := range applyOrder {
+		n := 0
+		for _, r := range m.Resources {
+			if r.Metadata.Kind == kind {
+				n++
+			}
+		}
+		if n > 0 {
+			counts = append(counts, fmt.Sprintf("%d %s", n, kind))
+		}
+	}
+	files := "file"
+	if len(m.Files) != 1 {
+		files = "files"
+	}
+	return fmt.Sprintf("%d objects in %d %s: %s", len(m.Resources), len(m.Files), files, strings.Join(counts, ", "))
+}
+
+// bulkDocuments returns the objects of the manifest that are sent to /apply, other than
+// catalogs, as a multi-document stream. Each document is JSON, which is valid YAML.
+func (m *Manifest) bulkDocuments() []byte {
+	var b bytes.Buffer
+	for _, r := range m.Resources {
+		if r.Metadata.Kind == KindCatalog {
+			continue
+		}
+		b.WriteString("---\n")
+		b.Write(r.JSON)
+		b.WriteString("\n")
+	}
+	return b.Bytes()
+}
+
+// applyManifest applies the objects of the manifest. Catalogs are created or updated one at
+// a time, since /apply operates within a catalog; all other objects are sent to /apply in a
+// single request. With prune, /apply also deletes the skillsets and resources of the
+// variants and namespaces in the manifest that the manifest does not contain. With dryRun,
+// the server validates the objects and reports the outcome without changing anything.
+func applyManifest(manifest *Manifest, prune, dryRun bool) error {
+	var results []applyResult
+	var applyErr error
+	catalog := updateCatalog
+	for _, r := range manifest.Resources {
+		if r.Metadata.Kind != KindCatalog {
+			continue
+		}
+		name, _ := r.Metadata.Metadata["name"].(string)
+		kv, err := handleUpdateResource(r.Metadata, r.JSON)
+		if err != nil {
+			results = append(results, applyResult{Kind: KindCatalog, Name: name, Status: "failed", Error: err.Error()})
+			applyErr = ErrAlreadyHandled
+			break
+		}
+		result := applyResult{Kind: KindCatalog, Name: name, Status: "updated"}
+		if created, _ := kv["created"].(bool); created {
+			result.Status = "created"
+			result.Location, _ = kv["location"].(string)
+		}
+		results = append(results, result)
+		// A manifest for a single catalog is applied to that catalog
+		if updateCatalog == "" {
+			if catalog == "" {
+				catalog = name
+			} else if catalog != name {
+				catalog = ""
+			}
+		}
+	}
+
+	if docs := manifest.bulkDocuments(); applyErr == nil && len(docs) > 0 {
+		queryParams := contextQueryParams(catalog, updateVariant, updateNamespace)
+		if prune {
+			queryParams["prune"] = "true"
+		}
+		if dryRun {
+			queryParams["dryRun"] = "true"
+		}
+		client := httpclient.NewClient(GetConfig())
+		body, _, err := client.DoRequest(httpclient.RequestOptions{
+			Method:      http.MethodPost,
+			Path:        "apply",
+			QueryParams: queryParams,
+			Body:        docs,
+		})
+		var rsp applyRsp
+		if err != nil {
+			// A failed apply still reports the outcome of the objects attempted so far
+			var httpErr *httpclient.HTTPError
+			if !errors.As(err, &httpErr) || json.Unmarshal([]byte(httpErr.Message), &rsp) != nil || len(rsp.Results) == 0 {
+				rsp.Results = []applyResult{{Status: "failed", Error: err.Error()}}
+			}
+			applyErr = ErrAlreadyHandled
+		} else if err := json.Unmarshal(body, &rsp); err != nil {
+			return fmt.Errorf("unable to parse apply response: %v", err)
+		}
+		results = append(results, rsp.Results...)
+	}
+
+	printApplyResults(results, dryRun)
+	return applyErr
+}
+
+// printApplyResults prints the outcome of each object followed by the totals.
+func printApplyResults(results []applyResult, dryRun bool) {
+	if jsonOutput {
+		printJSON(results)
+		return
+	}
+	counts := make(map[string]int)
+	for _, r := range results {
+		counts[r.Status]++
+		object := strings.TrimPrefix(r.Kind+": "+r.Name, ": ")
+		switch r.Status {
+		case "created":
+			okLabel.Fprintf(os.Stdout, "[OK] ")
+			fmt.Fprintf(os.Stdout, "Created: %s\n", object)
+		case "updated":
+			okLabel.Fprintf(os.Stdout, "[OK] ")
+			fmt.Fprintf(os.Stdout, "Updated: %s\n", object)
+		case "pruned":
+			okLabel.Fprintf(os.Stdout, "[OK] ")
+			fmt.Fprintf(os.Stdout, "Pruned: %s\n", object)
+		case "deleted":
+			okLabel.Fprintf(os.Stdout, "[OK] ")
+			fmt.Fprintf(os.Stdout, "Deleted: %s\n", object)
+		default:
+			errorLabel.Fprintf(os.Stderr, "[ERROR] ")
+			fmt.Fprintf(os.Stderr, "%s: %s\n", object, r.Error)
+		}
+	}
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run)"
+	}
+	if counts["deleted"] > 0 {
+		fmt.Fprintf(os.Stdout, "%d deleted, %d failed%s\n", counts["deleted"], counts["failed"], suffix)
+		return
+	}
+	fmt.Fprintf(os.Stdout, "%d created, %d updated, %d pruned, %d failed%s\n",
+		counts["created"], counts["updated"], counts["pruned"], counts["failed"], suffix)
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"resources/b.yaml": `
+apiVersion: 0.1.0-alpha.1
+kind: Resource
+metadata:
+  name: timeout
+spec:
+  schema:
+    type: integer
+---
+apiVersion: 0.1.0-alpha.1
+kind: Variant
+metadata:
+  name: dev
+`,
+		"resources/a.yml": `
+apiVersion: 0.1.0-alpha.1
+kind: Resource
+metadata:
+  name: retries
+spec:
+  schema:
+    type: integer
+`,
+		"catalog.json": `{"apiVersion": "0.1.0-alpha.1", "kind": "Catalog", "metadata": {"name": "my-catalog"}}`,
+		"README.md":    "not a manifest",
+	}
+	for name, content := range files {
+		p := filepath.Join(tmpDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+
+	manifest, err := LoadManifest(tmpDir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(tmpDir, "catalog.json"),
+		filepath.Join(tmpDir, "resources/a.yml"),
+		filepath.Join(tmpDir, "resources/b.yaml"),
+	}, manifest.Files)
+
+	var names []string
+	for _, r := range manifest.Resources {
+		names = append(names, r.Metadata.Kind+"/"+r.Metadata.Metadata["name"].(string))
+	}
+	assert.Equal(t, []string{"Catalog/my-catalog", "Variant/dev", "Resource/retries", "Resource/timeout"}, names)
+	assert.Equal(t, "4 objects in 3 files: 1 Catalog, 1 Variant, 2 Resource", manifest.Summary())
+
+	docs, err := ParseMultiYAMLFromBytes(manifest.bulkDocuments())
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+	assert.Equal(t, "Variant", docs[0]["kind"])
+
+	// A single file is read as is
+	manifest, err = LoadManifest(filepath.Join(tmpDir, "resources/b.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "2 objects in 1 file: 1 Variant, 1 Resource", manifest.Summary())
+
+	// Errors name the offending file
+	bad := filepath.Join(tmpDir, "resources/c.yaml")
+	require.NoError(t, os.WriteFile(bad, []byte("kind: Unknown\nmetadata:\n  name: x\n"), 0644))
+	_, err = LoadManifest(tmpDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), bad)
+
+	_, err = LoadManifest(t.TempDir())
+	assert.Error(t, err)
+}
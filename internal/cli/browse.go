@@ -0,0 +1,472 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+var (
+	// Browse command flags
+	browseCatalog   string
+	browseVariant   string
+	browseNamespace string
+)
+
+// defaultNamespaceEntry is the name shown for the objects of a variant that are not in a
+// namespace.
+const defaultNamespaceEntry = "(default)"
+
+// browseCollections are the collections of objects browsed within a namespace.
+var browseCollections = []string{"resources", "skillsets"}
+
+// Levels of a browse location.
+const (
+	browseLevelRoot = iota
+	browseLevelCatalog
+	browseLevelVariant
+	browseLevelNamespace
+	browseLevelCollection
+)
+
+// browseCommands describes the commands of the browser.
+const browseCommands = `Commands:
+  ls                    List the entries at the current location
+  cd ENTRY|NUMBER|PATH  Move to an entry, by name or by its number in the listing;
+                        ".." moves up and "/" to the top
+  show [ENTRY]          Show the object at the current location or the given entry
+  set ATTRIBUTE VALUE   Set an attribute of the resource value, e.g. "set limits.cpu 2";
+                        use "." as the attribute to replace the whole value. VALUE is
+                        read as JSON, and as a string if it is not valid JSON
+  help                  Show the commands
+  exit                  Leave the browser`
+
+// browseCmd represents the browse command
+var browseCmd = &cobra.Command{
+	Use:   "browse [flags]",
+	Short: "Browse catalogs interactively",
+	Long: `Browse catalogs interactively. The objects you can see are presented as a tree of
+catalogs, variants, namespaces and the resources and skillsets in them, which you move
+through like directories. Resource values can be inspected and individual attributes
+edited in place, without composing resource paths by hand.
+
+` + browseCommands + `
+
+Examples:
+  # Browse every catalog
+  tansive browse
+
+  # Start in a variant of a catalog
+  tansive browse -c my-catalog -v my-variant`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b := newBrowser(httpclient.NewClient(GetConfig()), os.Stdin, os.Stdout)
+		// Start in the catalog and variant given or configured; the namespace only if given
+		params := contextQueryParams(browseCatalog, browseVariant, "")
+		if catalog := params["catalog"]; catalog != "" {
+			b.location = append(b.location, catalog)
+			if variant := params["variant"]; variant != "" {
+				b.location = append(b.location, variant)
+				if browseNamespace != "" {
+					b.location = append(b.location, browseNamespace)
+				}
+			}
+		}
+		return b.run()
+	},
+}
+
+// browser is an interactive session that walks the objects of the catalogs.
+type browser struct {
+	client *httpclient.HTTPClient
+	in     *bufio.Scanner
+	out    io.Writer
+	// location is the catalog, variant, namespace and collection being browsed followed by
+	// the segments of an object path. Any suffix may be absent.
+	location []string
+}
+
+// browseEntry is an entry listed at a location. Leaf entries are objects with no entries
+// of their own.
+type browseEntry struct {
+	Name        string
+	Description string
+	Leaf        bool
+}
+
+func newBrowser(client *httpclient.HTTPClient, in io.Reader, out io.Writer) *browser {
+	return &browser{
+		client: client,
+		in:     bufio.NewScanner(in),
+		out:    out,
+	}
+}
+
+// run reads and executes commands until the input ends or the user exits. Errors of
+// individual commands are printed and do not end the session.
+func (b *browser) run() error {
+	fmt.Fprintln(b.out, `Type "help" for the commands.`)
+	for {
+		fmt.Fprintf(b.out, "%s> ", b.prompt())
+		if !b.in.Scan() {
+			fmt.Fprintln(b.out)
+			return b.in.Err()
+		}
+		fields := strings.Fields(b.in.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch command, args := fields[0], fields[1:]; command {
+		case "ls":
+			err = b.list()
+		case "cd":
+			if len(args) != 1 {
+				err = errors.New("usage: cd ENTRY|NUMBER|PATH")
+				break
+			}
+			if err = b.changeLocation(args[0]); err == nil {
+				err = b.list()
+			}
+		case "show":
+			if len(args) > 1 {
+				err = errors.New("usage: show [ENTRY]")
+				break
+			}
+			err = b.show(args)
+		case "set":
+			if len(args) < 2 {
+				err = errors.New("usage: set ATTRIBUTE VALUE")
+				break
+			}
+			// The value is the rest of the line, so that it may contain spaces
+			line := strings.TrimSpace(b.in.Text())
+			value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(line, command)), args[0]))
+			err = b.set(args[0], value)
+		case "help":
+			fmt.Fprintln(b.out, browseCommands)
+		case "exit", "quit", "q":
+			return nil
+		default:
+			err = fmt.Errorf("unknown command %q, type \"help\" for the commands", command)
+		}
+		if err != nil {
+			errorLabel.Fprintf(b.out, "[ERROR] ")
+			fmt.Fprintln(b.out, err)
+		}
+	}
+}
+
+// prompt returns the current location as a path.
+func (b *browser) prompt() string {
+	return "/" + strings.Join(b.location, "/")
+}
+
+// queryParams returns the query parameters selecting the catalog, variant and namespace of
+// the current location.
+func (b *browser) queryParams() map[string]string {
+	params := make(map[string]string)
+	if len(b.location) > browseLevelRoot {
+		params["catalog"] = b.location[0]
+	}
+	if len(b.location) > browseLevelCatalog {
+		params["variant"] = b.location[1]
+	}
+	if len(b.location) > browseLevelVariant && b.location[2] != defaultNamespaceEntry {
+		params["namespace"] = b.location[2]
+	}
+	return params
+}
+
+// objectPath returns the path of the object location within its collection.
+func (b *browser) objectPath() string {
+	if len(b.location) <= browseLevelCollection {
+		return "/"
+	}
+	return "/" + strings.Join(b.location[browseLevelCollection:], "/")
+}
+
+// entries returns the entries at the current location.
+func (b *browser) entries() ([]browseEntry, error) {
+	switch len(b.location) {
+	case browseLevelRoot:
+		return b.listEntries("catalogs")
+	case browseLevelCatalog:
+		return b.listEntries("variants")
+	case browseLevelVariant:
+		entries, err := b.listEntries("namespaces")
+		if err != nil {
+			return nil, err
+		}
+		return append([]browseEntry{{Name: defaultNamespaceEntry, Description: "Objects not in a namespace"}}, entries...), nil
+	case browseLevelNamespace:
+		entries := make([]browseEntry, 0, len(browseCollections))
+		for _, collection := range browseCollections {
+			entries = append(entries, browseEntry{Name: collection})
+		}
+		return entries, nil
+	}
+
+	// Objects are listed by their full path, and the entries are the next segments of the
+	// paths below the current location
+	objects, err := b.listEntries(b.location[browseLevelNamespace])
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.TrimSuffix(b.objectPath(), "/") + "/"
+	seen := make(map[string]int)
+	var entries []browseEntry
+	for _, object := range objects {
+		rest, ok := strings.CutPrefix(object.Name, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		name, _, isDir := strings.Cut(rest, "/")
+		if i, ok := seen[name]; ok {
+			// A name may be both an object and a folder of other objects
+			entries[i].Leaf = entries[i].Leaf && !isDir
+			continue
+		}
+		seen[name] = len(entries)
+		entry := browseEntry{Name: name, Leaf: !isDir}
+		if !isDir {
+			entry.Description = object.Description
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries, nil
+}
+
+// listEntries lists every object of a type in the current catalog, variant and namespace,
+// following the cursors of paged responses.
+func (b *browser) listEntries(resourceType string) ([]browseEntry, error) {
+	params := b.queryParams()
+	var entries []browseEntry
+	for {
+		response, err := b.client.ListResources(resourceType, params)
+		if err != nil {
+			return nil, err
+		}
+		rows, next, err := listRows(resourceType, response)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			entries = append(entries, browseEntry{Name: row.Name, Description: row.Description})
+		}
+		if next == "" {
+			return entries, nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// list prints the entries at the current location, numbered so they can be selected by
+// number. At an object, the object is shown instead.
+func (b *browser) list() error {
+	entries, err := b.entries()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		if isObject, err := b.isObject(); err != nil || isObject {
+			if err != nil {
+				return err
+			}
+			return b.show(nil)
+		}
+		fmt.Fprintln(b.out, "No entries.")
+		return nil
+	}
+	for i, entry := range entries {
+		name := entry.Name
+		if !entry.Leaf {
+			name += "/"
+		}
+		fmt.Fprintf(b.out, "%4d  %-30s %s\n", i+1, name, entry.Description)
+	}
+	return nil
+}
+
+// changeLocation moves to target, a slash separated path of entries, numbers, ".." and
+// ".", which is absolute if it starts with a slash. The location is unchanged if any
+// part of the path is not found.
+func (b *browser) changeLocation(target string) error {
+	saved := b.location
+	b.location = append([]string(nil), b.location...)
+	if strings.HasPrefix(target, "/") {
+		b.location = nil
+	}
+	for _, segment := range strings.Split(target, "/") {
+		if err := b.step(segment); err != nil {
+			b.location = saved
+			return err
+		}
+	}
+	return nil
+}
+
+// step moves one level, to the parent or to an entry of the current location.
+func (b *browser) step(segment string) error {
+	switch segment {
+	case "", ".":
+		return nil
+	case "..":
+		if len(b.location) > 0 {
+			b.location = b.location[:len(b.location)-1]
+		}
+		return nil
+	}
+	entries, err := b.entries()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name == segment {
+			b.location = append(b.location, entry.Name)
+			return nil
+		}
+	}
+	if n, err := strconv.Atoi(segment); err == nil && n >= 1 && n <= len(entries) {
+		b.location = append(b.location, entries[n-1].Name)
+		return nil
+	}
+	return fmt.Errorf("%s not found in %s", segment, b.prompt())
+}
+
+// isObject reports whether the current location is a resource or a skillset.
+func (b *browser) isObject() (bool, error) {
+	if len(b.location) <= browseLevelCollection {
+		return false, nil
+	}
+	objects, err := b.listEntries(b.location[browseLevelNamespace])
+	if err != nil {
+		return false, err
+	}
+	for _, object := range objects {
+		if object.Name == b.objectPath() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// show prints the object at the current location or at the given entry of it. Resources
+// are shown by their value; other objects by their definition.
+func (b *browser) show(args []string) error {
+	if len(args) == 1 {
+		saved := b.location
+		defer func() { b.location = saved }()
+		if err := b.changeLocation(args[0]); err != nil {
+			return err
+		}
+	}
+
+	params := b.queryParams()
+	var response []byte
+	var err error
+	switch len(b.location) {
+	case browseLevelRoot:
+		return errors.New("choose a catalog to show")
+	case browseLevelCatalog:
+		response, err = b.client.GetResource("catalogs", b.location[0], nil, "")
+	case browseLevelVariant:
+		delete(params, "variant")
+		response, err = b.client.GetResource("variants", b.location[1], params, "")
+	case browseLevelNamespace:
+		if b.location[2] == defaultNamespaceEntry {
+			return errors.New("the default namespace has no definition")
+		}
+		delete(params, "namespace")
+		response, err = b.client.GetResource("namespaces", b.location[2], params, "")
+	case browseLevelCollection:
+		return errors.New("choose an object to show")
+	default:
+		isObject, err := b.isObject()
+		if err != nil {
+			return err
+		}
+		if !isObject {
+			return fmt.Errorf("%s is not an object", b.prompt())
+		}
+		response, err = b.client.GetResource(b.location[browseLevelNamespace], b.objectPath(), params, "")
+		if err != nil {
+			return err
+		}
+		return printDocument(b.out, OutputYAML, response)
+	}
+	if err != nil {
+		return err
+	}
+	return printDescription(b.out, false, response)
+}
+
+// set sets an attribute of the value of the resource at the current location and shows
+// the updated value. The attribute is a dot separated path into the value, or "." for the
+// whole value.
+func (b *browser) set(attribute, value string) error {
+	if len(b.location) <= browseLevelCollection || b.location[browseLevelNamespace] != "resources" {
+		return errors.New("values can only be set on a resource")
+	}
+	isObject, err := b.isObject()
+	if err != nil {
+		return err
+	}
+	if !isObject {
+		return fmt.Errorf("%s is not a resource", b.prompt())
+	}
+
+	newValue := []byte(value)
+	if !json.Valid(newValue) {
+		newValue, _ = json.Marshal(value)
+	}
+	if attribute != "." {
+		current, err := b.client.GetResource("resources", b.objectPath(), b.queryParams(), "")
+		if err != nil {
+			return err
+		}
+		if parsed := gjson.ParseBytes(current); !parsed.IsObject() && !parsed.IsArray() {
+			return errors.New("the value has no attributes; use \".\" to replace the whole value")
+		}
+		newValue, err = sjson.SetRawBytes(current, attribute, newValue)
+		if err != nil {
+			return fmt.Errorf("invalid attribute %q: %v", attribute, err)
+		}
+	}
+
+	_, _, err = b.client.DoRequest(httpclient.RequestOptions{
+		Method:      http.MethodPut,
+		Path:        path.Join("resources", b.objectPath()),
+		QueryParams: b.queryParams(),
+		Body:        newValue,
+	})
+	if err != nil {
+		return err
+	}
+	okLabel.Fprintf(b.out, "[OK] ")
+	fmt.Fprintf(b.out, "Updated: %s\n", b.prompt())
+	return b.show(nil)
+}
+
+// init initializes the browse command with its flags and adds it to the root command
+func init() {
+	rootCmd.AddCommand(browseCmd)
+
+	browseCmd.Flags().StringVarP(&browseCatalog, "catalog", "c", "", "Catalog to start in")
+	browseCmd.Flags().StringVarP(&browseVariant, "variant", "v", "", "Variant to start in")
+	browseCmd.Flags().StringVarP(&browseNamespace, "namespace", "n", "", "Namespace to start in")
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+)
+
+func TestBrowse(t *testing.T) {
+	value := `{"limits": {"cpu": 1, "memory": "1Gi"}}`
+	var put string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.URL.Path == "/catalogs":
+			w.Write([]byte(`{"catalogs": [{"name": "my-catalog", "description": "Main catalog"}]}`))
+		case r.URL.Path == "/variants" && q.Get("catalog") == "my-catalog":
+			w.Write([]byte(`{"variants": [{"name": "dev"}]}`))
+		case r.URL.Path == "/namespaces" && q.Get("variant") == "dev":
+			w.Write([]byte(`{"namespaces": [{"name": "team-a"}]}`))
+		case r.URL.Path == "/resources" && q.Get("namespace") == "":
+			// The second page is fetched with the cursor of the first
+			if q.Get("cursor") == "" {
+				w.Write([]byte(`{"resources": [{"kind": "Resource", "metadata": {"name": "quota", "path": "/config"}}], "nextCursor": "next"}`))
+				return
+			}
+			w.Write([]byte(`{"resources": [{"kind": "Resource", "metadata": {"name": "region", "path": "/config/regions"}}]}`))
+		case r.URL.Path == "/resources/config/quota" && r.Method == http.MethodGet:
+			w.Write([]byte(value))
+		case r.URL.Path == "/resources/config/quota" && r.Method == http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			put = string(body)
+			value = put
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	script := strings.Join([]string{
+		"ls",
+		"cd 1",
+		"cd dev/(default)",
+		"cd resources/config",
+		"cd missing",
+		"cd quota",
+		"set limits.cpu 2",
+		"set limits.memory 2 Gi",
+		"cd ..",
+		"set x 1",
+		"exit",
+	}, "\n")
+	var out bytes.Buffer
+	b := newBrowser(httpclient.NewClient(&Config{ServerURL: server.URL}), strings.NewReader(script), &out)
+	require.NoError(t, b.run())
+
+	output := out.String()
+	assert.Contains(t, output, "1  my-catalog/")
+	assert.Contains(t, output, "Main catalog")
+	// Both pages are listed, and folders are shown as such
+	assert.Contains(t, output, "1  quota ")
+	assert.Contains(t, output, "2  regions/")
+	assert.Contains(t, output, "missing not found in /my-catalog/dev/(default)/resources/config")
+	assert.Contains(t, output, "Updated: /my-catalog/dev/(default)/resources/config/quota")
+	assert.Contains(t, output, "/config is not a resource")
+	assert.JSONEq(t, `{"limits": {"cpu": 2, "memory": "2 Gi"}}`, put)
+	assert.Equal(t, []string{"my-catalog", "dev", "(default)", "resources", "config"}, b.location)
+}
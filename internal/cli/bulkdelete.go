@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+)
+
+// selectorDeleteKinds are the kinds that can be deleted by label selector. Their lists are
+// filtered by labels on the server.
+var selectorDeleteKinds = []string{KindVariant, KindNamespace, KindSkillset, KindAdmissionWebhook}
+
+// deleteBySelector deletes the objects of a type that match a label selector. The matches,
+// and with cascade the objects they hold, are listed first and deleted once confirmed on in,
+// unless yes is set. The deletion itself is done by the server, which orders it so that the
+// contents of variants and namespaces are deleted before them.
+func deleteBySelector(resourceType, selector string, cascade, yes bool, in io.Reader) error {
+	urlResourceType, err := MapResourceTypeToURL(resourceType)
+	if err != nil {
+		return err
+	}
+	kind, err := kindForResourceType(urlResourceType)
+	if err != nil || !slices.Contains(selectorDeleteKinds, kind) {
+		return fmt.Errorf("%s cannot be deleted by label selector", urlResourceType)
+	}
+
+	if jsonOutput && !yes {
+		return errors.New("--yes is required with -j, since there is no prompt")
+	}
+
+	client := httpclient.NewClient(GetConfig())
+	queryParams := contextQueryParams(deleteCatalog, deleteVariant, deleteNamespace)
+	docs, err := listSelectedDocuments(client, kind, urlResourceType, selector, queryParams)
+	if err != nil {
+		return err
+	}
+	if len(docs) == 0 {
+		if jsonOutput {
+			printJSON([]applyResult{})
+		} else {
+			fmt.Fprintf(os.Stdout, "No %s match %s.\n", urlResourceType, selector)
+		}
+		return nil
+	}
+
+	var body strings.Builder
+	for _, doc := range docs {
+		j, err := json.Marshal(doc)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %v", doc.Metadata["name"], err)
+		}
+		body.WriteString("---\n")
+		body.Write(j)
+		body.WriteString("\n")
+	}
+	if cascade {
+		queryParams["cascade"] = "true"
+	}
+
+	if !yes {
+		// A dry run lists everything that would be deleted, including the contents of
+		// variants and namespaces, and fails as the deletion would
+		dryRunParams := make(map[string]string, len(queryParams)+1)
+		for k, v := range queryParams {
+			dryRunParams[k] = v
+		}
+		dryRunParams["dryRun"] = "true"
+		results, err := postDelete(client, dryRunParams, body.String())
+		if err != nil {
+			if results != nil {
+				printApplyResults(results, true)
+			}
+			return err
+		}
+		fmt.Fprintln(os.Stdout, "The following objects will be deleted:")
+		for _, r := range results {
+			fmt.Fprintf(os.Stdout, "  %s: %s\n", r.Kind, r.Name)
+		}
+		fmt.Fprintf(os.Stdout, "Delete %d objects? [y/N]: ", len(results))
+		answer, _ := bufio.NewReader(in).ReadString('\n')
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			fmt.Fprintln(os.Stdout, "Nothing deleted.")
+			return nil
+		}
+	}
+
+	results, err := postDelete(client, queryParams, body.String())
+	if results != nil {
+		printApplyResults(results, false)
+	}
+	return err
+}
+
+// selectedDocument is the part of an object document that identifies the object to /delete.
+type selectedDocument struct {
+	Kind     string         `json:"kind"`
+	Metadata map[string]any `json:"metadata"`
+}
+
+// listSelectedDocuments lists every object of a kind that matches the selector, following
+// the cursors of paged responses, and returns documents identifying them.
+func listSelectedDocuments(client *httpclient.HTTPClient, kind, resourceType, selector string, queryParams map[string]string) ([]selectedDocument, error) {
+	params := make(map[string]string, len(queryParams)+2)
+	for k, v := range queryParams {
+		params[k] = v
+	}
+	params["labelSelector"] = selector
+
+	var docs []selectedDocument
+	for {
+		response, err := client.ListResources(resourceType, params)
+		if err != nil {
+			return nil, err
+		}
+		var list map[string]json.RawMessage
+		if err := json.Unmarshal(response, &list); err != nil {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+		var items []map[string]any
+		if err := json.Unmarshal(list[resourceType], &items); err != nil && len(list[resourceType]) > 0 {
+			return nil, fmt.Errorf("failed to parse response: %v", err)
+		}
+		for _, item := range items {
+			// Some kinds are listed as summaries and others as full documents
+			metadata := map[string]any{"name": item["name"]}
+			if m, ok := item["metadata"].(map[string]any); ok {
+				metadata = map[string]any{"name": m["name"]}
+				if p, ok := m["path"]; ok {
+					metadata["path"] = p
+				}
+			}
+			if v := queryParams["variant"]; v != "" && kind != KindVariant {
+				metadata["variant"] = v
+			}
+			if ns := queryParams["namespace"]; ns != "" && kind != KindVariant && kind != KindNamespace {
+				metadata["namespace"] = ns
+			}
+			docs = append(docs, selectedDocument{Kind: kind, Metadata: metadata})
+		}
+		var next string
+		_ = json.Unmarshal(list["nextCursor"], &next)
+		if next == "" {
+			return docs, nil
+		}
+		params["cursor"] = next
+	}
+}
+
+// postDelete sends the documents to /delete and returns the outcome of each object. A failed
+// delete still reports the outcome of the objects attempted so far; the error is then
+// ErrAlreadyHandled once the results are printed.
+func postDelete(client *httpclient.HTTPClient, queryParams map[string]string, docs string) ([]applyResult, error) {
+	body, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method:      http.MethodPost,
+		Path:        "delete",
+		QueryParams: queryParams,
+		Body:        []byte(docs),
+	})
+	var rsp applyRsp
+	if err != nil {
+		var httpErr *httpclient.HTTPError
+		if !errors.As(err, &httpErr) || json.Unmarshal([]byte(httpErr.Message), &rsp) != nil || len(rsp.Results) == 0 {
+			rsp.Results = []applyResult{{Status: "failed", Error: err.Error()}}
+		}
+		return rsp.Results, ErrAlreadyHandled
+	}
+	if err := json.Unmarshal(body, &rsp); err != nil {
+		return nil, fmt.Errorf("unable to parse delete response: %v", err)
+	}
+	return rsp.Results, nil
+}
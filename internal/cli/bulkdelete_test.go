@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeleteBySelector(t *testing.T) {
+	var deletes []string
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/namespaces":
+			assert.Equal(t, "team=payments", q.Get("labelSelector"))
+			if q.Get("cursor") == "" {
+				w.Write([]byte(`{"namespaces": [{"name": "payments"}], "nextCursor": "next"}`))
+				return
+			}
+			w.Write([]byte(`{"namespaces": [{"name": "billing"}]}`))
+		case "/delete":
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+			deletes = append(deletes, q.Get("dryRun")+"|"+q.Get("cascade"))
+			w.Write([]byte(`{"results": [
+				{"kind": "Resource", "name": "limit", "status": "deleted"},
+				{"kind": "Namespace", "name": "billing", "status": "deleted"},
+				{"kind": "Namespace", "name": "payments", "status": "deleted"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	oldConfig := config
+	config = &Config{ServerURL: server.URL}
+	t.Cleanup(func() { config = oldConfig })
+	deleteVariant = "dev"
+	t.Cleanup(func() { deleteVariant = "" })
+
+	// Declining deletes nothing
+	require.NoError(t, deleteBySelector("ns", "team=payments", true, false, strings.NewReader("n\n")))
+	assert.Equal(t, []string{"true|true"}, deletes)
+	assert.Contains(t, body, `"name":"payments"`)
+	assert.Contains(t, body, `"name":"billing"`)
+	assert.Contains(t, body, `"variant":"dev"`)
+
+	require.NoError(t, deleteBySelector("ns", "team=payments", true, false, strings.NewReader("y\n")))
+	assert.Equal(t, []string{"true|true", "true|true", "|true"}, deletes)
+
+	assert.Error(t, deleteBySelector("resources", "team=payments", false, true, nil))
+}
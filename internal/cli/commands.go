@@ -55,6 +55,7 @@ func init() {
 	// Add commands
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newLoginCmd())
+	rootCmd.AddCommand(newLogoutCmd())
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -112,6 +113,7 @@ func preRunHandlePersistents(cmd *cobra.Command, args []string) {
 				os.Exit(1)
 			}
 		}
+		refreshCredentials(GetConfig())
 	}
 }
 
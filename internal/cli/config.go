@@ -30,6 +30,18 @@ type Config struct {
 	TokenExpiry string `yaml:"token_expiry"`
 	// CurrentCatalog is the currently selected catalog
 	CurrentCatalog string `yaml:"current_catalog"`
+	// CurrentVariant is the variant used when a command is not given one
+	CurrentVariant string `yaml:"current_variant,omitempty"`
+	// CurrentNamespace is the namespace used when a command is not given one
+	CurrentNamespace string `yaml:"current_namespace,omitempty"`
+	// CurrentContext is the name of the context in use. The settings above are those of
+	// the current context and are saved to it when the configuration is written.
+	CurrentContext string `yaml:"current_context,omitempty"`
+	// Contexts are named sets of settings that can be switched between
+	Contexts map[string]*Context `yaml:"contexts,omitempty"`
+
+	// credentials are the cached login credentials for the server
+	credentials *Credentials
 }
 
 var config *Config
@@ -78,6 +90,9 @@ func LoadConfig(file string) error {
 	// Morph the server URL before storing
 	c.ServerURL = MorphServer(c.ServerURL)
 
+	// A missing or unreadable credentials cache only means the user is not logged in
+	c.credentials, _ = LoadCredentials(c.ServerURL)
+
 	config = &c
 	return nil
 }
@@ -99,6 +114,7 @@ func (cfg *Config) WriteConfig(file string) error {
 		return fmt.Errorf("unable to create config directory: %w", err)
 	}
 
+	cfg.saveCurrentContext()
 	yamlStr, err := yaml.Marshal(cfg)
 	if err != nil {
 		return fmt.Errorf("unable to generate configuration: %w", err)
@@ -124,8 +140,8 @@ func (cfg *Config) ValidateConfig() error {
 	if !strings.Contains(cfg.ServerURL, ":") {
 		return errors.New("server:port must include port number")
 	}
-	if cfg.APIKey == "" {
-		return errors.New("API key is required")
+	if cfg.APIKey == "" && !cfg.credentials.IsValid() {
+		return errors.New("API key or login is required")
 	}
 	return nil
 }
@@ -158,8 +174,12 @@ func (cfg *Config) GetServerURL() string {
 	return MorphServer(cfg.ServerURL)
 }
 
-// GetAPIKey returns the API key from the configuration
+// GetAPIKey returns the token of the logged in user if there is one, and the API key
+// from the configuration otherwise
 func (cfg *Config) GetAPIKey() string {
+	if cfg.credentials.IsValid() {
+		return cfg.credentials.Token
+	}
 	return cfg.APIKey
 }
 
@@ -215,7 +235,11 @@ func (cfg *TangentConfig) GetTokenExpiry() time.Time {
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Manage CLI configuration",
-	Long:  `Manage CLI configuration settings like server connection and authentication.`,
+	Long: `Manage CLI configuration settings like server connection and authentication.
+
+Settings can be kept in named contexts, each with its own server, credentials and default
+catalog, variant and namespace. Use "tansive config set-context" to create one and
+"tansive config use-context" to switch between them.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if --server flag is provided
 		serverFlag, _ := cmd.Flags().GetString("server")
@@ -289,8 +313,10 @@ func setServerConfig(server string) error {
 		}
 	}
 
-	cfg := &Config{
-		Version: "0.1.0",
+	// Contexts are kept; the server becomes that of the current context
+	cfg, err := readConfigFile(configPath)
+	if err != nil {
+		return err
 	}
 
 	if !strings.Contains(server, ":") {
@@ -305,6 +331,8 @@ func setServerConfig(server string) error {
 	cfg.CurrentToken = ""
 	cfg.TokenExpiry = ""
 	cfg.CurrentCatalog = ""
+	cfg.CurrentVariant = ""
+	cfg.CurrentNamespace = ""
 
 	if err := cfg.WriteConfig(configPath); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
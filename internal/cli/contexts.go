@@ -0,0 +1,341 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultContextName is the name of the context that holds the settings configured before
+// any context was created.
+const DefaultContextName = "default"
+
+// Context is a named set of settings: the server to talk to, how to authenticate and the
+// catalog, variant and namespace used when a command is not given them.
+type Context struct {
+	ServerURL        string `yaml:"server_url"`
+	APIKey           string `yaml:"api_key,omitempty"`
+	CurrentToken     string `yaml:"current_token,omitempty"`
+	TokenExpiry      string `yaml:"token_expiry,omitempty"`
+	CurrentCatalog   string `yaml:"current_catalog,omitempty"`
+	CurrentVariant   string `yaml:"current_variant,omitempty"`
+	CurrentNamespace string `yaml:"current_namespace,omitempty"`
+}
+
+// saveCurrentContext copies the settings in use to the current context, if there is one.
+func (cfg *Config) saveCurrentContext() {
+	if cfg.CurrentContext == "" {
+		return
+	}
+	if cfg.Contexts == nil {
+		cfg.Contexts = make(map[string]*Context)
+	}
+	cfg.Contexts[cfg.CurrentContext] = &Context{
+		ServerURL:        cfg.ServerURL,
+		APIKey:           cfg.APIKey,
+		CurrentToken:     cfg.CurrentToken,
+		TokenExpiry:      cfg.TokenExpiry,
+		CurrentCatalog:   cfg.CurrentCatalog,
+		CurrentVariant:   cfg.CurrentVariant,
+		CurrentNamespace: cfg.CurrentNamespace,
+	}
+}
+
+// UseContext saves the settings in use to the current context and switches to the named
+// context.
+func (cfg *Config) UseContext(name string) error {
+	ctx, ok := cfg.Contexts[name]
+	if !ok {
+		return fmt.Errorf("context %q not found", name)
+	}
+	cfg.saveCurrentContext()
+	cfg.CurrentContext = name
+	cfg.ServerURL = ctx.ServerURL
+	cfg.APIKey = ctx.APIKey
+	cfg.CurrentToken = ctx.CurrentToken
+	cfg.TokenExpiry = ctx.TokenExpiry
+	cfg.CurrentCatalog = ctx.CurrentCatalog
+	cfg.CurrentVariant = ctx.CurrentVariant
+	cfg.CurrentNamespace = ctx.CurrentNamespace
+	cfg.credentials, _ = LoadCredentials(cfg.GetServerURL())
+	return nil
+}
+
+// contextQueryParams returns the query parameters selecting the catalog, variant and
+// namespace of a request. Values not given fall back to those of the current context;
+// anything still empty is left to the server, which takes it from the current view.
+func contextQueryParams(catalog, variant, namespace string) map[string]string {
+	if cfg := GetConfig(); cfg != nil {
+		if catalog == "" {
+			catalog = cfg.CurrentCatalog
+		}
+		if variant == "" {
+			variant = cfg.CurrentVariant
+		}
+		if namespace == "" {
+			namespace = cfg.CurrentNamespace
+		}
+	}
+	queryParams := make(map[string]string)
+	if catalog != "" {
+		queryParams["catalog"] = catalog
+	}
+	if variant != "" {
+		queryParams["variant"] = variant
+	}
+	if namespace != "" {
+		queryParams["namespace"] = namespace
+	}
+	return queryParams
+}
+
+// readConfigFile reads the configuration for editing. Unlike LoadConfig, a missing file
+// yields an empty configuration and the contents are not validated.
+func readConfigFile(file string) (*Config, error) {
+	yamlStr, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return &Config{Version: "0.1.0"}, nil
+		}
+		return nil, fmt.Errorf("unable to read config file: %w", err)
+	}
+	var c Config
+	if err := yaml.Unmarshal(yamlStr, &c); err != nil {
+		return nil, fmt.Errorf("unable to parse config file: %w", err)
+	}
+	return &c, nil
+}
+
+var (
+	// set-context command flags
+	contextServer    string
+	contextAPIKey    string
+	contextCatalog   string
+	contextVariant   string
+	contextNamespace string
+	contextCurrent   bool
+)
+
+// configSetContextCmd represents the config set-context command
+var configSetContextCmd = &cobra.Command{
+	Use:   "set-context [NAME] [flags]",
+	Short: "Create or update a context",
+	Long: `Create or update a context. A context holds a server URL, an API key and the catalog,
+variant and namespace used by commands that are not given them with -c, -v and -n.
+Only the settings given as flags are changed. Use --current to change the context in use.
+
+Examples:
+  # Create a context for a staging server
+  tansive config set-context staging --server staging.example.com:8678 --catalog my-catalog --variant dev
+
+  # Change the default namespace of the context in use
+  tansive config set-context --current --namespace team-a`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := readConfigFile(configFile)
+		if err != nil {
+			return err
+		}
+		var name string
+		switch {
+		case contextCurrent && len(args) == 0:
+			if cfg.CurrentContext == "" {
+				return errors.New("no context is in use")
+			}
+			name = cfg.CurrentContext
+		case !contextCurrent && len(args) == 1:
+			name = args[0]
+		default:
+			return errors.New("give either a context name or --current")
+		}
+
+		// Settings configured before any context was created are kept in the default context
+		if cfg.CurrentContext == "" && cfg.ServerURL != "" {
+			cfg.CurrentContext = DefaultContextName
+		}
+		cfg.saveCurrentContext()
+		if cfg.Contexts == nil {
+			cfg.Contexts = make(map[string]*Context)
+		}
+		ctx, ok := cfg.Contexts[name]
+		if !ok {
+			ctx = &Context{}
+			cfg.Contexts[name] = ctx
+		}
+		if cfg.CurrentContext == "" {
+			cfg.CurrentContext = name
+		}
+		if cmd.Flags().Changed("server") {
+			if !strings.Contains(contextServer, ":") {
+				return errors.New("server must include port number (e.g., example.com:8080)")
+			}
+			if ctx.ServerURL != MorphServer(contextServer) {
+				ctx.CurrentToken, ctx.TokenExpiry = "", ""
+			}
+			ctx.ServerURL = MorphServer(contextServer)
+		}
+		if cmd.Flags().Changed("api-key") {
+			ctx.APIKey = contextAPIKey
+		}
+		if cmd.Flags().Changed("catalog") {
+			if ctx.CurrentCatalog != contextCatalog {
+				// The token is for a view of the previous catalog
+				ctx.CurrentToken, ctx.TokenExpiry = "", ""
+			}
+			ctx.CurrentCatalog = contextCatalog
+		}
+		if cmd.Flags().Changed("variant") {
+			ctx.CurrentVariant = contextVariant
+		}
+		if cmd.Flags().Changed("namespace") {
+			ctx.CurrentNamespace = contextNamespace
+		}
+		if ctx.ServerURL == "" {
+			return fmt.Errorf("context %q has no server; set one with --server", name)
+		}
+
+		if name == cfg.CurrentContext {
+			// Reload the settings in use from the updated context
+			cfg.CurrentContext = ""
+			if err := cfg.UseContext(name); err != nil {
+				return err
+			}
+		}
+		if err := cfg.WriteConfig(configFile); err != nil {
+			return fmt.Errorf("failed to save config: %v", err)
+		}
+
+		if jsonOutput {
+			printJSON(map[string]int{"result": 1})
+		} else {
+			fmt.Printf("Context %s saved\n", name)
+		}
+		return nil
+	},
+}
+
+// configUseContextCmd represents the config use-context command
+var configUseContextCmd = &cobra.Command{
+	Use:   "use-context NAME",
+	Short: "Switch to a context",
+	Long: `Switch to a context. Subsequent commands use the server, credentials and defaults of
+the context until another context is selected.
+
+Examples:
+  tansive config use-context staging`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := readConfigFile(configFile)
+		if err != nil {
+			return err
+		}
+		if err := cfg.UseContext(args[0]); err != nil {
+			return err
+		}
+		if err := cfg.WriteConfig(configFile); err != nil {
+			return fmt.Errorf("failed to save config: %v", err)
+		}
+
+		if jsonOutput {
+			printJSON(map[string]int{"result": 1})
+		} else {
+			fmt.Printf("Switched to context %s\n", args[0])
+		}
+		return nil
+	},
+}
+
+// configGetContextsCmd represents the config get-contexts command
+var configGetContextsCmd = &cobra.Command{
+	Use:   "get-contexts",
+	Short: "List the contexts",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := readConfigFile(configFile)
+		if err != nil {
+			return err
+		}
+		cfg.saveCurrentContext()
+
+		names := make([]string, 0, len(cfg.Contexts))
+		for name := range cfg.Contexts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		if jsonOutput {
+			printJSON(map[string]any{
+				"current":  cfg.CurrentContext,
+				"contexts": cfg.Contexts,
+			})
+			return nil
+		}
+		if len(names) == 0 {
+			fmt.Println("No contexts configured. Create one with \"tansive config set-context\".")
+			return nil
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+		fmt.Fprintln(tw, "CURRENT\tNAME\tSERVER\tCATALOG\tVARIANT\tNAMESPACE")
+		for _, name := range names {
+			ctx := cfg.Contexts[name]
+			current := ""
+			if name == cfg.CurrentContext {
+				current = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", current, name, ctx.ServerURL, ctx.CurrentCatalog, ctx.CurrentVariant, ctx.CurrentNamespace)
+		}
+		return tw.Flush()
+	},
+}
+
+// configDeleteContextCmd represents the config delete-context command
+var configDeleteContextCmd = &cobra.Command{
+	Use:   "delete-context NAME",
+	Short: "Delete a context",
+	Long:  `Delete a context. The context in use cannot be deleted; switch to another context first.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := readConfigFile(configFile)
+		if err != nil {
+			return err
+		}
+		name := args[0]
+		if _, ok := cfg.Contexts[name]; !ok {
+			return fmt.Errorf("context %q not found", name)
+		}
+		if name == cfg.CurrentContext {
+			return fmt.Errorf("context %q is in use", name)
+		}
+		delete(cfg.Contexts, name)
+		if err := cfg.WriteConfig(configFile); err != nil {
+			return fmt.Errorf("failed to save config: %v", err)
+		}
+
+		if jsonOutput {
+			printJSON(map[string]int{"result": 1})
+		} else {
+			fmt.Printf("Context %s deleted\n", name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	configSetContextCmd.Flags().StringVar(&contextServer, "server", "", "Server URL and port (e.g., example.com:8080)")
+	configSetContextCmd.Flags().StringVar(&contextAPIKey, "api-key", "", "API key used to authenticate")
+	configSetContextCmd.Flags().StringVarP(&contextCatalog, "catalog", "c", "", "Default catalog")
+	configSetContextCmd.Flags().StringVarP(&contextVariant, "variant", "v", "", "Default variant")
+	configSetContextCmd.Flags().StringVarP(&contextNamespace, "namespace", "n", "", "Default namespace")
+	configSetContextCmd.Flags().BoolVar(&contextCurrent, "current", false, "Change the context in use")
+
+	configCmd.AddCommand(configSetContextCmd)
+	configCmd.AddCommand(configUseContextCmd)
+	configCmd.AddCommand(configGetContextsCmd)
+	configCmd.AddCommand(configDeleteContextCmd)
+}
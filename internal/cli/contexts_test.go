@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContexts(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	file := filepath.Join(t.TempDir(), "config.yaml")
+
+	// Settings configured before contexts existed
+	cfg := &Config{
+		Version:        "0.1.0",
+		ServerURL:      "https://local:8678",
+		CurrentToken:   "local-token",
+		CurrentCatalog: "local-catalog",
+	}
+	require.NoError(t, cfg.WriteConfig(file))
+
+	oldConfigFile := configFile
+	configFile = file
+	t.Cleanup(func() { configFile = oldConfigFile })
+
+	setContext := func(args []string, flags map[string]string) error {
+		cmd := configSetContextCmd
+		cmd.ResetFlags()
+		cmd.Flags().StringVar(&contextServer, "server", "", "")
+		cmd.Flags().StringVar(&contextAPIKey, "api-key", "", "")
+		cmd.Flags().StringVarP(&contextCatalog, "catalog", "c", "", "")
+		cmd.Flags().StringVarP(&contextVariant, "variant", "v", "", "")
+		cmd.Flags().StringVarP(&contextNamespace, "namespace", "n", "", "")
+		cmd.Flags().BoolVar(&contextCurrent, "current", false, "")
+		for k, v := range flags {
+			require.NoError(t, cmd.Flags().Set(k, v))
+		}
+		return cmd.RunE(cmd, args)
+	}
+
+	require.NoError(t, setContext([]string{"staging"}, map[string]string{
+		"server":  "staging:8678",
+		"catalog": "staging-catalog",
+		"variant": "dev",
+	}))
+
+	// The existing settings are kept in the default context, which stays in use
+	cfg, err := readConfigFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultContextName, cfg.CurrentContext)
+	assert.Equal(t, "https://local:8678", cfg.ServerURL)
+	require.Contains(t, cfg.Contexts, DefaultContextName)
+	assert.Equal(t, "local-token", cfg.Contexts[DefaultContextName].CurrentToken)
+	require.Contains(t, cfg.Contexts, "staging")
+	assert.Equal(t, "https://staging:8678", cfg.Contexts["staging"].ServerURL)
+
+	// Switching swaps the settings in use and remembers those of the previous context
+	cfg.CurrentToken = "refreshed-token"
+	require.NoError(t, cfg.UseContext("staging"))
+	assert.Equal(t, "staging", cfg.CurrentContext)
+	assert.Equal(t, "https://staging:8678", cfg.ServerURL)
+	assert.Equal(t, "staging-catalog", cfg.CurrentCatalog)
+	assert.Equal(t, "dev", cfg.CurrentVariant)
+	assert.Empty(t, cfg.CurrentToken)
+	assert.Equal(t, "refreshed-token", cfg.Contexts[DefaultContextName].CurrentToken)
+	assert.Error(t, cfg.UseContext("missing"))
+	require.NoError(t, cfg.WriteConfig(file))
+
+	// Updating the context in use updates the settings in use
+	require.NoError(t, setContext(nil, map[string]string{"current": "true", "namespace": "team-a"}))
+	cfg, err = readConfigFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", cfg.CurrentNamespace)
+	assert.Equal(t, "team-a", cfg.Contexts["staging"].CurrentNamespace)
+
+	// Commands fall back to the defaults of the context in use
+	oldConfig := config
+	config = cfg
+	t.Cleanup(func() { config = oldConfig })
+	assert.Equal(t, map[string]string{
+		"catalog":   "staging-catalog",
+		"variant":   "dev",
+		"namespace": "team-a",
+	}, contextQueryParams("", "", ""))
+	assert.Equal(t, map[string]string{
+		"catalog":   "staging-catalog",
+		"variant":   "prod",
+		"namespace": "team-a",
+	}, contextQueryParams("", "prod", ""))
+
+	assert.Error(t, setContext([]string{"broken"}, map[string]string{"catalog": "x"}))
+	assert.Error(t, setContext([]string{"staging"}, map[string]string{"current": "true"}))
+}
@@ -133,16 +133,7 @@ func handleCreateResource(resource ResourceMetadata, jsonData []byte) (map[strin
 	}
 
 	client := httpclient.NewClient(GetConfig())
-	queryParams := make(map[string]string)
-	if createCatalog != "" {
-		queryParams["catalog"] = createCatalog
-	}
-	if createVariant != "" {
-		queryParams["variant"] = createVariant
-	}
-	if createNamespace != "" {
-		queryParams["namespace"] = createNamespace
-	}
+	queryParams := contextQueryParams(createCatalog, createVariant, createNamespace)
 
 	_, location, err := client.CreateResource(resourceType, jsonData, queryParams)
 	if err != nil {
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultCredentialsFile is the name of the file that caches login credentials
+const DefaultCredentialsFile = "credentials.yaml"
+
+// Credentials are the identity token obtained by "tansive login" for a server
+type Credentials struct {
+	// Token is the identity token of the logged in user
+	Token string `yaml:"token"`
+	// IssuedAt is when the token was obtained
+	IssuedAt time.Time `yaml:"issued_at"`
+	// ExpiresAt is when the token expires
+	ExpiresAt time.Time `yaml:"expires_at"`
+}
+
+// credentialsFile is the on-disk format of the credentials cache, keyed by server URL
+type credentialsFile struct {
+	Servers map[string]*Credentials `yaml:"servers"`
+}
+
+// GetCredentialsPath returns the path of the credentials cache, ~/.tansive/credentials.yaml
+func GetCredentialsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".tansive", DefaultCredentialsFile), nil
+}
+
+// LoadCredentials returns the cached credentials for the server, or nil if there are none
+func LoadCredentials(serverURL string) (*Credentials, error) {
+	cf, err := readCredentialsFile()
+	if err != nil {
+		return nil, err
+	}
+	return cf.Servers[MorphServer(serverURL)], nil
+}
+
+// SaveCredentials caches the credentials for the server. Passing nil credentials removes
+// them. The cache is only readable by the current user.
+func SaveCredentials(serverURL string, creds *Credentials) error {
+	cf, err := readCredentialsFile()
+	if err != nil {
+		return err
+	}
+	if creds == nil {
+		delete(cf.Servers, MorphServer(serverURL))
+	} else {
+		cf.Servers[MorphServer(serverURL)] = creds
+	}
+
+	file, err := GetCredentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("unable to create credentials directory: %w", err)
+	}
+	yamlStr, err := yaml.Marshal(cf)
+	if err != nil {
+		return fmt.Errorf("unable to generate credentials: %w", err)
+	}
+	if err := os.WriteFile(file, yamlStr, 0600); err != nil {
+		return fmt.Errorf("unable to write credentials file: %w", err)
+	}
+	return nil
+}
+
+func readCredentialsFile() (*credentialsFile, error) {
+	file, err := GetCredentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	cf := &credentialsFile{}
+	yamlStr, err := os.ReadFile(file)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unable to read credentials file: %w", err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(yamlStr, cf); err != nil {
+			return nil, fmt.Errorf("unable to parse credentials file: %w", err)
+		}
+	}
+	if cf.Servers == nil {
+		cf.Servers = make(map[string]*Credentials)
+	}
+	return cf, nil
+}
+
+// IsValid reports whether the credentials hold a token that has not expired
+func (c *Credentials) IsValid() bool {
+	return c != nil && c.Token != "" && time.Now().Before(c.ExpiresAt)
+}
+
+// needsRefresh reports whether more than half of the token's lifetime has passed
+func (c *Credentials) needsRefresh() bool {
+	if !c.IsValid() {
+		return false
+	}
+	return time.Now().After(c.IssuedAt.Add(c.ExpiresAt.Sub(c.IssuedAt) / 2))
+}
+
+// credentialsConfig authenticates requests with the cached identity token alone, ignoring the
+// catalog token and API key in the configuration
+type credentialsConfig struct {
+	serverURL string
+	creds     *Credentials
+}
+
+func (cfg *credentialsConfig) GetServerURL() string {
+	return MorphServer(cfg.serverURL)
+}
+
+func (cfg *credentialsConfig) GetAPIKey() string {
+	return ""
+}
+
+func (cfg *credentialsConfig) GetSigningKey() (string, []byte) {
+	return "", nil
+}
+
+func (cfg *credentialsConfig) GetToken() string {
+	return cfg.creds.Token
+}
+
+func (cfg *credentialsConfig) GetTokenExpiry() time.Time {
+	return cfg.creds.ExpiresAt
+}
+
+// refreshCredentials exchanges the cached identity token for a new one once half of its
+// lifetime has passed. A failed refresh is not an error; the old token is used until it
+// expires, after which the user has to log in again.
+func refreshCredentials(cfg *Config) {
+	if cfg == nil || !cfg.credentials.needsRefresh() {
+		return
+	}
+
+	client := httpclient.NewClient(&credentialsConfig{serverURL: cfg.ServerURL, creds: cfg.credentials})
+	body, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "auth/token/refresh",
+	})
+	if err != nil {
+		return
+	}
+
+	var response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil || response.Token == "" {
+		return
+	}
+
+	creds := &Credentials{
+		Token:     response.Token,
+		IssuedAt:  time.Now(),
+		ExpiresAt: response.ExpiresAt,
+	}
+	if err := SaveCredentials(cfg.ServerURL, creds); err != nil {
+		return
+	}
+	cfg.credentials = creds
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	// Nothing is cached before login
+	creds, err := LoadCredentials("localhost:8678")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+
+	now := time.Now().Truncate(time.Second)
+	err = SaveCredentials("localhost:8678", &Credentials{
+		Token:     "token1",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	err = SaveCredentials("https://other:8678/", &Credentials{
+		Token:     "token2",
+		IssuedAt:  now,
+		ExpiresAt: now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	// Credentials are kept per server, with server URLs normalized
+	creds, err = LoadCredentials("https://localhost:8678")
+	require.NoError(t, err)
+	require.NotNil(t, creds)
+	assert.Equal(t, "token1", creds.Token)
+	assert.True(t, creds.ExpiresAt.Equal(now.Add(time.Hour)))
+	creds, err = LoadCredentials("other:8678")
+	require.NoError(t, err)
+	require.NotNil(t, creds)
+	assert.Equal(t, "token2", creds.Token)
+
+	// The cache is private to the user
+	path, err := GetCredentialsPath()
+	require.NoError(t, err)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Removing credentials leaves other servers alone
+	require.NoError(t, SaveCredentials("localhost:8678", nil))
+	creds, err = LoadCredentials("localhost:8678")
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+	creds, err = LoadCredentials("other:8678")
+	require.NoError(t, err)
+	assert.NotNil(t, creds)
+}
+
+func TestCredentialsRefresh(t *testing.T) {
+	now := time.Now()
+
+	var creds *Credentials
+	assert.False(t, creds.IsValid())
+	assert.False(t, creds.needsRefresh())
+
+	creds = &Credentials{Token: "t", IssuedAt: now.Add(-10 * time.Minute), ExpiresAt: now.Add(50 * time.Minute)}
+	assert.True(t, creds.IsValid())
+	assert.False(t, creds.needsRefresh())
+
+	creds = &Credentials{Token: "t", IssuedAt: now.Add(-40 * time.Minute), ExpiresAt: now.Add(20 * time.Minute)}
+	assert.True(t, creds.IsValid())
+	assert.True(t, creds.needsRefresh())
+
+	// Expired tokens cannot be refreshed
+	creds = &Credentials{Token: "t", IssuedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour)}
+	assert.False(t, creds.IsValid())
+	assert.False(t, creds.needsRefresh())
+
+	// Cached credentials take precedence over the configured API key
+	cfg := &Config{APIKey: "api-key"}
+	assert.Equal(t, "api-key", cfg.GetAPIKey())
+	cfg.credentials = &Credentials{Token: "login-token", IssuedAt: now, ExpiresAt: now.Add(time.Hour)}
+	assert.Equal(t, "login-token", cfg.GetAPIKey())
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -12,17 +13,26 @@ var (
 	deleteCatalog   string
 	deleteVariant   string
 	deleteNamespace string
+	deleteSelector  string
+	deleteCascade   bool
+	deleteYes       bool
 )
 
 var deleteCmd = &cobra.Command{
-	Use:   "delete RESOURCE_TYPE/RESOURCE_NAME [flags]",
-	Short: "Delete a resource by type and name",
+	Use:   "delete RESOURCE_TYPE/RESOURCE_NAME | RESOURCE_TYPE -l SELECTOR [flags]",
+	Short: "Delete a resource by type and name, or by label selector",
 	Long: `Delete a resource by type and name. The format is RESOURCE_TYPE/RESOURCE_NAME.
 Supported resource types include:
   - catalog/<catalog-name>
   - views/<view-name>
   - resources/<path/to/resource>
 
+With -l, every object of RESOURCE_TYPE whose labels match the selector is deleted. Variants,
+namespaces, skillsets and admission webhooks can be deleted this way. The objects are listed
+and deleted once you confirm, or right away with --yes. A variant or namespace that still
+holds skillsets or resources is only deleted with --cascade, which deletes its contents
+first; the listing then includes those contents.
+
 Examples:
   # Delete a catalog
   tansive delete catalog/my-catalog
@@ -34,7 +44,10 @@ Examples:
   tansive delete resources/path/to/resource
 
   # Delete a resource in a specific context
-  tansive delete resources/path/to/resource -c my-catalog -v my-variant -n my-namespace`,
+  tansive delete resources/path/to/resource -c my-catalog -v my-variant -n my-namespace
+
+  # Delete the namespaces of a team, with everything in them
+  tansive delete namespaces -l team=payments -v my-variant --cascade`,
 	Args: cobra.ExactArgs(1),
 	RunE: deleteResource,
 }
@@ -42,6 +55,13 @@ Examples:
 // deleteResource handles the deletion of a resource by type and name
 // It validates the input format and sends the delete request to the server
 func deleteResource(cmd *cobra.Command, args []string) error {
+	if deleteSelector != "" {
+		if strings.Contains(args[0], "/") {
+			return fmt.Errorf("give a resource type without a name with --selector")
+		}
+		return deleteBySelector(args[0], deleteSelector, deleteCascade, deleteYes, os.Stdin)
+	}
+
 	parts := strings.SplitN(args[0], "/", 2)
 	if len(parts) != 2 {
 		return fmt.Errorf("invalid resource format. Expected <resourceType>/<resourceName>")
@@ -57,16 +77,7 @@ func deleteResource(cmd *cobra.Command, args []string) error {
 
 	client := httpclient.NewClient(GetConfig())
 
-	queryParams := make(map[string]string)
-	if deleteCatalog != "" {
-		queryParams["catalog"] = deleteCatalog
-	}
-	if deleteVariant != "" {
-		queryParams["variant"] = deleteVariant
-	}
-	if deleteNamespace != "" {
-		queryParams["namespace"] = deleteNamespace
-	}
+	queryParams := contextQueryParams(deleteCatalog, deleteVariant, deleteNamespace)
 
 	objectType := ""
 	if urlResourceType == "resources" {
@@ -89,4 +100,7 @@ func init() {
 	deleteCmd.Flags().StringVarP(&deleteCatalog, "catalog", "c", "", "Catalog name")
 	deleteCmd.Flags().StringVarP(&deleteVariant, "variant", "v", "", "Variant name")
 	deleteCmd.Flags().StringVarP(&deleteNamespace, "namespace", "n", "", "Namespace name")
+	deleteCmd.Flags().StringVarP(&deleteSelector, "selector", "l", "", "Delete the objects whose labels match the selector, e.g. team=payments")
+	deleteCmd.Flags().BoolVar(&deleteCascade, "cascade", false, "Delete the skillsets and resources of variants and namespaces with them")
+	deleteCmd.Flags().BoolVarP(&deleteYes, "yes", "y", false, "Delete without asking for confirmation")
 }
@@ -3,7 +3,12 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 	"github.com/tansive/tansive-internal/internal/common/httpclient"
@@ -15,6 +20,7 @@ var (
 	describeCatalog   string
 	describeVariant   string
 	describeNamespace string
+	describeOutput    string
 )
 
 // describeCmd represents the describe command
@@ -24,9 +30,20 @@ var describeCmd = &cobra.Command{
 	Long: `Describe a resource by type and name. The format is RESOURCE_TYPE/RESOURCE_NAME.
 Supported resource types include:
   - catalogs/<catalog-name>
+  - variants/<variant-name>
+  - namespaces/<namespace-name>
   - views/<view-name>
+  - viewtemplates/<template-name>
+  - admissionwebhooks/<webhook-name>
+  - skillsets/<path/to/skillset>
   - resources/<path/to/resource>
 
+Output formats (-o):
+  table   The name, kind, description and labels, followed by the spec (default)
+  wide    As table, with all the metadata of the object
+  json    The object as returned by the server
+  yaml    The object as returned by the server, in YAML
+
 Examples:
   # Describe a catalog
   tansive describe catalogs/my-catalog
@@ -41,13 +58,16 @@ Examples:
   tansive describe resources/path/to/resource -c my-catalog -v my-variant -n my-namespace
 
   # Describe a resource in JSON format
-  tansive describe resources/path/to/resource -j`,
+  tansive describe resources/path/to/resource -j
+
+  # Describe a variant as YAML
+  tansive describe variants/dev -o yaml`,
 	Args: cobra.ExactArgs(1),
 	RunE: describeResource,
 }
 
 // describeResource handles the description of a resource by type and name
-// It retrieves the resource details and formats the output in YAML or JSON
+// It retrieves the resource details and formats the output according to -o
 func describeResource(cmd *cobra.Command, args []string) error {
 	// Split the argument into resource type and name
 	parts := strings.SplitN(args[0], "/", 2)
@@ -64,19 +84,14 @@ func describeResource(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	client := httpclient.NewClient(GetConfig())
-
-	queryParams := make(map[string]string)
-	if describeCatalog != "" {
-		queryParams["catalog"] = describeCatalog
-	}
-	if describeVariant != "" {
-		queryParams["variant"] = describeVariant
-	}
-	if describeNamespace != "" {
-		queryParams["namespace"] = describeNamespace
+	format, err := resolveOutputFormat(describeOutput, OutputTable)
+	if err != nil {
+		return err
 	}
 
+	client := httpclient.NewClient(GetConfig())
+	queryParams := contextQueryParams(describeCatalog, describeVariant, describeNamespace)
+
 	objectType := ""
 	if urlResourceType == "resources" {
 		objectType = "definition"
@@ -86,31 +101,73 @@ func describeResource(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	var responseData map[string]any
-	if err := json.Unmarshal(response, &responseData); err != nil {
-		return fmt.Errorf("failed to parse response: %v", err)
+	if format == OutputJSON || format == OutputYAML {
+		return printDocument(os.Stdout, format, response)
 	}
+	return printDescription(os.Stdout, format == OutputWide, response)
+}
 
-	if jsonOutput {
-		// Format as JSON with result and value
-		output := map[string]any{
-			"result": 1,
-			"value":  responseData,
+// printDescription prints an object for reading: the name, kind, description and labels,
+// or all the metadata when wide is set, followed by the rest of the object as YAML.
+func printDescription(w io.Writer, wide bool, response []byte) error {
+	var obj map[string]any
+	if err := json.Unmarshal(response, &obj); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+	metadata, _ := obj["metadata"].(map[string]any)
+	delete(obj, "metadata")
+	delete(obj, "apiVersion")
+	kind, _ := obj["kind"].(string)
+	delete(obj, "kind")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	name, _ := metadata["name"].(string)
+	if p, ok := metadata["path"].(string); ok {
+		name = path.Clean("/" + p + "/" + name)
+	}
+	fmt.Fprintf(tw, "Name:\t%s\n", name)
+	fmt.Fprintf(tw, "Kind:\t%s\n", kind)
+	description, _ := metadata["description"].(string)
+	fmt.Fprintf(tw, "Description:\t%s\n", description)
+	labels := make(map[string]string)
+	if l, ok := metadata["labels"].(map[string]any); ok {
+		for k, v := range l {
+			labels[k] = fmt.Sprint(v)
 		}
-
-		jsonBytes, err := json.MarshalIndent(output, "", "    ")
-		if err != nil {
-			return fmt.Errorf("failed to format JSON output: %v", err)
+	}
+	fmt.Fprintf(tw, "Labels:\t%s\n", formatLabels(labels))
+	if wide {
+		keys := make([]string, 0, len(metadata))
+		for k := range metadata {
+			switch k {
+			case "name", "path", "description", "labels":
+			default:
+				keys = append(keys, k)
+			}
 		}
-		fmt.Println(string(jsonBytes))
-	} else {
-		// Convert to YAML
-		yamlBytes, err := yaml.Marshal(responseData)
-		if err != nil {
-			return fmt.Errorf("failed to convert to YAML: %v", err)
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := metadata[k]
+			if _, ok := v.(string); !ok {
+				j, _ := json.Marshal(v)
+				v = string(j)
+			}
+			fmt.Fprintf(tw, "%s:\t%v\n", strings.ToUpper(k[:1])+k[1:], v)
 		}
-		fmt.Println(string(yamlBytes))
 	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(obj) == 0 {
+		return nil
+	}
+	yamlBytes, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("failed to convert to YAML: %v", err)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprint(w, string(yamlBytes))
 	return nil
 }
 
@@ -122,4 +179,5 @@ func init() {
 	describeCmd.Flags().StringVarP(&describeCatalog, "catalog", "c", "", "Catalog name")
 	describeCmd.Flags().StringVarP(&describeVariant, "variant", "v", "", "Variant name")
 	describeCmd.Flags().StringVarP(&describeNamespace, "namespace", "n", "", "Namespace name")
+	describeCmd.Flags().StringVarP(&describeOutput, "output", "o", "", "Output format: table|wide|json|yaml")
 }
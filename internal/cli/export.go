@@ -0,0 +1,520 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// Export command flags
+	exportOutput string
+	exportFormat string
+	exportToDir  string
+
+	// Import command flags
+	importOnConflict string
+	importDryRun     bool
+
+	// Filter flags shared by export and import
+	filterIncludeKinds      []string
+	filterExcludeKinds      []string
+	filterIncludeNamespaces []string
+	filterExcludeNamespaces []string
+)
+
+const (
+	exportFormatArchive = "tar.gz"
+	exportFormatYAML    = "yaml"
+)
+
+// exportFilter selects the objects of an export by kind and namespace. Namespace filters
+// apply to namespaces and to the skillsets and resources in them; with included
+// namespaces, skillsets and resources of the default namespace are left out. The catalog
+// is always kept, since an import starts with it.
+type exportFilter struct {
+	includeKinds      []string
+	excludeKinds      []string
+	includeNamespaces []string
+	excludeNamespaces []string
+}
+
+// newExportFilter returns the filter given by the flags, with resource types mapped to kinds.
+func newExportFilter() (*exportFilter, error) {
+	f := &exportFilter{
+		includeNamespaces: filterIncludeNamespaces,
+		excludeNamespaces: filterExcludeNamespaces,
+	}
+	for _, t := range filterIncludeKinds {
+		kind, err := kindForResourceType(t)
+		if err != nil {
+			return nil, err
+		}
+		f.includeKinds = append(f.includeKinds, kind)
+	}
+	for _, t := range filterExcludeKinds {
+		kind, err := kindForResourceType(t)
+		if err != nil {
+			return nil, err
+		}
+		f.excludeKinds = append(f.excludeKinds, kind)
+	}
+	return f, nil
+}
+
+// matches reports whether the filter keeps the object.
+func (f *exportFilter) matches(obj catalogmanager.ExportedObject) bool {
+	if obj.Kind == KindCatalog {
+		return true
+	}
+	if len(f.includeKinds) > 0 && !slices.Contains(f.includeKinds, obj.Kind) {
+		return false
+	}
+	if slices.Contains(f.excludeKinds, obj.Kind) {
+		return false
+	}
+
+	var namespace string
+	switch obj.Kind {
+	case KindNamespace:
+		namespace = obj.Name
+	case KindSkillset, KindResource:
+		namespace = obj.Namespace
+	default:
+		return true
+	}
+	if len(f.includeNamespaces) > 0 && !slices.Contains(f.includeNamespaces, namespace) {
+		return false
+	}
+	return namespace == "" || !slices.Contains(f.excludeNamespaces, namespace)
+}
+
+// apply removes the objects the filter does not keep from the manifest.
+func (f *exportFilter) apply(manifest *catalogmanager.ExportManifest) {
+	manifest.Objects = slices.DeleteFunc(manifest.Objects, func(obj catalogmanager.ExportedObject) bool {
+		return !f.matches(obj)
+	})
+}
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [CATALOG_NAME] [flags]",
+	Short: "Export a catalog to an archive, a YAML file or a directory",
+	Long: `Export every object of a catalog. By default the catalog is written to CATALOG_NAME.tar.gz,
+an archive that can be imported with "tansive import". With --format yaml, the objects are
+written as a multi-document YAML file that can be applied with "tansive apply".
+
+With --to-dir, each object is written to a YAML file of its own under the directory, next to
+a manifest.json listing them, so that a catalog can be kept in git and reviewed object by
+object. Files of objects that no longer exist are removed. The directory can be imported
+with "tansive import".
+
+Objects can be included or excluded by type and by namespace. The catalog itself is always
+exported.
+
+Examples:
+  # Export the current catalog to an archive
+  tansive export
+
+  # Export a catalog as YAML to standard output
+  tansive export my-catalog --format yaml -o -
+
+  # Keep the resources of a namespace in a git-friendly directory
+  tansive export my-catalog --to-dir ./catalog --include-kind resources --include-namespace payments`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: exportCatalog,
+}
+
+// exportCatalog downloads the export archive of a catalog, filters it and writes it out
+func exportCatalog(cmd *cobra.Command, args []string) error {
+	catalog := GetConfig().CurrentCatalog
+	if len(args) == 1 {
+		catalog = args[0]
+	}
+	if catalog == "" {
+		return fmt.Errorf("give a catalog name or set a catalog first with `tansive set-catalog <catalog-name>`")
+	}
+	if exportFormat != exportFormatArchive && exportFormat != exportFormatYAML {
+		return fmt.Errorf("invalid format %q, expected %s or %s", exportFormat, exportFormatArchive, exportFormatYAML)
+	}
+	filter, err := newExportFilter()
+	if err != nil {
+		return err
+	}
+
+	progress := newProgress(os.Stderr, "Exporting "+catalog)
+	client := httpclient.NewClient(GetConfig())
+	reader, err := client.StreamRequest(httpclient.RequestOptions{
+		Method:      http.MethodGet,
+		Path:        "catalogs/" + catalog + "/export",
+		QueryParams: map[string]string{"format": exportFormatArchive},
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	var archive bytes.Buffer
+	if _, err := io.Copy(io.MultiWriter(&archive, progress), reader); err != nil {
+		return fmt.Errorf("failed to download export: %v", err)
+	}
+	progress.done()
+
+	manifest, aerr := catalogmanager.ReadExportArchive(&archive)
+	if aerr != nil {
+		return fmt.Errorf("failed to read export: %v", aerr)
+	}
+	filter.apply(manifest)
+
+	output := exportOutput
+	switch {
+	case exportToDir != "":
+		if err := writeManifestDir(exportToDir, manifest); err != nil {
+			return err
+		}
+		output = exportToDir
+	case exportFormat == exportFormatYAML:
+		if output == "" {
+			output = catalog + ".yaml"
+		}
+		err = writeExportFile(output, manifest.WriteYAML)
+	default:
+		if output == "" {
+			output = catalog + ".tar.gz"
+		}
+		err = writeExportFile(output, manifest.WriteArchive)
+	}
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		printJSON(map[string]any{"result": 1, "catalog": catalog, "objects": len(manifest.Objects), "output": output})
+	} else if output != "-" {
+		okLabel.Fprintf(os.Stdout, "[OK] ")
+		fmt.Fprintf(os.Stdout, "Exported %s to %s\n", exportSummary(manifest), output)
+	}
+	return nil
+}
+
+// writeExportFile writes an export with write to file, or to standard output if file is "-".
+func writeExportFile(file string, write func(io.Writer) error) error {
+	if file == "-" {
+		return write(os.Stdout)
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %v", file, err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %v", file, err)
+	}
+	return f.Close()
+}
+
+// manifestDirFile returns the file of an object in a manifest directory: its file in the
+// export archive, as YAML.
+func manifestDirFile(obj catalogmanager.ExportedObject) string {
+	return strings.TrimSuffix(obj.File, filepath.Ext(obj.File)) + ".yaml"
+}
+
+// writeManifestDir writes each object of the manifest to a YAML file of its own under dir,
+// and the manifest, listing the files, to manifest.json. Files listed by the manifest
+// previously in dir that are no longer part of the export are removed.
+func writeManifestDir(dir string, manifest *catalogmanager.ExportManifest) error {
+	var previous catalogmanager.ExportManifest
+	if data, err := os.ReadFile(filepath.Join(dir, catalogmanager.ExportManifestFile)); err == nil {
+		_ = json.Unmarshal(data, &previous)
+	}
+
+	dirManifest := *manifest
+	dirManifest.Objects = make([]catalogmanager.ExportedObject, 0, len(manifest.Objects))
+	written := make(map[string]bool, len(manifest.Objects))
+	progress := newProgress(os.Stderr, "Writing objects")
+	for i, obj := range manifest.Objects {
+		y, err := yaml.JSONToYAML(obj.JSON)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s %s to YAML: %v", obj.Kind, obj.Name, err)
+		}
+		obj.File = manifestDirFile(obj)
+		file := filepath.Join(dir, filepath.FromSlash(obj.File))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %v", obj.File, err)
+		}
+		if err := os.WriteFile(file, y, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", obj.File, err)
+		}
+		written[obj.File] = true
+		dirManifest.Objects = append(dirManifest.Objects, obj)
+		progress.count(i+1, len(manifest.Objects))
+	}
+	progress.done()
+
+	for _, obj := range previous.Objects {
+		if obj.File == "" || written[obj.File] || !filepath.IsLocal(filepath.FromSlash(obj.File)) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, filepath.FromSlash(obj.File))); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove %s: %v", obj.File, err)
+		}
+	}
+
+	manifestJSON, err := json.MarshalIndent(&dirManifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, catalogmanager.ExportManifestFile), append(manifestJSON, '\n'), 0644)
+}
+
+// readManifestDir reads a directory written by writeManifestDir.
+func readManifestDir(dir string) (*catalogmanager.ExportManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, catalogmanager.ExportManifestFile))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not an exported catalog: %v", dir, err)
+	}
+	manifest := &catalogmanager.ExportManifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", catalogmanager.ExportManifestFile, err)
+	}
+	for i, obj := range manifest.Objects {
+		if !filepath.IsLocal(filepath.FromSlash(obj.File)) {
+			return nil, fmt.Errorf("invalid file %s in %s", obj.File, catalogmanager.ExportManifestFile)
+		}
+		y, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(obj.File)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", obj.File, err)
+		}
+		j, err := yaml.YAMLToJSON(y)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", obj.File, err)
+		}
+		manifest.Objects[i].JSON = j
+	}
+	return manifest, nil
+}
+
+// exportSummary describes the objects of a manifest, e.g. "12 objects of catalog my-catalog".
+func exportSummary(manifest *catalogmanager.ExportManifest) string {
+	return fmt.Sprintf("%d objects of catalog %s", len(manifest.Objects), manifest.Catalog)
+}
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import FILE|DIRECTORY [flags]",
+	Short: "Import a catalog from an archive or a directory",
+	Long: `Import a catalog exported with "tansive export", from an archive or from a directory written
+with --to-dir. Objects that do not exist are created and identical objects are left
+unchanged. Objects that exist with different content fail the import, unless --on-conflict
+is skip or overwrite. Any conflict aborts the import before changes are made.
+
+Objects can be included or excluded by type and by namespace, as for export.
+
+Examples:
+  # Import an archive, reporting what would change
+  tansive import my-catalog.tar.gz --dry-run
+
+  # Import a directory, overwriting objects that differ
+  tansive import ./catalog --on-conflict overwrite`,
+	Args: cobra.ExactArgs(1),
+	RunE: importCatalog,
+}
+
+// importReport is the outcome of an import, as reported by /catalogs/import.
+type importReport struct {
+	Catalog string `json:"catalog"`
+	DryRun  bool   `json:"dryRun"`
+	Results []struct {
+		Kind      string `json:"kind"`
+		Name      string `json:"name"`
+		Variant   string `json:"variant,omitempty"`
+		Namespace string `json:"namespace,omitempty"`
+		Path      string `json:"path,omitempty"`
+		Action    string `json:"action"`
+		Error     string `json:"error,omitempty"`
+	} `json:"results"`
+}
+
+// importCatalog reads an exported catalog, filters it and uploads it to /catalogs/import
+func importCatalog(cmd *cobra.Command, args []string) error {
+	filter, err := newExportFilter()
+	if err != nil {
+		return err
+	}
+
+	var manifest *catalogmanager.ExportManifest
+	if info, err := os.Stat(args[0]); err != nil {
+		return fmt.Errorf("failed to read %s: %v", args[0], err)
+	} else if info.IsDir() {
+		if manifest, err = readManifestDir(args[0]); err != nil {
+			return err
+		}
+	} else {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %v", args[0], err)
+		}
+		defer f.Close()
+		var aerr error
+		if manifest, aerr = catalogmanager.ReadExportArchive(f); aerr != nil {
+			return fmt.Errorf("failed to read %s: %v; YAML exports are applied with \"tansive apply\"", args[0], aerr)
+		}
+	}
+	filter.apply(manifest)
+
+	var archive bytes.Buffer
+	if err := manifest.WriteArchive(&archive); err != nil {
+		return fmt.Errorf("failed to build archive: %v", err)
+	}
+
+	queryParams := map[string]string{}
+	if importOnConflict != "" {
+		queryParams["on_conflict"] = importOnConflict
+	}
+	if importDryRun {
+		queryParams["dry_run"] = "true"
+	}
+	if !jsonOutput {
+		fmt.Fprintf(os.Stderr, "Importing %s (%s)\n", exportSummary(manifest), formatBytes(int64(archive.Len())))
+	}
+	client := httpclient.NewClient(GetConfig())
+	body, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method:      http.MethodPost,
+		Path:        "catalogs/import",
+		QueryParams: queryParams,
+		Body:        archive.Bytes(),
+	})
+	var report importReport
+	var importErr error
+	if err != nil {
+		// A failed import still reports the plan, with the conflicts that stopped it
+		var httpErr *httpclient.HTTPError
+		if !errors.As(err, &httpErr) || json.Unmarshal([]byte(httpErr.Message), &report) != nil || len(report.Results) == 0 {
+			return err
+		}
+		importErr = ErrAlreadyHandled
+	} else if err := json.Unmarshal(body, &report); err != nil {
+		return fmt.Errorf("unable to parse import response: %v", err)
+	}
+
+	if jsonOutput {
+		printJSON(report)
+		return importErr
+	}
+	counts := make(map[string]int)
+	for _, r := range report.Results {
+		counts[r.Action]++
+		name := r.Name
+		if r.Kind == KindResource || r.Kind == KindSkillset {
+			name = strings.TrimSuffix(r.Path, "/") + "/" + r.Name
+		}
+		scope := strings.Trim(r.Variant+"/"+r.Namespace, "/")
+		if scope != "" {
+			name += " (" + scope + ")"
+		}
+		if r.Error != "" || r.Action == catalogmanager.ImportActionConflict {
+			errorLabel.Fprintf(os.Stderr, "[ERROR] ")
+			fmt.Fprintf(os.Stderr, "%s %s: %s %s\n", r.Action, r.Kind, name, r.Error)
+			continue
+		}
+		okLabel.Fprintf(os.Stdout, "[OK] ")
+		fmt.Fprintf(os.Stdout, "%s %s: %s\n", r.Action, r.Kind, name)
+	}
+	suffix := ""
+	if report.DryRun {
+		suffix = " (dry run)"
+	}
+	fmt.Fprintf(os.Stdout, "%d created, %d updated, %d unchanged, %d skipped, %d conflicts%s\n",
+		counts[catalogmanager.ImportActionCreate], counts[catalogmanager.ImportActionUpdate],
+		counts[catalogmanager.ImportActionUnchanged], counts[catalogmanager.ImportActionSkip],
+		counts[catalogmanager.ImportActionConflict], suffix)
+	return importErr
+}
+
+// progress reports the progress of a long running transfer on a single line, as a byte
+// count when written to or as a count of items. Nothing is reported with -j.
+type progress struct {
+	w     io.Writer
+	label string
+	bytes int64
+	shown bool
+}
+
+func newProgress(w io.Writer, label string) *progress {
+	return &progress{w: w, label: label}
+}
+
+// Write counts the bytes transferred.
+func (p *progress) Write(b []byte) (int, error) {
+	p.bytes += int64(len(b))
+	p.show(formatBytes(p.bytes))
+	return len(b), nil
+}
+
+// count reports that n of total items are done.
+func (p *progress) count(n, total int) {
+	p.show(fmt.Sprintf("%d/%d", n, total))
+}
+
+func (p *progress) show(status string) {
+	if jsonOutput {
+		return
+	}
+	fmt.Fprintf(p.w, "\r%s: %s", p.label, status)
+	p.shown = true
+}
+
+// done ends the progress line.
+func (p *progress) done() {
+	if p.shown {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// formatBytes formats a byte count for display, e.g. "1.5 MiB".
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// addExportFilterFlags adds the flags selecting objects by kind and namespace to cmd.
+func addExportFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&filterIncludeKinds, "include-kind", nil, "Only include objects of these types, e.g. resources,skillsets")
+	cmd.Flags().StringSliceVar(&filterExcludeKinds, "exclude-kind", nil, "Leave out objects of these types")
+	cmd.Flags().StringSliceVar(&filterIncludeNamespaces, "include-namespace", nil, "Only include these namespaces and the objects in them")
+	cmd.Flags().StringSliceVar(&filterExcludeNamespaces, "exclude-namespace", nil, "Leave out these namespaces and the objects in them")
+}
+
+// init initializes the export and import commands with their flags and adds them to the root command
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "", "File to write, or - for standard output (default CATALOG_NAME.tar.gz or CATALOG_NAME.yaml)")
+	exportCmd.Flags().StringVar(&exportFormat, "format", exportFormatArchive, "Format of the export: tar.gz|yaml")
+	exportCmd.Flags().StringVar(&exportToDir, "to-dir", "", "Write one YAML file per object under this directory")
+	addExportFilterFlags(exportCmd)
+
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", "", "How to treat objects that exist with different content: fail|skip|overwrite (default fail)")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Report what would change without changing anything")
+	addExportFilterFlags(importCmd)
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
+)
+
+func testExportManifest() *catalogmanager.ExportManifest {
+	return &catalogmanager.ExportManifest{
+		Catalog: "my-catalog",
+		Objects: []catalogmanager.ExportedObject{
+			{Kind: KindCatalog, Name: "my-catalog", File: "catalog.json", JSON: []byte(`{"kind": "Catalog", "metadata": {"name": "my-catalog"}}`)},
+			{Kind: KindVariant, Name: "dev", File: "variants/dev/variant.json", JSON: []byte(`{"kind": "Variant", "metadata": {"name": "dev"}}`)},
+			{Kind: KindNamespace, Name: "team-a", Variant: "dev", File: "variants/dev/namespaces/team-a.json", JSON: []byte(`{"kind": "Namespace", "metadata": {"name": "team-a"}}`)},
+			{Kind: KindResource, Name: "quota", Variant: "dev", Path: "/config", File: "variants/dev/resources/config/quota.json", JSON: []byte(`{"kind": "Resource", "metadata": {"name": "quota"}}`)},
+			{Kind: KindResource, Name: "quota", Variant: "dev", Namespace: "team-a", Path: "/config", File: "variants/dev/namespaces/team-a/resources/config/quota.json", JSON: []byte(`{"kind": "Resource", "metadata": {"name": "quota", "namespace": "team-a"}}`)},
+		},
+	}
+}
+
+func TestExportFilter(t *testing.T) {
+	files := func(f *exportFilter) []string {
+		manifest := testExportManifest()
+		f.apply(manifest)
+		var files []string
+		for _, obj := range manifest.Objects {
+			files = append(files, obj.File)
+		}
+		return files
+	}
+
+	assert.Equal(t, []string{"catalog.json", "variants/dev/resources/config/quota.json", "variants/dev/namespaces/team-a/resources/config/quota.json"},
+		files(&exportFilter{includeKinds: []string{KindResource}}))
+	assert.Equal(t, []string{"catalog.json", "variants/dev/variant.json", "variants/dev/resources/config/quota.json"},
+		files(&exportFilter{excludeNamespaces: []string{"team-a"}}))
+	assert.Equal(t, []string{"catalog.json", "variants/dev/variant.json", "variants/dev/namespaces/team-a.json", "variants/dev/namespaces/team-a/resources/config/quota.json"},
+		files(&exportFilter{includeNamespaces: []string{"team-a"}}))
+	assert.Equal(t, []string{"catalog.json", "variants/dev/namespaces/team-a.json"},
+		files(&exportFilter{includeNamespaces: []string{"team-a"}, excludeKinds: []string{KindResource, KindVariant}}))
+}
+
+func TestManifestDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, writeManifestDir(dir, testExportManifest()))
+
+	quota, err := os.ReadFile(filepath.Join(dir, "variants/dev/resources/config/quota.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "kind: Resource\nmetadata:\n  name: quota\n", string(quota))
+
+	manifest, err := readManifestDir(dir)
+	require.NoError(t, err)
+	require.Len(t, manifest.Objects, 5)
+	assert.Equal(t, "variants/dev/namespaces/team-a.yaml", manifest.Objects[2].File)
+	assert.JSONEq(t, `{"kind": "Namespace", "metadata": {"name": "team-a"}}`, string(manifest.Objects[2].JSON))
+
+	// Files of objects left out of a later export are removed
+	smaller := testExportManifest()
+	(&exportFilter{excludeNamespaces: []string{"team-a"}}).apply(smaller)
+	require.NoError(t, writeManifestDir(dir, smaller))
+	_, err = os.Stat(filepath.Join(dir, "variants/dev/namespaces/team-a.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = os.Stat(filepath.Join(dir, "variants/dev/resources/config/quota.yaml"))
+	assert.NoError(t, err)
+}
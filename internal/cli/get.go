@@ -1,13 +1,13 @@
 package cli
 
 import (
-	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tansive/tansive-internal/internal/common/httpclient"
-	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -15,97 +15,111 @@ var (
 	getCatalog   string
 	getVariant   string
 	getNamespace string
+	getOutput    string
+	getSelector  string
+	getLimit     int
+	getCursor    string
 )
 
 // getCmd represents the get command
 var getCmd = &cobra.Command{
-	Use:   "get RESOURCE_PATH [flags]",
-	Short: "Get a resource value by path",
-	Long: `Get a resource value by path. The format is RESOURCE_PATH.
-This command only works with resources and returns their current values.
+	Use:   "get RESOURCE_TYPE[/NAME] [NAME] [flags]",
+	Short: "Get objects of a kind, or a single object by name",
+	Long: `Get objects of a kind, or a single object by name. Without a name, the objects of the kind
+are listed. The catalog, variant and namespace are taken from the flags, or from the current
+catalog when not given.
+
+Supported resource types include catalogs, variants, namespaces, views, viewtemplates,
+admissionwebhooks, resources, skillsets and sessions.
+
+For a single resource, the value of the resource is printed unless a table format is
+requested; use describe to print the definition.
+
+Output formats (-o):
+  table   Name and description of each object (default)
+  wide    Adds the labels and status of each object
+  json    The objects as returned by the server
+  yaml    The objects as returned by the server, in YAML
 
 Examples:
+  # List the variants of the current catalog
+  tansive get variants
+
+  # List resources with their labels
+  tansive get resources -c my-catalog -v my-variant -o wide
+
+  # Get a view as YAML
+  tansive get views/my-view -o yaml
+
   # Get a resource value
   tansive get resources/path/to/resource
 
-  # Get a resource value in a specific catalog
-  tansive get resources/path/to/resource -c my-catalog
-
   # Get a resource value in a specific context
   tansive get resources/path/to/resource -c my-catalog -v my-variant -n my-namespace
 
   # Get a resource value in JSON format
   tansive get resources/path/to/resource -j`,
-	Args: cobra.ExactArgs(1),
-	RunE: getResourceValue,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: getObjects,
 }
 
-// getResourceValue handles retrieving a resource value by path
-// It validates the input and formats the output in YAML or JSON
-func getResourceValue(cmd *cobra.Command, args []string) error {
-	// Split the argument into resource type and name
-	parts := strings.SplitN(args[0], "/", 2)
-	if len(parts) != 2 {
-		return fmt.Errorf("invalid resource format. Expected <resourceType>/<resourceName>")
+// getObjects lists the objects of a kind, or gets a single object when a name is given
+func getObjects(cmd *cobra.Command, args []string) error {
+	resourceType, name, _ := strings.Cut(args[0], "/")
+	if len(args) == 2 {
+		if name != "" {
+			return fmt.Errorf("invalid arguments. Expected <resourceType>/<name> or <resourceType> <name>")
+		}
+		name = args[1]
 	}
 
-	resourceType := parts[0]
-	resourcePath := parts[1]
-
-	// Map the resource type to its URL format
 	urlResourceType, err := MapResourceTypeToURL(resourceType)
 	if err != nil {
 		return err
 	}
 
 	client := httpclient.NewClient(GetConfig())
+	queryParams := contextQueryParams(getCatalog, getVariant, getNamespace)
 
-	queryParams := make(map[string]string)
-	if getCatalog != "" {
-		queryParams["catalog"] = getCatalog
-	}
-	if getVariant != "" {
-		queryParams["variant"] = getVariant
-	}
-	if getNamespace != "" {
-		queryParams["namespace"] = getNamespace
+	if name == "" {
+		format, err := resolveOutputFormat(getOutput, OutputTable)
+		if err != nil {
+			return err
+		}
+		if getSelector != "" {
+			queryParams["labelSelector"] = getSelector
+		}
+		if getLimit > 0 {
+			queryParams["limit"] = strconv.Itoa(getLimit)
+		}
+		if getCursor != "" {
+			queryParams["cursor"] = getCursor
+		}
+		response, err := client.ListResources(urlResourceType, queryParams)
+		if err != nil {
+			return err
+		}
+		return printList(os.Stdout, format, urlResourceType, response)
 	}
 
-	if urlResourceType != "resources" {
-		return fmt.Errorf("invalid resource type. Expected resources")
+	// Resource values are printed as documents, so they default to YAML
+	def := OutputTable
+	if urlResourceType == "resources" {
+		def = OutputYAML
 	}
-
-	response, err := client.GetResource(urlResourceType, resourcePath, queryParams, "")
+	format, err := resolveOutputFormat(getOutput, def)
 	if err != nil {
 		return err
 	}
-
-	var responseData map[string]any
-	if err := json.Unmarshal(response, &responseData); err != nil {
-		return fmt.Errorf("failed to parse response: %v", err)
+	objectType := ""
+	if urlResourceType == "resources" && (format == OutputTable || format == OutputWide) {
+		objectType = "definition"
 	}
-
-	if jsonOutput {
-		// Format as JSON with result and value
-		output := map[string]any{
-			"result": 1,
-			"value":  responseData,
-		}
-
-		jsonBytes, err := json.MarshalIndent(output, "", "    ")
-		if err != nil {
-			return fmt.Errorf("failed to format JSON output: %v", err)
-		}
-		fmt.Println(string(jsonBytes))
-	} else {
-		// Convert to YAML
-		yamlBytes, err := yaml.Marshal(responseData)
-		if err != nil {
-			return fmt.Errorf("failed to convert to YAML: %v", err)
-		}
-		fmt.Println(string(yamlBytes))
+	response, err := client.GetResource(urlResourceType, name, queryParams, objectType)
+	if err != nil {
+		return err
 	}
-	return nil
+	return printObject(os.Stdout, format, urlResourceType, response)
 }
 
 // init initializes the get command with its flags and adds it to the root command
@@ -116,4 +130,8 @@ func init() {
 	getCmd.Flags().StringVarP(&getCatalog, "catalog", "c", "", "Catalog name")
 	getCmd.Flags().StringVarP(&getVariant, "variant", "v", "", "Variant name")
 	getCmd.Flags().StringVarP(&getNamespace, "namespace", "n", "", "Namespace name")
+	getCmd.Flags().StringVarP(&getOutput, "output", "o", "", "Output format: table|wide|json|yaml")
+	getCmd.Flags().StringVarP(&getSelector, "selector", "l", "", "Label selector to filter the listed objects, e.g. env=prod")
+	getCmd.Flags().IntVar(&getLimit, "limit", 0, "Maximum number of objects to list")
+	getCmd.Flags().StringVar(&getCursor, "cursor", "", "Cursor returned by a previous call to fetch the next page")
 }
@@ -3,6 +3,8 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"path"
+	"strconv"
 
 	"github.com/spf13/cobra"
 	"github.com/tansive/tansive-internal/internal/common/httpclient"
@@ -15,6 +17,8 @@ var (
 	listCatalog   string
 	listVariant   string
 	listNamespace string
+	listLimit     int
+	listCursor    string
 )
 
 // listCmd represents the list command
@@ -53,7 +57,11 @@ Examples:
   tansive list resources -j
 
   # List catalogs in JSON format
-  tansive list catalogs -j`,
+  tansive list catalogs -j
+
+  # List the first 10 skillsets, then fetch the next page
+  tansive list skillsets -c my-catalog -v my-variant --limit 10
+  tansive list skillsets -c my-catalog -v my-variant --limit 10 --cursor <cursor>`,
 	Args: cobra.ExactArgs(1),
 	RunE: listResources,
 }
@@ -71,15 +79,12 @@ func listResources(cmd *cobra.Command, args []string) error {
 
 	client := httpclient.NewClient(GetConfig())
 
-	queryParams := make(map[string]string)
-	if listCatalog != "" {
-		queryParams["catalog"] = listCatalog
-	}
-	if listVariant != "" {
-		queryParams["variant"] = listVariant
+	queryParams := contextQueryParams(listCatalog, listVariant, listNamespace)
+	if listLimit > 0 {
+		queryParams["limit"] = strconv.Itoa(listLimit)
 	}
-	if listNamespace != "" {
-		queryParams["namespace"] = listNamespace
+	if listCursor != "" {
+		queryParams["cursor"] = listCursor
 	}
 
 	response, err := client.ListResources(urlResourceType, queryParams)
@@ -99,97 +104,60 @@ func init() {
 	listCmd.Flags().StringVarP(&listCatalog, "catalog", "c", "", "Catalog name")
 	listCmd.Flags().StringVarP(&listVariant, "variant", "v", "", "Variant name")
 	listCmd.Flags().StringVarP(&listNamespace, "namespace", "n", "", "Namespace name")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum number of items to return")
+	listCmd.Flags().StringVar(&listCursor, "cursor", "", "Cursor returned by a previous list call to fetch the next page")
 }
 
 // printResourceList formats and prints resources in either JSON or human-readable format
-// This unified function handles different response formats for various resource types
+// All list endpoints return an envelope keyed by the resource type, with an optional
+// nextCursor when more results are available.
 func printResourceList(resourceType string, response []byte) error {
+	var responseData map[string]any
+	if err := json.Unmarshal(response, &responseData); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+
 	if jsonOutput {
-		// For JSON output, keep the existing format
-		if resourceType == "catalogs" {
-			// Catalogs are returned as a simple array, so we need to wrap it
-			var catalogNames []string
-			if err := json.Unmarshal(response, &catalogNames); err != nil {
-				return fmt.Errorf("failed to parse catalog names: %v", err)
-			}
-
-			output := map[string]any{
-				"result": 1,
-				"value":  catalogNames,
-			}
-
-			jsonBytes, err := json.MarshalIndent(output, "", "    ")
-			if err != nil {
-				return fmt.Errorf("failed to format JSON output: %v", err)
-			}
-			fmt.Println(string(jsonBytes))
-		} else {
-			// For other resource types, keep the existing format
-			var responseData map[string]any
-			if err := json.Unmarshal(response, &responseData); err != nil {
-				return fmt.Errorf("failed to parse response")
-			}
-
-			output := map[string]any{
-				"result": 1,
-				"value":  responseData,
-			}
-
-			jsonBytes, err := json.MarshalIndent(output, "", "    ")
-			if err != nil {
-				return fmt.Errorf("failed to format JSON output: %v", err)
-			}
-			fmt.Println(string(jsonBytes))
+		output := map[string]any{
+			"result": 1,
+			"value":  responseData,
 		}
-	} else {
-		// For non-JSON output, print in a more readable format
-		fmt.Printf("%s:\n", cases.Title(language.English).String(resourceType))
-
-		// Handle different response formats based on resource type
-		switch resourceType {
-		case "catalogs":
-			// Catalogs are returned as a simple array of strings
-			var catalogNames []string
-			if err := json.Unmarshal(response, &catalogNames); err != nil {
-				return fmt.Errorf("failed to parse catalog names: %v", err)
-			}
-			for _, name := range catalogNames {
-				fmt.Printf("- %s\n", name)
-			}
-		case "views":
-			// Views are returned as an object with a "views" array
-			var responseData map[string]any
-			if err := json.Unmarshal(response, &responseData); err != nil {
-				return fmt.Errorf("failed to parse response: %v", err)
-			}
-			if views, ok := responseData["views"].([]any); ok {
-				for _, item := range views {
-					if viewMap, ok := item.(map[string]any); ok {
-						if name, ok := viewMap["name"].(string); ok {
-							fmt.Printf("- %s\n", name)
-						}
-					}
-				}
-			}
-		default:
-			// For other resource types, try to extract names from the response
-			var responseData map[string]any
-			if err := json.Unmarshal(response, &responseData); err != nil {
-				return fmt.Errorf("failed to parse response: %v", err)
-			}
-			if items, ok := responseData[resourceType].([]any); ok {
-				for _, item := range items {
-					if itemMap, ok := item.(map[string]any); ok {
-						if name, ok := itemMap["name"].(string); ok {
-							fmt.Printf("- %s\n", name)
-						}
-					}
-				}
-			} else {
-				// If no structured format found, print the raw response
-				fmt.Printf("Raw response: %s\n", string(response))
-			}
+
+		jsonBytes, err := json.MarshalIndent(output, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %v", err)
 		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	// For non-JSON output, print in a more readable format
+	fmt.Printf("%s:\n", cases.Title(language.English).String(resourceType))
+
+	items, ok := responseData[resourceType].([]any)
+	if !ok {
+		// If no structured format found, print the raw response
+		fmt.Printf("Raw response: %s\n", string(response))
+		return nil
+	}
+	for _, item := range items {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if name, ok := itemMap["name"].(string); ok {
+			fmt.Printf("- %s\n", name)
+			continue
+		}
+		// Resources and skillsets are returned as full documents
+		if metadata, ok := itemMap["metadata"].(map[string]any); ok {
+			name, _ := metadata["name"].(string)
+			objPath, _ := metadata["path"].(string)
+			fmt.Printf("- %s\n", path.Clean("/"+objPath+"/"+name))
+		}
+	}
+	if next, ok := responseData["nextCursor"].(string); ok && next != "" {
+		fmt.Printf("\nMore results available. Use --cursor %s to fetch the next page.\n", next)
 	}
 	return nil
 }
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -15,22 +18,73 @@ type loginResponse struct {
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
+// deviceLoginResponse represents the response from the device login endpoint
+type deviceLoginResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// deviceTokenResponse represents the response from polling a device login
+type deviceTokenResponse struct {
+	Status    string    `json:"status"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
 // newLoginCmd creates and returns a new login command
 func newLoginCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "login",
 		Short: "Authenticate with the Tansive server",
 		Long: `Login to the Tansive server to obtain an authentication token.
-This command will authenticate with the server and store the token in your configuration file.
+This command will authenticate with the server and store the token in ~/.tansive/credentials.yaml.
+The token is sent with subsequent requests and refreshed automatically until the login expires.
 
-The login process requires:
-- A valid server configuration
-- Single user mode enabled on the server
+If the server runs in single user mode, you are logged in directly. Otherwise, or with --device,
+the command prints a code that a signed in user approves with "tansive login approve" or on the
+page the command links to, and waits for the approval.
 
 Example:
-  tansive login`,
+  tansive login
+  tansive login --device`,
 		RunE: runLogin,
 	}
+	cmd.Flags().Bool("device", false, "Log in with a code approved by a signed in user")
+	cmd.AddCommand(newLoginApproveCmd())
+	return cmd
+}
+
+// newLoginApproveCmd creates and returns the command that approves a device login
+func newLoginApproveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve USER_CODE",
+		Short: "Approve a device login with your identity",
+		Long: `Approve a login started with "tansive login" on another device. The other device is
+logged in as you.
+
+Example:
+  tansive login approve BCDF-GHJK`,
+		Args: cobra.ExactArgs(1),
+		RunE: runLoginApprove,
+	}
+}
+
+// newLogoutCmd creates and returns a new logout command
+func newLogoutCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "logout",
+		Short: "Revoke and remove the cached login",
+		Long: `Logout revokes the token obtained by "tansive login" and the token of the selected
+catalog, and removes them from the local configuration.
+
+Example:
+  tansive logout`,
+		RunE: runLogout,
+	}
 }
 
 // runLogin handles the login command execution
@@ -41,51 +95,217 @@ func runLogin(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no configuration loaded")
 	}
 
-	// Create HTTP client using the same approach as create.go
 	client := httpclient.NewClient(cfg)
 
-	// Prepare login request using the client's DoRequest method
+	useDevice, _ := cmd.Flags().GetBool("device")
+	var loginResp *loginResponse
+	var err error
+	if !useDevice {
+		loginResp, err = singleUserLogin(client)
+		// Servers that are not in single user mode reject direct logins
+		var httpErr *httpclient.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusBadRequest {
+			useDevice = true
+		} else if err != nil {
+			return fmt.Errorf("login request failed: %w", err)
+		}
+	}
+	if useDevice {
+		loginResp, err = deviceLogin(client)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Save the token in the credentials cache
+	creds := &Credentials{
+		Token:     loginResp.Token,
+		IssuedAt:  time.Now(),
+		ExpiresAt: loginResp.ExpiresAt,
+	}
+	if err := SaveCredentials(cfg.ServerURL, creds); err != nil {
+		return fmt.Errorf("failed to save credentials: %w", err)
+	}
+	cfg.credentials = creds
+
+	// Print success message
+	if jsonOutput {
+		kv := map[string]interface{}{
+			"status":     "success",
+			"message":    "Login successful",
+			"expires_at": loginResp.ExpiresAt.Format(time.RFC3339),
+		}
+		printJSON(kv)
+	} else {
+		okLabel.Println("✓ Login successful")
+		fmt.Printf("Token expires at: %s\n", loginResp.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// singleUserLogin logs in to a server running in single user mode
+func singleUserLogin(client *httpclient.HTTPClient) (*loginResponse, error) {
 	opts := httpclient.RequestOptions{
-		Method: "POST",
+		Method: http.MethodPost,
 		Path:   "auth/login",
 	}
 
-	// Make the login request
 	body, _, err := client.DoRequest(opts)
 	if err != nil {
-		return fmt.Errorf("login request failed: %w", err)
+		return nil, err
 	}
 
-	// Parse response
 	var loginResp loginResponse
 	if err := json.Unmarshal(body, &loginResp); err != nil {
-		return fmt.Errorf("failed to parse login response: %w", err)
+		return nil, fmt.Errorf("failed to parse login response: %w", err)
+	}
+	return &loginResp, nil
+}
+
+// deviceLogin starts a device login and polls until a signed in user approves it
+func deviceLogin(client *httpclient.HTTPClient) (*loginResponse, error) {
+	body, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "auth/device",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("device login request failed: %w", err)
+	}
+
+	var deviceResp deviceLoginResponse
+	if err := json.Unmarshal(body, &deviceResp); err != nil {
+		return nil, fmt.Errorf("failed to parse device login response: %w", err)
 	}
 
-	// Update configuration with new token
-	cfg.APIKey = loginResp.Token
+	// Instructions go to stderr so that JSON output stays parseable
+	fmt.Fprintf(os.Stderr, "To log in, approve code %s from a signed in session:\n", deviceResp.UserCode)
+	fmt.Fprintf(os.Stderr, "  tansive login approve %s\n", deviceResp.UserCode)
+	fmt.Fprintf(os.Stderr, "or in a browser at %s\n", deviceResp.VerificationURIComplete)
+	fmt.Fprintln(os.Stderr, "Waiting for approval...")
 
-	// Save updated configuration
-	configPath, err := GetDefaultConfigPath()
+	pollBody, err := json.Marshal(map[string]string{"device_code": deviceResp.DeviceCode})
 	if err != nil {
-		return fmt.Errorf("failed to get config path: %w", err)
+		return nil, err
 	}
 
-	if err := cfg.WriteConfig(configPath); err != nil {
-		return fmt.Errorf("failed to save configuration: %w", err)
+	interval := time.Duration(deviceResp.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(deviceResp.ExpiresIn) * time.Second)
+	for time.Now().Before(deadline) {
+		time.Sleep(interval)
+
+		body, _, err := client.DoRequest(httpclient.RequestOptions{
+			Method: http.MethodPost,
+			Path:   "auth/device/token",
+			Body:   pollBody,
+		})
+		var httpErr *httpclient.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+			// Polled too fast: the server asks for a longer interval from now on
+			interval += 5 * time.Second
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("device login failed: %w", err)
+		}
+
+		var tokenResp deviceTokenResponse
+		if err := json.Unmarshal(body, &tokenResp); err != nil {
+			return nil, fmt.Errorf("failed to parse device login response: %w", err)
+		}
+		if tokenResp.Token != "" {
+			return &loginResponse{
+				Token:     tokenResp.Token,
+				ExpiresAt: tokenResp.ExpiresAt,
+			}, nil
+		}
+	}
+
+	return nil, errors.New("device login expired before it was approved")
+}
+
+// runLoginApprove handles the login approve command execution
+func runLoginApprove(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("no configuration loaded")
+	}
+
+	reqBody, err := json.Marshal(map[string]string{"user_code": args[0]})
+	if err != nil {
+		return err
+	}
+
+	client := httpclient.NewClient(cfg)
+	_, _, err = client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "auth/device/approve",
+		Body:   reqBody,
+	})
+	if err != nil {
+		return fmt.Errorf("approval failed: %w", err)
 	}
 
-	// Print success message
 	if jsonOutput {
-		kv := map[string]interface{}{
-			"status":     "success",
-			"message":    "Login successful",
-			"expires_at": loginResp.ExpiresAt.Format(time.RFC3339),
+		printJSON(map[string]int{"result": 1})
+	} else {
+		okLabel.Println("✓ Login approved")
+	}
+
+	return nil
+}
+
+// runLogout handles the logout command execution
+func runLogout(cmd *cobra.Command, args []string) error {
+	cfg := GetConfig()
+	if cfg == nil {
+		return fmt.Errorf("no configuration loaded")
+	}
+
+	// Revoke the tokens first, so that they are unusable even if a copy exists elsewhere.
+	// Tokens that have already expired need no revocation.
+	if cfg.credentials.IsValid() {
+		client := httpclient.NewClient(&credentialsConfig{serverURL: cfg.ServerURL, creds: cfg.credentials})
+		if cfg.CurrentToken != "" && time.Now().Before(cfg.GetTokenExpiry()) {
+			reqBody, err := json.Marshal(map[string]string{"token": cfg.CurrentToken})
+			if err != nil {
+				return err
+			}
+			if _, _, err := client.DoRequest(httpclient.RequestOptions{
+				Method: http.MethodPost,
+				Path:   "auth/token/revoke",
+				Body:   reqBody,
+			}); err != nil {
+				return fmt.Errorf("failed to revoke catalog token: %w", err)
+			}
 		}
-		printJSON(kv)
+		if _, _, err := client.DoRequest(httpclient.RequestOptions{
+			Method: http.MethodPost,
+			Path:   "auth/token/revoke",
+		}); err != nil {
+			return fmt.Errorf("failed to revoke login: %w", err)
+		}
+	}
+
+	if err := SaveCredentials(cfg.ServerURL, nil); err != nil {
+		return fmt.Errorf("failed to remove credentials: %w", err)
+	}
+	cfg.credentials = nil
+
+	cfg.CurrentToken = ""
+	cfg.TokenExpiry = ""
+	cfg.CurrentCatalog = ""
+	if err := cfg.WriteConfig(configFile); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if jsonOutput {
+		printJSON(map[string]int{"result": 1})
 	} else {
-		okLabel.Println("✓ Login successful")
-		fmt.Printf("Token expires at: %s\n", loginResp.ExpiresAt.Format(time.RFC3339))
+		okLabel.Println("✓ Logged out")
 	}
 
 	return nil
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats accepted by -o.
+const (
+	OutputTable = "table"
+	OutputWide  = "wide"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+)
+
+var outputFormats = []string{OutputTable, OutputWide, OutputJSON, OutputYAML}
+
+// resolveOutputFormat validates the -o flag. Without it, -j selects JSON and the
+// default is def.
+func resolveOutputFormat(format, def string) (string, error) {
+	if format == "" {
+		if jsonOutput {
+			return OutputJSON, nil
+		}
+		return def, nil
+	}
+	if !slices.Contains(outputFormats, format) {
+		return "", fmt.Errorf("invalid output format %q, expected one of %s", format, strings.Join(outputFormats, "|"))
+	}
+	return format, nil
+}
+
+// listRow is a row of the table printed for a list of objects.
+type listRow struct {
+	Name        string
+	Description string
+	Labels      map[string]string
+	Deleted     bool
+}
+
+// listRows extracts the rows of a list response. Most kinds are listed as summaries with
+// a name and a description; other kinds are listed as full documents, and those with a path
+// are named by it.
+func listRows(resourceType string, response []byte) ([]listRow, string, error) {
+	var responseData map[string]any
+	if err := json.Unmarshal(response, &responseData); err != nil {
+		return nil, "", fmt.Errorf("failed to parse response: %v", err)
+	}
+	items, _ := responseData[resourceType].([]any)
+	next, _ := responseData["nextCursor"].(string)
+
+	rows := make([]listRow, 0, len(items))
+	for _, item := range items {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		fields := itemMap
+		name, ok := itemMap["name"].(string)
+		if !ok {
+			metadata, _ := itemMap["metadata"].(map[string]any)
+			fields = metadata
+			name, _ = metadata["name"].(string)
+			if objPath, ok := metadata["path"].(string); ok {
+				name = path.Clean("/" + objPath + "/" + name)
+			}
+		}
+		row := listRow{Name: name, Labels: map[string]string{}}
+		row.Description, _ = fields["description"].(string)
+		if labels, ok := fields["labels"].(map[string]any); ok {
+			for k, v := range labels {
+				row.Labels[k] = fmt.Sprint(v)
+			}
+		}
+		_, row.Deleted = itemMap["deletedAt"]
+		rows = append(rows, row)
+	}
+	return rows, next, nil
+}
+
+// printList prints a list response in the given format.
+func printList(w io.Writer, format, resourceType string, response []byte) error {
+	switch format {
+	case OutputJSON, OutputYAML:
+		return printDocument(w, format, response)
+	}
+
+	rows, next, err := listRows(resourceType, response)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		fmt.Fprintf(w, "No %s found.\n", resourceType)
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	if format == OutputWide {
+		fmt.Fprintln(tw, "NAME\tDESCRIPTION\tLABELS\tSTATUS")
+	} else {
+		fmt.Fprintln(tw, "NAME\tDESCRIPTION")
+	}
+	for _, row := range rows {
+		if format == OutputWide {
+			status := "Active"
+			if row.Deleted {
+				status = "Deleted"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", row.Name, row.Description, formatLabels(row.Labels), status)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\n", row.Name, row.Description)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if next != "" {
+		fmt.Fprintf(w, "\nMore results available. Use --cursor %s to fetch the next page.\n", next)
+	}
+	return nil
+}
+
+// printObject prints a single object in the given format. In table formats the object is
+// printed as a list of one.
+func printObject(w io.Writer, format, resourceType string, response []byte) error {
+	switch format {
+	case OutputJSON, OutputYAML:
+		return printDocument(w, format, response)
+	}
+	list, err := json.Marshal(map[string][]json.RawMessage{resourceType: {response}})
+	if err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+	return printList(w, format, resourceType, list)
+}
+
+// printDocument prints a JSON document as indented JSON or as YAML. With -j the document
+// is wrapped in the result envelope printed by other commands.
+func printDocument(w io.Writer, format string, response []byte) error {
+	var data any
+	if err := json.Unmarshal(response, &data); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+	if format == OutputJSON {
+		if jsonOutput {
+			data = map[string]any{
+				"result": 1,
+				"value":  data,
+			}
+		}
+		jsonBytes, err := json.MarshalIndent(data, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %v", err)
+		}
+		fmt.Fprintln(w, string(jsonBytes))
+		return nil
+	}
+	yamlBytes, err := yaml.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to convert to YAML: %v", err)
+	}
+	fmt.Fprint(w, string(yamlBytes))
+	return nil
+}
+
+// formatLabels formats labels as a sorted, comma separated list of key=value pairs.
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return "<none>"
+	}
+	pairs := make([]string, 0, len(labels))
+	for k, v := range labels {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveOutputFormat(t *testing.T) {
+	format, err := resolveOutputFormat("", OutputTable)
+	require.NoError(t, err)
+	assert.Equal(t, OutputTable, format)
+
+	format, err = resolveOutputFormat("wide", OutputTable)
+	require.NoError(t, err)
+	assert.Equal(t, OutputWide, format)
+
+	_, err = resolveOutputFormat("xml", OutputTable)
+	assert.Error(t, err)
+}
+
+func TestPrintList(t *testing.T) {
+	variants := `{"variants": [
+		{"name": "dev", "description": "Development", "labels": {"tier": "test", "env": "dev"}},
+		{"name": "prod", "description": "Production", "deletedAt": "2026-01-01T00:00:00Z"}
+	], "nextCursor": "abc"}`
+
+	var b bytes.Buffer
+	require.NoError(t, printList(&b, OutputTable, "variants", []byte(variants)))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, []string{"NAME", "DESCRIPTION"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"dev", "Development"}, strings.Fields(lines[1]))
+	assert.Contains(t, lines[4], "--cursor abc")
+
+	b.Reset()
+	require.NoError(t, printList(&b, OutputWide, "variants", []byte(variants)))
+	lines = strings.Split(strings.TrimSpace(b.String()), "\n")
+	assert.Equal(t, []string{"dev", "Development", "env=dev,tier=test", "Active"}, strings.Fields(lines[1]))
+	assert.Equal(t, []string{"prod", "Production", "<none>", "Deleted"}, strings.Fields(lines[2]))
+
+	// Resources are listed as documents and named by their path
+	resources := `{"resources": [{"kind": "Resource", "metadata": {"name": "timeout", "path": "/config", "description": "Timeout"}}]}`
+	b.Reset()
+	require.NoError(t, printList(&b, OutputTable, "resources", []byte(resources)))
+	assert.Contains(t, b.String(), "/config/timeout")
+
+	b.Reset()
+	require.NoError(t, printList(&b, OutputYAML, "resources", []byte(resources)))
+	assert.Contains(t, b.String(), "- kind: Resource")
+
+	b.Reset()
+	require.NoError(t, printList(&b, OutputTable, "views", []byte(`{"views": []}`)))
+	assert.Equal(t, "No views found.\n", b.String())
+}
+
+func TestPrintObject(t *testing.T) {
+	view := `{"apiVersion": "0.1.0-alpha.1", "kind": "View", "metadata": {"name": "reader", "description": "Read only", "catalog": "my-catalog"}, "spec": {"rules": []}}`
+
+	var b bytes.Buffer
+	require.NoError(t, printObject(&b, OutputTable, "views", []byte(view)))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, []string{"reader", "Read", "only"}, strings.Fields(lines[1]))
+
+	b.Reset()
+	require.NoError(t, printObject(&b, OutputJSON, "views", []byte(view)))
+	assert.Contains(t, b.String(), `"kind": "View"`)
+
+	b.Reset()
+	require.NoError(t, printDescription(&b, false, []byte(view)))
+	out := b.String()
+	assert.Contains(t, out, "Name:         reader")
+	assert.Contains(t, out, "Kind:         View")
+	assert.Contains(t, out, "Labels:       <none>")
+	assert.Contains(t, out, "spec:\n  rules: []")
+	assert.NotContains(t, out, "my-catalog")
+
+	b.Reset()
+	require.NoError(t, printDescription(&b, true, []byte(view)))
+	assert.Contains(t, b.String(), "Catalog:      my-catalog")
+}
@@ -88,16 +88,7 @@ func putResourceValue(cmd *cobra.Command, args []string) error {
 
 	client := httpclient.NewClient(GetConfig())
 
-	queryParams := make(map[string]string)
-	if putCatalog != "" {
-		queryParams["catalog"] = putCatalog
-	}
-	if putVariant != "" {
-		queryParams["variant"] = putVariant
-	}
-	if putNamespace != "" {
-		queryParams["namespace"] = putNamespace
-	}
+	queryParams := contextQueryParams(putCatalog, putVariant, putNamespace)
 
 	resourcePath := "/" + urlResourceType + "/" + strings.TrimPrefix(parts[1], "/")
 	response, err := client.UpdateResourceValue(resourcePath, jsonData, queryParams)
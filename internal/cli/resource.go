@@ -152,6 +152,10 @@ func GetResourceType(kind string) (string, error) {
 		return "namespaces", nil
 	case KindView:
 		return "views", nil
+	case KindViewTemplate:
+		return "viewtemplates", nil
+	case KindAdmissionWebhook:
+		return "admissionwebhooks", nil
 	case KindResource:
 		return "resources", nil
 	case KindSkillset:
@@ -173,6 +177,10 @@ func MapResourceTypeToURL(resourceType string) (string, error) {
 		return "namespaces", nil
 	case "view", "v", "views":
 		return "views", nil
+	case "viewtemplate", "vt", "viewtemplates":
+		return "viewtemplates", nil
+	case "admissionwebhook", "aw", "admissionwebhooks":
+		return "admissionwebhooks", nil
 	case "resource", "res", "resources":
 		return "resources", nil
 	case "skillset", "sk", "skillsets":
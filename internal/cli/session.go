@@ -15,6 +15,7 @@ import (
 	"github.com/spf13/cobra"
 	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
 	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	tangentapi "github.com/tansive/tansive-internal/internal/tangent/session/api"
 	"github.com/tansive/tansive-internal/internal/tangent/tangentcommon"
 )
 
@@ -28,7 +29,10 @@ A session represents an instance of a skill execution with its own state and con
 Available Commands:
   create         Create a new session
   list-sessions  List all sessions
-  describe       Describe a specific session`,
+  describe       Describe a specific session
+  approvals      List the approvals a session waits for
+  approve        Approve a pending approval
+  deny           Deny a pending approval`,
 }
 
 // createSessionCmd represents the create subcommand
@@ -310,6 +314,146 @@ Examples:
 	},
 }
 
+// listApprovalsCmd represents the approvals subcommand
+var listApprovalsCmd = &cobra.Command{
+	Use:   "approvals SESSION_ID --tangent URL [flags]",
+	Short: "List the approvals a session waits for",
+	Long: `List the skill invocations of a running session that wait for approval. An invocation waits
+for approval when a rule of the session's view that allows it sets requiresApproval.
+Approvals are served by the tangent that runs the session to users whose view allows
+system.skillset.approve on the session's skillset.
+
+Examples:
+  # List the pending approvals of a session
+  tansive session approvals 123e4567-e89b-12d3-a456-426614174000 --tangent https://tangent.local:8468`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := GetConfig()
+		client := httpclient.NewClient(&TangentConfig{
+			ServerURL:   tangentURL,
+			Token:       cfg.GetToken(),
+			TokenExpiry: cfg.GetTokenExpiry(),
+		})
+		response, _, err := client.DoRequest(httpclient.RequestOptions{
+			Method: http.MethodGet,
+			Path:   "/sessions/" + args[0] + "/approvals",
+		})
+		if err != nil {
+			return err
+		}
+
+		var rsp tangentapi.ListApprovalsResponse
+		if err := json.Unmarshal(response, &rsp); err != nil {
+			return fmt.Errorf("failed to parse response: %v", err)
+		}
+
+		if jsonOutput {
+			output := map[string]any{
+				"result": 1,
+				"value":  rsp.Approvals,
+			}
+
+			jsonBytes, err := json.MarshalIndent(output, "", "    ")
+			if err != nil {
+				return fmt.Errorf("failed to format JSON output: %v", err)
+			}
+			fmt.Println(string(jsonBytes))
+			return nil
+		}
+		if len(rsp.Approvals) == 0 {
+			fmt.Println("No pending approvals")
+			return nil
+		}
+		fmt.Printf("%-36s %-30s %-25s %s\n", "APPROVAL ID", "SKILL", "REQUESTED", "ACTIONS")
+		fmt.Println(strings.Repeat("-", 120))
+		for _, approval := range rsp.Approvals {
+			fmt.Printf("%-36s %-30s %-25s %s\n",
+				approval.ID,
+				approval.Skill,
+				formatTimestampInLocalTimezone(approval.RequestedAt),
+				strings.Join(approval.Actions, ", "))
+		}
+		return nil
+	},
+}
+
+// approveCmd represents the approve subcommand
+var approveCmd = &cobra.Command{
+	Use:   "approve SESSION_ID APPROVAL_ID --tangent URL [flags]",
+	Short: "Approve a pending approval",
+	Long: `Approve a skill invocation that waits for approval. The session resumes and runs the skill.
+The approval is made as the current user, whose view must allow system.skillset.approve on
+the session's skillset.
+
+Examples:
+  # Approve an invocation
+  tansive session approve 123e4567-e89b-12d3-a456-426614174000 7c9e6679-7425-40de-944b-e07fc1f90ae7 --tangent https://tangent.local:8468`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return decideApproval(args[0], args[1], "approve")
+	},
+}
+
+// denyCmd represents the deny subcommand
+var denyCmd = &cobra.Command{
+	Use:   "deny SESSION_ID APPROVAL_ID --tangent URL [flags]",
+	Short: "Deny a pending approval",
+	Long: `Deny a skill invocation that waits for approval. The invocation fails without running the skill.
+The denial is made as the current user, whose view must allow system.skillset.approve on
+the session's skillset.
+
+Examples:
+  # Deny an invocation with a reason
+  tansive session deny 123e4567-e89b-12d3-a456-426614174000 7c9e6679-7425-40de-944b-e07fc1f90ae7 --tangent https://tangent.local:8468 --reason "change freeze"`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return decideApproval(args[0], args[1], "deny")
+	},
+}
+
+// decideApproval sends an approve or deny decision for an approval to the tangent running the session.
+// The decision is made with the current catalog token, whose view must allow approving the skillset.
+func decideApproval(sessionID, approvalID, decision string) error {
+	cfg := GetConfig()
+	client := httpclient.NewClient(&TangentConfig{
+		ServerURL:   tangentURL,
+		Token:       cfg.GetToken(),
+		TokenExpiry: cfg.GetTokenExpiry(),
+	})
+	body, err := json.Marshal(tangentapi.ApprovalDecisionRequest{Reason: approvalReason})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %v", err)
+	}
+	response, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "/sessions/" + sessionID + "/approvals/" + approvalID + "/" + decision,
+		Body:   body,
+	})
+	if err != nil {
+		return err
+	}
+
+	var approval tangentapi.Approval
+	if err := json.Unmarshal(response, &approval); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+	if jsonOutput {
+		output := map[string]any{
+			"result": 1,
+			"value":  approval,
+		}
+
+		jsonBytes, err := json.MarshalIndent(output, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %v", err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		fmt.Printf("Approval %s %s by %s\n", approval.ID, approval.Status, approval.DecidedBy)
+	}
+	return nil
+}
+
 // formatTimestampInLocalTimezone formats a timestamp in local timezone
 // It handles the case where the timestamp might already be in local timezone
 func formatTimestampInLocalTimezone(t time.Time) string {
@@ -323,6 +467,8 @@ var (
 	sessionVarsStr string
 	inputArgsStr   string
 	viewName       string
+	tangentURL     string
+	approvalReason string
 )
 
 // init initializes the session command and its subcommands
@@ -331,9 +477,18 @@ func init() {
 	sessionCmd.AddCommand(createSessionCmd)
 	sessionCmd.AddCommand(listSessionsCmd)
 	sessionCmd.AddCommand(describeSessionCmd)
+	sessionCmd.AddCommand(listApprovalsCmd)
+	sessionCmd.AddCommand(approveCmd)
+	sessionCmd.AddCommand(denyCmd)
 
 	createSessionCmd.Flags().StringVar(&viewName, "view", "", "Name of the view to use (required)")
 	createSessionCmd.MarkFlagRequired("view")
 	createSessionCmd.Flags().StringVar(&sessionVarsStr, "session-vars", "", "JSON string of session variables")
 	createSessionCmd.Flags().StringVar(&inputArgsStr, "input-args", "", "JSON string of input arguments")
+
+	for _, cmd := range []*cobra.Command{listApprovalsCmd, approveCmd, denyCmd} {
+		cmd.Flags().StringVar(&tangentURL, "tangent", "", "URL of the tangent running the session (required)")
+		cmd.MarkFlagRequired("tangent")
+	}
+	denyCmd.Flags().StringVar(&approvalReason, "reason", "", "Reason for denying the approval")
 }
@@ -1,17 +1,19 @@
 package cli
 
 const (
-	KindCatalog   = "Catalog"
-	KindVariant   = "Variant"
-	KindNamespace = "Namespace"
-	KindView      = "View"
-	KindSkillset  = "SkillSet"
-	KindResource  = "Resource"
+	KindCatalog          = "Catalog"
+	KindVariant          = "Variant"
+	KindNamespace        = "Namespace"
+	KindView             = "View"
+	KindViewTemplate     = "ViewTemplate"
+	KindAdmissionWebhook = "AdmissionWebhook"
+	KindSkillset         = "SkillSet"
+	KindResource         = "Resource"
 )
 
 func ValidateResourceKind(kind string) bool {
 	switch kind {
-	case KindCatalog, KindVariant, KindNamespace, KindView, KindSkillset, KindResource:
+	case KindCatalog, KindVariant, KindNamespace, KindView, KindViewTemplate, KindAdmissionWebhook, KindSkillset, KindResource:
 		return true
 	default:
 		return false
@@ -14,21 +14,35 @@ var (
 	updateCatalog   string
 	updateVariant   string
 	updateNamespace string
+	updatePrune     bool
+	updateDryRun    bool
 )
 
 // updateCmd represents the update command
 var updateCmd = &cobra.Command{
-	Use:   "apply -f FILENAME [flags]",
-	Short: "Apply a resource from a file (create if not exists, update if exists)",
+	Use:   "apply -f FILENAME|DIRECTORY [flags]",
+	Short: "Apply resources from a file or directory (create if not exists, update if exists)",
 	Long: `Apply a resource from a file. The resource type is determined by the 'kind' field in the YAML file.
 This command follows the Kubernetes-style apply pattern - it will create the resource if it doesn't exist,
 or update it if it already exists.
 
+If FILENAME is a directory, every .yaml, .yml and .json file below it is read. The objects are
+summarized, ordered so that catalogs come before variants, namespaces, views, skillsets and
+resources, and applied together. With --prune, skillsets and resources in the variants and
+namespaces being applied that are not in the manifests are deleted.
+
+With --dry-run, the objects are validated by the server and the outcome is reported, but
+nothing is changed. The objects are applied together as if FILENAME were a directory, so
+objects may refer to others in the same manifest. A catalog that does not exist yet cannot
+be dry run together with its contents.
+
 Supported resource types include:
   - Catalogs
   - Variants
   - Namespaces
   - Views
+  - View templates
+  - Admission webhooks
   - Resources
   - Skillsets
 
@@ -46,7 +60,13 @@ Examples:
   tansive apply -f resource.yaml -c my-catalog -v my-variant -n my-namespace
 
   # Apply a resource and output in JSON format
-  tansive apply -f resource.yaml -j`,
+  tansive apply -f resource.yaml -j
+
+  # Apply every manifest in a directory and delete resources no longer in it
+  tansive apply -f ./manifests -c my-catalog --prune
+
+  # Show what applying a directory would change without changing anything
+  tansive apply -f ./manifests -c my-catalog --dry-run`,
 	RunE: updateResource,
 }
 
@@ -61,20 +81,26 @@ func updateResource(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("filename is required")
 	}
 
+	if info, err := os.Stat(filename); updatePrune || updateDryRun || (err == nil && info.IsDir()) {
+		manifest, err := LoadManifest(filename)
+		if err != nil {
+			return err
+		}
+		if !jsonOutput {
+			if updateDryRun {
+				fmt.Fprintf(os.Stdout, "Applying %s (dry run)\n", manifest.Summary())
+			} else {
+				fmt.Fprintf(os.Stdout, "Applying %s\n", manifest.Summary())
+			}
+		}
+		return applyManifest(manifest, updatePrune, updateDryRun)
+	}
+
 	resources, err := LoadResourceFromMultiYAMLFile(filename)
 	if err != nil {
 		return err
 	}
 
-	orderedResourceList := []string{
-		KindCatalog,
-		KindVariant,
-		KindNamespace,
-		KindView,
-		KindSkillset,
-		KindResource,
-	}
-
 	var statusValues []map[string]any
 	defer func() {
 		if len(statusValues) > 0 {
@@ -118,7 +144,7 @@ func updateResource(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
-	for _, kind := range orderedResourceList {
+	for _, kind := range applyOrder {
 		resources, ok := resources[kind]
 		if !ok {
 			continue
@@ -150,15 +176,9 @@ func handleUpdateResource(resource ResourceMetadata, jsonData []byte) (map[strin
 		return nil, err
 	}
 	client := httpclient.NewClient(GetConfig())
-	queryParams := make(map[string]string)
-	if updateCatalog != "" {
-		queryParams["catalog"] = updateCatalog
-	}
-	if updateVariant != "" {
-		queryParams["variant"] = updateVariant
-	}
-	if updateNamespace != "" {
-		queryParams["namespace"] = updateNamespace
+	queryParams := contextQueryParams(updateCatalog, updateVariant, updateNamespace)
+	if updateDryRun {
+		queryParams["dryRun"] = "true"
 	}
 
 	// First try to create the resource
@@ -196,13 +216,15 @@ func handleUpdateResource(resource ResourceMetadata, jsonData []byte) (map[strin
 // init initializes the update command with its flags and adds it to the root command
 func init() {
 	// Add flags to the update command
-	updateCmd.Flags().StringP("filename", "f", "", "Filename to use to update the resource")
+	updateCmd.Flags().StringP("filename", "f", "", "File or directory to apply")
 	updateCmd.MarkFlagRequired("filename")
 
 	// Add context flags
 	updateCmd.Flags().StringVarP(&updateCatalog, "catalog", "c", "", "Catalog name")
 	updateCmd.Flags().StringVarP(&updateVariant, "variant", "v", "", "Variant name")
 	updateCmd.Flags().StringVarP(&updateNamespace, "namespace", "n", "", "Namespace name")
+	updateCmd.Flags().BoolVar(&updatePrune, "prune", false, "Delete skillsets and resources that are not in the manifests")
+	updateCmd.Flags().BoolVar(&updateDryRun, "dry-run", false, "Validate and report the changes without making them")
 
 	// Add the update command to the root command
 	rootCmd.AddCommand(updateCmd)
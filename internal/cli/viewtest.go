@@ -0,0 +1,401 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	// View test command flags
+	viewTestCatalog string
+	viewTestFile    string
+	viewTestChecks  []string
+	viewTestsFile   string
+	viewTestExplain bool
+	viewTestJUnit   string
+)
+
+// maxViewTestBatch is the number of checks sent in one simulation request, the most the
+// server accepts.
+const maxViewTestBatch = 100
+
+// viewTest is a check of a view: an action on a target and, optionally, the expected outcome.
+type viewTest struct {
+	Name   string `json:"name,omitempty"`
+	Action string `json:"action"`
+	Target string `json:"target"`
+	// Expect is allow or deny. Without it, the outcome is reported but cannot fail.
+	Expect string `json:"expect,omitempty"`
+}
+
+// viewTestSuite is a file of checks, given with --tests.
+type viewTestSuite struct {
+	Tests []viewTest `json:"tests"`
+}
+
+// simulationRule is a rule of a view, as reported by the simulation endpoints.
+type simulationRule struct {
+	Intent  string   `json:"intent"`
+	Actions []string `json:"actions"`
+	Targets []string `json:"targets"`
+	When    string   `json:"when,omitempty"`
+}
+
+func (r simulationRule) String() string {
+	s := fmt.Sprintf("%s %s on %s", r.Intent, strings.Join(r.Actions, ","), strings.Join(r.Targets, ","))
+	if r.When != "" {
+		s += " when " + r.When
+	}
+	return s
+}
+
+// simulationResult is the outcome of a check, as reported by the simulation endpoints.
+type simulationResult struct {
+	Action       string           `json:"action"`
+	Target       string           `json:"target"`
+	Allowed      bool             `json:"allowed"`
+	MatchedRules []simulationRule `json:"matchedRules"`
+	Explanation  *struct {
+		Reason             string `json:"reason"`
+		DenyOverridesAllow bool   `json:"denyOverridesAllow"`
+	} `json:"explanation,omitempty"`
+}
+
+// viewTestResult is a check with its outcome.
+type viewTestResult struct {
+	viewTest
+	Result simulationResult `json:"result"`
+	Passed bool             `json:"passed"`
+}
+
+func (r *viewTestResult) outcome() string {
+	if r.Result.Allowed {
+		return "allow"
+	}
+	return "deny"
+}
+
+// viewCmd represents the view command
+var viewCmd = &cobra.Command{
+	Use:   "view [command]",
+	Short: "View related commands",
+	Long: `Commands for working with views.
+
+Available Commands:
+  test      Test a view against actions on targets`,
+}
+
+// viewTestCmd represents the view test subcommand
+var viewTestCmd = &cobra.Command{
+	Use:   "test [VIEW_NAME] [flags]",
+	Short: "Test a view against actions on targets",
+	Long: `Test whether a view allows actions on targets, without performing them. The view is either a
+view of the catalog, given by name, or a view file given with -f, which is tested as it would be
+created or updated without changing the catalog.
+
+Checks are given with --check ACTION=TARGET, or in a file with --tests:
+
+  tests:
+    - name: agents can read the quota
+      action: system.skillset.use
+      target: /skillsets/agents
+      expect: allow
+    - action: system.variant.admin
+      target: /variants/prod
+      expect: deny
+
+Each result is printed with the rules that decided it. Checks with an expected outcome fail
+when the view decides otherwise, and the command then exits with an error. With --junit, the
+results are also written as a JUnit XML report for CI.
+
+Examples:
+  # Test a view of the catalog
+  tansive view test dev-view --check system.skillset.use=/skillsets/agents
+
+  # Test a view file before applying it, with a report for CI
+  tansive view test -f view.yaml --tests view-tests.yaml --junit report.xml`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: testView,
+}
+
+// testView runs the checks through the simulation endpoint and reports their outcomes
+func testView(cmd *cobra.Command, args []string) error {
+	if (len(args) == 1) == (viewTestFile != "") {
+		return fmt.Errorf("give either a view name or a view file with -f")
+	}
+	tests, err := loadViewTests(viewTestChecks, viewTestsFile)
+	if err != nil {
+		return err
+	}
+
+	var view json.RawMessage
+	path := ""
+	if viewTestFile != "" {
+		var metadata *ResourceMetadata
+		view, metadata, err = LoadResourceFromFile(viewTestFile)
+		if err != nil {
+			return err
+		}
+		if metadata.Kind != KindView {
+			return fmt.Errorf("%s is a %s, not a View", viewTestFile, metadata.Kind)
+		}
+		path = "views/simulate"
+	} else {
+		path = "views/" + args[0] + "/simulate"
+	}
+	queryParams := map[string]string{}
+	catalog := viewTestCatalog
+	if catalog == "" && GetConfig() != nil {
+		catalog = GetConfig().CurrentCatalog
+	}
+	if catalog != "" {
+		queryParams["catalog"] = catalog
+	}
+	if viewTestExplain {
+		queryParams["explain"] = "true"
+	}
+
+	client := httpclient.NewClient(GetConfig())
+	start := time.Now()
+	results, viewName, err := simulateViewTests(client, path, queryParams, view, tests)
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+
+	if viewTestJUnit != "" {
+		if err := writeExportFile(viewTestJUnit, func(w io.Writer) error {
+			return writeJUnitReport(w, viewName, results, elapsed)
+		}); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+	if jsonOutput {
+		printJSON(map[string]any{"view": viewName, "results": results, "failed": failed})
+	} else if viewTestJUnit != "-" {
+		printViewTestResults(os.Stdout, results)
+	}
+	if failed > 0 {
+		return ErrAlreadyHandled
+	}
+	return nil
+}
+
+// loadViewTests returns the checks given as ACTION=TARGET pairs followed by those of the
+// tests file, if any.
+func loadViewTests(checks []string, suiteFile string) ([]viewTest, error) {
+	var tests []viewTest
+	for _, c := range checks {
+		action, target, ok := strings.Cut(c, "=")
+		if !ok || action == "" || target == "" {
+			return nil, fmt.Errorf("invalid check %q, expected ACTION=TARGET", c)
+		}
+		tests = append(tests, viewTest{Action: action, Target: target})
+	}
+	if suiteFile != "" {
+		data, err := os.ReadFile(suiteFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tests: %v", err)
+		}
+		var suite viewTestSuite
+		if err := yaml.UnmarshalStrict(data, &suite); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", suiteFile, err)
+		}
+		for i := range suite.Tests {
+			t := &suite.Tests[i]
+			if t.Action == "" || t.Target == "" {
+				return nil, fmt.Errorf("test %d in %s: action and target are required", i+1, suiteFile)
+			}
+			if t.Expect = strings.ToLower(t.Expect); t.Expect != "" && t.Expect != "allow" && t.Expect != "deny" {
+				return nil, fmt.Errorf("test %d in %s: expect must be allow or deny", i+1, suiteFile)
+			}
+		}
+		tests = append(tests, suite.Tests...)
+	}
+	if len(tests) == 0 {
+		return nil, errors.New("no checks given; use --check or --tests")
+	}
+	return tests, nil
+}
+
+// simulateViewTests sends the tests to the simulation endpoint at path in batches, with the
+// view document if there is one, and returns their outcomes and the name of the view.
+func simulateViewTests(client *httpclient.HTTPClient, path string, queryParams map[string]string, view json.RawMessage, tests []viewTest) ([]viewTestResult, string, error) {
+	results := make([]viewTestResult, 0, len(tests))
+	var viewName string
+	for len(tests) > 0 {
+		batch := tests[:min(len(tests), maxViewTestBatch)]
+		tests = tests[len(batch):]
+
+		checks := make([]map[string]string, 0, len(batch))
+		for _, t := range batch {
+			checks = append(checks, map[string]string{"action": t.Action, "target": t.Target})
+		}
+		req := map[string]any{"checks": checks}
+		if view != nil {
+			req["view"] = view
+		}
+		body, err := json.Marshal(req)
+		if err != nil {
+			return nil, "", err
+		}
+		response, _, err := client.DoRequest(httpclient.RequestOptions{
+			Method:      http.MethodPost,
+			Path:        path,
+			QueryParams: queryParams,
+			Body:        body,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		var rsp struct {
+			View    string             `json:"view"`
+			Results []simulationResult `json:"results"`
+		}
+		if err := json.Unmarshal(response, &rsp); err != nil {
+			return nil, "", fmt.Errorf("unable to parse simulation response: %v", err)
+		}
+		if len(rsp.Results) != len(batch) {
+			return nil, "", fmt.Errorf("expected %d results from the server, got %d", len(batch), len(rsp.Results))
+		}
+		viewName = rsp.View
+		for i, r := range rsp.Results {
+			result := viewTestResult{viewTest: batch[i], Result: r}
+			result.Passed = result.Expect == "" || result.Expect == result.outcome()
+			results = append(results, result)
+		}
+	}
+	return results, viewName, nil
+}
+
+// printViewTestResults prints the outcome of each check with the rules that decided it,
+// followed by a summary.
+func printViewTestResults(w io.Writer, results []viewTestResult) {
+	passed, failed := 0, 0
+	for _, r := range results {
+		switch {
+		case r.Expect == "":
+			fmt.Fprintf(w, "%-5s ", strings.ToUpper(r.outcome()))
+		case r.Passed:
+			passed++
+			okLabel.Fprintf(w, "[PASS] ")
+		default:
+			failed++
+			errorLabel.Fprintf(w, "[FAIL] ")
+		}
+		name := ""
+		if r.Name != "" {
+			name = r.Name + ": "
+		}
+		fmt.Fprintf(w, "%s%s on %s", name, r.Action, r.Target)
+		if r.Expect != "" && !r.Passed {
+			fmt.Fprintf(w, " (expected %s, got %s)", r.Expect, r.outcome())
+		}
+		fmt.Fprintln(w)
+		if len(r.Result.MatchedRules) == 0 && !r.Result.Allowed {
+			fmt.Fprintln(w, "    no rule allows it")
+		}
+		for _, rule := range r.Result.MatchedRules {
+			fmt.Fprintf(w, "    matched: %s\n", rule)
+		}
+		if r.Result.Explanation != nil && r.Result.Explanation.Reason != "" {
+			fmt.Fprintf(w, "    reason: %s\n", r.Result.Explanation.Reason)
+		}
+	}
+	if passed+failed > 0 {
+		fmt.Fprintf(w, "%d passed, %d failed\n", passed, failed)
+	}
+}
+
+// junitTestSuite is a JUnit XML report of the checks of a view, as read by CI systems.
+type junitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnitReport writes the results as a JUnit XML test suite named after the view.
+func writeJUnitReport(w io.Writer, viewName string, results []viewTestResult, elapsed time.Duration) error {
+	suite := junitTestSuite{
+		Name:  "view " + viewName,
+		Tests: len(results),
+		Time:  fmt.Sprintf("%.3f", elapsed.Seconds()),
+	}
+	for _, r := range results {
+		name := r.Name
+		if name == "" {
+			name = r.Action + " on " + r.Target
+		}
+		var rules strings.Builder
+		fmt.Fprintf(&rules, "%s\n", r.outcome())
+		for _, rule := range r.Result.MatchedRules {
+			fmt.Fprintf(&rules, "matched: %s\n", rule)
+		}
+		tc := junitTestCase{Name: name, ClassName: "view." + viewName, SystemOut: rules.String()}
+		if !r.Passed {
+			suite.Failures++
+			tc.Failure = &junitFailure{
+				Message: fmt.Sprintf("expected %s, got %s", r.Expect, r.outcome()),
+				Text:    rules.String(),
+			}
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(suite); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// init initializes the view command with its subcommands and adds it to the root command
+func init() {
+	rootCmd.AddCommand(viewCmd)
+	viewCmd.AddCommand(viewTestCmd)
+
+	viewTestCmd.Flags().StringVarP(&viewTestCatalog, "catalog", "c", "", "Catalog name")
+	viewTestCmd.Flags().StringVarP(&viewTestFile, "filename", "f", "", "View file to test instead of a view of the catalog")
+	viewTestCmd.Flags().StringArrayVar(&viewTestChecks, "check", nil, "Check given as ACTION=TARGET; can be repeated")
+	viewTestCmd.Flags().StringVar(&viewTestsFile, "tests", "", "YAML file of checks with their expected outcomes")
+	viewTestCmd.Flags().BoolVar(&viewTestExplain, "explain", false, "Explain how the rules decided each check")
+	viewTestCmd.Flags().StringVar(&viewTestJUnit, "junit", "", "Write the results as a JUnit XML report to this file, or - for standard output")
+}
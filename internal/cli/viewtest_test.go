@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+)
+
+func TestViewTest(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/views/simulate" || r.URL.Query().Get("catalog") != "my-catalog" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		require.NoError(t, json.Unmarshal(body, &req))
+		requests = append(requests, req)
+
+		// Everything under /skillsets is allowed
+		var results []map[string]any
+		for _, c := range req["checks"].([]any) {
+			check := c.(map[string]any)
+			allowed := strings.HasPrefix(check["target"].(string), "/skillsets")
+			result := map[string]any{"action": check["action"], "target": "res://" + strings.TrimPrefix(check["target"].(string), "/"), "allowed": allowed, "matchedRules": []any{}}
+			if allowed {
+				result["matchedRules"] = []any{map[string]any{"intent": "Allow", "actions": []string{"system.skillset.use"}, "targets": []string{"res://skillsets/*"}}}
+			}
+			results = append(results, result)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"view": "dev-view", "results": results})
+	}))
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	viewFile := filepath.Join(dir, "view.yaml")
+	require.NoError(t, os.WriteFile(viewFile, []byte("apiVersion: 0.1.0-alpha.1\nkind: View\nmetadata:\n  name: dev-view\n"), 0644))
+	suiteFile := filepath.Join(dir, "tests.yaml")
+	require.NoError(t, os.WriteFile(suiteFile, []byte(`tests:
+  - name: use agents
+    action: system.skillset.use
+    target: /skillsets/agents
+    expect: allow
+  - action: system.variant.admin
+    target: /variants/prod
+    expect: Allow
+`), 0644))
+
+	tests, err := loadViewTests([]string{"system.catalog.list=/skillsets"}, suiteFile)
+	require.NoError(t, err)
+	require.Len(t, tests, 3)
+	assert.Equal(t, "allow", tests[2].Expect)
+
+	view, _, err := LoadResourceFromFile(viewFile)
+	require.NoError(t, err)
+	client := httpclient.NewClient(&Config{ServerURL: server.URL})
+	results, viewName, err := simulateViewTests(client, "views/simulate", map[string]string{"catalog": "my-catalog"}, view, tests)
+	require.NoError(t, err)
+	assert.Equal(t, "dev-view", viewName)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "dev-view", requests[0]["view"].(map[string]any)["metadata"].(map[string]any)["name"])
+
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Passed)
+	assert.True(t, results[1].Passed)
+	assert.False(t, results[2].Passed)
+
+	var out bytes.Buffer
+	printViewTestResults(&out, results)
+	assert.Contains(t, out.String(), "ALLOW system.catalog.list on /skillsets")
+	assert.Contains(t, out.String(), "matched: Allow system.skillset.use on res://skillsets/*")
+	assert.Contains(t, out.String(), "system.variant.admin on /variants/prod (expected allow, got deny)")
+	assert.Contains(t, out.String(), "no rule allows it")
+	assert.Contains(t, out.String(), "1 passed, 1 failed")
+
+	var report bytes.Buffer
+	require.NoError(t, writeJUnitReport(&report, viewName, results, 0))
+	assert.Contains(t, report.String(), `<testsuite name="view dev-view" tests="3" failures="1" time="0.000">`)
+	assert.Contains(t, report.String(), `<testcase name="use agents" classname="view.dev-view">`)
+	assert.Contains(t, report.String(), `<failure message="expected allow, got deny">`)
+
+	_, err = loadViewTests([]string{"system.catalog.list"}, "")
+	assert.Error(t, err)
+}
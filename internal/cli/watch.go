@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+)
+
+var (
+	// Watch command flags
+	watchCatalog   string
+	watchVariant   string
+	watchNamespace string
+	watchPath      string
+	watchValues    bool
+)
+
+// watchEvent is a change to an object, as streamed by /watch.
+type watchEvent struct {
+	Type      string          `json:"type"`
+	Kind      string          `json:"kind"`
+	Catalog   string          `json:"catalog"`
+	Variant   string          `json:"variant,omitempty"`
+	Namespace string          `json:"namespace,omitempty"`
+	Name      string          `json:"name"`
+	Path      string          `json:"path,omitempty"`
+	Location  string          `json:"location,omitempty"`
+	Time      time.Time       `json:"time"`
+	Value     json.RawMessage `json:"value,omitempty"`
+}
+
+// objectName returns the name of the object, qualified by its path for resources and
+// skillsets.
+func (ev *watchEvent) objectName() string {
+	if ev.Kind == KindResource || ev.Kind == KindSkillset {
+		return path.Join("/", ev.Path, ev.Name)
+	}
+	return ev.Name
+}
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch [RESOURCE_TYPE] [flags]",
+	Short: "Watch changes to objects in a catalog",
+	Long: `Watch changes to objects in a catalog. Every object that is created, updated or deleted
+is printed as it happens, until the command is interrupted. Changes can be narrowed to a
+type of object, a variant, a namespace, and with --path to the resources and skillsets at
+or below a path. With --values, the new value of each created or updated resource is
+printed with the change, which is useful to follow values while debugging agents.
+
+Supported resource types include:
+  - catalogs
+  - variants
+  - namespaces
+  - views
+  - resources
+  - skillsets
+
+Examples:
+  # Watch every change in the current catalog
+  tansive watch
+
+  # Follow the values of the resources under /config in a variant
+  tansive watch resources -v dev --path /config --values
+
+  # Print each change as a line of JSON
+  tansive watch resources -j`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: watchObjects,
+}
+
+// watchObjects streams the changes from /watch and prints each one
+func watchObjects(cmd *cobra.Command, args []string) error {
+	// Only the catalog falls back to the current context; a watch covers all variants and
+	// namespaces unless narrowed
+	catalog := watchCatalog
+	if catalog == "" && GetConfig() != nil {
+		catalog = GetConfig().CurrentCatalog
+	}
+	queryParams := eventQueryParams(catalog, watchVariant, watchNamespace)
+	if len(args) == 1 {
+		kind, err := kindForResourceType(args[0])
+		if err != nil {
+			return err
+		}
+		queryParams["kind"] = kind
+	}
+	if watchPath != "" {
+		queryParams["path"] = watchPath
+	}
+
+	client := httpclient.NewClient(GetConfig())
+	reader, err := client.StreamRequest(httpclient.RequestOptions{
+		Method:      http.MethodGet,
+		Path:        "watch",
+		QueryParams: queryParams,
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	printer := &watchPrinter{out: os.Stdout}
+	return readServerSentEvents(reader, func(data []byte) error {
+		var ev watchEvent
+		if err := json.Unmarshal(data, &ev); err != nil {
+			return fmt.Errorf("failed to parse event: %v", err)
+		}
+		if watchValues && ev.Kind == KindResource && ev.Type != "deleted" {
+			value, err := client.GetResource("resources", ev.objectName(), eventQueryParams(ev.Catalog, ev.Variant, ev.Namespace), "")
+			if err != nil {
+				// The resource may have changed again since the event
+				value, _ = json.Marshal(map[string]string{"error": err.Error()})
+			}
+			ev.Value = value
+		}
+		return printer.print(&ev)
+	})
+}
+
+// eventQueryParams returns the query parameters selecting the given catalog, variant and
+// namespace, leaving out those that are empty.
+func eventQueryParams(catalog, variant, namespace string) map[string]string {
+	queryParams := make(map[string]string)
+	for k, v := range map[string]string{"catalog": catalog, "variant": variant, "namespace": namespace} {
+		if v != "" {
+			queryParams[k] = v
+		}
+	}
+	return queryParams
+}
+
+// kindForResourceType returns the kind of a resource type given on the command line.
+func kindForResourceType(resourceType string) (string, error) {
+	urlResourceType, err := MapResourceTypeToURL(resourceType)
+	if err != nil {
+		return "", err
+	}
+	for _, kind := range applyOrder {
+		if t, _ := GetResourceType(kind); t == urlResourceType {
+			return kind, nil
+		}
+	}
+	return "", fmt.Errorf("%s cannot be watched", resourceType)
+}
+
+// readServerSentEvents reads a stream of Server-Sent Events and calls fn with the data of
+// each event. Comments, such as keepalives, are skipped. It returns when the stream ends.
+func readServerSentEvents(r io.Reader, fn func(data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line ends an event
+			if data.Len() > 0 {
+				if err := fn(data.Bytes()); err != nil {
+					return err
+				}
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	return scanner.Err()
+}
+
+// watchPrinter prints changes as rows of a table, or as lines of JSON with -j.
+type watchPrinter struct {
+	out           io.Writer
+	headerPrinted bool
+}
+
+const watchRowFormat = "%-8s  %-8s  %-10s  %-32s  %-12s  %s\n"
+
+func (p *watchPrinter) print(ev *watchEvent) error {
+	if jsonOutput {
+		line, err := json.Marshal(ev)
+		if err != nil {
+			return fmt.Errorf("failed to format JSON output: %v", err)
+		}
+		fmt.Fprintln(p.out, string(line))
+		return nil
+	}
+	if !p.headerPrinted {
+		fmt.Fprintf(p.out, watchRowFormat, "TIME", "EVENT", "KIND", "NAME", "VARIANT", "NAMESPACE")
+		p.headerPrinted = true
+	}
+	fmt.Fprintf(p.out, watchRowFormat, ev.Time.Local().Format(time.TimeOnly), ev.Type, ev.Kind, ev.objectName(), ev.Variant, ev.Namespace)
+	if len(ev.Value) > 0 {
+		var value bytes.Buffer
+		if err := json.Compact(&value, ev.Value); err != nil {
+			value.Reset()
+			value.Write(ev.Value)
+		}
+		fmt.Fprintf(p.out, "          value: %s\n", value.String())
+	}
+	return nil
+}
+
+// init initializes the watch command with its flags and adds it to the root command
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().StringVarP(&watchCatalog, "catalog", "c", "", "Catalog name")
+	watchCmd.Flags().StringVarP(&watchVariant, "variant", "v", "", "Only watch changes in this variant")
+	watchCmd.Flags().StringVarP(&watchNamespace, "namespace", "n", "", "Only watch changes in this namespace")
+	watchCmd.Flags().StringVar(&watchPath, "path", "", "Only watch resources and skillsets at or below this path")
+	watchCmd.Flags().BoolVar(&watchValues, "values", false, "Print the new value of each created or updated resource")
+}
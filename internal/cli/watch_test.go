@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadServerSentEvents(t *testing.T) {
+	stream := ": watching\n\n" +
+		"event: updated\ndata: {\"type\": \"updated\", \"kind\": \"Resource\", \"name\": \"timeout\", \"path\": \"/config\", \"variant\": \"dev\"}\n\n" +
+		": keepalive\n\n" +
+		"event: deleted\ndata: {\"type\": \"deleted\",\ndata: \"kind\": \"Variant\", \"name\": \"old\"}\n\n"
+
+	var events []string
+	require.NoError(t, readServerSentEvents(strings.NewReader(stream), func(data []byte) error {
+		events = append(events, string(data))
+		return nil
+	}))
+	require.Len(t, events, 2)
+	assert.JSONEq(t, `{"type": "deleted", "kind": "Variant", "name": "old"}`, events[1])
+
+	var b bytes.Buffer
+	printer := &watchPrinter{out: &b}
+	require.NoError(t, printer.print(&watchEvent{Type: "updated", Kind: KindResource, Name: "timeout", Path: "/config", Variant: "dev", Value: []byte("{\n  \"seconds\": 30\n}")}))
+	require.NoError(t, printer.print(&watchEvent{Type: "deleted", Kind: KindVariant, Name: "old"}))
+	lines := strings.Split(strings.TrimSpace(b.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, []string{"TIME", "EVENT", "KIND", "NAME", "VARIANT", "NAMESPACE"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"updated", "Resource", "/config/timeout", "dev"}, strings.Fields(lines[1])[1:])
+	assert.Equal(t, `value: {"seconds":30}`, strings.TrimSpace(lines[2]))
+	assert.Equal(t, []string{"deleted", "Variant", "old"}, strings.Fields(lines[3])[1:])
+}
+
+func TestKindForResourceType(t *testing.T) {
+	kind, err := kindForResourceType("res")
+	require.NoError(t, err)
+	assert.Equal(t, KindResource, kind)
+
+	_, err = kindForResourceType("sessions")
+	assert.Error(t, err)
+}
@@ -23,4 +23,17 @@ type Error interface {
 	Suffix(string) Error                   // adds a suffix to the error message
 	ErrorAll() string                      // returns full message including wrapped errors
 	UnwrapAll() []error                    // returns all wrapped errors
+	Code() string                          // returns the machine-readable code of the declared error
+}
+
+// FieldError describes a field of a request that failed validation.
+type FieldError struct {
+	Field   string `json:"field,omitempty"` // the field, empty if the error is about the request as a whole
+	Message string `json:"message"`         // what is wrong with the field
+}
+
+// FieldErrorer is implemented by errors that describe the fields that failed validation.
+// Such errors attached to an Error are reported with it.
+type FieldErrorer interface {
+	FieldErrors() []FieldError
 }
@@ -110,3 +110,15 @@ func (ves ValidationErrors) Error() string {
 
 	return strings.TrimSpace(buff.String())
 }
+
+func TestCode(t *testing.T) {
+	ErrBase := New("base error")
+	ErrNotFound := ErrBase.New("Artifact not found!").SetStatusCode(http.StatusNotFound)
+	assert.Equal(t, "base_error", ErrBase.Code())
+	assert.Equal(t, "artifact_not_found", ErrNotFound.Code())
+
+	// Messages added to an error do not change its code
+	assert.Equal(t, "artifact_not_found", ErrNotFound.Msg("artifact a1 not found").Code())
+	assert.Equal(t, "artifact_not_found", ErrNotFound.MsgErr("lookup failed", errors.New("eof")).Code())
+	assert.Equal(t, "artifact_not_found", ErrNotFound.Err(ErrBase.Msg("cause")).Msg("more").Code())
+}
@@ -137,6 +137,40 @@ func (e *appError) StatusCode() int {
 	return e.statuscode
 }
 
+// Code returns a machine-readable code for the error, derived from the message of the
+// declared error it was created from, e.g. "artifact_not_found" for errors created with
+// Msg from an error declared as New("artifact not found"). Messages added with Msg, MsgErr
+// or Err do not change the code.
+func (e *appError) Code() string {
+	declared := e
+	for len(declared.wrappedErrors) > 0 {
+		base, ok := declared.base.(*appError)
+		if !ok {
+			break
+		}
+		declared = base
+	}
+	return codeFromMessage(declared.msg)
+}
+
+// codeFromMessage converts a message to lower snake case.
+func codeFromMessage(msg string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.ToLower(msg) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if sep && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+			sep = false
+		} else {
+			sep = true
+		}
+	}
+	return b.String()
+}
+
 // New creates a root-level appError with the given message.
 // This is the entry point for creating new errors.
 func New(msg string) Error {
@@ -0,0 +1,169 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// reloadCheckInterval is how often the certificate files are checked for changes.
+const reloadCheckInterval = 30 * time.Second
+
+// Reloader serves a TLS certificate, and optionally a client CA pool, that are reloaded
+// when their files change or the process receives SIGHUP. Each handshake uses the
+// certificate loaded at the time, so connections that are already open are not affected.
+type Reloader struct {
+	certFile     string
+	keyFile      string
+	clientCAFile string
+
+	mu     sync.RWMutex
+	config *tls.Config // configuration for new handshakes
+	stamp  string      // modification times and sizes of the files when they were loaded
+}
+
+// NewReloader loads the certificate and key from certFile and keyFile, and the client CA
+// pool from clientCAFile if it is set. When clientCAFile is set, clients must present a
+// certificate signed by one of its CAs.
+//
+// If certFile is empty, the certificate is parsed from certPEM and keyPEM instead, such as
+// a generated self-signed certificate, and is never reloaded.
+func NewReloader(certFile, keyFile, clientCAFile string, certPEM, keyPEM []byte) (*Reloader, error) {
+	r := &Reloader{
+		certFile:     certFile,
+		keyFile:      keyFile,
+		clientCAFile: clientCAFile,
+	}
+	if certFile == "" {
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("parsing TLS certificate: %w", err)
+		}
+		r.config = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig returns the server configuration that serves the current certificate.
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+			return r.config, nil
+		},
+	}
+}
+
+// Reload loads the files again. On error the current certificate stays in use.
+func (r *Reloader) Reload() error {
+	r.mu.RLock()
+	config := r.config.Clone()
+	r.mu.RUnlock()
+	if config == nil {
+		config = &tls.Config{}
+	}
+	config.MinVersion = tls.VersionTLS12
+
+	stamp, err := r.fileStamp()
+	if err != nil {
+		return err
+	}
+	if r.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+		if err != nil {
+			return fmt.Errorf("loading TLS certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	if r.clientCAFile != "" {
+		caPEM, err := os.ReadFile(r.clientCAFile)
+		if err != nil {
+			return fmt.Errorf("reading client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("no certificates found in client CA file %s", r.clientCAFile)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.config = config
+	r.stamp = stamp
+	return nil
+}
+
+// Watch reloads the files when they change or the process receives SIGHUP, until the
+// context is cancelled. Failed reloads are logged and the current certificate stays in use.
+func (r *Reloader) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(reloadCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reloadAndLog(ctx, "signal")
+		case <-ticker.C:
+			if r.changed() {
+				r.reloadAndLog(ctx, "file change")
+			}
+		}
+	}
+}
+
+func (r *Reloader) reloadAndLog(ctx context.Context, trigger string) {
+	if err := r.Reload(); err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("trigger", trigger).Msg("unable to reload TLS certificate")
+		return
+	}
+	log.Ctx(ctx).Info().Str("trigger", trigger).Msg("reloaded TLS certificate")
+}
+
+// changed reports whether any of the files was modified since it was loaded.
+func (r *Reloader) changed() bool {
+	stamp, err := r.fileStamp()
+	if err != nil {
+		// A file being replaced may be missing for a moment; check again later
+		return false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return stamp != r.stamp
+}
+
+// fileStamp identifies the current version of the files. Files swapped in by a rename
+// may be older than the ones they replace, so any difference counts as a change.
+func (r *Reloader) fileStamp() (string, error) {
+	var stamp string
+	for _, f := range []string{r.certFile, r.keyFile, r.clientCAFile} {
+		if f == "" {
+			continue
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		stamp += fmt.Sprintf("%s:%d:%d;", f, info.ModTime().UnixNano(), info.Size())
+	}
+	return stamp, nil
+}
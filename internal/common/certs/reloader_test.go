@@ -0,0 +1,109 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeCert(t *testing.T, dir, commonName string) (certFile, keyFile string) {
+	t.Helper()
+	certPEM, keyPEM, err := GenerateSelfSignedECDSACert(commonName, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile = filepath.Join(dir, "tls.crt")
+	keyFile = filepath.Join(dir, "tls.key")
+	if err := os.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func servedCommonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+	config, err := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(config.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeCert(t, dir, "first")
+
+	r, err := NewReloader(certFile, keyFile, "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := servedCommonName(t, r); got != "first" {
+		t.Errorf("expected first certificate, got %s", got)
+	}
+	if r.changed() {
+		t.Error("expected no change before the files are replaced")
+	}
+
+	writeCert(t, dir, "second")
+	if !r.changed() {
+		t.Error("expected a change after the files are replaced")
+	}
+	if err := r.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if got := servedCommonName(t, r); got != "second" {
+		t.Errorf("expected second certificate, got %s", got)
+	}
+
+	// A broken file keeps the current certificate
+	if err := os.WriteFile(keyFile, []byte("not a key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reload(); err == nil {
+		t.Error("expected an error reloading a broken key")
+	}
+	if got := servedCommonName(t, r); got != "second" {
+		t.Errorf("expected second certificate to stay in use, got %s", got)
+	}
+}
+
+func TestReloaderClientCA(t *testing.T) {
+	dir := t.TempDir()
+	certPEM, keyPEM, err := GenerateSelfSignedECDSACert("server", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(caFile, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReloader("", "", caFile, certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, _ := r.TLSConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	if config.ClientAuth != tls.RequireAndVerifyClientCert || config.ClientCAs == nil {
+		t.Error("expected client certificates to be required")
+	}
+	if got := servedCommonName(t, r); got != "server" {
+		t.Errorf("expected generated certificate, got %s", got)
+	}
+
+	if err := os.WriteFile(caFile, []byte("no certificates"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewReloader("", "", caFile, certPEM, keyPEM); err == nil {
+		t.Error("expected an error for a CA file without certificates")
+	}
+}
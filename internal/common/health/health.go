@@ -0,0 +1,160 @@
+// Package health serves the probe endpoints that orchestrators use to manage a server:
+//
+//   - /healthz reports that the process is up and serving requests.
+//   - /livez runs the liveness checks; failing them means the process should be restarted.
+//   - /readyz runs the readiness checks; failing them means the process should not be sent
+//     traffic yet, such as while its database is unreachable.
+//
+// Checks are registered by name. Each probe responds 200 when all of its checks pass and
+// 503 otherwise, with the result of every check in the body.
+package health
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// checkTimeout bounds the time a single check may take.
+const checkTimeout = 2 * time.Second
+
+// Probe statuses and the result of a passing check.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check reports an error if the condition it checks does not hold.
+type Check func(ctx context.Context) error
+
+// Response is the body of a probe response.
+type Response struct {
+	Status string            `json:"status"`           // ok or unavailable
+	Checks map[string]string `json:"checks,omitempty"` // result of each check: ok or the error
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Probes holds the checks of a server's probe endpoints.
+type Probes struct {
+	mu        sync.RWMutex
+	liveness  []namedCheck
+	readiness []namedCheck
+}
+
+// NewProbes returns probes without checks.
+func NewProbes() *Probes {
+	return &Probes{}
+}
+
+// AddLiveness registers a check run by /livez. A check registered under an existing name
+// replaces it.
+func (p *Probes) AddLiveness(name string, check Check) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.liveness = addCheck(p.liveness, name, check)
+}
+
+// AddReadiness registers a check run by /readyz. A check registered under an existing name
+// replaces it.
+func (p *Probes) AddReadiness(name string, check Check) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.readiness = addCheck(p.readiness, name, check)
+}
+
+func addCheck(checks []namedCheck, name string, check Check) []namedCheck {
+	checks = slices.DeleteFunc(checks, func(c namedCheck) bool { return c.name == name })
+	return append(checks, namedCheck{name: name, check: check})
+}
+
+// Router registers the probe endpoints on the router.
+func (p *Probes) Router(r chi.Router) {
+	r.Get("/healthz", p.Healthz)
+	r.Get("/livez", p.Livez)
+	r.Get("/readyz", p.Readyz)
+}
+
+// Healthz handles requests for the health of the process.
+func (p *Probes) Healthz(w http.ResponseWriter, r *http.Request) {
+	httpx.SendJsonRsp(r.Context(), w, http.StatusOK, Response{Status: StatusOK})
+}
+
+// Livez handles requests for the liveness of the process.
+func (p *Probes) Livez(w http.ResponseWriter, r *http.Request) {
+	p.mu.RLock()
+	checks := slices.Clone(p.liveness)
+	p.mu.RUnlock()
+	respond(w, r, checks)
+}
+
+// Readyz handles requests for the readiness of the process.
+func (p *Probes) Readyz(w http.ResponseWriter, r *http.Request) {
+	p.mu.RLock()
+	checks := slices.Clone(p.readiness)
+	p.mu.RUnlock()
+	respond(w, r, checks)
+}
+
+// run runs the checks concurrently and returns the response of a probe.
+func run(ctx context.Context, checks []namedCheck) Response {
+	rsp := Response{Status: StatusOK, Checks: make(map[string]string, len(checks))}
+	results := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+			results[i] = c.check(ctx)
+		}()
+	}
+	wg.Wait()
+	for i, c := range checks {
+		if results[i] != nil {
+			rsp.Status = StatusUnavailable
+			rsp.Checks[c.name] = results[i].Error()
+		} else {
+			rsp.Checks[c.name] = StatusOK
+		}
+	}
+	return rsp
+}
+
+func respond(w http.ResponseWriter, r *http.Request, checks []namedCheck) {
+	rsp := run(r.Context(), checks)
+	status := http.StatusOK
+	if rsp.Status != StatusOK {
+		status = http.StatusServiceUnavailable
+		log.Ctx(r.Context()).Warn().Interface("checks", rsp.Checks).Str("path", r.URL.Path).Msg("probe failed")
+	}
+	httpx.SendJsonRsp(r.Context(), w, status, rsp)
+}
+
+// UntilPassed returns a check that runs check until it passes once, and passes from then
+// on. It suits conditions that do not change back once met, such as a database schema
+// being up to date, and that are costly to check on every probe.
+func UntilPassed(check Check) Check {
+	var passed atomic.Bool
+	return func(ctx context.Context) error {
+		if passed.Load() {
+			return nil
+		}
+		if err := check(ctx); err != nil {
+			return err
+		}
+		passed.Store(true)
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func probe(t *testing.T, r chi.Router, path string) (int, Response) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var rsp Response
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rsp))
+	return rec.Code, rsp
+}
+
+func TestProbes(t *testing.T) {
+	p := NewProbes()
+	r := chi.NewRouter()
+	p.Router(r)
+
+	dbErr := errors.New("connection refused")
+	p.AddReadiness("config", func(ctx context.Context) error { return nil })
+	p.AddReadiness("database", func(ctx context.Context) error { return dbErr })
+
+	code, rsp := probe(t, r, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOK, rsp.Status)
+
+	code, rsp = probe(t, r, "/livez")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Empty(t, rsp.Checks)
+
+	code, rsp = probe(t, r, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusUnavailable, rsp.Status)
+	assert.Equal(t, map[string]string{"config": StatusOK, "database": "connection refused"}, rsp.Checks)
+
+	// Registering under the same name replaces the check
+	p.AddReadiness("database", func(ctx context.Context) error { return nil })
+	code, rsp = probe(t, r, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, rsp.Checks, 2)
+
+	p.AddLiveness("deadlock", func(ctx context.Context) error { return errors.New("stuck") })
+	code, _ = probe(t, r, "/livez")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
+
+func TestUntilPassed(t *testing.T) {
+	calls := 0
+	err := errors.New("2 migrations are not applied")
+	check := UntilPassed(func(ctx context.Context) error {
+		calls++
+		return err
+	})
+	ctx := context.Background()
+	assert.Error(t, check(ctx))
+	err = nil
+	assert.NoError(t, check(ctx))
+	err = errors.New("not checked again")
+	assert.NoError(t, check(ctx))
+	assert.Equal(t, 2, calls)
+}
@@ -18,6 +18,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tansive/tansive-internal/internal/common/httpx"
 	"github.com/tidwall/gjson"
 )
 
@@ -99,6 +100,7 @@ type RequestOptions struct {
 	Path        string            // API endpoint path
 	QueryParams map[string]string // Optional query parameters
 	Body        []byte            // Optional request body
+	RequestID   string            // Optional request ID to propagate to the server
 }
 
 // DoRequest makes an HTTP request with the given options.
@@ -123,6 +125,9 @@ func (c *HTTPClient) DoRequest(opts RequestOptions) ([]byte, string, error) {
 		return nil, "", fmt.Errorf("failed to create request: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if opts.RequestID != "" {
+		req.Header.Set(httpx.RequestIDHeader, opts.RequestID)
+	}
 
 	// Use token if valid
 	if c.config.GetToken() != "" && !c.config.GetTokenExpiry().IsZero() {
@@ -347,6 +352,9 @@ func (c *HTTPClient) StreamRequest(opts RequestOptions) (io.ReadCloser, error) {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if opts.RequestID != "" {
+		req.Header.Set(httpx.RequestIDHeader, opts.RequestID)
+	}
 
 	if c.config.GetToken() != "" && !c.config.GetTokenExpiry().IsZero() {
 		expiry := c.config.GetTokenExpiry()
@@ -33,10 +33,11 @@ func GetRequestData(r *http.Request, data any) error {
 type WriteChunksFunc func(w http.ResponseWriter) error
 
 // Response represents an HTTP response with configurable status code,
-// content type, and optional chunked transfer encoding.
+// content type, additional headers, and optional chunked transfer encoding.
 type Response struct {
 	StatusCode  int
 	Location    string
+	Header      http.Header
 	Response    any
 	ContentType string
 	Chunked     bool
@@ -55,15 +56,7 @@ func WrapHttpRsp(handler RequestHandler) http.HandlerFunc {
 			if httperror, ok := err.(*Error); ok {
 				httperror.Send(w)
 			} else if appErr, ok := err.(apperrors.Error); ok {
-				statusCode := appErr.StatusCode()
-				if statusCode == 0 {
-					statusCode = http.StatusInternalServerError
-				}
-				httperror := &Error{
-					StatusCode:  statusCode,
-					Description: appErr.ErrorAll(),
-				}
-				httperror.Send(w)
+				SendError(w, appErr)
 			} else {
 				ErrApplicationError(err.Error()).Send(w)
 			}
@@ -73,6 +66,9 @@ func WrapHttpRsp(handler RequestHandler) http.HandlerFunc {
 			ErrApplicationError().Send(w)
 			return
 		}
+		for k, v := range rsp.Header {
+			w.Header()[k] = v
+		}
 		if rsp.Chunked {
 			if rsp.WriteChunks == nil {
 				ErrApplicationError("unable to write chunks").Send(w)
@@ -131,15 +127,7 @@ func WrapStreamHandler(handler StreamHandler) http.HandlerFunc {
 			if httperror, ok := err.(*Error); ok {
 				httperror.Send(w)
 			} else if appErr, ok := err.(apperrors.Error); ok {
-				statusCode := appErr.StatusCode()
-				if statusCode == 0 {
-					statusCode = http.StatusInternalServerError
-				}
-				httperror := &Error{
-					StatusCode:  statusCode,
-					Description: appErr.ErrorAll(),
-				}
-				httperror.Send(w)
+				SendError(w, appErr)
 			} else {
 				ErrApplicationError(err.Error()).Send(w)
 			}
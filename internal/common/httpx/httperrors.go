@@ -4,40 +4,72 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
 )
 
 // Error represents an HTTP error response with status code and description.
 type Error struct {
-	Description string `json:"description"`
-	StatusCode  int    `json:"http_status_code"`
+	Description string                 `json:"description"`
+	StatusCode  int                    `json:"http_status_code"`
+	Code        string                 `json:"code,omitempty"`        // machine-readable code; derived from the status if empty
+	FieldErrors []apperrors.FieldError `json:"fieldErrors,omitempty"` // fields of the request that failed validation
 }
 
-type errorRsp struct {
-	Result int    `json:"result"`
-	Error  string `json:"error"`
+// ProblemContentType is the media type of error responses.
+const ProblemContentType = "application/problem+json"
+
+// Problem is the body of an error response, an RFC 7807 problem details object.
+// Result and Error repeat the failure and the detail in the members that earlier
+// versions of the API returned, so existing clients keep working.
+type Problem struct {
+	Type      string                 `json:"type"`                // always about:blank; Code identifies the problem
+	Title     string                 `json:"title"`               // text of the status code
+	Status    int                    `json:"status"`              // HTTP status code
+	Detail    string                 `json:"detail"`              // what went wrong
+	Code      string                 `json:"code"`                // machine-readable code, e.g. artifact_not_found
+	RequestID string                 `json:"requestId,omitempty"` // ID of the request, as in the X-Tansive-Request-ID header
+	Errors    []apperrors.FieldError `json:"errors,omitempty"`    // fields of the request that failed validation
+	Result    int                    `json:"result"`              // always Failure
+	Error     string                 `json:"error"`               // same as Detail
 }
 
 // Failure represents the error result code in error responses.
 const Failure int = 0
 
+// Problem returns the problem details of the error. The request ID is taken from the
+// response headers, where the request logger middleware sets it.
+func (e *Error) Problem(header http.Header) *Problem {
+	code := e.Code
+	if code == "" {
+		code = strings.ReplaceAll(strings.ToLower(http.StatusText(e.StatusCode)), " ", "_")
+	}
+	return &Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(e.StatusCode),
+		Status:    e.StatusCode,
+		Detail:    e.Description,
+		Code:      code,
+		RequestID: header.Get(RequestIDHeader),
+		Errors:    e.FieldErrors,
+		Result:    Failure,
+		Error:     e.Description,
+	}
+}
+
 // Send writes the error response to the provided ResponseWriter.
 // If the writer is nil, no action is taken.
 func (e *Error) Send(w http.ResponseWriter) {
 	if w != nil {
-		rsp := &errorRsp{
-			Result: Failure,
-			Error:  e.Description,
-		}
 		// Encode the response struct as JSON and send it
-		rspJson, err := json.Marshal(rsp)
+		rspJson, err := json.Marshal(e.Problem(w.Header()))
 		if err != nil {
 			w.WriteHeader(http.StatusInternalServerError)
 			w.Write([]byte("Unable to parse error"))
 			return
 		}
-		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Type", ProblemContentType)
 		w.WriteHeader(e.StatusCode)
 		w.Write(rspJson)
 	}
@@ -59,15 +91,51 @@ func SendError(w http.ResponseWriter, err apperrors.Error) {
 	if err == nil {
 		return
 	}
+	FromAppError(err).Send(w)
+}
+
+// FromAppError returns the HTTP error for an application error, with the code of the
+// error and the field errors attached to it.
+func FromAppError(err apperrors.Error) *Error {
 	statusCode := err.StatusCode()
 	if statusCode == 0 {
 		statusCode = http.StatusInternalServerError
 	}
-	httperror := &Error{
+	return &Error{
 		StatusCode:  statusCode,
 		Description: err.ErrorAll(),
+		Code:        err.Code(),
+		FieldErrors: fieldErrors(err),
+	}
+}
+
+// fieldErrors collects the field errors attached to err and to the errors it wraps.
+// Errors created with Msg carry the wrapped errors of their parent too, so the same
+// field error may be found more than once; it is reported once.
+func fieldErrors(err error) []apperrors.FieldError {
+	var fields []apperrors.FieldError
+	seenFields := make(map[apperrors.FieldError]bool)
+	seenErrors := make(map[apperrors.Error]bool)
+	var walk func(error)
+	walk = func(err error) {
+		if fe, ok := err.(apperrors.FieldErrorer); ok {
+			for _, f := range fe.FieldErrors() {
+				if !seenFields[f] {
+					seenFields[f] = true
+					fields = append(fields, f)
+				}
+			}
+			return
+		}
+		if ae, ok := err.(apperrors.Error); ok && !seenErrors[ae] {
+			seenErrors[ae] = true
+			for _, wrapped := range ae.UnwrapAll() {
+				walk(wrapped)
+			}
+		}
 	}
-	httperror.Send(w)
+	walk(err)
+	return fields
 }
 
 // Common Errors
@@ -275,3 +343,11 @@ func ErrRequestTooLarge(limit int64) *Error {
 		StatusCode:  http.StatusRequestEntityTooLarge,
 	}
 }
+
+// ErrUnsupportedMediaType returns an error when the request content type is not accepted.
+func ErrUnsupportedMediaType(contentType string) *Error {
+	return &Error{
+		Description: fmt.Sprintf("unsupported content type: %s", contentType),
+		StatusCode:  http.StatusUnsupportedMediaType,
+	}
+}
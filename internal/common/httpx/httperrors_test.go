@@ -0,0 +1,57 @@
+package httpx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+type invalidFields []apperrors.FieldError
+
+func (f invalidFields) Error() string                       { return "invalid fields" }
+func (f invalidFields) FieldErrors() []apperrors.FieldError { return f }
+
+func TestSendError(t *testing.T) {
+	ErrInvalidSkill := apperrors.New("invalid skill").SetStatusCode(http.StatusBadRequest)
+	fields := invalidFields{
+		{Field: "spec.name", Message: "must not be empty"},
+		{Field: "spec.version", Message: "must be a semantic version"},
+	}
+	// Msg carries the wrapped errors of its parent, so the fields are reachable twice
+	err := ErrInvalidSkill.Err(fields).Msg("skill my-skill is invalid")
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set(RequestIDHeader, "req-1")
+	SendError(rec, err)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	var p Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "about:blank", p.Type)
+	assert.Equal(t, "Bad Request", p.Title)
+	assert.Equal(t, http.StatusBadRequest, p.Status)
+	assert.Equal(t, "invalid_skill", p.Code)
+	assert.Equal(t, "req-1", p.RequestID)
+	assert.Equal(t, []apperrors.FieldError(fields), p.Errors)
+	assert.Contains(t, p.Detail, "skill my-skill is invalid")
+	assert.Equal(t, p.Detail, p.Error)
+	assert.Equal(t, Failure, p.Result)
+}
+
+func TestErrorCodeFromStatus(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ErrRequestTooLarge(10).Send(rec)
+
+	var p Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, "request_entity_too_large", p.Code)
+	assert.Empty(t, p.RequestID)
+	assert.Empty(t, p.Errors)
+}
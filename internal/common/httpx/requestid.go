@@ -0,0 +1,20 @@
+package httpx
+
+import "context"
+
+// RequestIDHeader carries the ID of a request. Servers return it on every response and
+// accept it from callers, so that a request can be traced across services.
+const RequestIDHeader = "X-Tansive-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context that carries the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by the context, or an empty string.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
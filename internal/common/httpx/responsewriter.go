@@ -60,6 +60,11 @@ func (rw *ResponseWriter) Flush() {
 	}
 }
 
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // Hijack implements http.Hijacker if the underlying writer supports it.
 func (rw *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	hj, ok := rw.ResponseWriter.(http.Hijacker)
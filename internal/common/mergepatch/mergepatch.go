@@ -0,0 +1,60 @@
+// Package mergepatch implements JSON Merge Patch as defined in RFC 7386.
+// A patch is a JSON document describing the changes to be made to a target
+// document: object members in the patch replace or add members in the target,
+// null values remove members, and any non-object patch replaces the target.
+package mergepatch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ContentType is the media type registered for JSON merge patch documents.
+const ContentType = "application/merge-patch+json"
+
+var (
+	// ErrInvalidDocument is returned when the target document is not valid JSON.
+	ErrInvalidDocument = errors.New("invalid target document")
+	// ErrInvalidPatch is returned when the patch document is not valid JSON.
+	ErrInvalidPatch = errors.New("invalid merge patch")
+)
+
+// Apply applies the merge patch to doc and returns the patched document.
+// An empty doc is treated as null.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var target any
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, ErrInvalidDocument
+		}
+	}
+
+	var p any
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, ErrInvalidPatch
+	}
+
+	return json.Marshal(mergeValue(target, p))
+}
+
+// mergeValue is the MergePatch(Target, Patch) function from section 2 of RFC 7386.
+func mergeValue(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any)
+	}
+
+	for name, value := range patchObj {
+		if value == nil {
+			delete(targetObj, name)
+			continue
+		}
+		targetObj[name] = mergeValue(targetObj[name], value)
+	}
+	return targetObj
+}
@@ -0,0 +1,297 @@
+// Package metrics exposes the metrics of a server in the Prometheus text format. It
+// counts HTTP requests and their latency by route with a middleware, reports Go runtime
+// statistics, and lets each server add its own gauges and counters with collectors, such
+// as database pool statistics. The metrics are served on a separate listener so that they
+// are not exposed with the API.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the request latency histogram.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// unmatchedRoute labels requests that did not match a route.
+const unmatchedRoute = "unmatched"
+
+// Collector adds metrics to a scrape.
+type Collector func(w *Writer)
+
+// Registry holds the metrics of a server.
+type Registry struct {
+	namespace string
+
+	mu         sync.Mutex
+	requests   map[requestKey]int64
+	latencies  map[latencyKey]*histogram
+	collectors []Collector
+	handlers   map[string]http.Handler
+}
+
+type requestKey struct {
+	method, route, status string
+}
+
+type latencyKey struct {
+	method, route string
+}
+
+type histogram struct {
+	counts []int64 // per bucket of latencyBuckets, not cumulative
+	count  int64
+	sum    float64
+}
+
+// NewRegistry returns a registry whose HTTP metrics are named with the namespace, such
+// as catalogsrv_http_requests_total.
+func NewRegistry(namespace string) *Registry {
+	return &Registry{
+		namespace: namespace,
+		requests:  make(map[requestKey]int64),
+		latencies: make(map[latencyKey]*histogram),
+	}
+}
+
+// AddCollector registers a collector that runs on every scrape.
+func (m *Registry) AddCollector(c Collector) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.collectors = append(m.collectors, c)
+}
+
+// Handle registers a handler for a path of the metrics server besides /metrics, for
+// statistics that are not served in the Prometheus text format.
+func (m *Registry) Handle(path string, h http.Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.handlers == nil {
+		m.handlers = make(map[string]http.Handler)
+	}
+	m.handlers[path] = h
+}
+
+// Middleware counts requests by method, route and status, and records their latency. The
+// route is the pattern the request matched, such as /sessions/{sessionID}, so that the
+// number of series does not grow with the IDs in paths.
+func (m *Registry) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rw := httpx.NewResponseWriter(w)
+		defer func() {
+			route := unmatchedRoute
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				route = rctx.RoutePattern()
+			}
+			m.observe(r.Method, route, rw.Status(), time.Since(start))
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+func (m *Registry) observe(method, route string, status int, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[requestKey{method, route, strconv.Itoa(status)}]++
+	k := latencyKey{method, route}
+	h := m.latencies[k]
+	if h == nil {
+		h = &histogram{counts: make([]int64, len(latencyBuckets))}
+		m.latencies[k] = h
+	}
+	seconds := d.Seconds()
+	if i := sort.SearchFloat64s(latencyBuckets, seconds); i < len(latencyBuckets) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// NewServer returns a server that serves the metrics at /metrics on the port, along with the
+// handlers registered with Handle.
+func NewServer(port string, m *Registry) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	m.mu.Lock()
+	for path, h := range m.handlers {
+		mux.Handle(path, h)
+	}
+	m.mu.Unlock()
+	return &http.Server{
+		Addr:              ":" + port,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.Write(w)
+	})
+}
+
+// Write writes the metrics in the Prometheus text format.
+func (m *Registry) Write(out io.Writer) error {
+	w := &Writer{}
+	m.mu.Lock()
+	requests := m.namespace + "_http_requests_total"
+	w.describe(requests, "counter", "HTTP requests by method, route and status.")
+	for k, n := range m.requests {
+		w.sample(requests, float64(n), "method", k.method, "route", k.route, "status", k.status)
+	}
+	latency := m.namespace + "_http_request_duration_seconds"
+	w.describe(latency, "histogram", "Latency of HTTP requests by method and route.")
+	keys := make([]latencyKey, 0, len(m.latencies))
+	for k := range m.latencies {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b latencyKey) int {
+		return strings.Compare(a.route+" "+a.method, b.route+" "+b.method)
+	})
+	for _, k := range keys {
+		h := m.latencies[k]
+		var cumulative int64
+		for i, le := range latencyBuckets {
+			cumulative += h.counts[i]
+			w.sample(latency+"_bucket", float64(cumulative), "method", k.method, "route", k.route, "le", formatFloat(le))
+		}
+		w.sample(latency+"_bucket", float64(h.count), "method", k.method, "route", k.route, "le", "+Inf")
+		w.sample(latency+"_sum", h.sum, "method", k.method, "route", k.route)
+		w.sample(latency+"_count", float64(h.count), "method", k.method, "route", k.route)
+	}
+	collectors := slices.Clone(m.collectors)
+	m.mu.Unlock()
+
+	collectRuntime(w)
+	for _, c := range collectors {
+		c(w)
+	}
+	_, err := io.WriteString(out, w.String())
+	return err
+}
+
+// collectRuntime adds the statistics of the Go runtime, including the garbage collector.
+func collectRuntime(w *Writer) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	w.Gauge("go_goroutines", "Number of goroutines.", float64(runtime.NumGoroutine()))
+	w.Gauge("go_memstats_heap_alloc_bytes", "Bytes of allocated heap objects.", float64(ms.HeapAlloc))
+	w.Gauge("go_memstats_heap_inuse_bytes", "Bytes in in-use heap spans.", float64(ms.HeapInuse))
+	w.Gauge("go_memstats_sys_bytes", "Bytes of memory obtained from the OS.", float64(ms.Sys))
+	w.Counter("go_memstats_mallocs_total", "Heap objects allocated.", float64(ms.Mallocs))
+	w.Counter("go_gc_cycles_total", "Completed garbage collection cycles.", float64(ms.NumGC))
+	w.Counter("go_gc_pause_seconds_total", "Total time the garbage collector stopped the world.", float64(ms.PauseTotalNs)/1e9)
+	w.Gauge("go_gc_next_heap_bytes", "Heap size at which the next garbage collection runs.", float64(ms.NextGC))
+}
+
+// Writer accumulates the metrics of a scrape. Samples of the same metric are grouped
+// under its description.
+type Writer struct {
+	order    []string
+	families map[string]*family
+}
+
+type family struct {
+	typ, help string
+	samples   []string
+}
+
+// Gauge adds a sample of a gauge. Labels are given as name, value pairs.
+func (w *Writer) Gauge(name, help string, value float64, labels ...string) {
+	w.describe(name, "gauge", help)
+	w.sample(name, value, labels...)
+}
+
+// Counter adds a sample of a counter. Labels are given as name, value pairs.
+func (w *Writer) Counter(name, help string, value float64, labels ...string) {
+	w.describe(name, "counter", help)
+	w.sample(name, value, labels...)
+}
+
+func (w *Writer) describe(name, typ, help string) {
+	if w.families == nil {
+		w.families = make(map[string]*family)
+	}
+	if _, ok := w.families[name]; !ok {
+		w.families[name] = &family{typ: typ, help: help}
+		w.order = append(w.order, name)
+	}
+}
+
+// sample adds a sample of the family it belongs to; histogram samples have a suffix.
+func (w *Writer) sample(name string, value float64, labels ...string) {
+	f := w.families[name]
+	if f == nil {
+		for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+			if base, ok := strings.CutSuffix(name, suffix); ok && w.families[base] != nil {
+				f = w.families[base]
+				break
+			}
+		}
+	}
+	if f == nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "%s=\"%s\"", labels[i], labelEscaper.Replace(labels[i+1]))
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatFloat(value))
+	f.samples = append(f.samples, b.String())
+}
+
+// String returns the metrics in the Prometheus text format.
+func (w *Writer) String() string {
+	var b strings.Builder
+	for _, name := range w.order {
+		f := w.families[name]
+		// Series are sorted so scrapes are stable
+		samples := slices.Clone(f.samples)
+		if f.typ != "histogram" {
+			slices.Sort(samples)
+		}
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.typ)
+		for _, s := range samples {
+			b.WriteString(s)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// labelEscaper escapes label values as the text format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
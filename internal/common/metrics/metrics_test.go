@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	m := NewRegistry("test")
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Route("/sessions", func(r chi.Router) {
+		r.Get("/{sessionID}", func(w http.ResponseWriter, r *http.Request) {
+			if chi.URLParam(r, "sessionID") == "missing" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("ok"))
+		})
+	})
+
+	for _, path := range []string{"/sessions/a", "/sessions/b", "/sessions/missing", "/other"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain; version=0.0.4")
+	body := rec.Body.String()
+
+	assert.Contains(t, body, "# TYPE test_http_requests_total counter\n")
+	assert.Contains(t, body, `test_http_requests_total{method="GET",route="/sessions/{sessionID}",status="200"} 2`+"\n")
+	assert.Contains(t, body, `test_http_requests_total{method="GET",route="/sessions/{sessionID}",status="404"} 1`+"\n")
+	assert.Contains(t, body, `test_http_requests_total{method="GET",route="unmatched",status="404"} 1`+"\n")
+	assert.Contains(t, body, "# TYPE test_http_request_duration_seconds histogram\n")
+	assert.Contains(t, body, `test_http_request_duration_seconds_bucket{method="GET",route="/sessions/{sessionID}",le="+Inf"} 3`+"\n")
+	assert.Contains(t, body, `test_http_request_duration_seconds_count{method="GET",route="/sessions/{sessionID}"} 3`+"\n")
+	assert.Contains(t, body, "# TYPE go_goroutines gauge\n")
+	assert.Contains(t, body, "# TYPE go_gc_cycles_total counter\n")
+}
+
+func TestCollectors(t *testing.T) {
+	m := NewRegistry("test")
+	m.AddCollector(func(w *Writer) {
+		w.Gauge("test_pool_open_conns", "Open connections.", 3, "pool", "replica-1")
+		w.Gauge("test_pool_open_conns", "Open connections.", 5, "pool", "primary")
+		w.Counter("test_errors_total", "Errors.", 1, "message", "bad \"quote\"\nline")
+	})
+
+	var b strings.Builder
+	require.NoError(t, m.Write(&b))
+	body := b.String()
+
+	// Each family is described once, with its series sorted
+	assert.Equal(t, 1, strings.Count(body, "# TYPE test_pool_open_conns gauge"))
+	assert.Contains(t, body, "# HELP test_pool_open_conns Open connections.\n# TYPE test_pool_open_conns gauge\n"+
+		`test_pool_open_conns{pool="primary"} 5`+"\n"+
+		`test_pool_open_conns{pool="replica-1"} 3`+"\n")
+	assert.Contains(t, body, `test_errors_total{message="bad \"quote\"\nline"} 1`+"\n")
+}
+
+func TestServerHandlers(t *testing.T) {
+	m := NewRegistry("test")
+	m.Handle("/metrics/pools", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"pools":[]}`))
+	}))
+	srv := NewServer("0", m)
+
+	rec := httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics/pools", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `{"pools":[]}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// LimitRequestBody creates middleware that limits request bodies to limit bytes. A request
+// that declares a larger Content-Length is answered with a 413 error before its body is read;
+// otherwise handlers reading past the limit get an *http.MaxBytesError, which they report as
+// httpx.ErrRequestTooLarge. A limit of zero or less turns the limit off.
+func LimitRequestBody(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if limit <= 0 || r.Body == nil || r.Body == http.NoBody {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limit {
+				httpx.ErrRequestTooLarge(limit).Send(w)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
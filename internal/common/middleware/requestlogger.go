@@ -4,33 +4,36 @@
 package middleware
 
 import (
-	"context"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
 	"github.com/tansive/tansive-internal/internal/common/uuid"
 )
 
-// requestIdContextKey is a custom type for context key to store request IDs.
-type requestIdContextKey string
+// RequestIDHeader carries the ID of a request.
+const RequestIDHeader = httpx.RequestIDHeader
 
-const (
-	requestIdKey    = requestIdContextKey("requestId")
-	RequestIDHeader = "X-Tansive-Request-ID"
-)
+// maxRequestIdLength bounds the length of a request ID accepted from a caller.
+const maxRequestIdLength = 128
 
-// RequestLogger creates middleware that logs incoming requests and adds a unique request ID
+// RequestLogger creates middleware that logs incoming requests and adds a request ID
 // to both the request context and response headers. It logs request details including URL,
-// method, path, remote IP, and protocol. The request ID is used for request tracing.
+// method, path, remote IP, and protocol. The request ID is used for request tracing: an ID
+// sent by the caller in the X-Tansive-Request-ID header is kept, so a request can be
+// followed across services; otherwise a unique one is generated.
 func RequestLogger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 		ctx := r.Context()
 
-		requestID := newRequestId()
-		ctx = context.WithValue(ctx, requestIdKey, requestID)
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestId(requestID) {
+			requestID = newRequestId()
+		}
+		ctx = httpx.WithRequestID(ctx, requestID)
 		ctx = log.With().Str("request_id", requestID).Caller().Logger().WithContext(ctx)
 
 		w.Header().Set(RequestIDHeader, requestID)
@@ -59,6 +62,21 @@ func RequestLogger(next http.Handler) http.Handler {
 	})
 }
 
+// validRequestId reports whether a request ID sent by a caller can be used. IDs are
+// written to logs and headers, so only short IDs of printable ASCII without spaces are
+// accepted.
+func validRequestId(id string) bool {
+	if id == "" || len(id) > maxRequestIdLength {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
 // newRequestId generates a unique request identifier. It attempts to create a UUID first,
 // falling back to a timestamp-based ID if UUID generation fails.
 func newRequestId() string {
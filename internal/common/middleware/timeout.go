@@ -6,51 +6,194 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
 )
 
-// SetTimeout creates middleware that enforces a timeout for request handling. If the request
-// exceeds the specified duration, it returns a timeout error response. The timeout is added
-// to response headers for debugging purposes. The middleware handles panics and ensures
-// proper cleanup of resources.
+// SetTimeout creates middleware that enforces a timeout for request handling. If the handler
+// has not started its response when the timeout expires, the request context is canceled and
+// a 408 error response is sent; later writes by the handler fail with http.ErrHandlerTimeout.
+// Once the response has started, the timeout no longer applies, so that streaming responses
+// are bounded by the server's write deadline instead. The timeout is added to response headers
+// for debugging purposes. A panic in the handler is passed on to the enclosing middleware.
 func SetTimeout(timeout time.Duration) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			ctx, cancel := context.WithCancel(r.Context())
 			defer cancel()
 
-			rw := httpx.NewResponseWriter(w)
+			tw := &timeoutWriter{
+				w:       w,
+				header:  w.Header().Clone(),
+				started: make(chan struct{}),
+			}
+			tw.header.Set("X-Tansive-Timeout", timeout.String())
 			r = r.WithContext(ctx)
 
-			rw.Header().Set("X-Tansive-Timeout", timeout.String())
-
-			// Create a channel to signal when the handler is done
+			// The handler runs on its own goroutine so that the timeout can be answered
+			// while it is still running
 			done := make(chan struct{})
+			panicked := make(chan any, 1)
 			go func() {
 				defer func() {
-					if r := recover(); r != nil {
-						log.Ctx(ctx).Error().Msgf("panic in handler: %v", r)
+					if p := recover(); p != nil {
+						panicked <- p
+						return
 					}
 					close(done)
 				}()
-				next.ServeHTTP(rw, r)
+				next.ServeHTTP(tw, r)
 			}()
 
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+
 			select {
+			case p := <-panicked:
+				panic(p)
 			case <-done:
-				// Handler completed successfully
 				return
-			case <-ctx.Done():
-				// Only write error if headers haven't been sent yet
-				if !rw.Written() {
-					httpx.ErrRequestTimeout().Send(w)
+			case <-tw.started:
+			case <-timer.C:
+				if tw.timeout() {
+					cancel()
+					log.Ctx(ctx).Error().Str("timeout", timeout.String()).Msg("request timed out")
+					return
+				}
+			}
+
+			// The response has started; wait for the handler to finish it
+			select {
+			case p := <-panicked:
+				panic(p)
+			case <-done:
+			}
+		})
+	}
+}
+
+// RouteTimeouts holds the handler timeouts of a server. Routes are keyed by method and chi
+// route pattern, e.g. "POST /catalogs/import". A timeout of zero turns the timeout off.
+type RouteTimeouts struct {
+	Default time.Duration            // timeout of routes that are not listed
+	Routes  map[string]time.Duration // timeouts of individual routes
+}
+
+// HandlerTimeout creates middleware that applies SetTimeout with the timeout of the route the
+// request matches in routes. It is meant for the top of a router, before the route is known,
+// so routes is usually the router itself.
+func HandlerTimeout(routes chi.Routes, timeouts RouteTimeouts) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		// One handler per distinct timeout
+		handlers := make(map[time.Duration]http.Handler)
+		addHandler := func(timeout time.Duration) {
+			if _, ok := handlers[timeout]; !ok && timeout > 0 {
+				handlers[timeout] = SetTimeout(timeout)(next)
+			}
+		}
+		addHandler(timeouts.Default)
+		for _, timeout := range timeouts.Routes {
+			addHandler(timeout)
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := timeouts.Default
+			if len(timeouts.Routes) > 0 {
+				rctx := chi.NewRouteContext()
+				if routes.Match(rctx, r.Method, r.URL.Path) {
+					if t, ok := timeouts.Routes[r.Method+" "+rctx.RoutePattern()]; ok {
+						timeout = t
+					}
 				}
-				log.Ctx(ctx).Error().Msgf("request timed out")
+			}
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
 				return
 			}
+			handlers[timeout].ServeHTTP(w, r)
 		})
 	}
 }
+
+// timeoutWriter keeps the handler from writing to the response after its timeout has been
+// answered. The handler's headers are kept apart until the response starts, so that the
+// timeout response is not mixed with them.
+type timeoutWriter struct {
+	w       http.ResponseWriter
+	header  http.Header
+	started chan struct{} // closed when the handler starts its response
+
+	mu          sync.Mutex
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.writeHeader(code)
+}
+
+func (tw *timeoutWriter) writeHeader(code int) {
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	dst := tw.w.Header()
+	for k := range dst {
+		delete(dst, k)
+	}
+	for k, v := range tw.header {
+		dst[k] = v
+	}
+	tw.w.WriteHeader(code)
+	close(tw.started)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	tw.writeHeader(http.StatusOK)
+	return tw.w.Write(b)
+}
+
+// Flush implements http.Flusher if the underlying writer supports it.
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return
+	}
+	tw.writeHeader(http.StatusOK)
+	if f, ok := tw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.w
+}
+
+// timeout answers the request with a timeout error unless the handler has started its
+// response. It reports whether the request timed out.
+func (tw *timeoutWriter) timeout() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.wroteHeader {
+		return false
+	}
+	tw.timedOut = true
+	httpx.ErrRequestTimeout().Send(tw.w)
+	return true
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+func TestSetTimeout(t *testing.T) {
+	writeErr := make(chan error, 1)
+	slow := SetTimeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.Header().Set("X-Handler", "slow")
+		_, err := w.Write([]byte("late"))
+		writeErr <- err
+	}))
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set(httpx.RequestIDHeader, "req-1")
+	slow.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusRequestTimeout, rec.Code)
+	var p httpx.Problem
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &p))
+	assert.Equal(t, "req-1", p.RequestID)
+	assert.ErrorIs(t, <-writeErr, http.ErrHandlerTimeout)
+	assert.Empty(t, rec.Header().Get("X-Handler"))
+
+	// A response that has started is not cut off
+	streaming := SetTimeout(20 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("first\n"))
+		w.(http.Flusher).Flush()
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("second\n"))
+	}))
+	rec = httptest.NewRecorder()
+	streaming.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "first\nsecond\n", rec.Body.String())
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "20ms", rec.Header().Get("X-Tansive-Timeout"))
+
+	// Panics reach the enclosing middleware
+	panicking := SetTimeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	assert.PanicsWithValue(t, "boom", func() {
+		panicking.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(HandlerTimeout(r, RouteTimeouts{
+		Default: 20 * time.Millisecond,
+		Routes:  map[string]time.Duration{"POST /catalogs/{catalogName}/import": 0},
+	}))
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(50 * time.Millisecond):
+			w.Write([]byte("done"))
+		}
+	}
+	r.Route("/catalogs", func(r chi.Router) {
+		r.Post("/{catalogName}/import", slow)
+		r.Get("/{catalogName}", slow)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/catalogs/c1/import", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "done", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/catalogs/c1", nil))
+	assert.Equal(t, http.StatusRequestTimeout, rec.Code)
+}
+
+func TestLimitRequestBody(t *testing.T) {
+	var readErr error
+	h := LimitRequestBody(8)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, readErr = io.ReadAll(r.Body)
+	}))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789")))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	// Bodies of unknown length are cut off at the limit
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789"))
+	req.ContentLength = -1
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	var maxErr *http.MaxBytesError
+	assert.True(t, errors.As(readErr, &maxErr))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("01234567")))
+	assert.NoError(t, readErr)
+}
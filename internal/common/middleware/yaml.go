@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+	yamlv3 "gopkg.in/yaml.v3"
+	"sigs.k8s.io/yaml"
+)
+
+// YAMLContentType is the media type of YAML request and response bodies.
+const YAMLContentType = "application/yaml"
+
+// yamlMediaTypes are the media types accepted as YAML.
+var yamlMediaTypes = []string{YAMLContentType, "application/x-yaml", "text/yaml", "text/x-yaml"}
+
+// NegotiateYAML creates middleware that lets clients use YAML in place of JSON, converting at
+// the boundary so that handlers only deal with JSON:
+//
+//   - A request body sent with a YAML Content-Type is converted to JSON. Bodies holding several
+//     YAML documents are passed on unchanged, for the handlers that accept document streams.
+//   - A JSON response is converted to YAML when the Accept header prefers YAML over JSON.
+//     Other responses, including errors, are sent as they are.
+func NegotiateYAML(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isYAML(r.Header.Get("Content-Type")) && r.Body != nil {
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				var maxErr *http.MaxBytesError
+				if errors.As(err, &maxErr) {
+					httpx.ErrRequestTooLarge(maxErr.Limit).Send(w)
+					return
+				}
+				httpx.ErrUnableToReadRequest().Send(w)
+				return
+			}
+			converted, err := yamlToJSON(body)
+			if err != nil {
+				httpx.ErrInvalidRequest("unable to parse YAML: " + err.Error()).Send(w)
+				return
+			}
+			if converted != nil {
+				body = converted
+				r.Header.Set("Content-Type", "application/json")
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+		}
+
+		if !prefersYAML(r.Header.Get("Accept")) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		yw := &yamlResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(yw, r)
+		yw.finish(r)
+	})
+}
+
+// yamlToJSON converts a single YAML document to JSON. It returns nil if the body is empty or
+// holds several documents.
+func yamlToJSON(body []byte) ([]byte, error) {
+	decoder := yamlv3.NewDecoder(bytes.NewReader(body))
+	docs := 0
+	for {
+		var node yamlv3.Node
+		if err := decoder.Decode(&node); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		docs++
+	}
+	if docs != 1 {
+		return nil, nil
+	}
+	return yaml.YAMLToJSON(body)
+}
+
+// isYAML reports whether the content type is a YAML media type.
+func isYAML(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range yamlMediaTypes {
+		if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// prefersYAML reports whether the Accept header ranks a YAML media type at least as high as
+// JSON. Wildcards count as accepting JSON, so YAML is only served when asked for by name.
+func prefersYAML(accept string) bool {
+	if accept == "" {
+		return false
+	}
+	var yamlQ, jsonQ float64
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		switch {
+		case isYAML(mediaType):
+			yamlQ = max(yamlQ, q)
+		case mediaType == "application/json" || mediaType == "application/*" || mediaType == "*/*":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return yamlQ > 0 && yamlQ >= jsonQ
+}
+
+// yamlResponseWriter holds back a JSON response so that it can be sent as YAML. Responses of
+// other types are written through.
+type yamlResponseWriter struct {
+	http.ResponseWriter
+	status    int
+	decided   bool
+	buffering bool
+	buf       bytes.Buffer
+}
+
+func (w *yamlResponseWriter) WriteHeader(code int) {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.status = code
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	if mediaType == "application/json" && code != http.StatusNoContent && code != http.StatusNotModified {
+		w.buffering = true
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *yamlResponseWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher. A response held back for conversion is sent when the
+// handler returns.
+func (w *yamlResponseWriter) Flush() {
+	if w.buffering {
+		return
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *yamlResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// finish sends the held back response, converted to YAML. If it cannot be converted, it is
+// sent as JSON.
+func (w *yamlResponseWriter) finish(r *http.Request) {
+	if !w.buffering {
+		return
+	}
+	body := w.buf.Bytes()
+	if y, err := yaml.JSONToYAML(body); err == nil {
+		body = y
+		w.Header().Set("Content-Type", YAMLContentType)
+	} else {
+		log.Ctx(r.Context()).Error().Err(err).Msg("unable to convert response to YAML")
+	}
+	w.Header().Add("Vary", "Accept")
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tansive/tansive-internal/internal/common/httpx"
+)
+
+// echo responds with the request body as JSON, along with the content type it was given.
+func echo(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	w.Header().Set("X-Received-Content-Type", r.Header.Get("Content-Type"))
+	if r.Header.Get("Content-Type") != "application/json" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write(body)
+		return
+	}
+	httpx.SendJsonRsp(r.Context(), w, http.StatusCreated, body)
+}
+
+func serve(method, contentType, accept, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/resources", strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	NegotiateYAML(http.HandlerFunc(echo)).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNegotiateYAMLRequest(t *testing.T) {
+	rec := serve(http.MethodPost, "application/yaml", "", "kind: Resource\nmetadata:\n  name: r1\n")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("X-Received-Content-Type"))
+	assert.JSONEq(t, `{"kind":"Resource","metadata":{"name":"r1"}}`, rec.Body.String())
+
+	// Document streams are left to the handler
+	stream := "kind: Catalog\n---\nkind: Variant\n"
+	rec = serve(http.MethodPost, "application/x-yaml", "", stream)
+	assert.Equal(t, "application/x-yaml", rec.Header().Get("X-Received-Content-Type"))
+	assert.Equal(t, stream, rec.Body.String())
+
+	rec = serve(http.MethodPost, "application/yaml", "", "kind: [Resource\n")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, httpx.ProblemContentType, rec.Header().Get("Content-Type"))
+}
+
+func TestNegotiateYAMLResponse(t *testing.T) {
+	body := `{"kind":"Resource","metadata":{"name":"r1"}}`
+
+	rec := serve(http.MethodPost, "application/json", "application/yaml", body)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Equal(t, YAMLContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "kind: Resource\nmetadata:\n  name: r1\n", rec.Body.String())
+
+	rec = serve(http.MethodPost, "application/yaml", "application/yaml", "kind: Resource\n")
+	assert.Equal(t, "kind: Resource\n", rec.Body.String())
+
+	// Responses that are not JSON are sent as they are
+	rec = serve(http.MethodPost, "text/plain", "application/yaml", "plain")
+	assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "plain", rec.Body.String())
+
+	for _, accept := range []string{"", "*/*", "application/json", "application/json, application/yaml;q=0.5"} {
+		rec = serve(http.MethodPost, "application/json", accept, body)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"), accept)
+		assert.JSONEq(t, body, rec.Body.String(), accept)
+	}
+}
+
+func TestPrefersYAML(t *testing.T) {
+	assert.True(t, prefersYAML("application/yaml"))
+	assert.True(t, prefersYAML("text/yaml, */*"))
+	assert.True(t, prefersYAML("application/json;q=0.8, application/yaml"))
+	assert.False(t, prefersYAML("application/yaml;q=0, */*"))
+	assert.False(t, prefersYAML("text/html"))
+}
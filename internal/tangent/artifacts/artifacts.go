@@ -0,0 +1,447 @@
+// Package artifacts stores the files that sessions deposit in tangent. Skills write files
+// to a staging directory; after each execution they are collected into a store (local
+// disk or an S3 bucket) and listed with the session, within a size limit per artifact and
+// a quota per session. Artifacts expire after a configured time and are removed by a
+// periodic cleanup.
+package artifacts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+)
+
+var (
+	// ErrArtifactError is the base error for the package.
+	ErrArtifactError apperrors.Error = apperrors.New("artifact error").SetStatusCode(http.StatusInternalServerError)
+
+	// ErrArtifactNotFound is returned when a session has no artifact with the given ID.
+	ErrArtifactNotFound apperrors.Error = ErrArtifactError.New("artifact not found").SetStatusCode(http.StatusNotFound)
+
+	// ErrArtifactTooLarge is returned when an artifact exceeds the size limit.
+	ErrArtifactTooLarge apperrors.Error = ErrArtifactError.New("artifact too large").SetStatusCode(http.StatusRequestEntityTooLarge)
+
+	// ErrQuotaExceeded is returned when an artifact would exceed the quota of its session.
+	ErrQuotaExceeded apperrors.Error = ErrArtifactError.New("session artifact quota exceeded").SetStatusCode(http.StatusRequestEntityTooLarge)
+
+	// ErrInvalidName is returned for an artifact name that is empty or not a relative path.
+	ErrInvalidName apperrors.Error = ErrArtifactError.New("invalid artifact name").SetStatusCode(http.StatusBadRequest)
+)
+
+// cleanupInterval is how often expired artifacts are removed.
+const cleanupInterval = 10 * time.Minute
+
+// Artifact describes a file deposited by a session.
+type Artifact struct {
+	ID           string    `json:"id"`                     // unique artifact identifier
+	SessionID    string    `json:"sessionID"`              // session that deposited the artifact
+	Owner        string    `json:"owner,omitempty"`        // user the artifact is served to
+	InvocationID string    `json:"invocationID,omitempty"` // skill invocation that deposited the artifact
+	Name         string    `json:"name"`                   // slash-separated path the skill gave the file
+	Size         int64     `json:"size"`                   // size in bytes
+	ContentType  string    `json:"contentType"`            // media type, from the name's extension
+	SHA256       string    `json:"sha256"`                 // hex SHA-256 digest of the content
+	CreatedAt    time.Time `json:"createdAt"`              // when the artifact was stored
+	ExpiresAt    time.Time `json:"expiresAt"`              // when the artifact is removed
+}
+
+// key returns the key of the artifact's content in the store.
+func (a *Artifact) key() string {
+	return a.SessionID + "/" + a.ID
+}
+
+// Manager stores artifacts and keeps an index of them. The index is kept on local disk,
+// so that artifacts remain listed across restarts whichever store holds their content.
+type Manager struct {
+	store        Store
+	indexDir     string
+	maxSize      int64
+	sessionQuota int64
+	ttl          time.Duration
+
+	mu        sync.Mutex
+	bySession map[string][]*Artifact
+	reserved  map[string]int64 // bytes being uploaded, per session
+}
+
+// NewManager returns a manager that keeps its index in indexDir and artifact content in
+// the store. Artifacts already in the index are loaded.
+func NewManager(store Store, indexDir string, maxSize, sessionQuota int64, ttl time.Duration) (*Manager, error) {
+	if err := os.MkdirAll(indexDir, 0700); err != nil {
+		return nil, err
+	}
+	m := &Manager{
+		store:        store,
+		indexDir:     indexDir,
+		maxSize:      maxSize,
+		sessionQuota: sessionQuota,
+		ttl:          ttl,
+		bySession:    make(map[string][]*Artifact),
+		reserved:     make(map[string]int64),
+	}
+	err := filepath.WalkDir(indexDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || filepath.Ext(p) != ".json" {
+			return err
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		var a Artifact
+		if err := json.Unmarshal(b, &a); err != nil {
+			log.Warn().Err(err).Str("path", p).Msg("skipping unreadable artifact index entry")
+			return nil
+		}
+		m.bySession[a.SessionID] = append(m.bySession[a.SessionID], &a)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range m.bySession {
+		slices.SortFunc(list, func(a, b *Artifact) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	}
+	return m, nil
+}
+
+var defaultManager *Manager
+
+// Default returns the manager set up by Init, or nil if artifacts are not initialized.
+func Default() *Manager {
+	return defaultManager
+}
+
+// Init sets up the artifact manager from the [artifacts] section of the config and
+// removes expired artifacts until the context is cancelled.
+func Init(ctx context.Context) error {
+	c := config.Config().Artifacts
+	var store Store
+	var err error
+	switch c.Backend {
+	case config.ArtifactBackendS3:
+		store, err = NewS3Store(c.S3)
+	default:
+		store, err = NewLocalStore(filepath.Join(c.Dir, "objects"))
+	}
+	if err != nil {
+		return err
+	}
+	maxSize, err := c.GetMaxSize()
+	if err != nil {
+		return err
+	}
+	quota, err := c.GetSessionQuota()
+	if err != nil {
+		return err
+	}
+	ttl, err := c.GetTTL()
+	if err != nil {
+		return err
+	}
+	m, err := NewManager(store, filepath.Join(c.Dir, "index"), maxSize, quota, ttl)
+	if err != nil {
+		return err
+	}
+	defaultManager = m
+	go m.runCleanup(ctx, cleanupInterval)
+	return nil
+}
+
+// Put stores an artifact of the session read from r, served only to owner. The artifact
+// must fit within the size limit and the session's remaining quota.
+func (m *Manager) Put(ctx context.Context, sessionID, owner, invocationID, name string, r io.Reader) (*Artifact, apperrors.Error) {
+	name, apperr := cleanName(name)
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	// Spooled to a temporary file to learn the size and digest before storing
+	f, err := os.CreateTemp("", "tangent-artifact-*")
+	if err != nil {
+		return nil, ErrArtifactError.Msg("unable to create temporary file: " + err.Error())
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(r, m.maxSize+1))
+	if err != nil {
+		return nil, ErrArtifactError.Msg("unable to read artifact: " + err.Error())
+	}
+	if size > m.maxSize {
+		return nil, ErrArtifactTooLarge.Msg(name + " is larger than the limit of " + formatSize(m.maxSize))
+	}
+
+	// Reserved while the artifact is stored, so that concurrent uploads cannot together
+	// exceed the quota
+	m.mu.Lock()
+	if m.usedLocked(sessionID)+size > m.sessionQuota {
+		m.mu.Unlock()
+		return nil, ErrQuotaExceeded.Msg(name + " exceeds the session quota of " + formatSize(m.sessionQuota))
+	}
+	m.reserved[sessionID] += size
+	m.mu.Unlock()
+	committed := false
+	defer func() {
+		if !committed {
+			m.mu.Lock()
+			m.releaseLocked(sessionID, size)
+			m.mu.Unlock()
+		}
+	}()
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, ErrArtifactError.Msg(err.Error())
+	}
+
+	now := time.Now().UTC()
+	a := &Artifact{
+		ID:           uuid.New().String(),
+		SessionID:    sessionID,
+		Owner:        owner,
+		InvocationID: invocationID,
+		Name:         name,
+		Size:         size,
+		ContentType:  contentType(name),
+		SHA256:       hex.EncodeToString(h.Sum(nil)),
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(m.ttl),
+	}
+	if err := m.store.Put(ctx, a.key(), f, size); err != nil {
+		return nil, ErrArtifactError.Msg("unable to store artifact: " + err.Error())
+	}
+	if err := m.writeIndex(a); err != nil {
+		m.store.Delete(ctx, a.key())
+		return nil, ErrArtifactError.Msg("unable to index artifact: " + err.Error())
+	}
+
+	m.mu.Lock()
+	m.releaseLocked(sessionID, size)
+	m.bySession[sessionID] = append(m.bySession[sessionID], a)
+	m.mu.Unlock()
+	committed = true
+	return a, nil
+}
+
+// Collect stores the regular files under dir as artifacts of the session, named by their
+// path relative to dir and served only to owner. Files that cannot be stored, such as those
+// over the limits, are skipped and reported in the returned error.
+func (m *Manager) Collect(ctx context.Context, sessionID, owner, invocationID, dir string) ([]Artifact, error) {
+	var stored []Artifact
+	var errs []error
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := openRegular(dir, rel)
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		defer f.Close()
+		a, apperr := m.Put(ctx, sessionID, owner, invocationID, filepath.ToSlash(rel), f)
+		if apperr != nil {
+			errs = append(errs, apperr)
+			return nil
+		}
+		stored = append(stored, *a)
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return stored, errors.Join(errs...)
+}
+
+// openRegular opens the regular file at the relative path rel under dir. The skill may still
+// be changing the directory, so the file is opened without following symlinks and checked
+// through the opened descriptor rather than by its path.
+func openRegular(dir, rel string) (*os.File, error) {
+	f, err := openInDir(dir, rel)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, fmt.Errorf("%s is not a regular file", filepath.Join(dir, rel))
+	}
+	return f, nil
+}
+
+// List returns the artifacts of the session owned by owner that have not expired, oldest first.
+func (m *Manager) List(sessionID, owner string) []Artifact {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	list := []Artifact{}
+	for _, a := range m.bySession[sessionID] {
+		if a.Owner == owner && now.Before(a.ExpiresAt) {
+			list = append(list, *a)
+		}
+	}
+	return list
+}
+
+// Open returns an artifact of the session owned by owner and a reader of its content.
+func (m *Manager) Open(ctx context.Context, sessionID, owner, id string) (*Artifact, io.ReadCloser, apperrors.Error) {
+	m.mu.Lock()
+	i := slices.IndexFunc(m.bySession[sessionID], func(a *Artifact) bool { return a.ID == id && a.Owner == owner })
+	var a Artifact
+	if i >= 0 {
+		a = *m.bySession[sessionID][i]
+	}
+	m.mu.Unlock()
+	if i < 0 || !time.Now().Before(a.ExpiresAt) {
+		return nil, nil, ErrArtifactNotFound.Msg("no artifact " + id + " in session " + sessionID)
+	}
+	r, err := m.store.Get(ctx, a.key())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, ErrArtifactNotFound.Msg("content of artifact " + id + " is missing")
+	}
+	if err != nil {
+		return nil, nil, ErrArtifactError.Msg("unable to read artifact: " + err.Error())
+	}
+	return &a, r, nil
+}
+
+// Cleanup removes the artifacts that expired before now.
+func (m *Manager) Cleanup(ctx context.Context, now time.Time) {
+	m.mu.Lock()
+	var expired []*Artifact
+	for sessionID, list := range m.bySession {
+		kept := list[:0]
+		for _, a := range list {
+			if now.Before(a.ExpiresAt) {
+				kept = append(kept, a)
+			} else {
+				expired = append(expired, a)
+			}
+		}
+		if len(kept) == 0 {
+			delete(m.bySession, sessionID)
+		} else {
+			m.bySession[sessionID] = kept
+		}
+	}
+	m.mu.Unlock()
+
+	for _, a := range expired {
+		if err := m.store.Delete(ctx, a.key()); err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("artifact_id", a.ID).Msg("unable to delete expired artifact")
+		}
+		if err := os.Remove(m.indexPath(a)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Ctx(ctx).Warn().Err(err).Str("artifact_id", a.ID).Msg("unable to remove expired artifact from index")
+		}
+		os.Remove(filepath.Dir(m.indexPath(a))) // only succeeds once the session has no artifacts left
+	}
+	if len(expired) > 0 {
+		log.Ctx(ctx).Info().Int("count", len(expired)).Msg("removed expired artifacts")
+	}
+}
+
+func (m *Manager) runCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.Cleanup(ctx, now)
+		}
+	}
+}
+
+// usedLocked returns the bytes the session stores or is uploading. Must be called with m.mu held.
+func (m *Manager) usedLocked(sessionID string) int64 {
+	used := m.reserved[sessionID]
+	for _, a := range m.bySession[sessionID] {
+		used += a.Size
+	}
+	return used
+}
+
+// releaseLocked releases bytes reserved for an upload. Must be called with m.mu held.
+func (m *Manager) releaseLocked(sessionID string, size int64) {
+	m.reserved[sessionID] -= size
+	if m.reserved[sessionID] <= 0 {
+		delete(m.reserved, sessionID)
+	}
+}
+
+func (m *Manager) indexPath(a *Artifact) string {
+	return filepath.Join(m.indexDir, a.SessionID, a.ID+".json")
+}
+
+func (m *Manager) writeIndex(a *Artifact) error {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	p := m.indexPath(a)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0600)
+}
+
+// cleanName returns the name in canonical slash-separated form. The name must be a
+// relative path that stays within the artifact directory.
+func cleanName(name string) (string, apperrors.Error) {
+	name = path.Clean(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", ErrInvalidName.Msg("invalid artifact name: " + name)
+	}
+	return name, nil
+}
+
+func contentType(name string) string {
+	if t := mime.TypeByExtension(path.Ext(name)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// formatSize formats a size in bytes the way sizes are given in the config.
+func formatSize(n int64) string {
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}} {
+		if n >= unit.size && n%unit.size == 0 {
+			return strconv.FormatInt(n/unit.size, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(n, 10)
+}
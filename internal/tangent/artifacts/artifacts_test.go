@@ -0,0 +1,186 @@
+package artifacts
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+)
+
+func newTestManager(t *testing.T, store Store, dir string) *Manager {
+	m, err := NewManager(store, filepath.Join(dir, "index"), 10, 12, time.Hour)
+	require.NoError(t, err)
+	return m
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "objects"))
+	require.NoError(t, err)
+	m := newTestManager(t, store, dir)
+
+	a, apperr := m.Put(ctx, "s1", "user-1", "call-1", "reports/summary.txt", strings.NewReader("hello"))
+	require.NoError(t, apperr)
+	assert.Equal(t, int64(5), a.Size)
+	assert.Equal(t, "reports/summary.txt", a.Name)
+	assert.Contains(t, a.ContentType, "text/plain")
+	assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", a.SHA256)
+
+	got, r, apperr := m.Open(ctx, "s1", "user-1", a.ID)
+	require.NoError(t, apperr)
+	content, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "hello", string(content))
+	assert.Equal(t, a.ID, got.ID)
+	_, _, apperr = m.Open(ctx, "s2", "user-1", a.ID)
+	assert.ErrorIs(t, apperr, ErrArtifactNotFound)
+
+	// Artifacts are served only to their owner
+	_, _, apperr = m.Open(ctx, "s1", "user-2", a.ID)
+	assert.ErrorIs(t, apperr, ErrArtifactNotFound)
+	assert.Empty(t, m.List("s1", "user-2"))
+
+	// Limits
+	_, apperr = m.Put(ctx, "s1", "user-1", "", "big.bin", strings.NewReader(strings.Repeat("x", 11)))
+	assert.ErrorIs(t, apperr, ErrArtifactTooLarge)
+	_, apperr = m.Put(ctx, "s1", "user-1", "", "second.bin", strings.NewReader(strings.Repeat("x", 10)))
+	assert.ErrorIs(t, apperr, ErrQuotaExceeded)
+	_, apperr = m.Put(ctx, "s2", "user-1", "", "second.bin", strings.NewReader(strings.Repeat("x", 10)))
+	assert.NoError(t, apperr)
+	_, apperr = m.Put(ctx, "s1", "user-1", "", "../escape", strings.NewReader("x"))
+	assert.ErrorIs(t, apperr, ErrInvalidName)
+
+	// The index survives a restart
+	m = newTestManager(t, store, dir)
+	require.Len(t, m.List("s1", "user-1"), 1)
+	assert.Equal(t, a.ID, m.List("s1", "user-1")[0].ID)
+
+	// Expired artifacts are removed
+	m.Cleanup(ctx, time.Now().Add(2*time.Hour))
+	assert.Empty(t, m.List("s1", "user-1"))
+	_, err = store.Get(ctx, a.key())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Empty(t, newTestManager(t, store, dir).List("s2", "user-1"))
+}
+
+func TestCollect(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(dir, "objects"))
+	require.NoError(t, err)
+	m := newTestManager(t, store, dir)
+
+	staging := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(staging, "logs"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(staging, "out.json"), []byte(`{}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(staging, "logs", "run.log"), []byte("ok"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(staging, "huge.bin"), []byte(strings.Repeat("x", 11)), 0644))
+
+	stored, err := m.Collect(context.Background(), "s1", "user-1", "call-1", staging)
+	assert.ErrorIs(t, err, ErrArtifactTooLarge)
+	var names []string
+	for _, a := range stored {
+		names = append(names, a.Name)
+		assert.Equal(t, "call-1", a.InvocationID)
+	}
+	assert.ElementsMatch(t, []string{"out.json", "logs/run.log"}, names)
+}
+
+func TestOpenRegular(t *testing.T) {
+	outside := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0600))
+
+	staging := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(staging, "out.json"), []byte(`{}`), 0644))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "secret"), filepath.Join(staging, "link")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(staging, "dir")))
+	require.NoError(t, os.Mkdir(filepath.Join(staging, "sub"), 0755))
+
+	f, err := openRegular(staging, "out.json")
+	require.NoError(t, err)
+	f.Close()
+
+	// A file or directory swapped for a symlink after the walk is not followed
+	for _, rel := range []string{"link", filepath.Join("dir", "secret"), "sub"} {
+		f, err := openRegular(staging, rel)
+		if !assert.Error(t, err, rel) {
+			f.Close()
+		}
+	}
+}
+
+// fakeS3 is an in-memory bucket that checks requests are signed.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string]string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") || r.Header.Get("X-Amz-Date") == "" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		b, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = string(b)
+	case http.MethodGet:
+		o, ok := f.objects[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, o)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	bucket := &fakeS3{objects: make(map[string]string)}
+	srv := httptest.NewServer(bucket)
+	defer srv.Close()
+
+	store, err := NewS3Store(config.S3Config{
+		Endpoint:        srv.URL,
+		Region:          "us-east-1",
+		Bucket:          "artifacts",
+		Prefix:          "tangent/",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		PathStyle:       true,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "s1/a1", strings.NewReader("hello"), 5))
+	assert.Equal(t, "hello", bucket.objects["/artifacts/tangent/s1/a1"])
+
+	r, err := store.Get(ctx, "s1/a1")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "hello", string(content))
+
+	require.NoError(t, store.Delete(ctx, "s1/a1"))
+	_, err = store.Get(ctx, "s1/a1")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = NewS3Store(config.S3Config{Endpoint: srv.URL, Region: "us-east-1", Bucket: "artifacts"})
+	if os.Getenv("AWS_ACCESS_KEY_ID") == "" {
+		assert.Error(t, err)
+	}
+}
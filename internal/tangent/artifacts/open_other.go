@@ -0,0 +1,13 @@
+//go:build !unix
+
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// openInDir opens the file at the relative path rel under dir for reading.
+func openInDir(dir, rel string) (*os.File, error) {
+	return os.Open(filepath.Join(dir, rel))
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// openInDir opens the file at the relative path rel under dir for reading, one path
+// component at a time without following symlinks, so that a path swapped for a symlink
+// cannot lead outside dir. The file is opened non-blocking, so that a FIFO does not block.
+func openInDir(dir, rel string) (*os.File, error) {
+	p := filepath.Join(dir, rel)
+	fd, err := unix.Open(dir, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: dir, Err: err}
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i, part := range parts {
+		flags := unix.O_RDONLY | unix.O_NOFOLLOW | unix.O_CLOEXEC
+		if i < len(parts)-1 {
+			flags |= unix.O_DIRECTORY
+		} else {
+			flags |= unix.O_NONBLOCK
+		}
+		next, err := unix.Openat(fd, part, flags, 0)
+		unix.Close(fd)
+		if err != nil {
+			return nil, &os.PathError{Op: "open", Path: p, Err: err}
+		}
+		fd = next
+	}
+	return os.NewFile(uintptr(fd), p), nil
+}
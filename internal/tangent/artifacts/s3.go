@@ -0,0 +1,185 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+)
+
+// unsignedPayload is the payload hash of requests whose body is not signed. The body of
+// an upload is streamed, so its hash is not known when the request is signed.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3Store keeps artifacts as objects in an S3 or S3-compatible bucket. Requests are
+// signed with AWS Signature Version 4.
+type s3Store struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	prefix          string
+	accessKeyID     string
+	secretAccessKey string
+	pathStyle       bool
+	client          *http.Client
+}
+
+// NewS3Store returns a store that keeps artifacts in the bucket of the configuration.
+// Credentials missing from the configuration are read from the standard AWS environment
+// variables.
+func NewS3Store(c config.S3Config) (Store, error) {
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint: %s", c.Endpoint)
+	}
+	s := &s3Store{
+		endpoint:        endpoint,
+		region:          c.Region,
+		bucket:          c.Bucket,
+		prefix:          c.Prefix,
+		accessKeyID:     c.AccessKeyID,
+		secretAccessKey: c.SecretAccessKey,
+		pathStyle:       c.PathStyle,
+		client:          &http.Client{Timeout: 5 * time.Minute},
+	}
+	if s.accessKeyID == "" {
+		s.accessKeyID = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if s.secretAccessKey == "" {
+		s.secretAccessKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if s.accessKeyID == "" || s.secretAccessKey == "" {
+		return nil, fmt.Errorf("s3 credentials are not configured")
+	}
+	return s, nil
+}
+
+// objectURL returns the URL of the object stored under the key.
+func (s *s3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	objectPath := "/" + s.prefix + key
+	if s.pathStyle {
+		u.Path = "/" + s.bucket + objectPath
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = objectPath
+	}
+	return &u
+}
+
+func (s *s3Store) do(ctx context.Context, method, key string, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	s.sign(req, time.Now().UTC())
+	return s.client.Do(req)
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	rsp, err := s.do(ctx, http.MethodPut, key, r, size)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		return s3Error(rsp)
+	}
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	rsp, err := s.do(ctx, http.MethodGet, key, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if rsp.StatusCode == http.StatusNotFound {
+		rsp.Body.Close()
+		return nil, fmt.Errorf("s3 object %s: %w", key, os.ErrNotExist)
+	}
+	if rsp.StatusCode != http.StatusOK {
+		defer rsp.Body.Close()
+		return nil, s3Error(rsp)
+	}
+	return rsp.Body, nil
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	rsp, err := s.do(ctx, http.MethodDelete, key, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+	// S3 answers 204 whether or not the object existed
+	if rsp.StatusCode != http.StatusNoContent && rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNotFound {
+		return s3Error(rsp)
+	}
+	return nil
+}
+
+func s3Error(rsp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(rsp.Body, 1024))
+	return fmt.Errorf("s3 %s %s: %s: %s", rsp.Request.Method, rsp.Request.URL.Path, rsp.Status, strings.TrimSpace(string(body)))
+}
+
+// sign adds the AWS Signature Version 4 authorization to the request.
+func (s *s3Store) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + unsignedPayload,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
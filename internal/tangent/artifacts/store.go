@@ -0,0 +1,72 @@
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Store holds the contents of artifacts. Keys are slash-separated paths.
+type Store interface {
+	// Put stores the content under the key, replacing any content there.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+
+	// Get opens the content stored under the key.
+	// Returns an error wrapping os.ErrNotExist if there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the content stored under the key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// localStore keeps artifacts as files under a directory.
+type localStore struct {
+	dir string
+}
+
+// NewLocalStore returns a store that keeps artifacts as files under dir.
+func NewLocalStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &localStore{dir: dir}, nil
+}
+
+func (s *localStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+func (s *localStore) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Written to a temporary file first, so a reader never sees a partial artifact
+	f, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(s.path(key))
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
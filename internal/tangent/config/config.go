@@ -5,10 +5,12 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/common/certs"
 )
 
@@ -39,35 +41,189 @@ func (a *AuthConfig) GetTokenExpiryOrDefault() time.Duration {
 
 // TansiveServerConfig holds tansive server related configuration
 type TansiveServerConfig struct {
-	URL string `toml:"url"` // Tansive server URL
+	URL               string `toml:"url"`                // Tansive server URL
+	HeartbeatInterval string `toml:"heartbeat_interval"` // How often to send heartbeats
 }
 
 func (t *TansiveServerConfig) GetURL() string {
 	return t.URL
 }
 
+// GetHeartbeatInterval returns the heartbeat interval as time.Duration
+func (t *TansiveServerConfig) GetHeartbeatInterval() (time.Duration, error) {
+	return ParseDuration(t.HeartbeatInterval)
+}
+
+// SessionConfig holds session lifecycle related configuration
+type SessionConfig struct {
+	Timeout   string `toml:"timeout"`   // Longest a session may run
+	Retention string `toml:"retention"` // How long ended sessions remain listed
+}
+
+const (
+	DefaultSessionTimeout   = "1h"
+	DefaultSessionRetention = "1h"
+
+	DefaultHeartbeatInterval = "30s"
+)
+
+// GetTimeout returns the session timeout as time.Duration
+func (s *SessionConfig) GetTimeout() (time.Duration, error) {
+	return ParseDuration(s.Timeout)
+}
+
+// GetRetention returns the session retention as time.Duration
+func (s *SessionConfig) GetRetention() (time.Duration, error) {
+	return ParseDuration(s.Retention)
+}
+
+// ArtifactsConfig holds artifact storage related configuration
+type ArtifactsConfig struct {
+	Backend      string   `toml:"backend"`       // Where artifacts are stored: local or s3
+	Dir          string   `toml:"dir"`           // Directory for the artifact index and local objects
+	MaxSize      string   `toml:"max_size"`      // Largest artifact, e.g. "100M"
+	SessionQuota string   `toml:"session_quota"` // Most bytes of artifacts one session may store
+	TTL          string   `toml:"ttl"`           // How long artifacts are kept
+	S3           S3Config `toml:"s3"`            // S3 backend settings
+}
+
+// S3Config holds the settings of an S3 or S3-compatible bucket
+type S3Config struct {
+	Endpoint        string `toml:"endpoint"`          // Endpoint URL, e.g. "https://s3.us-east-1.amazonaws.com"
+	Region          string `toml:"region"`            // Region of the bucket
+	Bucket          string `toml:"bucket"`            // Bucket name
+	Prefix          string `toml:"prefix"`            // Prefix of the object keys
+	AccessKeyID     string `toml:"access_key_id"`     // Access key; AWS_ACCESS_KEY_ID if empty
+	SecretAccessKey string `toml:"secret_access_key"` // Secret key; AWS_SECRET_ACCESS_KEY if empty
+	PathStyle       bool   `toml:"path_style"`        // Address the bucket in the path instead of the host name
+}
+
+const (
+	ArtifactBackendLocal = "local"
+	ArtifactBackendS3    = "s3"
+
+	DefaultArtifactMaxSize      = "100M"
+	DefaultArtifactSessionQuota = "1G"
+	DefaultArtifactTTL          = "7d"
+)
+
+// GetMaxSize returns the largest artifact size in bytes
+func (a *ArtifactsConfig) GetMaxSize() (int64, error) {
+	return ParseSize(a.MaxSize)
+}
+
+// GetSessionQuota returns the artifact quota of a session in bytes
+func (a *ArtifactsConfig) GetSessionQuota() (int64, error) {
+	return ParseSize(a.SessionQuota)
+}
+
+// GetTTL returns how long artifacts are kept as time.Duration
+func (a *ArtifactsConfig) GetTTL() (time.Duration, error) {
+	return ParseDuration(a.TTL)
+}
+
+// HTTPConfig holds the timeouts of the HTTP server. Durations of "0s" turn a timeout off.
+type HTTPConfig struct {
+	ReadHeaderTimeout string            `toml:"read_header_timeout"` // Time to read the headers of a request
+	ReadTimeout       string            `toml:"read_timeout"`        // Time to read a whole request, including its body
+	WriteTimeout      string            `toml:"write_timeout"`       // Time to write a response; streaming responses clear it
+	IdleTimeout       string            `toml:"idle_timeout"`        // Time an idle keep-alive connection is kept open
+	HandlerTimeout    string            `toml:"handler_timeout"`     // Time a handler has to start its response before a 408 is sent
+	RouteTimeouts     map[string]string `toml:"route_timeouts"`      // Handler timeouts of routes, keyed by method and route pattern, e.g. "POST /sessions"
+}
+
+const (
+	DefaultReadHeaderTimeout  = "5s"
+	DefaultReadTimeout        = "30s"
+	DefaultWriteTimeout       = "0s" // sessions stream their output for as long as they run
+	DefaultIdleTimeout        = "120s"
+	DefaultHandlerTimeout     = "30s"
+	DefaultMaxRequestBodySize = 1 << 20
+)
+
+// GetReadHeaderTimeout returns the header read timeout as time.Duration
+func (h *HTTPConfig) GetReadHeaderTimeout() (time.Duration, error) {
+	return ParseDuration(h.ReadHeaderTimeout)
+}
+
+// GetReadTimeout returns the request read timeout as time.Duration
+func (h *HTTPConfig) GetReadTimeout() (time.Duration, error) {
+	return ParseDuration(h.ReadTimeout)
+}
+
+// GetWriteTimeout returns the response write timeout as time.Duration
+func (h *HTTPConfig) GetWriteTimeout() (time.Duration, error) {
+	return ParseDuration(h.WriteTimeout)
+}
+
+// GetIdleTimeout returns the idle connection timeout as time.Duration
+func (h *HTTPConfig) GetIdleTimeout() (time.Duration, error) {
+	return ParseDuration(h.IdleTimeout)
+}
+
+// GetHandlerTimeout returns the default handler timeout as time.Duration
+func (h *HTTPConfig) GetHandlerTimeout() (time.Duration, error) {
+	return ParseDuration(h.HandlerTimeout)
+}
+
+// GetRouteTimeouts returns the handler timeouts of routes as time.Duration, keyed by method
+// and route pattern
+func (h *HTTPConfig) GetRouteTimeouts() (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(h.RouteTimeouts))
+	for route, timeout := range h.RouteTimeouts {
+		d, err := ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", route, err)
+		}
+		timeouts[route] = d
+	}
+	return timeouts, nil
+}
+
+// RunnerSettings holds the settings of a runner, as given in its [runners."<id>"] section.
+// The keys are interpreted by the runner, except for "enabled", which the registry uses to
+// turn a runner off.
+type RunnerSettings map[string]any
+
 // ConfigParam holds all configuration parameters for the tangent service
 type ConfigParam struct {
 	// Configuration version
 	FormatVersion string `toml:"format_version"` // Version of this configuration file format
 
 	// Server configuration
-	ServerHostName string `toml:"server_hostname"` // Hostname for the server in the format of "hostname:port"
-	ServerPort     string `toml:"server_port"`     // Port for the server
-	HandleCORS     bool   `toml:"handle_cors"`     // Whether to handle CORS
-	WorkingDir     string `toml:"working_dir"`     // Working directory for the server
-	SupportTLS     bool   `toml:"support_tls"`     // Whether to support TLS
-	TLSCertPEM     []byte `toml:"-"`               // PEM encoded TLS certificate
-	TLSKeyPEM      []byte `toml:"-"`               // PEM encoded TLS key
+	ServerHostName     string `toml:"server_hostname"`       // Hostname for the server in the format of "hostname:port"
+	ServerPort         string `toml:"server_port"`           // Port for the server
+	MetricsPort        string `toml:"metrics_port"`          // Port for Prometheus metrics at /metrics; disabled if empty
+	HandleCORS         bool   `toml:"handle_cors"`           // Whether to handle CORS
+	MaxRequestBodySize int64  `toml:"max_request_body_size"` // Maximum size of request body in bytes
+	WorkingDir         string `toml:"working_dir"`           // Working directory for the server
+	SupportTLS         bool   `toml:"support_tls"`           // Whether to support TLS
+	TLSCertFile        string `toml:"tls_cert_file"`         // Path to TLS certificate file
+	TLSKeyFile         string `toml:"tls_key_file"`          // Path to TLS key file
+	TLSClientCAFile    string `toml:"tls_client_ca_file"`    // Path to CA certificates that client certificates must be signed by
+	TLSCertPEM         []byte `toml:"-"`                     // PEM encoded TLS certificate
+	TLSKeyPEM          []byte `toml:"-"`                     // PEM encoded TLS key
+
+	// HTTP server timeouts
+	HTTP HTTPConfig `toml:"http"`
 
 	// Stdio runner configuration
 	StdioRunner StdioRunnerConfig `toml:"stdio_runner"`
 
+	// Runner configuration, keyed by runner ID
+	Runners map[string]RunnerSettings `toml:"runners"`
+
 	// Auth configuration
 	Auth AuthConfig `toml:"auth"`
 
 	// Tansive server configuration
 	TansiveServer TansiveServerConfig `toml:"tansive_server"`
+
+	// Session configuration
+	Session SessionConfig `toml:"session"`
+
+	// Artifact storage configuration
+	Artifacts ArtifactsConfig `toml:"artifacts"`
 }
 
 var cfg *ConfigParam
@@ -89,6 +245,7 @@ func GetURL() string {
 // - d: days
 // - h: hours
 // - m: minutes
+// - s: seconds
 func ParseDuration(input string) (time.Duration, error) {
 	if len(input) < 2 {
 		return 0, fmt.Errorf("invalid input format")
@@ -111,6 +268,8 @@ func ParseDuration(input string) (time.Duration, error) {
 		duration = time.Duration(value) * time.Hour
 	case "m":
 		duration = time.Duration(value) * time.Minute
+	case "s":
+		duration = time.Duration(value) * time.Second
 	case "y":
 		// Assuming 1 year = 365 days for simplicity
 		duration = time.Duration(value) * 365 * 24 * time.Hour
@@ -121,6 +280,30 @@ func ParseDuration(input string) (time.Duration, error) {
 	return duration, nil
 }
 
+// ParseSize parses a size in bytes with an optional K, M or G suffix, e.g. "512M"
+func ParseSize(input string) (int64, error) {
+	if input == "" {
+		return 0, fmt.Errorf("invalid input format")
+	}
+	mult := int64(1)
+	switch input[len(input)-1] {
+	case 'K', 'k':
+		mult = 1 << 10
+	case 'M', 'm':
+		mult = 1 << 20
+	case 'G', 'g':
+		mult = 1 << 30
+	}
+	if mult > 1 {
+		input = input[:len(input)-1]
+	}
+	value, err := strconv.ParseInt(input, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size: %s", input)
+	}
+	return value * mult, nil
+}
+
 // ValidateConfig checks if all required configuration values are present and valid
 func ValidateConfig(cfg *ConfigParam) error {
 	// Check if the config file format version is supported
@@ -145,6 +328,39 @@ func ValidateConfig(cfg *ConfigParam) error {
 	if cfg.TansiveServer.URL == "" {
 		return fmt.Errorf("tansive_server.url is required")
 	}
+	if cfg.TansiveServer.HeartbeatInterval == "" {
+		cfg.TansiveServer.HeartbeatInterval = DefaultHeartbeatInterval
+	}
+	if d, err := ParseDuration(cfg.TansiveServer.HeartbeatInterval); err != nil {
+		return fmt.Errorf("invalid tansive_server.heartbeat_interval: %v", err)
+	} else if d < time.Second {
+		return fmt.Errorf("tansive_server.heartbeat_interval must be at least 1s")
+	}
+
+	// Session validation
+	if cfg.Session.Timeout == "" {
+		cfg.Session.Timeout = DefaultSessionTimeout
+	}
+	if _, err := ParseDuration(cfg.Session.Timeout); err != nil {
+		return fmt.Errorf("invalid session.timeout: %v", err)
+	}
+	if cfg.Session.Retention == "" {
+		cfg.Session.Retention = DefaultSessionRetention
+	}
+	if _, err := ParseDuration(cfg.Session.Retention); err != nil {
+		return fmt.Errorf("invalid session.retention: %v", err)
+	}
+
+	// HTTP validation
+	if cfg.MaxRequestBodySize == 0 {
+		cfg.MaxRequestBodySize = DefaultMaxRequestBodySize
+	}
+	if cfg.MaxRequestBodySize < 0 {
+		return fmt.Errorf("max_request_body_size must not be negative")
+	}
+	if err := validateHTTPConfig(&cfg.HTTP); err != nil {
+		return err
+	}
 
 	if cfg.WorkingDir == "" {
 		homeDir, err := os.UserHomeDir()
@@ -157,6 +373,10 @@ func ValidateConfig(cfg *ConfigParam) error {
 		}
 	}
 
+	if err := validateArtifactsConfig(cfg); err != nil {
+		return err
+	}
+
 	if cfg.StdioRunner.ScriptDir == "" {
 		cwd, err := os.Getwd()
 		if err != nil {
@@ -166,17 +386,113 @@ func ValidateConfig(cfg *ConfigParam) error {
 	}
 
 	if cfg.SupportTLS {
-		certPEM, keyPEM, err := certs.GenerateSelfSignedECDSACert(cfg.ServerHostName, 365*24*time.Hour)
-		if err != nil {
-			return fmt.Errorf("error generating self-signed certificate: %v", err)
+		var err error
+		var certPEM []byte
+		var keyPEM []byte
+		if cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
+			certPEM, err = os.ReadFile(cfg.TLSCertFile)
+			if err != nil {
+				return fmt.Errorf("error reading tls cert file: %v", err)
+			}
+			keyPEM, err = os.ReadFile(cfg.TLSKeyFile)
+			if err != nil {
+				return fmt.Errorf("error reading tls key file: %v", err)
+			}
+		} else {
+			certPEM, keyPEM, err = certs.GenerateSelfSignedECDSACert(cfg.ServerHostName, 365*24*time.Hour)
+			if err != nil {
+				return fmt.Errorf("error generating self-signed certificate: %v", err)
+			}
 		}
 		cfg.TLSCertPEM = certPEM
 		cfg.TLSKeyPEM = keyPEM
+		if cfg.TLSClientCAFile != "" {
+			if _, err := os.Stat(cfg.TLSClientCAFile); err != nil {
+				return fmt.Errorf("error reading tls client ca file: %v", err)
+			}
+		}
 	}
 
 	return nil
 }
 
+// validateHTTPConfig applies the defaults of the server timeouts and checks them.
+func validateHTTPConfig(h *HTTPConfig) error {
+	timeouts := []struct {
+		name  string
+		value *string
+		def   string
+	}{
+		{"read_header_timeout", &h.ReadHeaderTimeout, DefaultReadHeaderTimeout},
+		{"read_timeout", &h.ReadTimeout, DefaultReadTimeout},
+		{"write_timeout", &h.WriteTimeout, DefaultWriteTimeout},
+		{"idle_timeout", &h.IdleTimeout, DefaultIdleTimeout},
+		{"handler_timeout", &h.HandlerTimeout, DefaultHandlerTimeout},
+	}
+	for _, t := range timeouts {
+		if *t.value == "" {
+			*t.value = t.def
+		}
+		if d, err := ParseDuration(*t.value); err != nil || d < 0 {
+			return fmt.Errorf("invalid http.%s: %s", t.name, *t.value)
+		}
+	}
+	// A handler timing out after the write deadline could not send its 408
+	write, _ := h.GetWriteTimeout()
+	handler, _ := h.GetHandlerTimeout()
+	if write > 0 && handler >= write {
+		return fmt.Errorf("http.handler_timeout must be shorter than http.write_timeout")
+	}
+	for route, timeout := range h.RouteTimeouts {
+		method, pattern, ok := strings.Cut(route, " ")
+		if !ok || method == "" || method != strings.ToUpper(method) || !strings.HasPrefix(pattern, "/") {
+			return fmt.Errorf("invalid http.route_timeouts route: %q, expected a method and a route pattern such as \"POST /sessions\"", route)
+		}
+		if d, err := ParseDuration(timeout); err != nil || d < 0 {
+			return fmt.Errorf("invalid http.route_timeouts timeout for %s: %s", route, timeout)
+		}
+	}
+	return nil
+}
+
+func validateArtifactsConfig(cfg *ConfigParam) error {
+	a := &cfg.Artifacts
+	if a.Backend == "" {
+		a.Backend = ArtifactBackendLocal
+	}
+	if a.Dir == "" {
+		a.Dir = filepath.Join(cfg.WorkingDir, "artifacts")
+	}
+	if a.MaxSize == "" {
+		a.MaxSize = DefaultArtifactMaxSize
+	}
+	if _, err := a.GetMaxSize(); err != nil {
+		return fmt.Errorf("invalid artifacts.max_size: %v", err)
+	}
+	if a.SessionQuota == "" {
+		a.SessionQuota = DefaultArtifactSessionQuota
+	}
+	if _, err := a.GetSessionQuota(); err != nil {
+		return fmt.Errorf("invalid artifacts.session_quota: %v", err)
+	}
+	if a.TTL == "" {
+		a.TTL = DefaultArtifactTTL
+	}
+	if _, err := a.GetTTL(); err != nil {
+		return fmt.Errorf("invalid artifacts.ttl: %v", err)
+	}
+	switch a.Backend {
+	case ArtifactBackendLocal:
+	case ArtifactBackendS3:
+		if a.S3.Endpoint == "" || a.S3.Bucket == "" || a.S3.Region == "" {
+			return fmt.Errorf("artifacts.s3 requires endpoint, region and bucket")
+		}
+	default:
+		return fmt.Errorf("invalid artifacts.backend: %s", a.Backend)
+	}
+	return nil
+}
+
 // LoadConfig loads configuration from a file
 func LoadConfig(filename string) error {
 	if filename == "" {
@@ -228,7 +544,7 @@ func TestInit(t *testing.T) {
 	if err := LoadConfig(filepath.Join(projectRoot, "tangent.conf")); err != nil {
 		panic(fmt.Errorf("error loading config: %v", err))
 	}
-	RegisterTangent()
+	RegisterTangent("", []catcommon.RunnerID{catcommon.StdioRunnerID})
 	t.Cleanup(func() {
 		if isTestMode {
 			deleteRuntimeConfig()
@@ -3,6 +3,7 @@ package config
 import (
 	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -130,9 +131,9 @@ func RuntimeInit() {
 }
 
 // RegisterTangent registers this Tangent instance with the catalog server.
-// Sends registration request with capabilities and public keys.
-// Returns an error if registration fails after retry attempts.
-func RegisterTangent() error {
+// Sends registration request with the version, the runners it supports as capabilities,
+// and public keys. Returns an error if registration fails after retry attempts.
+func RegisterTangent(version string, capabilities []catcommon.RunnerID) error {
 	if runtimeConfig.Registered {
 		log.Info().Msg("tangent already registered. Updating...")
 	}
@@ -140,11 +141,10 @@ func RegisterTangent() error {
 	tangentInfo := &srvtangent.TangentInfo{
 		ID:                     runtimeConfig.TangentID,
 		URL:                    GetURL(),
+		Version:                version,
 		PublicKeyAccessKey:     runtimeConfig.AccessKey.PublicKey,
 		PublicKeyLogSigningKey: runtimeConfig.LogSigningKey.PublicKey,
-		Capabilities: []catcommon.RunnerID{
-			catcommon.StdioRunnerID,
-		},
+		Capabilities:           capabilities,
 	}
 
 	client := getHTTPClient(&clientConfig{
@@ -188,6 +188,34 @@ func RegisterTangent() error {
 	return saveRuntimeConfig()
 }
 
+// SendHeartbeat reports to the catalog server that this Tangent instance is connected.
+// Returns ErrNotRegistered if the catalog server does not know the instance, in which
+// case it should register again.
+func SendHeartbeat(hb *srvtangent.Heartbeat) error {
+	client := getHTTPClient(&clientConfig{
+		serverURL: Config().TansiveServer.GetURL(),
+	})
+
+	req, err := json.Marshal(hb)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodPost,
+		Path:   "/tangents/" + runtimeConfig.TangentID.String() + "/heartbeat",
+		Body:   req,
+	})
+	var httpErr *httpclient.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return ErrNotRegistered
+	}
+	return err
+}
+
+// ErrNotRegistered is returned when the catalog server has no record of this Tangent instance.
+var ErrNotRegistered = errors.New("tangent is not registered")
+
 // saveRuntimeConfig persists runtime configuration to storage.
 // Saves the current runtime configuration to JSON file.
 // Returns an error if file creation or encoding fails.
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/tangent/test"
 )
 
@@ -11,7 +12,7 @@ func TestRegisterTangent(t *testing.T) {
 	_ = test.SetupTest(t)
 	SetTestMode(true)
 	TestInit(t)
-	RegisterTangent()
+	RegisterTangent("0.1.0", []catcommon.RunnerID{catcommon.StdioRunnerID})
 	runtimeConfig := GetRuntimeConfig()
 	assert.NotNil(t, runtimeConfig)
 	assert.True(t, runtimeConfig.Registered)
@@ -7,7 +7,7 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
-	"github.com/tansive/tansive-internal/internal/tangent/eventbus"
+	"github.com/tansive/tansive-internal/internal/common/eventbus"
 )
 
 // LogWriter is a zerolog-compatible writer that sends logs to an EventBus topic.
@@ -6,7 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
-	"github.com/tansive/tansive-internal/internal/tangent/eventbus"
+	"github.com/tansive/tansive-internal/internal/common/eventbus"
 )
 
 func TestLogWriter(t *testing.T) {
@@ -0,0 +1,8 @@
+// Package builtin registers the runners that ship with tangent.
+// Importing it for its side effects makes each runner available in the runners registry;
+// a new backend is added by importing its package here.
+package builtin
+
+import (
+	_ "github.com/tansive/tansive-internal/internal/tangent/runners/stdiorunner"
+)
@@ -0,0 +1,108 @@
+package runners
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+)
+
+var (
+	// ErrRunnerError is the base error for the package.
+	ErrRunnerError = apperrors.New("runner error")
+
+	// ErrUnknownRunner is returned when no runner is registered with the given ID.
+	ErrUnknownRunner = ErrRunnerError.New("unknown runner")
+
+	// ErrRunnerDisabled is returned when a runner is turned off in tangent.conf.
+	ErrRunnerDisabled = ErrRunnerError.New("runner is disabled")
+)
+
+var registry = struct {
+	sync.RWMutex
+	runners  map[catcommon.RunnerID]Runner
+	disabled map[catcommon.RunnerID]bool
+}{
+	runners:  make(map[catcommon.RunnerID]Runner),
+	disabled: make(map[catcommon.RunnerID]bool),
+}
+
+// Register makes a runner available under the ID returned by its Describe method.
+// It is meant to be called from the init function of the runner's package and panics
+// if a runner with the same ID is already registered.
+func Register(r Runner) {
+	id := r.Describe().ID
+	registry.Lock()
+	defer registry.Unlock()
+	if _, ok := registry.runners[id]; ok {
+		panic("runners: runner already registered: " + string(id))
+	}
+	registry.runners[id] = r
+}
+
+// Lookup returns the runner registered with the given ID.
+// Returns an error if there is no such runner or it is disabled.
+func Lookup(id catcommon.RunnerID) (Runner, apperrors.Error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	r, ok := registry.runners[id]
+	if !ok {
+		return nil, ErrUnknownRunner.Msg("invalid runner id: " + string(id))
+	}
+	if registry.disabled[id] {
+		return nil, ErrRunnerDisabled.Msg("runner is disabled: " + string(id))
+	}
+	return r, nil
+}
+
+// Registered returns the descriptions of the enabled runners, sorted by ID.
+func Registered() []Description {
+	registry.RLock()
+	defer registry.RUnlock()
+	var descs []Description
+	for id, r := range registry.runners {
+		if !registry.disabled[id] {
+			descs = append(descs, r.Describe())
+		}
+	}
+	slices.SortFunc(descs, func(a, b Description) int {
+		return strings.Compare(string(a.ID), string(b.ID))
+	})
+	return descs
+}
+
+// Init configures the registered runners from the [runners] sections of the config.
+// Must be called before using any runner functionality.
+func Init() error {
+	return configure(config.Config().Runners)
+}
+
+func configure(settings map[string]config.RunnerSettings) error {
+	registry.Lock()
+	defer registry.Unlock()
+	for id := range settings {
+		if _, ok := registry.runners[catcommon.RunnerID(id)]; !ok {
+			return fmt.Errorf("runners: configuration for unknown runner %q", id)
+		}
+	}
+	clear(registry.disabled)
+	for id, r := range registry.runners {
+		s := settings[string(id)]
+		if enabled, ok := s["enabled"].(bool); ok && !enabled {
+			registry.disabled[id] = true
+			continue
+		}
+		c, ok := r.(Configurable)
+		if !ok {
+			continue
+		}
+		if err := c.Configure(s); err != nil {
+			return fmt.Errorf("runners: configuring %s: %w", id, err)
+		}
+	}
+	return nil
+}
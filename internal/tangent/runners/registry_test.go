@@ -0,0 +1,77 @@
+package runners
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+)
+
+type fakeRunner struct {
+	id       catcommon.RunnerID
+	settings map[string]any
+}
+
+func (f *fakeRunner) Describe() Description {
+	return Description{ID: f.id, Version: "1.0.0"}
+}
+
+func (f *fakeRunner) Validate(config map[string]any) apperrors.Error {
+	if _, ok := config["command"]; !ok {
+		return ErrRunnerError.Msg("command is required")
+	}
+	return nil
+}
+
+func (f *fakeRunner) Exec(ctx context.Context, req *ExecRequest) apperrors.Error {
+	return nil
+}
+
+func (f *fakeRunner) Configure(settings map[string]any) error {
+	f.settings = settings
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	r := &fakeRunner{id: "test.fakerunner"}
+	Register(r)
+	t.Cleanup(func() {
+		registry.Lock()
+		delete(registry.runners, r.id)
+		delete(registry.disabled, r.id)
+		registry.Unlock()
+	})
+
+	assert.Panics(t, func() { Register(&fakeRunner{id: r.id}) })
+
+	got, err := Lookup(r.id)
+	require.Nil(t, err)
+	assert.Same(t, r, got)
+	assert.Contains(t, Registered(), Description{ID: r.id, Version: "1.0.0"})
+
+	_, err = Lookup("test.missing")
+	assert.ErrorIs(t, err, ErrUnknownRunner)
+
+	// Settings are passed to the runner
+	require.NoError(t, configure(map[string]config.RunnerSettings{
+		string(r.id): {"endpoint": "http://localhost:9000"},
+	}))
+	assert.Equal(t, "http://localhost:9000", r.settings["endpoint"])
+
+	// A disabled runner cannot be looked up
+	require.NoError(t, configure(map[string]config.RunnerSettings{
+		string(r.id): {"enabled": false},
+	}))
+	_, err = Lookup(r.id)
+	assert.ErrorIs(t, err, ErrRunnerDisabled)
+	assert.NotContains(t, Registered(), Description{ID: r.id, Version: "1.0.0"})
+
+	// Settings for a runner that is not registered are rejected
+	assert.Error(t, configure(map[string]config.RunnerSettings{
+		"test.missing": {},
+	}))
+}
@@ -1,48 +1,75 @@
-// Package runners provides the interface and factory for skill execution runners.
-// It defines the Runner interface and provides factory methods to create appropriate
-// runner instances based on skill configuration. The package supports multiple runner
-// types including stdio-based execution for script and command running.
+// Package runners provides the interface and registry for skill execution runners.
+// It defines the Runner interface that execution backends implement and a registry
+// that maps runner IDs to backends. Backends register themselves from their package's
+// init function, so a new backend (subprocess, container, HTTP tool) is added by
+// importing its package from the builtin package without changes to the server.
 package runners
 
 import (
 	"context"
-	"fmt"
+	"time"
 
-	"github.com/tansive/tansive-internal/internal/catalogsrv/catalogmanager"
 	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
-	"github.com/tansive/tansive-internal/internal/tangent/runners/stdiorunner"
 	"github.com/tansive/tansive-internal/internal/tangent/tangentcommon"
 	"github.com/tansive/tansive-internal/pkg/api"
 )
 
-// Runner is the interface for all skill execution runners.
-// Defines the contract for executing skills with input/output handling and lifecycle management.
+// Runner is the interface for all skill execution backends.
+// A Runner is shared by all sessions; the per-skill configuration from the skillset
+// is passed with each call.
 type Runner interface {
-	// ID returns the unique identifier for this runner instance.
-	ID() string
+	// Describe returns the ID and version of the runner.
+	Describe() Description
 
-	// AddWriters adds I/O writers for capturing command output.
-	AddWriters(writers ...*tangentcommon.IOWriters)
+	// Validate checks a skill's runner configuration from the skillset source.
+	Validate(config map[string]any) apperrors.Error
 
-	// Run executes the skill with the given arguments and context.
-	Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error
+	// Exec executes a skill. The context can be used to cancel the execution.
+	Exec(ctx context.Context, req *ExecRequest) apperrors.Error
 }
 
-// NewRunner creates a new runner instance based on the runner definition.
-// Returns the appropriate runner type and any error encountered during creation.
-// Currently supports stdio runners for script and command execution.
-func NewRunner(ctx context.Context, sessionID string, runnerDef catalogmanager.SkillSetSource, writers ...*tangentcommon.IOWriters) (Runner, apperrors.Error) {
-	switch runnerDef.Runner {
-	case catcommon.StdioRunnerID:
-		return stdiorunner.New(ctx, sessionID, runnerDef.Config, writers...)
-	default:
-		return nil, apperrors.New(fmt.Sprintf("invalid runner id: %s", runnerDef.Runner))
-	}
+// Configurable is implemented by runners that accept settings from tangent.conf.
+// Configure is called once at startup with the runner's [runners."<id>"] section,
+// which is empty if the section is absent.
+type Configurable interface {
+	Configure(settings map[string]any) error
 }
 
-// Init initializes the runners package and its dependencies.
-// Must be called before using any runner functionality.
-func Init() {
-	stdiorunner.Init()
+// Description identifies a runner and what it does.
+type Description struct {
+	ID          catcommon.RunnerID `json:"id"`                    // runner ID referenced by skillset sources
+	Version     string             `json:"version"`               // version of the runner
+	Description string             `json:"description,omitempty"` // what the runner executes
 }
+
+// ExecRequest holds what a runner needs to execute a skill.
+type ExecRequest struct {
+	SessionID   string                     // session the skill runs in
+	Config      map[string]any             // runner configuration from the skillset source
+	Args        *api.SkillInputArgs        // arguments passed to the skill
+	Writers     []*tangentcommon.IOWriters // writers for the output of the skill
+	ArtifactDir string                     // if set, directory where the skill deposits files to keep as artifacts
+	Usage       *Usage                     // if not nil, filled in with the resources the execution used
+	// SessionUsage is the resources used by the earlier executions of the session, for
+	// runners that limit what a session may use
+	SessionUsage Usage
+}
+
+// Usage reports the resources used by an execution of a skill.
+// Runners fill in what they can measure; CPUTime and MaxMemory are zero when unknown.
+type Usage struct {
+	WallTime      time.Duration // time from start to exit
+	CPUTime       time.Duration // user and system CPU time
+	MaxMemory     int64         // peak resident set size in bytes
+	LimitExceeded Limit         // the limit that stopped the execution, if any
+}
+
+// Limit names a resource limit enforced on an execution.
+type Limit string
+
+const (
+	LimitCPUTime   Limit = "cpu_time"   // CPU time limit
+	LimitMemory    Limit = "memory"     // memory limit
+	LimitWallClock Limit = "wall_clock" // wall-clock time limit
+)
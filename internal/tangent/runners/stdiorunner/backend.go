@@ -0,0 +1,61 @@
+package stdiorunner
+
+import (
+	"context"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/tangent/runners"
+)
+
+func init() {
+	runners.Register(backend{})
+}
+
+// backend registers the stdio runner with the runners registry.
+// Each execution is carried out by a runner created with New.
+type backend struct{}
+
+func (backend) Describe() runners.Description {
+	return runners.Description{
+		ID:          catcommon.StdioRunnerID,
+		Version:     Version,
+		Description: "Runs scripts from the script directory as local processes over stdio",
+	}
+}
+
+func (backend) Validate(configMap map[string]any) apperrors.Error {
+	_, err := parseConfig(configMap)
+	return err
+}
+
+func (backend) Exec(ctx context.Context, req *runners.ExecRequest) apperrors.Error {
+	r, err := New(ctx, req.SessionID, req.Config, req.Writers...)
+	if err != nil {
+		return err
+	}
+	r.artifactDir = req.ArtifactDir
+	r.sessionUsage = req.SessionUsage
+	err = r.Run(ctx, req.Args)
+	if req.Usage != nil {
+		*req.Usage = r.Usage()
+	}
+	return err
+}
+
+func (backend) Configure(settings map[string]any) error {
+	return configure(settings)
+}
+
+// parseConfig decodes and validates the runner configuration of a skillset source.
+func parseConfig(configMap map[string]any) (Config, apperrors.Error) {
+	var config Config
+	if err := mapstructure.Decode(configMap, &config); err != nil {
+		return config, ErrInvalidConfig
+	}
+	if err := config.Validate(); err != nil {
+		return config, err
+	}
+	return config, nil
+}
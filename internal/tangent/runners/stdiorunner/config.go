@@ -5,26 +5,81 @@
 package stdiorunner
 
 import (
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 
 	"github.com/tansive/tansive-internal/internal/tangent/config"
 )
 
 // RunnerConfig holds configuration for the stdio runner.
-// Contains script directory and runtime settings for command execution.
+// Contains script directory and the isolation and limits applied to every execution.
 type RunnerConfig struct {
-	ScriptDir string `json:"scriptDir"` // directory containing executable scripts
+	ScriptDir        string    `json:"scriptDir"`        // directory containing executable scripts
+	Isolation        Isolation `json:"isolation"`        // how skills are isolated, process if empty
+	ContainerRuntime string    `json:"containerRuntime"` // podman or docker, for container isolation
+	ContainerImage   string    `json:"containerImage"`   // image skills run in, for container isolation
+	Limits           Limits    `json:"limits"`           // default and maximum limits of an execution
+	SessionLimits    Limits    `json:"sessionLimits"`    // limits on all executions of a session
+	EnvDir           string    `json:"envDir"`           // directory for the environments of skill dependencies
 }
 
 var runnerConfig *RunnerConfig
 
-// Init initializes the stdio runner with configuration from the main config.
-// Sets up the script directory and runtime environment for command execution.
-func Init() {
-	runnerConfig = &RunnerConfig{
-		ScriptDir: config.Config().StdioRunner.ScriptDir,
+// configure sets up the stdio runner from its [runners."system.stdiorunner"] settings.
+// The script directory falls back to the [stdio_runner] section when not set there.
+func configure(settings map[string]any) error {
+	c := &RunnerConfig{
+		ScriptDir:        config.Config().StdioRunner.ScriptDir,
+		Isolation:        IsolationProcess,
+		ContainerRuntime: defaultContainerRuntime,
+		ContainerImage:   defaultContainerImage,
 	}
+	var limits, sessionLimits LimitsConfig
+	for key, p := range map[string]*string{
+		"script_dir":        &c.ScriptDir,
+		"isolation":         (*string)(&c.Isolation),
+		"container_runtime": &c.ContainerRuntime,
+		"container_image":   &c.ContainerImage,
+		"env_dir":           &c.EnvDir,
+		"cpu_time":          &limits.CPUTime,
+		"memory":            &limits.Memory,
+		"timeout":           &limits.Timeout,
+		"session_cpu_time":  &sessionLimits.CPUTime,
+		"session_memory":    &sessionLimits.Memory,
+		"session_timeout":   &sessionLimits.Timeout,
+	} {
+		v, ok := settings[key]
+		if !ok {
+			continue
+		}
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s must be a string", key)
+		}
+		if s != "" {
+			*p = s
+		}
+	}
+
+	if _, ok := ValidIsolations[c.Isolation]; !ok {
+		return fmt.Errorf("invalid isolation %q", c.Isolation)
+	}
+	if c.Isolation == IsolationContainer {
+		if _, err := exec.LookPath(c.ContainerRuntime); err != nil {
+			return fmt.Errorf("container runtime %q not found: %w", c.ContainerRuntime, err)
+		}
+	}
+	var err error
+	if c.Limits, err = limits.parse(); err != nil {
+		return err
+	}
+	if c.SessionLimits, err = sessionLimits.parse(); err != nil {
+		return fmt.Errorf("session limits: %w", err)
+	}
+	runnerConfig = c
+	return nil
 }
 
 // TestInit initializes the stdio runner for testing purposes.
@@ -17,6 +17,10 @@ var (
 	// Occurs when Config.Security.Type is not in ValidSecurityTypes.
 	ErrInvalidSecurity = ErrShellCommandRunnerError.New("invalid security")
 
+	// ErrInvalidProtocol is returned for unsupported protocols.
+	// Occurs when Config.Protocol is not in ValidProtocols.
+	ErrInvalidProtocol = ErrShellCommandRunnerError.New("invalid protocol")
+
 	// ErrInvalidScript is returned for invalid script paths.
 	// Occurs when Config.Script is empty.
 	ErrInvalidScript = ErrShellCommandRunnerError.New("invalid script")
@@ -52,4 +56,16 @@ var (
 	// ErrInvalidArgs is returned for invalid arguments.
 	// Occurs when the arguments are nil.
 	ErrInvalidArgs = ErrShellCommandRunnerError.New("invalid args")
+
+	// ErrProvisioningFailed is returned when the dependencies of a skill cannot be installed.
+	// Occurs when creating the virtual environment or installing packages fails.
+	ErrProvisioningFailed = ErrShellCommandRunnerError.New("provisioning failed")
+
+	// ErrProtocolError is returned when a skill does not follow the jsonrpc protocol.
+	// Occurs when the skill exits without a response or responds with an error.
+	ErrProtocolError = ErrShellCommandRunnerError.New("protocol error")
+
+	// ErrLimitExceeded is returned when a skill is stopped by a resource limit.
+	// Occurs when the skill runs past its CPU time, memory or wall-clock limit.
+	ErrLimitExceeded = ErrShellCommandRunnerError.New("resource limit exceeded")
 )
@@ -0,0 +1,155 @@
+package stdiorunner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/pkg/api"
+)
+
+// A skill that uses ProtocolJSONRPC reads one JSON-RPC 2.0 request from stdin:
+//
+//	{"jsonrpc": "2.0", "id": 1, "method": "run", "params": <api.SkillInputArgs>}
+//
+// and writes newline-delimited JSON-RPC messages to stdout. While it runs it may send
+// notifications that stream output to the session:
+//
+//	{"jsonrpc": "2.0", "method": "output", "params": {"data": "text for the caller"}}
+//	{"jsonrpc": "2.0", "method": "log", "params": {"data": "diagnostic text"}}
+//
+// It finishes by sending the response to the request, with the result of the skill:
+//
+//	{"jsonrpc": "2.0", "id": 1, "result": {"pods": 3}}
+//	{"jsonrpc": "2.0", "id": 1, "error": {"code": -32000, "message": "namespace not found"}}
+//
+// The result is written to the session's output as sent. Lines on stdout that are not
+// JSON-RPC messages are passed through as output, and stderr is passed through as is.
+
+const (
+	jsonrpcVersion   = "2.0"
+	jsonrpcRequestID = 1
+	jsonrpcMethodRun = "run"
+
+	jsonrpcNotifyOutput = "output"
+	jsonrpcNotifyLog    = "log"
+)
+
+type jsonrpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int    `json:"id"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type jsonrpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonrpcError   `json:"error,omitempty"`
+}
+
+type jsonrpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// jsonrpcRunRequest returns the request line that starts a skill.
+func jsonrpcRunRequest(args *api.SkillInputArgs) ([]byte, error) {
+	b, err := json.Marshal(jsonrpcRequest{
+		JSONRPC: jsonrpcVersion,
+		ID:      jsonrpcRequestID,
+		Method:  jsonrpcMethodRun,
+		Params:  args,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// jsonrpcConn reads the messages a skill writes to stdout. It forwards notifications to the
+// session's writers and keeps the response.
+type jsonrpcConn struct {
+	out, err io.Writer
+	partial  []byte
+	response *jsonrpcMessage
+}
+
+func newJSONRPCConn(out, err io.Writer) *jsonrpcConn {
+	return &jsonrpcConn{out: out, err: err}
+}
+
+// Write implements io.Writer for the skill's stdout. Messages are handled line by line.
+func (c *jsonrpcConn) Write(p []byte) (int, error) {
+	c.partial = append(c.partial, p...)
+	for {
+		i := bytes.IndexByte(c.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := c.partial[:i+1]
+		if err := c.handleLine(line); err != nil {
+			return len(p), err
+		}
+		c.partial = c.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+func (c *jsonrpcConn) handleLine(line []byte) error {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 {
+		return nil
+	}
+	var msg jsonrpcMessage
+	if json.Unmarshal(trimmed, &msg) != nil || msg.JSONRPC != jsonrpcVersion {
+		_, err := c.out.Write(line)
+		return err
+	}
+
+	switch {
+	case msg.Method == jsonrpcNotifyOutput || msg.Method == jsonrpcNotifyLog:
+		var params struct {
+			Data string `json:"data"`
+		}
+		if json.Unmarshal(msg.Params, &params) != nil {
+			return nil
+		}
+		w := c.out
+		if msg.Method == jsonrpcNotifyLog {
+			w = c.err
+		}
+		_, err := io.WriteString(w, params.Data)
+		return err
+	case msg.Method == "" && string(msg.ID) == fmt.Sprint(jsonrpcRequestID) && c.response == nil:
+		c.response = &msg
+		if msg.Error == nil && len(msg.Result) > 0 && string(msg.Result) != "null" {
+			_, err := c.out.Write(append(msg.Result, '\n'))
+			return err
+		}
+	}
+	// Other requests and notifications are not part of the protocol yet
+	return nil
+}
+
+// close handles a final line the skill did not terminate and returns the outcome of the
+// request: an error if the skill responded with one or did not respond.
+func (c *jsonrpcConn) close() apperrors.Error {
+	if len(c.partial) > 0 {
+		c.handleLine(append(c.partial, '\n'))
+		c.partial = nil
+	}
+	if c.response == nil {
+		return ErrProtocolError.Msg("skill exited without a response")
+	}
+	if e := c.response.Error; e != nil {
+		return ErrProtocolError.Msg(fmt.Sprintf("skill returned error %d: %s", e.Code, e.Message))
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+
+package stdiorunner
+
+import (
+	"os"
+	"os/exec"
+)
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func maxRSS(state *os.ProcessState) int64 {
+	return 0
+}
+
+func killedByCPULimit(state *os.ProcessState) bool {
+	return false
+}
@@ -0,0 +1,42 @@
+//go:build unix
+
+package stdiorunner
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+)
+
+// setProcessGroup starts the command in its own process group and makes cancellation
+// kill the whole group, so that processes started by the skill do not outlive it.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// maxRSS returns the peak resident set size of a finished process in bytes.
+func maxRSS(state *os.ProcessState) int64 {
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok {
+		return 0
+	}
+	if runtime.GOOS == "darwin" {
+		return int64(rusage.Maxrss)
+	}
+	// Other systems report kilobytes
+	return int64(rusage.Maxrss) * 1024
+}
+
+// killedByCPULimit reports whether a process was stopped by the signals the kernel sends
+// when it reaches RLIMIT_CPU.
+func killedByCPULimit(state *os.ProcessState) bool {
+	status, ok := state.Sys().(syscall.WaitStatus)
+	if !ok || !status.Signaled() {
+		return false
+	}
+	return status.Signal() == syscall.SIGXCPU || status.Signal() == syscall.SIGKILL
+}
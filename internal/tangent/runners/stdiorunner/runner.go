@@ -4,40 +4,35 @@
 package stdiorunner
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/h2non/filetype"
 
-	"github.com/mitchellh/mapstructure"
-	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/tangent/runners"
 	"github.com/tansive/tansive-internal/internal/tangent/tangentcommon"
 	"github.com/tansive/tansive-internal/pkg/api"
 )
 
-// runner implements the runners.Runner interface.
+// runner executes a single skill for the stdio backend.
 // It manages command execution lifecycle and output streaming.
 type runner struct {
 	sessionID   string
 	config      Config
 	homeDirPath string
+	artifactDir string
 	writers     []*tangentcommon.IOWriters
-}
-
-func (r *runner) ID() string {
-	return catcommon.StdioRunnerID
-}
-
-func (r *runner) AddWriters(writers ...*tangentcommon.IOWriters) {
-	r.writers = append(r.writers, writers...)
+	usage       runners.Usage
+	// resources used by the earlier executions of the session
+	sessionUsage runners.Usage
 }
 
 // New creates a new runner with the given configuration.
@@ -45,19 +40,14 @@ func (r *runner) AddWriters(writers ...*tangentcommon.IOWriters) {
 // The writers must provide non-nil io.Writer implementations for both stdout and stderr.
 // Returns an error if the configuration is invalid or writers are not properly configured.
 func New(ctx context.Context, sessionID string, configMap map[string]any, writers ...*tangentcommon.IOWriters) (*runner, apperrors.Error) {
-	var config Config
-
 	for _, writer := range writers {
 		if writer == nil || writer.Out == nil || writer.Err == nil {
 			return nil, ErrInvalidWriters
 		}
 	}
 
-	if err := mapstructure.Decode(configMap, &config); err != nil {
-		return nil, ErrInvalidConfig
-	}
-
-	if err := config.Validate(); err != nil {
+	config, err := parseConfig(configMap)
+	if err != nil {
 		return nil, err
 	}
 
@@ -70,30 +60,36 @@ func New(ctx context.Context, sessionID string, configMap map[string]any, writer
 	return runner, nil
 }
 
-// Run executes the configured command.
+// Run executes the configured command with the isolation and limits of the runner.
 // The context can be used to cancel the execution.
-// Returns an error if execution fails or is cancelled.
+// Returns an error if execution fails, is cancelled, or exceeds a limit.
 func (r *runner) Run(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
 	if args == nil {
 		return ErrInvalidArgs.Msg("args is nil")
 	}
 
-	if r.config.Security.Type == SecurityTypeDefault {
-		return r.runWithDevModeSecurity(ctx, args)
+	sb, err := r.sandbox()
+	if err != nil {
+		return err
 	}
-	return ErrInvalidSecurity.Msg("security type not supported: " + string(r.config.Security.Type))
+	return r.runInSandbox(ctx, sb, args)
 }
 
-func (r *runner) runWithDevModeSecurity(ctx context.Context, args *api.SkillInputArgs) apperrors.Error {
-	scriptPath := filepath.Join(runnerConfig.ScriptDir, filepath.Clean(r.config.Script))
-	if !strings.HasPrefix(scriptPath, filepath.Clean(runnerConfig.ScriptDir)+string(os.PathSeparator)) {
-		return ErrInvalidScript.Msg("script path escapes trusted directory")
+func (r *runner) runInSandbox(ctx context.Context, sb sandbox, args *api.SkillInputArgs) apperrors.Error {
+	scriptPath, apperr := resolveScriptPath(r.config.Script)
+	if apperr != nil {
+		return apperr
 	}
 
 	if _, err := os.Stat(scriptPath); err != nil {
 		return ErrInvalidScript.Msg("script not found: " + err.Error())
 	}
 
+	runtimeCmd, runtimeEnv, apperr := r.runtimeCommand(ctx, sb)
+	if apperr != nil {
+		return apperr
+	}
+
 	homeDirPath := filepath.Join(os.TempDir(), r.sessionID)
 	if err := os.MkdirAll(homeDirPath, 0755); err != nil {
 		return ErrExecutionFailed.Msg("failed to create home directory: " + err.Error())
@@ -101,60 +97,63 @@ func (r *runner) runWithDevModeSecurity(ctx context.Context, args *api.SkillInpu
 
 	r.homeDirPath = homeDirPath
 	wrappedScriptPath := filepath.Join(homeDirPath, "wrapped.sh")
-	if err := r.writeWrappedScript(wrappedScriptPath, scriptPath, args); err != nil {
+	if err := r.writeWrappedScript(wrappedScriptPath, scriptPath, runtimeCmd, sb, args); err != nil {
 		return ErrExecutionFailed.Msg("failed to create wrapped script: " + err.Error())
 	}
 	if err := os.Chmod(wrappedScriptPath, 0755); err != nil {
 		return ErrExecutionFailed.Msg("failed to set permissions on wrapped script: " + err.Error())
 	}
 
-	baseEnv := os.Environ()
-	env := appendOrReplaceEnv(baseEnv, "HOME", homeDirPath)
+	var env []string
 	for k, v := range r.config.Env {
-		env = appendOrReplaceEnv(env, k, v)
+		env = append(env, k+"="+v)
 	}
+	env = append(env, runtimeEnv...)
 
-	outWriter := NewWriter(StdoutWriter, r.writers...)
+	var outWriter io.Writer = NewWriter(StdoutWriter, r.writers...)
 	errWriter := NewWriter(StderrWriter, r.writers...)
-
-	cmd := exec.CommandContext(ctx, "/bin/bash", wrappedScriptPath)
-	cmd.Dir = homeDirPath
-	cmd.Env = env
-	// cmd.Stdout = outWriter
-	// cmd.Stderr = errWriter
-
-	// if err := cmd.Run(); err != nil {
-	// 	return ErrExecutionFailed.Msg("command failed: " + err.Error())
-	// }
-
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return ErrExecutionFailed.Msg("failed to get stdout pipe: " + err.Error())
+	var rpc *jsonrpcConn
+	var stdin io.Reader
+	if r.config.Protocol == ProtocolJSONRPC {
+		request, err := jsonrpcRunRequest(args)
+		if err != nil {
+			return ErrExecutionFailed.Msg("failed to encode request: " + err.Error())
+		}
+		rpc = newJSONRPCConn(outWriter, errWriter)
+		outWriter = rpc
+		stdin = bytes.NewReader(request)
 	}
-	stderrPipe, err := cmd.StderrPipe()
-	if err != nil {
-		return ErrExecutionFailed.Msg("failed to get stderr pipe: " + err.Error())
+
+	if sb.limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, sb.limits.Timeout, errWallClockExceeded)
+		defer cancel()
 	}
 
+	cmd := sb.command(ctx, homeDirPath, wrappedScriptPath, args.ServiceEndpoint, r.artifactDir, os.Environ(), env)
+	// Wait copies the output until the pipes close; processes left behind by the skill may
+	// hold them open, so stop waiting for them shortly after the skill exits.
+	cmd.Stdin = stdin
+	cmd.Stdout = outWriter
+	cmd.Stderr = errWriter
+	cmd.WaitDelay = processWaitDelay
+
+	start := time.Now()
 	if err := cmd.Start(); err != nil {
 		return ErrExecutionFailed.Msg("startcommand failed: " + err.Error())
 	}
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		io.Copy(outWriter, stdoutPipe)
-	}()
-
-	go func() {
-		defer wg.Done()
-		io.Copy(errWriter, stderrPipe)
-	}()
-
-	err = cmd.Wait()
-	wg.Wait()
+	err := cmd.Wait()
 
+	r.usage = sb.usage(ctx, cmd, time.Since(start))
+	if r.usage.LimitExceeded != "" {
+		return ErrLimitExceeded.Msg(string(r.usage.LimitExceeded) + " limit exceeded")
+	}
+	if rpc != nil {
+		// The response explains a failure better than the exit status
+		if rpcErr := rpc.close(); rpcErr != nil && (err == nil || rpc.response != nil) {
+			return rpcErr
+		}
+	}
 	if err != nil {
 		return ErrExecutionFailed.Msg("command execution failed: " + err.Error())
 	}
@@ -162,13 +161,22 @@ func (r *runner) runWithDevModeSecurity(ctx context.Context, args *api.SkillInpu
 	return nil
 }
 
-func (r *runner) writeWrappedScript(wrappedPath, scriptPath string, args *api.SkillInputArgs) error {
+// Usage returns the resources used by the last run.
+func (r *runner) Usage() runners.Usage {
+	return r.usage
+}
+
+func (r *runner) writeWrappedScript(wrappedPath, scriptPath string, runtimeCmd []string, sb sandbox, args *api.SkillInputArgs) error {
 	jsonArgs, err := json.Marshal(args)
 	if err != nil {
 		return fmt.Errorf("could not normalize JSON args: %w", err)
 	}
 
-	escapedArgs := strings.ReplaceAll(string(jsonArgs), "'", "'\\''")
+	// With the jsonrpc protocol the arguments are sent on stdin instead
+	argv := " '" + strings.ReplaceAll(string(jsonArgs), "'", "'\\''") + "'"
+	if r.config.Protocol == ProtocolJSONRPC {
+		argv = ""
+	}
 
 	var content string
 	if r.config.Runtime == RuntimeBinary {
@@ -182,20 +190,15 @@ func (r *runner) writeWrappedScript(wrappedPath, scriptPath string, args *api.Sk
 
 		content = fmt.Sprintf(`#!/bin/bash
 set -euo pipefail
-
-exec '%s' '%s'
-`, scriptPath, escapedArgs)
+%s
+exec '%s'%s
+`, sb.limits.shellLimits(sb.isolation), sb.scriptPath(scriptPath), argv)
 	} else {
-		runtimeCmd, err := resolveRuntimeCommand(r.config.Runtime)
-		if err != nil {
-			return fmt.Errorf("unsupported runtime: %w", err)
-		}
-
 		content = fmt.Sprintf(`#!/bin/bash
 set -euo pipefail
-
-exec %s '%s' '%s'
-`, strings.Join(runtimeCmd, " "), scriptPath, escapedArgs)
+%s
+exec %s '%s'%s
+`, sb.limits.shellLimits(sb.isolation), strings.Join(runtimeCmd, " "), sb.scriptPath(scriptPath), argv)
 	}
 
 	return os.WriteFile(wrappedPath, []byte(content), 0644)
@@ -0,0 +1,264 @@
+package stdiorunner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+// RuntimeSettings are the runtime-specific settings read from Config.RuntimeConfig.
+// Other keys in RuntimeConfig are ignored. Paths are relative to the script directory.
+//
+// Example for a Python skill with dependencies:
+//
+//	"runtimeConfig": {"requirements": "patient-tools/requirements.txt"}
+//
+// Example for a Node skill with dependencies:
+//
+//	"runtimeConfig": {"packages": "patient-tools"}
+type RuntimeSettings struct {
+	Requirements string `mapstructure:"requirements"` // python: requirements file installed into a virtual environment
+	Packages     string `mapstructure:"packages"`     // node: directory with the package.json whose dependencies are installed
+}
+
+// provisionedMarker is created in an environment directory once its dependencies are installed.
+const provisionedMarker = ".tansive-provisioned"
+
+// runtimeAdapter installs the dependencies of skills written for a language runtime.
+// An environment is shared by all skills with the same dependency manifest and is
+// installed once; later executions reuse it.
+type runtimeAdapter interface {
+	// manifest returns the files that determine the skill's dependencies, or none if
+	// the skill does not declare any.
+	manifest(settings RuntimeSettings) ([]string, apperrors.Error)
+
+	// install installs the dependencies in the manifest into the empty directory envDir.
+	install(ctx context.Context, envDir string, manifest []string) error
+
+	// environment returns the command that runs a script in the environment, and the
+	// variables that let the script find the installed dependencies.
+	environment(envDir string) (command []string, env []string)
+}
+
+var runtimeAdapters = map[Runtime]runtimeAdapter{
+	RuntimePython: pythonAdapter{},
+	RuntimeNode:   nodeAdapter{},
+}
+
+// runtimeSettings decodes and checks the runtime-specific settings of the configuration.
+func (c *Config) runtimeSettings() (RuntimeSettings, apperrors.Error) {
+	var s RuntimeSettings
+	if err := mapstructure.Decode(c.RuntimeConfig, &s); err != nil {
+		return s, ErrInvalidRuntimeConfig.Msg(err.Error())
+	}
+	if s.Requirements != "" && c.Runtime != RuntimePython {
+		return s, ErrInvalidRuntimeConfig.Msg("requirements is only supported for the python runtime")
+	}
+	if s.Packages != "" && c.Runtime != RuntimeNode {
+		return s, ErrInvalidRuntimeConfig.Msg("packages is only supported for the node runtime")
+	}
+	return s, nil
+}
+
+// runtimeCommand returns the command that runs the skill's script and the variables its
+// runtime needs, installing the skill's dependencies first if it declares any.
+func (r *runner) runtimeCommand(ctx context.Context, sb sandbox) ([]string, []string, apperrors.Error) {
+	adapter, ok := runtimeAdapters[r.config.Runtime]
+	if !ok {
+		cmd, err := resolveRuntimeCommand(r.config.Runtime)
+		if err != nil {
+			return nil, nil, ErrInvalidRuntime.Msg(err.Error())
+		}
+		return cmd, nil, nil
+	}
+
+	settings, err := r.config.runtimeSettings()
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest, err := adapter.manifest(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(manifest) == 0 {
+		cmd, _ := resolveRuntimeCommand(r.config.Runtime)
+		return cmd, nil, nil
+	}
+	if sb.isolation == IsolationContainer {
+		// An environment installed on the host does not run with the container's interpreter
+		return nil, nil, ErrInvalidRuntimeConfig.Msg("dependencies cannot be provisioned with container isolation; install them in the container image")
+	}
+
+	envDir, err := provisionEnv(ctx, r.config.Runtime, adapter, manifest)
+	if err != nil {
+		return nil, nil, err
+	}
+	cmd, env := adapter.environment(envDir)
+	return cmd, env, nil
+}
+
+// envLocks serializes the provisioning of each environment directory.
+var envLocks sync.Map
+
+// provisionEnv returns the environment directory for the manifest, installing the
+// dependencies if this is the first skill to use it. The directory is named after the
+// contents of the manifest, so a changed manifest gets a new environment.
+func provisionEnv(ctx context.Context, rt Runtime, adapter runtimeAdapter, manifest []string) (string, apperrors.Error) {
+	h := sha256.New()
+	for _, path := range manifest {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", ErrInvalidRuntimeConfig.Msg("unable to read dependency manifest: " + err.Error())
+		}
+		fmt.Fprintf(h, "%s\n%d\n", filepath.Base(path), len(content))
+		h.Write(content)
+	}
+	envDir := filepath.Join(runnerConfig.envDir(), string(rt)+"-"+hex.EncodeToString(h.Sum(nil))[:16])
+
+	mu, _ := envLocks.LoadOrStore(envDir, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	if _, err := os.Stat(filepath.Join(envDir, provisionedMarker)); err == nil {
+		return envDir, nil
+	}
+	// A directory without the marker is left from an installation that did not finish
+	if err := os.RemoveAll(envDir); err != nil {
+		return "", ErrProvisioningFailed.Msg("unable to clear environment: " + err.Error())
+	}
+	if err := os.MkdirAll(envDir, 0755); err != nil {
+		return "", ErrProvisioningFailed.Msg("unable to create environment: " + err.Error())
+	}
+	if err := adapter.install(ctx, envDir, manifest); err != nil {
+		os.RemoveAll(envDir)
+		return "", ErrProvisioningFailed.Msg(err.Error())
+	}
+	if err := os.WriteFile(filepath.Join(envDir, provisionedMarker), nil, 0644); err != nil {
+		os.RemoveAll(envDir)
+		return "", ErrProvisioningFailed.Msg("unable to mark environment: " + err.Error())
+	}
+	return envDir, nil
+}
+
+// envDir returns the directory that holds the provisioned environments.
+func (c *RunnerConfig) envDir() string {
+	if c.EnvDir != "" {
+		return c.EnvDir
+	}
+	return filepath.Join(os.TempDir(), "tansive-runtimes")
+}
+
+// pythonAdapter installs a skill's requirements file into a virtual environment.
+type pythonAdapter struct{}
+
+func (pythonAdapter) manifest(settings RuntimeSettings) ([]string, apperrors.Error) {
+	if settings.Requirements == "" {
+		return nil, nil
+	}
+	path, err := resolveScriptPath(settings.Requirements)
+	if err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+func (pythonAdapter) install(ctx context.Context, envDir string, manifest []string) error {
+	if err := runInstallCommand(ctx, "", "python3", "-m", "venv", envDir); err != nil {
+		return err
+	}
+	return runInstallCommand(ctx, "", filepath.Join(envDir, "bin", "python"), "-m", "pip", "install",
+		"--disable-pip-version-check", "--no-input", "--quiet", "-r", manifest[0])
+}
+
+func (pythonAdapter) environment(envDir string) ([]string, []string) {
+	return []string{filepath.Join(envDir, "bin", "python"), "-u"}, []string{"VIRTUAL_ENV=" + envDir}
+}
+
+// nodeAdapter installs the dependencies of a skill's package.json into node_modules.
+// The package-lock.json next to it, if any, pins the installed versions.
+type nodeAdapter struct{}
+
+func (nodeAdapter) manifest(settings RuntimeSettings) ([]string, apperrors.Error) {
+	if settings.Packages == "" {
+		return nil, nil
+	}
+	dir, err := resolveScriptPath(settings.Packages)
+	if err != nil {
+		return nil, err
+	}
+	manifest := []string{filepath.Join(dir, "package.json")}
+	if _, err := os.Stat(manifest[0]); err != nil {
+		return nil, ErrInvalidRuntimeConfig.Msg("package.json not found: " + err.Error())
+	}
+	if lock := filepath.Join(dir, "package-lock.json"); fileExists(lock) {
+		manifest = append(manifest, lock)
+	}
+	return manifest, nil
+}
+
+func (nodeAdapter) install(ctx context.Context, envDir string, manifest []string) error {
+	for _, path := range manifest {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(envDir, filepath.Base(path)), content, 0644); err != nil {
+			return err
+		}
+	}
+	verb := "install"
+	if len(manifest) > 1 {
+		verb = "ci"
+	}
+	return runInstallCommand(ctx, envDir, "npm", verb, "--omit=dev", "--no-audit", "--no-fund", "--loglevel=error")
+}
+
+func (nodeAdapter) environment(envDir string) ([]string, []string) {
+	return []string{"node"}, []string{"NODE_PATH=" + filepath.Join(envDir, "node_modules")}
+}
+
+// maxInstallOutput bounds the output of a failed installation included in its error.
+const maxInstallOutput = 2048
+
+// runInstallCommand runs a command that installs dependencies. If it fails, the end of
+// its output is included in the error.
+func runInstallCommand(ctx context.Context, dir, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		out := strings.TrimSpace(output.String())
+		if len(out) > maxInstallOutput {
+			out = "..." + out[len(out)-maxInstallOutput:]
+		}
+		return fmt.Errorf("%s %s: %w: %s", filepath.Base(name), args[0], err, out)
+	}
+	return nil
+}
+
+// resolveScriptPath returns the path of a file in the script directory. The path must not
+// escape the directory.
+func resolveScriptPath(rel string) (string, apperrors.Error) {
+	path := filepath.Join(runnerConfig.ScriptDir, filepath.Clean(rel))
+	if !strings.HasPrefix(path, filepath.Clean(runnerConfig.ScriptDir)+string(os.PathSeparator)) {
+		return "", ErrInvalidScript.Msg("script path escapes trusted directory")
+	}
+	return path, nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,130 @@
+package stdiorunner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/tangent/runners"
+	"github.com/tansive/tansive-internal/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive-internal/pkg/api"
+)
+
+// runRuntimeSkill runs a skill with the given runner configuration from scriptDir and
+// returns its stdout and stderr.
+func runRuntimeSkill(t *testing.T, scriptDir string, configMap map[string]any) (string, string, apperrors.Error) {
+	saved := runnerConfig
+	t.Cleanup(func() { runnerConfig = saved })
+	runnerConfig = &RunnerConfig{ScriptDir: scriptDir, EnvDir: filepath.Join(scriptDir, ".envs")}
+
+	configMap["version"] = Version
+	var stdout, stderr strings.Builder
+	sessionID := fmt.Sprintf("runtime-test-%d", time.Now().UnixNano())
+	t.Cleanup(func() { os.RemoveAll(filepath.Join(os.TempDir(), sessionID)) })
+	err := backend{}.Exec(context.Background(), &runners.ExecRequest{
+		SessionID: sessionID,
+		Config:    configMap,
+		Args: &api.SkillInputArgs{
+			SessionID: sessionID,
+			SkillName: "test-skill",
+			InputArgs: map[string]any{"mode": configMap["mode"]},
+		},
+		Writers: []*tangentcommon.IOWriters{{Out: &stdout, Err: &stderr}},
+	})
+	return stdout.String(), stderr.String(), err
+}
+
+func TestJSONRPCProtocol(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		t.Skip("python3 not found")
+	}
+	scriptDir := t.TempDir()
+	script := `import json, sys
+req = json.loads(sys.stdin.readline())
+mode = req["params"]["inputArgs"]["mode"]
+def send(msg):
+    msg["jsonrpc"] = "2.0"
+    print(json.dumps(msg), flush=True)
+send({"method": "output", "params": {"data": "working on " + req["params"]["skillName"] + "\n"}})
+send({"method": "log", "params": {"data": "debug line\n"}})
+print("plain text")
+if mode == "fail":
+    send({"id": req["id"], "error": {"code": -32000, "message": "namespace not found"}})
+elif mode == "silent":
+    pass
+else:
+    send({"id": req["id"], "result": {"pods": 3}})
+`
+	require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "rpc.py"), []byte(script), 0644))
+
+	stdout, stderr, err := runRuntimeSkill(t, scriptDir, map[string]any{"runtime": "python", "protocol": "jsonrpc", "script": "rpc.py"})
+	require.NoError(t, err)
+	assert.Equal(t, "working on test-skill\nplain text\n{\"pods\": 3}\n", stdout)
+	assert.Equal(t, "debug line\n", stderr)
+
+	_, _, err = runRuntimeSkill(t, scriptDir, map[string]any{"runtime": "python", "protocol": "jsonrpc", "script": "rpc.py", "mode": "fail"})
+	assert.ErrorIs(t, err, ErrProtocolError)
+	assert.ErrorContains(t, err, "namespace not found")
+
+	_, _, err = runRuntimeSkill(t, scriptDir, map[string]any{"runtime": "python", "protocol": "jsonrpc", "script": "rpc.py", "mode": "silent"})
+	assert.ErrorIs(t, err, ErrProtocolError)
+}
+
+func TestRuntimeProvisioning(t *testing.T) {
+	t.Run("python", func(t *testing.T) {
+		if _, err := exec.LookPath("python3"); err != nil {
+			t.Skip("python3 not found")
+		}
+		scriptDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "requirements.txt"), []byte("# no dependencies\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "env.py"), []byte("import os, sys\nprint(os.environ['VIRTUAL_ENV'] == sys.prefix)\n"), 0644))
+		configMap := map[string]any{"runtime": "python", "script": "env.py", "runtimeConfig": map[string]any{"requirements": "requirements.txt"}}
+
+		stdout, stderr, err := runRuntimeSkill(t, scriptDir, configMap)
+		require.NoError(t, err, stderr)
+		assert.Equal(t, "True\n", stdout)
+
+		// The environment is reused
+		envs, _ := filepath.Glob(filepath.Join(scriptDir, ".envs", "python-*", provisionedMarker))
+		require.Len(t, envs, 1)
+		marker, _ := os.Stat(envs[0])
+		_, _, err = runRuntimeSkill(t, scriptDir, configMap)
+		require.NoError(t, err)
+		again, _ := os.Stat(envs[0])
+		assert.Equal(t, marker.ModTime(), again.ModTime())
+	})
+
+	t.Run("node", func(t *testing.T) {
+		if _, err := exec.LookPath("npm"); err != nil {
+			t.Skip("npm not found")
+		}
+		scriptDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(scriptDir, "tools"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "tools", "package.json"), []byte(`{"name": "tools", "version": "1.0.0"}`), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "tools", "env.js"), []byte("console.log(process.env.NODE_PATH.endsWith('node_modules'))\n"), 0644))
+
+		stdout, stderr, err := runRuntimeSkill(t, scriptDir, map[string]any{"runtime": "node", "script": "tools/env.js", "runtimeConfig": map[string]any{"packages": "tools"}})
+		require.NoError(t, err, stderr)
+		assert.Equal(t, "true\n", stdout)
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		_, err := parseConfig(map[string]any{"version": Version, "runtime": "node", "script": "x.js", "runtimeConfig": map[string]any{"requirements": "requirements.txt"}})
+		assert.ErrorIs(t, err, ErrInvalidRuntimeConfig)
+		_, err = parseConfig(map[string]any{"version": Version, "runtime": "python", "script": "x.py", "protocol": "grpc"})
+		assert.ErrorIs(t, err, ErrInvalidProtocol)
+
+		scriptDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "x.py"), nil, 0644))
+		_, _, err = runRuntimeSkill(t, scriptDir, map[string]any{"runtime": "python", "script": "x.py", "runtimeConfig": map[string]any{"requirements": "../requirements.txt"}})
+		assert.ErrorIs(t, err, ErrInvalidScript)
+	})
+}
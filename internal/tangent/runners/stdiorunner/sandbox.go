@@ -0,0 +1,343 @@
+package stdiorunner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/tangent/runners"
+)
+
+// Isolation specifies how a skill's process is isolated from tangent and the host.
+type Isolation string
+
+const (
+	// IsolationProcess runs the skill as a plain subprocess that inherits tangent's environment.
+	IsolationProcess Isolation = "process"
+
+	// IsolationRestricted runs the skill in its own process group with a scrubbed environment
+	// and no core dumps, so that it cannot read tangent's secrets or leave processes behind.
+	// On Linux it also runs under a seccomp filter that denies syscalls which administer the
+	// host, trace other processes or create namespaces.
+	IsolationRestricted Isolation = "restricted"
+
+	// IsolationContainer runs the skill in a rootless container without network access,
+	// capabilities or privilege escalation. The script directory is mounted read-only.
+	IsolationContainer Isolation = "container"
+)
+
+// ValidIsolations defines the supported isolation modes.
+var ValidIsolations = map[Isolation]struct{}{
+	IsolationProcess:    {},
+	IsolationRestricted: {},
+	IsolationContainer:  {},
+}
+
+const (
+	defaultContainerRuntime = "podman"
+	defaultContainerImage   = "docker.io/library/python:3.12-slim"
+
+	// maxProcesses caps the processes a containerized skill may create.
+	maxProcesses = 256
+
+	// processWaitDelay is how long a cancelled or exited skill has to release its output
+	// before it is killed.
+	processWaitDelay = 5 * time.Second
+
+	containerHomeDir     = "/home/tansive"
+	containerArtifactDir = "/opt/tansive/artifacts"
+	containerScriptDir   = "/opt/tansive/scripts"
+
+	// artifactDirEnv names the directory where a skill deposits files to keep as artifacts.
+	artifactDirEnv = "TANSIVE_ARTIFACT_DIR"
+)
+
+// errWallClockExceeded is the cause of the context of an execution that ran past its timeout.
+var errWallClockExceeded = errors.New("wall-clock limit exceeded")
+
+// Limits are the resource limits of a skill execution, or of all executions in a session.
+// Zero means unlimited.
+type Limits struct {
+	CPUTime time.Duration // CPU time, enforced with RLIMIT_CPU
+	Memory  int64         // address space in bytes, or container memory
+	Timeout time.Duration // wall-clock time
+}
+
+// LimitsConfig is how limits are given in a skillset source, e.g.
+//
+//	"limits": {"cpuTime": "30s", "memory": "512M", "timeout": "5m"}
+type LimitsConfig struct {
+	CPUTime string `json:"cpuTime"` // Go duration
+	Memory  string `json:"memory"`  // bytes, with an optional K, M or G suffix
+	Timeout string `json:"timeout"` // Go duration
+}
+
+// parse converts the limits to their numeric form.
+func (c LimitsConfig) parse() (Limits, error) {
+	var l Limits
+	var err error
+	if l.CPUTime, err = parseLimitDuration(c.CPUTime); err != nil {
+		return l, fmt.Errorf("cpuTime: %w", err)
+	}
+	if l.Memory, err = parseBytes(c.Memory); err != nil {
+		return l, fmt.Errorf("memory: %w", err)
+	}
+	if l.Timeout, err = parseLimitDuration(c.Timeout); err != nil {
+		return l, fmt.Errorf("timeout: %w", err)
+	}
+	return l, nil
+}
+
+// within returns the limits l lowered to those of max. A limit that is zero in l
+// takes the value in max.
+func (l Limits) within(max Limits) Limits {
+	return Limits{
+		CPUTime: minLimit(l.CPUTime, max.CPUTime),
+		Memory:  minLimit(l.Memory, max.Memory),
+		Timeout: minLimit(l.Timeout, max.Timeout),
+	}
+}
+
+// remaining returns what is left of the session limits l after the session's earlier
+// executions used u, and the limit they used up, if any. Memory is a limit on each process,
+// so it is not used up.
+func (l Limits) remaining(u runners.Usage) (Limits, runners.Limit) {
+	left := l
+	if l.CPUTime > 0 {
+		if left.CPUTime = l.CPUTime - u.CPUTime; left.CPUTime <= 0 {
+			return left, runners.LimitCPUTime
+		}
+	}
+	if l.Timeout > 0 {
+		if left.Timeout = l.Timeout - u.WallTime; left.Timeout <= 0 {
+			return left, runners.LimitWallClock
+		}
+	}
+	return left, ""
+}
+
+func minLimit[T time.Duration | int64](a, b T) T {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// shellLimits returns the ulimit commands that apply the CPU and memory limits to the
+// wrapper script before it executes the skill.
+func (l Limits) shellLimits(isolation Isolation) string {
+	var b strings.Builder
+	if l.CPUTime > 0 {
+		fmt.Fprintf(&b, "ulimit -t %d\n", cpuSeconds(l.CPUTime))
+	}
+	if l.Memory > 0 && isolation != IsolationContainer {
+		// The container runtime enforces memory through cgroups instead
+		fmt.Fprintf(&b, "ulimit -v %d\n", (l.Memory+1023)/1024)
+	}
+	if isolation == IsolationRestricted {
+		b.WriteString("ulimit -c 0\n")
+	}
+	return b.String()
+}
+
+// cpuSeconds returns a CPU time limit in seconds, the granularity of RLIMIT_CPU.
+func cpuSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// sandbox is how a single execution is isolated and limited.
+type sandbox struct {
+	isolation Isolation
+	limits    Limits
+}
+
+// sandbox returns the isolation and limits of the execution. Sandboxed skills run with at
+// least restricted isolation. Limits requested by the skill are lowered to the runner's, and
+// to what is left of the session's limits. ErrLimitExceeded is returned if the session has
+// used up a limit.
+func (r *runner) sandbox() (sandbox, apperrors.Error) {
+	sb := sandbox{isolation: runnerConfig.Isolation, limits: runnerConfig.Limits}
+	if sb.isolation == "" {
+		sb.isolation = IsolationProcess
+	}
+	if r.config.Security.Type == SecurityTypeSandboxed && sb.isolation == IsolationProcess {
+		sb.isolation = IsolationRestricted
+	}
+	if r.config.Security.Limits != nil {
+		l, err := r.config.Security.Limits.parse()
+		if err != nil {
+			return sb, ErrInvalidSecurity.Msg("invalid limits: " + err.Error())
+		}
+		sb.limits = l.within(runnerConfig.Limits)
+	}
+	left, exceeded := runnerConfig.SessionLimits.remaining(r.sessionUsage)
+	if exceeded != "" {
+		r.usage = runners.Usage{LimitExceeded: exceeded}
+		return sb, ErrLimitExceeded.Msg("session " + string(exceeded) + " limit exceeded")
+	}
+	sb.limits = sb.limits.within(left)
+	return sb, nil
+}
+
+// scriptPath returns the path at which the skill sees a script in the script directory.
+func (sb sandbox) scriptPath(hostPath string) string {
+	if sb.isolation != IsolationContainer {
+		return hostPath
+	}
+	rel, _ := filepath.Rel(runnerConfig.ScriptDir, hostPath)
+	return filepath.Join(containerScriptDir, rel)
+}
+
+// command returns the command that runs the wrapper script in the home directory.
+// env holds the variables set by the skill's configuration. If artifactDir is set, the
+// skill finds it in TANSIVE_ARTIFACT_DIR.
+func (sb sandbox) command(ctx context.Context, homeDir, wrappedScript, socketPath, artifactDir string, baseEnv, env []string) *exec.Cmd {
+	if sb.isolation == IsolationContainer {
+		args := []string{"run", "--rm", "-i",
+			"--network", "none",
+			"--cap-drop", "ALL",
+			"--security-opt", "no-new-privileges",
+			"--pids-limit", strconv.Itoa(maxProcesses),
+			"-v", runnerConfig.ScriptDir + ":" + containerScriptDir + ":ro",
+			"-v", homeDir + ":" + containerHomeDir,
+			"-w", containerHomeDir,
+			"-e", "HOME=" + containerHomeDir,
+		}
+		if socketPath != "" {
+			args = append(args, "-v", socketPath+":"+socketPath)
+		}
+		if artifactDir != "" {
+			args = append(args, "-v", artifactDir+":"+containerArtifactDir, "-e", artifactDirEnv+"="+containerArtifactDir)
+		}
+		if sb.limits.Memory > 0 {
+			args = append(args, "--memory", strconv.FormatInt(sb.limits.Memory, 10))
+		}
+		if sb.limits.CPUTime > 0 {
+			args = append(args, "--ulimit", "cpu="+strconv.FormatInt(cpuSeconds(sb.limits.CPUTime), 10))
+		}
+		for _, kv := range env {
+			args = append(args, "-e", kv)
+		}
+		args = append(args, runnerConfig.ContainerImage, "/bin/bash", filepath.Join(containerHomeDir, filepath.Base(wrappedScript)))
+		cmd := exec.CommandContext(ctx, runnerConfig.ContainerRuntime, args...)
+		cmd.Dir = homeDir
+		// The runtime's client forwards the signal to the container, which a kill would leave running
+		cmd.Cancel = func() error {
+			return cmd.Process.Signal(syscall.SIGTERM)
+		}
+		return cmd
+	}
+
+	cmd := exec.CommandContext(ctx, "/bin/bash", wrappedScript)
+	cmd.Dir = homeDir
+	if sb.isolation == IsolationRestricted {
+		baseEnv = scrubEnv(baseEnv)
+	}
+	cmd.Env = appendOrReplaceEnv(baseEnv, "HOME", homeDir)
+	if artifactDir != "" {
+		cmd.Env = appendOrReplaceEnv(cmd.Env, artifactDirEnv, artifactDir)
+	}
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		cmd.Env = appendOrReplaceEnv(cmd.Env, k, v)
+	}
+	if sb.isolation == IsolationRestricted {
+		setProcessGroup(cmd)
+		if err := withSeccomp(cmd); err != nil {
+			// Start reports the error
+			cmd.Err = err
+		}
+	}
+	return cmd
+}
+
+// usage returns the resources used by a finished command, and the limit that stopped it.
+// CPU and memory are not reported for containers, since the command is the container
+// runtime's client rather than the skill.
+func (sb sandbox) usage(ctx context.Context, cmd *exec.Cmd, wallTime time.Duration) runners.Usage {
+	u := runners.Usage{WallTime: wallTime}
+	if errors.Is(context.Cause(ctx), errWallClockExceeded) {
+		u.LimitExceeded = runners.LimitWallClock
+	}
+	state := cmd.ProcessState
+	if state == nil {
+		return u
+	}
+	if sb.isolation == IsolationContainer {
+		// 137 is SIGKILL, which the kernel sends when the container exceeds its memory, and
+		// 152 is SIGXCPU, which it sends when the skill reaches its CPU time
+		switch {
+		case u.LimitExceeded != "":
+		case sb.limits.Memory > 0 && state.ExitCode() == 137:
+			u.LimitExceeded = runners.LimitMemory
+		case sb.limits.CPUTime > 0 && state.ExitCode() == 152:
+			u.LimitExceeded = runners.LimitCPUTime
+		}
+		return u
+	}
+	u.CPUTime = state.UserTime() + state.SystemTime()
+	u.MaxMemory = maxRSS(state)
+	// The kernel accounts CPU time in ticks, so a process it stops may show slightly less
+	if u.LimitExceeded == "" && sb.limits.CPUTime > 0 && killedByCPULimit(state) && u.CPUTime >= sb.limits.CPUTime*9/10 {
+		u.LimitExceeded = runners.LimitCPUTime
+	}
+	return u
+}
+
+// scrubEnv keeps only the variables a skill needs to locate its runtime.
+func scrubEnv(env []string) []string {
+	var kept []string
+	for _, kv := range env {
+		k, _, _ := strings.Cut(kv, "=")
+		switch k {
+		case "PATH", "LANG", "LC_ALL", "TZ", "TMPDIR":
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
+
+func parseLimitDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("must not be negative")
+	}
+	return d, nil
+}
+
+// parseBytes parses a size such as 1048576, 512K, 256M or 2G.
+func parseBytes(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	mult := int64(1)
+	switch strings.ToUpper(s[len(s)-1:]) {
+	case "K":
+		mult = 1 << 10
+	case "M":
+		mult = 1 << 20
+	case "G":
+		mult = 1 << 30
+	}
+	if mult > 1 {
+		s = s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
@@ -0,0 +1,219 @@
+package stdiorunner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/tangent/runners"
+	"github.com/tansive/tansive-internal/internal/tangent/tangentcommon"
+	"github.com/tansive/tansive-internal/pkg/api"
+)
+
+func TestLimits(t *testing.T) {
+	l, err := LimitsConfig{CPUTime: "30s", Memory: "512M", Timeout: "5m"}.parse()
+	require.NoError(t, err)
+	assert.Equal(t, Limits{CPUTime: 30 * time.Second, Memory: 512 << 20, Timeout: 5 * time.Minute}, l)
+
+	_, err = LimitsConfig{Memory: "lots"}.parse()
+	assert.Error(t, err)
+	_, err = LimitsConfig{Timeout: "-1s"}.parse()
+	assert.Error(t, err)
+
+	// Skills can lower the runner's limits but not raise or remove them
+	max := Limits{CPUTime: time.Minute, Timeout: time.Hour}
+	got := Limits{CPUTime: 2 * time.Minute, Memory: 1 << 30, Timeout: time.Minute}.within(max)
+	assert.Equal(t, Limits{CPUTime: time.Minute, Memory: 1 << 30, Timeout: time.Minute}, got)
+	assert.Equal(t, max, Limits{}.within(max))
+
+	// Sessions use up CPU and wall-clock time but not memory
+	session := Limits{CPUTime: time.Minute, Memory: 1 << 30, Timeout: time.Hour}
+	left, exceeded := session.remaining(runners.Usage{CPUTime: 20 * time.Second, WallTime: time.Minute, MaxMemory: 1 << 30})
+	assert.Equal(t, Limits{CPUTime: 40 * time.Second, Memory: 1 << 30, Timeout: 59 * time.Minute}, left)
+	assert.Empty(t, exceeded)
+	_, exceeded = session.remaining(runners.Usage{CPUTime: time.Minute})
+	assert.Equal(t, runners.LimitCPUTime, exceeded)
+	_, exceeded = session.remaining(runners.Usage{WallTime: 2 * time.Hour})
+	assert.Equal(t, runners.LimitWallClock, exceeded)
+	left, exceeded = Limits{}.remaining(runners.Usage{CPUTime: time.Hour})
+	assert.Equal(t, Limits{}, left)
+	assert.Empty(t, exceeded)
+
+	assert.Equal(t, "ulimit -t 2\nulimit -v 1024\nulimit -c 0\n",
+		Limits{CPUTime: 1500 * time.Millisecond, Memory: 1 << 20}.shellLimits(IsolationRestricted))
+	assert.Equal(t, "ulimit -t 2\n",
+		Limits{CPUTime: 1500 * time.Millisecond, Memory: 1 << 20}.shellLimits(IsolationContainer))
+}
+
+func TestSandboxedRun(t *testing.T) {
+	scriptDir := t.TempDir()
+	script := `#!/bin/bash
+case "$(echo "$1" | sed -n 's/.*"mode":"\([a-z]*\)".*/\1/p')" in
+  spin) while true; do :; done ;;
+  sleep) sleep 30 ;;
+  unshare) unshare --user true && echo "UNSHARE=allowed" || echo "UNSHARE=denied" ;;
+  *) echo "SECRET=${TANGENT_TEST_SECRET:-}" ;;
+esac
+`
+	require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "sandbox.sh"), []byte(script), 0755))
+	t.Setenv("TANGENT_TEST_SECRET", "hunter2")
+
+	saved := runnerConfig
+	t.Cleanup(func() { runnerConfig = saved })
+
+	tests := []struct {
+		name      string
+		isolation Isolation
+		security  string
+		mode      string
+		wantErr   bool
+		wantLimit runners.Limit
+		check     func(t *testing.T, stdout string)
+	}{
+		{
+			name:      "process inherits environment",
+			isolation: IsolationProcess,
+			security:  `{"type": "default"}`,
+			check: func(t *testing.T, stdout string) {
+				assert.Contains(t, stdout, "SECRET=hunter2")
+			},
+		},
+		{
+			name:      "sandboxed skill runs restricted",
+			isolation: IsolationProcess,
+			security:  `{"type": "sandboxed"}`,
+			check: func(t *testing.T, stdout string) {
+				assert.Contains(t, stdout, "SECRET=\n")
+			},
+		},
+		{
+			name:      "restricted skill cannot create namespaces",
+			isolation: IsolationRestricted,
+			security:  `{"type": "default"}`,
+			mode:      "unshare",
+			check: func(t *testing.T, stdout string) {
+				if runtime.GOOS != "linux" {
+					t.Skip("seccomp is only available on linux")
+				}
+				if _, err := exec.LookPath("unshare"); err != nil {
+					t.Skip("unshare not installed")
+				}
+				assert.Contains(t, stdout, "UNSHARE=denied")
+			},
+		},
+		{
+			name:      "wall-clock limit",
+			isolation: IsolationRestricted,
+			security:  `{"type": "default", "limits": {"timeout": "200ms"}}`,
+			mode:      "sleep",
+			wantErr:   true,
+			wantLimit: runners.LimitWallClock,
+		},
+		{
+			name:      "cpu time limit",
+			isolation: IsolationRestricted,
+			security:  `{"type": "default", "limits": {"cpuTime": "1s", "timeout": "20s"}}`,
+			mode:      "spin",
+			wantErr:   true,
+			wantLimit: runners.LimitCPUTime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runnerConfig = &RunnerConfig{ScriptDir: scriptDir, Isolation: tt.isolation}
+
+			var security map[string]any
+			require.NoError(t, json.Unmarshal([]byte(tt.security), &security))
+			configMap := map[string]any{
+				"version":  Version,
+				"runtime":  "bash",
+				"script":   "sandbox.sh",
+				"security": security,
+			}
+
+			var stdout, stderr strings.Builder
+			var usage runners.Usage
+			sessionID := fmt.Sprintf("sandbox-test-%d", time.Now().UnixNano())
+			t.Cleanup(func() { os.RemoveAll(filepath.Join(os.TempDir(), sessionID)) })
+
+			err := backend{}.Exec(context.Background(), &runners.ExecRequest{
+				SessionID: sessionID,
+				Config:    configMap,
+				Args: &api.SkillInputArgs{
+					SessionID: sessionID,
+					SkillName: "test-skill",
+					InputArgs: map[string]any{"mode": tt.mode},
+				},
+				Writers: []*tangentcommon.IOWriters{{Out: &stdout, Err: &stderr}},
+				Usage:   &usage,
+			})
+			t.Logf("stderr: %s", stderr.String())
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrLimitExceeded)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantLimit, usage.LimitExceeded)
+			assert.Positive(t, usage.WallTime)
+			if tt.check != nil {
+				tt.check(t, stdout.String())
+			}
+		})
+	}
+}
+
+func TestSessionLimits(t *testing.T) {
+	scriptDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(scriptDir, "sleep.sh"), []byte("#!/bin/bash\nsleep 30\n"), 0755))
+
+	saved := runnerConfig
+	t.Cleanup(func() { runnerConfig = saved })
+	runnerConfig = &RunnerConfig{
+		ScriptDir:     scriptDir,
+		Isolation:     IsolationRestricted,
+		SessionLimits: Limits{CPUTime: 10 * time.Second, Timeout: time.Second},
+	}
+
+	run := func(sessionUsage runners.Usage) (runners.Usage, error) {
+		sessionID := fmt.Sprintf("session-limits-test-%d", time.Now().UnixNano())
+		t.Cleanup(func() { os.RemoveAll(filepath.Join(os.TempDir(), sessionID)) })
+		var stdout, stderr strings.Builder
+		var usage runners.Usage
+		err := backend{}.Exec(context.Background(), &runners.ExecRequest{
+			SessionID: sessionID,
+			Config: map[string]any{
+				"version":  Version,
+				"runtime":  "bash",
+				"script":   "sleep.sh",
+				"security": map[string]any{"type": "default"},
+			},
+			Args:         &api.SkillInputArgs{SessionID: sessionID, SkillName: "test-skill"},
+			Writers:      []*tangentcommon.IOWriters{{Out: &stdout, Err: &stderr}},
+			Usage:        &usage,
+			SessionUsage: sessionUsage,
+		})
+		return usage, err
+	}
+
+	// An execution only gets what is left of the session's wall-clock time
+	usage, err := run(runners.Usage{WallTime: 800 * time.Millisecond})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Equal(t, runners.LimitWallClock, usage.LimitExceeded)
+	assert.Less(t, usage.WallTime, time.Second)
+
+	// A session that used up a limit cannot run more skills
+	usage, err = run(runners.Usage{CPUTime: 10 * time.Second})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Equal(t, runners.LimitCPUTime, usage.LimitExceeded)
+	assert.Zero(t, usage.WallTime)
+}
@@ -0,0 +1,185 @@
+//go:build linux && (amd64 || arm64)
+
+package stdiorunner
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// seccompExecName is the name tangent runs itself under to start a restricted skill. The
+// process installs the seccomp filter and then executes the skill in its place.
+const seccompExecName = "tansive-seccomp-exec"
+
+func init() {
+	if len(os.Args) > 1 && os.Args[0] == seccompExecName {
+		seccompExec(os.Args[1:])
+	}
+}
+
+// blockedSyscalls are denied with EPERM to restricted skills. They administer the host,
+// inspect other processes or escape the process's namespaces, none of which a skill needs.
+var blockedSyscalls = []uint32{
+	unix.SYS_ACCT,
+	unix.SYS_ADD_KEY,
+	unix.SYS_ADJTIMEX,
+	unix.SYS_BPF,
+	unix.SYS_CHROOT,
+	unix.SYS_CLOCK_ADJTIME,
+	unix.SYS_CLOCK_SETTIME,
+	unix.SYS_DELETE_MODULE,
+	unix.SYS_FANOTIFY_INIT,
+	unix.SYS_FINIT_MODULE,
+	unix.SYS_FSCONFIG,
+	unix.SYS_FSMOUNT,
+	unix.SYS_FSOPEN,
+	unix.SYS_INIT_MODULE,
+	unix.SYS_KEXEC_FILE_LOAD,
+	unix.SYS_KEXEC_LOAD,
+	unix.SYS_KEYCTL,
+	unix.SYS_MOUNT,
+	unix.SYS_MOUNT_SETATTR,
+	unix.SYS_MOVE_MOUNT,
+	unix.SYS_NAME_TO_HANDLE_AT,
+	unix.SYS_OPEN_BY_HANDLE_AT,
+	unix.SYS_OPEN_TREE,
+	unix.SYS_PERF_EVENT_OPEN,
+	unix.SYS_PERSONALITY,
+	unix.SYS_PIVOT_ROOT,
+	unix.SYS_PROCESS_VM_READV,
+	unix.SYS_PROCESS_VM_WRITEV,
+	unix.SYS_PTRACE,
+	unix.SYS_QUOTACTL,
+	unix.SYS_REBOOT,
+	unix.SYS_REQUEST_KEY,
+	unix.SYS_SETDOMAINNAME,
+	unix.SYS_SETHOSTNAME,
+	unix.SYS_SETNS,
+	unix.SYS_SETTIMEOFDAY,
+	unix.SYS_SWAPOFF,
+	unix.SYS_SWAPON,
+	unix.SYS_UMOUNT2,
+	unix.SYS_UNSHARE,
+	unix.SYS_USERFAULTFD,
+}
+
+// namespaceCloneFlags are the flags of clone that create namespaces
+const namespaceCloneFlags = unix.CLONE_NEWNS | unix.CLONE_NEWUTS | unix.CLONE_NEWIPC |
+	unix.CLONE_NEWUSER | unix.CLONE_NEWPID | unix.CLONE_NEWNET | unix.CLONE_NEWCGROUP
+
+// Offsets of the fields of struct seccomp_data
+const (
+	seccompDataNr   = 0
+	seccompDataArch = 4
+	seccompDataArg0 = 16 // low word, on little-endian architectures
+)
+
+// x32SyscallBit marks the syscalls of the x32 ABI, which has its own numbers
+const x32SyscallBit = 0x40000000
+
+// withSeccomp makes the command run with the seccomp filter installed, by starting tangent
+// under seccompExecName with the command's path and arguments.
+func withSeccomp(cmd *exec.Cmd) error {
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("unable to locate tangent executable: %w", err)
+	}
+	cmd.Args = append([]string{seccompExecName, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = self
+	return nil
+}
+
+// seccompExec installs the seccomp filter and executes args in place of the process. It
+// does not return.
+func seccompExec(args []string) {
+	// The filter and the exec must happen on the same thread
+	runtime.LockOSThread()
+	if err := installSeccompFilter(); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to install seccomp filter: %v\n", err)
+		os.Exit(126)
+	}
+	err := syscall.Exec(args[0], args, os.Environ())
+	fmt.Fprintf(os.Stderr, "unable to execute %s: %v\n", args[0], err)
+	os.Exit(127)
+}
+
+// installSeccompFilter installs the filter on all threads of the process. The process can
+// no longer gain privileges, so the filter applies to everything it executes.
+func installSeccompFilter() error {
+	filter, err := seccompFilter()
+	if err != nil {
+		return err
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("unable to set no_new_privs: %w", err)
+	}
+	prog := unix.SockFprog{Len: uint16(len(filter)), Filter: &filter[0]}
+	if _, _, errno := unix.Syscall(unix.SYS_SECCOMP, unix.SECCOMP_SET_MODE_FILTER, unix.SECCOMP_FILTER_FLAG_TSYNC, uintptr(unsafe.Pointer(&prog))); errno != 0 {
+		return fmt.Errorf("seccomp: %w", errno)
+	}
+	return nil
+}
+
+// seccompFilter returns the BPF program of the filter. Syscalls of other architectures kill
+// the process. The blocked syscalls and clones into new namespaces fail with EPERM, and
+// clone3, whose flags the filter cannot inspect, fails with ENOSYS so that the C library
+// falls back to clone.
+func seccompFilter() ([]unix.SockFilter, error) {
+	var arch uint32
+	switch runtime.GOARCH {
+	case "amd64":
+		arch = unix.AUDIT_ARCH_X86_64
+	case "arm64":
+		arch = unix.AUDIT_ARCH_AARCH64
+	default:
+		return nil, fmt.Errorf("seccomp filter is not supported on %s", runtime.GOARCH)
+	}
+	const (
+		load    = unix.BPF_LD | unix.BPF_W | unix.BPF_ABS
+		jeq     = unix.BPF_JMP | unix.BPF_JEQ | unix.BPF_K
+		jge     = unix.BPF_JMP | unix.BPF_JGE | unix.BPF_K
+		jset    = unix.BPF_JMP | unix.BPF_JSET | unix.BPF_K
+		ret     = unix.BPF_RET | unix.BPF_K
+		allow   = unix.SECCOMP_RET_ALLOW
+		kill    = unix.SECCOMP_RET_KILL_PROCESS
+		eperm   = unix.SECCOMP_RET_ERRNO | uint32(unix.EPERM)
+		enosys  = unix.SECCOMP_RET_ERRNO | uint32(unix.ENOSYS)
+		nsFlags = namespaceCloneFlags
+	)
+
+	filter := []unix.SockFilter{
+		{Code: load, K: seccompDataArch},
+		{Code: jeq, K: arch, Jt: 1},
+		{Code: ret, K: kill},
+		{Code: load, K: seccompDataNr},
+	}
+	if runtime.GOARCH == "amd64" {
+		filter = append(filter,
+			unix.SockFilter{Code: jge, K: x32SyscallBit, Jf: 1},
+			unix.SockFilter{Code: ret, K: eperm},
+		)
+	}
+	for _, nr := range blockedSyscalls {
+		filter = append(filter,
+			unix.SockFilter{Code: jeq, K: nr, Jf: 1},
+			unix.SockFilter{Code: ret, K: eperm},
+		)
+	}
+	filter = append(filter,
+		unix.SockFilter{Code: jeq, K: unix.SYS_CLONE3, Jf: 1},
+		unix.SockFilter{Code: ret, K: enosys},
+		// The flags replace the syscall number, so clone is checked last
+		unix.SockFilter{Code: jeq, K: unix.SYS_CLONE, Jf: 3},
+		unix.SockFilter{Code: load, K: seccompDataArg0},
+		unix.SockFilter{Code: jset, K: nsFlags, Jf: 1},
+		unix.SockFilter{Code: ret, K: eperm},
+		unix.SockFilter{Code: ret, K: allow},
+	)
+	return filter, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux || !(amd64 || arm64)
+
+package stdiorunner
+
+import "os/exec"
+
+// withSeccomp leaves the command unchanged, since seccomp is only available on Linux.
+func withSeccomp(cmd *exec.Cmd) error {
+	return nil
+}
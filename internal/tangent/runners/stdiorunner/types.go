@@ -12,6 +12,7 @@ import (
 //	{
 //	  "version": "0.1.0",
 //	  "runtime": "bash",
+//	  "protocol": "args",
 //	  "runtimeConfig": {
 //	    "key1": "value1"
 //	  },
@@ -20,13 +21,15 @@ import (
 //	  },
 //	  "script": "my-script.sh",
 //	  "security": {
-//	    "type": "default"
+//	    "type": "default",
+//	    "limits": {"cpuTime": "30s", "memory": "512M", "timeout": "5m"}
 //	  }
 //	}
 type Config struct {
 	Version       string            `json:"version"`       // must be compatible with current version
 	Runtime       Runtime           `json:"runtime"`       // must be one of ValidRunTimes
-	RuntimeConfig map[string]any    `json:"runtimeConfig"` // optional runtime-specific settings
+	Protocol      Protocol          `json:"protocol"`      // defaults to "args" if empty
+	RuntimeConfig map[string]any    `json:"runtimeConfig"` // optional runtime-specific settings, see RuntimeSettings
 	Env           map[string]string `json:"env"`           // optional environment variables
 	Script        string            `json:"script"`        // must be non-empty
 	Security      Security          `json:"security"`      // defaults to "default" if empty
@@ -56,6 +59,27 @@ const (
 	RuntimeBinary Runtime = "binary"
 )
 
+// Protocol specifies how a skill receives its arguments and returns its output.
+// The value must be one of the constants defined below.
+type Protocol string
+
+const (
+	// ProtocolArgs passes the arguments as JSON in the first command-line argument.
+	// Whatever the skill writes to stdout is its output.
+	ProtocolArgs Protocol = "args"
+
+	// ProtocolJSONRPC sends the arguments as a JSON-RPC 2.0 request on stdin and reads
+	// notifications and the response from stdout, one message per line. See jsonrpc.go.
+	ProtocolJSONRPC Protocol = "jsonrpc"
+)
+
+// ValidProtocols defines the supported protocols.
+// Only protocols in this map are allowed in Config.Protocol.
+var ValidProtocols = map[Protocol]struct{}{
+	ProtocolArgs:    {},
+	ProtocolJSONRPC: {},
+}
+
 // SecurityType specifies the security profile for command execution.
 // The value must be one of the constants defined below.
 type SecurityType string
@@ -65,13 +89,15 @@ const (
 	SecurityTypeDefault SecurityType = "default"
 
 	// SecurityTypeSandboxed provides enhanced security constraints.
+	// Runs with at least restricted isolation regardless of the runner's isolation.
 	SecurityTypeSandboxed SecurityType = "sandboxed"
 )
 
 // Security defines the security settings for command execution.
-// Type defaults to "default" if empty.
+// Type defaults to "default" if empty. Limits can only lower the runner's limits.
 type Security struct {
-	Type SecurityType `json:"type"` // must be one of ValidSecurityTypes
+	Type   SecurityType  `json:"type"`   // must be one of ValidSecurityTypes
+	Limits *LimitsConfig `json:"limits"` // optional resource limits
 }
 
 // ValidRunTimes defines the supported runtime environments.
@@ -104,12 +130,28 @@ func (c *Config) Validate() apperrors.Error {
 		return ErrInvalidRuntime
 	}
 
+	if c.Protocol == "" {
+		c.Protocol = ProtocolArgs
+	} else if _, ok := ValidProtocols[c.Protocol]; !ok {
+		return ErrInvalidProtocol
+	}
+
+	if _, err := c.runtimeSettings(); err != nil {
+		return err
+	}
+
 	if c.Security.Type == "" {
 		c.Security.Type = SecurityTypeDefault
 	} else if _, ok := ValidSecurityTypes[c.Security.Type]; !ok {
 		return ErrInvalidSecurity
 	}
 
+	if c.Security.Limits != nil {
+		if _, err := c.Security.Limits.parse(); err != nil {
+			return ErrInvalidSecurity.Msg("invalid limits: " + err.Error())
+		}
+	}
+
 	if c.Script == "" {
 		return ErrInvalidScript
 	}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	srvtangent "github.com/tansive/tansive-internal/internal/catalogsrv/tangent"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+	"github.com/tansive/tansive-internal/internal/tangent/runners"
+	"github.com/tansive/tansive-internal/internal/tangent/session"
+)
+
+// RegisterTangent registers this tangent with the Tansive server, with the enabled
+// runners as its capabilities. Runners must be initialized first.
+func RegisterTangent() error {
+	return config.RegisterTangent(Version, capabilities())
+}
+
+// StartHeartbeat sends heartbeats to the Tansive server at the configured interval until
+// ctx is done. If the server no longer knows this tangent, it registers again.
+func StartHeartbeat(ctx context.Context) error {
+	interval, err := config.Config().TansiveServer.GetHeartbeatInterval()
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			err := config.SendHeartbeat(heartbeat(interval))
+			if errors.Is(err, config.ErrNotRegistered) {
+				log.Warn().Msg("tangent not known to the tansive server, registering again")
+				err = RegisterTangent()
+			}
+			if err != nil {
+				log.Error().Err(err).Msg("failed to send heartbeat")
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// heartbeat returns the current state of this tangent. A tangent without enabled runners
+// cannot run skills and reports itself degraded.
+func heartbeat(interval time.Duration) *srvtangent.Heartbeat {
+	hb := &srvtangent.Heartbeat{
+		Status:          srvtangent.HealthHealthy,
+		Version:         Version,
+		Capabilities:    capabilities(),
+		ActiveSessions:  session.ActiveSessionCount(),
+		IntervalSeconds: int(interval / time.Second),
+	}
+	if len(hb.Capabilities) == 0 {
+		hb.Status = srvtangent.HealthDegraded
+	}
+	return hb
+}
+
+// capabilities returns the IDs of the enabled runners.
+func capabilities() []catcommon.RunnerID {
+	ids := []catcommon.RunnerID{}
+	for _, d := range runners.Registered() {
+		ids = append(ids, d.ID)
+	}
+	return ids
+}
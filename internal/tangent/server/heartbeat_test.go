@@ -0,0 +1,19 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	srvtangent "github.com/tansive/tansive-internal/internal/catalogsrv/tangent"
+)
+
+func TestHeartbeat(t *testing.T) {
+	hb := heartbeat(30 * time.Second)
+	assert.Equal(t, srvtangent.HealthHealthy, hb.Status)
+	assert.Equal(t, Version, hb.Version)
+	assert.Contains(t, hb.Capabilities, catcommon.RunnerID(catcommon.StdioRunnerID))
+	assert.Equal(t, 30, hb.IntervalSeconds)
+	assert.GreaterOrEqual(t, hb.ActiveSessions, 0)
+}
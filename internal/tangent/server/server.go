@@ -5,6 +5,8 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -12,8 +14,10 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/rs/zerolog/log"
 
+	"github.com/tansive/tansive-internal/internal/common/health"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
 	"github.com/tansive/tansive-internal/internal/common/logtrace"
+	"github.com/tansive/tansive-internal/internal/common/metrics"
 	"github.com/tansive/tansive-internal/internal/common/middleware"
 	"github.com/tansive/tansive-internal/internal/tangent/config"
 	"github.com/tansive/tansive-internal/internal/tangent/session"
@@ -22,7 +26,9 @@ import (
 // AgentServer provides the main HTTP server for the Tangent runtime.
 // Manages routing, middleware, and endpoint handling for session operations.
 type AgentServer struct {
-	Router *chi.Mux // HTTP router for request handling
+	Router  *chi.Mux          // HTTP router for request handling
+	Probes  *health.Probes    // checks of the /healthz, /livez and /readyz endpoints
+	Metrics *metrics.Registry // metrics served on the metrics port
 }
 
 // CreateNewServer creates a new AgentServer instance.
@@ -30,17 +36,28 @@ type AgentServer struct {
 func CreateNewServer() (*AgentServer, error) {
 	s := &AgentServer{}
 	s.Router = chi.NewRouter()
+	s.Probes = health.NewProbes()
+	s.Probes.AddReadiness("config", checkConfig)
+	s.Probes.AddReadiness("runners", checkRunners)
+	s.Metrics = metrics.NewRegistry("tangent")
+	s.Metrics.AddCollector(collectSessionMetrics)
 	return s, nil
 }
 
 // MountHandlers sets up all HTTP routes and middleware for the server.
-// Configures logging, panic handling, CORS, and resource endpoints.
+// Configures logging, panic handling, request limits, CORS, YAML negotiation, and resource endpoints.
 func (s *AgentServer) MountHandlers() {
+	if config.Config().MetricsPort != "" {
+		s.Router.Use(s.Metrics.Middleware)
+	}
 	s.Router.Use(middleware.RequestLogger)
 	s.Router.Use(middleware.PanicHandler)
+	s.Router.Use(middleware.LimitRequestBody(config.Config().MaxRequestBodySize))
+	s.Router.Use(middleware.HandlerTimeout(s.Router, handlerTimeouts()))
 	if config.Config().HandleCORS {
 		s.Router.Use(s.HandleCORS)
 	}
+	s.Router.Use(middleware.NegotiateYAML)
 	s.mountResourceHandlers(s.Router)
 	if logtrace.IsTraceEnabled() {
 		fmt.Println("Routes in tangent router")
@@ -54,6 +71,14 @@ func (s *AgentServer) MountHandlers() {
 	}
 }
 
+// handlerTimeouts returns the handler timeouts of the configuration, which were checked when
+// it was loaded. Timeouts that are not set are off.
+func handlerTimeouts() middleware.RouteTimeouts {
+	timeout, _ := config.Config().HTTP.GetHandlerTimeout()
+	routes, _ := config.Config().HTTP.GetRouteTimeouts()
+	return middleware.RouteTimeouts{Default: timeout, Routes: routes}
+}
+
 // mountResourceHandlers registers all resource endpoints on the router.
 // Sets up session management routes and system endpoints.
 func (s *AgentServer) mountResourceHandlers(r chi.Router) {
@@ -62,6 +87,7 @@ func (s *AgentServer) mountResourceHandlers(r chi.Router) {
 	})
 	r.Get("/version", s.getVersion)
 	r.Get("/ready", s.getReadiness)
+	s.Probes.Router(r)
 }
 
 // GetVersionRsp represents the response for version information.
@@ -94,6 +120,28 @@ func (s *AgentServer) getReadiness(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// checkConfig reports an error if the configuration was not loaded.
+func checkConfig(ctx context.Context) error {
+	if config.Config() == nil {
+		return errors.New("configuration not loaded")
+	}
+	return nil
+}
+
+// checkRunners reports an error if no runner is enabled, since the tangent cannot run
+// skills without one.
+func checkRunners(ctx context.Context) error {
+	if len(capabilities()) == 0 {
+		return errors.New("no runners are enabled")
+	}
+	return nil
+}
+
+// collectSessionMetrics adds the number of sessions that have not ended to the metrics.
+func collectSessionMetrics(w *metrics.Writer) {
+	w.Gauge("tangent_active_sessions", "Sessions that have not ended.", float64(session.ActiveSessionCount()))
+}
+
 // HandleCORS provides CORS middleware for cross-origin requests.
 // Configures allowed origins, methods, headers, and credentials handling.
 func (s *AgentServer) HandleCORS(next http.Handler) http.Handler {
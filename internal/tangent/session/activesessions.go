@@ -2,6 +2,9 @@ package session
 
 import (
 	"context"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -15,7 +18,10 @@ import (
 
 // activeSessions manages the collection of active sessions.
 // Provides thread-safe access to session storage and lifecycle management.
+// Ended sessions are kept for the configured retention period so that their outcome
+// can still be retrieved.
 type activeSessions struct {
+	mu       sync.RWMutex
 	sessions map[uuid.UUID]*session
 }
 
@@ -33,6 +39,7 @@ type ServerContext struct {
 	Variant          string                 `json:"variant"`           // variant name
 	Namespace        string                 `json:"namespace"`         // namespace for resource isolation
 	TenantID         catcommon.TenantId     `json:"tenant_id"`         // tenant identifier
+	UserID           string                 `json:"user_id"`           // user who created the session
 }
 
 var sessionManager *activeSessions
@@ -44,6 +51,9 @@ func (as *activeSessions) CreateSession(ctx context.Context, c *ServerContext, t
 	if c.SessionID == uuid.Nil {
 		return nil, ErrInvalidSession
 	}
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.pruneEndedSessions()
 	// if a session with the same ID already exists, return an error
 	if _, exists := as.sessions[c.SessionID]; exists {
 		return nil, ErrAlreadyExists.New("session already exists")
@@ -57,7 +67,11 @@ func (as *activeSessions) CreateSession(ctx context.Context, c *ServerContext, t
 		tokenExpiry:   tokenExpiry,
 		callGraph:     toolgraph.NewCallGraph(3), // max depth of 3
 		invocationIDs: make(map[string]*policy.ViewDefinition),
+		output:        newOutputLog(defaultOutputCapacity),
+		invocations:   newInvocationLog(config.GetRuntimeConfig().LogSigningKey.PrivateKey, config.GetRuntimeConfig().LogSigningKey.PublicKey),
 	}
+	session.lifecycle.status = SessionStatusCreated
+	session.lifecycle.createdAt = time.Now()
 	logger := log.Ctx(ctx)
 	if logger == nil {
 		newLogger := log.With().Str("session_id", c.SessionID.String()).Logger()
@@ -67,37 +81,76 @@ func (as *activeSessions) CreateSession(ctx context.Context, c *ServerContext, t
 	session.auditLogInfo.auditLogger = session.getLogger(TopicAuditLog)
 	session.auditLogInfo.auditLogPubKey = config.GetRuntimeConfig().LogSigningKey.PublicKey
 	as.sessions[c.SessionID] = session
+	if c.ViewDefinition != nil {
+		_, generation := views.get(session.viewKey())
+		views.put(session.viewKey(), c.ViewDefinition, generation)
+	}
 	return session, nil
 }
 
 // GetSession retrieves a session by its unique identifier.
 // Returns the session and any error encountered during retrieval.
 func (as *activeSessions) GetSession(id uuid.UUID) (*session, apperrors.Error) {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
 	if session, exists := as.sessions[id]; exists {
 		return session, nil
 	}
-	return nil, ErrInvalidSession
+	return nil, ErrSessionNotFound
 }
 
-// ListSessions returns all active sessions in the session manager.
+// ListSessions returns all sessions in the session manager, oldest first.
 // Returns the session list and any error encountered during listing.
 func (as *activeSessions) ListSessions() ([]*session, apperrors.Error) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.pruneEndedSessions()
 	var sessionList []*session
 	for _, session := range as.sessions {
 		sessionList = append(sessionList, session)
 	}
+	slices.SortFunc(sessionList, func(a, b *session) int {
+		if c := a.lifecycle.createdAt.Compare(b.lifecycle.createdAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.id.String(), b.id.String())
+	})
 	return sessionList, nil
 }
 
 // DeleteSession removes a session from the session manager.
 // Cleans up associated event bus subscriptions and resources.
 func (as *activeSessions) DeleteSession(id uuid.UUID) apperrors.Error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
 	if _, exists := as.sessions[id]; !exists {
-		return ErrInvalidSession
+		return ErrSessionNotFound
 	}
+	as.deleteSession(id)
+	return nil
+}
+
+func (as *activeSessions) deleteSession(id uuid.UUID) {
 	GetEventBus().CloseAllForPattern(GetAllSessionTopics(id.String()))
 	delete(as.sessions, id)
-	return nil
+}
+
+// pruneEndedSessions removes sessions that ended longer ago than the retention period.
+// Must be called with the lock held.
+func (as *activeSessions) pruneEndedSessions() {
+	if config.Config() == nil {
+		return
+	}
+	retention, err := config.Config().Session.GetRetention()
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-retention)
+	for id, session := range as.sessions {
+		if session.endedBefore(cutoff) {
+			as.deleteSession(id)
+		}
+	}
 }
 
 func init() {
@@ -106,6 +159,19 @@ func init() {
 	}
 }
 
+// ActiveSessionCount returns the number of sessions that have not ended.
+func ActiveSessionCount() int {
+	sessionManager.mu.RLock()
+	defer sessionManager.mu.RUnlock()
+	n := 0
+	for _, session := range sessionManager.sessions {
+		if !session.status().IsEnded() {
+			n++
+		}
+	}
+	return n
+}
+
 // ActiveSessionManager returns the global session manager instance.
 // Provides access to session lifecycle management functions.
 func ActiveSessionManager() SessionManager {
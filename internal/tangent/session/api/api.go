@@ -1,33 +1,49 @@
 // Package api provides data structures for session API operations.
-// Defines request and response types for session creation, retrieval, and listing operations.
+// Defines response types for session retrieval, listing, and termination operations.
 // All types support JSON serialization for HTTP API communication.
 package api
 
 import (
-	"github.com/tansive/tansive-internal/pkg/types"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	"github.com/tansive/tansive-internal/internal/tangent/artifacts"
 )
 
-// Session represents a session with its identifier and context.
-// Contains the session ID and optional context data for session operations.
+// Session represents a session with its identifier and lifecycle state.
+// Contains what the session runs, under which view, and how far it has progressed.
 type Session struct {
-	ID      string            `json:"id"`      // unique session identifier
-	Context types.NullableAny `json:"context"` // optional session context data
-}
+	ID              string     `json:"id"`                        // unique session identifier
+	Status          string     `json:"status"`                    // created, running, awaiting_approval, completed, failed, terminated or timed_out
+	SkillSet        string     `json:"skillset"`                  // skillset of the skill the session runs
+	Skill           string     `json:"skill"`                     // skill the session runs
+	View            string     `json:"view"`                      // view the session runs under
+	Catalog         string     `json:"catalog"`                   // catalog name
+	Variant         string     `json:"variant"`                   // variant name
+	Namespace       string     `json:"namespace,omitempty"`       // namespace name
+	ViewTokenExpiry *time.Time `json:"viewTokenExpiry,omitempty"` // expiry of the view token of the session
+	CreatedAt       time.Time  `json:"createdAt"`                 // when the session was created
+	StartedAt       *time.Time `json:"startedAt,omitempty"`       // when the session started running
+	EndedAt         *time.Time `json:"endedAt,omitempty"`         // when the session ended
+	Deadline        *time.Time `json:"deadline,omitempty"`        // when a running session times out
+	Error           string     `json:"error,omitempty"`           // why the session failed, timed out or was terminated
+	Usage           *Usage     `json:"usage,omitempty"`           // resources used by the skills the session ran
 
-// CreateSessionRequest represents a request to create a new session.
-// Contains session information required for session creation.
-type CreateSessionRequest struct {
-	Session
+	Artifacts []artifacts.Artifact `json:"artifacts,omitempty"` // files the session deposited that have not expired
 }
 
-// CreateSessionResponse represents the response from session creation.
-// Contains the ID of the newly created session.
-type CreateSessionResponse struct {
-	ID string `json:"id"` // unique identifier of the created session
+// Usage represents the resources used by the skills run in a session.
+// CPU time and memory are reported only by runners that can measure them.
+type Usage struct {
+	Executions    int    `json:"executions"`              // number of skill executions
+	WallTimeMs    int64  `json:"wallTimeMs"`              // total wall-clock time of the executions
+	CPUTimeMs     int64  `json:"cpuTimeMs"`               // total CPU time of the executions
+	MaxMemory     int64  `json:"maxMemory"`               // peak resident set size of any execution, in bytes
+	LimitExceeded string `json:"limitExceeded,omitempty"` // the last limit that stopped an execution
 }
 
 // GetSessionResponse represents the response from session retrieval.
-// Contains the complete session information including ID and context.
+// Contains the complete session information.
 type GetSessionResponse struct {
 	Session
 }
@@ -37,3 +53,33 @@ type GetSessionResponse struct {
 type ListSessionsResponse struct {
 	Sessions []Session `json:"sessions"` // array of session objects
 }
+
+// ListArtifactsResponse represents the response from listing the artifacts of a session.
+type ListArtifactsResponse struct {
+	Artifacts []artifacts.Artifact `json:"artifacts"` // artifacts that have not expired, oldest first
+}
+
+// Approval represents a skill invocation that waits for a person to approve or deny it,
+// because a rule of the session's view that allows it requires approval.
+type Approval struct {
+	ID           string        `json:"id"`                  // unique approval identifier
+	InvocationID string        `json:"invocationID"`        // invocation that waits for the approval
+	Skill        string        `json:"skill"`               // skill the invocation runs
+	Actions      []string      `json:"actions"`             // actions the skill requires
+	Rules        []policy.Rule `json:"rules"`               // rules of the view that require approval
+	Status       string        `json:"status"`              // pending, approved or denied
+	Reason       string        `json:"reason,omitempty"`    // reason given by the approver
+	DecidedBy    string        `json:"decidedBy,omitempty"` // catalog user who decided the approval
+	RequestedAt  time.Time     `json:"requestedAt"`         // when the invocation started waiting
+}
+
+// ListApprovalsResponse represents the response from listing the pending approvals of a session.
+type ListApprovalsResponse struct {
+	Approvals []Approval `json:"approvals"` // pending approvals, oldest first
+}
+
+// ApprovalDecisionRequest represents the body of a request to approve or deny an approval.
+// The body is optional.
+type ApprovalDecisionRequest struct {
+	Reason string `json:"reason,omitempty"` // why the approval was decided, recorded in the audit log
+}
@@ -0,0 +1,237 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+	"github.com/tansive/tansive-internal/internal/tangent/session/api"
+)
+
+// Decisions on a pending approval.
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusDenied   = "denied"
+)
+
+// pendingApproval is a skill invocation that is allowed by the view but waits for a person
+// to approve it, because a rule that allows it requires approval.
+type pendingApproval struct {
+	id           string
+	invocationID string
+	skill        string
+	actions      []string
+	rules        []policy.Rule
+	requestedAt  time.Time
+	decision     chan approvalDecision // buffered; receives exactly one decision
+}
+
+type approvalDecision struct {
+	approved bool
+	reason   string
+	approver string // catalog user who decided
+}
+
+// approvalGate holds the approvals a session waits for. The zero value is ready to use.
+// Guarded by its mutex, since approvals are decided from other requests.
+type approvalGate struct {
+	mu      sync.Mutex
+	pending []*pendingApproval
+}
+
+func (p *pendingApproval) info(status, reason string) api.Approval {
+	return api.Approval{
+		ID:           p.id,
+		InvocationID: p.invocationID,
+		Skill:        p.skill,
+		Actions:      p.actions,
+		Rules:        p.rules,
+		Status:       status,
+		Reason:       reason,
+		RequestedAt:  p.requestedAt,
+	}
+}
+
+// approvalRequired reports whether running the skill needs approval, and the rules of the
+// view that require it.
+func (s *session) approvalRequired(skillName string) (bool, []policy.Rule, apperrors.Error) {
+	skill, err := s.resolveSkill(skillName)
+	if err != nil {
+		return false, nil, err
+	}
+	return policy.ApprovalRequired(s.viewDef, s.skillSet.GetResourcePath(), skill.GetExportedActions())
+}
+
+// awaitApproval pauses the invocation until its approval is decided through the sessions
+// API. The session reports the awaiting_approval status while any approval is pending.
+// Returns ErrApprovalDenied if the invocation is denied, and an error if the session ends
+// before a decision is made.
+func (s *session) awaitApproval(ctx context.Context, invocationID, skillName string, actions []string, rules []policy.Rule) apperrors.Error {
+	p := &pendingApproval{
+		id:           uuid.New().String(),
+		invocationID: invocationID,
+		skill:        skillName,
+		actions:      actions,
+		rules:        rules,
+		requestedAt:  time.Now(),
+		decision:     make(chan approvalDecision, 1),
+	}
+	s.approvals.mu.Lock()
+	s.approvals.pending = append(s.approvals.pending, p)
+	s.approvals.mu.Unlock()
+	s.setAwaitingApproval(true)
+	defer func() {
+		s.approvals.mu.Lock()
+		s.approvals.pending = slices.DeleteFunc(s.approvals.pending, func(q *pendingApproval) bool { return q == p })
+		awaiting := len(s.approvals.pending) > 0
+		s.approvals.mu.Unlock()
+		s.setAwaitingApproval(awaiting)
+	}()
+
+	s.logger.Warn().Str("approval_id", p.id).Str("skill", skillName).Msgf("skill requires approval; waiting for approval %s", p.id)
+	s.auditLogInfo.auditLogger.Info().
+		Str("event", "approval_requested").
+		Str("approval_id", p.id).
+		Str("invocation_id", invocationID).
+		Str("skill", skillName).
+		Any("actions", actions).
+		Any("rules", rules).
+		Msg("approval requested")
+
+	select {
+	case <-ctx.Done():
+		s.auditLogInfo.auditLogger.Info().
+			Str("event", "approval_cancelled").
+			Str("approval_id", p.id).
+			Str("invocation_id", invocationID).
+			Msg("session ended while awaiting approval")
+		return ErrSessionError.Msg("session ended while awaiting approval: " + context.Cause(ctx).Error())
+	case d := <-p.decision:
+		status := ApprovalStatusApproved
+		if !d.approved {
+			status = ApprovalStatusDenied
+		}
+		s.logger.Info().Str("approval_id", p.id).Str("decision", status).Str("approver", d.approver).Msgf("approval %s %s by %s", p.id, status, d.approver)
+		s.auditLogInfo.auditLogger.Info().
+			Str("event", "approval_decision").
+			Str("approval_id", p.id).
+			Str("invocation_id", invocationID).
+			Str("decision", status).
+			Str("approver", d.approver).
+			Str("reason", d.reason).
+			Msg("approval decided")
+		if !d.approved {
+			msg := fmt.Sprintf("approval %s denied", p.id)
+			if d.reason != "" {
+				msg += ": " + d.reason
+			}
+			return ErrApprovalDenied.Msg(msg)
+		}
+		return nil
+	}
+}
+
+// decideApproval approves or denies a pending approval of the session on behalf of the
+// approver, resuming the invocation that waits for it.
+func (s *session) decideApproval(id string, approved bool, reason, approver string) (api.Approval, apperrors.Error) {
+	s.approvals.mu.Lock()
+	defer s.approvals.mu.Unlock()
+	i := slices.IndexFunc(s.approvals.pending, func(p *pendingApproval) bool { return p.id == id })
+	if i < 0 {
+		return api.Approval{}, ErrApprovalNotFound.Msg("no pending approval " + id)
+	}
+	p := s.approvals.pending[i]
+	// Removed here so that a second decision finds nothing to decide
+	s.approvals.pending = slices.Delete(s.approvals.pending, i, i+1)
+	p.decision <- approvalDecision{approved: approved, reason: reason, approver: approver}
+
+	status := ApprovalStatusApproved
+	if !approved {
+		status = ApprovalStatusDenied
+	}
+	info := p.info(status, reason)
+	info.DecidedBy = approver
+	return info, nil
+}
+
+// pendingApprovals returns the approvals the session waits for, oldest first.
+func (s *session) pendingApprovals() []api.Approval {
+	s.approvals.mu.Lock()
+	defer s.approvals.mu.Unlock()
+	approvals := make([]api.Approval, 0, len(s.approvals.pending))
+	for _, p := range s.approvals.pending {
+		approvals = append(approvals, p.info(ApprovalStatusPending, ""))
+	}
+	return approvals
+}
+
+// setAwaitingApproval moves a running session to awaiting_approval and back.
+func (s *session) setAwaitingApproval(awaiting bool) {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case awaiting && l.status == SessionStatusRunning:
+		l.status = SessionStatusAwaitingApproval
+	case !awaiting && l.status == SessionStatusAwaitingApproval:
+		l.status = SessionStatusRunning
+	}
+}
+
+type approverContextKey struct{}
+
+// withApprover returns a context carrying the catalog user who decides approvals.
+func withApprover(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, approverContextKey{}, userID)
+}
+
+// approverFromContext returns the catalog user who decides approvals, if authenticated.
+func approverFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(approverContextKey{}).(string)
+	return userID
+}
+
+// authorizeApprover asks the catalog server whether the holder of token may decide the
+// approvals of the session, and returns the user it identifies. The session's own token
+// never qualifies, so a skill cannot approve its own invocations.
+func authorizeApprover(ctx context.Context, sessionID uuid.UUID, token string) (string, apperrors.Error) {
+	client := getHTTPClient(&clientConfig{
+		token:       token,
+		tokenExpiry: time.Now().Add(forwardedTokenLifetime),
+		serverURL:   config.Config().TansiveServer.GetURL(),
+	})
+	if client == nil {
+		return "", ErrFailedRequestToTansiveServer.Msg("unable to create client")
+	}
+	body, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "sessions/" + sessionID.String() + "/approver",
+	})
+	if err != nil {
+		var httpErr *httpclient.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode < http.StatusInternalServerError {
+			log.Ctx(ctx).Warn().Err(err).Str("session_id", sessionID.String()).Msg("approver refused")
+			return "", ErrApproverNotAuthorized.Msg(httpErr.Message)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("unable to authorize approver")
+		return "", ErrFailedRequestToTansiveServer.Msg("unable to authorize approver: " + err.Error())
+	}
+	var approver srvsession.SessionApprover
+	if err := json.Unmarshal(body, &approver); err != nil || approver.UserID == "" {
+		return "", ErrFailedRequestToTansiveServer.Msg("unable to parse approver")
+	}
+	return approver.UserID, nil
+}
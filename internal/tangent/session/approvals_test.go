@@ -0,0 +1,62 @@
+package session
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+)
+
+func TestApprovalGate(t *testing.T) {
+	s := newLifecycleTestSession(time.Now().Add(time.Hour))
+	logger := zerolog.Nop()
+	s.logger = &logger
+	ctx, err := s.start(context.Background(), time.Minute)
+	require.NoError(t, err)
+
+	// await starts an invocation waiting for approval and returns the approval and the
+	// channel its result is sent on
+	await := func(skill string) (string, chan apperrors.Error) {
+		done := make(chan apperrors.Error, 1)
+		go func() {
+			done <- s.awaitApproval(ctx, skill+"-call", skill, []string{"kubernetes.deployments.restart"}, nil)
+		}()
+		require.Eventually(t, func() bool { return len(s.pendingApprovals()) == 1 }, time.Second, time.Millisecond)
+		return s.pendingApprovals()[0].ID, done
+	}
+
+	// An approved invocation resumes
+	id, done := await("restart-deployment")
+	assert.Equal(t, string(SessionStatusAwaitingApproval), s.info().Status)
+	assert.False(t, SessionStatusAwaitingApproval.IsEnded())
+	approval, err := s.decideApproval(id, true, "", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalStatusApproved, approval.Status)
+	assert.Equal(t, "alice", approval.DecidedBy)
+	assert.NoError(t, <-done)
+	assert.Equal(t, string(SessionStatusRunning), s.info().Status)
+	assert.Empty(t, s.pendingApprovals())
+
+	// An approval is decided once
+	_, err = s.decideApproval(id, false, "", "alice")
+	assert.ErrorIs(t, err, ErrApprovalNotFound)
+
+	// A denied invocation fails with the reason
+	id, done = await("restart-deployment")
+	_, err = s.decideApproval(id, false, "change freeze", "alice")
+	require.NoError(t, err)
+	err = <-done
+	assert.ErrorIs(t, err, ErrApprovalDenied)
+	assert.ErrorContains(t, err, "change freeze")
+
+	// A terminated session stops waiting
+	_, done = await("restart-deployment")
+	require.NoError(t, s.terminate())
+	assert.Error(t, <-done)
+	assert.Equal(t, SessionStatusTerminated, s.finish(ctx, nil))
+	assert.Empty(t, s.pendingApprovals())
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"os"
+
+	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
+	"github.com/tansive/tansive-internal/internal/tangent/artifacts"
+)
+
+// stageArtifacts creates the directory where a skill deposits files to keep as artifacts.
+// Returns an empty path if artifacts are not enabled or the directory cannot be created,
+// in which case the skill runs without one.
+func (s *session) stageArtifacts() string {
+	if artifacts.Default() == nil {
+		return ""
+	}
+	dir, err := os.MkdirTemp("", "tansive-artifacts-"+s.id.String()+"-")
+	if err != nil {
+		s.logger.Error().Err(err).Msg("unable to create artifact directory")
+		return ""
+	}
+	return dir
+}
+
+// collectArtifacts stores the files the skill left in the staging directory as artifacts
+// of the session and removes the directory. Files that cannot be stored are logged and
+// dropped; they do not fail the invocation.
+func (s *session) collectArtifacts(ctx context.Context, invocationID, dir string) {
+	if dir == "" {
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	stored, err := artifacts.Default().Collect(ctx, s.id.String(), s.artifactOwner(), invocationID, dir)
+	if err != nil {
+		s.logger.Warn().Err(err).Str("invocation_id", invocationID).Msg("some artifacts were not stored")
+	}
+	if len(stored) == 0 && err == nil {
+		return
+	}
+	names := make([]string, 0, len(stored))
+	for _, a := range stored {
+		names = append(names, a.Name)
+	}
+	event := s.auditLogInfo.auditLogger.Info()
+	if err != nil {
+		event = s.auditLogInfo.auditLogger.Warn().Str("error", err.Error())
+	}
+	event.
+		Str("event", "artifacts_collected").
+		Str("invocation_id", invocationID).
+		Strs("artifacts", names).
+		Msg("artifacts collected")
+}
+
+// artifactList returns the artifacts of the session that have not expired.
+func (s *session) artifactList() []artifacts.Artifact {
+	m := artifacts.Default()
+	if m == nil {
+		return nil
+	}
+	return m.List(s.id.String(), s.artifactOwner())
+}
+
+// artifactOwner returns the owner recorded with the artifacts of the session, the user who
+// created it.
+func (s *session) artifactOwner() string {
+	return artifactOwner(s.context.TenantID, s.context.UserID)
+}
+
+// artifactRefs returns references to the artifacts of the session, recorded with its
+// execution status in the catalog server.
+func (s *session) artifactRefs() []srvsession.ArtifactRef {
+	var refs []srvsession.ArtifactRef
+	for _, a := range s.artifactList() {
+		refs = append(refs, srvsession.ArtifactRef{
+			ID:        a.ID,
+			Name:      a.Name,
+			Size:      a.Size,
+			SHA256:    a.SHA256,
+			ExpiresAt: a.ExpiresAt,
+		})
+	}
+	return refs
+}
@@ -0,0 +1,91 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+)
+
+// forwardedTokenLifetime is the expiry given to a token of a request that is forwarded to
+// the catalog server, as the HTTP client sends only tokens with an expiry. The catalog
+// server checks the expiry of the token itself.
+const forwardedTokenLifetime = time.Minute
+
+type callerContextKey struct{}
+
+// withCaller returns a context carrying the catalog user who made the request.
+func withCaller(ctx context.Context, caller srvsession.SessionCaller) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, caller)
+}
+
+// callerFromContext returns the catalog user who made the request, if authenticated.
+func callerFromContext(ctx context.Context) (srvsession.SessionCaller, bool) {
+	caller, ok := ctx.Value(callerContextKey{}).(srvsession.SessionCaller)
+	return caller, ok && caller.UserID != ""
+}
+
+// authenticateCaller asks the catalog server which user holds token. Only tokens of users
+// are accepted, so a skill cannot use its session token to reach other sessions.
+func authenticateCaller(ctx context.Context, token string) (srvsession.SessionCaller, apperrors.Error) {
+	var caller srvsession.SessionCaller
+	client := getHTTPClient(&clientConfig{
+		token:       token,
+		tokenExpiry: time.Now().Add(forwardedTokenLifetime),
+		serverURL:   config.Config().TansiveServer.GetURL(),
+	})
+	if client == nil {
+		return caller, ErrFailedRequestToTansiveServer.Msg("unable to create client")
+	}
+	body, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "sessions/caller",
+	})
+	if err != nil {
+		var httpErr *httpclient.HTTPError
+		if errors.As(err, &httpErr) && httpErr.StatusCode < http.StatusInternalServerError {
+			log.Ctx(ctx).Warn().Err(err).Msg("caller refused")
+			return caller, ErrCallerNotAuthenticated.Msg(httpErr.Message)
+		}
+		log.Ctx(ctx).Error().Err(err).Msg("unable to authenticate caller")
+		return caller, ErrFailedRequestToTansiveServer.Msg("unable to authenticate caller: " + err.Error())
+	}
+	if err := json.Unmarshal(body, &caller); err != nil || caller.UserID == "" {
+		return caller, ErrFailedRequestToTansiveServer.Msg("unable to parse caller")
+	}
+	return caller, nil
+}
+
+// ownedBy reports whether the session was created by caller.
+func (s *session) ownedBy(caller srvsession.SessionCaller) bool {
+	return s.context != nil && s.context.UserID != "" &&
+		s.context.UserID == caller.UserID && s.context.TenantID == caller.TenantID
+}
+
+// artifactOwner returns the owner recorded with the artifacts of the sessions of a user.
+func artifactOwner(tenantID catcommon.TenantId, userID string) string {
+	return string(tenantID) + "/" + userID
+}
+
+// ownSessionFromRequest returns the session addressed by the request if it was created by
+// the caller. Sessions of other users are reported as not found, so that their IDs are not
+// disclosed.
+func ownSessionFromRequest(r *http.Request) (*session, apperrors.Error) {
+	session, err := sessionFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	caller, ok := callerFromContext(r.Context())
+	if !ok || !session.ownedBy(caller) {
+		return nil, ErrSessionNotFound
+	}
+	return session, nil
+}
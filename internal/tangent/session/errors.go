@@ -15,6 +15,18 @@ var (
 	// Occurs when session ID is invalid or session does not exist.
 	ErrInvalidSession apperrors.Error = ErrSessionError.New("invalid session").SetStatusCode(http.StatusBadRequest)
 
+	// ErrSessionNotFound is returned when no session has the given ID.
+	// Occurs when the session never existed or ended longer ago than the retention period.
+	ErrSessionNotFound apperrors.Error = ErrInvalidSession.New("session not found").SetStatusCode(http.StatusNotFound)
+
+	// ErrSessionNotRunnable is returned when a session is run more than once.
+	// Occurs when the session is already running, has ended, or was terminated before it started.
+	ErrSessionNotRunnable apperrors.Error = ErrSessionError.New("session cannot be run").SetStatusCode(http.StatusConflict)
+
+	// ErrSessionEnded is returned when terminating a session that has already ended.
+	// Occurs when the session completed, failed, timed out, or was already terminated.
+	ErrSessionEnded apperrors.Error = ErrSessionError.New("session has ended").SetStatusCode(http.StatusConflict)
+
 	// ErrAlreadyExists is returned when attempting to create a session that already exists.
 	// Occurs when a session with the same ID already exists in the session manager.
 	ErrAlreadyExists apperrors.Error = ErrSessionError.New("session already exists").SetStatusCode(http.StatusConflict)
@@ -63,6 +75,22 @@ var (
 	// Occurs when the current view does not authorize the required actions for the skill.
 	ErrBlockedByPolicy apperrors.Error = ErrSessionError.New("blocked by policy").SetStatusCode(http.StatusForbidden)
 
+	// ErrApprovalDenied is returned when an invocation that requires approval is denied.
+	// Occurs when the approver denies the pending approval through the sessions API.
+	ErrApprovalDenied apperrors.Error = ErrSessionError.New("denied by approver").SetStatusCode(http.StatusForbidden)
+
+	// ErrApprovalNotFound is returned when deciding an approval that is not pending.
+	// Occurs when the approval ID is unknown or the approval was already decided.
+	ErrApprovalNotFound apperrors.Error = ErrSessionError.New("approval not found").SetStatusCode(http.StatusNotFound)
+
+	// ErrApproverNotAuthorized is returned when the caller may not decide a session's approvals.
+	// Decisions must be made by a catalog user whose view allows approving the skillset.
+	ErrApproverNotAuthorized apperrors.Error = ErrSessionError.New("not authorized to decide approvals").SetStatusCode(http.StatusForbidden)
+
+	// ErrCallerNotAuthenticated is returned when the catalog server does not accept the token of a request.
+	// Sessions are served only to catalog users, who must present a valid catalog token.
+	ErrCallerNotAuthenticated apperrors.Error = ErrSessionError.New("unable to authenticate caller").SetStatusCode(http.StatusUnauthorized)
+
 	// ErrTokenRequired is returned when authentication token is missing.
 	// Occurs when a valid authentication token is required but not provided.
 	ErrTokenRequired apperrors.Error = ErrSessionError.New("token is required").SetStatusCode(http.StatusBadRequest)
@@ -3,7 +3,7 @@ package session
 import (
 	"fmt"
 
-	"github.com/tansive/tansive-internal/internal/tangent/eventbus"
+	"github.com/tansive/tansive-internal/internal/common/eventbus"
 )
 
 var eventBus *eventbus.EventBus
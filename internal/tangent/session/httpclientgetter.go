@@ -6,6 +6,7 @@ import (
 	"github.com/tansive/tansive-internal/internal/common/httpclient"
 	"github.com/tansive/tansive-internal/internal/tangent/config"
 	"github.com/tansive/tansive-internal/internal/tangent/runners"
+	_ "github.com/tansive/tansive-internal/internal/tangent/runners/builtin"
 )
 
 // clientConfig defines the configuration for HTTP client creation.
@@ -76,6 +77,6 @@ func SetTestMode(testMode bool) {
 
 // Init initializes the session package dependencies.
 // Must be called before using session functionality.
-func Init() {
-	runners.Init()
+func Init() error {
+	return runners.Init()
 }
@@ -0,0 +1,137 @@
+package session
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+)
+
+const (
+	// invocationShipInterval is how often recorded invocations are shipped to the catalog server
+	invocationShipInterval = 5 * time.Second
+
+	// invocationBatchSize is the most records shipped in one request
+	invocationBatchSize = 100
+)
+
+// invocationLog records the skill invocations of a session as a signed hash chain and
+// holds the records until they are shipped to the catalog server. Records that fail to
+// ship are kept and sent with the next batch.
+type invocationLog struct {
+	mu       sync.Mutex
+	seq      int64
+	prevHash string
+	pending  []srvsession.InvocationRecord
+	privKey  ed25519.PrivateKey
+	pubKey   ed25519.PublicKey
+
+	// shipMu serializes shipping, so that a record is not sent by two requests at once
+	shipMu sync.Mutex
+}
+
+func newInvocationLog(privKey, pubKey []byte) *invocationLog {
+	return &invocationLog{privKey: privKey, pubKey: pubKey}
+}
+
+// record signs a record, chaining it to the previous one, and queues it for shipping.
+// The outcome is derived from the policy decision and the error the invocation ended with.
+func (l *invocationLog) record(rec *srvsession.InvocationRecord, err apperrors.Error) {
+	rec.EndedAt = time.Now().UTC()
+	switch {
+	case rec.Decision == srvsession.InvocationDecisionBlocked, rec.Decision == srvsession.InvocationDecisionDenied:
+		rec.Outcome = srvsession.InvocationOutcomeBlocked
+	case err != nil:
+		rec.Outcome = srvsession.InvocationOutcomeFailed
+	default:
+		rec.Outcome = srvsession.InvocationOutcomeSuccess
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	rec.Seq = l.seq + 1
+	if err := rec.Sign(l.prevHash, l.privKey); err != nil {
+		log.Error().Err(err).Str("invocation_id", rec.InvocationID).Msg("unable to sign invocation record")
+		return
+	}
+	l.seq, l.prevHash = rec.Seq, rec.Hash
+	l.pending = append(l.pending, *rec)
+}
+
+// ship sends the pending records to the catalog server in batches, and drops those it
+// accepted.
+func (l *invocationLog) ship(client httpclient.HTTPClientInterface) apperrors.Error {
+	l.shipMu.Lock()
+	defer l.shipMu.Unlock()
+	for {
+		l.mu.Lock()
+		batch := srvsession.InvocationBatch{
+			VerificationKey: l.pubKey,
+			Records:         l.pending[:min(len(l.pending), invocationBatchSize)],
+		}
+		l.mu.Unlock()
+		if len(batch.Records) == 0 {
+			return nil
+		}
+
+		body, err := json.Marshal(batch)
+		if err != nil {
+			return ErrSessionError.Msg("unable to encode invocation records: " + err.Error())
+		}
+		_, _, err = client.DoRequest(httpclient.RequestOptions{
+			Method: http.MethodPost,
+			Path:   "sessions/execution-state/invocations",
+			Body:   body,
+		})
+		if err != nil {
+			return ErrFailedRequestToTansiveServer.Msg("unable to ship invocation records: " + err.Error())
+		}
+
+		// Records are only appended while shipping, so the batch is still at the front
+		l.mu.Lock()
+		l.pending = l.pending[len(batch.Records):]
+		l.mu.Unlock()
+	}
+}
+
+// recordInvocation adds the record of a skill invocation to the session's audit trail.
+func (s *session) recordInvocation(rec *srvsession.InvocationRecord, err apperrors.Error) {
+	s.invocations.record(rec, err)
+}
+
+// shipInvocations ships the session's invocation records to the catalog server until the
+// context is cancelled. Records left at the end are shipped by Finalize.
+func (s *session) shipInvocations(ctx context.Context) {
+	ticker := time.NewTicker(invocationShipInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.invocations.ship(s.tansiveClient()); err != nil {
+				log.Ctx(ctx).Warn().Err(err).Msg("unable to ship invocation records; will retry")
+			}
+		}
+	}
+}
+
+// tansiveClient returns a client for the catalog server authorized by the session's token.
+func (s *session) tansiveClient() httpclient.HTTPClientInterface {
+	return getHTTPClient(&clientConfig{
+		token:       s.token,
+		tokenExpiry: s.tokenExpiry,
+		serverURL:   config.Config().TansiveServer.GetURL(),
+	})
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+)
+
+// batchRecorder is a catalog server client that records the invocation batches it receives.
+type batchRecorder struct {
+	httpclient.HTTPClientInterface
+	batches []srvsession.InvocationBatch
+	fail    bool
+}
+
+func (c *batchRecorder) DoRequest(opts httpclient.RequestOptions) ([]byte, string, error) {
+	if c.fail {
+		return nil, "", errors.New("connection refused")
+	}
+	var batch srvsession.InvocationBatch
+	if err := json.Unmarshal(opts.Body, &batch); err != nil {
+		return nil, "", err
+	}
+	c.batches = append(c.batches, batch)
+	return nil, "", nil
+}
+
+func TestInvocationLog(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	l := newInvocationLog(privKey, pubKey)
+
+	l.record(&srvsession.InvocationRecord{InvocationID: "1", Skill: "list-pods", Decision: srvsession.InvocationDecisionAllowed}, nil)
+	l.record(&srvsession.InvocationRecord{InvocationID: "2", Skill: "delete-pod", Decision: srvsession.InvocationDecisionBlocked}, ErrBlockedByPolicy)
+
+	// Records are kept until the server accepts them
+	client := &batchRecorder{fail: true}
+	assert.Error(t, l.ship(client))
+	client.fail = false
+	l.record(&srvsession.InvocationRecord{InvocationID: "3", Skill: "list-pods", Decision: srvsession.InvocationDecisionAllowed}, ErrSessionError)
+	require.NoError(t, l.ship(client))
+	require.Len(t, client.batches, 1)
+	require.NoError(t, l.ship(client))
+	assert.Len(t, client.batches, 1)
+
+	batch := client.batches[0]
+	assert.Equal(t, []byte(pubKey), batch.VerificationKey)
+	require.Len(t, batch.Records, 3)
+	assert.Equal(t, srvsession.InvocationOutcomeSuccess, batch.Records[0].Outcome)
+	assert.Equal(t, srvsession.InvocationOutcomeBlocked, batch.Records[1].Outcome)
+	assert.Equal(t, srvsession.InvocationOutcomeFailed, batch.Records[2].Outcome)
+	assert.NotEmpty(t, batch.Records[2].Error)
+	assert.NoError(t, srvsession.VerifyInvocationChain(batch.Records, 0, "", pubKey))
+
+	// Later records continue the chain
+	l.record(&srvsession.InvocationRecord{InvocationID: "4", Skill: "list-pods"}, nil)
+	require.NoError(t, l.ship(client))
+	require.Len(t, client.batches, 2)
+	assert.NoError(t, srvsession.VerifyInvocationChain(client.batches[1].Records, 3, batch.Records[2].Hash, pubKey))
+}
@@ -0,0 +1,207 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/tangent/runners"
+	"github.com/tansive/tansive-internal/internal/tangent/session/api"
+)
+
+// SessionStatus is the lifecycle state of a session.
+type SessionStatus string
+
+const (
+	SessionStatusCreated          SessionStatus = "created"           // created, not yet running
+	SessionStatusRunning          SessionStatus = "running"           // skill execution in progress
+	SessionStatusAwaitingApproval SessionStatus = "awaiting_approval" // running, paused until an invocation is approved or denied
+	SessionStatusCompleted        SessionStatus = "completed"         // ran to completion
+	SessionStatusFailed           SessionStatus = "failed"            // ended with an error
+	SessionStatusTerminated       SessionStatus = "terminated"        // cancelled through the API
+	SessionStatusTimedOut         SessionStatus = "timed_out"         // exceeded its timeout or its view token expired
+)
+
+// IsEnded reports whether the status is final.
+func (s SessionStatus) IsEnded() bool {
+	return s != SessionStatusCreated && s != SessionStatusRunning && s != SessionStatusAwaitingApproval
+}
+
+var (
+	errSessionTerminated = errors.New("session terminated")
+	errSessionTimedOut   = errors.New("session timed out")
+)
+
+// sessionLifecycle tracks the state of a session from creation until it ends.
+// Guarded by its mutex, since sessions are inspected and terminated from other requests.
+type sessionLifecycle struct {
+	mu        sync.Mutex
+	status    SessionStatus
+	createdAt time.Time
+	startedAt time.Time
+	endedAt   time.Time
+	deadline  time.Time
+	err       string
+	cancel    context.CancelCauseFunc
+	usage     api.Usage
+}
+
+// start marks the session as running and returns the context it runs in, which is
+// cancelled when the session is terminated or its deadline passes. The deadline is the
+// session timeout or the expiry of the view token, whichever comes first.
+func (s *session) start(ctx context.Context, timeout time.Duration) (context.Context, apperrors.Error) {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.status != SessionStatusCreated {
+		return nil, ErrSessionNotRunnable.Msg("session is " + string(l.status))
+	}
+
+	deadline := time.Now().Add(timeout)
+	if !s.tokenExpiry.IsZero() && s.tokenExpiry.Before(deadline) {
+		deadline = s.tokenExpiry
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	ctx, cancelTimeout := context.WithDeadlineCause(ctx, deadline, errSessionTimedOut)
+	l.cancel = func(cause error) {
+		cancel(cause)
+		cancelTimeout()
+	}
+	l.status = SessionStatusRunning
+	l.startedAt = time.Now()
+	l.deadline = deadline
+	return ctx, nil
+}
+
+// finish records how the session ended, given the context it ran in and the error it
+// returned, and releases its context.
+func (s *session) finish(ctx context.Context, apperr apperrors.Error) SessionStatus {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.status.IsEnded() {
+		return l.status
+	}
+
+	switch cause := context.Cause(ctx); {
+	case errors.Is(cause, errSessionTerminated):
+		l.status = SessionStatusTerminated
+		l.err = cause.Error()
+	case errors.Is(cause, errSessionTimedOut):
+		l.status = SessionStatusTimedOut
+		l.err = cause.Error()
+	case apperr != nil:
+		l.status = SessionStatusFailed
+		l.err = apperr.Error()
+	default:
+		l.status = SessionStatusCompleted
+	}
+	l.endedAt = time.Now()
+	if l.cancel != nil {
+		l.cancel(nil)
+	}
+	s.output.close()
+	return l.status
+}
+
+// terminate cancels a session that has not ended. A session that has not started is ended
+// immediately; a running session ends once its skill has stopped.
+func (s *session) terminate() apperrors.Error {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case l.status.IsEnded():
+		return ErrSessionEnded.Msg("session has already " + string(l.status))
+	case l.status == SessionStatusCreated:
+		l.status = SessionStatusTerminated
+		l.err = errSessionTerminated.Error()
+		l.endedAt = time.Now()
+		s.output.close()
+	default:
+		l.cancel(errSessionTerminated)
+	}
+	return nil
+}
+
+// recordUsage adds the resources used by a skill execution to the session's usage.
+func (s *session) recordUsage(u runners.Usage) {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.usage.Executions++
+	l.usage.WallTimeMs += u.WallTime.Milliseconds()
+	l.usage.CPUTimeMs += u.CPUTime.Milliseconds()
+	l.usage.MaxMemory = max(l.usage.MaxMemory, u.MaxMemory)
+	if u.LimitExceeded != "" {
+		l.usage.LimitExceeded = string(u.LimitExceeded)
+	}
+}
+
+// runnerUsage returns the resources used by the session's skill executions so far.
+func (s *session) runnerUsage() runners.Usage {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return runners.Usage{
+		WallTime:  time.Duration(l.usage.WallTimeMs) * time.Millisecond,
+		CPUTime:   time.Duration(l.usage.CPUTimeMs) * time.Millisecond,
+		MaxMemory: l.usage.MaxMemory,
+	}
+}
+
+// status returns the lifecycle state of the session.
+func (s *session) status() SessionStatus {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status
+}
+
+// endedBefore reports whether the session ended before t.
+func (s *session) endedBefore(t time.Time) bool {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.status.IsEnded() && l.endedAt.Before(t)
+}
+
+// info returns the state of the session as reported by the sessions API. The view token
+// itself is not included.
+func (s *session) info() api.Session {
+	l := &s.lifecycle
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var usage *api.Usage
+	if l.usage.Executions > 0 {
+		u := l.usage
+		usage = &u
+	}
+	return api.Session{
+		ID:              s.id.String(),
+		Status:          string(l.status),
+		SkillSet:        s.context.SkillSet,
+		Skill:           s.context.Skill,
+		View:            s.context.View,
+		Catalog:         s.context.Catalog,
+		Variant:         s.context.Variant,
+		Namespace:       s.context.Namespace,
+		ViewTokenExpiry: timeOrNil(s.tokenExpiry),
+		CreatedAt:       l.createdAt,
+		StartedAt:       timeOrNil(l.startedAt),
+		EndedAt:         timeOrNil(l.endedAt),
+		Deadline:        timeOrNil(l.deadline),
+		Error:           l.err,
+		Usage:           usage,
+		Artifacts:       s.artifactList(),
+	}
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,209 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+	"github.com/tansive/tansive-internal/internal/tangent/runners"
+	"github.com/tansive/tansive-internal/internal/tangent/session/api"
+)
+
+func newLifecycleTestSession(tokenExpiry time.Time) *session {
+	s := &session{
+		id:          uuid.New(),
+		context:     &ServerContext{Skill: "k8s_troubleshooter", View: "dev-view", Catalog: "test-catalog", Variant: "dev", TenantID: "TABCDE", UserID: "user-1"},
+		tokenExpiry: tokenExpiry,
+		output:      newOutputLog(defaultOutputCapacity),
+	}
+	s.lifecycle.status = SessionStatusCreated
+	s.lifecycle.createdAt = time.Now()
+	return s
+}
+
+// serveTestCallers points the session package at a catalog server that authenticates the
+// tokens in callers, and allows them to approve the invocations of any session.
+func serveTestCallers(t *testing.T, callers map[string]srvsession.SessionCaller) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		caller, ok := callers[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		switch {
+		case !ok:
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/sessions/caller":
+			json.NewEncoder(w).Encode(caller)
+		case strings.HasSuffix(r.URL.Path, "/approver"):
+			json.NewEncoder(w).Encode(srvsession.SessionApprover{UserID: caller.UserID})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	if config.Config() == nil {
+		require.NoError(t, config.LoadConfig("../../../tangent.conf"))
+	}
+	serverURL, testMode := config.Config().TansiveServer.URL, isTestMode
+	config.Config().TansiveServer.URL = srv.URL
+	SetTestMode(false)
+	t.Cleanup(func() {
+		config.Config().TansiveServer.URL = serverURL
+		SetTestMode(testMode)
+	})
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	tokenExpiry := time.Now().Add(time.Hour)
+
+	// A session that runs to completion
+	s := newLifecycleTestSession(tokenExpiry)
+	ctx, err := s.start(context.Background(), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, string(SessionStatusRunning), s.info().Status)
+	assert.NotNil(t, s.info().Deadline)
+	_, err = s.start(context.Background(), time.Minute)
+	assert.ErrorIs(t, err, ErrSessionNotRunnable)
+	assert.Equal(t, SessionStatusCompleted, s.finish(ctx, nil))
+	assert.NotNil(t, s.info().EndedAt)
+	assert.ErrorIs(t, s.terminate(), ErrSessionEnded)
+
+	// A session that fails
+	s = newLifecycleTestSession(tokenExpiry)
+	ctx, err = s.start(context.Background(), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, SessionStatusFailed, s.finish(ctx, ErrExecutionFailed))
+	assert.Equal(t, ErrExecutionFailed.Error(), s.info().Error)
+
+	// A running session that is terminated
+	s = newLifecycleTestSession(tokenExpiry)
+	ctx, err = s.start(context.Background(), time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, s.terminate())
+	<-ctx.Done()
+	assert.Equal(t, SessionStatusTerminated, s.finish(ctx, ErrExecutionFailed))
+
+	// A session terminated before it starts cannot be run
+	s = newLifecycleTestSession(tokenExpiry)
+	require.NoError(t, s.terminate())
+	assert.Equal(t, string(SessionStatusTerminated), s.info().Status)
+	_, err = s.start(context.Background(), time.Minute)
+	assert.ErrorIs(t, err, ErrSessionNotRunnable)
+
+	// A session that times out
+	s = newLifecycleTestSession(tokenExpiry)
+	ctx, err = s.start(context.Background(), 10*time.Millisecond)
+	require.NoError(t, err)
+	<-ctx.Done()
+	assert.Equal(t, SessionStatusTimedOut, s.finish(ctx, ErrExecutionFailed))
+
+	// The deadline is capped by the expiry of the view token
+	s = newLifecycleTestSession(time.Now().Add(10 * time.Millisecond))
+	ctx, err = s.start(context.Background(), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, s.tokenExpiry, *s.info().Deadline)
+	<-ctx.Done()
+	assert.Equal(t, SessionStatusTimedOut, s.finish(ctx, nil))
+}
+
+func TestSessionUsage(t *testing.T) {
+	s := newLifecycleTestSession(time.Time{})
+	assert.Nil(t, s.info().Usage)
+
+	s.recordUsage(runners.Usage{WallTime: 2 * time.Second, CPUTime: time.Second, MaxMemory: 64 << 20})
+	s.recordUsage(runners.Usage{WallTime: time.Second, CPUTime: 500 * time.Millisecond, MaxMemory: 32 << 20, LimitExceeded: runners.LimitCPUTime})
+	assert.Equal(t, &api.Usage{
+		Executions:    2,
+		WallTimeMs:    3000,
+		CPUTimeMs:     1500,
+		MaxMemory:     64 << 20,
+		LimitExceeded: string(runners.LimitCPUTime),
+	}, s.info().Usage)
+}
+
+func TestSessionAPI(t *testing.T) {
+	running := newLifecycleTestSession(time.Now().Add(time.Hour))
+	_, err := running.start(context.Background(), time.Minute)
+	require.NoError(t, err)
+	created := newLifecycleTestSession(time.Now().Add(time.Hour))
+	sessionManager.mu.Lock()
+	sessionManager.sessions[running.id] = running
+	sessionManager.sessions[created.id] = created
+	sessionManager.mu.Unlock()
+	t.Cleanup(func() {
+		sessionManager.DeleteSession(running.id)
+		sessionManager.DeleteSession(created.id)
+	})
+
+	serveTestCallers(t, map[string]srvsession.SessionCaller{
+		"owner-token": {UserID: "user-1", TenantID: "TABCDE"},
+		"other-token": {UserID: "user-2", TenantID: "TABCDE"},
+	})
+
+	router := chi.NewRouter()
+	router.Route("/sessions", Router)
+	doAs := func(token, method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+	do := func(method, path string) *httptest.ResponseRecorder {
+		return doAs("owner-token", method, path)
+	}
+
+	// Sessions are served only to the user who created them
+	assert.Equal(t, http.StatusUnauthorized, doAs("", http.MethodGet, "/sessions").Code)
+	assert.Equal(t, http.StatusUnauthorized, doAs("bad-token", http.MethodGet, "/sessions").Code)
+	rr := doAs("other-token", http.MethodGet, "/sessions")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var others api.ListSessionsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &others))
+	assert.Empty(t, others.Sessions)
+	assert.Equal(t, http.StatusNotFound, doAs("other-token", http.MethodGet, "/sessions/"+created.id.String()).Code)
+	assert.Equal(t, http.StatusNotFound, doAs("other-token", http.MethodDelete, "/sessions/"+created.id.String()).Code)
+
+	// Artifacts are listed only to the owner of the session
+	assert.Equal(t, http.StatusOK, do(http.MethodGet, "/sessions/"+created.id.String()+"/artifacts").Code)
+	assert.Equal(t, http.StatusNotFound, doAs("other-token", http.MethodGet, "/sessions/"+created.id.String()+"/artifacts").Code)
+	assert.Equal(t, http.StatusUnauthorized, doAs("", http.MethodGet, "/sessions/"+created.id.String()+"/artifacts").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/sessions/"+uuid.New().String()+"/artifacts").Code)
+
+	// Approvers need not own the session
+	assert.Equal(t, http.StatusOK, doAs("other-token", http.MethodGet, "/sessions/"+running.id.String()+"/approvals").Code)
+	assert.Equal(t, http.StatusUnauthorized, doAs("", http.MethodGet, "/sessions/"+running.id.String()+"/approvals").Code)
+
+	rr = do(http.MethodGet, "/sessions?status=running")
+	require.Equal(t, http.StatusOK, rr.Code)
+	var list api.ListSessionsResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &list))
+	require.Len(t, list.Sessions, 1)
+	assert.Equal(t, running.id.String(), list.Sessions[0].ID)
+	assert.Equal(t, "dev-view", list.Sessions[0].View)
+
+	rr = do(http.MethodGet, "/sessions/"+created.id.String())
+	require.Equal(t, http.StatusOK, rr.Code)
+	var got api.GetSessionResponse
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, string(SessionStatusCreated), got.Status)
+
+	rr = do(http.MethodDelete, "/sessions/"+created.id.String())
+	require.Equal(t, http.StatusAccepted, rr.Code)
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+	assert.Equal(t, string(SessionStatusTerminated), got.Status)
+	assert.Equal(t, http.StatusConflict, do(http.MethodDelete, "/sessions/"+created.id.String()).Code)
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/sessions/"+uuid.New().String()).Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/sessions/not-a-uuid").Code)
+}
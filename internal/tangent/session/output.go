@@ -0,0 +1,93 @@
+package session
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+const (
+	// OutputStreamLog is the stream of session log events.
+	OutputStreamLog = "log"
+
+	// OutputStreamInteractive is the stream of the stdout and stderr of skills.
+	OutputStreamInteractive = "output"
+
+	// defaultOutputCapacity is the number of events a session retains for replay.
+	defaultOutputCapacity = 10000
+)
+
+// OutputEvent is an event of the output of a session. Offsets start at 0 and increase by
+// one for each event, so that a client can resume after the last event it received.
+type OutputEvent struct {
+	Offset int64           `json:"offset"` // position of the event in the session's output
+	Stream string          `json:"stream"` // log or output
+	Data   json.RawMessage `json:"data"`   // the event, a JSON log entry
+}
+
+// outputLog retains the most recent events of a session's output so that clients can
+// attach at any time and replay from an offset. Readers pull events at their own pace, so
+// a slow reader never holds up the session; a reader that falls further behind than the
+// capacity skips the events that were dropped.
+type outputLog struct {
+	mu       sync.Mutex
+	events   []OutputEvent
+	first    int64         // offset of events[0]
+	capacity int           // most events retained
+	notify   chan struct{} // closed and replaced when events are appended or the log is closed
+	closed   bool
+}
+
+func newOutputLog(capacity int) *outputLog {
+	return &outputLog{capacity: capacity, notify: make(chan struct{})}
+}
+
+// append adds an event to the log, dropping the oldest event if the log is full.
+func (o *outputLog) append(stream string, data []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return
+	}
+	if !json.Valid(data) {
+		data, _ = json.Marshal(string(data))
+	}
+	o.events = append(o.events, OutputEvent{
+		Offset: o.first + int64(len(o.events)),
+		Stream: stream,
+		Data:   data,
+	})
+	if dropped := len(o.events) - o.capacity; dropped > 0 {
+		// The backing array is reclaimed as append reallocates it
+		o.events = o.events[dropped:]
+		o.first += int64(dropped)
+	}
+	close(o.notify)
+	o.notify = make(chan struct{})
+}
+
+// close marks the end of the output. Readers receive the remaining events and then
+// see that the log is closed.
+func (o *outputLog) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.closed {
+		o.closed = true
+		close(o.notify)
+	}
+}
+
+// read returns the events from offset on, at most limit of them. If events before offset
+// were dropped, the first retained events are returned instead. When there are no events
+// from offset on, read returns a channel that is closed once there are, or once the log is
+// closed, and whether the log is closed.
+func (o *outputLog) read(offset int64, limit int) ([]OutputEvent, <-chan struct{}, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	offset = max(offset, o.first)
+	start := int(offset - o.first)
+	if start >= len(o.events) {
+		return nil, o.notify, o.closed
+	}
+	end := min(len(o.events), start+limit)
+	return append([]OutputEvent(nil), o.events[start:end]...), o.notify, o.closed
+}
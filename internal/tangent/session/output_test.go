@@ -0,0 +1,133 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
+)
+
+func TestOutputLog(t *testing.T) {
+	o := newOutputLog(3)
+	events, notify, closed := o.read(0, 10)
+	assert.Empty(t, events)
+	assert.False(t, closed)
+
+	o.append(OutputStreamLog, []byte(`{"message": "one"}`))
+	select {
+	case <-notify:
+	default:
+		t.Fatal("readers are not notified of new events")
+	}
+	o.append(OutputStreamInteractive, []byte(`not json`))
+	events, _, _ = o.read(0, 10)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(1), events[1].Offset)
+	assert.JSONEq(t, `"not json"`, string(events[1].Data))
+
+	// The oldest events are dropped beyond the capacity, and reads resume at the first
+	// retained event
+	o.append(OutputStreamLog, []byte(`{"message": "three"}`))
+	o.append(OutputStreamLog, []byte(`{"message": "four"}`))
+	o.append(OutputStreamLog, []byte(`{"message": "five"}`))
+	events, _, _ = o.read(0, 2)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(2), events[0].Offset)
+	events, notify, _ = o.read(5, 10)
+	assert.Empty(t, events)
+
+	o.close()
+	<-notify
+	o.append(OutputStreamLog, []byte(`{"message": "late"}`))
+	events, _, closed = o.read(4, 10)
+	assert.True(t, closed)
+	require.Len(t, events, 1)
+	assert.Equal(t, int64(4), events[0].Offset)
+}
+
+func TestStreamSessionOutput(t *testing.T) {
+	s := newLifecycleTestSession(time.Now().Add(time.Hour))
+	s.output = newOutputLog(3)
+	sessionManager.mu.Lock()
+	sessionManager.sessions[s.id] = s
+	sessionManager.mu.Unlock()
+	t.Cleanup(func() { sessionManager.DeleteSession(s.id) })
+
+	serveTestCallers(t, map[string]srvsession.SessionCaller{
+		"owner-token": {UserID: "user-1", TenantID: "TABCDE"},
+		"other-token": {UserID: "user-2", TenantID: "TABCDE"},
+	})
+
+	router := chi.NewRouter()
+	router.Route("/sessions", Router)
+	stream := func(query string, header http.Header) string {
+		req := httptest.NewRequest(http.MethodGet, "/sessions/"+s.id.String()+"/events"+query, nil)
+		req.Header.Set("Authorization", "Bearer owner-token")
+		for k, v := range header {
+			req.Header.Set(k, v[0])
+		}
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "text/event-stream", rr.Header().Get("Content-Type"))
+		return rr.Body.String()
+	}
+
+	// Output appended while a client is attached is streamed until the session ends
+	ctx, err := s.start(context.Background(), time.Minute)
+	require.NoError(t, err)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		s.output.append(OutputStreamLog, []byte(`{"message": "starting"}`))
+		s.output.append(OutputStreamInteractive, []byte(`{"source": "stdout", "message": "hello"}`))
+		s.finish(ctx, nil)
+	}()
+	body := stream("", nil)
+	assert.Contains(t, body, "id: 0\nevent: log\ndata: {\"offset\":0,\"stream\":\"log\",\"data\":{\"message\":\"starting\"}}\n\n")
+	assert.Contains(t, body, "id: 1\nevent: output\n")
+	assert.Contains(t, body, "event: end\ndata: {\"id\":\""+s.id.String()+"\",\"status\":\"completed\"")
+
+	// A client can resume after the last event it received, or filter a stream
+	body = stream("", http.Header{"Last-Event-ID": {"0"}})
+	assert.NotContains(t, body, "id: 0\n")
+	assert.Contains(t, body, "id: 1\n")
+	body = stream("?stream=output", nil)
+	assert.NotContains(t, body, "event: log\n")
+	assert.Contains(t, body, "event: output\n")
+
+	// Events dropped before the requested offset are reported as a gap
+	s.output = newOutputLog(1)
+	s.output.append(OutputStreamLog, []byte(`{"message": "one"}`))
+	s.output.append(OutputStreamLog, []byte(`{"message": "two"}`))
+	s.output.close()
+	body = stream("?offset=0", nil)
+	assert.Contains(t, body, "event: gap\ndata: {\"from\": 0, \"to\": 1}\n\n")
+	assert.Contains(t, body, "id: 1\n")
+
+	req := httptest.NewRequest(http.MethodGet, "/sessions/"+s.id.String()+"/events?offset=-1", nil)
+	req.Header.Set("Authorization", "Bearer owner-token")
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.False(t, strings.Contains(rr.Body.String(), "event:"))
+
+	// The output of a session is streamed only to the user who created it
+	for token, code := range map[string]int{"": http.StatusUnauthorized, "other-token": http.StatusNotFound} {
+		req = httptest.NewRequest(http.MethodGet, "/sessions/"+s.id.String()+"/events", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		req.Header.Set("Last-Event-ID", "0")
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		assert.Equal(t, code, rr.Code, token)
+		assert.False(t, strings.Contains(rr.Body.String(), "event:"))
+	}
+}
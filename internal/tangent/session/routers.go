@@ -2,9 +2,11 @@ package session
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
 )
 
 // ResponseHandlerParam defines the configuration for HTTP route handlers.
@@ -23,23 +25,127 @@ var resourceObjectHandlers = []ResponseHandlerParam{
 	},
 }
 
+// sessionUserHandlers serve the sessions of a catalog user and are authenticated as the
+// user who created the session.
+var sessionUserHandlers = []ResponseHandlerParam{
+	{
+		Method:  http.MethodGet,
+		Path:    "/",
+		Handler: listSessions,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}",
+		Handler: getSession,
+	},
+	{
+		Method:  http.MethodDelete,
+		Path:    "/{sessionID}",
+		Handler: terminateSession,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/events",
+		Handler: streamSessionOutput,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/artifacts",
+		Handler: listArtifacts,
+	},
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/artifacts/{artifactID}",
+		Handler: downloadArtifact,
+	},
+}
+
+// approvalHandlers list and decide approvals and are authenticated as a catalog user
+// allowed to approve the session's skillset.
+var approvalHandlers = []ResponseHandlerParam{
+	{
+		Method:  http.MethodGet,
+		Path:    "/{sessionID}/approvals",
+		Handler: listApprovals,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{sessionID}/approvals/{approvalID}/approve",
+		Handler: approveApproval,
+	},
+	{
+		Method:  http.MethodPost,
+		Path:    "/{sessionID}/approvals/{approvalID}/deny",
+		Handler: denyApproval,
+	},
+}
+
 // Router sets up HTTP routes for session management.
-// Registers session creation endpoint and applies authentication middleware.
+// Registers session lifecycle endpoints and applies authentication middleware. Sessions
+// are created with the code issued by the catalog server, so creation needs no token.
 func Router(r chi.Router) {
-	r.Use(SessionAuthenticator)
 	for _, handler := range resourceObjectHandlers {
 		r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
 	}
+	r.Group(func(r chi.Router) {
+		r.Use(SessionAuthenticator)
+		for _, handler := range sessionUserHandlers {
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
+		}
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(ApproverAuthenticator)
+		for _, handler := range approvalHandlers {
+			r.Method(handler.Method, handler.Path, httpx.WrapHttpRsp(handler.Handler))
+		}
+	})
 	// Route for connection that'll upgrade HTTP to WebSocket
 	//	r.Method(http.MethodGet, "/{id}/channel", http.HandlerFunc(getSessionChannel))
 }
 
-// SessionAuthenticator provides authentication middleware for session endpoints.
-// Currently a placeholder for future authentication implementation.
+// SessionAuthenticator authenticates requests for the sessions of a user. The request must
+// carry the catalog token of a user, which the catalog server resolves to the user. Handlers
+// serve only the sessions the user created.
 func SessionAuthenticator(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
-		// implement authentication logic here
-		next.ServeHTTP(w, r.WithContext(ctx))
+		authHeader := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			httpx.ErrUnAuthorized("missing or invalid authorization header").Send(w)
+			return
+		}
+		token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+		caller, apperr := authenticateCaller(ctx, token)
+		if apperr != nil {
+			httpx.SendError(w, apperr)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withCaller(ctx, caller)))
+	})
+}
+
+// ApproverAuthenticator authenticates decisions on a session's approvals. The request must
+// carry the catalog token of a user, which the catalog server checks allows approving the
+// session's skillset. The user is recorded as the approver of the decision.
+func ApproverAuthenticator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		authHeader := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			httpx.ErrUnAuthorized("missing or invalid authorization header").Send(w)
+			return
+		}
+		token := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+		sessionID, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+		if err != nil {
+			httpx.SendError(w, ErrInvalidSession.Msg("invalid session ID"))
+			return
+		}
+		approver, apperr := authorizeApprover(ctx, sessionID, token)
+		if apperr != nil {
+			httpx.SendError(w, apperr)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withApprover(ctx, approver)))
 	})
 }
@@ -6,8 +6,10 @@ package session
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"sync"
 	"time"
 
@@ -43,6 +45,10 @@ type session struct {
 	invocationIDs map[string]*policy.ViewDefinition
 	auditLogInfo  auditLogInfo
 	logger        *zerolog.Logger
+	lifecycle     sessionLifecycle
+	output        *outputLog
+	invocations   *invocationLog
+	approvals     approvalGate
 }
 
 // GetSessionID returns the unique identifier for this session.
@@ -53,10 +59,20 @@ func (s *session) GetSessionID() string {
 // Run executes a skill with the given parameters and input arguments.
 // The invokerID must be valid if provided, and the skill must be authorized by policy.
 // Returns an error if execution fails or policy validation fails.
-func (s *session) Run(ctx context.Context, invokerID string, skillName string, inputArgs map[string]any, ioWriters ...*tangentcommon.IOWriters) apperrors.Error {
+func (s *session) Run(ctx context.Context, invokerID string, skillName string, inputArgs map[string]any, ioWriters ...*tangentcommon.IOWriters) (retErr apperrors.Error) {
 	s.logger.Info().Str("skill", skillName).Msg("requested skill")
 	log.Ctx(ctx).Info().Msgf("requested skill: %s", skillName)
 	invocationID := uuid.New().String()
+	invocation := &srvsession.InvocationRecord{
+		InvocationID: invocationID,
+		InvokerID:    invokerID,
+		Skill:        skillName,
+		InputsHash:   srvsession.HashInputs(inputArgs),
+		StartedAt:    time.Now().UTC(),
+	}
+	defer func() {
+		s.recordInvocation(invocation, retErr)
+	}()
 	s.auditLogInfo.auditLogger.Info().
 		Str("event", "skill_start").
 		Str("invoker_id", invokerID).
@@ -81,7 +97,10 @@ func (s *session) Run(ctx context.Context, invokerID string, skillName string, i
 		s.logger.Error().Err(err).Msg("unable to validate run policy")
 		return err
 	}
+	invocation.Actions = actions
+	invocation.Decision = srvsession.InvocationDecisionAllowed
 	if !isAllowed {
+		invocation.Decision = srvsession.InvocationDecisionBlocked
 		msg := fmt.Sprintf("blocked by Tansive policy: view '%s' does not authorize any of required actions - %v - to use this skill", s.context.View, actions)
 		s.logger.Error().Str("policy_decision", "true").Msg(msg)
 		log.Ctx(ctx).Error().Str("policy_decision", "true").Msg(msg)
@@ -109,6 +128,21 @@ func (s *session) Run(ctx context.Context, invokerID string, skillName string, i
 		Any("actions", actions).
 		Msg("allowed by policy")
 
+	needsApproval, approvalRules, err := s.approvalRequired(skillName)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("unable to check approval requirement")
+		return err
+	}
+	if needsApproval {
+		if err := s.awaitApproval(ctx, invocationID, skillName, actions, approvalRules); err != nil {
+			if errors.Is(err, ErrApprovalDenied) {
+				invocation.Decision = srvsession.InvocationDecisionDenied
+			}
+			s.logger.Error().Err(err).Msg("skill not approved")
+			return err
+		}
+	}
+
 	transformApplied, inputArgs, err := s.TransformInputForSkill(ctx, skillName, inputArgs)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("unable to transform input")
@@ -237,18 +271,17 @@ func (s *session) runInteractiveSkill(ctx context.Context, invokerID, invocation
 		return err
 	}
 
-	runner, err := s.getRunner(ctx, skillName, ioWriters...)
+	runner, runnerDef, err := s.getRunner(skillName)
 	if err != nil {
 		return err
 	}
+	runnerID := string(runner.Describe().ID)
 
 	interactiveIOWriters := &tangentcommon.IOWriters{
-		Out: s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", "stdout").Str("runner", runner.ID()).Str("skill", skillName).Logger(),
-		Err: s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", "stderr").Str("runner", runner.ID()).Str("skill", skillName).Logger(),
+		Out: s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", "stdout").Str("runner", runnerID).Str("skill", skillName).Logger(),
+		Err: s.getLogger(TopicInteractiveLog).With().Str("actor", "skill").Str("source", "stderr").Str("runner", runnerID).Str("skill", skillName).Logger(),
 	}
 
-	runner.AddWriters(interactiveIOWriters)
-
 	serviceEndpoint, goerr := config.GetSocketPath()
 	if goerr != nil {
 		return ErrUnableToGetSkillset.Msg("failed to get socket path")
@@ -282,16 +315,28 @@ func (s *session) runInteractiveSkill(ctx context.Context, invokerID, invocation
 		defer wg.Done()
 		defer cancel()
 
-		s.logger.Info().Str("runner", runner.ID()).Str("actor", "runner").Msg("running skill")
-		ctx = log.Ctx(ctx).With().Str("runner", runner.ID()).Str("actor", "runner").Logger().WithContext(ctx)
+		s.logger.Info().Str("runner", runnerID).Str("actor", "runner").Msg("running skill")
+		ctx = log.Ctx(ctx).With().Str("runner", runnerID).Str("actor", "runner").Logger().WithContext(ctx)
 		log.Ctx(ctx).Info().Msgf("running skill: %s", skillName)
 		s.auditLogInfo.auditLogger.Info().
 			Str("event", "runner_start").
-			Str("runner", runner.ID()).
+			Str("runner", runnerID).
 			Str("invocation_id", invocationID).
 			Str("skill", skillName).
 			Msg("starting runner")
-		err := runner.Run(ctx, &args)
+		artifactDir := s.stageArtifacts()
+		var usage runners.Usage
+		err := runner.Exec(ctx, &runners.ExecRequest{
+			SessionID:    s.id.String(),
+			Config:       runnerDef.Config,
+			Args:         &args,
+			Writers:      append(slices.Clone(ioWriters), interactiveIOWriters),
+			ArtifactDir:  artifactDir,
+			Usage:        &usage,
+			SessionUsage: s.runnerUsage(),
+		})
+		s.recordUsage(usage)
+		s.collectArtifacts(ctx, invocationID, artifactDir)
 		if err != nil {
 			s.logger.Error().Err(err).Msg("error running skill")
 			log.Ctx(ctx).Error().Err(err).Msgf("error running skill: %s", skillName)
@@ -300,8 +345,12 @@ func (s *session) runInteractiveSkill(ctx context.Context, invokerID, invocation
 				Str("status", "failed").
 				Str("invocation_id", invocationID).
 				Err(err).
-				Str("runner", runner.ID()).
+				Str("runner", runnerID).
 				Str("skill", skillName).
+				Dur("wall_time", usage.WallTime).
+				Dur("cpu_time", usage.CPUTime).
+				Int64("max_memory", usage.MaxMemory).
+				Str("limit_exceeded", string(usage.LimitExceeded)).
 				Msg("runner completed")
 			resultChan <- err
 		} else {
@@ -311,8 +360,11 @@ func (s *session) runInteractiveSkill(ctx context.Context, invokerID, invocation
 				Str("event", "runner_completed").
 				Str("status", "success").
 				Str("invocation_id", invocationID).
-				Str("runner", runner.ID()).
+				Str("runner", runnerID).
 				Str("skill", skillName).
+				Dur("wall_time", usage.WallTime).
+				Dur("cpu_time", usage.CPUTime).
+				Int64("max_memory", usage.MaxMemory).
 				Msg("runner completed")
 			resultChan <- nil
 		}
@@ -346,27 +398,30 @@ func (s *session) runInteractiveSkill(ctx context.Context, invokerID, invocation
 	return <-resultChan
 }
 
-// getRunner creates a runner instance for the specified skill.
-// Returns the runner and any error encountered during creation.
-func (s *session) getRunner(ctx context.Context, skillName string, ioWriters ...*tangentcommon.IOWriters) (runners.Runner, apperrors.Error) {
+// getRunner looks up the runner for the specified skill and validates the skill's
+// runner configuration. Returns the runner and the skillset source that configures it.
+func (s *session) getRunner(skillName string) (runners.Runner, catalogmanager.SkillSetSource, apperrors.Error) {
 	if s.skillSet == nil {
-		return nil, ErrUnableToGetSkillset.Msg("skillset not found")
+		return nil, catalogmanager.SkillSetSource{}, ErrUnableToGetSkillset.Msg("skillset not found")
 	}
 
 	runnerDef, err := s.skillSet.GetSourceForSkill(skillName)
 	if err != nil {
-		return nil, err
+		return nil, runnerDef, err
 	}
-	runner, err := runners.NewRunner(ctx, s.id.String(), runnerDef, ioWriters...)
+	runner, err := runners.Lookup(runnerDef.Runner)
 	if err != nil {
-		return nil, err
+		return nil, runnerDef, err
+	}
+	if err := runner.Validate(runnerDef.Config); err != nil {
+		return nil, runnerDef, err
 	}
 
-	return runner, nil
+	return runner, runnerDef, nil
 }
 
-// fetchObjects retrieves the skillset and view definition from the catalog server.
-// Must be called before skill execution to ensure proper authorization.
+// fetchObjects retrieves the skillset from the catalog server and the view definition from
+// the view cache. Must be called before skill execution to ensure proper authorization.
 func (s *session) fetchObjects(ctx context.Context) apperrors.Error {
 	client := getHTTPClient(&clientConfig{
 		token:       s.token,
@@ -384,7 +439,11 @@ func (s *session) fetchObjects(ctx context.Context) apperrors.Error {
 	}
 
 	// get view definition
-	s.viewDef = s.context.ViewDefinition
+	viewDef, err := s.viewDefinition(ctx, client)
+	if err != nil {
+		return err
+	}
+	s.viewDef = viewDef
 
 	return nil
 }
@@ -543,6 +602,7 @@ func (s *session) Finalize(ctx context.Context, apperr apperrors.Error) apperror
 		Status: srvsession.ExecutionStatus{
 			AuditLog:                auditLog,
 			AuditLogVerificationKey: s.auditLogInfo.auditLogPubKey,
+			Artifacts:               s.artifactRefs(),
 		},
 	}
 	if apperr != nil {
@@ -552,11 +612,10 @@ func (s *session) Finalize(ctx context.Context, apperr apperrors.Error) apperror
 		}
 	}
 
-	client := getHTTPClient(&clientConfig{
-		token:       s.token,
-		tokenExpiry: s.tokenExpiry,
-		serverURL:   config.Config().TansiveServer.GetURL(),
-	})
+	client := s.tansiveClient()
+	if err := s.invocations.ship(client); err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("failed to ship invocation records")
+	}
 
 	body, err := json.Marshal(sessionStatus)
 	if err != nil {
@@ -1,19 +1,29 @@
 package session
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"path"
+	"strconv"
 	"sync"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog/log"
 	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
 	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/eventbus"
 	"github.com/tansive/tansive-internal/internal/common/httpclient"
 	"github.com/tansive/tansive-internal/internal/common/httpx"
+	"github.com/tansive/tansive-internal/internal/common/uuid"
+	"github.com/tansive/tansive-internal/internal/tangent/artifacts"
 	"github.com/tansive/tansive-internal/internal/tangent/config"
+	"github.com/tansive/tansive-internal/internal/tangent/session/api"
 	"github.com/tansive/tansive-internal/internal/tangent/tangentcommon"
 )
 
@@ -41,6 +51,11 @@ func createSession(r *http.Request) (*httpx.Response, error) {
 		return nil, httpx.ErrInvalidRequest("only interactive sessions are supported")
 	}
 
+	timeout, err := sessionTimeout(req)
+	if err != nil {
+		return nil, err
+	}
+
 	session, err := handleInteractiveSession(ctx, req)
 	if err != nil {
 		return nil, err
@@ -52,13 +67,333 @@ func createSession(r *http.Request) (*httpx.Response, error) {
 		Chunked:     true,
 		WriteChunks: func(w http.ResponseWriter) error {
 			ctx := log.Ctx(ctx).With().Str("session_id", session.id.String()).Logger().WithContext(ctx)
-			return runSession(ctx, w, session)
+			return runSession(ctx, w, session, timeout)
 		},
 	}
 
 	return rsp, nil
 }
 
+// sessionTimeout returns the longest the requested session may run: the timeout of the
+// request if it has one, capped by the configured session timeout.
+func sessionTimeout(req *tangentcommon.SessionCreateRequest) (time.Duration, error) {
+	timeout, err := config.Config().Session.GetTimeout()
+	if err != nil {
+		return 0, ErrSessionError.Msg("invalid session timeout: " + err.Error())
+	}
+	if req.Timeout == "" {
+		return timeout, nil
+	}
+	requested, err := time.ParseDuration(req.Timeout)
+	if err != nil || requested <= 0 {
+		return 0, httpx.ErrInvalidRequest("invalid timeout: " + req.Timeout)
+	}
+	return min(requested, timeout), nil
+}
+
+// getSession handles HTTP requests to retrieve the state of a session.
+// Returns an error if the session ID is invalid or the caller has no such session.
+func getSession(r *http.Request) (*httpx.Response, error) {
+	session, err := ownSessionFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   api.GetSessionResponse{Session: session.info()},
+	}, nil
+}
+
+// listSessions handles HTTP requests to list the caller's sessions, oldest first.
+// Sessions can be filtered by status with the status query parameter.
+func listSessions(r *http.Request) (*httpx.Response, error) {
+	caller, ok := callerFromContext(r.Context())
+	if !ok {
+		return nil, ErrCallerNotAuthenticated
+	}
+	status := SessionStatus(r.URL.Query().Get("status"))
+	sessions, err := ActiveSessionManager().ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	rsp := api.ListSessionsResponse{Sessions: []api.Session{}}
+	for _, session := range sessions {
+		if !session.ownedBy(caller) {
+			continue
+		}
+		info := session.info()
+		if status != "" && info.Status != string(status) {
+			continue
+		}
+		rsp.Sessions = append(rsp.Sessions, info)
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   rsp,
+	}, nil
+}
+
+// terminateSession handles HTTP requests to terminate a session.
+// A running session is cancelled and ends once its skill has stopped; the response
+// reports its state at the time of the request.
+func terminateSession(r *http.Request) (*httpx.Response, error) {
+	session, err := ownSessionFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := session.terminate(); err != nil {
+		return nil, err
+	}
+	log.Ctx(r.Context()).Info().Str("session_id", session.id.String()).Msg("session terminated")
+	return &httpx.Response{
+		StatusCode: http.StatusAccepted,
+		Response:   api.GetSessionResponse{Session: session.info()},
+	}, nil
+}
+
+// listApprovals handles HTTP requests to list the approvals a session waits for. The request
+// is authenticated by ApproverAuthenticator.
+func listApprovals(r *http.Request) (*httpx.Response, error) {
+	session, err := sessionFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   api.ListApprovalsResponse{Approvals: session.pendingApprovals()},
+	}, nil
+}
+
+// approveApproval handles HTTP requests to approve a pending approval, resuming the
+// invocation that waits for it. The request is authenticated by ApproverAuthenticator.
+func approveApproval(r *http.Request) (*httpx.Response, error) {
+	return decideApproval(r, true)
+}
+
+// denyApproval handles HTTP requests to deny a pending approval. The invocation that waits
+// for it fails with ErrApprovalDenied.
+func denyApproval(r *http.Request) (*httpx.Response, error) {
+	return decideApproval(r, false)
+}
+
+func decideApproval(r *http.Request, approved bool) (*httpx.Response, error) {
+	session, err := sessionFromRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	var req api.ApprovalDecisionRequest
+	if r.Body != nil {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return nil, ErrBadRequest.Msg("unable to read request body")
+		}
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := json.Unmarshal(body, &req); err != nil {
+				return nil, ErrBadRequest.Msg("invalid request body")
+			}
+		}
+	}
+	approver := approverFromContext(r.Context())
+	if approver == "" {
+		return nil, ErrApproverNotAuthorized
+	}
+	approval, err := session.decideApproval(chi.URLParam(r, "approvalID"), approved, req.Reason, approver)
+	if err != nil {
+		return nil, err
+	}
+	log.Ctx(r.Context()).Info().Str("session_id", session.id.String()).Str("approval_id", approval.ID).Str("decision", approval.Status).Str("approver", approver).Msg("approval decided")
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   approval,
+	}, nil
+}
+
+const (
+	outputReadBatch         = 100
+	outputHeartbeatInterval = 30 * time.Second
+)
+
+// streamSessionOutput streams the output of a session as Server-Sent Events, so that
+// clients can attach to a running session. Each event carries its offset as the event ID
+// and its stream (log or output) as the event type. The stream starts at the offset query
+// parameter, or after the Last-Event-ID of a reconnecting client, and at the first event
+// otherwise. Retained events are replayed first. If some were dropped because the client
+// fell too far behind, a gap event reports the offset the stream resumed from. Once the
+// session has ended and its output is sent, an end event carries the final state of the
+// session and the stream closes. Only the user who created the session may attach to it.
+func streamSessionOutput(r *http.Request) (*httpx.Response, error) {
+	ctx := r.Context()
+
+	session, apperr := ownSessionFromRequest(r)
+	if apperr != nil {
+		return nil, apperr
+	}
+	var offset int64
+	if o := r.URL.Query().Get("offset"); o != "" {
+		var err error
+		offset, err = strconv.ParseInt(o, 10, 64)
+		if err != nil || offset < 0 {
+			return nil, httpx.ErrInvalidRequest("invalid offset: " + o)
+		}
+	}
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		lastID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil || lastID < 0 {
+			return nil, httpx.ErrInvalidRequest("invalid Last-Event-ID: " + id)
+		}
+		offset = lastID + 1
+	}
+	stream := r.URL.Query().Get("stream")
+	if stream != "" && stream != OutputStreamLog && stream != OutputStreamInteractive {
+		return nil, httpx.ErrInvalidRequest("invalid stream: " + stream)
+	}
+
+	return &httpx.Response{
+		StatusCode:  http.StatusOK,
+		ContentType: "text/event-stream",
+		Header:      http.Header{"Cache-Control": {"no-cache"}},
+		Chunked:     true,
+		WriteChunks: func(w http.ResponseWriter) error {
+			// Sessions outlive the server's write timeout
+			rc := http.NewResponseController(w)
+			if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msg("unable to clear write deadline")
+			}
+			if _, err := fmt.Fprint(w, ": attached\n\n"); err != nil {
+				return err
+			}
+			if err := rc.Flush(); err != nil {
+				return err
+			}
+
+			heartbeat := time.NewTicker(outputHeartbeatInterval)
+			defer heartbeat.Stop()
+			for {
+				events, notify, closed := session.output.read(offset, outputReadBatch)
+				if len(events) == 0 {
+					if closed {
+						data, err := json.Marshal(session.info())
+						if err != nil {
+							return err
+						}
+						_, err = fmt.Fprintf(w, "event: end\ndata: %s\n\n", data)
+						return err
+					}
+					select {
+					case <-ctx.Done():
+						return nil
+					case <-heartbeat.C:
+						if _, err := fmt.Fprint(w, ": keepalive\n\n"); err != nil {
+							return err
+						}
+						if err := rc.Flush(); err != nil {
+							return err
+						}
+					case <-notify:
+					}
+					continue
+				}
+
+				if events[0].Offset > offset {
+					if _, err := fmt.Fprintf(w, "event: gap\ndata: {\"from\": %d, \"to\": %d}\n\n", offset, events[0].Offset); err != nil {
+						return err
+					}
+				}
+				for _, ev := range events {
+					if stream != "" && ev.Stream != stream {
+						continue
+					}
+					data, err := json.Marshal(ev)
+					if err != nil {
+						log.Ctx(ctx).Error().Err(err).Msg("unable to marshal output event")
+						continue
+					}
+					if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Offset, ev.Stream, data); err != nil {
+						return err
+					}
+				}
+				offset = events[len(events)-1].Offset + 1
+				if err := rc.Flush(); err != nil {
+					return err
+				}
+			}
+		},
+	}, nil
+}
+
+// listArtifacts handles HTTP requests to list the artifacts of a session. Artifacts are
+// kept until they expire, so they are listed after the session itself is gone, to the user
+// who created the session.
+func listArtifacts(r *http.Request) (*httpx.Response, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return nil, ErrInvalidSession.Msg("invalid session ID")
+	}
+	caller, ok := callerFromContext(r.Context())
+	if !ok {
+		return nil, ErrCallerNotAuthenticated
+	}
+	list := []artifacts.Artifact{}
+	if m := artifacts.Default(); m != nil {
+		list = m.List(id.String(), artifactOwner(caller.TenantID, caller.UserID))
+	}
+	// Without artifacts of the caller, the session must be one of theirs
+	if len(list) == 0 {
+		if _, apperr := ownSessionFromRequest(r); apperr != nil {
+			return nil, apperr
+		}
+	}
+	return &httpx.Response{
+		StatusCode: http.StatusOK,
+		Response:   api.ListArtifactsResponse{Artifacts: list},
+	}, nil
+}
+
+// downloadArtifact handles HTTP requests for the content of an artifact. Artifacts of other
+// users are reported as not found.
+func downloadArtifact(r *http.Request) (*httpx.Response, error) {
+	id, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return nil, ErrInvalidSession.Msg("invalid session ID")
+	}
+	caller, ok := callerFromContext(r.Context())
+	if !ok {
+		return nil, ErrCallerNotAuthenticated
+	}
+	m := artifacts.Default()
+	if m == nil {
+		return nil, artifacts.ErrArtifactNotFound.Msg("artifacts are not enabled")
+	}
+	a, content, apperr := m.Open(r.Context(), id.String(), artifactOwner(caller.TenantID, caller.UserID), chi.URLParam(r, "artifactID"))
+	if apperr != nil {
+		return nil, apperr
+	}
+
+	header := http.Header{}
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": path.Base(a.Name)}))
+	header.Set("X-Checksum-Sha256", a.SHA256)
+	return &httpx.Response{
+		StatusCode:  http.StatusOK,
+		ContentType: a.ContentType,
+		Header:      header,
+		Chunked:     true,
+		WriteChunks: func(w http.ResponseWriter) error {
+			defer content.Close()
+			_, err := io.Copy(w, content)
+			return err
+		},
+	}, nil
+}
+
+// sessionFromRequest returns the session identified by the sessionID URL parameter.
+func sessionFromRequest(r *http.Request) (*session, apperrors.Error) {
+	id, err := uuid.Parse(chi.URLParam(r, "sessionID"))
+	if err != nil {
+		return nil, ErrInvalidSession.Msg("invalid session ID")
+	}
+	return ActiveSessionManager().GetSession(id)
+}
+
 // handleInteractiveSession creates an interactive session from the request.
 // Retrieves execution state from the catalog server and creates an active session.
 // Returns the created session and any error encountered during creation.
@@ -157,6 +492,7 @@ func createActiveSession(ctx context.Context, executionState *srvsession.Executi
 		Variant:          executionState.Variant,
 		Namespace:        executionState.Namespace,
 		TenantID:         executionState.TenantID,
+		UserID:           executionState.UserID,
 	}
 
 	session, err := ActiveSessionManager().CreateSession(ctx, serverCtx, token, tokenExpiry)
@@ -169,16 +505,27 @@ func createActiveSession(ctx context.Context, executionState *srvsession.Executi
 }
 
 // runSession executes a session and streams results to the HTTP response.
-// Initializes audit logging, subscribes to event streams, and runs the session.
+// Initializes audit logging, subscribes to event streams, and runs the session until it
+// ends, is terminated, or times out.
 // Returns any error encountered during session execution.
-func runSession(ctx context.Context, w http.ResponseWriter, session *session) (apperr apperrors.Error) {
+func runSession(ctx context.Context, w http.ResponseWriter, session *session, timeout time.Duration) (apperr apperrors.Error) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		log.Ctx(ctx).Error().Msg("response writer does not support flushing")
 		return ErrSessionError.Msg("response writer does not support flushing")
 	}
 
-	defer session.Finalize(ctx, apperr)
+	sessionCtx, apperr := session.start(ctx, timeout)
+	defer func() {
+		if sessionCtx != nil {
+			status := session.finish(sessionCtx, apperr)
+			log.Ctx(ctx).Info().Str("status", string(status)).Msg("session ended")
+		}
+		session.Finalize(ctx, apperr)
+	}()
+	if apperr != nil {
+		return apperr
+	}
 
 	auditLogCtx, cancelAuditLog := context.WithCancel(context.Background())
 	defer cancelAuditLog()
@@ -187,6 +534,7 @@ func runSession(ctx context.Context, w http.ResponseWriter, session *session) (a
 	if apperr != nil {
 		log.Ctx(ctx).Error().Err(apperr).Msg("unable to initialize audit log")
 	}
+	go session.shipInvocations(auditLogCtx)
 
 	sessionLog, unsubSessionLog := GetEventBus().Subscribe(session.getTopic(TopicSessionLog), 100)
 	defer unsubSessionLog()
@@ -199,24 +547,45 @@ func runSession(ctx context.Context, w http.ResponseWriter, session *session) (a
 	wg.Add(1)
 	go func(ctx context.Context) {
 		defer wg.Done()
+		// Events are recorded in the session's output, for clients that attach to it, and
+		// streamed to the client that created the session. write reports false once the
+		// subscription is closed.
+		write := func(stream string, event eventbus.Event, open bool) bool {
+			if !open {
+				return false
+			}
+			if data, ok := event.Data.([]byte); ok {
+				session.output.append(stream, bytes.TrimSpace(data))
+				w.Write(data)
+				flusher.Flush()
+			}
+			return true
+		}
 		for {
 			select {
 			case <-ctx.Done():
-				return
-			case event := <-sessionLog:
-				data, ok := event.Data.([]byte)
-				if !ok {
-					continue
+				// Record what was published before the session ended
+				for {
+					var open bool
+					select {
+					case event, ok := <-sessionLog:
+						open = write(OutputStreamLog, event, ok)
+					case event, ok := <-interactiveLog:
+						open = write(OutputStreamInteractive, event, ok)
+					default:
+					}
+					if !open {
+						return
+					}
 				}
-				w.Write(data)
-				flusher.Flush()
-			case event := <-interactiveLog:
-				data, ok := event.Data.([]byte)
-				if !ok {
-					continue
+			case event, ok := <-sessionLog:
+				if !write(OutputStreamLog, event, ok) {
+					return
+				}
+			case event, ok := <-interactiveLog:
+				if !write(OutputStreamInteractive, event, ok) {
+					return
 				}
-				w.Write(data)
-				flusher.Flush()
 			}
 		}
 	}(logCtx)
@@ -228,7 +597,7 @@ func runSession(ctx context.Context, w http.ResponseWriter, session *session) (a
 		Msg("starting session")
 
 	log.Ctx(ctx).Info().Str("skill", session.context.Skill).Msg("running session")
-	runCtx := session.getLogger(TopicSessionLog).With().Str("skill", session.context.Skill).Str("actor", "system").Logger().WithContext(ctx)
+	runCtx := session.getLogger(TopicSessionLog).With().Str("skill", session.context.Skill).Str("actor", "system").Logger().WithContext(sessionCtx)
 
 	apperr = session.Run(runCtx, "", session.context.Skill, session.context.InputArgs)
 
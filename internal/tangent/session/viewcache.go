@@ -0,0 +1,220 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/catcommon"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+	srvsession "github.com/tansive/tansive-internal/internal/catalogsrv/session"
+	"github.com/tansive/tansive-internal/internal/common/apperrors"
+	"github.com/tansive/tansive-internal/internal/common/httpclient"
+	"github.com/tansive/tansive-internal/internal/tangent/config"
+)
+
+// viewCache holds the definitions of the views of the sessions on this tangent, so that
+// the actions of each skill call are checked locally instead of with a request to the
+// catalog server. Definitions of a catalog are dropped whenever a view in the catalog
+// changes, as reported by a watch on the catalog server, and are fetched again on the
+// next skill call. A view includes the rules of other views, so a change to any view of
+// the catalog may change the definition of another.
+type viewCache struct {
+	mu       sync.Mutex
+	views    map[viewKey]*policy.ViewDefinition
+	catalogs map[catalogKey]*catalogViews
+}
+
+type catalogKey struct {
+	tenantID catcommon.TenantId
+	catalog  string
+}
+
+type viewKey struct {
+	catalogKey
+	view string
+}
+
+// catalogViews tracks the watch on the views of a catalog.
+type catalogViews struct {
+	generation uint64 // incremented each time the catalog's views are dropped
+	watching   bool   // a watch has been started and has not ended
+}
+
+var views = &viewCache{
+	views:    make(map[viewKey]*policy.ViewDefinition),
+	catalogs: make(map[catalogKey]*catalogViews),
+}
+
+func (c *viewCache) catalog(ck catalogKey) *catalogViews {
+	cv, ok := c.catalogs[ck]
+	if !ok {
+		cv = &catalogViews{}
+		c.catalogs[ck] = cv
+	}
+	return cv
+}
+
+// get returns the cached definition of a view and the generation of its catalog.
+func (c *viewCache) get(key viewKey) (*policy.ViewDefinition, uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.views[key], c.catalog(key.catalogKey).generation
+}
+
+// put caches the definition of a view fetched at the given generation of its catalog.
+// It is not cached if the catalog's views have been dropped since, as it may be stale.
+func (c *viewCache) put(key viewKey, def *policy.ViewDefinition, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if def == nil || c.catalog(key.catalogKey).generation != generation {
+		return
+	}
+	c.views[key] = def
+}
+
+// invalidate drops the cached definitions of the views of a catalog.
+func (c *viewCache) invalidate(ck catalogKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.catalog(ck).generation++
+	for key := range c.views {
+		if key.catalogKey == ck {
+			delete(c.views, key)
+		}
+	}
+}
+
+// startWatch reports whether the caller should start a watch on the views of a catalog,
+// which is the case if none is running.
+func (c *viewCache) startWatch(ck catalogKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cv := c.catalog(ck)
+	if cv.watching {
+		return false
+	}
+	cv.watching = true
+	return true
+}
+
+// endWatch records that the watch on the views of a catalog has ended. If it was connected,
+// changes made since it ended would go unnoticed, so the catalog's views are dropped.
+func (c *viewCache) endWatch(ck catalogKey, connected bool) {
+	if connected {
+		c.invalidate(ck)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.catalog(ck).watching = false
+}
+
+// viewKey returns the key of the session's view in the view cache.
+func (s *session) viewKey() viewKey {
+	return viewKey{
+		catalogKey: catalogKey{tenantID: s.context.TenantID, catalog: s.context.Catalog},
+		view:       s.context.View,
+	}
+}
+
+// viewDefinition returns the definition of the session's view from the view cache, fetching
+// it from the catalog server if it is not cached.
+func (s *session) viewDefinition(ctx context.Context, client httpclient.HTTPClientInterface) (*policy.ViewDefinition, apperrors.Error) {
+	s.watchViews(ctx)
+
+	key := s.viewKey()
+	viewDef, generation := views.get(key)
+	if viewDef != nil {
+		return viewDef, nil
+	}
+
+	body, _, err := client.DoRequest(httpclient.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "sessions/execution-state",
+	})
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("unable to get view definition")
+		return nil, ErrFailedRequestToTansiveServer.Msg("unable to get view definition: " + err.Error())
+	}
+	executionState := &srvsession.ExecutionState{}
+	if err := json.Unmarshal(body, executionState); err != nil {
+		return nil, ErrFailedRequestToTansiveServer.Msg("unable to parse execution state: " + err.Error())
+	}
+	if executionState.ViewDefinition == nil {
+		return nil, ErrFailedRequestToTansiveServer.Msg("execution state has no view definition")
+	}
+	views.put(key, executionState.ViewDefinition, generation)
+	return executionState.ViewDefinition, nil
+}
+
+// watchViews starts a watch on the views of the session's catalog unless one is running.
+// The watch is authorized by the session's token, so it ends when the token expires and
+// is started again by the next session of the catalog that calls a skill.
+func (s *session) watchViews(ctx context.Context) {
+	// The in-process test client cannot stream
+	if isTestMode || s.token == "" {
+		return
+	}
+	ck := s.viewKey().catalogKey
+	if !views.startWatch(ck) {
+		return
+	}
+	client := getHTTPClient(&clientConfig{
+		token:       s.token,
+		tokenExpiry: s.tokenExpiry,
+		serverURL:   config.Config().TansiveServer.GetURL(),
+	})
+	logger := log.Ctx(ctx).With().Str("catalog", ck.catalog).Logger()
+	tokenExpiry := s.tokenExpiry
+
+	go func() {
+		reader, err := client.StreamRequest(httpclient.RequestOptions{
+			Method: http.MethodGet,
+			Path:   "sessions/execution-state/watch",
+		})
+		if err != nil {
+			logger.Warn().Err(err).Msg("unable to watch views; cached view definitions will not be refreshed")
+			views.endWatch(ck, false)
+			return
+		}
+		expire := time.AfterFunc(time.Until(tokenExpiry), func() { reader.Close() })
+		defer expire.Stop()
+		defer reader.Close()
+
+		logger.Debug().Msg("watching views")
+		err = readViewEvents(reader, func() {
+			views.invalidate(ck)
+		})
+		logger.Debug().Err(err).Msg("view watch ended")
+		views.endWatch(ck, true)
+	}()
+}
+
+// readViewEvents reads a stream of Server-Sent Events and calls fn for each event.
+// Comments, such as keepalives, are skipped. It returns when the stream ends.
+func readViewEvents(r io.Reader, fn func()) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var data bytes.Buffer
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line ends an event
+			if data.Len() > 0 {
+				fn()
+				data.Reset()
+			}
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(line, "data:"))
+		}
+	}
+	return scanner.Err()
+}
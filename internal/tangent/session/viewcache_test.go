@@ -0,0 +1,69 @@
+package session
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tansive/tansive-internal/internal/catalogsrv/policy"
+)
+
+func TestViewCache(t *testing.T) {
+	c := &viewCache{
+		views:    make(map[viewKey]*policy.ViewDefinition),
+		catalogs: make(map[catalogKey]*catalogViews),
+	}
+	prod := viewKey{catalogKey: catalogKey{tenantID: "t1", catalog: "cat"}, view: "prod-view"}
+	dev := viewKey{catalogKey: prod.catalogKey, view: "dev-view"}
+	other := viewKey{catalogKey: catalogKey{tenantID: "t1", catalog: "other"}, view: "prod-view"}
+	def := &policy.ViewDefinition{Scope: policy.Scope{Catalog: "cat"}}
+
+	viewDef, generation := c.get(prod)
+	assert.Nil(t, viewDef)
+	c.put(prod, def, generation)
+	c.put(dev, def, generation)
+	_, otherGeneration := c.get(other)
+	c.put(other, def, otherGeneration)
+	viewDef, _ = c.get(prod)
+	assert.Same(t, def, viewDef)
+
+	// A change to any view of the catalog drops all of its views
+	c.invalidate(prod.catalogKey)
+	viewDef, _ = c.get(prod)
+	assert.Nil(t, viewDef)
+	viewDef, _ = c.get(dev)
+	assert.Nil(t, viewDef)
+	viewDef, _ = c.get(other)
+	assert.Same(t, def, viewDef)
+
+	// A definition fetched before the views were dropped is not cached
+	c.put(prod, def, generation)
+	viewDef, generation = c.get(prod)
+	assert.Nil(t, viewDef)
+	c.put(prod, def, generation)
+	viewDef, _ = c.get(prod)
+	assert.Same(t, def, viewDef)
+
+	// One watch per catalog; a watch that was connected drops the views when it ends
+	require.True(t, c.startWatch(prod.catalogKey))
+	assert.False(t, c.startWatch(dev.catalogKey))
+	c.endWatch(prod.catalogKey, false)
+	viewDef, _ = c.get(prod)
+	assert.Same(t, def, viewDef)
+	require.True(t, c.startWatch(prod.catalogKey))
+	c.endWatch(prod.catalogKey, true)
+	viewDef, _ = c.get(prod)
+	assert.Nil(t, viewDef)
+	assert.True(t, c.startWatch(prod.catalogKey))
+}
+
+func TestReadViewEvents(t *testing.T) {
+	stream := ": watching\n\n" +
+		"event: updated\ndata: {\"kind\":\"View\",\"name\":\"prod-view\"}\n\n" +
+		": keepalive\n\n" +
+		"event: deleted\ndata: {\"kind\":\"View\",\"name\":\"dev-view\"}\n\n"
+	events := 0
+	require.NoError(t, readViewEvents(strings.NewReader(stream), func() { events++ }))
+	assert.Equal(t, 2, events)
+}
@@ -11,3 +11,6 @@ BEGIN
    END IF;
 END
 $$;
+
+-- catalog_api applies the schema migrations on startup
+GRANT CREATE ON SCHEMA public TO catalog_api;